@@ -0,0 +1,47 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/adapters/prometheus"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/config"
+)
+
+// runAlertRules implements the alert-rules subcommand: it prints recommended
+// Prometheus alert rules for the metrics served at /metrics?format=prometheus
+func runAlertRules(args []string, stdout, stderr io.Writer) int {
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(stderr, "failed to load configuration: %v\n", err)
+		return 1
+	}
+
+	fs := flag.NewFlagSet("alert-rules", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	job := fs.String("job", "price-snapshot-service", "Prometheus job label the service is scraped under")
+	group := fs.String("group", "price-snapshot-service", "name of the generated rule group")
+	pollInterval := fs.Duration("poll-interval", cfg.Poller.Interval, "poller interval the staleness alert is based on (defaults to POLLER_INTERVAL)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	if *pollInterval <= 0 {
+		fmt.Fprintln(stderr, "poll interval must be positive")
+		return 2
+	}
+
+	rules := prometheus.Rules(prometheus.RuleOptions{
+		Job:          *job,
+		PollInterval: *pollInterval,
+	})
+
+	fmt.Fprintln(stdout, "# Generated by snapshot-service alert-rules")
+	if err := prometheus.WriteRules(stdout, *group, rules); err != nil {
+		fmt.Fprintf(stderr, "failed to write rules: %v\n", err)
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+)
+
+// Exit codes tell orchestrators why the service stopped, so a bad config or
+// failed migration is not restarted in a loop like a transient crash
+const (
+	exitClean          = 0 // stopped by SIGINT/SIGTERM
+	exitFailure        = 1 // startup failed for another reason
+	exitConfig         = 2 // configuration could not be loaded or is invalid
+	exitDatabase       = 3 // database unreachable at startup
+	exitMigration      = 4 // schema migration failed or was refused
+	exitComponentCrash = 5 // a running component stopped unexpectedly
+)
+
+var (
+	errDatabaseUnreachable = errors.New("database unreachable")
+	errMigration           = errors.New("schema migration failed")
+)
+
+// exitReason describes why the process is exiting. It is logged as the
+// final line before exit.
+type exitReason struct {
+	code      int
+	reason    string
+	signal    string
+	component string
+	err       error
+}
+
+func signalExit(signal string) exitReason {
+	return exitReason{code: exitClean, reason: "signal", signal: signal}
+}
+
+func configExit(err error) exitReason {
+	return exitReason{code: exitConfig, reason: "config_invalid", err: err}
+}
+
+func crashExit(component string, err error) exitReason {
+	return exitReason{code: exitComponentCrash, reason: "component_crash", component: component, err: err}
+}
+
+// startupExit classifies a failure to build or start the application
+func startupExit(err error) exitReason {
+	switch {
+	case errors.Is(err, errDatabaseUnreachable):
+		return exitReason{code: exitDatabase, reason: "database_unreachable", err: err}
+	case errors.Is(err, errMigration), errors.Is(err, domain.ErrSchemaDirty), errors.Is(err, domain.ErrSchemaAhead):
+		return exitReason{code: exitMigration, reason: "migration_failed", err: err}
+	default:
+		return exitReason{code: exitFailure, reason: "startup_failed", err: err}
+	}
+}
+
+// log writes the final structured log line
+func (r exitReason) log(logger *slog.Logger) {
+	level := slog.LevelInfo
+	attrs := []any{"reason", r.reason, "exit_code", r.code}
+	if r.signal != "" {
+		attrs = append(attrs, "signal", r.signal)
+	}
+	if r.component != "" {
+		attrs = append(attrs, "component", r.component)
+	}
+	if r.err != nil {
+		level = slog.LevelError
+		attrs = append(attrs, "error", r.err)
+	}
+	logger.Log(context.Background(), level, "service exiting", attrs...)
+}
@@ -2,38 +2,67 @@ package main
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
-	"github.com/prxgr4mmer/price-snapshot-service/internal/adapters/binance"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/adapters/chaos"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/adapters/clock"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/adapters/coingecko"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/adapters/exchange"
+	grpcAdapter "github.com/prxgr4mmer/price-snapshot-service/internal/adapters/grpc"
 	httpAdapter "github.com/prxgr4mmer/price-snapshot-service/internal/adapters/http"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/adapters/memory"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/adapters/notify"
 	"github.com/prxgr4mmer/price-snapshot-service/internal/adapters/postgres"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/adapters/schema"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/adapters/sqlite"
 	"github.com/prxgr4mmer/price-snapshot-service/internal/config"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
 	"github.com/prxgr4mmer/price-snapshot-service/internal/services"
 	"github.com/prxgr4mmer/price-snapshot-service/internal/worker"
+	"github.com/prxgr4mmer/price-snapshot-service/pkg/retry"
+	"github.com/prxgr4mmer/price-snapshot-service/pkg/shutdown"
 )
 
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "alert-rules":
+			os.Exit(runAlertRules(os.Args[2:], os.Stdout, os.Stderr))
+		case "export-range":
+			os.Exit(runExportRange(os.Args[2:], os.Stdout, os.Stderr))
+		}
+	}
+
 	// Initialize logger
 	logger := initLogger()
 	slog.SetDefault(logger)
 
-	logger.Info("starting crypto snapshot service")
+	logger.Info("starting crypto snapshot service", "version", version)
 
+	reason := run(logger)
+	reason.log(logger)
+	os.Exit(reason.code)
+}
+
+// run starts the service and blocks until it stops, returning why
+func run(logger *slog.Logger) exitReason {
 	// Load configuration
 	cfg, err := config.Load()
 	if err != nil {
-		logger.Error("failed to load configuration", "error", err)
-		os.Exit(1)
+		return configExit(err)
 	}
 
 	// Validate configuration
 	if err := cfg.Validate(); err != nil {
-		logger.Error("invalid configuration", "error", err)
-		os.Exit(1)
+		return configExit(err)
 	}
 
 	// Create root context with cancellation
@@ -43,18 +72,17 @@ func main() {
 	// Build and start application
 	app, err := buildApplication(ctx, cfg, logger)
 	if err != nil {
-		logger.Error("failed to build application", "error", err)
-		os.Exit(1)
+		return startupExit(err)
 	}
 
 	// Start application components
 	if err := app.Start(ctx); err != nil {
-		logger.Error("failed to start application", "error", err)
-		os.Exit(1)
+		app.Shutdown()
+		return startupExit(err)
 	}
 
-	// Wait for shutdown signal
-	waitForShutdown(ctx, cancel, app, logger)
+	// Wait for shutdown signal or a component crash
+	return waitForShutdown(ctx, cancel, app, logger)
 }
 
 func initLogger() *slog.Logger {
@@ -89,118 +117,832 @@ func initLogger() *slog.Logger {
 
 // Application holds all components
 type Application struct {
-	db         *postgres.DB
 	httpServer *httpAdapter.Server
-	poller     *worker.Poller
+	grpcServer *grpcAdapter.Server
+	pollers    []*worker.Poller
+	streamer   *worker.Streamer
+	pruner     *worker.Pruner
+	compressor *worker.Compressor
+	flusher    *worker.SnapshotFlusher
+	candles    *worker.CandleAggregator
+	metadata   *worker.MetadataRefresher
+	jobRunner  *worker.JobRunner
+	digest     *worker.DigestScheduler
+	events     *worker.SymbolEventPublisher
+	usage      *worker.UsageFlusher
+	renewer    *worker.TokenRenewer
+	warmup     *services.WarmupService
+	hooks      *shutdown.Registry
 	logger     *slog.Logger
+
+	// crashed receives the first component that stops unexpectedly
+	crashed chan componentFailure
+}
+
+// componentFailure is a background component that returned an error
+type componentFailure struct {
+	component string
+	err       error
 }
 
 func buildApplication(ctx context.Context, cfg *config.Config, logger *slog.Logger) (*Application, error) {
 	logger.Info("building application")
 
-	// 1. Infrastructure Layer - Database
-	db, err := postgres.NewDB(ctx, cfg.Database, logger)
-	if err != nil {
-		return nil, err
+	// Chaos mode injects failures into the database, the exchange and the
+	// API; config validation keeps it out of production
+	var faults *chaos.Injector
+	var dbOpts []postgres.DBOption
+	var exchangeTransport http.RoundTripper
+	if cfg.Chaos.Enabled {
+		faults = chaos.NewInjector(chaos.Faults{
+			DBDropRate:        cfg.Chaos.DBDropRate,
+			ExchangeDelay:     cfg.Chaos.ExchangeDelay,
+			ExchangeDelayRate: cfg.Chaos.ExchangeDelayRate,
+			HTTPErrorRate:     cfg.Chaos.HTTPErrorRate,
+		}, retry.NewSource(time.Now().UnixNano()), logger)
+		dbOpts = append(dbOpts, postgres.WithPrepareConn(faults.PrepareConn))
+		exchangeTransport = faults.Transport(nil)
+
+		logger.Warn("chaos mode enabled",
+			"environment", cfg.Environment,
+			"db_drop_rate", cfg.Chaos.DBDropRate,
+			"exchange_delay", cfg.Chaos.ExchangeDelay,
+			"exchange_delay_rate", cfg.Chaos.ExchangeDelayRate,
+			"http_error_rate", cfg.Chaos.HTTPErrorRate,
+		)
 	}
 
-	// Run migrations
-	if err := db.Migrate(); err != nil {
-		db.Close()
-		return nil, err
+	// 1. Infrastructure Layer - Database
+	// Teardown runs in reverse registration order, so each component
+	// registers right after it is built and is stopped before its dependencies
+	slowQueries := services.NewSlowQueryCounter()
+	hooks := shutdown.NewRegistry()
+
+	// STORAGE_DRIVER=memory runs without a database; nothing survives a
+	// restart and the features config validation rejects need one.
+	// STORAGE_DRIVER=sqlite keeps the same features in a single file.
+	var (
+		db       *postgres.DB
+		sqliteDB *sqlite.DB
+		store    *memory.Store
+		analyzer ports.SnapshotTableAnalyzer
+	)
+	switch {
+	case cfg.Storage.InMemory():
+		store = memory.NewStore()
+		analyzer = store
+		logger.Warn("storing data in memory, it is lost on restart", "storage_driver", cfg.Storage.Driver)
+	case cfg.Storage.SQLite():
+		var err error
+		sqliteDB, err = sqlite.Open(ctx, cfg.Storage.SQLitePath, logger)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", errDatabaseUnreachable, err)
+		}
+
+		if err := sqliteDB.Migrate(); err != nil {
+			sqliteDB.Close()
+			return nil, fmt.Errorf("%w: %w", errMigration, err)
+		}
+
+		analyzer = sqliteDB
+		hooks.Register("database", 5*time.Second, func(ctx context.Context) error {
+			sqliteDB.Close()
+			return nil
+		})
+	default:
+		var err error
+		db, err = postgres.NewDB(ctx, cfg.Database, slowQueries, logger, dbOpts...)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", errDatabaseUnreachable, err)
+		}
+
+		// Run migrations
+		if err := db.Migrate(domain.MigrationPolicy(cfg.Database.MigrationPolicy)); err != nil {
+			db.Close()
+			return nil, fmt.Errorf("%w: %w", errMigration, err)
+		}
+
+		analyzer = db
+		hooks.Register("database", 5*time.Second, func(ctx context.Context) error {
+			db.Close()
+			return nil
+		})
 	}
 
 	// 2. Infrastructure Layer - Repositories
-	symbolRepo := postgres.NewSymbolRepository(db)
-	snapshotRepo := postgres.NewSnapshotRepository(db)
+	var (
+		symbolRepo      ports.SymbolRepository
+		symbolEventRepo ports.SymbolEventRepository
+		snapshotRepo    ports.SnapshotRepository
+		jobRepo         ports.JobRepository
+	)
+	switch {
+	case store != nil:
+		symbolRepo = memory.NewSymbolRepository(store)
+		symbolEventRepo = memory.NewSymbolEventRepository(store)
+		snapshotRepo = memory.NewSnapshotRepository(store)
+		jobRepo = memory.NewJobRepository(store)
+	case sqliteDB != nil:
+		symbolRepo = sqlite.NewSymbolRepository(sqliteDB)
+		symbolEventRepo = sqlite.NewSymbolEventRepository(sqliteDB)
+		snapshotRepo = sqlite.NewSnapshotRepository(sqliteDB)
+		jobRepo = sqlite.NewJobRepository(sqliteDB)
+	default:
+		symbolRepo = postgres.NewSymbolRepository(db)
+		symbolEventRepo = postgres.NewSymbolEventRepository(db)
+		snapshotRepo = postgres.NewSnapshotRepository(db)
+		jobRepo = postgres.NewJobRepository(db)
+	}
+
+	var symbolCache *services.CachedSymbolRepository
+	if cfg.Cache.SymbolTTL > 0 {
+		symbolCache = services.NewCachedSymbolRepository(symbolRepo, cfg.Cache.SymbolTTL, logger)
+		symbolRepo = symbolCache
+	}
+	if cfg.Cache.HistoryTTL > 0 {
+		historyCache := services.NewCachedHistoryRepository(snapshotRepo, cfg.Cache.HistoryTTL, cfg.Cache.HistoryEntries, logger)
+		snapshotRepo = historyCache
+		symbolRepo = historyCache.PurgingSymbols(symbolRepo)
+	}
+
+	// 24h ticker stats are only captured when POLLER_TICKER_STATS is enabled
+	var tickerRepo ports.TickerStatsRepository
+	if cfg.Poller.TickerStats {
+		tickerRepo = postgres.NewTickerStatsRepository(db)
+	}
 
 	// 3. Infrastructure Layer - Exchange Client
-	exchangeClient := binance.NewClient(
-		binance.WithBaseURL(cfg.Exchange.BaseURL),
-		binance.WithTimeout(cfg.Exchange.Timeout),
-		binance.WithRetry(cfg.Exchange.MaxRetries, cfg.Exchange.RetryBackoff),
-		binance.WithLogger(logger),
+	wallClock := clock.System()
+
+	// The poll, symbol validation, backfills and API requests share the
+	// exchange's weight limit by priority
+	var (
+		exchangeBudget ports.ExchangeBudget
+		weightBudget   *services.ExchangeBudget
 	)
+	if cfg.Exchange.WeightLimit > 0 {
+		weightBudget = services.NewExchangeBudget(cfg.Exchange.WeightLimit, cfg.Exchange.WeightWindow, wallClock, logger)
+		exchangeBudget = weightBudget
+	}
+
+	// Symbols are polled from their own exchange; features that are not
+	// per symbol use the default one
+	exchangeErrors := services.NewExchangeErrorCounter()
+	exchangeRetries := services.NewExchangeRetryCounter()
+	exchanges, err := exchange.Open(cfg.Exchange, exchange.Deps{
+		Errors:    exchangeErrors,
+		Retries:   exchangeRetries,
+		Logger:    logger,
+		Budget:    exchangeBudget,
+		Transport: exchangeTransport,
+	})
+	if err != nil {
+		hooks.Shutdown(context.Background())
+		return nil, err
+	}
+	exchangeClient, err := exchanges.Client("")
+	if err != nil {
+		hooks.Shutdown(context.Background())
+		return nil, err
+	}
+
+	// Rejected exchange credentials are reported, but public market data
+	// still works without them
+	if checker, ok := exchangeClient.(interface {
+		CheckCredentials(ctx context.Context) error
+	}); ok && cfg.Exchange.Authenticated() {
+		checkCtx, cancel := context.WithTimeout(ctx, cfg.Exchange.Timeout)
+		if err := checker.CheckCredentials(checkCtx); err != nil {
+			logger.Warn("exchange credentials check failed", "error", err)
+		}
+		cancel()
+	}
 
 	// 4. Service Layer
+	healthService := services.NewHealthService(symbolRepo, exchangeClient, cfg.Cache.HealthTTL, wallClock, logger)
+
 	metricsService := services.NewMetricsService(
 		symbolRepo,
 		snapshotRepo,
-		exchangeClient,
+		healthService,
+		exchangeErrors,
+		slowQueries,
+		wallClock,
 		logger,
 	)
 
+	metricLabels, err := cfg.Metrics.ConstLabels()
+	if err != nil {
+		hooks.Shutdown(context.Background())
+		return nil, err
+	}
+	metricsService.SetTarget(buildInfo(), metricLabels)
+	metricsService.SetSymbolLimits(cfg.Symbols.Limits())
+	metricsService.SetConfig(cfg.Effective())
+	metricsService.SetExchangeRetries(exchangeRetries)
+	if weightBudget != nil {
+		metricsService.SetExchangeBudget(weightBudget)
+	}
+	if db != nil {
+		metricsService.SetPoolStats(db)
+	}
+	if cfg.Metrics.Runtime {
+		metricsService.EnableRuntimeStats()
+	}
+
+	symbolNames, err := cfg.Symbols.Normalizer()
+	if err != nil {
+		hooks.Shutdown(context.Background())
+		return nil, err
+	}
+
 	symbolService := services.NewSymbolService(
 		symbolRepo,
-		exchangeClient,
+		symbolEventRepo,
+		exchanges,
+		symbolNames,
+		cfg.Symbols.Limits(),
+		cfg.Symbols.ValidationTimeout,
 		logger,
 	)
 
 	snapshotService := services.NewSnapshotService(
 		snapshotRepo,
 		symbolRepo,
+		symbolNames,
 		logger,
 	)
 
+	// Polled snapshots are written as each poll completes unless
+	// POLLER_WRITE_BUFFER_INTERVAL is set
+	var (
+		polledSnapshots ports.SnapshotRepository = snapshotRepo
+		snapshotBuffer  *services.BufferedSnapshotRepository
+	)
+	if cfg.Poller.WriteBufferInterval > 0 {
+		snapshotBuffer = services.NewBufferedSnapshotRepository(snapshotRepo, cfg.Poller.WriteBufferRows, logger)
+		polledSnapshots = snapshotBuffer
+	}
+
 	pollerService := services.NewPollerService(
 		symbolRepo,
-		snapshotRepo,
-		exchangeClient,
+		polledSnapshots,
+		tickerRepo,
+		exchanges,
+		metricsService,
+		domain.TimestampSource(cfg.Poller.TimestampSource),
+		cfg.Poller.Budget(),
+		domain.SnapshotWriteMode(cfg.Poller.WriteMode),
+		wallClock,
+		logger,
+	)
+	if db != nil {
+		pollerService.SetDatabaseFailover(db)
+	}
+	pollerService.SetPipelineVersion(buildInfo().Version)
+	pollerService.SetFetchMode(domain.PollFetchMode(cfg.Poller.FetchMode), cfg.Poller.FetchConcurrency)
+
+	synthetic, err := cfg.Symbols.SyntheticSymbols()
+	if err != nil {
+		hooks.Shutdown(context.Background())
+		return nil, err
+	}
+	if len(synthetic) > 0 {
+		pollerService.SetSyntheticSymbols(synthetic)
+		if err := pollerService.TrackSyntheticSymbols(ctx); err != nil {
+			hooks.Shutdown(context.Background())
+			return nil, fmt.Errorf("failed to track synthetic symbols: %w", err)
+		}
+	}
+
+	warmupService := services.NewWarmupService(
+		symbolRepo,
+		exchanges,
 		metricsService,
 		logger,
 	)
+	warmupService.SetEvents(symbolEventRepo)
+
+	// Retention is disabled when POLLER_RETENTION_DAYS <= 0
+	var prunerService *services.PrunerService
+	if cfg.Poller.RetentionDays > 0 {
+		prunerService = services.NewPrunerService(
+			symbolRepo,
+			snapshotRepo,
+			tickerRepo,
+			metricsService,
+			cfg.Poller.RetentionDays,
+			cfg.Poller.PruneConcurrency,
+			wallClock,
+			logger,
+		)
+	}
+
+	maintenanceService := services.NewMaintenanceService(analyzer, cfg.Storage.AnalyzeAfterRows, logger)
+	if prunerService != nil {
+		prunerService.SetMaintenance(maintenanceService)
+	}
+
+	// Compression is disabled when STORAGE_COMPRESS_AFTER is 0
+	var compressionService *services.CompressionService
+	if cfg.Storage.CompressAfter > 0 {
+		compressionService = services.NewCompressionService(
+			symbolRepo,
+			snapshotRepo,
+			cfg.Storage.CompressAfter,
+			cfg.Storage.CompressChunk,
+			logger,
+		)
+	}
+
+	// Candle aggregation is disabled unless CANDLES_ENABLED is set
+	var candleService *services.CandleService
+	if cfg.Candles.Enabled {
+		candleService = services.NewCandleService(
+			postgres.NewCandleRepository(db),
+			symbolRepo,
+			symbolNames,
+			cfg.Candles.Backfill,
+			wallClock,
+			logger,
+		)
+	}
+
+	jobService := services.NewJobService(
+		jobRepo,
+		cfg.Jobs.MaxAttempts,
+		cfg.Jobs.RetryBackoff,
+		cfg.Jobs.LeaseTimeout,
+		logger,
+	)
+	jobService.Register(domain.JobTypeBackfill, services.NewBackfillJob(exchanges, symbolRepo, snapshotRepo, symbolNames))
+	jobService.Register(domain.JobTypeImport, services.NewImportJob(symbolRepo, snapshotRepo, symbolNames, maintenanceService))
+	jobService.Register(domain.JobTypeRebuildCache, services.NewRebuildCacheJob(symbolCache))
+	jobService.Register(domain.JobTypeRevalidate, services.NewRevalidateJob(warmupService))
+	jobService.Register(domain.JobTypeMaintenance, services.NewMaintenanceJob(maintenanceService))
+	symbolService.SetRevalidation(jobService)
+	if prunerService != nil {
+		jobService.Register(domain.JobTypePrune, services.NewPruneJob(prunerService))
+	}
+
+	// Symbol metadata is only enriched when COINGECKO_ENABLED is set
+	var metadataService *services.MetadataService
+	if cfg.CoinGecko.Enabled {
+		metadataService = services.NewMetadataService(
+			symbolRepo,
+			coingecko.NewClient(
+				coingecko.WithBaseURL(cfg.CoinGecko.BaseURL),
+				coingecko.WithAPIKey(cfg.CoinGecko.APIKey),
+				coingecko.WithTimeout(cfg.CoinGecko.Timeout),
+				coingecko.WithLogger(logger),
+			),
+			logger,
+		)
+		jobService.Register(domain.JobTypeMetadata, services.NewRefreshMetadataJob(metadataService))
+	}
+
+	// Price alerts are only evaluated when ALERTS_ENABLED is set
+	var alertService *services.AlertService
+	if cfg.Alerts.Enabled {
+		alertRepo := postgres.NewAlertRepository(db)
+		alertService = services.NewAlertService(alertRepo, jobService, logger)
+		jobService.Register(domain.JobTypeAlertWebhook, services.NewAlertWebhookJob(
+			alertRepo,
+			notify.NewAlertWebhookNotifier(cfg.Alerts.WebhookTimeout),
+		))
+		pollerService.SetAlerts(alertService)
+	}
 
 	// 5. Transport Layer - HTTP Server
-	httpServer := httpAdapter.NewServer(
+	handlerOpts := []httpAdapter.HandlerOption{
+		httpAdapter.WithWarmup(warmupService),
+		httpAdapter.WithSymbolNormalizer(symbolNames),
+		httpAdapter.WithHealth(healthService),
+		httpAdapter.WithJobs(jobService, cfg.Jobs.MaxBodyBytes),
+		httpAdapter.WithHistoryCacheGrace(cfg.Poller.Interval + cfg.Poller.WriteBufferInterval),
+		httpAdapter.WithSymbolPoller(pollerService),
+		httpAdapter.WithRequestMetrics(metricsService),
+		httpAdapter.WithRetention(services.NewRetentionService(
+			symbolRepo,
+			snapshotRepo,
+			cfg.Poller.Interval,
+			cfg.Poller.RetentionDays,
+			cfg.Storage.DiskThresholdBytes,
+			logger,
+		)),
+	}
+	if db != nil {
+		handlerOpts = append(handlerOpts,
+			httpAdapter.WithMigrations(db),
+			httpAdapter.WithDatabaseFailover(db),
+		)
+	}
+	if cfg.Cache.PrecisionTTL > 0 {
+		handlerOpts = append(handlerOpts, httpAdapter.WithPrecision(
+			services.NewPrecisionService(exchangeClient, cfg.Cache.PrecisionTTL, logger),
+		))
+	}
+	if cfg.Server.RateLimit > 0 {
+		handlerOpts = append(handlerOpts, httpAdapter.WithRateLimiter(services.NewRateLimiter(
+			postgres.NewRateLimitRepository(db),
+			cfg.Server.RateLimit,
+			cfg.Server.RateLimitWindow,
+			logger,
+		), cfg.KnownAPIKeys()))
+	}
+	// The usage flusher is registered before the HTTP server so that it is
+	// stopped, and stores the last requests, after it
+	var usageFlusher *worker.UsageFlusher
+	if cfg.Usage.Enabled {
+		usageService := services.NewUsageService(postgres.NewUsageRepository(db), wallClock, logger)
+		handlerOpts = append(handlerOpts, httpAdapter.WithUsage(usageService, cfg.KnownAPIKeys()))
+		usageFlusher = worker.NewUsageFlusher(usageService, cfg.Usage.FlushInterval, wallClock, logger)
+		hooks.Register("usage_flusher", 0, stopHook(usageFlusher.Stop))
+	}
+	handlerOpts = append(handlerOpts, httpAdapter.WithAlertBacktest(
+		services.NewAlertBacktestService(snapshotRepo, symbolRepo, logger),
+	))
+	if cfg.Server.UI {
+		handlerOpts = append(handlerOpts, httpAdapter.WithUI())
+	}
+	if faults != nil {
+		handlerOpts = append(handlerOpts, httpAdapter.WithFaultInjector(faults))
+	}
+	if cfg.Ingest.Enabled() {
+		handlerOpts = append(handlerOpts, httpAdapter.WithIngest(
+			services.NewIngestService(symbolRepo, snapshotRepo, logger),
+			cfg.Ingest.APIKeys,
+		))
+	}
+	if alertService != nil {
+		handlerOpts = append(handlerOpts, httpAdapter.WithAlerts(alertService))
+	}
+	if candleService != nil {
+		handlerOpts = append(handlerOpts, httpAdapter.WithCandles(candleService))
+	}
+	if tickerRepo != nil {
+		handlerOpts = append(handlerOpts, httpAdapter.WithTicker(
+			services.NewTickerService(tickerRepo, symbolRepo, symbolNames, logger),
+		))
+	}
+
+	httpServer, err := httpAdapter.NewServer(
 		cfg.Server,
 		symbolService,
 		snapshotService,
 		metricsService,
 		exchangeClient,
 		logger,
+		handlerOpts...,
 	)
+	if err != nil {
+		hooks.Shutdown(context.Background())
+		return nil, err
+	}
+	hooks.Register("http_server", 15*time.Second, httpServer.Shutdown)
+
+	var grpcServer *grpcAdapter.Server
+	if cfg.GRPC.Enabled() {
+		grpcServer = grpcAdapter.NewServer(cfg.GRPC, symbolService, snapshotService, logger)
+		hooks.Register("grpc_server", 15*time.Second, grpcServer.Shutdown)
+	}
 
 	// 6. Background Workers
-	poller := worker.NewPoller(
-		pollerService,
-		cfg.Poller.Interval,
+	// The flusher is registered before the pollers so that it is stopped,
+	// and flushes what they buffered, after them
+	var flusher *worker.SnapshotFlusher
+	if snapshotBuffer != nil {
+		flusher = worker.NewSnapshotFlusher(snapshotBuffer, cfg.Poller.WriteBufferInterval, wallClock, logger)
+		hooks.Register("snapshot_flusher", 0, stopHook(flusher.Stop))
+	}
+
+	var (
+		pollers  []*worker.Poller
+		streamer *worker.Streamer
+	)
+	if cfg.Poller.Mode == "stream" {
+		streamer, err = buildStreamer(cfg, pollerService, exchange.Deps{
+			Logger:    logger,
+			Transport: exchangeTransport,
+		}, wallClock, hooks, logger)
+	} else {
+		pollers, err = buildPollers(cfg.Poller, pollerService, wallClock, hooks, logger)
+	}
+	if err != nil {
+		hooks.Shutdown(context.Background())
+		return nil, err
+	}
+
+	var pruner *worker.Pruner
+	if prunerService != nil {
+		pruner = worker.NewPruner(prunerService, cfg.Poller.PruneInterval, wallClock, logger)
+		hooks.Register("pruner", 0, stopHook(pruner.Stop))
+	}
+
+	var compressor *worker.Compressor
+	if compressionService != nil {
+		compressor = worker.NewCompressor(compressionService, cfg.Storage.CompressChunk, logger)
+		hooks.Register("compressor", 0, stopHook(compressor.Stop))
+	}
+
+	var candleAggregator *worker.CandleAggregator
+	if candleService != nil {
+		candleAggregator = worker.NewCandleAggregator(candleService, cfg.Candles.AggregateInterval, logger)
+		hooks.Register("candle_aggregator", 0, stopHook(candleAggregator.Stop))
+	}
+
+	var metadataRefresher *worker.MetadataRefresher
+	if metadataService != nil {
+		metadataRefresher = worker.NewMetadataRefresher(metadataService, cfg.CoinGecko.RefreshInterval, logger)
+		hooks.Register("metadata_refresher", 0, stopHook(metadataRefresher.Stop))
+	}
+
+	jobRunner := worker.NewJobRunner(
+		jobService,
+		cfg.Jobs.PollInterval,
+		cfg.Jobs.Concurrency,
 		logger,
 	)
+	hooks.Register("job_runner", 0, stopHook(jobRunner.Stop))
+
+	var digestScheduler *worker.DigestScheduler
+	if cfg.Digest.Enabled() {
+		digestService := services.NewDigestService(
+			snapshotRepo,
+			buildNotifiers(cfg.Digest),
+			cfg.Digest.TopN,
+			logger,
+		)
+
+		digestScheduler, err = worker.NewDigestScheduler(digestService, cfg.Digest.Schedule, logger)
+		if err != nil {
+			hooks.Shutdown(context.Background())
+			return nil, err
+		}
+		hooks.Register("digest_scheduler", 0, stopHook(digestScheduler.Stop))
+	}
+
+	var eventPublisher *worker.SymbolEventPublisher
+	if cfg.Events.Enabled() {
+		types, err := cfg.Events.EventTypes()
+		if err != nil {
+			hooks.Shutdown(context.Background())
+			return nil, err
+		}
+
+		format, err := cfg.Events.EventFormat()
+		if err != nil {
+			hooks.Shutdown(context.Background())
+			return nil, err
+		}
+		var registry *schema.Registry
+		if !format.Schemaless() {
+			registry = schema.NewRegistry(cfg.Events.SchemaRegistryURL, cfg.Events.Timeout)
+		}
+		encoder, err := schema.NewSymbolEventEncoder(format, registry, cfg.Events.SchemaSubject)
+		if err != nil {
+			hooks.Shutdown(context.Background())
+			return nil, err
+		}
+
+		feed := services.NewSymbolEventFeed(
+			symbolEventRepo,
+			notify.NewSymbolEventWebhookNotifier(cfg.Events.WebhookURL, encoder, cfg.Events.Timeout),
+			types,
+			logger,
+		)
+		eventPublisher = worker.NewSymbolEventPublisher(feed, cfg.Events.Interval, logger)
+		hooks.Register("symbol_event_publisher", 0, stopHook(eventPublisher.Stop))
+	}
+
+	var tokenRenewer *worker.TokenRenewer
+	if cfg.Vault.Enabled() {
+		tokenRenewer = worker.NewTokenRenewer(cfg.Vault.Client(), logger)
+		hooks.Register("token_renewer", 0, stopHook(tokenRenewer.Stop))
+	}
 
 	logger.Info("application built successfully")
 
 	return &Application{
-		db:         db,
 		httpServer: httpServer,
-		poller:     poller,
+		grpcServer: grpcServer,
+		pollers:    pollers,
+		streamer:   streamer,
+		pruner:     pruner,
+		compressor: compressor,
+		flusher:    flusher,
+		candles:    candleAggregator,
+		metadata:   metadataRefresher,
+		jobRunner:  jobRunner,
+		digest:     digestScheduler,
+		events:     eventPublisher,
+		usage:      usageFlusher,
+		renewer:    tokenRenewer,
+		warmup:     warmupService,
+		hooks:      hooks,
 		logger:     logger,
+		crashed:    make(chan componentFailure, 1),
 	}, nil
 }
 
+// stopHook adapts a worker's Stop method to a shutdown hook
+func stopHook(stop func() error) shutdown.HookFunc {
+	return func(ctx context.Context) error {
+		return stop()
+	}
+}
+
+// buildPollers returns a single poller over all active symbols, or one
+// poller per configured group, each registered for shutdown
+func buildPollers(
+	cfg config.PollerConfig,
+	pollerService *services.PollerService,
+	wallClock ports.Clock,
+	hooks *shutdown.Registry,
+	logger *slog.Logger,
+) ([]*worker.Poller, error) {
+	groups, err := cfg.PollerGroups()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(groups) == 0 {
+		poller := worker.NewPoller(pollerService, cfg.Interval, wallClock, logger)
+		hooks.Register("poller", 0, stopHook(poller.Stop))
+		return []*worker.Poller{poller}, nil
+	}
+
+	pollers := make([]*worker.Poller, 0, len(groups))
+	for _, group := range groups {
+		poller := worker.NewPoller(
+			pollerService.ForGroup(group.Name, groups),
+			group.Interval,
+			wallClock,
+			logger.With("group", group.Name),
+		)
+		hooks.Register("poller:"+group.Name, 0, stopHook(poller.Stop))
+		pollers = append(pollers, poller)
+	}
+
+	return pollers, nil
+}
+
+// buildStreamer returns a streamer writing the prices pushed by the
+// configured exchange's stream, registered for shutdown
+func buildStreamer(
+	cfg *config.Config,
+	pollerService *services.PollerService,
+	deps exchange.Deps,
+	wallClock ports.Clock,
+	hooks *shutdown.Registry,
+	logger *slog.Logger,
+) (*worker.Streamer, error) {
+	stream, err := exchange.NewStream(cfg.Exchange.Provider, cfg.Exchange, deps)
+	if err != nil {
+		return nil, err
+	}
+	pollerService.SetPriceStream(stream)
+
+	streamer := worker.NewStreamer(pollerService, cfg.Poller.StreamFlushInterval, wallClock, logger)
+	hooks.Register("poller", 0, stopHook(streamer.Stop))
+	return streamer, nil
+}
+
+// buildNotifiers returns a notifier for every configured digest channel
+func buildNotifiers(cfg config.DigestConfig) []ports.Notifier {
+	var notifiers []ports.Notifier
+
+	if cfg.WebhookURL != "" {
+		notifiers = append(notifiers, notify.NewWebhookNotifier(cfg.WebhookURL, cfg.Timeout))
+	}
+
+	if cfg.SlackWebhookURL != "" {
+		notifiers = append(notifiers, notify.NewSlackNotifier(cfg.SlackWebhookURL, cfg.Timeout))
+	}
+
+	if cfg.SMTPAddr != "" {
+		notifiers = append(notifiers, notify.NewEmailNotifier(
+			cfg.SMTPAddr,
+			cfg.SMTPUsername,
+			cfg.SMTPPassword,
+			cfg.EmailFrom,
+			cfg.EmailTo,
+		))
+	}
+
+	return notifiers
+}
+
 func (a *Application) Start(ctx context.Context) error {
 	a.logger.Info("starting application components")
 
-	// Start poller in background
-	go func() {
-		if err := a.poller.Start(ctx); err != nil {
-			a.logger.Error("poller error", "error", err)
+	// Verify tracked symbols before the first poll; readiness also waits
+	// for the poller's initial run to prime latest prices
+	warmupCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	if _, err := a.warmup.Warmup(warmupCtx); err != nil {
+		a.logger.Warn("warm-up failed, continuing", "error", err)
+	}
+	cancel()
+
+	// Start pollers in background
+	for i, poller := range a.pollers {
+		name := "poller"
+		if len(a.pollers) > 1 {
+			name = fmt.Sprintf("poller_%d", i)
 		}
-	}()
+		a.runComponent(ctx, name, poller.Start)
+	}
+
+	// Start streamer in background
+	if a.streamer != nil {
+		a.runComponent(ctx, "streamer", a.streamer.Start)
+	}
+
+	// Start snapshot flusher in background
+	if a.flusher != nil {
+		a.runComponent(ctx, "snapshot_flusher", a.flusher.Start)
+	}
+
+	// Start pruner in background
+	if a.pruner != nil {
+		a.runComponent(ctx, "pruner", a.pruner.Start)
+	}
+
+	// Start compressor in background
+	if a.compressor != nil {
+		a.runComponent(ctx, "compressor", a.compressor.Start)
+	}
+
+	// Start candle aggregator in background
+	if a.candles != nil {
+		a.runComponent(ctx, "candle_aggregator", a.candles.Start)
+	}
+
+	// Start metadata refresher in background
+	if a.metadata != nil {
+		a.runComponent(ctx, "metadata_refresher", a.metadata.Start)
+	}
+
+	// Start job runner in background
+	a.runComponent(ctx, "job_runner", a.jobRunner.Start)
+
+	// Start digest scheduler in background
+	if a.digest != nil {
+		a.runComponent(ctx, "digest_scheduler", a.digest.Start)
+	}
+
+	// Start symbol event publisher in background
+	if a.events != nil {
+		a.runComponent(ctx, "symbol_event_publisher", a.events.Start)
+	}
+
+	// Start usage flusher in background
+	if a.usage != nil {
+		a.runComponent(ctx, "usage_flusher", a.usage.Start)
+	}
+
+	// Start Vault token renewer in background
+	if a.renewer != nil {
+		a.runComponent(ctx, "token_renewer", a.renewer.Start)
+	}
 
 	// Start HTTP server in background (will block until shutdown)
-	go func() {
-		if err := a.httpServer.Start(); err != nil {
-			a.logger.Error("http server error", "error", err)
-		}
-	}()
+	a.runComponent(ctx, "http_server", func(context.Context) error {
+		return a.httpServer.Start()
+	})
+
+	// Start gRPC server in background
+	if a.grpcServer != nil {
+		a.runComponent(ctx, "grpc_server", func(context.Context) error {
+			return a.grpcServer.Start()
+		})
+	}
 
 	a.logger.Info("application started",
 		"http_addr", a.httpServer.Addr(),
+		"grpc_enabled", a.grpcServer != nil,
 	)
 
 	return nil
 }
 
+// runComponent starts a component in the background and reports it as
+// crashed if it returns an error other than cancellation
+func (a *Application) runComponent(ctx context.Context, name string, start func(context.Context) error) {
+	go func() {
+		err := start(ctx)
+		if err == nil || errors.Is(err, context.Canceled) {
+			return
+		}
+
+		a.logger.Error("component stopped unexpectedly", "component", name, "error", err)
+		select {
+		case a.crashed <- componentFailure{component: name, err: err}:
+		default:
+		}
+	}()
+}
+
 func (a *Application) Shutdown() {
 	a.logger.Info("shutting down application")
 
@@ -208,32 +950,29 @@ func (a *Application) Shutdown() {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	// Stop poller first
-	if err := a.poller.Stop(); err != nil {
-		a.logger.Error("failed to stop poller", "error", err)
+	if err := a.hooks.Shutdown(ctx); err != nil {
+		a.logger.Error("shutdown completed with errors", "error", err)
 	}
 
-	// Stop HTTP server
-	if err := a.httpServer.Shutdown(ctx); err != nil {
-		a.logger.Error("failed to shutdown http server", "error", err)
-	}
-
-	// Close database connection
-	a.db.Close()
-
 	a.logger.Info("application shutdown complete")
 }
 
-func waitForShutdown(ctx context.Context, cancel context.CancelFunc, app *Application, logger *slog.Logger) {
+func waitForShutdown(ctx context.Context, cancel context.CancelFunc, app *Application, logger *slog.Logger) exitReason {
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 
+	var reason exitReason
 	select {
 	case sig := <-sigChan:
 		logger.Info("received shutdown signal", "signal", sig)
-		cancel()
-		app.Shutdown()
+		reason = signalExit(sig.String())
+	case failure := <-app.crashed:
+		reason = crashExit(failure.component, failure.err)
 	case <-ctx.Done():
-		app.Shutdown()
+		reason = exitReason{code: exitClean, reason: "cancelled"}
 	}
+
+	cancel()
+	app.Shutdown()
+	return reason
 }
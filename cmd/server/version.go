@@ -0,0 +1,29 @@
+package main
+
+import (
+	"runtime"
+	"runtime/debug"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+)
+
+// version is set at build time with -ldflags "-X main.version=..."
+var version = "dev"
+
+// buildInfo returns the version, VCS revision and Go version of the binary
+func buildInfo() domain.BuildInfo {
+	info := domain.BuildInfo{
+		Version:   version,
+		GoVersion: runtime.Version(),
+	}
+
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range bi.Settings {
+			if setting.Key == "vcs.revision" {
+				info.Revision = setting.Value
+			}
+		}
+	}
+
+	return info
+}
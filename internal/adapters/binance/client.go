@@ -9,7 +9,9 @@ import (
 	"log/slog"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/shopspring/decimal"
@@ -20,28 +22,94 @@ import (
 )
 
 const (
-	defaultBaseURL = "https://api.binance.com"
-	tickerPath     = "/api/v3/ticker/price"
-	pingPath       = "/api/v3/ping"
-	exchangeInfo   = "/api/v3/exchangeInfo"
+	tickerPath    = "/api/v3/ticker/price"
+	ticker24hPath = "/api/v3/ticker/24hr"
+	pingPath      = "/api/v3/ping"
+	exchangeInfo  = "/api/v3/exchangeInfo"
+	klinesPath    = "/api/v3/klines"
+	maxKlines     = 1000
+
+	// usedWeightHeader reports the request weight used in the current
+	// minute by every client sharing this IP
+	usedWeightHeader = "X-MBX-USED-WEIGHT-1M"
 )
 
+// Request weights of the endpoints used, counted against the exchange's
+// per-minute limit
+const (
+	weightPing         = 1
+	weightPrice        = 2
+	weightPrices       = 4
+	weightKlines       = 2
+	weightExchangeInfo = 20
+)
+
+// ticker24hWeight returns the weight of a 24hr ticker request for n symbols
+func ticker24hWeight(n int) int {
+	switch {
+	case n <= 20:
+		return 2
+	case n <= 100:
+		return 40
+	default:
+		return 80
+	}
+}
+
 // Client implements the ExchangeClient interface for Binance
 type Client struct {
 	httpClient *http.Client
-	baseURL    string
+	hosts      *hostPool
 	retryConf  retry.Config
+	errors     ports.ExchangeErrorRecorder
+	retries    ports.ExchangeRetryRecorder
+	budget     ports.ExchangeBudget
+	recordDir  string
 	logger     *slog.Logger
+
+	// apiKey and secret authenticate requests; signed requests carry a
+	// timestamp corrected by clockOffset and are valid for recvWindow
+	apiKey      string
+	secret      []byte
+	recvWindow  time.Duration
+	clockOffset atomic.Int64
 }
 
 // ClientOption configures the client
 type ClientOption func(*Client)
 
-// WithBaseURL sets the base URL
+// WithBaseURL replaces the host list with a single base URL
 func WithBaseURL(url string) ClientOption {
 	return func(c *Client) {
 		if url != "" {
-			c.baseURL = url
+			c.hosts.set([]string{url})
+		}
+	}
+}
+
+// WithRegion replaces the host list with a predefined regional cluster.
+// Unknown regions are ignored.
+func WithRegion(region Region) ClientOption {
+	return func(c *Client) {
+		if hosts, ok := regionHosts[region]; ok {
+			c.hosts.set(hosts)
+		}
+	}
+}
+
+// WithFallbackURLs appends hosts tried, in order, when earlier hosts fail
+func WithFallbackURLs(urls ...string) ClientOption {
+	return func(c *Client) {
+		c.hosts.append(urls)
+	}
+}
+
+// WithHostCooldown sets how long a failing host is skipped before it is
+// tried again. The cooldown doubles with each consecutive failure.
+func WithHostCooldown(d time.Duration) ClientOption {
+	return func(c *Client) {
+		if d > 0 {
+			c.hosts.cooldown = d
 		}
 	}
 }
@@ -53,6 +121,25 @@ func WithTimeout(timeout time.Duration) ClientOption {
 	}
 }
 
+// WithTransport sets the HTTP transport used for requests. A nil transport
+// keeps the default.
+func WithTransport(transport http.RoundTripper) ClientOption {
+	return func(c *Client) {
+		if transport != nil {
+			c.httpClient.Transport = transport
+		}
+	}
+}
+
+// WithRecording saves every response received from Binance as a fixture in
+// dir, for replay in tests with NewReplayTransport. An empty dir disables
+// recording.
+func WithRecording(dir string) ClientOption {
+	return func(c *Client) {
+		c.recordDir = dir
+	}
+}
+
 // WithRetry configures retry behavior
 func WithRetry(maxRetries int, backoff time.Duration) ClientOption {
 	return func(c *Client) {
@@ -61,6 +148,44 @@ func WithRetry(maxRetries int, backoff time.Duration) ClientOption {
 	}
 }
 
+// WithRetryJitter sets how retry backoffs are randomized. Full jitter
+// keeps many replicas from retrying against Binance in lockstep.
+func WithRetryJitter(mode retry.JitterMode) ClientOption {
+	return func(c *Client) {
+		c.retryConf.JitterMode = mode
+	}
+}
+
+// WithRetrySource sets the random source used for retry jitter
+func WithRetrySource(src retry.Source) ClientOption {
+	return func(c *Client) {
+		c.retryConf.Rand = src
+	}
+}
+
+// WithErrorRecorder reports every failed request attempt, classified by kind
+func WithErrorRecorder(recorder ports.ExchangeErrorRecorder) ClientOption {
+	return func(c *Client) {
+		c.errors = recorder
+	}
+}
+
+// WithRetryRecorder reports every retry, classified by the kind of failure
+// retried, and the attempts made by every call
+func WithRetryRecorder(recorder ports.ExchangeRetryRecorder) ClientOption {
+	return func(c *Client) {
+		c.retries = recorder
+	}
+}
+
+// WithBudget makes every request attempt, retries included, wait for its
+// weight in budget and reports the used weight Binance returns
+func WithBudget(budget ports.ExchangeBudget) ClientOption {
+	return func(c *Client) {
+		c.budget = budget
+	}
+}
+
 // WithLogger sets the logger
 func WithLogger(logger *slog.Logger) ClientOption {
 	return func(c *Client) {
@@ -74,18 +199,177 @@ func NewClient(opts ...ClientOption) *Client {
 		httpClient: &http.Client{
 			Timeout: 10 * time.Second,
 		},
-		baseURL:   defaultBaseURL,
-		retryConf: retry.DefaultConfig(),
-		logger:    slog.Default().With("component", "binance_client"),
+		hosts:      newHostPool(regionHosts[RegionGlobal], defaultHostCooldown),
+		retryConf:  retry.DefaultConfig(),
+		logger:     slog.Default().With("component", "binance_client"),
+		recvWindow: defaultRecvWindow,
 	}
 
 	for _, opt := range opts {
 		opt(c)
 	}
 
+	if c.recordDir != "" {
+		c.httpClient.Transport = NewRecordingTransport(c.recordDir, c.httpClient.Transport, c.logger)
+		c.logger.Warn("recording exchange responses", "dir", c.recordDir)
+	}
+
 	return c
 }
 
+// Hosts returns the health of each configured API host in failover order
+func (c *Client) Hosts() []HostHealth {
+	return c.hosts.health()
+}
+
+// retryConfig returns the retry behavior for a call of an operation made
+// with ctx, which makes a single attempt when the caller asked for one.
+// Retries and attempts are reported to the retry recorder; calls cancelled
+// by the caller are not.
+func (c *Client) retryConfig(ctx context.Context, operation string) retry.Config {
+	conf := c.retryConf
+	if domain.SingleExchangeAttempt(ctx) {
+		conf.MaxRetries = 0
+	}
+
+	conf.Classify = retryable
+	conf.Delay = c.retryDelay
+
+	if c.retries != nil {
+		conf.OnRetry = func(_ int, err error) {
+			if kind := classifyError(err); kind != "" {
+				c.retries.RecordExchangeRetry(operation, kind)
+			}
+		}
+		conf.OnDone = func(attempts int, err error) {
+			if !errors.Is(err, context.Canceled) {
+				c.retries.RecordExchangeAttempts(operation, attempts)
+			}
+		}
+	}
+	return conf
+}
+
+// retryable reports whether a failed attempt is retried: errors marked
+// retryable, and rate limits, whose wait retryDelay decides on
+func retryable(err error) bool {
+	var limited *rateLimitError
+	return retry.IsRetryable(err) || errors.As(err, &limited)
+}
+
+// retryDelay waits at least as long as Binance asked after a rate limit,
+// but gives up rather than wait longer than the longest backoff. A ban
+// without a Retry-After is not retried.
+func (c *Client) retryDelay(_ int, err error, backoff time.Duration) (time.Duration, bool) {
+	var limited *rateLimitError
+	if !errors.As(err, &limited) {
+		return backoff, true
+	}
+	if limited.status == http.StatusTeapot && limited.retryAfter == 0 {
+		return 0, false
+	}
+	if limited.retryAfter > c.retryConf.MaxBackoff {
+		return 0, false
+	}
+	return max(backoff, limited.retryAfter), true
+}
+
+// get sends a GET request of the given weight to the first available host.
+// Transport errors, server errors and geo-blocking responses put the host
+// into cooldown and are returned as retryable, so the next attempt fails
+// over to another host. Rate limits return a rateLimitError carrying the
+// wait Binance asked for.
+func (c *Client) get(ctx context.Context, path string, query url.Values, weight int) (*http.Response, error) {
+	return c.send(ctx, path, query.Encode(), weight)
+}
+
+// send sends a GET request with an encoded query, see get. The API key, when
+// configured, is sent with every request.
+func (c *Client) send(ctx context.Context, path, rawQuery string, weight int) (*http.Response, error) {
+	if c.budget != nil {
+		if err := c.budget.Acquire(ctx, weight); err != nil {
+			return nil, err
+		}
+	}
+
+	host := c.hosts.pick()
+
+	u := host.url + path
+	if rawQuery != "" {
+		u += "?" + rawQuery
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	if c.apiKey != "" {
+		req.Header.Set(apiKeyHeader, c.apiKey)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		c.hostFailed(host, err)
+		c.logger.Debug("request failed, will retry", "host", host.url, "error", err)
+		return nil, retry.NewRetryableError(err)
+	}
+
+	if c.budget != nil {
+		if used, err := strconv.Atoi(resp.Header.Get(usedWeightHeader)); err == nil {
+			c.budget.Observe(used)
+		}
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests, resp.StatusCode == http.StatusTeapot:
+		resp.Body.Close()
+		err := &rateLimitError{status: resp.StatusCode, retryAfter: parseRetryAfter(resp.Header.Get("Retry-After"))}
+		c.logger.Warn("rate limited by exchange",
+			"status", resp.StatusCode,
+			"retry_after", err.retryAfter,
+			"used_weight", resp.Header.Get(usedWeightHeader))
+		return nil, err
+
+	case resp.StatusCode == http.StatusForbidden,
+		resp.StatusCode == http.StatusUnavailableForLegalReasons,
+		resp.StatusCode >= 500:
+		resp.Body.Close()
+		err := &statusError{status: resp.StatusCode}
+		c.hostFailed(host, err)
+		c.logger.Warn("exchange host error", "host", host.url, "status", resp.StatusCode)
+		return nil, retry.NewRetryableError(err)
+	}
+
+	if c.hosts.markSuccess(host) {
+		c.logger.Info("exchange host recovered", "host", host.url)
+	}
+
+	return resp, nil
+}
+
+// observed wraps a request attempt so that its failure, if any, is
+// classified and reported to the error recorder under the operation name
+func (c *Client) observed(operation string, fn func(ctx context.Context) error) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		err := fn(ctx)
+		if err != nil && c.errors != nil {
+			if kind := classifyError(err); kind != "" {
+				c.errors.RecordExchangeError(operation, kind)
+			}
+		}
+		return err
+	}
+}
+
+func (c *Client) hostFailed(host *hostState, err error) {
+	if c.hosts.markFailure(host, err) {
+		c.logger.Warn("exchange host marked unhealthy", "host", host.url, "error", err)
+	}
+}
+
 // tickerResponse represents the Binance API ticker response
 type tickerResponse struct {
 	Symbol string `json:"symbol"`
@@ -100,38 +384,17 @@ func (c *Client) GetPrices(ctx context.Context, symbols []string) ([]*domain.Pri
 
 	var result []*domain.Price
 
-	err := retry.Do(ctx, c.retryConf, func(ctx context.Context) error {
-		// Build URL with symbols parameter
-		u, _ := url.Parse(c.baseURL + tickerPath)
-		q := u.Query()
-
+	err := retry.Do(ctx, c.retryConfig(ctx, "prices"), c.observed("prices", func(ctx context.Context) error {
 		// Format symbols as JSON array: ["BTCUSDT","ETHUSDT"]
-		symbolsJSON := fmt.Sprintf(`["%s"]`, strings.Join(symbols, `","`))
-		q.Set("symbols", symbolsJSON)
-		u.RawQuery = q.Encode()
+		q := url.Values{}
+		q.Set("symbols", fmt.Sprintf(`["%s"]`, strings.Join(symbols, `","`)))
 
-		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+		resp, err := c.get(ctx, tickerPath, q, weightPrices)
 		if err != nil {
 			return err
 		}
-
-		resp, err := c.httpClient.Do(req)
-		if err != nil {
-			c.logger.Debug("request failed, will retry", "error", err)
-			return retry.NewRetryableError(err)
-		}
 		defer resp.Body.Close()
 
-		if resp.StatusCode == http.StatusTooManyRequests {
-			c.logger.Warn("rate limited by exchange")
-			return retry.NewRetryableError(domain.ErrRateLimited)
-		}
-
-		if resp.StatusCode >= 500 {
-			c.logger.Warn("exchange server error", "status", resp.StatusCode)
-			return retry.NewRetryableError(domain.ErrExchangeUnavailable)
-		}
-
 		if resp.StatusCode != http.StatusOK {
 			body, _ := io.ReadAll(resp.Body)
 			c.logger.Error("unexpected response",
@@ -143,8 +406,9 @@ func (c *Client) GetPrices(ctx context.Context, symbols []string) ([]*domain.Pri
 		var tickers []tickerResponse
 		if err := json.NewDecoder(resp.Body).Decode(&tickers); err != nil {
 			c.logger.Error("failed to decode response", "error", err)
-			return fmt.Errorf("failed to decode response: %w", err)
+			return fmt.Errorf("%w: %w", errDecode, err)
 		}
+		fetchedAt := time.Now().UTC()
 
 		result = make([]*domain.Price, 0, len(tickers))
 		for _, t := range tickers {
@@ -154,72 +418,130 @@ func (c *Client) GetPrices(ctx context.Context, symbols []string) ([]*domain.Pri
 				continue
 			}
 			result = append(result, &domain.Price{
-				Symbol: t.Symbol,
-				Price:  price,
+				Symbol:    t.Symbol,
+				Price:     price,
+				FetchedAt: fetchedAt,
 			})
 		}
 
 		return nil
-	})
+	}))
 
 	return result, err
 }
 
-// GetPrice fetches the current price for a single symbol
-func (c *Client) GetPrice(ctx context.Context, symbol string) (*domain.Price, error) {
-	var result *domain.Price
+// ticker24hResponse represents the subset of the Binance 24hr ticker response we use
+type ticker24hResponse struct {
+	Symbol             string `json:"symbol"`
+	PriceChangePercent string `json:"priceChangePercent"`
+	HighPrice          string `json:"highPrice"`
+	LowPrice           string `json:"lowPrice"`
+	Volume             string `json:"volume"`
+}
 
-	err := retry.Do(ctx, c.retryConf, func(ctx context.Context) error {
-		u, _ := url.Parse(c.baseURL + tickerPath)
-		q := u.Query()
-		q.Set("symbol", symbol)
-		u.RawQuery = q.Encode()
+// GetTickerStats fetches rolling 24h statistics for multiple symbols
+func (c *Client) GetTickerStats(ctx context.Context, symbols []string) ([]*domain.TickerStats, error) {
+	if len(symbols) == 0 {
+		return nil, nil
+	}
+
+	var result []*domain.TickerStats
 
-		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	err := retry.Do(ctx, c.retryConfig(ctx, "ticker24h"), c.observed("ticker24h", func(ctx context.Context) error {
+		q := url.Values{}
+		q.Set("symbols", fmt.Sprintf(`["%s"]`, strings.Join(symbols, `","`)))
+
+		resp, err := c.get(ctx, ticker24hPath, q, ticker24hWeight(len(symbols)))
 		if err != nil {
 			return err
 		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return domain.ErrInvalidResponse
+		}
+
+		var tickers []ticker24hResponse
+		if err := json.NewDecoder(resp.Body).Decode(&tickers); err != nil {
+			return fmt.Errorf("%w: %w", errDecode, err)
+		}
+
+		result = make([]*domain.TickerStats, 0, len(tickers))
+		for _, t := range tickers {
+			stats, err := parseTicker24h(t)
+			if err != nil {
+				c.logger.Warn("invalid 24h ticker", "symbol", t.Symbol, "error", err)
+				continue
+			}
+			result = append(result, stats)
+		}
 
-		resp, err := c.httpClient.Do(req)
+		return nil
+	}))
+
+	return result, err
+}
+
+func parseTicker24h(t ticker24hResponse) (*domain.TickerStats, error) {
+	values := make([]decimal.Decimal, 4)
+	for i, str := range []string{t.HighPrice, t.LowPrice, t.Volume, t.PriceChangePercent} {
+		v, err := decimal.NewFromString(str)
 		if err != nil {
-			return retry.NewRetryableError(err)
+			return nil, err
 		}
-		defer resp.Body.Close()
+		values[i] = v
+	}
+
+	return &domain.TickerStats{
+		Symbol:             t.Symbol,
+		High:               values[0],
+		Low:                values[1],
+		Volume:             values[2],
+		PriceChangePercent: values[3],
+	}, nil
+}
 
-		if resp.StatusCode == http.StatusTooManyRequests {
-			return retry.NewRetryableError(domain.ErrRateLimited)
+// GetPrice fetches the current price for a single symbol
+func (c *Client) GetPrice(ctx context.Context, symbol string) (*domain.Price, error) {
+	var result *domain.Price
+
+	err := retry.Do(ctx, c.retryConfig(ctx, "price"), c.observed("price", func(ctx context.Context) error {
+		q := url.Values{}
+		q.Set("symbol", symbol)
+
+		resp, err := c.get(ctx, tickerPath, q, weightPrice)
+		if err != nil {
+			return err
 		}
+		defer resp.Body.Close()
 
 		if resp.StatusCode == http.StatusBadRequest {
 			// Symbol doesn't exist
 			return domain.ErrInvalidSymbol
 		}
 
-		if resp.StatusCode >= 500 {
-			return retry.NewRetryableError(domain.ErrExchangeUnavailable)
-		}
-
 		if resp.StatusCode != http.StatusOK {
 			return domain.ErrInvalidResponse
 		}
 
 		var ticker tickerResponse
 		if err := json.NewDecoder(resp.Body).Decode(&ticker); err != nil {
-			return fmt.Errorf("failed to decode response: %w", err)
+			return fmt.Errorf("%w: %w", errDecode, err)
 		}
 
 		price, err := decimal.NewFromString(ticker.Price)
 		if err != nil {
-			return fmt.Errorf("failed to parse price: %w", err)
+			return fmt.Errorf("%w: invalid price: %w", errDecode, err)
 		}
 
 		result = &domain.Price{
-			Symbol: ticker.Symbol,
-			Price:  price,
+			Symbol:    ticker.Symbol,
+			Price:     price,
+			FetchedAt: time.Now().UTC(),
 		}
 
 		return nil
-	})
+	}))
 
 	return result, err
 }
@@ -236,17 +558,195 @@ func (c *Client) ValidateSymbol(ctx context.Context, symbol string) (bool, error
 	return true, nil
 }
 
-// Ping checks if Binance API is reachable
-func (c *Client) Ping(ctx context.Context) error {
-	return retry.Do(ctx, c.retryConf, func(ctx context.Context) error {
-		req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+pingPath, nil)
+// GetCandles fetches historical klines for a symbol between from and to
+func (c *Client) GetCandles(ctx context.Context, symbol, interval string, from, to time.Time, limit int) ([]*domain.Candle, error) {
+	if limit <= 0 || limit > maxKlines {
+		limit = maxKlines
+	}
+
+	var result []*domain.Candle
+
+	err := retry.Do(ctx, c.retryConfig(ctx, "klines"), c.observed("klines", func(ctx context.Context) error {
+		q := url.Values{}
+		q.Set("symbol", symbol)
+		q.Set("interval", interval)
+		q.Set("startTime", strconv.FormatInt(from.UnixMilli(), 10))
+		q.Set("endTime", strconv.FormatInt(to.UnixMilli(), 10))
+		q.Set("limit", strconv.Itoa(limit))
+
+		resp, err := c.get(ctx, klinesPath, q, weightKlines)
 		if err != nil {
 			return err
 		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode == http.StatusBadRequest {
+			return domain.ErrInvalidSymbol
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			return domain.ErrInvalidResponse
+		}
+
+		// Each kline is a positional array:
+		// [openTime, open, high, low, close, volume, closeTime, ...]
+		var klines [][]json.RawMessage
+		if err := json.NewDecoder(resp.Body).Decode(&klines); err != nil {
+			return fmt.Errorf("%w: %w", errDecode, err)
+		}
+
+		result = make([]*domain.Candle, 0, len(klines))
+		for _, k := range klines {
+			candle, err := parseKline(symbol, k)
+			if err != nil {
+				c.logger.Warn("invalid kline", "symbol", symbol, "error", err)
+				continue
+			}
+			result = append(result, candle)
+		}
+
+		return nil
+	}))
 
-		resp, err := c.httpClient.Do(req)
+	return result, err
+}
+
+func parseKline(symbol string, k []json.RawMessage) (*domain.Candle, error) {
+	if len(k) < 7 {
+		return nil, domain.ErrInvalidResponse
+	}
+
+	var openTime, closeTime int64
+	if err := json.Unmarshal(k[0], &openTime); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(k[6], &closeTime); err != nil {
+		return nil, err
+	}
+
+	prices := make([]decimal.Decimal, 4)
+	for i := range prices {
+		var str string
+		if err := json.Unmarshal(k[i+1], &str); err != nil {
+			return nil, err
+		}
+		price, err := decimal.NewFromString(str)
+		if err != nil {
+			return nil, err
+		}
+		prices[i] = price
+	}
+
+	return &domain.Candle{
+		Symbol:    symbol,
+		OpenTime:  time.UnixMilli(openTime).UTC(),
+		CloseTime: time.UnixMilli(closeTime).UTC(),
+		Open:      prices[0],
+		High:      prices[1],
+		Low:       prices[2],
+		Close:     prices[3],
+	}, nil
+}
+
+// exchangeInfoResponse represents the subset of the Binance exchangeInfo response we use
+type exchangeInfoResponse struct {
+	Symbols []struct {
+		Symbol  string `json:"symbol"`
+		Status  string `json:"status"`
+		Filters []struct {
+			FilterType string `json:"filterType"`
+			TickSize   string `json:"tickSize"`
+		} `json:"filters"`
+	} `json:"symbols"`
+}
+
+// exchangeInfo fetches exchange metadata, optionally restricted to symbols
+func (c *Client) exchangeInfo(ctx context.Context, symbols []string) (*exchangeInfoResponse, error) {
+	var query url.Values
+	if len(symbols) > 0 {
+		symbolsJSON, _ := json.Marshal(symbols)
+		query = url.Values{"symbols": {string(symbolsJSON)}}
+	}
+
+	var info exchangeInfoResponse
+	err := retry.Do(ctx, c.retryConfig(ctx, "exchange_info"), c.observed("exchange_info", func(ctx context.Context) error {
+		resp, err := c.get(ctx, exchangeInfo, query, weightExchangeInfo)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return domain.ErrInvalidResponse
+		}
+
+		info = exchangeInfoResponse{}
+		if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+			return fmt.Errorf("%w: %w", errDecode, err)
+		}
+
+		return nil
+	}))
+	if err != nil {
+		return nil, err
+	}
+
+	return &info, nil
+}
+
+// ListTradingSymbols returns all symbols with TRADING status in a single exchangeInfo call
+func (c *Client) ListTradingSymbols(ctx context.Context) ([]string, error) {
+	info, err := c.exchangeInfo(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]string, 0, len(info.Symbols))
+	for _, s := range info.Symbols {
+		if s.Status == "TRADING" {
+			result = append(result, s.Symbol)
+		}
+	}
+
+	return result, nil
+}
+
+// GetSymbolPrecisions returns the price tick size of the given symbols, or
+// of every listed symbol when none are given. Symbols without a price
+// filter are omitted.
+func (c *Client) GetSymbolPrecisions(ctx context.Context, symbols []string) ([]*domain.SymbolPrecision, error) {
+	info, err := c.exchangeInfo(ctx, symbols)
+	if err != nil {
+		return nil, err
+	}
+
+	precisions := make([]*domain.SymbolPrecision, 0, len(info.Symbols))
+	for _, s := range info.Symbols {
+		for _, f := range s.Filters {
+			if f.FilterType != "PRICE_FILTER" {
+				continue
+			}
+			tickSize, err := decimal.NewFromString(f.TickSize)
+			if err != nil {
+				c.logger.Warn("invalid tick size", "symbol", s.Symbol, "tick_size", f.TickSize)
+				break
+			}
+			if p, err := domain.NewSymbolPrecision(s.Symbol, tickSize); err == nil {
+				precisions = append(precisions, p)
+			}
+			break
+		}
+	}
+
+	return precisions, nil
+}
+
+// Ping checks if Binance API is reachable
+func (c *Client) Ping(ctx context.Context) error {
+	return retry.Do(ctx, c.retryConfig(ctx, "ping"), c.observed("ping", func(ctx context.Context) error {
+		resp, err := c.get(ctx, pingPath, nil, weightPing)
 		if err != nil {
-			return retry.NewRetryableError(err)
+			return err
 		}
 		defer resp.Body.Close()
 
@@ -255,7 +755,7 @@ func (c *Client) Ping(ctx context.Context) error {
 		}
 
 		return nil
-	})
+	}))
 }
 
 // Ensure Client implements ExchangeClient
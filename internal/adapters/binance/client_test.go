@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -82,6 +83,25 @@ func TestClient_GetPrice(t *testing.T) {
 		assert.Equal(t, "BTCUSDT", price.Symbol)
 		assert.Equal(t, 3, callCount) // Retried twice
 	})
+
+	t.Run("makes a single attempt when asked to", func(t *testing.T) {
+		callCount := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			callCount++
+			w.WriteHeader(http.StatusTooManyRequests)
+		}))
+		defer server.Close()
+
+		client := binance.NewClient(
+			binance.WithBaseURL(server.URL),
+			binance.WithRetry(3, 10*time.Millisecond),
+		)
+
+		ctx := domain.WithSingleExchangeAttempt(context.Background())
+		_, err := client.GetPrice(ctx, "BTCUSDT")
+		require.Error(t, err)
+		assert.Equal(t, 1, callCount)
+	})
 }
 
 func TestClient_GetPrices(t *testing.T) {
@@ -154,6 +174,109 @@ func TestClient_ValidateSymbol(t *testing.T) {
 	})
 }
 
+func TestClient_GetCandles(t *testing.T) {
+	t.Run("parses klines", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/api/v3/klines", r.URL.Path)
+			assert.Equal(t, "BTCUSDT", r.URL.Query().Get("symbol"))
+			assert.Equal(t, "1m", r.URL.Query().Get("interval"))
+			assert.Equal(t, "1705314600000", r.URL.Query().Get("startTime"))
+
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`[[1705314600000,"43100.00","43150.00","43090.00","43123.45","12.5",1705314659999,"0",10,"0","0","0"]]`))
+		}))
+		defer server.Close()
+
+		client := binance.NewClient(binance.WithBaseURL(server.URL))
+
+		from := time.UnixMilli(1705314600000)
+		candles, err := client.GetCandles(context.Background(), "BTCUSDT", "1m", from, from.Add(time.Hour), 0)
+		require.NoError(t, err)
+		require.Len(t, candles, 1)
+		assert.Equal(t, from.UTC(), candles[0].OpenTime)
+		assert.True(t, candles[0].Close.Equal(decimal.RequireFromString("43123.45")))
+		assert.True(t, candles[0].High.Equal(decimal.RequireFromString("43150")))
+	})
+}
+
+func TestClient_GetTickerStats(t *testing.T) {
+	t.Run("parses 24h tickers", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/api/v3/ticker/24hr", r.URL.Path)
+			assert.Equal(t, `["BTCUSDT"]`, r.URL.Query().Get("symbols"))
+
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`[{"symbol":"BTCUSDT","priceChangePercent":"-1.250","highPrice":"44000.00","lowPrice":"42500.50","volume":"18234.12"}]`))
+		}))
+		defer server.Close()
+
+		client := binance.NewClient(binance.WithBaseURL(server.URL))
+
+		stats, err := client.GetTickerStats(context.Background(), []string{"BTCUSDT"})
+		require.NoError(t, err)
+		require.Len(t, stats, 1)
+		assert.Equal(t, "BTCUSDT", stats[0].Symbol)
+		assert.True(t, stats[0].High.Equal(decimal.RequireFromString("44000")))
+		assert.True(t, stats[0].Low.Equal(decimal.RequireFromString("42500.5")))
+		assert.True(t, stats[0].Volume.Equal(decimal.RequireFromString("18234.12")))
+		assert.True(t, stats[0].PriceChangePercent.Equal(decimal.RequireFromString("-1.25")))
+	})
+}
+
+func TestClient_ListTradingSymbols(t *testing.T) {
+	t.Run("returns only trading symbols", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/api/v3/exchangeInfo", r.URL.Path)
+
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"symbols": []map[string]string{
+					{"symbol": "BTCUSDT", "status": "TRADING"},
+					{"symbol": "ETHUSDT", "status": "TRADING"},
+					{"symbol": "LUNAUSDT", "status": "BREAK"},
+				},
+			})
+		}))
+		defer server.Close()
+
+		client := binance.NewClient(binance.WithBaseURL(server.URL))
+
+		symbols, err := client.ListTradingSymbols(context.Background())
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"BTCUSDT", "ETHUSDT"}, symbols)
+	})
+}
+
+func TestClient_GetSymbolPrecisions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v3/exchangeInfo", r.URL.Path)
+		assert.Equal(t, `["BTCUSDT","SHIBUSDT","NOFILTER"]`, r.URL.Query().Get("symbols"))
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"symbols": [
+			{"symbol": "BTCUSDT", "status": "TRADING", "filters": [
+				{"filterType": "PRICE_FILTER", "minPrice": "0.01000000", "tickSize": "0.01000000"},
+				{"filterType": "LOT_SIZE", "stepSize": "0.00001000"}
+			]},
+			{"symbol": "SHIBUSDT", "status": "TRADING", "filters": [
+				{"filterType": "PRICE_FILTER", "tickSize": "0.00000001"}
+			]},
+			{"symbol": "NOFILTER", "status": "TRADING", "filters": []}
+		]}`))
+	}))
+	defer server.Close()
+
+	client := binance.NewClient(binance.WithBaseURL(server.URL))
+
+	precisions, err := client.GetSymbolPrecisions(context.Background(), []string{"BTCUSDT", "SHIBUSDT", "NOFILTER"})
+	require.NoError(t, err)
+	require.Len(t, precisions, 2)
+	assert.Equal(t, "BTCUSDT", precisions[0].Symbol)
+	assert.True(t, precisions[0].TickSize.Equal(decimal.RequireFromString("0.01")))
+	assert.Equal(t, int32(2), precisions[0].DisplayDecimals)
+	assert.Equal(t, int32(8), precisions[1].DisplayDecimals)
+}
+
 func TestClient_Ping(t *testing.T) {
 	t.Run("successful ping", func(t *testing.T) {
 		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -184,6 +307,74 @@ func TestClient_Ping(t *testing.T) {
 	})
 }
 
+func TestClient_Failover(t *testing.T) {
+	t.Run("fails over from geo-blocked host", func(t *testing.T) {
+		blocked := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnavailableForLegalReasons)
+		}))
+		defer blocked.Close()
+
+		var fallbackCalls atomic.Int32
+		fallback := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fallbackCalls.Add(1)
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer fallback.Close()
+
+		client := binance.NewClient(
+			binance.WithBaseURL(blocked.URL),
+			binance.WithFallbackURLs(fallback.URL),
+			binance.WithRetry(2, 10*time.Millisecond),
+		)
+
+		require.NoError(t, client.Ping(context.Background()))
+
+		hosts := client.Hosts()
+		require.Len(t, hosts, 2)
+		assert.False(t, hosts[0].Healthy)
+		assert.NotNil(t, hosts[0].RetryAt)
+		assert.True(t, hosts[1].Healthy)
+
+		// The blocked host is skipped while cooling down
+		require.NoError(t, client.Ping(context.Background()))
+		assert.Equal(t, int32(2), fallbackCalls.Load())
+	})
+
+	t.Run("returns to primary after cooldown", func(t *testing.T) {
+		var primaryDown atomic.Bool
+		primaryDown.Store(true)
+		primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if primaryDown.Load() {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer primary.Close()
+
+		fallback := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer fallback.Close()
+
+		client := binance.NewClient(
+			binance.WithBaseURL(primary.URL),
+			binance.WithFallbackURLs(fallback.URL),
+			binance.WithRetry(1, 10*time.Millisecond),
+			binance.WithHostCooldown(20*time.Millisecond),
+		)
+
+		require.NoError(t, client.Ping(context.Background()))
+		assert.False(t, client.Hosts()[0].Healthy)
+
+		primaryDown.Store(false)
+		time.Sleep(30 * time.Millisecond)
+
+		require.NoError(t, client.Ping(context.Background()))
+		assert.True(t, client.Hosts()[0].Healthy)
+	})
+}
+
 func findPrice(prices []*domain.Price, symbol string) *domain.Price {
 	for _, p := range prices {
 		if p.Symbol == symbol {
@@ -192,3 +383,304 @@ func findPrice(prices []*domain.Price, symbol string) *domain.Price {
 	}
 	return nil
 }
+
+type recordedError struct {
+	operation string
+	kind      domain.ExchangeErrorKind
+}
+
+type errorRecorder struct {
+	errors []recordedError
+}
+
+func (r *errorRecorder) RecordExchangeError(operation string, kind domain.ExchangeErrorKind) {
+	r.errors = append(r.errors, recordedError{operation, kind})
+}
+
+func TestClient_ErrorClassification(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		call    func(ctx context.Context, c *binance.Client) error
+		want    []recordedError
+	}{
+		{
+			name: "rate limited",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusTooManyRequests)
+			},
+			call: func(ctx context.Context, c *binance.Client) error {
+				_, err := c.GetPrices(ctx, []string{"BTCUSDT"})
+				return err
+			},
+			want: []recordedError{{"prices", domain.ExchangeErrorRateLimited}, {"prices", domain.ExchangeErrorRateLimited}},
+		},
+		{
+			name: "server error",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusBadGateway)
+			},
+			call: func(ctx context.Context, c *binance.Client) error {
+				return c.Ping(ctx)
+			},
+			want: []recordedError{{"ping", domain.ExchangeErrorServer}, {"ping", domain.ExchangeErrorServer}},
+		},
+		{
+			name: "geo-blocked",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusUnavailableForLegalReasons)
+			},
+			call: func(ctx context.Context, c *binance.Client) error {
+				_, err := c.ListTradingSymbols(ctx)
+				return err
+			},
+			want: []recordedError{{"exchange_info", domain.ExchangeErrorBlocked}, {"exchange_info", domain.ExchangeErrorBlocked}},
+		},
+		{
+			name: "invalid symbol",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusBadRequest)
+			},
+			call: func(ctx context.Context, c *binance.Client) error {
+				_, err := c.GetPrice(ctx, "NOPEUSDT")
+				return err
+			},
+			want: []recordedError{{"price", domain.ExchangeErrorInvalidSymbol}},
+		},
+		{
+			name: "decode error",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(`{"symbol":`))
+			},
+			call: func(ctx context.Context, c *binance.Client) error {
+				_, err := c.GetTickerStats(ctx, []string{"BTCUSDT"})
+				return err
+			},
+			want: []recordedError{{"ticker24h", domain.ExchangeErrorDecode}},
+		},
+		{
+			name: "timeout",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				time.Sleep(100 * time.Millisecond)
+			},
+			call: func(ctx context.Context, c *binance.Client) error {
+				_, err := c.GetCandles(ctx, "BTCUSDT", "1m", time.Now().Add(-time.Hour), time.Now(), 10)
+				return err
+			},
+			want: []recordedError{{"klines", domain.ExchangeErrorTimeout}, {"klines", domain.ExchangeErrorTimeout}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(tt.handler)
+			defer server.Close()
+
+			recorder := &errorRecorder{}
+			client := binance.NewClient(
+				binance.WithBaseURL(server.URL),
+				binance.WithRetry(1, time.Millisecond),
+				binance.WithTimeout(20*time.Millisecond),
+				binance.WithHostCooldown(time.Millisecond),
+				binance.WithErrorRecorder(recorder),
+			)
+
+			require.Error(t, tt.call(context.Background(), client))
+			assert.Equal(t, tt.want, recorder.errors)
+		})
+	}
+
+	t.Run("cancelled requests are not recorded", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{}`))
+		}))
+		defer server.Close()
+
+		recorder := &errorRecorder{}
+		client := binance.NewClient(binance.WithBaseURL(server.URL), binance.WithErrorRecorder(recorder))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		require.Error(t, client.Ping(ctx))
+		assert.Empty(t, recorder.errors)
+	})
+}
+
+type retryRecorder struct {
+	retries  []recordedError
+	attempts map[string][]int
+}
+
+func (r *retryRecorder) RecordExchangeRetry(operation string, reason domain.ExchangeErrorKind) {
+	r.retries = append(r.retries, recordedError{operation, reason})
+}
+
+func (r *retryRecorder) RecordExchangeAttempts(operation string, attempts int) {
+	if r.attempts == nil {
+		r.attempts = make(map[string][]int)
+	}
+	r.attempts[operation] = append(r.attempts[operation], attempts)
+}
+
+func TestClient_RecordsRetries(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch calls.Add(1) {
+		case 1:
+			w.WriteHeader(http.StatusTooManyRequests)
+		case 2:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			w.Write([]byte(`[{"symbol":"BTCUSDT","price":"43000.00"}]`))
+		}
+	}))
+	defer server.Close()
+
+	recorder := &retryRecorder{}
+	client := binance.NewClient(
+		binance.WithBaseURL(server.URL),
+		binance.WithRetry(3, time.Millisecond),
+		binance.WithHostCooldown(time.Millisecond),
+		binance.WithRetryRecorder(recorder),
+	)
+
+	for i := 0; i < 2; i++ {
+		_, err := client.GetPrices(context.Background(), []string{"BTCUSDT"})
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, []recordedError{
+		{"prices", domain.ExchangeErrorRateLimited},
+		{"prices", domain.ExchangeErrorServer},
+	}, recorder.retries)
+	assert.Equal(t, map[string][]int{"prices": {3, 1}}, recorder.attempts)
+
+	t.Run("cancelled calls are not recorded", func(t *testing.T) {
+		recorder := &retryRecorder{}
+		client := binance.NewClient(binance.WithBaseURL(server.URL), binance.WithRetryRecorder(recorder))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		require.Error(t, client.Ping(ctx))
+		assert.Empty(t, recorder.attempts)
+	})
+}
+
+func TestClient_RetryAfter(t *testing.T) {
+	t.Run("waits as long as the exchange asks", func(t *testing.T) {
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if calls.Add(1) == 1 {
+				w.Header().Set("Retry-After", "1")
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			w.Write([]byte(`{}`))
+		}))
+		defer server.Close()
+
+		client := binance.NewClient(
+			binance.WithBaseURL(server.URL),
+			binance.WithRetry(3, time.Millisecond),
+		)
+
+		start := time.Now()
+		require.NoError(t, client.Ping(context.Background()))
+		assert.GreaterOrEqual(t, time.Since(start), time.Second)
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	tests := []struct {
+		name       string
+		status     int
+		retryAfter string
+	}{
+		{name: "gives up on a wait longer than the longest backoff", status: http.StatusTooManyRequests, retryAfter: "120"},
+		{name: "gives up on a ban", status: http.StatusTeapot, retryAfter: "3600"},
+		{name: "gives up on a ban of unknown length", status: http.StatusTeapot},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls.Add(1)
+				if tt.retryAfter != "" {
+					w.Header().Set("Retry-After", tt.retryAfter)
+				}
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			client := binance.NewClient(
+				binance.WithBaseURL(server.URL),
+				binance.WithRetry(3, time.Millisecond),
+			)
+
+			_, err := client.GetPrices(context.Background(), []string{"BTCUSDT"})
+			assert.ErrorIs(t, err, domain.ErrRateLimited)
+			assert.Equal(t, int32(1), calls.Load())
+		})
+	}
+}
+
+type recordingBudget struct {
+	acquired []int
+	observed []int
+	err      error
+}
+
+func (b *recordingBudget) Acquire(ctx context.Context, weight int) error {
+	b.acquired = append(b.acquired, weight)
+	return b.err
+}
+
+func (b *recordingBudget) Observe(used int) {
+	b.observed = append(b.observed, used)
+}
+
+func TestClient_Budget(t *testing.T) {
+	t.Run("acquires the weight of every attempt", func(t *testing.T) {
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-MBX-USED-WEIGHT-1M", "42")
+			if calls.Add(1) == 1 {
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			json.NewEncoder(w).Encode([]map[string]string{{"symbol": "BTCUSDT", "price": "43123.45"}})
+		}))
+		defer server.Close()
+
+		budget := &recordingBudget{}
+		client := binance.NewClient(
+			binance.WithBaseURL(server.URL),
+			binance.WithRetry(2, time.Millisecond),
+			binance.WithBudget(budget),
+		)
+
+		_, err := client.GetPrices(context.Background(), []string{"BTCUSDT", "ETHUSDT"})
+		require.NoError(t, err)
+		assert.Equal(t, []int{4, 4}, budget.acquired)
+		assert.Equal(t, []int{42, 42}, budget.observed)
+	})
+
+	t.Run("does not send a request without budget", func(t *testing.T) {
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+		}))
+		defer server.Close()
+
+		client := binance.NewClient(
+			binance.WithBaseURL(server.URL),
+			binance.WithBudget(&recordingBudget{err: context.DeadlineExceeded}),
+		)
+
+		err := client.Ping(context.Background())
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Zero(t, calls.Load())
+	})
+}
@@ -0,0 +1,85 @@
+package binance_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/adapters/binance"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports/exchangetest"
+)
+
+func TestClient_Conformance(t *testing.T) {
+	exchangetest.Run(t, func(t *testing.T, s *exchangetest.Scenario) ports.ExchangeClient {
+		server := httptest.NewServer(fakeBinance(s))
+		t.Cleanup(server.Close)
+
+		return binance.NewClient(
+			binance.WithBaseURL(server.URL),
+			binance.WithRetry(exchangetest.MaxRetries, time.Millisecond),
+		)
+	})
+}
+
+// fakeBinance serves the price ticker and ping endpoints of the Binance API
+// as the scenario directs
+func fakeBinance(s *exchangetest.Scenario) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch s.Next() {
+		case exchangetest.FailRateLimited:
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		case exchangetest.FailServerError:
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		case exchangetest.FailMalformed:
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"symbol":`))
+			return
+		case exchangetest.FailHang:
+			s.Hang(r.Context())
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v3/ping":
+			w.Write([]byte(`{}`))
+
+		case "/api/v3/ticker/price":
+			if symbol := r.URL.Query().Get("symbol"); symbol != "" {
+				price, ok := s.Price(symbol)
+				if !ok {
+					w.WriteHeader(http.StatusBadRequest)
+					json.NewEncoder(w).Encode(map[string]interface{}{"code": -1121, "msg": "Invalid symbol."})
+					return
+				}
+				json.NewEncoder(w).Encode(map[string]string{"symbol": symbol, "price": price})
+				return
+			}
+
+			var symbols []string
+			if err := json.Unmarshal([]byte(r.URL.Query().Get("symbols")), &symbols); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			tickers := make([]map[string]string, 0, len(symbols))
+			for _, symbol := range symbols {
+				price, ok := s.Price(symbol)
+				if !ok {
+					w.WriteHeader(http.StatusBadRequest)
+					json.NewEncoder(w).Encode(map[string]interface{}{"code": -1121, "msg": "Invalid symbol."})
+					return
+				}
+				tickers = append(tickers, map[string]string{"symbol": symbol, "price": price})
+			}
+			json.NewEncoder(w).Encode(tickers)
+
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+}
@@ -0,0 +1,96 @@
+package binance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+)
+
+// errDecode marks a response body that could not be decoded
+var errDecode = errors.New("failed to decode response")
+
+// statusError is a response status that makes a host unusable. It unwraps
+// to domain.ErrExchangeUnavailable so callers see a single error.
+type statusError struct {
+	status int
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("unexpected status %d", e.status)
+}
+
+func (e *statusError) Unwrap() error {
+	return domain.ErrExchangeUnavailable
+}
+
+// rateLimitError is a 429 response, or a 418 once Binance has banned the IP
+// for sending requests after a 429. It unwraps to domain.ErrRateLimited.
+type rateLimitError struct {
+	status int
+
+	// retryAfter is how long Binance asked to wait, zero when it did not say
+	retryAfter time.Duration
+}
+
+func (e *rateLimitError) Error() string {
+	if e.retryAfter > 0 {
+		return fmt.Sprintf("rate limited with status %d, retry after %s", e.status, e.retryAfter)
+	}
+	return fmt.Sprintf("rate limited with status %d", e.status)
+}
+
+func (e *rateLimitError) Unwrap() error {
+	return domain.ErrRateLimited
+}
+
+// parseRetryAfter reads a Retry-After header in seconds, as Binance sends
+// it. Missing or malformed values return zero.
+func parseRetryAfter(v string) time.Duration {
+	seconds, err := strconv.Atoi(v)
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// classifyError maps a failed request attempt to its error kind. Requests
+// cancelled by the caller or shed by the request budget are not failures
+// and return an empty kind.
+func classifyError(err error) domain.ExchangeErrorKind {
+	var status *statusError
+	var netErr net.Error
+
+	switch {
+	case errors.Is(err, context.Canceled), errors.Is(err, domain.ErrExchangeBudget):
+		return ""
+	case errors.Is(err, context.DeadlineExceeded):
+		return domain.ExchangeErrorTimeout
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return domain.ExchangeErrorTimeout
+	case errors.As(err, &status):
+		if status.status == http.StatusForbidden || status.status == http.StatusUnavailableForLegalReasons {
+			return domain.ExchangeErrorBlocked
+		}
+		return domain.ExchangeErrorServer
+	case errors.Is(err, domain.ErrRateLimited):
+		return domain.ExchangeErrorRateLimited
+	case errors.Is(err, domain.ErrInvalidSymbol):
+		return domain.ExchangeErrorInvalidSymbol
+	case errors.Is(err, domain.ErrExchangeAuth):
+		return domain.ExchangeErrorAuth
+	case errors.Is(err, errDecode):
+		return domain.ExchangeErrorDecode
+	case errors.Is(err, domain.ErrInvalidResponse), errors.Is(err, domain.ErrExchangeUnavailable):
+		return domain.ExchangeErrorBadStatus
+	case errors.As(err, &netErr):
+		return domain.ExchangeErrorNetwork
+	default:
+		return domain.ExchangeErrorUnknown
+	}
+}
@@ -0,0 +1,175 @@
+package binance
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// Region selects a predefined Binance API cluster
+type Region string
+
+const (
+	// RegionGlobal is api.binance.com with its api1–api4 mirrors
+	RegionGlobal Region = "global"
+	// RegionUS is Binance.US, which lists a different set of markets
+	RegionUS Region = "us"
+)
+
+// regionHosts lists the hosts of each region in failover order
+var regionHosts = map[Region][]string{
+	RegionGlobal: {
+		"https://api.binance.com",
+		"https://api1.binance.com",
+		"https://api2.binance.com",
+		"https://api3.binance.com",
+		"https://api4.binance.com",
+	},
+	RegionUS: {
+		"https://api.binance.us",
+	},
+}
+
+const (
+	defaultHostCooldown = 30 * time.Second
+	maxHostCooldown     = 5 * time.Minute
+)
+
+// HostHealth describes the failover state of a single API host
+type HostHealth struct {
+	URL                 string     `json:"url"`
+	Healthy             bool       `json:"healthy"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	LastError           string     `json:"last_error,omitempty"`
+	RetryAt             *time.Time `json:"retry_at,omitempty"`
+}
+
+type hostState struct {
+	url           string
+	failures      int
+	lastErr       string
+	cooldownUntil time.Time
+}
+
+// hostPool tracks health independently for each host and picks the first
+// one in failover order that is not cooling down after a failure
+type hostPool struct {
+	mu       sync.Mutex
+	hosts    []*hostState
+	cooldown time.Duration
+}
+
+func newHostPool(urls []string, cooldown time.Duration) *hostPool {
+	p := &hostPool{cooldown: cooldown}
+	p.set(urls)
+	return p
+}
+
+// set replaces the pool's hosts, dropping duplicates and empty entries
+func (p *hostPool) set(urls []string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.hosts = nil
+	p.add(urls)
+}
+
+// append adds hosts after the existing ones
+func (p *hostPool) append(urls []string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.add(urls)
+}
+
+func (p *hostPool) add(urls []string) {
+	for _, u := range urls {
+		u = strings.TrimRight(strings.TrimSpace(u), "/")
+		if u == "" || p.find(u) != nil {
+			continue
+		}
+		p.hosts = append(p.hosts, &hostState{url: u})
+	}
+}
+
+func (p *hostPool) find(url string) *hostState {
+	for _, h := range p.hosts {
+		if h.url == url {
+			return h
+		}
+	}
+	return nil
+}
+
+// pick returns the first available host. When every host is cooling down,
+// the one that becomes available soonest is returned.
+func (p *hostPool) pick() *hostState {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	var soonest *hostState
+	for _, h := range p.hosts {
+		if !now.Before(h.cooldownUntil) {
+			return h
+		}
+		if soonest == nil || h.cooldownUntil.Before(soonest.cooldownUntil) {
+			soonest = h
+		}
+	}
+	return soonest
+}
+
+// markFailure puts a host into cooldown, doubling the cooldown on each
+// consecutive failure. Reports whether the host was healthy before.
+func (p *hostPool) markFailure(h *hostState, err error) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	wasHealthy := h.failures == 0
+	h.failures++
+	h.lastErr = err.Error()
+
+	cooldown := p.cooldown << (h.failures - 1)
+	if cooldown > maxHostCooldown || cooldown <= 0 {
+		cooldown = maxHostCooldown
+	}
+	h.cooldownUntil = time.Now().Add(cooldown)
+
+	return wasHealthy
+}
+
+// markSuccess clears a host's failure state. Reports whether it had been failing.
+func (p *hostPool) markSuccess(h *hostState) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	recovered := h.failures > 0
+	h.failures = 0
+	h.lastErr = ""
+	h.cooldownUntil = time.Time{}
+
+	return recovered
+}
+
+// health returns a snapshot of every host's state
+func (p *hostPool) health() []HostHealth {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	result := make([]HostHealth, len(p.hosts))
+	for i, h := range p.hosts {
+		result[i] = HostHealth{
+			URL:                 h.url,
+			Healthy:             h.failures == 0,
+			ConsecutiveFailures: h.failures,
+			LastError:           h.lastErr,
+		}
+		if now.Before(h.cooldownUntil) {
+			retryAt := h.cooldownUntil.UTC()
+			result[i].RetryAt = &retryAt
+		}
+	}
+	return result
+}
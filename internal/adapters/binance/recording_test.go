@@ -0,0 +1,137 @@
+package binance_test
+
+import (
+	"context"
+	"flag"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/adapters/binance"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+)
+
+// record refreshes the fixtures from the live Binance API:
+//
+//	go test ./internal/adapters/binance -run TestClient_Fixtures -record
+var record = flag.Bool("record", false, "record Binance fixtures from the live API")
+
+const fixtureDir = "testdata/fixtures"
+
+func TestRecordingTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json;charset=UTF-8")
+		w.Header().Set("X-Mbx-Used-Weight-1m", "2")
+		if r.URL.Query().Get("symbol") != "BTCUSDT" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"code":-1121,"msg":"Invalid symbol."}`))
+			return
+		}
+		w.Write([]byte(`{"symbol":"BTCUSDT","price":"43123.45000000"}`))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	recorder := binance.NewClient(
+		binance.WithBaseURL(server.URL),
+		binance.WithRecording(dir),
+		binance.WithRetry(0, 0),
+		binance.WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+	)
+
+	price, err := recorder.GetPrice(context.Background(), "BTCUSDT")
+	require.NoError(t, err)
+	_, err = recorder.GetPrice(context.Background(), "NOPE")
+	require.ErrorIs(t, err, domain.ErrInvalidSymbol)
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 2)
+
+	replayer := binance.NewClient(
+		binance.WithBaseURL("http://replay.invalid"),
+		binance.WithTransport(binance.NewReplayTransport(os.DirFS(dir))),
+		binance.WithRetry(0, 0),
+	)
+
+	t.Run("replays a recorded response", func(t *testing.T) {
+		replayed, err := replayer.GetPrice(context.Background(), "BTCUSDT")
+		require.NoError(t, err)
+		assert.Equal(t, price.Symbol, replayed.Symbol)
+		assert.True(t, price.Price.Equal(replayed.Price))
+	})
+
+	t.Run("replays a recorded error body", func(t *testing.T) {
+		_, err := replayer.GetPrice(context.Background(), "NOPE")
+		assert.ErrorIs(t, err, domain.ErrInvalidSymbol)
+	})
+
+	t.Run("fails a request that was not recorded", func(t *testing.T) {
+		_, err := replayer.GetPrice(context.Background(), "ETHUSDT")
+		assert.ErrorIs(t, err, binance.ErrNoFixture)
+	})
+}
+
+// TestClient_Fixtures checks response parsing against recorded Binance
+// payloads, including error bodies
+func TestClient_Fixtures(t *testing.T) {
+	opts := []binance.ClientOption{
+		binance.WithRetry(0, 0),
+		binance.WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+	}
+	if *record {
+		opts = append(opts, binance.WithRecording(fixtureDir))
+	} else {
+		opts = append(opts, binance.WithTransport(binance.NewReplayTransport(os.DirFS(fixtureDir))))
+	}
+	client := binance.NewClient(opts...)
+	ctx := context.Background()
+
+	t.Run("prices", func(t *testing.T) {
+		prices, err := client.GetPrices(ctx, []string{"BTCUSDT", "ETHUSDT"})
+		require.NoError(t, err)
+		require.Len(t, prices, 2)
+		for _, p := range prices {
+			assert.True(t, p.Price.IsPositive(), p.Symbol)
+		}
+	})
+
+	t.Run("invalid symbol", func(t *testing.T) {
+		_, err := client.GetPrice(ctx, "NOTAREALPAIR")
+		assert.ErrorIs(t, err, domain.ErrInvalidSymbol)
+	})
+
+	t.Run("24h ticker stats", func(t *testing.T) {
+		stats, err := client.GetTickerStats(ctx, []string{"BTCUSDT"})
+		require.NoError(t, err)
+		require.Len(t, stats, 1)
+		assert.True(t, stats[0].High.GreaterThanOrEqual(stats[0].Low))
+		assert.True(t, stats[0].Volume.IsPositive())
+	})
+
+	t.Run("symbol precisions", func(t *testing.T) {
+		precisions, err := client.GetSymbolPrecisions(ctx, []string{"BTCUSDT"})
+		require.NoError(t, err)
+		require.Len(t, precisions, 1)
+		assert.True(t, precisions[0].TickSize.GreaterThan(decimal.Zero))
+	})
+
+	t.Run("candles", func(t *testing.T) {
+		from := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+		candles, err := client.GetCandles(ctx, "BTCUSDT", "1h", from, from.Add(2*time.Hour), 3)
+		require.NoError(t, err)
+		require.Len(t, candles, 3)
+		assert.Equal(t, from, candles[0].OpenTime)
+		for _, c := range candles {
+			assert.True(t, c.High.GreaterThanOrEqual(c.Low))
+		}
+	})
+}
@@ -0,0 +1,196 @@
+package binance
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+	"github.com/prxgr4mmer/price-snapshot-service/pkg/retry"
+)
+
+const (
+	serverTimePath      = "/api/v3/time"
+	apiRestrictionsPath = "/sapi/v1/account/apiRestrictions"
+
+	weightServerTime      = 1
+	weightAPIRestrictions = 1
+
+	// apiKeyHeader carries the API key of authenticated requests
+	apiKeyHeader = "X-MBX-APIKEY"
+
+	defaultRecvWindow = 5 * time.Second
+
+	// MaxRecvWindow is the longest Binance accepts a signed request for
+	MaxRecvWindow = time.Minute
+)
+
+// Error codes Binance answers signed requests with
+const (
+	codeInvalidTimestamp = -1021
+	codeInvalidSignature = -1022
+	codeBadAPIKeyFormat  = -2014
+	codeRejectedAPIKey   = -2015
+)
+
+// signedParams are the query parameters added by signing, which differ on
+// every request
+var signedParams = []string{"timestamp", "recvWindow", "signature"}
+
+var (
+	// errNoCredentials is a signed request made without an API key and secret
+	errNoCredentials = errors.New("no API key and secret configured")
+
+	// errClockSkew is a signed request whose timestamp fell outside its
+	// recvWindow on the server
+	errClockSkew = fmt.Errorf("%w: request timestamp outside recvWindow", domain.ErrInvalidResponse)
+)
+
+// WithCredentials authenticates requests with an API key, and signs those
+// to endpoints that require it with the secret. An empty key leaves the
+// client anonymous.
+func WithCredentials(apiKey, secret string) ClientOption {
+	return func(c *Client) {
+		c.apiKey = apiKey
+		c.secret = []byte(secret)
+	}
+}
+
+// WithRecvWindow sets how long after its timestamp Binance accepts a signed
+// request, up to MaxRecvWindow. A non-positive window keeps the default of
+// 5 seconds.
+func WithRecvWindow(d time.Duration) ClientOption {
+	return func(c *Client) {
+		if d > 0 {
+			c.recvWindow = min(d, MaxRecvWindow)
+		}
+	}
+}
+
+// getSigned sends a GET request signed with the secret, see get. Its
+// timestamp is the local time corrected by the last measured offset to the
+// exchange's clock. A request rejected for its timestamp measures the
+// offset again and is returned as retryable; rejected credentials fail with
+// domain.ErrExchangeAuth.
+func (c *Client) getSigned(ctx context.Context, path string, query url.Values, weight int) (*http.Response, error) {
+	if c.apiKey == "" || len(c.secret) == 0 {
+		return nil, errNoCredentials
+	}
+
+	q := make(url.Values, len(query)+2)
+	for key, values := range query {
+		q[key] = values
+	}
+	q.Set("timestamp", strconv.FormatInt(c.serverNow().UnixMilli(), 10))
+	q.Set("recvWindow", strconv.FormatInt(c.recvWindow.Milliseconds(), 10))
+
+	// The signature covers the query exactly as sent, so it goes last
+	rawQuery := q.Encode()
+	rawQuery += "&signature=" + c.sign(rawQuery)
+
+	resp, err := c.send(ctx, path, rawQuery, weight)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusBadRequest && resp.StatusCode != http.StatusUnauthorized {
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	var apiErr struct {
+		Code int `json:"code"`
+	}
+	_ = json.Unmarshal(body, &apiErr)
+
+	switch {
+	case apiErr.Code == codeInvalidTimestamp:
+		if err := c.syncClock(ctx); err != nil {
+			return nil, err
+		}
+		return nil, retry.NewRetryableError(errClockSkew)
+	case apiErr.Code == codeInvalidSignature,
+		apiErr.Code == codeBadAPIKeyFormat,
+		apiErr.Code == codeRejectedAPIKey,
+		resp.StatusCode == http.StatusUnauthorized:
+		return nil, domain.ErrExchangeAuth
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return resp, nil
+}
+
+// sign returns the hex HMAC-SHA256 of a query with the secret
+func (c *Client) sign(rawQuery string) string {
+	mac := hmac.New(sha256.New, c.secret)
+	mac.Write([]byte(rawQuery))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// serverNow returns the exchange's current time as last measured
+func (c *Client) serverNow() time.Time {
+	return time.Now().Add(time.Duration(c.clockOffset.Load()))
+}
+
+// syncClock measures the offset of the exchange's clock to the local one,
+// assuming the server read its clock halfway through the request
+func (c *Client) syncClock(ctx context.Context) error {
+	sent := time.Now()
+	resp, err := c.send(ctx, serverTimePath, "", weightServerTime)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	received := time.Now()
+
+	if resp.StatusCode != http.StatusOK {
+		return domain.ErrInvalidResponse
+	}
+
+	var serverTime struct {
+		ServerTime int64 `json:"serverTime"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&serverTime); err != nil {
+		return fmt.Errorf("%w: %w", errDecode, err)
+	}
+
+	local := sent.Add(received.Sub(sent) / 2)
+	offset := time.UnixMilli(serverTime.ServerTime).Sub(local)
+	c.clockOffset.Store(int64(offset))
+
+	c.logger.Info("synced clock with exchange", "offset", offset)
+	return nil
+}
+
+// CheckCredentials verifies the configured API key and secret with a signed
+// request for the key's restrictions. It fails with domain.ErrExchangeAuth
+// when Binance rejects them.
+func (c *Client) CheckCredentials(ctx context.Context) error {
+	return retry.Do(ctx, c.retryConfig(ctx, "api_restrictions"), c.observed("api_restrictions", func(ctx context.Context) error {
+		resp, err := c.getSigned(ctx, apiRestrictionsPath, nil, weightAPIRestrictions)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return domain.ErrInvalidResponse
+		}
+
+		return nil
+	}))
+}
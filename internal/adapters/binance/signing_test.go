@@ -0,0 +1,130 @@
+package binance_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/adapters/binance"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+)
+
+// signature returns what Binance expects as the signature of a raw query
+func signature(secret, rawQuery string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(rawQuery))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestClient_CheckCredentials(t *testing.T) {
+	t.Run("sends a signed request with the API key", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/sapi/v1/account/apiRestrictions", r.URL.Path)
+			assert.Equal(t, "key", r.Header.Get("X-MBX-APIKEY"))
+			assert.Equal(t, "10000", r.URL.Query().Get("recvWindow"))
+
+			timestamp, err := strconv.ParseInt(r.URL.Query().Get("timestamp"), 10, 64)
+			require.NoError(t, err)
+			assert.WithinDuration(t, time.Now(), time.UnixMilli(timestamp), 5*time.Second)
+
+			signed, sig, found := strings.Cut(r.URL.RawQuery, "&signature=")
+			require.True(t, found, "signature must be the last parameter")
+			assert.Equal(t, signature("secret", signed), sig)
+
+			w.Write([]byte(`{"enableReading": true}`))
+		}))
+		defer server.Close()
+
+		client := binance.NewClient(
+			binance.WithBaseURL(server.URL),
+			binance.WithCredentials("key", "secret"),
+			binance.WithRecvWindow(10*time.Second),
+		)
+
+		require.NoError(t, client.CheckCredentials(context.Background()))
+	})
+
+	t.Run("resyncs the clock when the timestamp is rejected", func(t *testing.T) {
+		serverTime := time.Now().Add(time.Hour)
+		var attempts atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/api/v3/time" {
+				w.Write([]byte(`{"serverTime": ` + strconv.FormatInt(serverTime.UnixMilli(), 10) + `}`))
+				return
+			}
+
+			timestamp, _ := strconv.ParseInt(r.URL.Query().Get("timestamp"), 10, 64)
+			if attempts.Add(1) == 1 {
+				assert.WithinDuration(t, time.Now(), time.UnixMilli(timestamp), 5*time.Second)
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"code": -1021, "msg": "Timestamp for this request is outside of the recvWindow."}`))
+				return
+			}
+			assert.WithinDuration(t, serverTime, time.UnixMilli(timestamp), 5*time.Second)
+			w.Write([]byte(`{}`))
+		}))
+		defer server.Close()
+
+		client := binance.NewClient(
+			binance.WithBaseURL(server.URL),
+			binance.WithCredentials("key", "secret"),
+			binance.WithRetry(2, time.Millisecond),
+		)
+
+		require.NoError(t, client.CheckCredentials(context.Background()))
+		assert.Equal(t, int32(2), attempts.Load())
+	})
+
+	t.Run("fails without retrying when the key is rejected", func(t *testing.T) {
+		var attempts atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts.Add(1)
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"code": -2015, "msg": "Invalid API-key, IP, or permissions for action."}`))
+		}))
+		defer server.Close()
+
+		client := binance.NewClient(
+			binance.WithBaseURL(server.URL),
+			binance.WithCredentials("key", "wrong"),
+			binance.WithRetry(3, time.Millisecond),
+		)
+
+		err := client.CheckCredentials(context.Background())
+		assert.ErrorIs(t, err, domain.ErrExchangeAuth)
+		assert.Equal(t, int32(1), attempts.Load())
+	})
+
+	t.Run("fails without credentials", func(t *testing.T) {
+		client := binance.NewClient(binance.WithBaseURL("http://127.0.0.1:1"))
+
+		assert.Error(t, client.CheckCredentials(context.Background()))
+	})
+}
+
+func TestClient_SendsAPIKeyOnPublicRequests(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "key", r.Header.Get("X-MBX-APIKEY"))
+		assert.Empty(t, r.URL.Query().Get("signature"))
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client := binance.NewClient(
+		binance.WithBaseURL(server.URL),
+		binance.WithCredentials("key", "secret"),
+	)
+
+	require.NoError(t, client.Ping(context.Background()))
+}
@@ -0,0 +1,276 @@
+package binance
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+	"github.com/prxgr4mmer/price-snapshot-service/pkg/retry"
+)
+
+// tickerArrStream is the all-market 24hr ticker stream, pushed about once
+// a second with every symbol whose ticker changed
+const tickerArrStream = "!ticker@arr"
+
+const (
+	defaultStreamHandshakeTimeout = 10 * time.Second
+
+	// defaultStreamReadTimeout is how long the stream may stay silent
+	// before the connection is presumed dead and replaced
+	defaultStreamReadTimeout = time.Minute
+)
+
+// regionStreamURLs lists the market data stream endpoint of each region
+var regionStreamURLs = map[Region]string{
+	RegionGlobal: "wss://stream.binance.com:9443/ws",
+	RegionUS:     "wss://stream.binance.us:9443/ws",
+}
+
+// Stream implements the ports.PriceStream interface with Binance's
+// websocket market data streams
+type Stream struct {
+	url              string
+	httpClient       *http.Client
+	handshakeTimeout time.Duration
+	readTimeout      time.Duration
+	backoff          retry.Config
+	logger           *slog.Logger
+}
+
+// StreamOption configures the stream
+type StreamOption func(*Stream)
+
+// WithStreamURL replaces the stream endpoint, e.g. for a proxy or a test
+// server. An empty url keeps the region's endpoint.
+func WithStreamURL(url string) StreamOption {
+	return func(s *Stream) {
+		if url != "" {
+			s.url = url
+		}
+	}
+}
+
+// WithStreamRegion selects the stream endpoint of a predefined regional
+// cluster. Unknown regions are ignored.
+func WithStreamRegion(region Region) StreamOption {
+	return func(s *Stream) {
+		if url, ok := regionStreamURLs[region]; ok {
+			s.url = url
+		}
+	}
+}
+
+// WithStreamReadTimeout sets how long the stream may stay silent before
+// it is reconnected
+func WithStreamReadTimeout(d time.Duration) StreamOption {
+	return func(s *Stream) {
+		if d > 0 {
+			s.readTimeout = d
+		}
+	}
+}
+
+// WithStreamReconnect sets the backoff between reconnect attempts, which
+// doubles up to max while the exchange stays unreachable
+func WithStreamReconnect(initial, max time.Duration) StreamOption {
+	return func(s *Stream) {
+		if initial > 0 {
+			s.backoff.InitialBackoff = initial
+		}
+		if max > 0 {
+			s.backoff.MaxBackoff = max
+		}
+	}
+}
+
+// WithStreamTransport sets the HTTP transport used for the handshake. A
+// nil transport keeps the default.
+func WithStreamTransport(transport http.RoundTripper) StreamOption {
+	return func(s *Stream) {
+		if transport != nil {
+			s.httpClient.Transport = transport
+		}
+	}
+}
+
+// WithStreamLogger sets the logger
+func WithStreamLogger(logger *slog.Logger) StreamOption {
+	return func(s *Stream) {
+		s.logger = logger.With("component", "binance_stream")
+	}
+}
+
+// NewStream creates a new Binance market data stream
+func NewStream(opts ...StreamOption) *Stream {
+	backoff := retry.DefaultConfig()
+	backoff.InitialBackoff = time.Second
+	backoff.MaxBackoff = time.Minute
+	backoff.JitterMode = retry.JitterFull
+
+	s := &Stream{
+		url:              regionStreamURLs[RegionGlobal],
+		httpClient:       &http.Client{},
+		handshakeTimeout: defaultStreamHandshakeTimeout,
+		readTimeout:      defaultStreamReadTimeout,
+		backoff:          backoff,
+		logger:           slog.Default().With("component", "binance_stream"),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Stream subscribes to the all-market ticker and passes the prices of each
+// update to fn until ctx is done. A dropped or silent connection, including
+// the one Binance closes after 24 hours, is reconnected with backoff and
+// the ticker resubscribed.
+func (s *Stream) Stream(ctx context.Context, fn func(prices []*domain.Price)) error {
+	attempt := 0
+	for {
+		received, err := s.session(ctx, fn)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		// A session that received data was healthy; start the backoff over
+		if received {
+			attempt = 0
+		}
+		attempt++
+		wait := retry.Backoff(s.backoff, attempt)
+
+		s.logger.Warn("market stream disconnected, reconnecting",
+			"error", err, "attempt", attempt, "wait", wait)
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// subscribeRequest subscribes the connection to streams
+type subscribeRequest struct {
+	Method string   `json:"method"`
+	Params []string `json:"params"`
+	ID     int      `json:"id"`
+}
+
+// session connects, subscribes and delivers updates until the connection
+// fails. received reports whether any update arrived.
+func (s *Stream) session(ctx context.Context, fn func(prices []*domain.Price)) (received bool, err error) {
+	handshakeCtx, cancel := context.WithTimeout(ctx, s.handshakeTimeout)
+	conn, err := dialWebSocket(handshakeCtx, s.httpClient, s.url)
+	cancel()
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	// Closing the connection unblocks the read when ctx is done or the
+	// stream goes silent
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+	watchdog := time.AfterFunc(s.readTimeout, func() {
+		s.logger.Warn("market stream silent, closing connection", "timeout", s.readTimeout)
+		conn.Close()
+	})
+	defer watchdog.Stop()
+
+	err = conn.WriteJSON(subscribeRequest{Method: "SUBSCRIBE", Params: []string{tickerArrStream}, ID: 1})
+	if err != nil {
+		return false, err
+	}
+	s.logger.Info("subscribed to market stream", "url", s.url, "stream", tickerArrStream)
+
+	for {
+		msg, err := conn.ReadMessage()
+		if err != nil {
+			return received, err
+		}
+		watchdog.Reset(s.readTimeout)
+
+		prices, err := decodeStreamMessage(msg, time.Now().UTC())
+		if err != nil {
+			return received, err
+		}
+		if len(prices) > 0 {
+			received = true
+			fn(prices)
+		}
+	}
+}
+
+// streamTicker is the subset of a 24hr ticker stream event we use. Keys
+// differing only in case are all declared, since encoding/json would
+// otherwise match them to the wrong field.
+type streamTicker struct {
+	Event     string `json:"e"`
+	EventTime int64  `json:"E"`
+	Symbol    string `json:"s"`
+	LastPrice string `json:"c"`
+	CloseTime int64  `json:"C"`
+}
+
+// streamReply answers a subscription request
+type streamReply struct {
+	ID    int `json:"id"`
+	Error *struct {
+		Code int    `json:"code"`
+		Msg  string `json:"msg"`
+	} `json:"error"`
+}
+
+// decodeStreamMessage returns the prices of a ticker array message. Replies
+// to requests carry no prices; a rejected subscription is an error.
+func decodeStreamMessage(msg []byte, receivedAt time.Time) ([]*domain.Price, error) {
+	msg = bytes.TrimSpace(msg)
+	if len(msg) == 0 || msg[0] != '[' {
+		var reply streamReply
+		if err := json.Unmarshal(msg, &reply); err != nil {
+			return nil, fmt.Errorf("%w: %w", errDecode, err)
+		}
+		if reply.Error != nil {
+			return nil, fmt.Errorf("stream request %d rejected: %s (code %d)", reply.ID, reply.Error.Msg, reply.Error.Code)
+		}
+		return nil, nil
+	}
+
+	var tickers []streamTicker
+	if err := json.Unmarshal(msg, &tickers); err != nil {
+		return nil, fmt.Errorf("%w: %w", errDecode, err)
+	}
+
+	prices := make([]*domain.Price, 0, len(tickers))
+	for _, t := range tickers {
+		price, err := decimal.NewFromString(t.LastPrice)
+		if err != nil {
+			continue
+		}
+		eventTime := time.UnixMilli(t.EventTime).UTC()
+		prices = append(prices, &domain.Price{
+			Symbol:    t.Symbol,
+			Price:     price,
+			FetchedAt: receivedAt,
+			EventTime: &eventTime,
+		})
+	}
+	return prices, nil
+}
+
+// Ensure Stream implements ports.PriceStream
+var _ ports.PriceStream = (*Stream)(nil)
@@ -0,0 +1,179 @@
+package binance_test
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/adapters/binance"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+)
+
+// wsServerConn is the server side of a test websocket connection
+type wsServerConn struct {
+	conn net.Conn
+	rw   *bufio.ReadWriter
+}
+
+// upgrade completes a websocket handshake on a test server
+func upgrade(t *testing.T, w http.ResponseWriter, r *http.Request) *wsServerConn {
+	t.Helper()
+	require.Equal(t, "websocket", r.Header.Get("Upgrade"))
+
+	sum := sha1.Sum([]byte(r.Header.Get("Sec-WebSocket-Key") + "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"))
+
+	conn, rw, err := w.(http.Hijacker).Hijack()
+	require.NoError(t, err)
+
+	rw.WriteString("HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\nConnection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + base64.StdEncoding.EncodeToString(sum[:]) + "\r\n\r\n")
+	require.NoError(t, rw.Flush())
+
+	return &wsServerConn{conn: conn, rw: rw}
+}
+
+// write sends an unmasked frame, as servers do
+func (c *wsServerConn) write(op byte, payload []byte) error {
+	frame := []byte{0x80 | op}
+	switch n := len(payload); {
+	case n < 126:
+		frame = append(frame, byte(n))
+	default:
+		frame = append(frame, 126)
+		frame = binary.BigEndian.AppendUint16(frame, uint16(n))
+	}
+	frame = append(frame, payload...)
+	if _, err := c.rw.Write(frame); err != nil {
+		return err
+	}
+	return c.rw.Flush()
+}
+
+// read returns the opcode and unmasked payload of the next client frame
+func (c *wsServerConn) read() (byte, []byte, error) {
+	var header [2]byte
+	if _, err := io.ReadFull(c.rw, header[:]); err != nil {
+		return 0, nil, err
+	}
+	length := int(header[1] & 0x7F)
+	if length == 126 {
+		var ext [2]byte
+		if _, err := io.ReadFull(c.rw, ext[:]); err != nil {
+			return 0, nil, err
+		}
+		length = int(binary.BigEndian.Uint16(ext[:]))
+	}
+
+	var mask [4]byte
+	if _, err := io.ReadFull(c.rw, mask[:]); err != nil {
+		return 0, nil, err
+	}
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(c.rw, payload); err != nil {
+		return 0, nil, err
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+	return header[0] & 0x0F, payload, nil
+}
+
+func TestStream_ReconnectsAndResubscribes(t *testing.T) {
+	var (
+		mu          sync.Mutex
+		connections int
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		connections++
+		n := connections
+		mu.Unlock()
+
+		conn := upgrade(t, w, r)
+		defer conn.conn.Close()
+
+		// Every connection must subscribe again
+		op, payload, err := conn.read()
+		require.NoError(t, err)
+		assert.Equal(t, byte(0x1), op)
+		var sub struct {
+			Method string   `json:"method"`
+			Params []string `json:"params"`
+		}
+		require.NoError(t, json.Unmarshal(payload, &sub))
+		assert.Equal(t, "SUBSCRIBE", sub.Method)
+		assert.Equal(t, []string{"!ticker@arr"}, sub.Params)
+		require.NoError(t, conn.write(0x1, []byte(`{"result":null,"id":1}`)))
+
+		// Pings are answered with the same payload
+		require.NoError(t, conn.write(0x9, []byte("hb")))
+		op, payload, err = conn.read()
+		require.NoError(t, err)
+		assert.Equal(t, byte(0xA), op)
+		assert.Equal(t, "hb", string(payload))
+
+		price := "43000.10"
+		if n > 1 {
+			price = "43100.20"
+		}
+		msg := `[{"e":"24hrTicker","E":1705312800000,"s":"BTCUSDT","c":"` + price + `","C":1705312799999}]`
+		require.NoError(t, conn.write(0x1, []byte(msg)))
+
+		if n > 1 {
+			// Keep the second connection open until the client leaves
+			_, _, _ = conn.read()
+		}
+		// The first connection drops without a close frame
+	}))
+	defer server.Close()
+
+	stream := binance.NewStream(
+		binance.WithStreamURL("ws"+strings.TrimPrefix(server.URL, "http")),
+		binance.WithStreamReconnect(time.Millisecond, time.Millisecond),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	updates := make(chan []*domain.Price, 2)
+	done := make(chan error, 1)
+	go func() {
+		done <- stream.Stream(ctx, func(prices []*domain.Price) { updates <- prices })
+	}()
+
+	for _, want := range []string{"43000.1", "43100.2"} {
+		select {
+		case prices := <-updates:
+			require.Len(t, prices, 1)
+			assert.Equal(t, "BTCUSDT", prices[0].Symbol)
+			assert.Equal(t, want, prices[0].Price.String())
+			require.NotNil(t, prices[0].EventTime)
+			assert.Equal(t, time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC), *prices[0].EventTime)
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for stream update")
+		}
+	}
+
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, 2, connections)
+}
@@ -0,0 +1,112 @@
+// Package chaos injects failures into the service's I/O for resilience
+// testing: dropped database connections, delayed exchange responses and
+// server errors from the HTTP API. It must never be enabled in production.
+package chaos
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+	"github.com/prxgr4mmer/price-snapshot-service/pkg/retry"
+)
+
+// ErrConnectionDropped is returned by queries whose connection was dropped
+var ErrConnectionDropped = errors.New("chaos: database connection dropped")
+
+// serverErrorStatuses are the statuses injected into HTTP responses
+var serverErrorStatuses = []int{
+	http.StatusInternalServerError,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+}
+
+// Faults configures how often each failure is injected. Rates are
+// probabilities in [0, 1]; zero disables the fault.
+type Faults struct {
+	DBDropRate        float64
+	ExchangeDelay     time.Duration
+	ExchangeDelayRate float64
+	HTTPErrorRate     float64
+}
+
+// Injector decides, per operation, whether to inject a fault
+type Injector struct {
+	faults Faults
+	rand   retry.Source
+	logger *slog.Logger
+}
+
+// NewInjector creates a fault injector drawing from the given random source
+func NewInjector(faults Faults, rand retry.Source, logger *slog.Logger) *Injector {
+	return &Injector{
+		faults: faults,
+		rand:   rand,
+		logger: logger.With("component", "chaos"),
+	}
+}
+
+// roll reports whether a fault with the given rate fires
+func (i *Injector) roll(rate float64) bool {
+	return rate > 0 && i.rand.Float64() < rate
+}
+
+// PrepareConn drops the connection about to serve a query, failing that
+// query. It is meant for pgxpool.Config.PrepareConn.
+func (i *Injector) PrepareConn(ctx context.Context, _ *pgx.Conn) (bool, error) {
+	if !i.roll(i.faults.DBDropRate) {
+		return true, nil
+	}
+	i.logger.Debug("dropping database connection")
+	return false, ErrConnectionDropped
+}
+
+// Transport wraps next so that responses are delayed
+func (i *Injector) Transport(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &delayTransport{injector: i, next: next}
+}
+
+// ServerError returns the status of an error to inject into an HTTP
+// response, or 0 to serve the request normally
+func (i *Injector) ServerError() int {
+	if !i.roll(i.faults.HTTPErrorRate) {
+		return 0
+	}
+	return serverErrorStatuses[int(i.rand.Float64()*float64(len(serverErrorStatuses)))]
+}
+
+// delayTransport delays requests before sending them
+type delayTransport struct {
+	injector *Injector
+	next     http.RoundTripper
+}
+
+func (t *delayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.injector.roll(t.injector.faults.ExchangeDelayRate) {
+		t.injector.logger.Debug("delaying exchange request",
+			"host", req.URL.Host,
+			"path", req.URL.Path,
+			"delay", t.injector.faults.ExchangeDelay,
+		)
+
+		timer := time.NewTimer(t.injector.faults.ExchangeDelay)
+		defer timer.Stop()
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+	return t.next.RoundTrip(req)
+}
+
+// Ensure Injector implements ports.FaultInjector
+var _ ports.FaultInjector = (*Injector)(nil)
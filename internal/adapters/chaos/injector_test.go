@@ -0,0 +1,80 @@
+package chaos_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/adapters/chaos"
+)
+
+// fixedSource always returns the same number
+type fixedSource float64
+
+func (s fixedSource) Float64() float64 { return float64(s) }
+
+var logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+func TestInjector_PrepareConn(t *testing.T) {
+	faults := chaos.Faults{DBDropRate: 0.5}
+
+	ok, err := chaos.NewInjector(faults, fixedSource(0.9), logger).PrepareConn(context.Background(), nil)
+	assert.True(t, ok)
+	assert.NoError(t, err)
+
+	ok, err = chaos.NewInjector(faults, fixedSource(0.1), logger).PrepareConn(context.Background(), nil)
+	assert.False(t, ok)
+	assert.ErrorIs(t, err, chaos.ErrConnectionDropped)
+
+	ok, err = chaos.NewInjector(chaos.Faults{}, fixedSource(0), logger).PrepareConn(context.Background(), nil)
+	assert.True(t, ok)
+	assert.NoError(t, err)
+}
+
+func TestInjector_ServerError(t *testing.T) {
+	faults := chaos.Faults{HTTPErrorRate: 0.5}
+
+	assert.Zero(t, chaos.NewInjector(faults, fixedSource(0.9), logger).ServerError())
+	assert.Equal(t, http.StatusInternalServerError, chaos.NewInjector(faults, fixedSource(0.1), logger).ServerError())
+	assert.Zero(t, chaos.NewInjector(chaos.Faults{}, fixedSource(0), logger).ServerError())
+}
+
+func TestInjector_Transport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	t.Run("delays requests", func(t *testing.T) {
+		injector := chaos.NewInjector(chaos.Faults{ExchangeDelay: 50 * time.Millisecond, ExchangeDelayRate: 1}, fixedSource(0), logger)
+		client := &http.Client{Transport: injector.Transport(nil)}
+
+		start := time.Now()
+		resp, err := client.Get(server.URL)
+		require.NoError(t, err)
+		resp.Body.Close()
+
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+		assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+	})
+
+	t.Run("gives up when the request is cancelled", func(t *testing.T) {
+		injector := chaos.NewInjector(chaos.Faults{ExchangeDelay: time.Minute, ExchangeDelayRate: 1}, fixedSource(0), logger)
+		client := &http.Client{Transport: injector.Transport(nil)}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+		require.NoError(t, err)
+
+		_, err = client.Do(req)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
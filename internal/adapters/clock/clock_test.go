@@ -0,0 +1,58 @@
+package clock_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/adapters/clock"
+)
+
+func TestFake(t *testing.T) {
+	start := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	t.Run("moves only when advanced", func(t *testing.T) {
+		c := clock.NewFake(start)
+		assert.Equal(t, start, c.Now())
+
+		c.Advance(90 * time.Second)
+		assert.Equal(t, start.Add(90*time.Second), c.Now())
+		assert.Equal(t, 90*time.Second, c.Since(start))
+	})
+
+	t.Run("fires tickers that are due", func(t *testing.T) {
+		c := clock.NewFake(start)
+		ticker := c.NewTicker(time.Minute)
+
+		c.Advance(59 * time.Second)
+		assert.Empty(t, ticker.C())
+
+		c.Advance(time.Second)
+		assert.Equal(t, start.Add(time.Minute), <-ticker.C())
+	})
+
+	t.Run("drops ticks for a slow receiver", func(t *testing.T) {
+		c := clock.NewFake(start)
+		ticker := c.NewTicker(time.Minute)
+
+		c.Advance(3 * time.Minute)
+		assert.Equal(t, start.Add(time.Minute), <-ticker.C())
+		assert.Empty(t, ticker.C())
+
+		c.Advance(time.Minute)
+		assert.Equal(t, start.Add(4*time.Minute), <-ticker.C())
+	})
+
+	t.Run("stops firing once stopped", func(t *testing.T) {
+		c := clock.NewFake(start)
+		ticker := c.NewTicker(time.Minute)
+		assert.Equal(t, 1, c.Tickers())
+
+		ticker.Stop()
+		c.Advance(time.Hour)
+
+		assert.Empty(t, ticker.C())
+		assert.Zero(t, c.Tickers())
+	})
+}
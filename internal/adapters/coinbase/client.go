@@ -0,0 +1,538 @@
+package coinbase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+	"github.com/prxgr4mmer/price-snapshot-service/pkg/retry"
+)
+
+const (
+	// DefaultBaseURL is the public Coinbase Exchange market data API
+	DefaultBaseURL = "https://api.exchange.coinbase.com"
+
+	productsPath = "/products"
+	timePath     = "/time"
+
+	// maxCandles is the most candles Coinbase returns per request
+	maxCandles = 300
+)
+
+// quoteAssets are the quote currencies symbols are split by into Coinbase
+// product IDs, longest first so BTCUSDT is BTC-USDT rather than BTC-USD
+var quoteAssets = []string{"USDT", "USDC", "EUR", "GBP", "USD", "BTC", "ETH", "DAI"}
+
+// granularities maps supported candle intervals to Coinbase granularities.
+// Coinbase has no 4h candles.
+var granularities = map[string]time.Duration{
+	"1m":  time.Minute,
+	"5m":  5 * time.Minute,
+	"15m": 15 * time.Minute,
+	"1h":  time.Hour,
+	"1d":  24 * time.Hour,
+}
+
+// Client implements the ExchangeClient interface for the Coinbase Exchange
+// public market data API. Symbols are named as on Binance, base and quote
+// asset without a separator, and mapped to product IDs such as BTC-USD.
+type Client struct {
+	httpClient *http.Client
+	baseURL    string
+	retryConf  retry.Config
+	errors     ports.ExchangeErrorRecorder
+	retries    ports.ExchangeRetryRecorder
+	logger     *slog.Logger
+}
+
+// ClientOption configures the client
+type ClientOption func(*Client)
+
+// WithBaseURL sets the API base URL
+func WithBaseURL(url string) ClientOption {
+	return func(c *Client) {
+		if url != "" {
+			c.baseURL = strings.TrimRight(url, "/")
+		}
+	}
+}
+
+// WithTimeout sets the HTTP client timeout
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(c *Client) {
+		c.httpClient.Timeout = timeout
+	}
+}
+
+// WithTransport sets the HTTP transport used for requests. A nil transport
+// keeps the default.
+func WithTransport(transport http.RoundTripper) ClientOption {
+	return func(c *Client) {
+		if transport != nil {
+			c.httpClient.Transport = transport
+		}
+	}
+}
+
+// WithRetry configures retry behavior
+func WithRetry(maxRetries int, backoff time.Duration) ClientOption {
+	return func(c *Client) {
+		c.retryConf.MaxRetries = maxRetries
+		c.retryConf.InitialBackoff = backoff
+	}
+}
+
+// WithRetryJitter sets how retry backoffs are randomized
+func WithRetryJitter(mode retry.JitterMode) ClientOption {
+	return func(c *Client) {
+		c.retryConf.JitterMode = mode
+	}
+}
+
+// WithErrorRecorder reports every failed request attempt, classified by kind
+func WithErrorRecorder(recorder ports.ExchangeErrorRecorder) ClientOption {
+	return func(c *Client) {
+		c.errors = recorder
+	}
+}
+
+// WithRetryRecorder reports every retry, classified by the kind of failure
+// retried, and the attempts made by every call
+func WithRetryRecorder(recorder ports.ExchangeRetryRecorder) ClientOption {
+	return func(c *Client) {
+		c.retries = recorder
+	}
+}
+
+// WithLogger sets the logger
+func WithLogger(logger *slog.Logger) ClientOption {
+	return func(c *Client) {
+		c.logger = logger.With("component", "coinbase_client")
+	}
+}
+
+// NewClient creates a new Coinbase client
+func NewClient(opts ...ClientOption) *Client {
+	c := &Client{
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+		baseURL:   DefaultBaseURL,
+		retryConf: retry.DefaultConfig(),
+		logger:    slog.Default().With("component", "coinbase_client"),
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// ProductID returns the Coinbase product ID of a symbol, e.g. BTC-USD for
+// BTCUSD. It reports false when the symbol does not end in a known quote
+// currency.
+func ProductID(symbol string) (string, bool) {
+	for _, quote := range quoteAssets {
+		if base, ok := strings.CutSuffix(symbol, quote); ok && base != "" {
+			return base + "-" + quote, true
+		}
+	}
+	return "", false
+}
+
+// retryConfig returns the retry behavior for a call of an operation made
+// with ctx, which makes a single attempt when the caller asked for one
+func (c *Client) retryConfig(ctx context.Context, operation string) retry.Config {
+	conf := c.retryConf
+	if domain.SingleExchangeAttempt(ctx) {
+		conf.MaxRetries = 0
+	}
+
+	if c.retries != nil {
+		conf.OnRetry = func(_ int, err error) {
+			if kind := classifyError(err); kind != "" {
+				c.retries.RecordExchangeRetry(operation, kind)
+			}
+		}
+		conf.OnDone = func(attempts int, err error) {
+			if !errors.Is(err, context.Canceled) {
+				c.retries.RecordExchangeAttempts(operation, attempts)
+			}
+		}
+	}
+	return conf
+}
+
+// observed wraps a request attempt so that its failure, if any, is
+// classified and reported to the error recorder under the operation name
+func (c *Client) observed(operation string, fn func(ctx context.Context) error) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		err := fn(ctx)
+		if err != nil && c.errors != nil {
+			if kind := classifyError(err); kind != "" {
+				c.errors.RecordExchangeError(operation, kind)
+			}
+		}
+		return err
+	}
+}
+
+// get sends a GET request and decodes a successful JSON response into v.
+// Rate limits and server errors are returned as retryable; a 404 is an
+// unknown product.
+func (c *Client) get(ctx context.Context, path string, query url.Values, v interface{}) error {
+	u := c.baseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		c.logger.Debug("request failed, will retry", "path", path, "error", err)
+		return retry.NewRetryableError(err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		c.logger.Warn("rate limited by exchange", "path", path)
+		return retry.NewRetryableError(domain.ErrRateLimited)
+	case resp.StatusCode >= 500:
+		c.logger.Warn("exchange server error", "path", path, "status", resp.StatusCode)
+		return retry.NewRetryableError(&statusError{status: resp.StatusCode})
+	case resp.StatusCode == http.StatusNotFound:
+		return domain.ErrInvalidSymbol
+	case resp.StatusCode != http.StatusOK:
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		c.logger.Error("unexpected response", "path", path, "status", resp.StatusCode, "body", string(body))
+		return domain.ErrInvalidResponse
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("%w: %w", errDecode, err)
+	}
+	return nil
+}
+
+// tickerResponse is the subset of a Coinbase product ticker we use
+type tickerResponse struct {
+	Price string `json:"price"`
+}
+
+// GetPrice fetches the current price for a single symbol
+func (c *Client) GetPrice(ctx context.Context, symbol string) (*domain.Price, error) {
+	product, ok := ProductID(symbol)
+	if !ok {
+		return nil, domain.ErrInvalidSymbol
+	}
+
+	var result *domain.Price
+
+	err := retry.Do(ctx, c.retryConfig(ctx, "price"), c.observed("price", func(ctx context.Context) error {
+		var ticker tickerResponse
+		if err := c.get(ctx, productsPath+"/"+product+"/ticker", nil, &ticker); err != nil {
+			return err
+		}
+
+		price, err := decimal.NewFromString(ticker.Price)
+		if err != nil {
+			return fmt.Errorf("%w: invalid price: %w", errDecode, err)
+		}
+
+		result = &domain.Price{
+			Symbol:    symbol,
+			Price:     price,
+			FetchedAt: time.Now().UTC(),
+		}
+		return nil
+	}))
+
+	return result, err
+}
+
+// GetPrices fetches current prices for multiple symbols. Coinbase has no
+// batch ticker, so each symbol is requested in turn; unknown symbols are
+// left out of the result.
+func (c *Client) GetPrices(ctx context.Context, symbols []string) ([]*domain.Price, error) {
+	if len(symbols) == 0 {
+		return nil, nil
+	}
+
+	result := make([]*domain.Price, 0, len(symbols))
+	for _, symbol := range symbols {
+		price, err := c.GetPrice(ctx, symbol)
+		if errors.Is(err, domain.ErrInvalidSymbol) {
+			c.logger.Warn("unknown product", "symbol", symbol)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, price)
+	}
+
+	return result, nil
+}
+
+// ValidateSymbol checks if a symbol exists on Coinbase
+func (c *Client) ValidateSymbol(ctx context.Context, symbol string) (bool, error) {
+	_, err := c.GetPrice(ctx, symbol)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidSymbol) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// GetCandles fetches historical candles for a symbol between from and to.
+// Coinbase returns at most 300 candles per request, newest first; they are
+// returned oldest first.
+func (c *Client) GetCandles(ctx context.Context, symbol, interval string, from, to time.Time, limit int) ([]*domain.Candle, error) {
+	product, ok := ProductID(symbol)
+	if !ok {
+		return nil, domain.ErrInvalidSymbol
+	}
+	step, ok := granularities[interval]
+	if !ok {
+		return nil, fmt.Errorf("unsupported interval %q", interval)
+	}
+	if limit <= 0 || limit > maxCandles {
+		limit = maxCandles
+	}
+	if end := from.Add(time.Duration(limit-1) * step); end.Before(to) {
+		to = end
+	}
+
+	var result []*domain.Candle
+
+	err := retry.Do(ctx, c.retryConfig(ctx, "klines"), c.observed("klines", func(ctx context.Context) error {
+		q := url.Values{}
+		q.Set("granularity", strconv.Itoa(int(step.Seconds())))
+		q.Set("start", from.UTC().Format(time.RFC3339))
+		q.Set("end", to.UTC().Format(time.RFC3339))
+
+		// Each candle is [time, low, high, open, close, volume]
+		var rows [][]json.Number
+		if err := c.get(ctx, productsPath+"/"+product+"/candles", q, &rows); err != nil {
+			return err
+		}
+
+		result = make([]*domain.Candle, 0, len(rows))
+		for i := len(rows) - 1; i >= 0; i-- {
+			candle, err := parseCandle(symbol, step, rows[i])
+			if err != nil {
+				c.logger.Warn("invalid candle", "symbol", symbol, "error", err)
+				continue
+			}
+			result = append(result, candle)
+		}
+		return nil
+	}))
+
+	return result, err
+}
+
+func parseCandle(symbol string, step time.Duration, row []json.Number) (*domain.Candle, error) {
+	if len(row) < 5 {
+		return nil, domain.ErrInvalidResponse
+	}
+
+	openTime, err := row[0].Int64()
+	if err != nil {
+		return nil, err
+	}
+
+	prices := make([]decimal.Decimal, 4)
+	for i := range prices {
+		price, err := decimal.NewFromString(row[i+1].String())
+		if err != nil {
+			return nil, err
+		}
+		prices[i] = price
+	}
+
+	open := time.Unix(openTime, 0).UTC()
+	return &domain.Candle{
+		Symbol:    symbol,
+		OpenTime:  open,
+		CloseTime: open.Add(step - time.Millisecond),
+		Low:       prices[0],
+		High:      prices[1],
+		Open:      prices[2],
+		Close:     prices[3],
+	}, nil
+}
+
+// statsResponse is the Coinbase 24h product stats response
+type statsResponse struct {
+	Open   string `json:"open"`
+	High   string `json:"high"`
+	Low    string `json:"low"`
+	Last   string `json:"last"`
+	Volume string `json:"volume"`
+}
+
+// GetTickerStats fetches rolling 24h statistics for multiple symbols, one
+// request per symbol. Unknown symbols are left out.
+func (c *Client) GetTickerStats(ctx context.Context, symbols []string) ([]*domain.TickerStats, error) {
+	result := make([]*domain.TickerStats, 0, len(symbols))
+
+	for _, symbol := range symbols {
+		product, ok := ProductID(symbol)
+		if !ok {
+			continue
+		}
+
+		var stats statsResponse
+		err := retry.Do(ctx, c.retryConfig(ctx, "ticker24h"), c.observed("ticker24h", func(ctx context.Context) error {
+			return c.get(ctx, productsPath+"/"+product+"/stats", nil, &stats)
+		}))
+		if errors.Is(err, domain.ErrInvalidSymbol) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		ticker, err := parseStats(symbol, stats)
+		if err != nil {
+			c.logger.Warn("invalid 24h stats", "symbol", symbol, "error", err)
+			continue
+		}
+		result = append(result, ticker)
+	}
+
+	return result, nil
+}
+
+func parseStats(symbol string, s statsResponse) (*domain.TickerStats, error) {
+	values := make([]decimal.Decimal, 5)
+	for i, str := range []string{s.Open, s.High, s.Low, s.Last, s.Volume} {
+		v, err := decimal.NewFromString(str)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = v
+	}
+
+	open := values[0]
+	change := decimal.Zero
+	if open.IsPositive() {
+		change = values[3].Sub(open).Div(open).Mul(decimal.NewFromInt(100)).Round(4)
+	}
+
+	return &domain.TickerStats{
+		Symbol:             symbol,
+		High:               values[1],
+		Low:                values[2],
+		Volume:             values[4],
+		PriceChangePercent: change,
+	}, nil
+}
+
+// productResponse is the subset of a listed Coinbase product we use
+type productResponse struct {
+	ID              string `json:"id"`
+	BaseCurrency    string `json:"base_currency"`
+	QuoteCurrency   string `json:"quote_currency"`
+	QuoteIncrement  string `json:"quote_increment"`
+	Status          string `json:"status"`
+	TradingDisabled bool   `json:"trading_disabled"`
+}
+
+// symbol returns the product's symbol name, e.g. BTCUSD for BTC-USD
+func (p productResponse) symbol() string {
+	return strings.ToUpper(p.BaseCurrency + p.QuoteCurrency)
+}
+
+// products lists every product Coinbase offers
+func (c *Client) products(ctx context.Context) ([]productResponse, error) {
+	var products []productResponse
+	err := retry.Do(ctx, c.retryConfig(ctx, "exchange_info"), c.observed("exchange_info", func(ctx context.Context) error {
+		products = nil
+		return c.get(ctx, productsPath, nil, &products)
+	}))
+	return products, err
+}
+
+// ListTradingSymbols returns the symbols of all online products
+func (c *Client) ListTradingSymbols(ctx context.Context) ([]string, error) {
+	products, err := c.products(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]string, 0, len(products))
+	for _, p := range products {
+		if p.Status == "online" && !p.TradingDisabled {
+			result = append(result, p.symbol())
+		}
+	}
+	return result, nil
+}
+
+// GetSymbolPrecisions returns the quote increment of the given symbols, or
+// of every listed product when none are given
+func (c *Client) GetSymbolPrecisions(ctx context.Context, symbols []string) ([]*domain.SymbolPrecision, error) {
+	products, err := c.products(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	wanted := make(map[string]bool, len(symbols))
+	for _, s := range symbols {
+		wanted[s] = true
+	}
+
+	precisions := make([]*domain.SymbolPrecision, 0, len(products))
+	for _, p := range products {
+		symbol := p.symbol()
+		if len(wanted) > 0 && !wanted[symbol] {
+			continue
+		}
+		tickSize, err := decimal.NewFromString(p.QuoteIncrement)
+		if err != nil {
+			c.logger.Warn("invalid quote increment", "product", p.ID, "quote_increment", p.QuoteIncrement)
+			continue
+		}
+		if precision, err := domain.NewSymbolPrecision(symbol, tickSize); err == nil {
+			precisions = append(precisions, precision)
+		}
+	}
+	return precisions, nil
+}
+
+// Ping checks if the Coinbase API is reachable
+func (c *Client) Ping(ctx context.Context) error {
+	return retry.Do(ctx, c.retryConfig(ctx, "ping"), c.observed("ping", func(ctx context.Context) error {
+		var serverTime json.RawMessage
+		return c.get(ctx, timePath, nil, &serverTime)
+	}))
+}
+
+// Ensure Client implements ExchangeClient
+var _ ports.ExchangeClient = (*Client)(nil)
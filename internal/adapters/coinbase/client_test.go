@@ -0,0 +1,194 @@
+package coinbase_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/adapters/coinbase"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports/exchangetest"
+)
+
+func TestClient_Conformance(t *testing.T) {
+	exchangetest.Run(t, func(t *testing.T, s *exchangetest.Scenario) ports.ExchangeClient {
+		server := httptest.NewServer(fakeCoinbase(s))
+		t.Cleanup(server.Close)
+
+		return coinbase.NewClient(
+			coinbase.WithBaseURL(server.URL),
+			coinbase.WithRetry(exchangetest.MaxRetries, time.Millisecond),
+		)
+	})
+}
+
+// fakeCoinbase serves the product ticker and time endpoints of the Coinbase
+// Exchange API as the scenario directs
+func fakeCoinbase(s *exchangetest.Scenario) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch s.Next() {
+		case exchangetest.FailRateLimited:
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		case exchangetest.FailServerError:
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		case exchangetest.FailMalformed:
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"price":`))
+			return
+		case exchangetest.FailHang:
+			s.Hang(r.Context())
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/time" {
+			w.Write([]byte(`{"iso":"2024-01-15T10:00:00Z","epoch":1705312800}`))
+			return
+		}
+
+		product, ok := strings.CutSuffix(strings.TrimPrefix(r.URL.Path, "/products/"), "/ticker")
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		price, ok := s.Price(strings.ReplaceAll(product, "-", ""))
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message":"NotFound"}`))
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"price": price, "time": "2024-01-15T10:00:00Z"})
+	})
+}
+
+func TestProductID(t *testing.T) {
+	for symbol, want := range map[string]string{
+		"BTCUSD":  "BTC-USD",
+		"BTCUSDT": "BTC-USDT",
+		"ETHBTC":  "ETH-BTC",
+		"SOLEUR":  "SOL-EUR",
+	} {
+		got, ok := coinbase.ProductID(symbol)
+		assert.True(t, ok, symbol)
+		assert.Equal(t, want, got)
+	}
+
+	_, ok := coinbase.ProductID("USD")
+	assert.False(t, ok, "a quote currency alone is not a product")
+	_, ok = coinbase.ProductID("BTCJPY")
+	assert.False(t, ok)
+}
+
+func TestClient_GetPrices_SkipsUnknownProducts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/products/BTC-USD/ticker" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"price":"43123.45"}`))
+	}))
+	defer server.Close()
+
+	client := coinbase.NewClient(coinbase.WithBaseURL(server.URL))
+	prices, err := client.GetPrices(context.Background(), []string{"BTCUSD", "NOPEUSD", "BTCJPY"})
+	require.NoError(t, err)
+	require.Len(t, prices, 1)
+	assert.Equal(t, "BTCUSD", prices[0].Symbol)
+	assert.Equal(t, "43123.45", prices[0].Price.String())
+}
+
+func TestClient_GetCandles(t *testing.T) {
+	var query map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/products/BTC-USD/candles", r.URL.Path)
+		query = map[string]string{
+			"granularity": r.URL.Query().Get("granularity"),
+			"start":       r.URL.Query().Get("start"),
+			"end":         r.URL.Query().Get("end"),
+		}
+		// Newest first: [time, low, high, open, close, volume]
+		w.Write([]byte(`[
+			[1705312860, 42990.5, 43200, 43100, 43150.25, 12.5],
+			[1705312800, 42900, 43110, 43000, 43100, 8.25]
+		]`))
+	}))
+	defer server.Close()
+
+	client := coinbase.NewClient(coinbase.WithBaseURL(server.URL))
+	from := time.Unix(1705312800, 0).UTC()
+
+	candles, err := client.GetCandles(context.Background(), "BTCUSD", "1m", from, from.Add(24*time.Hour), 0)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{
+		"granularity": "60",
+		"start":       "2024-01-15T10:00:00Z",
+		"end":         "2024-01-15T14:59:00Z",
+	}, query, "a request spans at most 300 candles")
+
+	require.Len(t, candles, 2)
+	assert.Equal(t, from, candles[0].OpenTime)
+	assert.Equal(t, "43000", candles[0].Open.String())
+	assert.Equal(t, "43100", candles[0].Close.String())
+	assert.Equal(t, from.Add(time.Minute), candles[1].OpenTime)
+	assert.Equal(t, "43150.25", candles[1].Close.String())
+	assert.Equal(t, "42990.5", candles[1].Low.String())
+
+	_, err = client.GetCandles(context.Background(), "BTCUSD", "4h", from, from.Add(time.Hour), 0)
+	assert.ErrorContains(t, err, "unsupported interval")
+}
+
+func TestClient_GetTickerStats(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/products/ETH-USD/stats" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"open":"2000","high":"2200","low":"1950","last":"2100","volume":"1234.5"}`))
+	}))
+	defer server.Close()
+
+	client := coinbase.NewClient(coinbase.WithBaseURL(server.URL))
+	stats, err := client.GetTickerStats(context.Background(), []string{"ETHUSD", "NOPEUSD"})
+	require.NoError(t, err)
+
+	require.Len(t, stats, 1)
+	assert.Equal(t, "ETHUSD", stats[0].Symbol)
+	assert.Equal(t, "2200", stats[0].High.String())
+	assert.Equal(t, "1950", stats[0].Low.String())
+	assert.Equal(t, "1234.5", stats[0].Volume.String())
+	assert.Equal(t, "5", stats[0].PriceChangePercent.String())
+}
+
+func TestClient_ListTradingSymbols(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/products", r.URL.Path)
+		w.Write([]byte(`[
+			{"id":"BTC-USD","base_currency":"BTC","quote_currency":"USD","quote_increment":"0.01","status":"online"},
+			{"id":"ETH-EUR","base_currency":"ETH","quote_currency":"EUR","quote_increment":"0.01","status":"delisted"},
+			{"id":"SOL-USD","base_currency":"SOL","quote_currency":"USD","quote_increment":"0.001","status":"online","trading_disabled":true}
+		]`))
+	}))
+	defer server.Close()
+
+	client := coinbase.NewClient(coinbase.WithBaseURL(server.URL))
+
+	symbols, err := client.ListTradingSymbols(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"BTCUSD"}, symbols)
+
+	precisions, err := client.GetSymbolPrecisions(context.Background(), []string{"SOLUSD"})
+	require.NoError(t, err)
+	require.Len(t, precisions, 1)
+	assert.Equal(t, "SOLUSD", precisions[0].Symbol)
+	assert.Equal(t, "0.001", precisions[0].TickSize.String())
+}
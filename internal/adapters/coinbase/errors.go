@@ -0,0 +1,57 @@
+package coinbase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+)
+
+// errDecode marks a response body that could not be decoded
+var errDecode = errors.New("failed to decode response")
+
+// statusError is a server error response. It unwraps to
+// domain.ErrExchangeUnavailable so callers see a single error.
+type statusError struct {
+	status int
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("unexpected status %d", e.status)
+}
+
+func (e *statusError) Unwrap() error {
+	return domain.ErrExchangeUnavailable
+}
+
+// classifyError maps a failed request attempt to its error kind. Requests
+// cancelled by the caller are not failures and return an empty kind.
+func classifyError(err error) domain.ExchangeErrorKind {
+	var status *statusError
+	var netErr net.Error
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		return ""
+	case errors.Is(err, context.DeadlineExceeded):
+		return domain.ExchangeErrorTimeout
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return domain.ExchangeErrorTimeout
+	case errors.As(err, &status):
+		return domain.ExchangeErrorServer
+	case errors.Is(err, domain.ErrRateLimited):
+		return domain.ExchangeErrorRateLimited
+	case errors.Is(err, domain.ErrInvalidSymbol):
+		return domain.ExchangeErrorInvalidSymbol
+	case errors.Is(err, errDecode):
+		return domain.ExchangeErrorDecode
+	case errors.Is(err, domain.ErrInvalidResponse):
+		return domain.ExchangeErrorBadStatus
+	case errors.As(err, &netErr):
+		return domain.ExchangeErrorNetwork
+	default:
+		return domain.ExchangeErrorUnknown
+	}
+}
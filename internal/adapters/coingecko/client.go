@@ -0,0 +1,194 @@
+package coingecko
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+	"github.com/prxgr4mmer/price-snapshot-service/pkg/retry"
+)
+
+const (
+	// DefaultBaseURL is the public CoinGecko API
+	DefaultBaseURL = "https://api.coingecko.com/api/v3"
+
+	marketsPath = "/coins/markets"
+
+	// maxAssetsPerRequest keeps the symbols query within URL length limits
+	maxAssetsPerRequest = 100
+)
+
+// Client implements the ports.CoinMetadataProvider interface for CoinGecko
+type Client struct {
+	httpClient *http.Client
+	baseURL    string
+	apiKey     string
+	retryConf  retry.Config
+	logger     *slog.Logger
+}
+
+// ClientOption configures the client
+type ClientOption func(*Client)
+
+// WithBaseURL sets the API base URL, e.g. the Pro API
+func WithBaseURL(url string) ClientOption {
+	return func(c *Client) {
+		if url != "" {
+			c.baseURL = strings.TrimRight(url, "/")
+		}
+	}
+}
+
+// WithAPIKey sends an API key with every request. The Pro API host takes
+// a pro key, any other host a demo key.
+func WithAPIKey(key string) ClientOption {
+	return func(c *Client) {
+		c.apiKey = key
+	}
+}
+
+// WithTimeout sets the HTTP client timeout
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(c *Client) {
+		c.httpClient.Timeout = timeout
+	}
+}
+
+// WithLogger sets the logger
+func WithLogger(logger *slog.Logger) ClientOption {
+	return func(c *Client) {
+		c.logger = logger.With("component", "coingecko_client")
+	}
+}
+
+// NewClient creates a new CoinGecko client
+func NewClient(opts ...ClientOption) *Client {
+	c := &Client{
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+		baseURL:   DefaultBaseURL,
+		retryConf: retry.DefaultConfig(),
+		logger:    slog.Default().With("component", "coingecko_client"),
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// marketResponse is a coin in the CoinGecko markets response
+type marketResponse struct {
+	Symbol    string       `json:"symbol"`
+	Name      string       `json:"name"`
+	Image     string       `json:"image"`
+	MarketCap *json.Number `json:"market_cap"`
+}
+
+// GetCoinMetadata returns metadata keyed by the given base assets. When
+// several coins share a ticker, CoinGecko returns the one with the largest
+// market cap.
+func (c *Client) GetCoinMetadata(ctx context.Context, assets []string) (map[string]*domain.CoinMetadata, error) {
+	result := make(map[string]*domain.CoinMetadata, len(assets))
+
+	for start := 0; start < len(assets); start += maxAssetsPerRequest {
+		chunk := assets[start:min(start+maxAssetsPerRequest, len(assets))]
+
+		markets, err := retry.DoWithResult(ctx, c.retryConf, func(ctx context.Context) ([]marketResponse, error) {
+			return c.markets(ctx, chunk)
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		fetchedAt := time.Now().UTC()
+		for _, m := range markets {
+			asset := strings.ToUpper(m.Symbol)
+			if _, seen := result[asset]; seen {
+				continue
+			}
+
+			metadata := &domain.CoinMetadata{
+				Name:      m.Name,
+				LogoURL:   m.Image,
+				UpdatedAt: fetchedAt,
+			}
+			if m.MarketCap != nil {
+				if marketCap, err := decimal.NewFromString(m.MarketCap.String()); err == nil {
+					metadata.MarketCap = marketCap
+				}
+			}
+			result[asset] = metadata
+		}
+	}
+
+	return result, nil
+}
+
+// markets fetches one page of market data for the given assets
+func (c *Client) markets(ctx context.Context, assets []string) ([]marketResponse, error) {
+	q := url.Values{}
+	q.Set("vs_currency", "usd")
+	q.Set("symbols", strings.ToLower(strings.Join(assets, ",")))
+	q.Set("per_page", "250")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+marketsPath+"?"+q.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.apiKey != "" {
+		req.Header.Set(c.apiKeyHeader(), c.apiKey)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, retry.NewRetryableError(err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		c.logger.Warn("rate limited by coingecko")
+		return nil, retry.NewRetryableError(domain.ErrRateLimited)
+	case resp.StatusCode >= 500:
+		return nil, retry.NewRetryableError(fmt.Errorf("coingecko returned status %d", resp.StatusCode))
+	case resp.StatusCode != http.StatusOK:
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		c.logger.Error("unexpected response", "status", resp.StatusCode, "body", string(body))
+		return nil, domain.ErrInvalidResponse
+	}
+
+	var markets []marketResponse
+	if err := json.NewDecoder(resp.Body).Decode(&markets); err != nil {
+		return nil, fmt.Errorf("%w: %w", domain.ErrInvalidResponse, err)
+	}
+
+	return markets, nil
+}
+
+// apiKeyHeader returns the header carrying the API key for the configured host
+func (c *Client) apiKeyHeader() string {
+	if strings.Contains(c.baseURL, "pro-api.coingecko.com") {
+		return "x-cg-pro-api-key"
+	}
+	return "x-cg-demo-api-key"
+}
+
+// Ensure Client implements ports.CoinMetadataProvider
+var _ ports.CoinMetadataProvider = (*Client)(nil)
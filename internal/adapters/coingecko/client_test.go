@@ -0,0 +1,60 @@
+package coingecko_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/adapters/coingecko"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+)
+
+func TestClient_GetCoinMetadata(t *testing.T) {
+	t.Run("maps coins to base assets", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/coins/markets", r.URL.Path)
+			assert.Equal(t, "btc,eth", r.URL.Query().Get("symbols"))
+			assert.Equal(t, "usd", r.URL.Query().Get("vs_currency"))
+			assert.Equal(t, "demo-key", r.Header.Get("x-cg-demo-api-key"))
+
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`[
+				{"id": "bitcoin", "symbol": "btc", "name": "Bitcoin", "image": "https://img/btc.png", "market_cap": 845123456789},
+				{"id": "ethereum", "symbol": "eth", "name": "Ethereum", "image": "https://img/eth.png", "market_cap": null}
+			]`))
+		}))
+		defer server.Close()
+
+		client := coingecko.NewClient(
+			coingecko.WithBaseURL(server.URL),
+			coingecko.WithAPIKey("demo-key"),
+		)
+
+		metadata, err := client.GetCoinMetadata(context.Background(), []string{"BTC", "ETH"})
+		require.NoError(t, err)
+		require.Len(t, metadata, 2)
+
+		assert.Equal(t, "Bitcoin", metadata["BTC"].Name)
+		assert.Equal(t, "https://img/btc.png", metadata["BTC"].LogoURL)
+		assert.True(t, metadata["BTC"].MarketCap.Equal(decimal.NewFromInt(845123456789)))
+		assert.False(t, metadata["BTC"].UpdatedAt.IsZero())
+		assert.True(t, metadata["ETH"].MarketCap.IsZero())
+	})
+
+	t.Run("rejects unexpected responses", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+		}))
+		defer server.Close()
+
+		client := coingecko.NewClient(coingecko.WithBaseURL(server.URL))
+
+		_, err := client.GetCoinMetadata(context.Background(), []string{"BTC"})
+		assert.ErrorIs(t, err, domain.ErrInvalidResponse)
+	})
+}
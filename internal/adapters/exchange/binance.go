@@ -0,0 +1,73 @@
+package exchange
+
+import (
+	"github.com/prxgr4mmer/price-snapshot-service/internal/adapters/binance"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/config"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+)
+
+const (
+	// ProviderBinance is the built-in Binance spot API provider
+	ProviderBinance = "binance"
+
+	// ProviderBinanceUS is Binance.US, a separate exchange with its own
+	// markets served by the same API
+	ProviderBinanceUS = "binanceus"
+)
+
+func init() {
+	Register(ProviderBinance, newBinance)
+	RegisterStream(ProviderBinance, newBinanceStream)
+	Register(ProviderBinanceUS, newBinanceUS)
+	RegisterStream(ProviderBinanceUS, newBinanceUSStream)
+}
+
+// newBinance builds the Binance client from the exchange configuration
+func newBinance(cfg config.ExchangeConfig, deps Deps) (ports.ExchangeClient, error) {
+	retryJitter, err := cfg.RetryJitterMode()
+	if err != nil {
+		return nil, err
+	}
+
+	return binance.NewClient(
+		binance.WithRegion(binance.Region(cfg.Region)),
+		binance.WithBaseURL(cfg.BaseURL),
+		binance.WithFallbackURLs(cfg.FallbackURLs...),
+		binance.WithHostCooldown(cfg.HostCooldown),
+		binance.WithTimeout(cfg.Timeout),
+		binance.WithRetry(cfg.MaxRetries, cfg.RetryBackoff),
+		binance.WithRetryJitter(retryJitter),
+		binance.WithTransport(deps.Transport),
+		binance.WithRecording(cfg.RecordDir),
+		binance.WithErrorRecorder(deps.Errors),
+		binance.WithRetryRecorder(deps.Retries),
+		binance.WithBudget(deps.Budget),
+		binance.WithCredentials(cfg.APIKey, cfg.APISecret),
+		binance.WithRecvWindow(cfg.RecvWindow),
+		binance.WithLogger(deps.Logger),
+	), nil
+}
+
+// newBinanceUS builds the Binance.US client from the exchange configuration
+func newBinanceUS(cfg config.ExchangeConfig, deps Deps) (ports.ExchangeClient, error) {
+	cfg.Region = string(binance.RegionUS)
+	return newBinance(cfg, deps)
+}
+
+// newBinanceStream builds the Binance market data stream from the exchange
+// configuration
+func newBinanceStream(cfg config.ExchangeConfig, deps Deps) (ports.PriceStream, error) {
+	return binance.NewStream(
+		binance.WithStreamRegion(binance.Region(cfg.Region)),
+		binance.WithStreamURL(cfg.StreamURL),
+		binance.WithStreamTransport(deps.Transport),
+		binance.WithStreamLogger(deps.Logger),
+	), nil
+}
+
+// newBinanceUSStream builds the Binance.US market data stream from the
+// exchange configuration
+func newBinanceUSStream(cfg config.ExchangeConfig, deps Deps) (ports.PriceStream, error) {
+	cfg.Region = string(binance.RegionUS)
+	return newBinanceStream(cfg, deps)
+}
@@ -0,0 +1,49 @@
+package exchange
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+)
+
+// Clients holds the clients of the configured exchanges by provider name
+type Clients struct {
+	defaultName string
+	clients     map[string]ports.ExchangeClient
+}
+
+// Client returns the named exchange's client, or the default exchange's
+// when name is empty
+func (c *Clients) Client(name string) (ports.ExchangeClient, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		name = c.defaultName
+	}
+
+	client, ok := c.clients[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", domain.ErrUnknownExchange, name)
+	}
+	return client, nil
+}
+
+// Default returns the name of the default exchange
+func (c *Clients) Default() string {
+	return c.defaultName
+}
+
+// Names returns the configured exchange names in sorted order
+func (c *Clients) Names() []string {
+	names := make([]string, 0, len(c.clients))
+	for name := range c.clients {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Ensure Clients implements ports.ExchangeRegistry
+var _ ports.ExchangeRegistry = (*Clients)(nil)
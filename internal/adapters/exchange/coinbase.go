@@ -0,0 +1,34 @@
+package exchange
+
+import (
+	"github.com/prxgr4mmer/price-snapshot-service/internal/adapters/coinbase"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/config"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+)
+
+// ProviderCoinbase is the Coinbase Exchange public market data API
+const ProviderCoinbase = "coinbase"
+
+func init() {
+	Register(ProviderCoinbase, newCoinbase)
+}
+
+// newCoinbase builds the Coinbase client from the exchange configuration.
+// The request budget counts Binance weights and does not apply.
+func newCoinbase(cfg config.ExchangeConfig, deps Deps) (ports.ExchangeClient, error) {
+	retryJitter, err := cfg.RetryJitterMode()
+	if err != nil {
+		return nil, err
+	}
+
+	return coinbase.NewClient(
+		coinbase.WithBaseURL(cfg.BaseURL),
+		coinbase.WithTimeout(cfg.Timeout),
+		coinbase.WithRetry(cfg.MaxRetries, cfg.RetryBackoff),
+		coinbase.WithRetryJitter(retryJitter),
+		coinbase.WithTransport(deps.Transport),
+		coinbase.WithErrorRecorder(deps.Errors),
+		coinbase.WithRetryRecorder(deps.Retries),
+		coinbase.WithLogger(deps.Logger),
+	), nil
+}
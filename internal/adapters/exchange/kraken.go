@@ -0,0 +1,34 @@
+package exchange
+
+import (
+	"github.com/prxgr4mmer/price-snapshot-service/internal/adapters/kraken"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/config"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+)
+
+// ProviderKraken is the Kraken public spot API
+const ProviderKraken = "kraken"
+
+func init() {
+	Register(ProviderKraken, newKraken)
+}
+
+// newKraken builds the Kraken client from the exchange configuration.
+// The request budget counts Binance weights and does not apply.
+func newKraken(cfg config.ExchangeConfig, deps Deps) (ports.ExchangeClient, error) {
+	retryJitter, err := cfg.RetryJitterMode()
+	if err != nil {
+		return nil, err
+	}
+
+	return kraken.NewClient(
+		kraken.WithBaseURL(cfg.BaseURL),
+		kraken.WithTimeout(cfg.Timeout),
+		kraken.WithRetry(cfg.MaxRetries, cfg.RetryBackoff),
+		kraken.WithRetryJitter(retryJitter),
+		kraken.WithTransport(deps.Transport),
+		kraken.WithErrorRecorder(deps.Errors),
+		kraken.WithRetryRecorder(deps.Retries),
+		kraken.WithLogger(deps.Logger),
+	), nil
+}
@@ -0,0 +1,223 @@
+// Package exchange builds price sources by provider name. Providers register
+// a Factory, typically from an init function, and the configured provider is
+// instantiated at startup without the application wiring knowing about it.
+package exchange
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/config"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+)
+
+// Deps are the shared components handed to every provider factory
+type Deps struct {
+	Errors ports.ExchangeErrorRecorder
+	Logger *slog.Logger
+
+	// Retries, when set, receives the retries of exchange requests
+	Retries ports.ExchangeRetryRecorder
+
+	// Budget, when set, paces requests to the exchange's weight limit
+	Budget ports.ExchangeBudget
+
+	// Transport, when set, replaces the default HTTP transport
+	Transport http.RoundTripper
+}
+
+// Factory builds an exchange client from the exchange configuration
+type Factory func(cfg config.ExchangeConfig, deps Deps) (ports.ExchangeClient, error)
+
+// StreamFactory builds a price stream from the exchange configuration
+type StreamFactory func(cfg config.ExchangeConfig, deps Deps) (ports.PriceStream, error)
+
+// Registry maps provider names to factories
+type Registry struct {
+	mu        sync.RWMutex
+	factories map[string]Factory
+	streams   map[string]StreamFactory
+}
+
+// NewRegistry creates an empty provider registry
+func NewRegistry() *Registry {
+	return &Registry{
+		factories: make(map[string]Factory),
+		streams:   make(map[string]StreamFactory),
+	}
+}
+
+// Register adds a provider factory. Names are case-insensitive.
+// It panics if the name is empty, the factory is nil or the name is taken.
+func (r *Registry) Register(name string, factory Factory) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		panic("exchange: provider name is empty")
+	}
+	if factory == nil {
+		panic("exchange: factory for provider " + name + " is nil")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.factories[name]; exists {
+		panic("exchange: provider " + name + " registered twice")
+	}
+	r.factories[name] = factory
+}
+
+// New builds a client for the named provider
+func (r *Registry) New(name string, cfg config.ExchangeConfig, deps Deps) (ports.ExchangeClient, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+
+	r.mu.RLock()
+	factory, ok := r.factories[name]
+	r.mu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("unknown exchange provider %q (registered: %s)",
+			name, strings.Join(r.Providers(), ", "))
+	}
+
+	if deps.Logger == nil {
+		deps.Logger = slog.Default()
+	}
+
+	client, err := factory(cfg, deps)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create exchange provider %s: %w", name, err)
+	}
+	return client, nil
+}
+
+// Open builds the clients of the configured exchanges: the default
+// Provider and every further one in Providers. Only the default provider's
+// client is paced by deps.Budget, since the weight limit is that
+// exchange's.
+func (r *Registry) Open(cfg config.ExchangeConfig, deps Deps) (*Clients, error) {
+	defaultName := strings.ToLower(strings.TrimSpace(cfg.Provider))
+	client, err := r.New(defaultName, cfg, deps)
+	if err != nil {
+		return nil, err
+	}
+
+	clients := &Clients{
+		defaultName: defaultName,
+		clients:     map[string]ports.ExchangeClient{defaultName: client},
+	}
+
+	further := deps
+	further.Budget = nil
+	for _, name := range cfg.Providers {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if _, exists := clients.clients[name]; exists {
+			return nil, fmt.Errorf("exchange provider %s is configured twice", name)
+		}
+
+		client, err := r.New(name, cfg.ForProvider(name), further)
+		if err != nil {
+			return nil, err
+		}
+		clients.clients[name] = client
+	}
+
+	return clients, nil
+}
+
+// RegisterStream adds the price stream factory of a provider that can push
+// prices. It panics if the name is empty, the factory is nil or the
+// provider already has a stream.
+func (r *Registry) RegisterStream(name string, factory StreamFactory) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		panic("exchange: provider name is empty")
+	}
+	if factory == nil {
+		panic("exchange: stream factory for provider " + name + " is nil")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.streams[name]; exists {
+		panic("exchange: stream for provider " + name + " registered twice")
+	}
+	r.streams[name] = factory
+}
+
+// NewStream builds a price stream for the named provider
+func (r *Registry) NewStream(name string, cfg config.ExchangeConfig, deps Deps) (ports.PriceStream, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+
+	r.mu.RLock()
+	factory, ok := r.streams[name]
+	r.mu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("exchange provider %q does not support streaming", name)
+	}
+
+	if deps.Logger == nil {
+		deps.Logger = slog.Default()
+	}
+
+	stream, err := factory(cfg, deps)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s price stream: %w", name, err)
+	}
+	return stream, nil
+}
+
+// Providers returns the registered provider names in sorted order
+func (r *Registry) Providers() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.factories))
+	for name := range r.factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// defaultRegistry holds the built-in providers and those registered by
+// packages imported into the binary
+var defaultRegistry = NewRegistry()
+
+// Register adds a provider factory to the default registry
+func Register(name string, factory Factory) {
+	defaultRegistry.Register(name, factory)
+}
+
+// New builds a client for the named provider from the default registry
+func New(name string, cfg config.ExchangeConfig, deps Deps) (ports.ExchangeClient, error) {
+	return defaultRegistry.New(name, cfg, deps)
+}
+
+// Open builds the clients of the configured exchanges from the default
+// registry
+func Open(cfg config.ExchangeConfig, deps Deps) (*Clients, error) {
+	return defaultRegistry.Open(cfg, deps)
+}
+
+// RegisterStream adds a price stream factory to the default registry
+func RegisterStream(name string, factory StreamFactory) {
+	defaultRegistry.RegisterStream(name, factory)
+}
+
+// NewStream builds a price stream for the named provider from the default
+// registry
+func NewStream(name string, cfg config.ExchangeConfig, deps Deps) (ports.PriceStream, error) {
+	return defaultRegistry.NewStream(name, cfg, deps)
+}
+
+// Providers returns the provider names in the default registry
+func Providers() []string {
+	return defaultRegistry.Providers()
+}
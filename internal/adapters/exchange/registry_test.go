@@ -0,0 +1,184 @@
+package exchange_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/adapters/binance"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/adapters/coinbase"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/adapters/exchange"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/adapters/kraken"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/config"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+)
+
+type stubClient struct {
+	ports.ExchangeClient
+	baseURL string
+}
+
+func (s *stubClient) Ping(ctx context.Context) error { return nil }
+
+type stubBudget struct {
+	ports.ExchangeBudget
+}
+
+func TestRegistry_New(t *testing.T) {
+	registry := exchange.NewRegistry()
+	registry.Register("OTC", func(cfg config.ExchangeConfig, deps exchange.Deps) (ports.ExchangeClient, error) {
+		return &stubClient{baseURL: cfg.BaseURL}, nil
+	})
+
+	client, err := registry.New("otc", config.ExchangeConfig{BaseURL: "http://otc.internal"}, exchange.Deps{})
+	require.NoError(t, err)
+
+	stub, ok := client.(*stubClient)
+	require.True(t, ok)
+	assert.Equal(t, "http://otc.internal", stub.baseURL)
+	assert.Equal(t, []string{"otc"}, registry.Providers())
+}
+
+func TestRegistry_UnknownProvider(t *testing.T) {
+	registry := exchange.NewRegistry()
+
+	_, err := registry.New("pricing-engine", config.ExchangeConfig{}, exchange.Deps{})
+	assert.ErrorContains(t, err, "unknown exchange provider")
+}
+
+func TestRegistry_FactoryError(t *testing.T) {
+	registry := exchange.NewRegistry()
+	factoryErr := errors.New("missing credentials")
+	registry.Register("otc", func(cfg config.ExchangeConfig, deps exchange.Deps) (ports.ExchangeClient, error) {
+		return nil, factoryErr
+	})
+
+	_, err := registry.New("otc", config.ExchangeConfig{}, exchange.Deps{})
+	assert.ErrorIs(t, err, factoryErr)
+}
+
+func TestRegistry_DuplicatePanics(t *testing.T) {
+	registry := exchange.NewRegistry()
+	factory := func(cfg config.ExchangeConfig, deps exchange.Deps) (ports.ExchangeClient, error) {
+		return &stubClient{}, nil
+	}
+	registry.Register("otc", factory)
+
+	assert.Panics(t, func() { registry.Register("otc", factory) })
+}
+
+func TestDefaultRegistry_Binance(t *testing.T) {
+	assert.Contains(t, exchange.Providers(), exchange.ProviderBinance)
+
+	client, err := exchange.New(exchange.ProviderBinance, config.ExchangeConfig{
+		Region:       "global",
+		RetryJitter:  "full",
+		HostCooldown: 30 * time.Second,
+	}, exchange.Deps{})
+	require.NoError(t, err)
+	assert.IsType(t, &binance.Client{}, client)
+}
+
+func TestRegistry_StreamUnsupported(t *testing.T) {
+	registry := exchange.NewRegistry()
+	registry.Register("otc", func(cfg config.ExchangeConfig, deps exchange.Deps) (ports.ExchangeClient, error) {
+		return &stubClient{}, nil
+	})
+
+	_, err := registry.NewStream("otc", config.ExchangeConfig{}, exchange.Deps{})
+	assert.ErrorContains(t, err, "does not support streaming")
+}
+
+func TestDefaultRegistry_BinanceStream(t *testing.T) {
+	stream, err := exchange.NewStream(exchange.ProviderBinance, config.ExchangeConfig{Region: "us"}, exchange.Deps{})
+	require.NoError(t, err)
+	assert.IsType(t, &binance.Stream{}, stream)
+}
+
+func TestRegistry_Open(t *testing.T) {
+	registry := exchange.NewRegistry()
+	var budgets []ports.ExchangeBudget
+	for _, name := range []string{"otc", "dex"} {
+		registry.Register(name, func(cfg config.ExchangeConfig, deps exchange.Deps) (ports.ExchangeClient, error) {
+			budgets = append(budgets, deps.Budget)
+			return &stubClient{baseURL: cfg.BaseURL}, nil
+		})
+	}
+
+	clients, err := registry.Open(config.ExchangeConfig{
+		Provider:  "OTC",
+		BaseURL:   "http://otc.internal",
+		Providers: []string{"dex"},
+	}, exchange.Deps{Budget: &stubBudget{}})
+	require.NoError(t, err)
+
+	assert.Equal(t, "otc", clients.Default())
+	assert.Equal(t, []string{"dex", "otc"}, clients.Names())
+
+	def, err := clients.Client("")
+	require.NoError(t, err)
+	assert.Equal(t, "http://otc.internal", def.(*stubClient).baseURL)
+
+	dex, err := clients.Client("DEX")
+	require.NoError(t, err)
+	assert.Empty(t, dex.(*stubClient).baseURL, "further providers use their own endpoints")
+
+	require.Len(t, budgets, 2)
+	assert.NotNil(t, budgets[0])
+	assert.Nil(t, budgets[1], "only the default exchange is budgeted")
+
+	_, err = clients.Client("kraken")
+	assert.ErrorIs(t, err, domain.ErrUnknownExchange)
+}
+
+func TestRegistry_OpenUnknownProvider(t *testing.T) {
+	registry := exchange.NewRegistry()
+	registry.Register("otc", func(cfg config.ExchangeConfig, deps exchange.Deps) (ports.ExchangeClient, error) {
+		return &stubClient{}, nil
+	})
+
+	_, err := registry.Open(config.ExchangeConfig{Provider: "otc", Providers: []string{"kraken"}}, exchange.Deps{})
+	assert.ErrorContains(t, err, "unknown exchange provider")
+}
+
+func TestDefaultRegistry_BinanceUS(t *testing.T) {
+	clients, err := exchange.Open(config.ExchangeConfig{
+		Provider:     exchange.ProviderBinance,
+		Region:       "global",
+		RetryJitter:  "full",
+		HostCooldown: 30 * time.Second,
+		Providers:    []string{exchange.ProviderBinanceUS},
+	}, exchange.Deps{})
+	require.NoError(t, err)
+
+	client, err := clients.Client(exchange.ProviderBinanceUS)
+	require.NoError(t, err)
+	assert.IsType(t, &binance.Client{}, client)
+}
+
+func TestDefaultRegistry_CoinbaseKraken(t *testing.T) {
+	clients, err := exchange.Open(config.ExchangeConfig{
+		Provider:     exchange.ProviderBinance,
+		Region:       "global",
+		RetryJitter:  "full",
+		HostCooldown: 30 * time.Second,
+		Providers:    []string{exchange.ProviderCoinbase, exchange.ProviderKraken},
+	}, exchange.Deps{})
+	require.NoError(t, err)
+
+	client, err := clients.Client(exchange.ProviderCoinbase)
+	require.NoError(t, err)
+	assert.IsType(t, &coinbase.Client{}, client)
+
+	client, err = clients.Client(exchange.ProviderKraken)
+	require.NoError(t, err)
+	assert.IsType(t, &kraken.Client{}, client)
+
+	_, err = exchange.NewStream(exchange.ProviderKraken, config.ExchangeConfig{}, exchange.Deps{})
+	assert.ErrorContains(t, err, "does not support streaming")
+}
@@ -0,0 +1,169 @@
+package grpc
+
+import (
+	"time"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+)
+
+// The messages of api/proto/snapshot/v1/snapshot.proto. Requests decode
+// their fields by number and ignore fields they do not know, so clients
+// built from a newer contract keep working.
+
+type listSymbolsRequest struct {
+	Tag string
+}
+
+func (m *listSymbolsRequest) unmarshal(data []byte) error {
+	return decodeMessage(data, func(f field) error {
+		if f.num == 1 && f.typ == wireBytes {
+			m.Tag = string(f.bytes)
+		}
+		return nil
+	})
+}
+
+type addSymbolRequest struct {
+	Symbol   string
+	Exchange string
+	Tags     []string
+}
+
+func (m *addSymbolRequest) unmarshal(data []byte) error {
+	return decodeMessage(data, func(f field) error {
+		if f.typ != wireBytes {
+			return nil
+		}
+		switch f.num {
+		case 1:
+			m.Symbol = string(f.bytes)
+		case 2:
+			m.Exchange = string(f.bytes)
+		case 3:
+			m.Tags = append(m.Tags, string(f.bytes))
+		}
+		return nil
+	})
+}
+
+// symbolsRequest is GetLatestPricesRequest and StreamPricesRequest, which
+// both only list symbols
+type symbolsRequest struct {
+	Symbols []string
+}
+
+func (m *symbolsRequest) unmarshal(data []byte) error {
+	return decodeMessage(data, func(f field) error {
+		if f.num == 1 && f.typ == wireBytes {
+			m.Symbols = append(m.Symbols, string(f.bytes))
+		}
+		return nil
+	})
+}
+
+type getHistoryRequest struct {
+	Symbol string
+	From   time.Time
+	To     time.Time
+	Limit  int
+	Cursor string
+}
+
+func (m *getHistoryRequest) unmarshal(data []byte) error {
+	return decodeMessage(data, func(f field) error {
+		var err error
+		switch {
+		case f.num == 1 && f.typ == wireBytes:
+			m.Symbol = string(f.bytes)
+		case f.num == 2 && f.typ == wireBytes:
+			m.From, err = decodeTimestamp(f.bytes)
+		case f.num == 3 && f.typ == wireBytes:
+			m.To, err = decodeTimestamp(f.bytes)
+		case f.num == 4 && f.typ == wireVarint:
+			m.Limit = int(int32(f.varint))
+		case f.num == 5 && f.typ == wireBytes:
+			m.Cursor = string(f.bytes)
+		}
+		return err
+	})
+}
+
+func marshalSymbol(s *domain.Symbol) []byte {
+	var b []byte
+	b = appendString(b, 1, s.Name)
+	b = appendBool(b, 2, s.Active)
+	b = appendString(b, 3, s.Exchange)
+	for _, tag := range s.Tags {
+		b = appendBytes(b, 4, []byte(tag))
+	}
+	b = appendTimestamp(b, 5, s.CreatedAt)
+	b = appendTimestamp(b, 6, s.UpdatedAt)
+	return b
+}
+
+func marshalPrice(s *domain.PriceSnapshot) []byte {
+	var b []byte
+	b = appendString(b, 1, s.Symbol)
+	b = appendString(b, 2, s.Price.String())
+	b = appendTimestamp(b, 3, s.Timestamp)
+	return b
+}
+
+func marshalListSymbolsResponse(symbols []*domain.Symbol) []byte {
+	var b []byte
+	for _, s := range symbols {
+		b = appendBytes(b, 1, marshalSymbol(s))
+	}
+	return b
+}
+
+func marshalAddSymbolResponse(symbol *domain.Symbol, created bool) []byte {
+	var b []byte
+	b = appendBytes(b, 1, marshalSymbol(symbol))
+	b = appendBool(b, 2, created)
+	return b
+}
+
+func marshalGetLatestPricesResponse(prices []*domain.PriceSnapshot, missing []string) []byte {
+	var b []byte
+	for _, p := range prices {
+		b = appendBytes(b, 1, marshalPrice(p))
+	}
+	for _, name := range missing {
+		b = appendBytes(b, 2, []byte(name))
+	}
+	return b
+}
+
+// historyPage is a GetHistoryResponse
+type historyPage struct {
+	Symbol     string
+	Items      []*domain.PriceSnapshot
+	Limit      int
+	HasMore    bool
+	Summary    *domain.HistorySummary
+	NextCursor string
+}
+
+func (p *historyPage) marshal() []byte {
+	var b []byte
+	b = appendString(b, 1, p.Symbol)
+	for _, item := range p.Items {
+		b = appendBytes(b, 2, marshalPrice(item))
+	}
+	b = appendInt(b, 3, int64(p.Limit))
+	b = appendBool(b, 4, p.HasMore)
+	b = appendInt(b, 5, p.Summary.TotalCount)
+	if p.Summary.Oldest != nil {
+		b = appendTimestamp(b, 6, *p.Summary.Oldest)
+	}
+	if p.Summary.Newest != nil {
+		b = appendTimestamp(b, 7, *p.Summary.Newest)
+	}
+	b = appendString(b, 8, p.NextCursor)
+	return b
+}
+
+func marshalPriceUpdate(s *domain.PriceSnapshot) []byte {
+	return appendBytes(nil, 1, marshalPrice(s))
+}
@@ -0,0 +1,275 @@
+package grpc
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/config"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+)
+
+const (
+	// servicePath prefixes the path of every SnapshotService method
+	servicePath = "/snapshot.v1.SnapshotService/"
+
+	// maxMessageBytes caps request messages, as gRPC servers do by default
+	maxMessageBytes = 4 << 20
+)
+
+// unaryMethod handles a call with a single response message
+type unaryMethod func(ctx context.Context, req []byte) ([]byte, error)
+
+// streamMethod handles a call with a stream of response messages
+type streamMethod func(ctx context.Context, req []byte, send func([]byte) error) error
+
+// Server serves the gRPC API over cleartext HTTP/2 with graceful shutdown.
+// The protocol is spoken directly on net/http, so the service has no
+// dependency on a gRPC framework.
+type Server struct {
+	server  *http.Server
+	unary   map[string]unaryMethod
+	streams map[string]streamMethod
+	logger  *slog.Logger
+
+	// stopping is closed on shutdown to end open streams, which would
+	// otherwise keep the server from shutting down
+	stopping chan struct{}
+	stopOnce sync.Once
+}
+
+// NewServer creates a new gRPC server
+func NewServer(
+	cfg config.GRPCConfig,
+	symbolSvc ports.SymbolService,
+	snapshotSvc ports.SnapshotService,
+	logger *slog.Logger,
+) *Server {
+	svc := &service{
+		symbolSvc:      symbolSvc,
+		snapshotSvc:    snapshotSvc,
+		streamInterval: cfg.StreamInterval,
+	}
+
+	s := &Server{
+		unary: map[string]unaryMethod{
+			"ListSymbols":     svc.listSymbols,
+			"AddSymbol":       svc.addSymbol,
+			"GetLatestPrices": svc.getLatestPrices,
+			"GetHistory":      svc.getHistory,
+		},
+		streams: map[string]streamMethod{
+			"StreamPrices": svc.streamPrices,
+		},
+		logger:   logger.With("component", "grpc_server"),
+		stopping: make(chan struct{}),
+	}
+
+	protocols := new(http.Protocols)
+	protocols.SetUnencryptedHTTP2(true)
+	s.server = &http.Server{
+		Addr:      fmt.Sprintf(":%d", cfg.Port),
+		Handler:   s,
+		Protocols: protocols,
+	}
+
+	return s
+}
+
+// Start starts the gRPC server
+func (s *Server) Start() error {
+	ln, err := net.Listen("tcp", s.server.Addr)
+	if err != nil {
+		return fmt.Errorf("grpc server error: %w", err)
+	}
+	return s.Serve(ln)
+}
+
+// Serve accepts connections on the listener
+func (s *Server) Serve(ln net.Listener) error {
+	s.logger.Info("starting grpc server", "addr", ln.Addr().String())
+
+	if err := s.server.Serve(ln); err != nil && err != http.ErrServerClosed {
+		return fmt.Errorf("grpc server error: %w", err)
+	}
+	return nil
+}
+
+// Shutdown ends open streams and waits for calls in flight to finish
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.logger.Info("shutting down grpc server")
+	s.stopOnce.Do(func() { close(s.stopping) })
+
+	shutdownCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	return s.server.Shutdown(shutdownCtx)
+}
+
+// Addr returns the server address
+func (s *Server) Addr() string {
+	return s.server.Addr
+}
+
+// ServeHTTP handles a gRPC call. Responses always carry their status in
+// the grpc-status and grpc-message trailers.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.ProtoMajor != 2 || r.Method != http.MethodPost || !strings.HasPrefix(r.Header.Get("Content-Type"), "application/grpc") {
+		http.Error(w, "gRPC requests only", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/grpc")
+	w.Header().Add("Trailer", "Grpc-Status")
+	w.Header().Add("Trailer", "Grpc-Message")
+	w.WriteHeader(http.StatusOK)
+
+	err := s.call(w, r)
+	status := &statusError{code: codeOK}
+	if err != nil {
+		status = toStatus(err)
+		if status.code == codeInternal {
+			s.logger.Error("grpc call failed", "method", r.URL.Path, "error", err)
+		}
+	}
+
+	w.Header().Set("Grpc-Status", formatCode(status.code))
+	w.Header().Set("Grpc-Message", encodeStatusMessage(status.message))
+}
+
+// call reads the request message and runs the method
+func (s *Server) call(w http.ResponseWriter, r *http.Request) error {
+	name, ok := strings.CutPrefix(r.URL.Path, servicePath)
+	if !ok {
+		return statusf(codeUnimplemented, "unknown service in %s", r.URL.Path)
+	}
+	unary, isUnary := s.unary[name]
+	stream, isStream := s.streams[name]
+	if !isUnary && !isStream {
+		return statusf(codeUnimplemented, "unknown method %s", name)
+	}
+
+	ctx, cancel, err := callContext(r)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	req, err := readMessage(r.Body)
+	if err != nil {
+		return err
+	}
+
+	if isUnary {
+		resp, err := unary(ctx, req)
+		if err != nil {
+			return err
+		}
+		return writeMessage(w, resp)
+	}
+
+	// Stop the stream when the server shuts down
+	ctx, stop := context.WithCancelCause(ctx)
+	defer stop(nil)
+	go func() {
+		select {
+		case <-s.stopping:
+			stop(statusf(codeUnavailable, "server is shutting down"))
+		case <-ctx.Done():
+		}
+	}()
+
+	err = stream(ctx, req, func(msg []byte) error {
+		return writeMessage(w, msg)
+	})
+	if cause := context.Cause(ctx); cause != nil && err != nil {
+		return cause
+	}
+	return err
+}
+
+// callContext returns the request context bounded by the grpc-timeout
+// header, if the client sent one
+func callContext(r *http.Request) (context.Context, context.CancelFunc, error) {
+	header := r.Header.Get("Grpc-Timeout")
+	if header == "" {
+		ctx, cancel := context.WithCancel(r.Context())
+		return ctx, cancel, nil
+	}
+
+	timeout, err := parseTimeout(header)
+	if err != nil {
+		return nil, nil, err
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	return ctx, cancel, nil
+}
+
+// parseTimeout parses a grpc-timeout value: up to 8 digits and a unit
+func parseTimeout(value string) (time.Duration, error) {
+	units := map[byte]time.Duration{
+		'H': time.Hour,
+		'M': time.Minute,
+		'S': time.Second,
+		'm': time.Millisecond,
+		'u': time.Microsecond,
+		'n': time.Nanosecond,
+	}
+
+	if len(value) < 2 || len(value) > 9 {
+		return 0, statusf(codeInvalidArgument, "invalid grpc-timeout %q", value)
+	}
+	unit, ok := units[value[len(value)-1]]
+	n, err := strconv.ParseInt(value[:len(value)-1], 10, 64)
+	if !ok || err != nil || n < 0 {
+		return 0, statusf(codeInvalidArgument, "invalid grpc-timeout %q", value)
+	}
+	return time.Duration(n) * unit, nil
+}
+
+// readMessage reads the single length-prefixed message of a request
+func readMessage(body io.Reader) ([]byte, error) {
+	var header [5]byte
+	if _, err := io.ReadFull(body, header[:]); err != nil {
+		return nil, statusf(codeInvalidArgument, "missing request message")
+	}
+	if header[0] != 0 {
+		return nil, statusf(codeUnimplemented, "compressed messages are not supported")
+	}
+
+	size := binary.BigEndian.Uint32(header[1:])
+	if size > maxMessageBytes {
+		return nil, statusf(codeResourceExhausted, "request message larger than %d bytes", maxMessageBytes)
+	}
+
+	msg := make([]byte, size)
+	if _, err := io.ReadFull(body, msg); err != nil {
+		return nil, statusf(codeInvalidArgument, "truncated request message")
+	}
+	return msg, nil
+}
+
+// writeMessage writes an uncompressed length-prefixed message and flushes
+// it, so stream messages reach the client as they are sent
+func writeMessage(w http.ResponseWriter, msg []byte) error {
+	framed := make([]byte, 5, 5+len(msg))
+	binary.BigEndian.PutUint32(framed[1:], uint32(len(msg)))
+	framed = append(framed, msg...)
+
+	if _, err := w.Write(framed); err != nil {
+		return err
+	}
+	if err := http.NewResponseController(w).Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,351 @@
+package grpc_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	grpcAdapter "github.com/prxgr4mmer/price-snapshot-service/internal/adapters/grpc"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/config"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+)
+
+type mockSymbolService struct {
+	ports.SymbolService
+	symbols []*domain.Symbol
+}
+
+func (m *mockSymbolService) ListSymbols(ctx context.Context) ([]*domain.Symbol, error) {
+	return m.symbols, nil
+}
+
+func (m *mockSymbolService) AddSymbol(ctx context.Context, name, exchange string, tags ...string) (*domain.Symbol, error) {
+	for _, s := range m.symbols {
+		if s.Name == name {
+			return nil, domain.ErrSymbolExists
+		}
+	}
+	if err := domain.ValidateSymbolName(name); err != nil {
+		return nil, err
+	}
+	s := &domain.Symbol{Name: name, Exchange: exchange, Active: true, Tags: tags}
+	m.symbols = append(m.symbols, s)
+	return s, nil
+}
+
+func (m *mockSymbolService) GetSymbol(ctx context.Context, name string) (*domain.Symbol, error) {
+	for _, s := range m.symbols {
+		if s.Name == name {
+			return s, nil
+		}
+	}
+	return nil, domain.ErrSymbolNotFound
+}
+
+type mockSnapshotService struct {
+	ports.SnapshotService
+
+	mu      sync.Mutex
+	history []*domain.PriceSnapshot
+}
+
+func (m *mockSnapshotService) GetLatestPrices(ctx context.Context, symbols []string) ([]*domain.PriceSnapshot, []string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var prices []*domain.PriceSnapshot
+	var missing []string
+	for _, name := range symbols {
+		if len(m.history) > 0 && m.history[0].Symbol == name {
+			prices = append(prices, m.history[0])
+		} else {
+			missing = append(missing, name)
+		}
+	}
+	return prices, missing, nil
+}
+
+func (m *mockSnapshotService) GetPriceHistory(ctx context.Context, symbol string, before *domain.HistoryCursor, limit int) ([]*domain.PriceSnapshot, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var page []*domain.PriceSnapshot
+	for _, s := range m.history {
+		if (before == nil || before.Follows(s)) && len(page) < limit {
+			page = append(page, s)
+		}
+	}
+	return page, nil
+}
+
+func (m *mockSnapshotService) GetHistorySummary(ctx context.Context, symbol string) (*domain.HistorySummary, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return &domain.HistorySummary{TotalCount: int64(len(m.history))}, nil
+}
+
+// store records a newer snapshot
+func (m *mockSnapshotService) store(s *domain.PriceSnapshot) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.history = append([]*domain.PriceSnapshot{s}, m.history...)
+}
+
+// protoField is a decoded protobuf field
+type protoField struct {
+	num    int
+	varint uint64
+	bytes  []byte
+}
+
+func decodeFields(t *testing.T, data []byte) []protoField {
+	var fields []protoField
+	for len(data) > 0 {
+		tag, n := binary.Uvarint(data)
+		require.Positive(t, n)
+		data = data[n:]
+
+		f := protoField{num: int(tag >> 3)}
+		switch tag & 7 {
+		case 0:
+			f.varint, n = binary.Uvarint(data)
+			require.Positive(t, n)
+			data = data[n:]
+		case 2:
+			size, n := binary.Uvarint(data)
+			require.Positive(t, n)
+			f.bytes = data[n : n+int(size)]
+			data = data[n+int(size):]
+		default:
+			t.Fatalf("unexpected wire type %d", tag&7)
+		}
+		fields = append(fields, f)
+	}
+	return fields
+}
+
+func appendString(b []byte, num int, s string) []byte {
+	b = binary.AppendUvarint(b, uint64(num)<<3|2)
+	b = binary.AppendUvarint(b, uint64(len(s)))
+	return append(b, s...)
+}
+
+type testServer struct {
+	server   *grpcAdapter.Server
+	client   *http.Client
+	baseURL  string
+	symbols  *mockSymbolService
+	snapshot *mockSnapshotService
+}
+
+func newTestServer(t *testing.T) *testServer {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	ts := &testServer{
+		symbols:  &mockSymbolService{symbols: []*domain.Symbol{{Name: "BTCUSDT", Exchange: "binance", Active: true, Tags: []string{"l1"}}}},
+		snapshot: &mockSnapshotService{},
+	}
+	ts.server = grpcAdapter.NewServer(config.GRPCConfig{StreamInterval: 10 * time.Millisecond}, ts.symbols, ts.snapshot, logger)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go ts.server.Serve(ln)
+	t.Cleanup(func() { ts.server.Shutdown(context.Background()) })
+
+	protocols := new(http.Protocols)
+	protocols.SetUnencryptedHTTP2(true)
+	ts.client = &http.Client{Transport: &http.Transport{Protocols: protocols}}
+	ts.baseURL = "http://" + ln.Addr().String() + "/snapshot.v1.SnapshotService/"
+	return ts
+}
+
+// call sends a request message and returns the response messages and the
+// grpc-status
+func (ts *testServer) call(t *testing.T, method string, req []byte) ([][]byte, int) {
+	resp := ts.open(t, context.Background(), method, req)
+	defer resp.Body.Close()
+
+	var messages [][]byte
+	for {
+		msg, ok := readFrame(t, resp.Body)
+		if !ok {
+			break
+		}
+		messages = append(messages, msg)
+	}
+
+	status, err := strconv.Atoi(resp.Trailer.Get("Grpc-Status"))
+	require.NoError(t, err)
+	return messages, status
+}
+
+func (ts *testServer) open(t *testing.T, ctx context.Context, method string, req []byte) *http.Response {
+	body := make([]byte, 5, 5+len(req))
+	binary.BigEndian.PutUint32(body[1:], uint32(len(req)))
+	body = append(body, req...)
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, ts.baseURL+method, bytes.NewReader(body))
+	require.NoError(t, err)
+	httpReq.Header.Set("Content-Type", "application/grpc")
+	httpReq.Header.Set("TE", "trailers")
+
+	resp, err := ts.client.Do(httpReq)
+	require.NoError(t, err)
+	require.Equal(t, 2, resp.ProtoMajor)
+	require.Equal(t, "application/grpc", resp.Header.Get("Content-Type"))
+	return resp
+}
+
+func readFrame(t *testing.T, body io.Reader) ([]byte, bool) {
+	var header [5]byte
+	if _, err := io.ReadFull(body, header[:]); err != nil {
+		return nil, false
+	}
+	msg := make([]byte, binary.BigEndian.Uint32(header[1:]))
+	_, err := io.ReadFull(body, msg)
+	require.NoError(t, err)
+	return msg, true
+}
+
+func TestServer_UnaryCalls(t *testing.T) {
+	ts := newTestServer(t)
+
+	t.Run("lists symbols", func(t *testing.T) {
+		messages, status := ts.call(t, "ListSymbols", nil)
+		require.Equal(t, 0, status)
+		require.Len(t, messages, 1)
+
+		symbols := decodeFields(t, messages[0])
+		require.Len(t, symbols, 1)
+		symbol := decodeFields(t, symbols[0].bytes)
+		assert.Equal(t, protoField{num: 1, bytes: []byte("BTCUSDT")}, symbol[0])
+		assert.Equal(t, protoField{num: 2, varint: 1}, symbol[1])
+		assert.Equal(t, protoField{num: 3, bytes: []byte("binance")}, symbol[2])
+		assert.Equal(t, protoField{num: 4, bytes: []byte("l1")}, symbol[3])
+	})
+
+	t.Run("returns an existing symbol as not created", func(t *testing.T) {
+		messages, status := ts.call(t, "AddSymbol", appendString(nil, 1, "BTCUSDT"))
+		require.Equal(t, 0, status)
+
+		fields := decodeFields(t, messages[0])
+		require.Len(t, fields, 1, "created is false and left out")
+		assert.Equal(t, 1, fields[0].num)
+	})
+
+	t.Run("adds a new symbol", func(t *testing.T) {
+		messages, status := ts.call(t, "AddSymbol", appendString(nil, 1, "ETHUSDT"))
+		require.Equal(t, 0, status)
+
+		fields := decodeFields(t, messages[0])
+		require.Len(t, fields, 2)
+		assert.Equal(t, protoField{num: 2, varint: 1}, fields[1])
+	})
+
+	t.Run("maps domain errors to status codes", func(t *testing.T) {
+		_, status := ts.call(t, "AddSymbol", appendString(nil, 1, "bad symbol!"))
+		assert.Equal(t, 3, status, "INVALID_ARGUMENT")
+
+		_, status = ts.call(t, "GetLatestPrices", nil)
+		assert.Equal(t, 3, status, "INVALID_ARGUMENT")
+
+		_, status = ts.call(t, "Unknown", nil)
+		assert.Equal(t, 12, status, "UNIMPLEMENTED")
+
+		_, status = ts.call(t, "ListSymbols", []byte{0xff})
+		assert.Equal(t, 3, status, "INVALID_ARGUMENT")
+	})
+}
+
+func TestServer_GetHistoryPages(t *testing.T) {
+	ts := newTestServer(t)
+	start := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	for i := 1; i <= 3; i++ {
+		ts.snapshot.store(&domain.PriceSnapshot{ID: int64(i), Symbol: "BTCUSDT", Price: decimal.NewFromInt(int64(i)), Timestamp: start.Add(time.Duration(i) * time.Second)})
+	}
+
+	req := appendString(nil, 1, "BTCUSDT")
+	req = append(req, 4<<3, 2)
+	messages, status := ts.call(t, "GetHistory", req)
+	require.Equal(t, 0, status)
+
+	var prices []string
+	var hasMore bool
+	var cursor string
+	for _, f := range decodeFields(t, messages[0]) {
+		switch f.num {
+		case 2:
+			prices = append(prices, string(decodeFields(t, f.bytes)[1].bytes))
+		case 4:
+			hasMore = f.varint == 1
+		case 8:
+			cursor = string(f.bytes)
+		}
+	}
+	assert.Equal(t, []string{"3", "2"}, prices)
+	assert.True(t, hasMore)
+	require.NotEmpty(t, cursor)
+
+	messages, status = ts.call(t, "GetHistory", appendString(req, 5, cursor))
+	require.Equal(t, 0, status)
+	prices, hasMore = nil, false
+	for _, f := range decodeFields(t, messages[0]) {
+		switch f.num {
+		case 2:
+			prices = append(prices, string(decodeFields(t, f.bytes)[1].bytes))
+		case 4:
+			hasMore = true
+		}
+	}
+	assert.Equal(t, []string{"1"}, prices)
+	assert.False(t, hasMore)
+}
+
+func TestServer_StreamPrices(t *testing.T) {
+	ts := newTestServer(t)
+	start := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	ts.snapshot.store(&domain.PriceSnapshot{ID: 1, Symbol: "BTCUSDT", Price: decimal.NewFromInt(100), Timestamp: start})
+
+	resp := ts.open(t, context.Background(), "StreamPrices", appendString(nil, 1, "BTCUSDT"))
+	defer resp.Body.Close()
+
+	price := func() string {
+		msg, ok := readFrame(t, resp.Body)
+		require.True(t, ok)
+		update := decodeFields(t, msg)
+		return string(decodeFields(t, update[0].bytes)[1].bytes)
+	}
+
+	assert.Equal(t, "100", price(), "the latest price is sent right away")
+
+	ts.snapshot.store(&domain.PriceSnapshot{ID: 2, Symbol: "BTCUSDT", Price: decimal.NewFromInt(101), Timestamp: start.Add(time.Second)})
+	assert.Equal(t, "101", price(), "a newer snapshot is sent once stored")
+
+	// Shutdown ends the stream rather than waiting for the client
+	done := make(chan error, 1)
+	go func() { done <- ts.server.Shutdown(context.Background()) }()
+
+	_, ok := readFrame(t, resp.Body)
+	assert.False(t, ok)
+	assert.Equal(t, "14", resp.Trailer.Get("Grpc-Status"), "UNAVAILABLE")
+
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("shutdown waited for the stream")
+	}
+}
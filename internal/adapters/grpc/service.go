@@ -0,0 +1,206 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+)
+
+// defaultHistoryLimit is the page size of GetHistory without a limit, as
+// on GET /history
+const defaultHistoryLimit = 100
+
+// service implements the SnapshotService RPCs on the application services
+type service struct {
+	symbolSvc      ports.SymbolService
+	snapshotSvc    ports.SnapshotService
+	streamInterval time.Duration
+}
+
+func (s *service) listSymbols(ctx context.Context, data []byte) ([]byte, error) {
+	var req listSymbolsRequest
+	if err := req.unmarshal(data); err != nil {
+		return nil, err
+	}
+
+	var (
+		symbols []*domain.Symbol
+		err     error
+	)
+	if req.Tag != "" {
+		symbols, err = s.symbolSvc.ListSymbolsByTag(ctx, req.Tag)
+	} else {
+		symbols, err = s.symbolSvc.ListSymbols(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return marshalListSymbolsResponse(symbols), nil
+}
+
+func (s *service) addSymbol(ctx context.Context, data []byte) ([]byte, error) {
+	var req addSymbolRequest
+	if err := req.unmarshal(data); err != nil {
+		return nil, err
+	}
+	if req.Symbol == "" {
+		return nil, statusf(codeInvalidArgument, "symbol is required")
+	}
+
+	symbol, err := s.symbolSvc.AddSymbol(ctx, req.Symbol, req.Exchange, req.Tags...)
+	if errors.Is(err, domain.ErrSymbolExists) {
+		existing, getErr := s.symbolSvc.GetSymbol(ctx, req.Symbol)
+		if getErr != nil {
+			return nil, getErr
+		}
+		return marshalAddSymbolResponse(existing, false), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return marshalAddSymbolResponse(symbol, true), nil
+}
+
+func (s *service) getLatestPrices(ctx context.Context, data []byte) ([]byte, error) {
+	var req symbolsRequest
+	if err := req.unmarshal(data); err != nil {
+		return nil, err
+	}
+	if len(req.Symbols) == 0 {
+		return nil, statusf(codeInvalidArgument, "symbols are required")
+	}
+
+	prices, missing, err := s.snapshotSvc.GetLatestPrices(ctx, req.Symbols)
+	if err != nil {
+		return nil, err
+	}
+
+	return marshalGetLatestPricesResponse(prices, missing), nil
+}
+
+func (s *service) getHistory(ctx context.Context, data []byte) ([]byte, error) {
+	var req getHistoryRequest
+	if err := req.unmarshal(data); err != nil {
+		return nil, err
+	}
+	if req.Symbol == "" {
+		return nil, statusf(codeInvalidArgument, "symbol is required")
+	}
+
+	limit := defaultHistoryLimit
+	if req.Limit > 0 && req.Limit <= domain.MaxHistoryLimit {
+		limit = req.Limit
+	}
+
+	var cursor *domain.HistoryCursor
+	if req.Cursor != "" {
+		var err error
+		if cursor, err = domain.ParseHistoryCursor(req.Cursor); err != nil {
+			return nil, err
+		}
+	}
+
+	// Either end of the window may be open, as on GET /history
+	ranged := !req.From.IsZero() || !req.To.IsZero()
+	if ranged && req.To.IsZero() {
+		req.To = time.Now().UTC()
+	}
+	if ranged && !req.From.Before(req.To) {
+		return nil, domain.ErrInvalidTimeRange
+	}
+
+	history, err := s.history(ctx, req, ranged, cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	summary, err := s.snapshotSvc.GetHistorySummary(ctx, req.Symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &historyPage{
+		Symbol:  domain.NormalizeSymbol(req.Symbol),
+		Items:   history,
+		Limit:   limit,
+		Summary: summary,
+	}
+
+	// A full page is followed by another when the window holds snapshots
+	// past its oldest
+	if len(history) == limit {
+		next := domain.HistoryCursorAt(history[limit-1])
+		older, err := s.history(ctx, req, ranged, &next, 1)
+		if err != nil {
+			return nil, err
+		}
+		if len(older) > 0 {
+			page.HasMore = true
+			page.NextCursor = next.Encode()
+		}
+	}
+
+	return page.marshal(), nil
+}
+
+func (s *service) history(ctx context.Context, req getHistoryRequest, ranged bool, before *domain.HistoryCursor, limit int) ([]*domain.PriceSnapshot, error) {
+	if ranged {
+		return s.snapshotSvc.GetPriceHistoryBetween(ctx, req.Symbol, req.From, req.To, before, limit)
+	}
+	return s.snapshotSvc.GetPriceHistory(ctx, req.Symbol, before, limit)
+}
+
+// streamPrices sends each symbol's latest price once, then again whenever
+// a newer snapshot is stored, checking every streamInterval. Reading the
+// store rather than the poller sees snapshots stored by every replica.
+func (s *service) streamPrices(ctx context.Context, data []byte, send func([]byte) error) error {
+	var req symbolsRequest
+	if err := req.unmarshal(data); err != nil {
+		return err
+	}
+
+	sent := make(map[string]time.Time)
+	ticker := time.NewTicker(s.streamInterval)
+	defer ticker.Stop()
+
+	for {
+		symbols := req.Symbols
+		if len(symbols) == 0 {
+			tracked, err := s.symbolSvc.ListSymbols(ctx)
+			if err != nil {
+				return err
+			}
+			symbols = make([]string, len(tracked))
+			for i, sym := range tracked {
+				symbols[i] = sym.Name
+			}
+		}
+
+		if len(symbols) > 0 {
+			prices, _, err := s.snapshotSvc.GetLatestPrices(ctx, symbols)
+			if err != nil {
+				return err
+			}
+			for _, p := range prices {
+				if last, ok := sent[p.Symbol]; ok && !p.Timestamp.After(last) {
+					continue
+				}
+				if err := send(marshalPriceUpdate(p)); err != nil {
+					return err
+				}
+				sent[p.Symbol] = p.Timestamp
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
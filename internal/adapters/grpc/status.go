@@ -0,0 +1,106 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+)
+
+// code is a gRPC status code
+type code int
+
+const (
+	codeOK                 code = 0
+	codeCanceled           code = 1
+	codeInvalidArgument    code = 3
+	codeDeadlineExceeded   code = 4
+	codeNotFound           code = 5
+	codeResourceExhausted  code = 8
+	codeFailedPrecondition code = 9
+	codeUnimplemented      code = 12
+	codeInternal           code = 13
+	codeUnavailable        code = 14
+)
+
+// statusError is an error sent to the client as a gRPC status
+type statusError struct {
+	code    code
+	message string
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("rpc error: code = %d desc = %s", e.code, e.message)
+}
+
+func statusf(c code, format string, args ...interface{}) error {
+	return &statusError{code: c, message: fmt.Sprintf(format, args...)}
+}
+
+// toStatus maps an error to the status sent to the client, the way the HTTP
+// API maps it to a response status
+func toStatus(err error) *statusError {
+	var status *statusError
+	switch {
+	case errors.As(err, &status):
+		return status
+
+	case errors.Is(err, context.Canceled):
+		return &statusError{codeCanceled, "request cancelled"}
+
+	case errors.Is(err, context.DeadlineExceeded):
+		return &statusError{codeDeadlineExceeded, "deadline exceeded"}
+
+	case errors.Is(err, errMalformed):
+		return &statusError{codeInvalidArgument, err.Error()}
+
+	case errors.Is(err, domain.ErrInvalidSymbol),
+		errors.Is(err, domain.ErrInvalidTag),
+		errors.Is(err, domain.ErrInvalidTimeRange),
+		errors.Is(err, domain.ErrInvalidCursor),
+		errors.Is(err, domain.ErrUnknownExchange):
+		return &statusError{codeInvalidArgument, err.Error()}
+
+	case errors.Is(err, domain.ErrSymbolNotFound),
+		errors.Is(err, domain.ErrSnapshotNotFound):
+		return &statusError{codeNotFound, err.Error()}
+
+	case errors.Is(err, domain.ErrSymbolQuota):
+		return &statusError{codeFailedPrecondition, err.Error()}
+
+	case errors.Is(err, domain.ErrRateLimited):
+		return &statusError{codeResourceExhausted, err.Error()}
+
+	case errors.Is(err, domain.ErrExchangeUnavailable),
+		errors.Is(err, domain.ErrDatabaseConnection):
+		return &statusError{codeUnavailable, err.Error()}
+
+	case errors.Is(err, domain.ErrInvalidResponse):
+		return &statusError{codeUnavailable, "invalid response from exchange"}
+
+	default:
+		return &statusError{codeInternal, "internal server error"}
+	}
+}
+
+// encodeStatusMessage percent-encodes a grpc-message trailer value
+func encodeStatusMessage(msg string) string {
+	var b strings.Builder
+	for i := 0; i < len(msg); i++ {
+		c := msg[i]
+		if c < 0x20 || c > 0x7e || c == '%' {
+			fmt.Fprintf(&b, "%%%02X", c)
+			continue
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+// formatCode renders a status code for the grpc-status trailer
+func formatCode(c code) string {
+	return strconv.Itoa(int(c))
+}
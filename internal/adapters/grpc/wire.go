@@ -0,0 +1,145 @@
+package grpc
+
+import (
+	"encoding/binary"
+	"errors"
+	"time"
+)
+
+// Protobuf wire types
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+// errMalformed is returned for a request message that is not valid protobuf
+var errMalformed = errors.New("malformed protobuf message")
+
+// field is a decoded protobuf field. Varint fields carry their value in
+// varint, length-delimited fields in bytes.
+type field struct {
+	num    int
+	typ    int
+	varint uint64
+	bytes  []byte
+}
+
+// decodeMessage calls fn for each field of a protobuf message in order.
+// Fixed-width fields are skipped, as no request message has any.
+func decodeMessage(data []byte, fn func(f field) error) error {
+	for len(data) > 0 {
+		tag, n := binary.Uvarint(data)
+		if n <= 0 {
+			return errMalformed
+		}
+		data = data[n:]
+
+		f := field{num: int(tag >> 3), typ: int(tag & 7)}
+		if f.num == 0 {
+			return errMalformed
+		}
+
+		switch f.typ {
+		case wireVarint:
+			if f.varint, n = binary.Uvarint(data); n <= 0 {
+				return errMalformed
+			}
+			data = data[n:]
+		case wireBytes:
+			size, n := binary.Uvarint(data)
+			if n <= 0 || size > uint64(len(data)-n) {
+				return errMalformed
+			}
+			f.bytes = data[n : n+int(size)]
+			data = data[n+int(size):]
+		case wireFixed64:
+			if len(data) < 8 {
+				return errMalformed
+			}
+			data = data[8:]
+			continue
+		case wireFixed32:
+			if len(data) < 4 {
+				return errMalformed
+			}
+			data = data[4:]
+			continue
+		default:
+			return errMalformed
+		}
+
+		if err := fn(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// decodeTimestamp decodes a google.protobuf.Timestamp
+func decodeTimestamp(data []byte) (time.Time, error) {
+	var seconds, nanos int64
+	err := decodeMessage(data, func(f field) error {
+		switch {
+		case f.num == 1 && f.typ == wireVarint:
+			seconds = int64(f.varint)
+		case f.num == 2 && f.typ == wireVarint:
+			nanos = int64(int32(f.varint))
+		}
+		return nil
+	})
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(seconds, nanos).UTC(), nil
+}
+
+func appendTag(b []byte, num, typ int) []byte {
+	return binary.AppendUvarint(b, uint64(num)<<3|uint64(typ))
+}
+
+// appendString appends a string field, leaving out the empty default
+func appendString(b []byte, num int, s string) []byte {
+	if s == "" {
+		return b
+	}
+	return appendBytes(b, num, []byte(s))
+}
+
+// appendBytes appends a length-delimited field, e.g. an embedded message
+func appendBytes(b []byte, num int, data []byte) []byte {
+	b = appendTag(b, num, wireBytes)
+	b = binary.AppendUvarint(b, uint64(len(data)))
+	return append(b, data...)
+}
+
+// appendInt appends an int64 or int32 field, leaving out the zero default
+func appendInt(b []byte, num int, v int64) []byte {
+	if v == 0 {
+		return b
+	}
+	b = appendTag(b, num, wireVarint)
+	return binary.AppendUvarint(b, uint64(v))
+}
+
+// appendBool appends a bool field, leaving out the false default
+func appendBool(b []byte, num int, v bool) []byte {
+	if !v {
+		return b
+	}
+	b = appendTag(b, num, wireVarint)
+	return append(b, 1)
+}
+
+// appendTimestamp appends a google.protobuf.Timestamp field, leaving out
+// the zero time
+func appendTimestamp(b []byte, num int, t time.Time) []byte {
+	if t.IsZero() {
+		return b
+	}
+	var ts []byte
+	ts = appendInt(ts, 1, t.Unix())
+	ts = appendInt(ts, 2, int64(t.Nanosecond()))
+	return appendBytes(b, num, ts)
+}
@@ -0,0 +1,194 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+)
+
+// WithAlertBacktest enables the /alerts/backtest endpoint backed by the
+// given backtest service
+func WithAlertBacktest(backtests ports.AlertBacktestService) HandlerOption {
+	return func(h *Handler) {
+		h.backtests = backtests
+	}
+}
+
+// WithAlerts enables the /alerts endpoints backed by the given alert service
+func WithAlerts(alerts ports.AlertService) HandlerOption {
+	return func(h *Handler) {
+		h.alerts = alerts
+	}
+}
+
+// AlertsResponse represents the registered alerts
+type AlertsResponse struct {
+	Alerts []*domain.Alert `json:"alerts"`
+}
+
+// ListAlerts returns every alert with its latest firing and delivery state
+func (h *Handler) ListAlerts(w http.ResponseWriter, r *http.Request) {
+	alerts, err := h.alerts.ListAlerts(r.Context())
+	if err != nil {
+		handleDomainError(w, err)
+		return
+	}
+
+	if alerts == nil {
+		alerts = []*domain.Alert{}
+	}
+
+	respond(w, r, http.StatusOK, AlertsResponse{Alerts: alerts})
+}
+
+// CreateAlert registers the alert rule in the body, which also carries the
+// webhook_url its firings are posted to
+func (h *Handler) CreateAlert(w http.ResponseWriter, r *http.Request) {
+	var body json.RawMessage
+	if err := decodeRequest(w, r, maxRequestBodyBytes, &body); err != nil {
+		respondInvalidRequest(w, err)
+		return
+	}
+
+	// The rule decodes itself, so the webhook is read separately
+	var rule domain.AlertRule
+	var req struct {
+		WebhookURL string `json:"webhook_url" validate:"required,max=2048"`
+	}
+	if err := json.Unmarshal(body, &rule); err != nil {
+		if errors.Is(err, domain.ErrInvalidAlertRule) {
+			respondErrorWithCode(w, http.StatusBadRequest, err.Error(), "INVALID_ALERT_RULE")
+			return
+		}
+		respondInvalidRequest(w, err)
+		return
+	}
+	if err := json.Unmarshal(body, &req); err != nil {
+		respondInvalidRequest(w, err)
+		return
+	}
+	if err := validateRequest(&req); err != nil {
+		respondInvalidRequest(w, err)
+		return
+	}
+	rule.Symbol = h.symbols.Normalize(rule.Symbol)
+
+	alert, err := h.alerts.CreateAlert(r.Context(), rule, req.WebhookURL)
+	if errors.Is(err, domain.ErrInvalidAlertRule) {
+		respondErrorWithCode(w, http.StatusBadRequest, err.Error(), "INVALID_ALERT_RULE")
+		return
+	}
+	if err != nil {
+		handleDomainError(w, err)
+		return
+	}
+
+	respond(w, r, http.StatusCreated, alert)
+}
+
+// WatchRequest represents the request body for registering a watch; ttl is
+// a duration such as "6h"
+type WatchRequest struct {
+	Symbol     string          `json:"symbol" validate:"required"`
+	Price      decimal.Decimal `json:"price" validate:"required"`
+	WebhookURL string          `json:"webhook_url" validate:"required,max=2048"`
+	TTL        string          `json:"ttl,omitempty"`
+}
+
+// CreateWatch registers a one-shot alert that calls the webhook once when
+// the symbol's price crosses the given price
+func (h *Handler) CreateWatch(w http.ResponseWriter, r *http.Request) {
+	var req WatchRequest
+	if err := decodeRequest(w, r, maxRequestBodyBytes, &req); err != nil {
+		respondInvalidRequest(w, err)
+		return
+	}
+
+	var ttl time.Duration
+	if req.TTL != "" {
+		d, err := time.ParseDuration(req.TTL)
+		if err != nil {
+			respondErrorWithCode(w, http.StatusBadRequest, "ttl must be a duration such as 6h", "INVALID_ALERT_RULE")
+			return
+		}
+		ttl = d
+	}
+
+	watch, err := h.alerts.CreateWatch(r.Context(), h.symbols.Normalize(req.Symbol), req.Price, req.WebhookURL, ttl)
+	if errors.Is(err, domain.ErrInvalidAlertRule) {
+		respondErrorWithCode(w, http.StatusBadRequest, err.Error(), "INVALID_ALERT_RULE")
+		return
+	}
+	if err != nil {
+		handleDomainError(w, err)
+		return
+	}
+
+	respond(w, r, http.StatusCreated, watch)
+}
+
+// DeleteAlert removes an alert
+func (h *Handler) DeleteAlert(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid alert id")
+		return
+	}
+
+	if err := h.alerts.DeleteAlert(r.Context(), id); err != nil {
+		handleDomainError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// BacktestAlert replays the alert rule in the body over ?from= to ?to=
+// (default now) and returns when it would have fired
+func (h *Handler) BacktestAlert(w http.ResponseWriter, r *http.Request) {
+	from, ok, err := parseTimeParam(r, "from")
+	if err != nil || !ok {
+		respondErrorWithCode(w, http.StatusBadRequest, "from parameter is required as an RFC 3339 time", "INVALID_TIME_RANGE")
+		return
+	}
+	to, ok, err := parseTimeParam(r, "to")
+	if err != nil {
+		respondErrorWithCode(w, http.StatusBadRequest, "to must be an RFC 3339 time", "INVALID_TIME_RANGE")
+		return
+	}
+	if !ok {
+		to = time.Now().UTC()
+	}
+
+	var rule domain.AlertRule
+	if err := decodeRequest(w, r, maxRequestBodyBytes, &rule); err != nil {
+		if errors.Is(err, domain.ErrInvalidAlertRule) {
+			respondErrorWithCode(w, http.StatusBadRequest, err.Error(), "INVALID_ALERT_RULE")
+			return
+		}
+		respondInvalidRequest(w, err)
+		return
+	}
+
+	backtest, err := h.backtests.Backtest(r.Context(), rule, from, to)
+	switch {
+	case errors.Is(err, domain.ErrInvalidAlertRule):
+		respondErrorWithCode(w, http.StatusBadRequest, err.Error(), "INVALID_ALERT_RULE")
+		return
+	case errors.Is(err, domain.ErrInvalidTimeRange):
+		respondErrorWithCode(w, http.StatusBadRequest, err.Error(), "INVALID_TIME_RANGE")
+		return
+	case err != nil:
+		handleDomainError(w, err)
+		return
+	}
+
+	respond(w, r, http.StatusOK, backtest)
+}
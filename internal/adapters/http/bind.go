@@ -0,0 +1,301 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// maxRequestBodyBytes bounds the size of a JSON request body, unless its
+// handler allows more
+const maxRequestBodyBytes = 1 << 20
+
+// DefaultMaxJobBodyBytes bounds the size of a POST /admin/jobs body when
+// WithJobs is given no limit. Import jobs carry their rows inline, so it is
+// well above maxRequestBodyBytes.
+const DefaultMaxJobBodyBytes = 64 << 20
+
+// FieldError is a request body field that failed validation. Field is its
+// JSON path, e.g. "snapshots[2].symbol".
+type FieldError struct {
+	Field string `json:"field"`
+	Error string `json:"error"`
+}
+
+// ValidationError lists every invalid field of a request body
+type ValidationError []FieldError
+
+func (e ValidationError) Error() string {
+	msgs := make([]string, len(e))
+	for i, f := range e {
+		msgs[i] = f.Field + ": " + f.Error
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// errMalformedBody is a request body that is not a single JSON value
+var errMalformedBody = errors.New("malformed JSON")
+
+// decodeRequest decodes the JSON body of r, of at most limit bytes, into v
+// and validates the struct tags of v with validateRequest. A field of the
+// wrong JSON type is reported as a ValidationError like a failed rule;
+// errors returned by the UnmarshalJSON of a field's type are passed through.
+func decodeRequest(w http.ResponseWriter, r *http.Request, limit int64, v interface{}) error {
+	r.Body = http.MaxBytesReader(w, r.Body, limit)
+
+	dec := json.NewDecoder(r.Body)
+	if err := dec.Decode(v); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		var syntaxErr *json.SyntaxError
+		var maxErr *http.MaxBytesError
+		switch {
+		case errors.As(err, &typeErr) && typeErr.Field != "":
+			return ValidationError{{Field: typeErr.Field, Error: "must be a JSON " + jsonKind(typeErr.Type)}}
+		case errors.As(err, &maxErr):
+			return fmt.Errorf("body exceeds %d bytes", maxErr.Limit)
+		case errors.As(err, &syntaxErr), errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF), errors.As(err, &typeErr):
+			return errMalformedBody
+		}
+		return err
+	}
+	if dec.More() {
+		return errMalformedBody
+	}
+
+	return validateRequest(v)
+}
+
+// respondInvalidRequest sends a 400 INVALID_REQUEST for a body rejected by
+// decodeRequest, listing the invalid fields of a ValidationError
+func respondInvalidRequest(w http.ResponseWriter, err error) {
+	resp := ErrorResponse{Error: "invalid request body", Code: "INVALID_REQUEST"}
+
+	var invalid ValidationError
+	if errors.As(err, &invalid) {
+		resp.Fields = invalid
+	} else {
+		resp.Details = err.Error()
+	}
+	respondJSON(w, http.StatusBadRequest, resp)
+}
+
+// validateRequest checks the fields of the struct v points to against their
+// `validate` and `pattern` tags, descending into nested structs and slices
+// of structs, and returns a ValidationError listing every field that fails.
+//
+// A `validate` tag holds comma-separated rules:
+//
+//	required   the value is not its zero value, or empty
+//	min=N      a string or slice has at least N elements, a number is at least N
+//	max=N      a string or slice has at most N elements, a number is at most N
+//	oneof=a b  a string is one of the space-separated values
+//
+// A `pattern` tag holds a regular expression a non-empty string must match.
+// Rules other than required are skipped for empty values. A malformed tag
+// panics, like a malformed regular expression in regexp.MustCompile.
+func validateRequest(v interface{}) error {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+
+	var invalid ValidationError
+	validateValue(rv, "", &invalid)
+	if len(invalid) > 0 {
+		return invalid
+	}
+	return nil
+}
+
+func validateValue(rv reflect.Value, path string, invalid *ValidationError) {
+	switch rv.Kind() {
+	case reflect.Pointer:
+		if !rv.IsNil() {
+			validateValue(rv.Elem(), path, invalid)
+		}
+	case reflect.Slice, reflect.Array:
+		if elem := rv.Type().Elem().Kind(); elem != reflect.Struct && elem != reflect.Pointer {
+			return
+		}
+		for i := 0; i < rv.Len(); i++ {
+			validateValue(rv.Index(i), fmt.Sprintf("%s[%d]", path, i), invalid)
+		}
+	case reflect.Struct:
+		rt := rv.Type()
+		for i := 0; i < rt.NumField(); i++ {
+			field := rt.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			name := jsonName(field)
+			if name == "" {
+				continue
+			}
+			if path != "" {
+				name = path + "." + name
+			}
+
+			value := rv.Field(i)
+			if msg := checkField(field, value); msg != "" {
+				*invalid = append(*invalid, FieldError{Field: name, Error: msg})
+				continue
+			}
+			validateValue(value, name, invalid)
+		}
+	}
+}
+
+// checkField returns why value breaks a rule of field's tags, or ""
+func checkField(field reflect.StructField, value reflect.Value) string {
+	rules := field.Tag.Get("validate")
+	pattern := field.Tag.Get("pattern")
+	if rules == "" && pattern == "" {
+		return ""
+	}
+
+	empty := isEmpty(value)
+	for _, rule := range strings.Split(rules, ",") {
+		name, arg, _ := strings.Cut(strings.TrimSpace(rule), "=")
+		switch name {
+		case "":
+		case "required":
+			if empty {
+				return "is required"
+			}
+		case "min", "max":
+			if empty {
+				continue
+			}
+			limit, err := strconv.ParseFloat(arg, 64)
+			if err != nil {
+				panic(fmt.Sprintf("http: invalid %s rule on %s: %q", name, field.Name, rule))
+			}
+			if msg := checkBound(name, limit, value); msg != "" {
+				return msg
+			}
+		case "oneof":
+			if empty {
+				continue
+			}
+			allowed := strings.Fields(arg)
+			if !slices.Contains(allowed, value.String()) {
+				return "must be one of " + strings.Join(allowed, ", ")
+			}
+		default:
+			panic(fmt.Sprintf("http: unknown validation rule on %s: %q", field.Name, rule))
+		}
+	}
+
+	if pattern != "" && !empty && value.Kind() == reflect.String {
+		if !compiledPattern(pattern).MatchString(value.String()) {
+			return "must match " + pattern
+		}
+	}
+	return ""
+}
+
+// checkBound checks a min or max rule: a length for strings and slices, a
+// value for numbers
+func checkBound(rule string, limit float64, value reflect.Value) string {
+	var n float64
+	unit := ""
+	switch value.Kind() {
+	case reflect.String:
+		n, unit = float64(utf8.RuneCountInString(value.String())), " characters"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		n, unit = float64(value.Len()), " items"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n = float64(value.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n = float64(value.Uint())
+	case reflect.Float32, reflect.Float64:
+		n = value.Float()
+	default:
+		return ""
+	}
+
+	bound := strconv.FormatFloat(limit, 'f', -1, 64)
+	if rule == "min" && n < limit {
+		if unit != "" {
+			return "must have at least " + bound + unit
+		}
+		return "must be at least " + bound
+	}
+	if rule == "max" && n > limit {
+		if unit != "" {
+			return "must have at most " + bound + unit
+		}
+		return "must be at most " + bound
+	}
+	return ""
+}
+
+// isEmpty reports whether a value counts as missing for required: nil, a
+// zero value, an empty string, slice or map, or a type whose IsZero says so
+// (time.Time, decimal.Decimal)
+func isEmpty(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		return value.Len() == 0
+	case reflect.Pointer, reflect.Interface:
+		return value.IsNil()
+	}
+	if z, ok := value.Interface().(interface{ IsZero() bool }); ok {
+		return z.IsZero()
+	}
+	return value.IsZero()
+}
+
+// jsonName returns the name a field is decoded from, or "" for a field
+// json skips
+func jsonName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	switch name {
+	case "-":
+		return ""
+	case "":
+		return field.Name
+	}
+	return name
+}
+
+// jsonKind names the JSON type a Go type is decoded from
+func jsonKind(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	}
+	return "object"
+}
+
+// patterns caches the compiled expressions of pattern tags
+var patterns sync.Map
+
+func compiledPattern(expr string) *regexp.Regexp {
+	if re, ok := patterns.Load(expr); ok {
+		return re.(*regexp.Regexp)
+	}
+	re := regexp.MustCompile(expr)
+	patterns.Store(expr, re)
+	return re
+}
@@ -0,0 +1,125 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+)
+
+const (
+	minBucketStep = time.Second
+	maxBuckets    = maxSamplePoints
+
+	// minRollupRange is the shortest bucketed range read from candles
+	// rather than raw snapshots
+	minRollupRange = 24 * time.Hour
+)
+
+// historyBucketing is a request to average a history into fixed buckets
+type historyBucketing struct {
+	step time.Duration
+	fill domain.FillMode
+}
+
+// parseBucketing reads the optional ?step= and ?fill= parameters, e.g.
+// step=5m&fill=linear. It returns nil without step; fill defaults to none.
+// Bucketing needs a from, since buckets are aligned to it, and at most
+// maxBuckets of them.
+func parseBucketing(r *http.Request, rng historyRange) (*historyBucketing, error) {
+	q := r.URL.Query()
+	stepParam := q.Get("step")
+	fill := domain.FillMode(q.Get("fill"))
+
+	if stepParam == "" {
+		if fill != "" {
+			return nil, fmt.Errorf("fill requires step")
+		}
+		return nil, nil
+	}
+
+	step, err := time.ParseDuration(stepParam)
+	if err != nil || step < minBucketStep {
+		return nil, fmt.Errorf("step must be a duration of at least %s", minBucketStep)
+	}
+
+	if fill == "" {
+		fill = domain.FillNone
+	}
+	if !fill.Valid() {
+		return nil, fmt.Errorf("unknown fill %q", fill)
+	}
+
+	if rng.from.IsZero() {
+		return nil, fmt.Errorf("step requires from")
+	}
+	if n := domain.BucketCount(rng.from, rng.to, step); n == 0 || n > maxBuckets {
+		return nil, fmt.Errorf("step must split the range into 1 to %d buckets", maxBuckets)
+	}
+
+	return &historyBucketing{step: step, fill: fill}, nil
+}
+
+// apply averages a history into buckets and fills its gaps, returning the
+// buckets and a description of them
+func (b *historyBucketing) apply(history []*domain.PriceSnapshot, rng historyRange) ([]domain.PriceBucket, *domain.HistoryBuckets) {
+	buckets := domain.BucketAverages(history, rng.from, rng.to, b.step)
+
+	desc := &domain.HistoryBuckets{Step: b.step.String(), Fill: b.fill}
+	for _, bucket := range buckets {
+		if bucket.Count == 0 {
+			desc.Gaps++
+		}
+	}
+
+	buckets = domain.FillGaps(buckets, b.fill)
+	for _, bucket := range buckets {
+		if bucket.Filled {
+			desc.Filled++
+		}
+	}
+
+	return buckets, desc
+}
+
+// rollupInterval returns the coarsest candle interval a bucketed history
+// can be read from instead of raw snapshots: one that divides the step and
+// that from is aligned to. Only ranges of at least minRollupRange use
+// candles, and only when the candles endpoint is enabled.
+func (h *Handler) rollupInterval(b *historyBucketing, rng historyRange) (domain.CandleInterval, bool) {
+	if h.candles == nil || b == nil || rng.to.Sub(rng.from) < minRollupRange {
+		return "", false
+	}
+
+	for i := len(domain.CandleIntervals) - 1; i >= 0; i-- {
+		interval := domain.CandleIntervals[i]
+		width := interval.Duration()
+		if b.step%width == 0 && rng.from.Equal(rng.from.Truncate(width)) &&
+			domain.BucketCount(rng.from, rng.to, width) <= maxCandles {
+			return interval, true
+		}
+	}
+	return "", false
+}
+
+// rollupHistory reads a symbol's candles of interval in the range as a
+// newest-first history of their closes, each timestamped with its candle's
+// open, for bucketing to average
+func (h *Handler) rollupHistory(ctx context.Context, symbol string, interval domain.CandleInterval, rng historyRange) ([]*domain.PriceSnapshot, error) {
+	candles, err := h.candles.GetCandles(ctx, symbol, interval, rng.from, rng.to, domain.FillNone)
+	if err != nil {
+		return nil, err
+	}
+
+	history := make([]*domain.PriceSnapshot, len(candles))
+	for i, c := range candles {
+		history[len(candles)-1-i] = &domain.PriceSnapshot{
+			Symbol:    c.Symbol,
+			Price:     c.Close,
+			Timestamp: c.OpenTime,
+		}
+	}
+	return history, nil
+}
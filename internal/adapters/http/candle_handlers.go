@@ -0,0 +1,159 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+)
+
+const (
+	defaultCandleInterval = domain.CandleInterval1h
+	defaultCandles        = 100
+	maxCandles            = maxBuckets
+)
+
+// WithCandles enables the /candles endpoint backed by the given candle service
+func WithCandles(candles ports.CandleService) HandlerOption {
+	return func(h *Handler) {
+		h.candles = candles
+	}
+}
+
+// CandlesResponse represents a symbol's candles, oldest first
+type CandlesResponse struct {
+	Symbol   string                `json:"symbol"`
+	Interval domain.CandleInterval `json:"interval"`
+	Fill     domain.FillMode       `json:"fill"`
+	Candles  []CandleItem          `json:"candles"`
+}
+
+// CandleItem represents one candle. Samples is the number of snapshots it
+// was built from; a filled candle had none and repeats the previous close.
+type CandleItem struct {
+	OpenTime  string `json:"open_time"`
+	CloseTime string `json:"close_time"`
+	Open      string `json:"open"`
+	High      string `json:"high"`
+	Low       string `json:"low"`
+	Close     string `json:"close"`
+	Samples   int    `json:"samples"`
+	Filled    bool   `json:"filled,omitempty"`
+}
+
+func (c CandlesResponse) columns() []string {
+	return []string{"open_time", "close_time", "open", "high", "low", "close", "samples", "filled"}
+}
+
+func (c CandlesResponse) records() [][]string {
+	records := make([][]string, len(c.Candles))
+	for i, item := range c.Candles {
+		records[i] = []string{
+			item.OpenTime,
+			item.CloseTime,
+			item.Open,
+			item.High,
+			item.Low,
+			item.Close,
+			strconv.Itoa(item.Samples),
+			strconv.FormatBool(item.Filled),
+		}
+	}
+	return records
+}
+
+func (c CandlesResponse) items() []interface{} {
+	items := make([]interface{}, len(c.Candles))
+	for i, item := range c.Candles {
+		items[i] = item
+	}
+	return items
+}
+
+// GetCandles returns OHLC candles for a symbol, e.g.
+// /candles?symbol=BTCUSDT&interval=5m&from=...&to=...&fill=prev. The range
+// defaults to the last 100 candles of the interval.
+func (h *Handler) GetCandles(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	symbol := h.symbols.Normalize(q.Get("symbol"))
+	if symbol == "" {
+		respondError(w, http.StatusBadRequest, "symbol parameter is required")
+		return
+	}
+
+	interval := domain.CandleInterval(q.Get("interval"))
+	if interval == "" {
+		interval = defaultCandleInterval
+	}
+	if !interval.Valid() {
+		respondErrorWithCode(w, http.StatusBadRequest, fmt.Sprintf("unknown interval %q", interval), "INVALID_INTERVAL")
+		return
+	}
+
+	fill := domain.FillMode(q.Get("fill"))
+	if fill == "" {
+		fill = domain.FillNone
+	}
+	if !fill.Valid() {
+		respondErrorWithCode(w, http.StatusBadRequest, fmt.Sprintf("unknown fill %q", fill), "INVALID_FILL")
+		return
+	}
+
+	to, hasTo, err := parseTimeParam(r, "to")
+	if err != nil {
+		handleDomainError(w, err)
+		return
+	}
+	if !hasTo {
+		to = time.Now().UTC()
+	}
+	width := interval.Duration()
+	from, hasFrom, err := parseTimeParam(r, "from")
+	if err != nil {
+		handleDomainError(w, err)
+		return
+	}
+	if !hasFrom {
+		from = to.Add(-defaultCandles * width)
+	}
+	from = from.UTC().Truncate(width)
+	if !from.Before(to) {
+		handleDomainError(w, domain.ErrInvalidTimeRange)
+		return
+	}
+	if domain.BucketCount(from, to, width) > maxCandles {
+		respondErrorWithCode(w, http.StatusBadRequest,
+			fmt.Sprintf("range spans more than %d %s candles", maxCandles, interval), "INVALID_TIME_RANGE")
+		return
+	}
+
+	candles, err := h.candles.GetCandles(r.Context(), symbol, interval, from, to, fill)
+	if err != nil {
+		handleDomainError(w, err)
+		return
+	}
+
+	resp := CandlesResponse{
+		Symbol:   symbol,
+		Interval: interval,
+		Fill:     fill,
+		Candles:  make([]CandleItem, len(candles)),
+	}
+	for i, c := range candles {
+		resp.Candles[i] = CandleItem{
+			OpenTime:  c.OpenTime.UTC().Format(time.RFC3339),
+			CloseTime: c.CloseTime.UTC().Format(time.RFC3339),
+			Open:      c.Open.String(),
+			High:      c.High.String(),
+			Low:       c.Low.String(),
+			Close:     c.Close.String(),
+			Samples:   c.Samples,
+			Filled:    c.Filled,
+		}
+	}
+
+	respond(w, r, http.StatusOK, resp)
+}
@@ -0,0 +1,38 @@
+package http
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+)
+
+// WithFaultInjector fails a share of API requests with server errors, for
+// testing clients and alerting against an unreliable service
+func WithFaultInjector(faults ports.FaultInjector) HandlerOption {
+	return func(h *Handler) {
+		h.faults = faults
+	}
+}
+
+// FaultInjectionMiddleware responds with the server error chosen by the
+// injector instead of serving the request. Health probes are never failed,
+// so orchestrators keep the instance running.
+func FaultInjectionMiddleware(faults ports.FaultInjector, logger *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/health" || r.URL.Path == "/ready" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if status := faults.ServerError(); status != 0 {
+				logger.Debug("injecting server error", "path", r.URL.Path, "status", status)
+				respondErrorWithCode(w, status, "injected fault", "CHAOS_INJECTED")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
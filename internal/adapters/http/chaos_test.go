@@ -0,0 +1,57 @@
+package http_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	httpAdapter "github.com/prxgr4mmer/price-snapshot-service/internal/adapters/http"
+)
+
+// mockFaultInjector always injects the same status
+type mockFaultInjector struct {
+	status int
+}
+
+func (m *mockFaultInjector) ServerError() int {
+	return m.status
+}
+
+func TestFaultInjectionMiddleware(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	request := func(handler http.Handler, path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec
+	}
+
+	t.Run("responds with the injected error", func(t *testing.T) {
+		handler := httpAdapter.FaultInjectionMiddleware(&mockFaultInjector{status: http.StatusServiceUnavailable}, newTestLogger())(next)
+
+		rec := request(handler, "/prices")
+
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+		var resp httpAdapter.ErrorResponse
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+		assert.Equal(t, "CHAOS_INJECTED", resp.Code)
+	})
+
+	t.Run("serves requests without a fault", func(t *testing.T) {
+		handler := httpAdapter.FaultInjectionMiddleware(&mockFaultInjector{}, newTestLogger())(next)
+
+		assert.Equal(t, http.StatusOK, request(handler, "/prices").Code)
+	})
+
+	t.Run("never fails health probes", func(t *testing.T) {
+		handler := httpAdapter.FaultInjectionMiddleware(&mockFaultInjector{status: http.StatusInternalServerError}, newTestLogger())(next)
+
+		assert.Equal(t, http.StatusOK, request(handler, "/health").Code)
+		assert.Equal(t, http.StatusOK, request(handler, "/ready").Code)
+	})
+}
@@ -0,0 +1,76 @@
+package http_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	httpAdapter "github.com/prxgr4mmer/price-snapshot-service/internal/adapters/http"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientIPMiddleware(t *testing.T) {
+	trusted, err := httpAdapter.ParseTrustedProxies([]string{"10.0.0.0/8", "192.168.1.5"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		headers    map[string]string
+		want       string
+	}{
+		{
+			name:       "untrusted peer ignores forwarding headers",
+			remoteAddr: "203.0.113.7:5555",
+			headers:    map[string]string{"X-Forwarded-For": "1.2.3.4"},
+			want:       "203.0.113.7",
+		},
+		{
+			name:       "trusted peer uses forwarded client",
+			remoteAddr: "10.1.2.3:5555",
+			headers:    map[string]string{"X-Forwarded-For": "198.51.100.9"},
+			want:       "198.51.100.9",
+		},
+		{
+			name:       "skips trusted hops from the right",
+			remoteAddr: "10.1.2.3:5555",
+			headers:    map[string]string{"X-Forwarded-For": "1.2.3.4, 198.51.100.9, 192.168.1.5"},
+			want:       "198.51.100.9",
+		},
+		{
+			name:       "falls back to X-Real-IP",
+			remoteAddr: "192.168.1.5:5555",
+			headers:    map[string]string{"X-Real-IP": "198.51.100.20"},
+			want:       "198.51.100.20",
+		},
+		{
+			name:       "trusted peer without headers",
+			remoteAddr: "10.1.2.3:5555",
+			want:       "10.1.2.3",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			handler := httpAdapter.ClientIPMiddleware(trusted)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = httpAdapter.ClientIP(r)
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/health", nil)
+			req.RemoteAddr = tt.remoteAddr
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseTrustedProxies_Invalid(t *testing.T) {
+	_, err := httpAdapter.ParseTrustedProxies([]string{"not-an-ip"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,209 @@
+package http
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/vmihailenco/msgpack/v5"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/adapters/prometheus"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+)
+
+// Content negotiation for successful responses. Handlers hand a payload and a
+// status to respond(), which picks an encoder from ?format= or the Accept header.
+// Error responses are always JSON.
+
+// errNotTabular is returned by row-based encoders for payloads without rows
+var errNotTabular = errors.New("response cannot be represented as rows")
+
+// errNotMetrics is returned by the Prometheus encoder for anything but metrics
+var errNotMetrics = errors.New("response is not a metrics payload")
+
+// tabular is implemented by payloads that can be rendered as CSV or NDJSON
+type tabular interface {
+	// columns returns the CSV header
+	columns() []string
+
+	// records returns one CSV record per row, aligned with columns
+	records() [][]string
+
+	// items returns one value per row for line-delimited encodings
+	items() []interface{}
+}
+
+// Encoder writes a response payload in a specific media type
+type Encoder interface {
+	// ContentType returns the media type written to the Content-Type header
+	ContentType() string
+
+	// Encode writes data to w
+	Encode(w io.Writer, data interface{}) error
+}
+
+type jsonEncoder struct{}
+
+func (jsonEncoder) ContentType() string { return "application/json" }
+
+func (jsonEncoder) Encode(w io.Writer, data interface{}) error {
+	return json.NewEncoder(w).Encode(data)
+}
+
+type ndjsonEncoder struct{}
+
+func (ndjsonEncoder) ContentType() string { return "application/x-ndjson" }
+
+func (ndjsonEncoder) Encode(w io.Writer, data interface{}) error {
+	enc := json.NewEncoder(w)
+
+	t, ok := data.(tabular)
+	if !ok {
+		return enc.Encode(data)
+	}
+
+	for _, item := range t.items() {
+		if err := enc.Encode(item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type csvEncoder struct{}
+
+func (csvEncoder) ContentType() string { return "text/csv" }
+
+func (csvEncoder) Encode(w io.Writer, data interface{}) error {
+	t, ok := data.(tabular)
+	if !ok {
+		return errNotTabular
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(t.columns()); err != nil {
+		return err
+	}
+	if err := cw.WriteAll(t.records()); err != nil {
+		return err
+	}
+	return cw.Error()
+}
+
+type msgpackEncoder struct{}
+
+func (msgpackEncoder) ContentType() string { return "application/msgpack" }
+
+func (msgpackEncoder) Encode(w io.Writer, data interface{}) error {
+	enc := msgpack.NewEncoder(w)
+	enc.SetCustomStructTag("json")
+	return enc.Encode(data)
+}
+
+type prometheusEncoder struct{}
+
+func (prometheusEncoder) ContentType() string { return prometheus.ContentType }
+
+func (prometheusEncoder) Encode(w io.Writer, data interface{}) error {
+	m, ok := data.(*domain.Metrics)
+	if !ok {
+		return errNotMetrics
+	}
+	return prometheus.WriteMetrics(w, m)
+}
+
+type openMetricsEncoder struct{}
+
+func (openMetricsEncoder) ContentType() string { return prometheus.OpenMetricsContentType }
+
+func (openMetricsEncoder) Encode(w io.Writer, data interface{}) error {
+	m, ok := data.(*domain.Metrics)
+	if !ok {
+		return errNotMetrics
+	}
+	return prometheus.WriteOpenMetrics(w, m)
+}
+
+// encodersByFormat maps ?format= values to encoders
+var encodersByFormat = map[string]Encoder{
+	"json":        jsonEncoder{},
+	"ndjson":      ndjsonEncoder{},
+	"csv":         csvEncoder{},
+	"msgpack":     msgpackEncoder{},
+	"prometheus":  prometheusEncoder{},
+	"openmetrics": openMetricsEncoder{},
+}
+
+// encodersByMediaType maps Accept media types to encoders
+var encodersByMediaType = map[string]Encoder{
+	"application/json":             jsonEncoder{},
+	"application/x-ndjson":         ndjsonEncoder{},
+	"application/ndjson":           ndjsonEncoder{},
+	"text/csv":                     csvEncoder{},
+	"application/msgpack":          msgpackEncoder{},
+	"application/x-msgpack":        msgpackEncoder{},
+	"application/vnd.msgpack":      msgpackEncoder{},
+	"application/openmetrics-text": openMetricsEncoder{},
+}
+
+// negotiateEncoder selects the encoder for a request. An explicit ?format=
+// wins over Accept; an unknown format is an error, while an Accept header
+// with no supported media type falls back to JSON.
+func negotiateEncoder(r *http.Request) (Encoder, bool) {
+	if format := r.URL.Query().Get("format"); format != "" {
+		enc, ok := encodersByFormat[strings.ToLower(format)]
+		return enc, ok
+	}
+
+	for _, mediaType := range parseAccept(r.Header.Get("Accept")) {
+		if enc, ok := encodersByMediaType[mediaType]; ok {
+			return enc, true
+		}
+	}
+
+	return jsonEncoder{}, true
+}
+
+// parseAccept returns the media types of an Accept header ordered by quality
+func parseAccept(header string) []string {
+	type candidate struct {
+		mediaType string
+		quality   float64
+	}
+
+	var candidates []candidate
+	for _, part := range strings.Split(header, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		quality := 1.0
+		if q, ok := params["q"]; ok {
+			if v, err := strconv.ParseFloat(q, 64); err == nil {
+				quality = v
+			}
+		}
+		if quality <= 0 {
+			continue
+		}
+
+		candidates = append(candidates, candidate{mediaType: mediaType, quality: quality})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].quality > candidates[j].quality
+	})
+
+	mediaTypes := make([]string, len(candidates))
+	for i, c := range candidates {
+		mediaTypes[i] = c.mediaType
+	}
+	return mediaTypes
+}
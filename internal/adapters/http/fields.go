@@ -0,0 +1,182 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// priceFields are the per-row fields of price and history responses that
+// can be selected with ?fields=. ts_local is only filled with ?tz=.
+var priceFields = []string{"symbol", "price", "price_num", "ts", "ts_local"}
+
+// latestPriceFields are the fields of latest price responses, which also
+// carry freshness, formatting hints and, with ?include=previous, the move
+// since the previous snapshot
+var latestPriceFields = []string{
+	"symbol", "price", "price_num", "ts", "ts_age_ms", "fetch_latency_ms", "exchange", "inverted_from", "tick_size", "display_decimals",
+	"prev_price", "prev_ts", "delta", "delta_pct",
+}
+
+// previousPriceFields are the latest price fields only filled with
+// ?include=previous
+var previousPriceFields = map[string]bool{"prev_price": true, "prev_ts": true, "delta": true, "delta_pct": true}
+
+// fieldSet is the set of fields selected with ?fields= (sparse fieldsets).
+// A nil set selects every field.
+type fieldSet map[string]bool
+
+// parseFields reads ?fields= as a comma-separated list of allowed names
+func parseFields(r *http.Request, allowed []string) (fieldSet, error) {
+	param := r.URL.Query().Get("fields")
+	if param == "" {
+		return nil, nil
+	}
+
+	valid := make(map[string]bool, len(allowed))
+	for _, name := range allowed {
+		valid[name] = true
+	}
+
+	fields := make(fieldSet)
+	for _, name := range strings.Split(param, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		if !valid[name] {
+			return nil, fmt.Errorf("unknown field %q, expected one of: %s", name, strings.Join(allowed, ", "))
+		}
+		fields[name] = true
+	}
+
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	return fields, nil
+}
+
+// has reports whether the field is selected
+func (f fieldSet) has(name string) bool {
+	return f == nil || f[name]
+}
+
+// columns returns the selected columns in their canonical order
+func (f fieldSet) columns(all []string) []string {
+	if f == nil {
+		return all
+	}
+
+	columns := make([]string, 0, len(f))
+	for _, name := range all {
+		if f[name] {
+			columns = append(columns, name)
+		}
+	}
+	return columns
+}
+
+// priceRecord returns the CSV record for a price row, aligned with columns
+func priceRecord(columns []string, p PriceResponse) []string {
+	record := make([]string, len(columns))
+	for i, column := range columns {
+		switch column {
+		case "symbol":
+			record[i] = p.Symbol
+		case "price":
+			if p.Price != nil {
+				record[i] = fmt.Sprint(p.Price)
+			}
+		case "price_num":
+			record[i] = numberString(p.PriceNum)
+		case "ts":
+			record[i] = p.Timestamp
+		case "ts_local":
+			record[i] = p.TimestampLocal
+		case "filled":
+			record[i] = strconv.FormatBool(p.Filled)
+		case "ts_age_ms":
+			if p.TimestampAgeMs != nil {
+				record[i] = strconv.FormatInt(*p.TimestampAgeMs, 10)
+			}
+		case "fetch_latency_ms":
+			if p.FetchLatencyMs != nil {
+				record[i] = strconv.FormatInt(*p.FetchLatencyMs, 10)
+			}
+		case "exchange":
+			record[i] = p.Exchange
+		case "inverted_from":
+			record[i] = p.InvertedFrom
+		case "tick_size":
+			record[i] = p.TickSize
+		case "display_decimals":
+			if p.DisplayDecimals != nil {
+				record[i] = strconv.Itoa(int(*p.DisplayDecimals))
+			}
+		case "prev_price":
+			if p.PrevPrice != nil {
+				record[i] = fmt.Sprint(p.PrevPrice)
+			}
+		case "prev_ts":
+			record[i] = p.PrevTimestamp
+		case "delta":
+			if p.Delta != nil {
+				record[i] = fmt.Sprint(p.Delta)
+			}
+		case "delta_pct":
+			record[i] = p.DeltaPercent
+		}
+	}
+	return record
+}
+
+// selectPrice clears the fields of a price row that are not selected
+func selectPrice(fields fieldSet, row PriceResponse) PriceResponse {
+	if !fields.has("symbol") {
+		row.Symbol = ""
+	}
+	if !fields.has("price") {
+		row.Price = nil
+	}
+	if !fields.has("price_num") {
+		row.PriceNum = nil
+	}
+	if !fields.has("ts") {
+		row.Timestamp = ""
+	}
+	if !fields.has("ts_local") {
+		row.TimestampLocal = ""
+	}
+	if !fields.has("ts_age_ms") {
+		row.TimestampAgeMs = nil
+	}
+	if !fields.has("fetch_latency_ms") {
+		row.FetchLatencyMs = nil
+	}
+	if !fields.has("exchange") {
+		row.Exchange = ""
+	}
+	if !fields.has("inverted_from") {
+		row.InvertedFrom = ""
+	}
+	if !fields.has("tick_size") {
+		row.TickSize = ""
+	}
+	if !fields.has("display_decimals") {
+		row.DisplayDecimals = nil
+	}
+	if !fields.has("prev_price") {
+		row.PrevPrice = nil
+	}
+	if !fields.has("prev_ts") {
+		row.PrevTimestamp = ""
+	}
+	if !fields.has("delta") {
+		row.Delta = nil
+	}
+	if !fields.has("delta_pct") {
+		row.DeltaPercent = ""
+	}
+	return row
+}
@@ -3,23 +3,106 @@ package http
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"log/slog"
 	"net/http"
+	"net/netip"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/shopspring/decimal"
+
 	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
 	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
 )
 
 // Handler contains all HTTP handlers
 type Handler struct {
-	symbolSvc   ports.SymbolService
-	snapshotSvc ports.SnapshotService
-	metricsSvc  ports.MetricsService
-	exchange    ports.ExchangeClient
-	logger      *slog.Logger
+	symbolSvc       ports.SymbolService
+	snapshotSvc     ports.SnapshotService
+	metricsSvc      ports.MetricsService
+	exchange        ports.ExchangeClient
+	health          ports.HealthService
+	warmup          ports.WarmupService
+	failover        ports.FailoverMonitor
+	jobs            ports.JobService
+	maxJobBodyBytes int64
+	ticker          ports.TickerService
+	candles         ports.CandleService
+	retention       ports.RetentionService
+	precision       ports.PrecisionService
+	rateLimiter     ports.RateLimiter
+	rateLimitKeys   []string
+	usageKeys       []string
+	requests        ports.HTTPRequestRecorder
+	faults          ports.FaultInjector
+	ingest          ports.IngestService
+	backtests       ports.AlertBacktestService
+	alerts          ports.AlertService
+	migrations      ports.MigrationStatusProvider
+	poller          ports.SymbolPoller
+	usage           ports.UsageService
+	symbols         *domain.SymbolNormalizer
+	prices          priceFormatter
+	logger          *slog.Logger
+	ui              bool
+
+	trustedProxies []netip.Prefix
+	ingestKeys     []string
+
+	historyCacheGrace time.Duration
+}
+
+// HandlerOption configures the handler
+type HandlerOption func(*Handler)
+
+// WithPriceFormat sets how prices are serialized in responses
+func WithPriceFormat(format PriceFormat, decimals int) HandlerOption {
+	return func(h *Handler) {
+		if format != "" {
+			h.prices.format = format
+		}
+		h.prices.decimals = int32(decimals)
+	}
+}
+
+// WithPrecision adds exchange tick sizes and display decimals to prices
+func WithPrecision(precision ports.PrecisionService) HandlerOption {
+	return func(h *Handler) {
+		h.precision = precision
+	}
+}
+
+// WithHealth reports dependency health on /health
+func WithHealth(health ports.HealthService) HandlerOption {
+	return func(h *Handler) {
+		h.health = health
+	}
+}
+
+// WithWarmup gates readiness on the given warm-up service
+func WithWarmup(warmup ports.WarmupService) HandlerOption {
+	return func(h *Handler) {
+		h.warmup = warmup
+	}
+}
+
+// WithDatabaseFailover reports readiness as degraded while the database
+// is failing over to a new primary
+func WithDatabaseFailover(failover ports.FailoverMonitor) HandlerOption {
+	return func(h *Handler) {
+		h.failover = failover
+	}
+}
+
+// WithSymbolNormalizer resolves symbol aliases in requests, so responses
+// name the canonical symbol
+func WithSymbolNormalizer(symbols *domain.SymbolNormalizer) HandlerOption {
+	return func(h *Handler) {
+		h.symbols = symbols
+	}
 }
 
 // NewHandler creates a new handler
@@ -29,43 +112,92 @@ func NewHandler(
 	metricsSvc ports.MetricsService,
 	exchange ports.ExchangeClient,
 	logger *slog.Logger,
+	opts ...HandlerOption,
 ) *Handler {
-	return &Handler{
+	h := &Handler{
 		symbolSvc:   symbolSvc,
 		snapshotSvc: snapshotSvc,
 		metricsSvc:  metricsSvc,
 		exchange:    exchange,
+		prices:      priceFormatter{format: PriceFormatString, decimals: 8},
 		logger:      logger.With("component", "http_handler"),
+
+		historyCacheGrace: DefaultHistoryCacheGrace,
+	}
+
+	for _, opt := range opts {
+		opt(h)
 	}
+
+	return h
 }
 
-// Health returns service health status
+// Health returns service health status. Without a health service it only
+// reports that the process is up.
 func (h *Handler) Health(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+	if h.health == nil {
+		respond(w, r, http.StatusOK, map[string]interface{}{"status": ports.HealthHealthy})
+		return
+	}
 
-	status := "healthy"
-	dbStatus := "healthy"
-	exchangeStatus := "healthy"
+	status, err := h.health.CheckHealth(r.Context())
+	if err != nil {
+		handleDomainError(w, err)
+		return
+	}
 
-	// Check exchange connectivity (with timeout)
-	checkCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
-	defer cancel()
+	respond(w, r, http.StatusOK, status)
+}
 
-	if err := h.exchange.Ping(checkCtx); err != nil {
-		exchangeStatus = "unhealthy"
-		status = "degraded"
+// Ready reports whether the service finished warm-up and can serve prices,
+// and whether the database has a writable primary
+func (h *Handler) Ready(w http.ResponseWriter, r *http.Request) {
+	if h.failover != nil {
+		if failover := h.failover.Failover(); failover != nil {
+			respond(w, r, http.StatusServiceUnavailable, map[string]interface{}{
+				"status":   ports.HealthDegraded,
+				"database": "failing_over",
+				"failover": failover,
+			})
+			return
+		}
 	}
 
-	respondJSON(w, http.StatusOK, map[string]interface{}{
-		"status":   status,
-		"database": dbStatus,
-		"exchange": exchangeStatus,
-	})
+	if h.warmup == nil {
+		respond(w, r, http.StatusOK, map[string]interface{}{"status": "ready"})
+		return
+	}
+
+	response := map[string]interface{}{"status": "ready"}
+	if report := h.warmup.Report(); report != nil && len(report.UnrecognizedSymbols) > 0 {
+		response["unrecognized_symbols"] = report.UnrecognizedSymbols
+	}
+
+	status := http.StatusOK
+	if !h.warmup.Ready() {
+		status = http.StatusServiceUnavailable
+		response["status"] = "warming_up"
+	}
+
+	respond(w, r, status, response)
 }
 
-// ListSymbols returns all tracked symbols
+// ListSymbols returns all tracked symbols, optionally filtered by ?tag=
 func (h *Handler) ListSymbols(w http.ResponseWriter, r *http.Request) {
-	symbols, err := h.symbolSvc.ListSymbols(r.Context())
+	if r.URL.Query().Has("limit") || r.URL.Query().Has("cursor") {
+		h.listSymbolsPage(w, r)
+		return
+	}
+
+	var (
+		symbols []*domain.Symbol
+		err     error
+	)
+	if tag := r.URL.Query().Get("tag"); tag != "" {
+		symbols, err = h.symbolSvc.ListSymbolsByTag(r.Context(), tag)
+	} else {
+		symbols, err = h.symbolSvc.ListSymbols(r.Context())
+	}
 	if err != nil {
 		handleDomainError(w, err)
 		return
@@ -77,37 +209,175 @@ func (h *Handler) ListSymbols(w http.ResponseWriter, r *http.Request) {
 		symbolNames[i] = s.Name
 	}
 
-	respondJSON(w, http.StatusOK, map[string]interface{}{
-		"symbols": symbolNames,
-	})
+	respond(w, r, http.StatusOK, SymbolsResponse{Symbols: symbolNames})
+}
+
+// listSymbolsPage returns ?limit= symbols (default 100, at most 1000)
+// ordered by name, starting after ?cursor=
+func (h *Handler) listSymbolsPage(w http.ResponseWriter, r *http.Request) {
+	limit := 100
+	if limitParam := r.URL.Query().Get("limit"); limitParam != "" {
+		if l, err := strconv.Atoi(limitParam); err == nil && l > 0 && l <= 1000 {
+			limit = l
+		}
+	}
+
+	var cursor *domain.SymbolCursor
+	if param := r.URL.Query().Get("cursor"); param != "" {
+		var err error
+		if cursor, err = domain.ParseSymbolCursor(param); err != nil {
+			handleDomainError(w, err)
+			return
+		}
+	}
+
+	// One extra symbol tells whether another page follows
+	symbols, err := h.symbolSvc.ListSymbolsPage(r.Context(), r.URL.Query().Get("tag"), cursor, limit+1)
+	if err != nil {
+		handleDomainError(w, err)
+		return
+	}
+
+	resp := SymbolsResponse{Symbols: []string{}}
+	if len(symbols) > limit {
+		symbols = symbols[:limit]
+		next := domain.SymbolCursor{Name: symbols[limit-1].Name}.Encode()
+		resp.NextCursor = &next
+	}
+	for _, s := range symbols {
+		resp.Symbols = append(resp.Symbols, s.Name)
+	}
+
+	respond(w, r, http.StatusOK, resp)
+}
+
+// SymbolsResponse represents the list of tracked symbols. NextCursor is set
+// on a page of symbols that is followed by another.
+type SymbolsResponse struct {
+	Symbols    []string `json:"symbols"`
+	NextCursor *string  `json:"next_cursor,omitempty"`
+}
+
+func (s SymbolsResponse) columns() []string { return []string{"symbol"} }
+
+func (s SymbolsResponse) records() [][]string {
+	records := make([][]string, len(s.Symbols))
+	for i, name := range s.Symbols {
+		records[i] = []string{name}
+	}
+	return records
+}
+
+func (s SymbolsResponse) items() []interface{} {
+	items := make([]interface{}, len(s.Symbols))
+	for i, name := range s.Symbols {
+		items[i] = map[string]string{"symbol": name}
+	}
+	return items
+}
+
+// defaultStaleMaxAge is the ?max_age= of /symbols/stale when none is given
+const defaultStaleMaxAge = 10 * time.Minute
+
+// ListStaleSymbols returns the active symbols whose latest snapshot is
+// older than ?max_age= (default 10m), or that have none, so silently
+// broken pairs stand out
+func (h *Handler) ListStaleSymbols(w http.ResponseWriter, r *http.Request) {
+	maxAge := defaultStaleMaxAge
+	if param := r.URL.Query().Get("max_age"); param != "" {
+		d, err := time.ParseDuration(param)
+		if err != nil || d <= 0 {
+			respondErrorWithCode(w, http.StatusBadRequest, "max_age must be a positive duration, e.g. 10m", "INVALID_MAX_AGE")
+			return
+		}
+		maxAge = d
+	}
+
+	now := time.Now()
+	stale, err := h.snapshotSvc.GetStaleSymbols(r.Context(), now.Add(-maxAge))
+	if err != nil {
+		handleDomainError(w, err)
+		return
+	}
+
+	resp := StaleSymbolsResponse{MaxAge: maxAge.String(), Symbols: make([]StaleSymbolResponse, len(stale))}
+	for i, s := range stale {
+		row := StaleSymbolResponse{Symbol: s.Symbol}
+		if s.LastSnapshotAt != nil {
+			at := s.LastSnapshotAt.UTC().Format(time.RFC3339)
+			age := now.Sub(*s.LastSnapshotAt).Milliseconds()
+			row.LastSnapshotAt = &at
+			row.AgeMs = &age
+		}
+		resp.Symbols[i] = row
+	}
+
+	respond(w, r, http.StatusOK, resp)
+}
+
+// StaleSymbolsResponse lists the active symbols without a recent snapshot
+type StaleSymbolsResponse struct {
+	MaxAge  string                `json:"max_age"`
+	Symbols []StaleSymbolResponse `json:"symbols"`
+}
+
+// StaleSymbolResponse is one stale symbol. The last snapshot time and age
+// are null for a symbol that has never had a snapshot.
+type StaleSymbolResponse struct {
+	Symbol         string  `json:"symbol"`
+	LastSnapshotAt *string `json:"last_snapshot_at"`
+	AgeMs          *int64  `json:"age_ms"`
+}
+
+func (s StaleSymbolsResponse) columns() []string {
+	return []string{"symbol", "last_snapshot_at", "age_ms"}
+}
+
+func (s StaleSymbolsResponse) records() [][]string {
+	records := make([][]string, len(s.Symbols))
+	for i, row := range s.Symbols {
+		var at, age string
+		if row.LastSnapshotAt != nil {
+			at = *row.LastSnapshotAt
+			age = strconv.FormatInt(*row.AgeMs, 10)
+		}
+		records[i] = []string{row.Symbol, at, age}
+	}
+	return records
+}
+
+func (s StaleSymbolsResponse) items() []interface{} {
+	items := make([]interface{}, len(s.Symbols))
+	for i, row := range s.Symbols {
+		items[i] = row
+	}
+	return items
 }
 
 // CreateSymbolRequest represents the request body for creating a symbol
 type CreateSymbolRequest struct {
-	Symbol string `json:"symbol"`
+	Symbol   string   `json:"symbol" validate:"required"`
+	Exchange string   `json:"exchange,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
 }
 
-// CreateSymbol adds a new symbol to track
+// CreateSymbol adds a new symbol to track. With a symbol poller, its
+// first snapshot is stored right away.
 func (h *Handler) CreateSymbol(w http.ResponseWriter, r *http.Request) {
 	var req CreateSymbolRequest
 
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondError(w, http.StatusBadRequest, "invalid request body")
+	if err := decodeRequest(w, r, maxRequestBodyBytes, &req); err != nil {
+		respondInvalidRequest(w, err)
 		return
 	}
 
-	if req.Symbol == "" {
-		respondError(w, http.StatusBadRequest, "symbol is required")
-		return
-	}
-
-	symbol, err := h.symbolSvc.AddSymbol(r.Context(), req.Symbol)
+	symbol, err := h.symbolSvc.AddSymbol(r.Context(), req.Symbol, req.Exchange, req.Tags...)
 	if err != nil {
 		// Check if symbol already exists - return 200 instead of error
 		if err == domain.ErrSymbolExists {
 			existing, getErr := h.symbolSvc.GetSymbol(r.Context(), req.Symbol)
 			if getErr == nil {
-				respondJSON(w, http.StatusOK, existing)
+				respond(w, r, http.StatusOK, existing)
 				return
 			}
 		}
@@ -115,10 +385,31 @@ func (h *Handler) CreateSymbol(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	respondJSON(w, http.StatusCreated, symbol)
+	// The symbol gets its first snapshot now rather than at the next poll;
+	// it is added even when that fails
+	if h.poller != nil {
+		if _, err := h.poller.PollSymbol(r.Context(), symbol.Name); err != nil {
+			h.logger.Warn("failed to poll new symbol", "symbol", symbol.Name, "error", err)
+		}
+	}
+
+	respond(w, r, http.StatusCreated, symbol)
+}
+
+// GetSymbol returns a tracked symbol, including its coin metadata once it
+// has been enriched
+func (h *Handler) GetSymbol(w http.ResponseWriter, r *http.Request) {
+	symbol, err := h.symbolSvc.GetSymbol(r.Context(), r.PathValue("symbol"))
+	if err != nil {
+		handleDomainError(w, err)
+		return
+	}
+
+	respond(w, r, http.StatusOK, symbol)
 }
 
-// DeleteSymbol removes a tracked symbol
+// DeleteSymbol removes a tracked symbol, keeping its snapshots unless
+// purge=true
 func (h *Handler) DeleteSymbol(w http.ResponseWriter, r *http.Request) {
 	// Extract symbol from path
 	symbol := r.PathValue("symbol")
@@ -127,7 +418,21 @@ func (h *Handler) DeleteSymbol(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.symbolSvc.RemoveSymbol(r.Context(), symbol); err != nil {
+	purge := false
+	if param := r.URL.Query().Get("purge"); param != "" {
+		v, err := strconv.ParseBool(param)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "purge must be a boolean")
+			return
+		}
+		purge = v
+	}
+
+	remove := h.symbolSvc.RemoveSymbol
+	if purge {
+		remove = h.symbolSvc.PurgeSymbol
+	}
+	if err := remove(r.Context(), symbol); err != nil {
 		handleDomainError(w, err)
 		return
 	}
@@ -135,25 +440,280 @@ func (h *Handler) DeleteSymbol(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
-// PriceResponse represents a price in the API response
+// UpdateSymbolRequest represents the request body for updating a symbol
+type UpdateSymbolRequest struct {
+	Active *bool `json:"active" validate:"required"`
+}
+
+// UpdateSymbol pauses or resumes polling of a tracked symbol without
+// touching its history
+func (h *Handler) UpdateSymbol(w http.ResponseWriter, r *http.Request) {
+	var req UpdateSymbolRequest
+
+	if err := decodeRequest(w, r, maxRequestBodyBytes, &req); err != nil {
+		respondInvalidRequest(w, err)
+		return
+	}
+
+	symbol, err := h.symbolSvc.SetActive(r.Context(), r.PathValue("symbol"), *req.Active)
+	if err != nil {
+		handleDomainError(w, err)
+		return
+	}
+
+	respond(w, r, http.StatusOK, symbol)
+}
+
+// UpdateSymbolsRequest represents the request body for pausing or resuming
+// several symbols at once
+type UpdateSymbolsRequest struct {
+	Symbols []string `json:"symbols" validate:"required"`
+	Active  *bool    `json:"active" validate:"required"`
+}
+
+// UpdateSymbols pauses or resumes polling of several symbols at once,
+// e.g. during an incident. The batch is applied to every symbol or to
+// none; when it is not applied it answers 422 with the reason per symbol.
+func (h *Handler) UpdateSymbols(w http.ResponseWriter, r *http.Request) {
+	var req UpdateSymbolsRequest
+
+	if err := decodeRequest(w, r, maxRequestBodyBytes, &req); err != nil {
+		respondInvalidRequest(w, err)
+		return
+	}
+	if len(req.Symbols) > domain.MaxSymbolBatch {
+		respondErrorWithCode(w, http.StatusBadRequest,
+			fmt.Sprintf("at most %d symbols per batch", domain.MaxSymbolBatch), "BATCH_TOO_LARGE")
+		return
+	}
+
+	batch, err := h.symbolSvc.SetActiveBatch(r.Context(), req.Symbols, *req.Active)
+	if err != nil {
+		handleDomainError(w, err)
+		return
+	}
+
+	status := http.StatusOK
+	if !batch.Applied {
+		status = http.StatusUnprocessableEntity
+	}
+	respond(w, r, status, batch)
+}
+
+// SetTagsRequest represents the request body for replacing a symbol's tags
+type SetTagsRequest struct {
+	Tags []string `json:"tags"`
+}
+
+// SetSymbolTags replaces the tags of a tracked symbol
+func (h *Handler) SetSymbolTags(w http.ResponseWriter, r *http.Request) {
+	var req SetTagsRequest
+
+	if err := decodeRequest(w, r, maxRequestBodyBytes, &req); err != nil {
+		respondInvalidRequest(w, err)
+		return
+	}
+
+	symbol, err := h.symbolSvc.SetTags(r.Context(), r.PathValue("symbol"), req.Tags)
+	if err != nil {
+		handleDomainError(w, err)
+		return
+	}
+
+	respond(w, r, http.StatusOK, symbol)
+}
+
+// DeleteSymbolTag removes a single tag from a tracked symbol
+func (h *Handler) DeleteSymbolTag(w http.ResponseWriter, r *http.Request) {
+	symbol, err := h.symbolSvc.RemoveTag(r.Context(), r.PathValue("symbol"), r.PathValue("tag"))
+	if err != nil {
+		handleDomainError(w, err)
+		return
+	}
+
+	respond(w, r, http.StatusOK, symbol)
+}
+
+// SymbolEventsResponse represents a symbol's change history
+type SymbolEventsResponse struct {
+	Symbol string                `json:"symbol"`
+	Events []*domain.SymbolEvent `json:"events"`
+}
+
+// GetSymbolEvents returns a symbol's change history, newest first
+func (h *Handler) GetSymbolEvents(w http.ResponseWriter, r *http.Request) {
+	symbol := h.symbols.Normalize(r.PathValue("symbol"))
+
+	limit := 100
+	if limitParam := r.URL.Query().Get("limit"); limitParam != "" {
+		if l, err := strconv.Atoi(limitParam); err == nil && l > 0 && l <= 1000 {
+			limit = l
+		}
+	}
+
+	events, err := h.symbolSvc.ListEvents(r.Context(), symbol, limit)
+	if err != nil {
+		handleDomainError(w, err)
+		return
+	}
+	if events == nil {
+		events = []*domain.SymbolEvent{}
+	}
+
+	respond(w, r, http.StatusOK, SymbolEventsResponse{Symbol: symbol, Events: events})
+}
+
+// PriceResponse represents a price in the API response. TickSize and
+// DisplayDecimals are formatting hints from exchange metadata, only set on
+// latest prices, as is FetchLatencyMs, the exchange latency of the poll that
+// stored the price. NewlyAdded flags prices of symbols registered by
+// ?track_missing=true, and Tracked whether their price was stored as a
+// snapshot or only fetched live.
 type PriceResponse struct {
-	Symbol    string `json:"symbol"`
-	Price     string `json:"price"`
-	Timestamp string `json:"ts"`
+	Symbol          string       `json:"symbol,omitempty"`
+	Price           interface{}  `json:"price,omitempty"`
+	PriceNum        *json.Number `json:"price_num,omitempty"`
+	Timestamp       string       `json:"ts,omitempty"`
+	TimestampLocal  string       `json:"ts_local,omitempty"`
+	TimestampAgeMs  *int64       `json:"ts_age_ms,omitempty"`
+	FetchLatencyMs  *int64       `json:"fetch_latency_ms,omitempty"`
+	Exchange        string       `json:"exchange,omitempty"`
+	InvertedFrom    string       `json:"inverted_from,omitempty"`
+	TickSize        string       `json:"tick_size,omitempty"`
+	DisplayDecimals *int32       `json:"display_decimals,omitempty"`
+	Tracked         *bool        `json:"tracked,omitempty"`
+	NewlyAdded      bool         `json:"newly_added,omitempty"`
+	Filled          bool         `json:"filled,omitempty"`
+
+	// The previous snapshot and the move from it to this price, with
+	// ?include=previous
+	PrevPrice     interface{} `json:"prev_price,omitempty"`
+	PrevTimestamp string      `json:"prev_ts,omitempty"`
+	Delta         interface{} `json:"delta,omitempty"`
+	DeltaPercent  string      `json:"delta_pct,omitempty"`
+}
+
+// PricesResponse represents the latest prices and any symbols without data
+type PricesResponse struct {
+	Prices  []PriceResponse `json:"prices"`
+	Missing []string        `json:"missing,omitempty"`
+
+	fields   fieldSet
+	previous bool
+	inverted bool
+}
+
+func (p PricesResponse) columns() []string {
+	columns := p.fields.columns(latestPriceFields)
+	if p.previous && p.inverted {
+		return columns
+	}
+
+	// Leave out the empty previous price and inversion columns
+	filtered := columns[:0:0]
+	for _, column := range columns {
+		if (!p.previous && previousPriceFields[column]) || (!p.inverted && column == "inverted_from") {
+			continue
+		}
+		filtered = append(filtered, column)
+	}
+	return filtered
+}
+
+func (p PricesResponse) records() [][]string {
+	columns := p.columns()
+	records := make([][]string, len(p.Prices))
+	for i, price := range p.Prices {
+		records[i] = priceRecord(columns, price)
+	}
+	return records
+}
+
+func (p PricesResponse) items() []interface{} {
+	items := make([]interface{}, len(p.Prices))
+	for i, price := range p.Prices {
+		items[i] = price
+	}
+	return items
 }
 
-// GetPrices returns latest prices for specified symbols
+// GetPrices returns latest prices for the symbols listed in ?symbols=,
+// the symbols carrying ?tag=, or the listed symbols that carry the tag.
+// With ?track_missing=true, listed symbols that are not tracked yet are
+// registered and returned with a live exchange price, stored as their
+// first snapshot when a symbol poller is configured.
 func (h *Handler) GetPrices(w http.ResponseWriter, r *http.Request) {
 	symbolsParam := r.URL.Query().Get("symbols")
-	if symbolsParam == "" {
-		respondError(w, http.StatusBadRequest, "symbols parameter is required")
+	tag := r.URL.Query().Get("tag")
+	if symbolsParam == "" && tag == "" {
+		respondError(w, http.StatusBadRequest, "symbols or tag parameter is required")
 		return
 	}
 
+	trackMissing := false
+	if param := r.URL.Query().Get("track_missing"); param != "" {
+		v, err := strconv.ParseBool(param)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "track_missing must be a boolean")
+			return
+		}
+		trackMissing = v
+	}
+
+	invert := false
+	if param := r.URL.Query().Get("invert"); param != "" {
+		v, err := strconv.ParseBool(param)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "invert must be a boolean")
+			return
+		}
+		invert = v
+	}
+	if invert && trackMissing {
+		respondError(w, http.StatusBadRequest, "invert cannot be combined with track_missing")
+		return
+	}
+
+	fields, err := parseFields(r, latestPriceFields)
+	if err != nil {
+		respondErrorWithCode(w, http.StatusBadRequest, err.Error(), "INVALID_FIELDS")
+		return
+	}
+
+	includePrevious := false
+	if param := r.URL.Query().Get("include"); param != "" {
+		for _, name := range strings.Split(param, ",") {
+			switch name = strings.ToLower(strings.TrimSpace(name)); name {
+			case "previous":
+				includePrevious = true
+			case "":
+			default:
+				respondErrorWithCode(w, http.StatusBadRequest, fmt.Sprintf("unknown include %q, expected: previous", name), "INVALID_INCLUDE")
+				return
+			}
+		}
+	}
+
 	// Parse symbols
-	symbols := strings.Split(symbolsParam, ",")
-	for i := range symbols {
-		symbols[i] = strings.TrimSpace(symbols[i])
+	var symbols []string
+	if symbolsParam != "" {
+		symbols = strings.Split(symbolsParam, ",")
+		for i := range symbols {
+			symbols[i] = strings.TrimSpace(symbols[i])
+		}
+	}
+
+	if tag != "" {
+		tagged, err := h.symbolSvc.ListSymbolsByTag(r.Context(), tag)
+		if err != nil {
+			handleDomainError(w, err)
+			return
+		}
+		symbols = h.filterByTagged(symbols, tagged)
+		if len(symbols) == 0 {
+			respond(w, r, http.StatusOK, PricesResponse{Prices: []PriceResponse{}, fields: fields, previous: includePrevious})
+			return
+		}
 	}
 
 	prices, missing, err := h.snapshotSvc.GetLatestPrices(r.Context(), symbols)
@@ -162,31 +722,381 @@ func (h *Handler) GetPrices(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Symbols requested as the inverse of a tracked pair, e.g. USDTBTC,
+	// are served from the tracked pair; inverted maps the tracked name to
+	// the requested one
+	var inverted map[string]string
+	if invert {
+		inverted = make(map[string]string, len(prices))
+		for _, p := range prices {
+			name, _ := domain.InvertSymbol(p.Symbol)
+			inverted[p.Symbol] = name
+		}
+	} else if len(missing) > 0 {
+		var found []*domain.PriceSnapshot
+		found, missing, inverted, err = h.inversePrices(r.Context(), missing)
+		if err != nil {
+			handleDomainError(w, err)
+			return
+		}
+		prices = append(prices, found...)
+	}
+
+	var previous map[string]*domain.PriceSnapshot
+	if includePrevious && len(prices) > 0 {
+		stored := make([]string, len(prices))
+		for i, p := range prices {
+			stored[i] = p.Symbol
+		}
+		previous, err = h.snapshotSvc.GetPreviousPrices(r.Context(), stored)
+		if err != nil {
+			handleDomainError(w, err)
+			return
+		}
+	}
+
+	var added map[string]*domain.PriceSnapshot
+	if trackMissing && len(missing) > 0 {
+		var live []*domain.PriceSnapshot
+		live, missing = h.trackMissing(r.Context(), missing)
+		added = make(map[string]*domain.PriceSnapshot, len(live))
+		for _, p := range live {
+			added[p.Symbol] = p
+		}
+		prices = append(prices, live...)
+	}
+
+	precisions := h.precisions(r.Context(), fields, prices)
+
 	// Format response
+	now := time.Now()
 	priceResponses := make([]PriceResponse, len(prices))
 	for i, p := range prices {
-		priceResponses[i] = PriceResponse{
-			Symbol:    p.Symbol,
-			Price:     p.Price.String(),
-			Timestamp: p.Timestamp.Format(time.RFC3339),
+		tracked := p.Symbol
+		prev, hasPrev := previous[tracked]
+		name, invertRow := inverted[tracked]
+		if invertRow {
+			p = invertSnapshot(p, name)
+			if hasPrev {
+				prev = invertSnapshot(prev, name)
+			}
+		}
+
+		price, priceNum := h.prices.render(p.Price)
+		age := p.Age(now).Milliseconds()
+		row := PriceResponse{
+			Symbol:         p.Symbol,
+			Price:          price,
+			PriceNum:       priceNum,
+			Timestamp:      p.Timestamp.Format(time.RFC3339),
+			TimestampAgeMs: &age,
+			FetchLatencyMs: p.FetchLatencyMs,
+			Exchange:       p.Exchange,
+		}
+		if invertRow {
+			row.InvertedFrom = tracked
+		}
+		if hasPrev {
+			move := domain.NewPriceMove(prev, p)
+			row.PrevPrice, _ = h.prices.render(prev.Price)
+			row.PrevTimestamp = prev.Timestamp.Format(time.RFC3339)
+			row.Delta, _ = h.prices.render(move.Delta)
+			row.DeltaPercent = move.DeltaPercent.String()
+		}
+		// The tracked pair's tick size does not apply to its inverse
+		if precision, ok := precisions[tracked]; ok && !invertRow {
+			decimals := precision.DisplayDecimals
+			row.TickSize = precision.TickSize.String()
+			row.DisplayDecimals = &decimals
+		}
+		if snap, ok := added[p.Symbol]; ok {
+			stored := snap.ID != 0
+			row.Tracked = &stored
+			row.NewlyAdded = true
+		}
+		priceResponses[i] = selectPrice(fields, row)
+	}
+
+	respond(w, r, http.StatusOK, PricesResponse{
+		Prices:   priceResponses,
+		Missing:  missing,
+		fields:   fields,
+		previous: includePrevious,
+		inverted: len(inverted) > 0,
+	})
+}
+
+// inversePrices looks up the latest prices of the tracked pairs whose
+// inverse was requested among missing, e.g. BTCUSDT for USDTBTC. It
+// returns them with the symbols that are still missing and a map from
+// each tracked pair to the name it was requested by.
+func (h *Handler) inversePrices(ctx context.Context, missing []string) ([]*domain.PriceSnapshot, []string, map[string]string, error) {
+	requested := make(map[string]string, len(missing))
+	names := make([]string, 0, len(missing))
+	for _, symbol := range missing {
+		name := h.symbols.Normalize(symbol)
+		if pair, ok := domain.InvertSymbol(name); ok {
+			requested[pair] = symbol
+			names = append(names, pair)
+		}
+	}
+	if len(names) == 0 {
+		return nil, missing, nil, nil
+	}
+
+	prices, _, err := h.snapshotSvc.GetLatestPrices(ctx, names)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	found := make([]*domain.PriceSnapshot, 0, len(prices))
+	inverted := make(map[string]string, len(prices))
+	for _, p := range prices {
+		symbol, ok := requested[p.Symbol]
+		if !ok {
+			continue
+		}
+		inverted[p.Symbol] = h.symbols.Normalize(symbol)
+		found = append(found, p)
+	}
+
+	stillMissing := make([]string, 0, len(missing))
+	for _, symbol := range missing {
+		name := h.symbols.Normalize(symbol)
+		if pair, ok := domain.InvertSymbol(name); ok {
+			if _, found := inverted[pair]; found {
+				continue
+			}
+		}
+		stillMissing = append(stillMissing, symbol)
+	}
+	return found, stillMissing, inverted, nil
+}
+
+// invertSnapshot returns a copy of snap priced as the inverse pair name.
+// A price without an inverse is left as is.
+func invertSnapshot(snap *domain.PriceSnapshot, name string) *domain.PriceSnapshot {
+	inverse := *snap
+	inverse.Symbol = name
+	if price, ok := domain.InvertPrice(snap.Price); ok {
+		inverse.Price = price
+	}
+	return &inverse
+}
+
+// trackMissing registers symbols without stored prices and fetches their
+// current price from the exchange. With a symbol poller the prices are
+// stored as the symbols' first snapshots. Symbols that cannot be
+// registered (not listed on the exchange, already tracked but not polled
+// yet, or over the symbol quota) or priced stay missing.
+func (h *Handler) trackMissing(ctx context.Context, missing []string) ([]*domain.PriceSnapshot, []string) {
+	var added, stillMissing []string
+	for _, symbol := range missing {
+		if _, err := h.symbolSvc.AddSymbol(ctx, symbol, ""); err != nil {
+			h.logger.Debug("could not track missing symbol", "symbol", symbol, "error", err)
+			stillMissing = append(stillMissing, symbol)
+			continue
 		}
+		added = append(added, symbol)
+	}
+	if len(added) == 0 {
+		return nil, stillMissing
 	}
 
-	response := map[string]interface{}{
-		"prices": priceResponses,
+	if h.poller != nil {
+		return h.pollMissing(ctx, added, stillMissing)
 	}
 
-	if len(missing) > 0 {
-		response["missing"] = missing
+	prices, err := h.exchange.GetPrices(ctx, added)
+	if err != nil {
+		h.logger.Warn("failed to fetch prices of newly tracked symbols", "symbols", added, "error", err)
+		return nil, append(stillMissing, added...)
 	}
 
-	respondJSON(w, http.StatusOK, response)
+	fetched := make(map[string]*domain.Price, len(prices))
+	for _, p := range prices {
+		fetched[p.Symbol] = p
+	}
+
+	live := make([]*domain.PriceSnapshot, 0, len(added))
+	for _, symbol := range added {
+		p, ok := fetched[symbol]
+		if !ok {
+			stillMissing = append(stillMissing, symbol)
+			continue
+		}
+		timestamp := p.FetchedAt
+		if p.EventTime != nil {
+			timestamp = *p.EventTime
+		}
+		if timestamp.IsZero() {
+			timestamp = time.Now().UTC()
+		}
+		live = append(live, &domain.PriceSnapshot{
+			Symbol:    symbol,
+			Price:     p.Price,
+			Timestamp: timestamp,
+		})
+	}
+	return live, stillMissing
+}
+
+// pollMissing stores a first snapshot of each newly tracked symbol with
+// the symbol poller. Symbols that could not be polled stay missing.
+func (h *Handler) pollMissing(ctx context.Context, added, stillMissing []string) ([]*domain.PriceSnapshot, []string) {
+	polled := make([]*domain.PriceSnapshot, 0, len(added))
+	for _, symbol := range added {
+		snap, err := h.poller.PollSymbol(ctx, symbol)
+		if err != nil {
+			h.logger.Warn("failed to poll newly tracked symbol", "symbol", symbol, "error", err)
+			stillMissing = append(stillMissing, symbol)
+			continue
+		}
+		polled = append(polled, snap)
+	}
+	return polled, stillMissing
+}
+
+// precisions looks up formatting hints for the returned prices, unless no
+// hint field is selected or no precision service is configured
+func (h *Handler) precisions(ctx context.Context, fields fieldSet, prices []*domain.PriceSnapshot) map[string]*domain.SymbolPrecision {
+	if h.precision == nil || len(prices) == 0 || !(fields.has("tick_size") || fields.has("display_decimals")) {
+		return nil
+	}
+
+	symbols := make([]string, len(prices))
+	for i, p := range prices {
+		symbols[i] = p.Symbol
+	}
+	return h.precision.GetPrecisions(ctx, symbols)
+}
+
+// filterByTagged restricts requested symbols to the tagged ones. With no
+// requested symbols, every tagged symbol is returned.
+func (h *Handler) filterByTagged(requested []string, tagged []*domain.Symbol) []string {
+	if len(requested) == 0 {
+		names := make([]string, len(tagged))
+		for i, s := range tagged {
+			names[i] = s.Name
+		}
+		return names
+	}
+
+	taggedSet := make(map[string]bool, len(tagged))
+	for _, s := range tagged {
+		taggedSet[s.Name] = true
+	}
+
+	var names []string
+	for _, name := range requested {
+		if taggedSet[h.symbols.Normalize(name)] {
+			names = append(names, name)
+		}
+	}
+	return names
 }
 
 // HistoryItem represents a history item in the API response
 type HistoryItem struct {
-	Price     string `json:"price"`
-	Timestamp string `json:"ts"`
+	Price          interface{}  `json:"price,omitempty"`
+	PriceNum       *json.Number `json:"price_num,omitempty"`
+	Timestamp      string       `json:"ts,omitempty"`
+	TimestampLocal string       `json:"ts_local,omitempty"`
+
+	// Filled marks a bucket without snapshots whose price was filled in
+	Filled bool `json:"filled,omitempty"`
+}
+
+// HistoryResponse represents the price history of a symbol. Source tells
+// clients which data the items were read from, Sampling how they were
+// downsampled with ?points=, Buckets how they were averaged with ?step=,
+// and Timezone the zone of each item's ts_local when one was requested
+// with ?tz=. Limit, From and To echo the row limit and window applied,
+// with open ends left null, and Order the order of the items. Count is the number of items returned,
+// OldestTs and NewestTs the span they cover, and HasMore whether the window
+// holds older snapshots than the page reached; NextCursor, passed back as
+// ?cursor=, returns the page after. TotalCount, Oldest and
+// Newest describe everything stored for the symbol, so an empty window can
+// be told apart from a symbol without any snapshots.
+type HistoryResponse struct {
+	Symbol     string                  `json:"symbol"`
+	Source     domain.HistorySource    `json:"source"`
+	Sampling   *domain.HistorySampling `json:"sampling,omitempty"`
+	Buckets    *domain.HistoryBuckets  `json:"buckets,omitempty"`
+	Timezone   string                  `json:"tz,omitempty"`
+	Limit      int                     `json:"limit"`
+	From       *string                 `json:"from"`
+	To         *string                 `json:"to"`
+	Order      historyOrder            `json:"order"`
+	Count      int                     `json:"count"`
+	HasMore    bool                    `json:"has_more"`
+	NextCursor *string                 `json:"next_cursor"`
+	OldestTs   *string                 `json:"oldest_ts"`
+	NewestTs   *string                 `json:"newest_ts"`
+	TotalCount int64                   `json:"total_count"`
+	Oldest     *string                 `json:"oldest"`
+	Newest     *string                 `json:"newest"`
+	Items      []HistoryItem           `json:"items"`
+
+	fields fieldSet
+}
+
+func (h HistoryResponse) columns() []string {
+	columns := h.fields.columns(priceFields)
+	if h.Timezone == "" {
+		// Leave out the empty local time column
+		filtered := columns[:0:0]
+		for _, column := range columns {
+			if column != "ts_local" {
+				filtered = append(filtered, column)
+			}
+		}
+		columns = filtered
+	}
+
+	if h.Buckets != nil {
+		columns = append(columns, "filled")
+	}
+	return columns
+}
+
+func (h HistoryResponse) records() [][]string {
+	columns := h.columns()
+	records := make([][]string, len(h.Items))
+	for i, item := range h.Items {
+		records[i] = priceRecord(columns, h.row(item))
+	}
+	return records
+}
+
+func (h HistoryResponse) items() []interface{} {
+	items := make([]interface{}, len(h.Items))
+	for i, item := range h.Items {
+		items[i] = h.row(item)
+	}
+	return items
+}
+
+// row returns a history item as a price row with the selected fields
+func (h HistoryResponse) row(item HistoryItem) PriceResponse {
+	return selectPrice(h.fields, PriceResponse{
+		Symbol:         h.Symbol,
+		Price:          item.Price,
+		PriceNum:       item.PriceNum,
+		Timestamp:      item.Timestamp,
+		TimestampLocal: item.TimestampLocal,
+		Filled:         item.Filled,
+	})
+}
+
+// formatOptionalTime formats t in UTC as RFC 3339, or returns nil for a
+// missing time so that it is rendered as null
+func formatOptionalTime(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	formatted := t.UTC().Format(time.RFC3339)
+	return &formatted
 }
 
 // GetHistory returns price history for a symbol
@@ -197,33 +1107,239 @@ func (h *Handler) GetHistory(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	fields, err := parseFields(r, priceFields)
+	if err != nil {
+		respondErrorWithCode(w, http.StatusBadRequest, err.Error(), "INVALID_FIELDS")
+		return
+	}
+
+	loc, err := parseTimezone(r)
+	if err != nil {
+		respondErrorWithCode(w, http.StatusBadRequest, err.Error(), "INVALID_TIMEZONE")
+		return
+	}
+
 	// Parse limit
 	limit := 100
 	if limitParam := r.URL.Query().Get("limit"); limitParam != "" {
-		if l, err := strconv.Atoi(limitParam); err == nil && l > 0 && l <= 1000 {
+		if l, err := strconv.Atoi(limitParam); err == nil && l > 0 && l <= domain.MaxHistoryLimit {
 			limit = l
 		}
 	}
 
-	history, err := h.snapshotSvc.GetPriceHistory(r.Context(), symbol, limit)
+	sampling, err := parseSampling(r)
+	if err != nil {
+		respondErrorWithCode(w, http.StatusBadRequest, err.Error(), "INVALID_SAMPLING")
+		return
+	}
+	if sampling != nil {
+		// Sampling reads every row of the window, up to a cap
+		limit = maxSampledRows
+	}
+
+	rng, err := parseHistoryRange(r, time.Now(), h.historyCacheGrace)
+	if err != nil {
+		handleDomainError(w, err)
+		return
+	}
+
+	bucketing, err := parseBucketing(r, rng)
+	if err == nil && bucketing != nil && sampling != nil {
+		err = fmt.Errorf("step and points cannot be combined")
+	}
+	if err != nil {
+		respondErrorWithCode(w, http.StatusBadRequest, err.Error(), "INVALID_STEP")
+		return
+	}
+	if bucketing != nil {
+		// Buckets average every row of the window, up to a cap
+		limit = maxSampledRows
+	}
+
+	order, err := parseHistoryOrder(r)
+	if err != nil {
+		respondErrorWithCode(w, http.StatusBadRequest, err.Error(), "INVALID_ORDER")
+		return
+	}
+
+	var cursor *domain.HistoryCursor
+	if param := r.URL.Query().Get("cursor"); param != "" {
+		if sampling != nil || bucketing != nil {
+			respondErrorWithCode(w, http.StatusBadRequest, "cursor cannot be combined with step or points", "INVALID_CURSOR")
+			return
+		}
+		if cursor, err = domain.ParseHistoryCursor(param); err != nil {
+			handleDomainError(w, err)
+			return
+		}
+	}
+
+	// Long bucketed ranges are averaged from candle closes when candles
+	// hold them at a fine enough interval
+	source := domain.HistorySourceRaw
+	var history []*domain.PriceSnapshot
+	if interval, ok := h.rollupInterval(bucketing, rng); ok {
+		source = domain.HistorySourceRollup
+		history, err = h.rollupHistory(r.Context(), symbol, interval, rng)
+	}
+	if err == nil && len(history) == 0 {
+		// No candles cover the range, e.g. one older than the candle
+		// backfill, so read it from the snapshots after all
+		source = domain.HistorySourceRaw
+		history, err = h.history(r.Context(), symbol, rng, cursor, limit)
+	}
+	if err != nil {
+		handleDomainError(w, err)
+		return
+	}
+
+	if writeHistoryCaching(w, r, rng, history) {
+		return
+	}
+
+	summary, err := h.snapshotSvc.GetHistorySummary(r.Context(), symbol)
 	if err != nil {
 		handleDomainError(w, err)
 		return
 	}
 
+	hasMore, err := h.hasMoreHistory(r.Context(), symbol, rng, history, limit)
+	if err != nil {
+		handleDomainError(w, err)
+		return
+	}
+
+	var nextCursor *string
+	if hasMore && sampling == nil && bucketing == nil {
+		next := domain.HistoryCursorAt(history[len(history)-1]).Encode()
+		nextCursor = &next
+	}
+
+	if sampling != nil {
+		sampling.Rows = len(history)
+		history = domain.DownsampleLTTB(history, sampling.Points)
+	}
+
 	// Format response
-	items := make([]HistoryItem, len(history))
-	for i, h := range history {
-		items[i] = HistoryItem{
-			Price:     h.Price.String(),
-			Timestamp: h.Timestamp.Format(time.RFC3339),
+	var oldestTs, newestTs *time.Time
+	item := func(price decimal.Decimal, ts time.Time, filled bool) HistoryItem {
+		if oldestTs == nil || ts.Before(*oldestTs) {
+			oldestTs = &ts
+		}
+		if newestTs == nil || ts.After(*newestTs) {
+			newestTs = &ts
+		}
+
+		rendered, priceNum := h.prices.render(price)
+		row := selectPrice(fields, PriceResponse{
+			Price:          rendered,
+			PriceNum:       priceNum,
+			Timestamp:      ts.UTC().Format(time.RFC3339),
+			TimestampLocal: localTimestamp(ts, loc),
+		})
+		return HistoryItem{
+			Price:          row.Price,
+			PriceNum:       row.PriceNum,
+			Timestamp:      row.Timestamp,
+			TimestampLocal: row.TimestampLocal,
+			Filled:         filled,
 		}
 	}
 
-	respondJSON(w, http.StatusOK, map[string]interface{}{
-		"symbol": strings.ToUpper(symbol),
-		"items":  items,
-	})
+	var items []HistoryItem
+	var buckets *domain.HistoryBuckets
+	if bucketing != nil {
+		var priced []domain.PriceBucket
+		priced, buckets = bucketing.apply(history, rng)
+		// Newest first, like raw history
+		items = make([]HistoryItem, len(priced))
+		for i, b := range priced {
+			items[len(priced)-1-i] = item(b.Price, b.Start, b.Filled)
+		}
+	} else {
+		items = make([]HistoryItem, len(history))
+		for i, snap := range history {
+			items[i] = item(snap.Price, snap.Timestamp, false)
+		}
+	}
+	if order == historyOrderAsc {
+		slices.Reverse(items)
+	}
+
+	response := HistoryResponse{
+		Symbol:     h.symbols.Normalize(symbol),
+		Source:     source,
+		Sampling:   sampling,
+		Buckets:    buckets,
+		Limit:      limit,
+		Order:      order,
+		Count:      len(items),
+		HasMore:    hasMore,
+		NextCursor: nextCursor,
+		OldestTs:   formatOptionalTime(oldestTs),
+		NewestTs:   formatOptionalTime(newestTs),
+		TotalCount: summary.TotalCount,
+		Oldest:     formatOptionalTime(summary.Oldest),
+		Newest:     formatOptionalTime(summary.Newest),
+		Items:      items,
+		fields:     fields,
+	}
+	if loc != nil {
+		response.Timezone = loc.String()
+	}
+	if r.URL.Query().Get("from") != "" {
+		response.From = formatOptionalTime(&rng.from)
+	}
+	if r.URL.Query().Get("to") != "" {
+		response.To = formatOptionalTime(&rng.to)
+	}
+	respond(w, r, http.StatusOK, response)
+}
+
+// historyOrder is the order of the items of a history page
+type historyOrder string
+
+const (
+	historyOrderDesc historyOrder = "desc"
+	historyOrderAsc  historyOrder = "asc"
+)
+
+// parseHistoryOrder reads ?order=, newest first by default. It only orders
+// the items of a page: a page still holds the newest snapshots of the
+// window and next_cursor continues to older ones.
+func parseHistoryOrder(r *http.Request) (historyOrder, error) {
+	switch order := historyOrder(r.URL.Query().Get("order")); order {
+	case "":
+		return historyOrderDesc, nil
+	case historyOrderDesc, historyOrderAsc:
+		return order, nil
+	}
+	return "", fmt.Errorf("order must be asc or desc")
+}
+
+// history returns a page of newest-first history, within the range when
+// one is set, continuing after before when it is set
+func (h *Handler) history(ctx context.Context, symbol string, rng historyRange, before *domain.HistoryCursor, limit int) ([]*domain.PriceSnapshot, error) {
+	if rng.set {
+		return h.snapshotSvc.GetPriceHistoryBetween(ctx, symbol, rng.from, rng.to, before, limit)
+	}
+	return h.snapshotSvc.GetPriceHistory(ctx, symbol, before, limit)
+}
+
+// hasMoreHistory reports whether the window holds snapshots past the oldest
+// of a full page of newest-first history. A client reaches them with the
+// page's next_cursor.
+func (h *Handler) hasMoreHistory(ctx context.Context, symbol string, rng historyRange, history []*domain.PriceSnapshot, limit int) (bool, error) {
+	if len(history) == 0 || len(history) < limit {
+		return false, nil
+	}
+
+	next := domain.HistoryCursorAt(history[len(history)-1])
+	older, err := h.history(ctx, symbol, rng, &next, 1)
+	if err != nil {
+		return false, err
+	}
+	return len(older) > 0, nil
 }
 
 // GetMetrics returns operational metrics
@@ -234,5 +1350,22 @@ func (h *Handler) GetMetrics(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	respondJSON(w, http.StatusOK, metrics)
+	respond(w, r, http.StatusOK, metrics)
+}
+
+// GetPrometheusMetrics returns operational metrics for scraping, in the
+// OpenMetrics format when the scraper accepts it and the Prometheus text
+// format otherwise
+func (h *Handler) GetPrometheusMetrics(w http.ResponseWriter, r *http.Request) {
+	metrics, err := h.metricsSvc.GetMetrics(r.Context())
+	if err != nil {
+		handleDomainError(w, err)
+		return
+	}
+
+	var enc Encoder = prometheusEncoder{}
+	if negotiated, _ := negotiateEncoder(r); negotiated == (openMetricsEncoder{}) {
+		enc = negotiated
+	}
+	respondWith(w, enc, http.StatusOK, metrics)
 }
@@ -4,10 +4,13 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
@@ -17,6 +20,7 @@ import (
 
 	httpAdapter "github.com/prxgr4mmer/price-snapshot-service/internal/adapters/http"
 	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
 )
 
 // Mock implementations for testing
@@ -25,19 +29,32 @@ type mockSymbolService struct {
 	symbols     []*domain.Symbol
 	addErr      error
 	removeErr   error
+	removed     []string
+	purged      []string
 	existsValue bool
+	events      []*domain.SymbolEvent
 }
 
-func (m *mockSymbolService) AddSymbol(ctx context.Context, name string) (*domain.Symbol, error) {
+func (m *mockSymbolService) AddSymbol(ctx context.Context, name, exchange string, tags ...string) (*domain.Symbol, error) {
 	if m.addErr != nil {
 		return nil, m.addErr
 	}
-	s := &domain.Symbol{ID: 1, Name: name, Active: true, CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	s := &domain.Symbol{ID: 1, Name: name, Exchange: exchange, Active: true, Tags: tags, CreatedAt: time.Now(), UpdatedAt: time.Now()}
 	m.symbols = append(m.symbols, s)
 	return s, nil
 }
 
 func (m *mockSymbolService) RemoveSymbol(ctx context.Context, name string) error {
+	if m.removeErr == nil {
+		m.removed = append(m.removed, name)
+	}
+	return m.removeErr
+}
+
+func (m *mockSymbolService) PurgeSymbol(ctx context.Context, name string) error {
+	if m.removeErr == nil {
+		m.purged = append(m.purged, name)
+	}
 	return m.removeErr
 }
 
@@ -45,6 +62,93 @@ func (m *mockSymbolService) ListSymbols(ctx context.Context) ([]*domain.Symbol,
 	return m.symbols, nil
 }
 
+func (m *mockSymbolService) ListSymbolsByTag(ctx context.Context, tag string) ([]*domain.Symbol, error) {
+	tag, err := domain.NormalizeTag(tag)
+	if err != nil {
+		return nil, err
+	}
+	var tagged []*domain.Symbol
+	for _, s := range m.symbols {
+		if s.HasTag(tag) {
+			tagged = append(tagged, s)
+		}
+	}
+	return tagged, nil
+}
+
+func (m *mockSymbolService) ListSymbolsPage(ctx context.Context, tag string, after *domain.SymbolCursor, limit int) ([]*domain.Symbol, error) {
+	var page []*domain.Symbol
+	for _, s := range m.symbols {
+		if (tag == "" || s.HasTag(tag)) && (after == nil || s.Name > after.Name) && len(page) < limit {
+			page = append(page, s)
+		}
+	}
+	return page, nil
+}
+
+func (m *mockSymbolService) SetTags(ctx context.Context, name string, tags []string) (*domain.Symbol, error) {
+	s, err := m.GetSymbol(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.SetTags(tags); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (m *mockSymbolService) SetActive(ctx context.Context, name string, active bool) (*domain.Symbol, error) {
+	s, err := m.GetSymbol(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if active {
+		s.Activate()
+	} else {
+		s.Deactivate()
+	}
+	return s, nil
+}
+
+func (m *mockSymbolService) SetActiveBatch(ctx context.Context, names []string, active bool) (*domain.SymbolBatch, error) {
+	batch := &domain.SymbolBatch{}
+	var found []*domain.Symbol
+	for _, name := range names {
+		s, err := m.GetSymbol(ctx, name)
+		if err != nil {
+			batch.Results = append(batch.Results, domain.SymbolBatchResult{Symbol: name, Outcome: domain.SymbolBatchNotFound, Error: err.Error()})
+			continue
+		}
+		outcome := domain.SymbolBatchUnchanged
+		if s.Active != active {
+			outcome = domain.SymbolBatchDeactivated
+			if active {
+				outcome = domain.SymbolBatchActivated
+			}
+		}
+		batch.Results = append(batch.Results, domain.SymbolBatchResult{Symbol: name, Outcome: outcome})
+		found = append(found, s)
+	}
+	if batch.Failed() {
+		batch.Skip()
+		return batch, nil
+	}
+	for _, s := range found {
+		s.Active = active
+	}
+	batch.Applied = true
+	return batch, nil
+}
+
+func (m *mockSymbolService) RemoveTag(ctx context.Context, name, tag string) (*domain.Symbol, error) {
+	s, err := m.GetSymbol(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	s.RemoveTag(tag)
+	return s, nil
+}
+
 func (m *mockSymbolService) GetSymbol(ctx context.Context, name string) (*domain.Symbol, error) {
 	for _, s := range m.symbols {
 		if s.Name == name {
@@ -58,24 +162,114 @@ func (m *mockSymbolService) SymbolExists(ctx context.Context, name string) (bool
 	return m.existsValue, nil
 }
 
+func (m *mockSymbolService) ListEvents(ctx context.Context, name string, limit int) ([]*domain.SymbolEvent, error) {
+	var events []*domain.SymbolEvent
+	for _, e := range m.events {
+		if e.Symbol == name && len(events) < limit {
+			events = append(events, e)
+		}
+	}
+	if len(events) == 0 {
+		if _, err := m.GetSymbol(ctx, name); err != nil {
+			return nil, err
+		}
+	}
+	return events, nil
+}
+
 type mockSnapshotService struct {
 	snapshots []*domain.PriceSnapshot
 	missing   []string
 	err       error
+	from, to  time.Time
+	before    *domain.HistoryCursor
+	previous  map[string]*domain.PriceSnapshot
+	runID     string
+	stale     []*domain.StaleSymbol
+	cutoff    time.Time
+	dryRun    bool
+
+	// latest, when set, answers GetLatestPrices by the requested symbols
+	latest func(symbols []string) ([]*domain.PriceSnapshot, []string)
 }
 
 func (m *mockSnapshotService) GetLatestPrices(ctx context.Context, symbols []string) ([]*domain.PriceSnapshot, []string, error) {
+	if m.latest != nil {
+		prices, missing := m.latest(symbols)
+		return prices, missing, m.err
+	}
 	return m.snapshots, m.missing, m.err
 }
 
-func (m *mockSnapshotService) GetPriceHistory(ctx context.Context, symbol string, limit int) ([]*domain.PriceSnapshot, error) {
+func (m *mockSnapshotService) GetPreviousPrices(ctx context.Context, symbols []string) (map[string]*domain.PriceSnapshot, error) {
+	return m.previous, m.err
+}
+
+func (m *mockSnapshotService) GetPriceHistory(ctx context.Context, symbol string, before *domain.HistoryCursor, limit int) ([]*domain.PriceSnapshot, error) {
+	m.before = before
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.snapshots, nil
+}
+
+func (m *mockSnapshotService) GetPriceHistoryBetween(ctx context.Context, symbol string, from, to time.Time, before *domain.HistoryCursor, limit int) ([]*domain.PriceSnapshot, error) {
+	m.from, m.to, m.before = from, to, before
 	if m.err != nil {
 		return nil, m.err
 	}
 	return m.snapshots, nil
 }
 
-type mockMetricsService struct{}
+func (m *mockSnapshotService) GetHistorySummary(ctx context.Context, symbol string) (*domain.HistorySummary, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	summary := &domain.HistorySummary{TotalCount: int64(len(m.snapshots))}
+	for _, snap := range m.snapshots {
+		ts := snap.Timestamp
+		if summary.Oldest == nil || ts.Before(*summary.Oldest) {
+			summary.Oldest = &ts
+		}
+		if summary.Newest == nil || ts.After(*summary.Newest) {
+			summary.Newest = &ts
+		}
+	}
+	return summary, nil
+}
+
+func (m *mockSnapshotService) GetStaleSymbols(ctx context.Context, olderThan time.Time) ([]*domain.StaleSymbol, error) {
+	m.cutoff = olderThan
+	return m.stale, m.err
+}
+
+func (m *mockSnapshotService) DeleteSnapshotRange(ctx context.Context, symbol string, from, to time.Time, dryRun bool) (*domain.SnapshotRangeDeletion, error) {
+	m.from, m.to, m.dryRun = from, to, dryRun
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &domain.SnapshotRangeDeletion{Symbol: symbol, From: from, To: to, DryRun: dryRun, Deleted: 120, KeptCompressed: 10}, nil
+}
+
+func (m *mockSnapshotService) GetPollRun(ctx context.Context, runID string) (*domain.PollRun, error) {
+	m.runID = runID
+	if len(m.snapshots) == 0 {
+		return nil, domain.ErrPollRunNotFound
+	}
+	return &domain.PollRun{ID: runID, Snapshots: m.snapshots}, nil
+}
+
+func (m *mockSnapshotService) DeletePollRun(ctx context.Context, runID string) (int64, error) {
+	m.runID = runID
+	if len(m.snapshots) == 0 {
+		return 0, domain.ErrPollRunNotFound
+	}
+	return int64(len(m.snapshots)), nil
+}
+
+type mockMetricsService struct {
+	pollSymbols []*domain.PollSymbols
+}
 
 func (m *mockMetricsService) GetMetrics(ctx context.Context) (*domain.Metrics, error) {
 	return &domain.Metrics{
@@ -90,12 +284,22 @@ func (m *mockMetricsService) GetMetrics(ctx context.Context) (*domain.Metrics, e
 	}, nil
 }
 
-func (m *mockMetricsService) RecordPollSuccess(duration time.Duration) {}
-func (m *mockMetricsService) RecordPollError(duration time.Duration)   {}
-func (m *mockMetricsService) GetLastPollTime() *time.Time              { return nil }
+func (m *mockMetricsService) RecordPollSuccess(group string, duration time.Duration) {}
+func (m *mockMetricsService) RecordPollError(group string, duration time.Duration)   {}
+func (m *mockMetricsService) RecordPollStage(domain.PollStage, time.Duration, bool)  {}
+func (m *mockMetricsService) RecordSnapshotWriteFailures(failed int)                 {}
+func (m *mockMetricsService) GetLastPollTime() *time.Time                            { return nil }
+func (m *mockMetricsService) RecordPrune(symbol string, deleted int64)               {}
+func (m *mockMetricsService) RecordPollSymbols(run *domain.PollSymbols)              {}
+func (m *mockMetricsService) GetPollSymbols() []*domain.PollSymbols                  { return m.pollSymbols }
 
 type mockExchangeClient struct {
 	pingErr error
+	prices  []*domain.Price
+}
+
+func (m *mockExchangeClient) GetSymbolPrecisions(ctx context.Context, symbols []string) ([]*domain.SymbolPrecision, error) {
+	return nil, nil
 }
 
 func (m *mockExchangeClient) GetPrice(ctx context.Context, symbol string) (*domain.Price, error) {
@@ -103,13 +307,31 @@ func (m *mockExchangeClient) GetPrice(ctx context.Context, symbol string) (*doma
 }
 
 func (m *mockExchangeClient) GetPrices(ctx context.Context, symbols []string) ([]*domain.Price, error) {
-	return nil, nil
+	var prices []*domain.Price
+	for _, p := range m.prices {
+		if slices.Contains(symbols, p.Symbol) {
+			prices = append(prices, p)
+		}
+	}
+	return prices, nil
 }
 
 func (m *mockExchangeClient) ValidateSymbol(ctx context.Context, symbol string) (bool, error) {
 	return true, nil
 }
 
+func (m *mockExchangeClient) GetCandles(ctx context.Context, symbol, interval string, from, to time.Time, limit int) ([]*domain.Candle, error) {
+	return nil, nil
+}
+
+func (m *mockExchangeClient) GetTickerStats(ctx context.Context, symbols []string) ([]*domain.TickerStats, error) {
+	return nil, nil
+}
+
+func (m *mockExchangeClient) ListTradingSymbols(ctx context.Context) ([]string, error) {
+	return nil, nil
+}
+
 func (m *mockExchangeClient) Ping(ctx context.Context) error {
 	return m.pingErr
 }
@@ -118,6 +340,15 @@ func newTestLogger() *slog.Logger {
 	return slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
 }
 
+type mockHealthService struct {
+	status *ports.HealthStatus
+	err    error
+}
+
+func (m *mockHealthService) CheckHealth(ctx context.Context) (*ports.HealthStatus, error) {
+	return m.status, m.err
+}
+
 func TestHandler_Health(t *testing.T) {
 	t.Run("returns healthy status", func(t *testing.T) {
 		handler := httpAdapter.NewHandler(
@@ -126,6 +357,11 @@ func TestHandler_Health(t *testing.T) {
 			&mockMetricsService{},
 			&mockExchangeClient{},
 			newTestLogger(),
+			httpAdapter.WithHealth(&mockHealthService{status: &ports.HealthStatus{
+				Status:   ports.HealthHealthy,
+				Database: ports.HealthHealthy,
+				Exchange: ports.HealthHealthy,
+			}}),
 		)
 
 		req := httptest.NewRequest(http.MethodGet, "/health", nil)
@@ -139,6 +375,8 @@ func TestHandler_Health(t *testing.T) {
 		err := json.Unmarshal(rec.Body.Bytes(), &response)
 		require.NoError(t, err)
 		assert.Equal(t, "healthy", response["status"])
+		assert.Equal(t, "healthy", response["database"])
+		assert.NotContains(t, response, "details")
 	})
 
 	t.Run("returns degraded when exchange is down", func(t *testing.T) {
@@ -146,8 +384,14 @@ func TestHandler_Health(t *testing.T) {
 			&mockSymbolService{},
 			&mockSnapshotService{},
 			&mockMetricsService{},
-			&mockExchangeClient{pingErr: domain.ErrExchangeUnavailable},
+			&mockExchangeClient{},
 			newTestLogger(),
+			httpAdapter.WithHealth(&mockHealthService{status: &ports.HealthStatus{
+				Status:   ports.HealthDegraded,
+				Database: ports.HealthHealthy,
+				Exchange: ports.HealthUnhealthy,
+				Details:  map[string]string{"exchange": "exchange unavailable"},
+			}}),
 		)
 
 		req := httptest.NewRequest(http.MethodGet, "/health", nil)
@@ -162,6 +406,145 @@ func TestHandler_Health(t *testing.T) {
 		require.NoError(t, err)
 		assert.Equal(t, "degraded", response["status"])
 		assert.Equal(t, "unhealthy", response["exchange"])
+		assert.Equal(t, map[string]interface{}{"exchange": "exchange unavailable"}, response["details"])
+	})
+
+	t.Run("reports liveness without a health service", func(t *testing.T) {
+		handler := httpAdapter.NewHandler(
+			&mockSymbolService{},
+			&mockSnapshotService{},
+			&mockMetricsService{},
+			&mockExchangeClient{pingErr: domain.ErrExchangeUnavailable},
+			newTestLogger(),
+		)
+
+		req := httptest.NewRequest(http.MethodGet, "/health", nil)
+		rec := httptest.NewRecorder()
+
+		handler.Health(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"status": "healthy"}`, rec.Body.String())
+	})
+}
+
+type mockFailoverMonitor struct {
+	failover *domain.DatabaseFailover
+}
+
+func (m *mockFailoverMonitor) Failover() *domain.DatabaseFailover {
+	return m.failover
+}
+
+func TestHandler_ReadyDuringFailover(t *testing.T) {
+	t.Run("reports degraded while failing over", func(t *testing.T) {
+		since := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+		handler := httpAdapter.NewHandler(
+			&mockSymbolService{},
+			&mockSnapshotService{},
+			&mockMetricsService{},
+			&mockExchangeClient{},
+			newTestLogger(),
+			httpAdapter.WithDatabaseFailover(&mockFailoverMonitor{failover: &domain.DatabaseFailover{
+				Since:  since,
+				Reason: "FATAL: terminating connection due to administrator command (SQLSTATE 57P01)",
+			}}),
+		)
+
+		req := httptest.NewRequest(http.MethodGet, "/ready", nil)
+		rec := httptest.NewRecorder()
+
+		handler.Ready(rec, req)
+
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+
+		var response map[string]interface{}
+		err := json.Unmarshal(rec.Body.Bytes(), &response)
+		require.NoError(t, err)
+		assert.Equal(t, "degraded", response["status"])
+		assert.Equal(t, "failing_over", response["database"])
+		failover, ok := response["failover"].(map[string]interface{})
+		require.True(t, ok)
+		assert.Equal(t, "2024-01-15T10:30:00Z", failover["since"])
+	})
+
+	t.Run("reports ready with a writable primary", func(t *testing.T) {
+		handler := httpAdapter.NewHandler(
+			&mockSymbolService{},
+			&mockSnapshotService{},
+			&mockMetricsService{},
+			&mockExchangeClient{},
+			newTestLogger(),
+			httpAdapter.WithDatabaseFailover(&mockFailoverMonitor{}),
+		)
+
+		req := httptest.NewRequest(http.MethodGet, "/ready", nil)
+		rec := httptest.NewRecorder()
+
+		handler.Ready(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"status": "ready"}`, rec.Body.String())
+	})
+}
+
+func TestHandler_ListStaleSymbols(t *testing.T) {
+	lastSnapshot := time.Now().Add(-time.Hour)
+	newRouter := func(snapshots *mockSnapshotService) http.Handler {
+		handler := httpAdapter.NewHandler(
+			&mockSymbolService{},
+			snapshots,
+			&mockMetricsService{},
+			&mockExchangeClient{},
+			newTestLogger(),
+		)
+		return httpAdapter.NewRouter(handler, newTestLogger())
+	}
+
+	t.Run("lists stale symbols", func(t *testing.T) {
+		snapshots := &mockSnapshotService{stale: []*domain.StaleSymbol{
+			{Symbol: "DOGEUSDT", LastSnapshotAt: &lastSnapshot},
+			{Symbol: "NEWUSDT"},
+		}}
+		req := httptest.NewRequest(http.MethodGet, "/symbols/stale?max_age=30m", nil)
+		rec := httptest.NewRecorder()
+		newRouter(snapshots).ServeHTTP(rec, req)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.WithinDuration(t, time.Now().Add(-30*time.Minute), snapshots.cutoff, 5*time.Second)
+
+		var resp httpAdapter.StaleSymbolsResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.Equal(t, "30m0s", resp.MaxAge)
+		require.Len(t, resp.Symbols, 2)
+		assert.Equal(t, "DOGEUSDT", resp.Symbols[0].Symbol)
+		require.NotNil(t, resp.Symbols[0].AgeMs)
+		assert.InDelta(t, time.Hour.Milliseconds(), *resp.Symbols[0].AgeMs, 5000)
+		assert.Equal(t, "NEWUSDT", resp.Symbols[1].Symbol)
+		assert.Nil(t, resp.Symbols[1].LastSnapshotAt)
+		assert.Contains(t, rec.Body.String(), `"last_snapshot_at":null`)
+	})
+
+	t.Run("defaults max_age to 10 minutes", func(t *testing.T) {
+		snapshots := &mockSnapshotService{stale: []*domain.StaleSymbol{}}
+		req := httptest.NewRequest(http.MethodGet, "/symbols/stale", nil)
+		rec := httptest.NewRecorder()
+		newRouter(snapshots).ServeHTTP(rec, req)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.WithinDuration(t, time.Now().Add(-10*time.Minute), snapshots.cutoff, 5*time.Second)
+		assert.JSONEq(t, `{"max_age": "10m0s", "symbols": []}`, rec.Body.String())
+	})
+
+	t.Run("rejects an invalid max_age", func(t *testing.T) {
+		for _, maxAge := range []string{"ten", "0s", "-5m"} {
+			req := httptest.NewRequest(http.MethodGet, "/symbols/stale?max_age="+maxAge, nil)
+			rec := httptest.NewRecorder()
+			newRouter(&mockSnapshotService{}).ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusBadRequest, rec.Code, maxAge)
+			assert.Contains(t, rec.Body.String(), "INVALID_MAX_AGE")
+		}
 	})
 }
 
@@ -246,6 +629,128 @@ func TestHandler_CreateSymbol(t *testing.T) {
 
 		assert.Equal(t, http.StatusBadRequest, rec.Code)
 	})
+
+	t.Run("returns 422 when a symbol limit is reached", func(t *testing.T) {
+		handler := httpAdapter.NewHandler(
+			&mockSymbolService{addErr: fmt.Errorf("%w: 50 of 50 active symbols in use", domain.ErrSymbolQuota)},
+			&mockSnapshotService{},
+			&mockMetricsService{},
+			&mockExchangeClient{},
+			newTestLogger(),
+		)
+
+		body := bytes.NewBufferString(`{"symbol": "BTCUSDT"}`)
+		req := httptest.NewRequest(http.MethodPost, "/symbols", body)
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+
+		handler.CreateSymbol(rec, req)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+		assert.JSONEq(t, `{"error": "symbol quota exceeded: 50 of 50 active symbols in use", "code": "SYMBOL_QUOTA_EXCEEDED"}`, rec.Body.String())
+	})
+	t.Run("creates the symbol on the requested exchange", func(t *testing.T) {
+		handler := httpAdapter.NewHandler(
+			&mockSymbolService{},
+			&mockSnapshotService{},
+			&mockMetricsService{},
+			&mockExchangeClient{},
+			newTestLogger(),
+		)
+
+		body := bytes.NewBufferString(`{"symbol": "BTCUSD", "exchange": "binanceus"}`)
+		req := httptest.NewRequest(http.MethodPost, "/symbols", body)
+		rec := httptest.NewRecorder()
+
+		handler.CreateSymbol(rec, req)
+
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		var response domain.Symbol
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Equal(t, "binanceus", response.Exchange)
+	})
+
+	t.Run("returns 400 for an unconfigured exchange", func(t *testing.T) {
+		handler := httpAdapter.NewHandler(
+			&mockSymbolService{addErr: fmt.Errorf("%w: kraken", domain.ErrUnknownExchange)},
+			&mockSnapshotService{},
+			&mockMetricsService{},
+			&mockExchangeClient{},
+			newTestLogger(),
+		)
+
+		body := bytes.NewBufferString(`{"symbol": "BTCUSD", "exchange": "kraken"}`)
+		req := httptest.NewRequest(http.MethodPost, "/symbols", body)
+		rec := httptest.NewRecorder()
+
+		handler.CreateSymbol(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.JSONEq(t, `{"error": "unknown exchange: kraken", "code": "UNKNOWN_EXCHANGE"}`, rec.Body.String())
+	})
+}
+
+func TestHandler_RequestValidation(t *testing.T) {
+	router := httpAdapter.NewRouter(httpAdapter.NewHandler(
+		&mockSymbolService{},
+		&mockSnapshotService{},
+		&mockMetricsService{},
+		&mockExchangeClient{},
+		newTestLogger(),
+		httpAdapter.WithAlerts(&mockAlertService{}),
+	), newTestLogger())
+
+	post := func(target, body string) (*httptest.ResponseRecorder, httpAdapter.ErrorResponse) {
+		req := httptest.NewRequest(http.MethodPost, target, bytes.NewBufferString(body))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		var response httpAdapter.ErrorResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		return rec, response
+	}
+
+	t.Run("lists every missing field", func(t *testing.T) {
+		rec, response := post("/watch", `{"ttl": "6h"}`)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, "INVALID_REQUEST", response.Code)
+		assert.Equal(t, []httpAdapter.FieldError{
+			{Field: "symbol", Error: "is required"},
+			{Field: "price", Error: "is required"},
+			{Field: "webhook_url", Error: "is required"},
+		}, response.Fields)
+	})
+
+	t.Run("rejects a field that is too long", func(t *testing.T) {
+		long := "https://hooks.example.com/" + strings.Repeat("a", 2048)
+		rec, response := post("/watch", `{"symbol": "BTCUSDT", "price": "1", "webhook_url": "`+long+`"}`)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, []httpAdapter.FieldError{
+			{Field: "webhook_url", Error: "must have at most 2048 characters"},
+		}, response.Fields)
+	})
+
+	t.Run("rejects a field of the wrong JSON type", func(t *testing.T) {
+		rec, response := post("/symbols", `{"symbol": 42}`)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, []httpAdapter.FieldError{
+			{Field: "symbol", Error: "must be a JSON string"},
+		}, response.Fields)
+	})
+
+	t.Run("rejects malformed JSON", func(t *testing.T) {
+		for _, body := range []string{``, `{"symbol": "BTCUSDT"`, `{"symbol": "BTCUSDT"} {}`} {
+			rec, response := post("/symbols", body)
+
+			assert.Equal(t, http.StatusBadRequest, rec.Code, body)
+			assert.Equal(t, "INVALID_REQUEST", response.Code, body)
+			assert.Equal(t, "malformed JSON", response.Details, body)
+			assert.Empty(t, response.Fields, body)
+		}
+	})
 }
 
 func TestHandler_ListSymbols(t *testing.T) {
@@ -279,21 +784,139 @@ func TestHandler_ListSymbols(t *testing.T) {
 		assert.Contains(t, response["symbols"], "BTCUSDT")
 		assert.Contains(t, response["symbols"], "ETHUSDT")
 	})
-}
 
-func TestHandler_GetHistory(t *testing.T) {
-	t.Run("returns price history", func(t *testing.T) {
-		now := time.Now()
-		mockSvc := &mockSnapshotService{
-			snapshots: []*domain.PriceSnapshot{
-				{ID: 1, Symbol: "BTCUSDT", Price: decimal.NewFromFloat(43123.45), Timestamp: now},
-				{ID: 2, Symbol: "BTCUSDT", Price: decimal.NewFromFloat(43100.00), Timestamp: now.Add(-time.Minute)},
+	t.Run("pages through symbols with a cursor", func(t *testing.T) {
+		handler := httpAdapter.NewHandler(
+			&mockSymbolService{
+				symbols: []*domain.Symbol{
+					{ID: 1, Name: "BTCUSDT", Active: true},
+					{ID: 2, Name: "ETHUSDT", Active: true},
+					{ID: 3, Name: "SOLUSDT", Active: true},
+				},
 			},
+			&mockSnapshotService{},
+			&mockMetricsService{},
+			&mockExchangeClient{},
+			newTestLogger(),
+		)
+
+		var response struct {
+			Symbols    []string `json:"symbols"`
+			NextCursor *string  `json:"next_cursor"`
 		}
 
+		rec := httptest.NewRecorder()
+		handler.ListSymbols(rec, httptest.NewRequest(http.MethodGet, "/symbols?limit=2", nil))
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Equal(t, []string{"BTCUSDT", "ETHUSDT"}, response.Symbols)
+		require.NotNil(t, response.NextCursor)
+
+		rec = httptest.NewRecorder()
+		handler.ListSymbols(rec, httptest.NewRequest(http.MethodGet, "/symbols?limit=2&cursor="+*response.NextCursor, nil))
+		require.Equal(t, http.StatusOK, rec.Code)
+		response.NextCursor = nil
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Equal(t, []string{"SOLUSDT"}, response.Symbols)
+		assert.Nil(t, response.NextCursor)
+	})
+
+	t.Run("returns 400 for an invalid cursor", func(t *testing.T) {
 		handler := httpAdapter.NewHandler(
 			&mockSymbolService{},
-			mockSvc,
+			&mockSnapshotService{},
+			&mockMetricsService{},
+			&mockExchangeClient{},
+			newTestLogger(),
+		)
+
+		rec := httptest.NewRecorder()
+		handler.ListSymbols(rec, httptest.NewRequest(http.MethodGet, "/symbols?cursor=bogus", nil))
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "INVALID_CURSOR")
+	})
+}
+
+func TestHandler_GetHistoryCursor(t *testing.T) {
+	from := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	newHandler := func(svc *mockSnapshotService) *httpAdapter.Handler {
+		return httpAdapter.NewHandler(
+			&mockSymbolService{},
+			svc,
+			&mockMetricsService{},
+			&mockExchangeClient{},
+			newTestLogger(),
+		)
+	}
+
+	t.Run("returns a cursor continuing after the page", func(t *testing.T) {
+		svc := &mockSnapshotService{
+			snapshots: []*domain.PriceSnapshot{
+				{ID: 8, Symbol: "BTCUSDT", Price: decimal.NewFromInt(43200), Timestamp: from.Add(time.Minute)},
+				{ID: 7, Symbol: "BTCUSDT", Price: decimal.NewFromInt(43100), Timestamp: from},
+			},
+		}
+
+		rec := httptest.NewRecorder()
+		newHandler(svc).GetHistory(rec, httptest.NewRequest(http.MethodGet, "/history?symbol=BTCUSDT&limit=2", nil))
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		var response struct {
+			NextCursor *string `json:"next_cursor"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		require.NotNil(t, response.NextCursor)
+
+		cursor, err := domain.ParseHistoryCursor(*response.NextCursor)
+		require.NoError(t, err)
+		assert.Equal(t, domain.HistoryCursor{Timestamp: from, ID: 7}, *cursor)
+	})
+
+	t.Run("passes the cursor to the query", func(t *testing.T) {
+		svc := &mockSnapshotService{}
+		cursor := domain.HistoryCursor{Timestamp: from, ID: 7}
+
+		rec := httptest.NewRecorder()
+		newHandler(svc).GetHistory(rec, httptest.NewRequest(http.MethodGet, "/history?symbol=BTCUSDT&cursor="+cursor.Encode(), nil))
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.NotNil(t, svc.before)
+		assert.Equal(t, cursor, *svc.before)
+	})
+
+	t.Run("returns 400 for an invalid cursor", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		newHandler(&mockSnapshotService{}).GetHistory(rec, httptest.NewRequest(http.MethodGet, "/history?symbol=BTCUSDT&cursor=bogus", nil))
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "INVALID_CURSOR")
+	})
+
+	t.Run("returns 400 for a cursor with points", func(t *testing.T) {
+		cursor := domain.HistoryCursor{Timestamp: from, ID: 7}
+
+		rec := httptest.NewRecorder()
+		newHandler(&mockSnapshotService{}).GetHistory(rec, httptest.NewRequest(http.MethodGet, "/history?symbol=BTCUSDT&points=10&cursor="+cursor.Encode(), nil))
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "INVALID_CURSOR")
+	})
+}
+
+func TestHandler_GetHistory(t *testing.T) {
+	t.Run("returns price history", func(t *testing.T) {
+		now := time.Now()
+		mockSvc := &mockSnapshotService{
+			snapshots: []*domain.PriceSnapshot{
+				{ID: 1, Symbol: "BTCUSDT", Price: decimal.NewFromFloat(43123.45), Timestamp: now},
+				{ID: 2, Symbol: "BTCUSDT", Price: decimal.NewFromFloat(43100.00), Timestamp: now.Add(-time.Minute)},
+			},
+		}
+
+		handler := httpAdapter.NewHandler(
+			&mockSymbolService{},
+			mockSvc,
 			&mockMetricsService{},
 			&mockExchangeClient{},
 			newTestLogger(),
@@ -312,6 +935,92 @@ func TestHandler_GetHistory(t *testing.T) {
 		assert.Equal(t, "BTCUSDT", response["symbol"])
 		items := response["items"].([]interface{})
 		assert.Len(t, items, 2)
+		assert.Equal(t, float64(2), response["count"])
+		assert.Equal(t, float64(2), response["total_count"])
+		assert.Equal(t, now.Add(-time.Minute).UTC().Format(time.RFC3339), response["oldest"])
+		assert.Equal(t, now.UTC().Format(time.RFC3339), response["newest"])
+	})
+
+	t.Run("reports more history past a full page", func(t *testing.T) {
+		from := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+		mockSvc := &mockSnapshotService{
+			snapshots: []*domain.PriceSnapshot{
+				{Symbol: "BTCUSDT", Price: decimal.NewFromInt(43200), Timestamp: from.Add(2 * time.Minute)},
+				{Symbol: "BTCUSDT", Price: decimal.NewFromInt(43100), Timestamp: from.Add(time.Minute)},
+			},
+		}
+
+		handler := httpAdapter.NewHandler(
+			&mockSymbolService{},
+			mockSvc,
+			&mockMetricsService{},
+			&mockExchangeClient{},
+			newTestLogger(),
+		)
+
+		req := httptest.NewRequest(http.MethodGet, "/history?symbol=BTCUSDT&limit=2&from=2024-01-15T10:00:00Z", nil)
+		rec := httptest.NewRecorder()
+
+		handler.GetHistory(rec, req)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		var response struct {
+			Limit    int     `json:"limit"`
+			From     *string `json:"from"`
+			To       *string `json:"to"`
+			Count    int     `json:"count"`
+			HasMore  bool    `json:"has_more"`
+			OldestTs string  `json:"oldest_ts"`
+			NewestTs string  `json:"newest_ts"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Equal(t, 2, response.Limit)
+		require.NotNil(t, response.From)
+		assert.Equal(t, "2024-01-15T10:00:00Z", *response.From)
+		assert.Nil(t, response.To)
+		assert.Equal(t, 2, response.Count)
+		assert.True(t, response.HasMore)
+		assert.Equal(t, "2024-01-15T10:01:00Z", response.OldestTs)
+		assert.Equal(t, "2024-01-15T10:02:00Z", response.NewestTs)
+
+		// The probe for older snapshots continues after the page's oldest
+		assert.Equal(t, from, mockSvc.from)
+		require.NotNil(t, mockSvc.before)
+		assert.Equal(t, from.Add(time.Minute), mockSvc.before.Timestamp)
+	})
+
+	t.Run("returns empty items for a tracked symbol without snapshots", func(t *testing.T) {
+		handler := httpAdapter.NewHandler(
+			&mockSymbolService{},
+			&mockSnapshotService{},
+			&mockMetricsService{},
+			&mockExchangeClient{},
+			newTestLogger(),
+		)
+
+		req := httptest.NewRequest(http.MethodGet, "/history?symbol=BTCUSDT", nil)
+		rec := httptest.NewRecorder()
+
+		handler.GetHistory(rec, req)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{
+			"symbol": "BTCUSDT",
+			"source": "raw",
+			"limit": 100,
+			"from": null,
+			"to": null,
+			"order": "desc",
+			"count": 0,
+			"has_more": false,
+			"next_cursor": null,
+			"oldest_ts": null,
+			"newest_ts": null,
+			"total_count": 0,
+			"oldest": null,
+			"newest": null,
+			"items": []
+		}`, rec.Body.String())
 	})
 
 	t.Run("returns 400 for missing symbol", func(t *testing.T) {
@@ -349,28 +1058,2878 @@ func TestHandler_GetHistory(t *testing.T) {
 	})
 }
 
-func TestHandler_GetMetrics(t *testing.T) {
-	t.Run("returns metrics", func(t *testing.T) {
+func TestHandler_GetHistorySampling(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	var snapshots []*domain.PriceSnapshot
+	for i := 0; i < 1000; i++ {
+		snapshots = append(snapshots, &domain.PriceSnapshot{
+			Symbol:    "BTCUSDT",
+			Price:     decimal.NewFromInt(int64(43000 + i%10)),
+			Timestamp: now.Add(-time.Duration(i) * time.Second),
+		})
+	}
+
+	newHandler := func() http.HandlerFunc {
+		return httpAdapter.NewHandler(
+			&mockSymbolService{},
+			&mockSnapshotService{snapshots: snapshots},
+			&mockMetricsService{},
+			&mockExchangeClient{},
+			newTestLogger(),
+		).GetHistory
+	}
+
+	t.Run("downsamples to the requested points", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/history?symbol=BTCUSDT&points=50&algorithm=lttb", nil)
+		rec := httptest.NewRecorder()
+
+		newHandler()(rec, req)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		var response struct {
+			Sampling domain.HistorySampling `json:"sampling"`
+			Items    []struct {
+				Ts string `json:"ts"`
+			} `json:"items"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Equal(t, domain.HistorySampling{Algorithm: domain.SamplingLTTB, Points: 50, Rows: 1000}, response.Sampling)
+		require.Len(t, response.Items, 50)
+		assert.Equal(t, now.UTC().Format(time.RFC3339), response.Items[0].Ts)
+	})
+
+	t.Run("rejects invalid parameters", func(t *testing.T) {
+		for _, query := range []string{"points=2", "points=many", "points=50&algorithm=minmax", "algorithm=lttb"} {
+			req := httptest.NewRequest(http.MethodGet, "/history?symbol=BTCUSDT&"+query, nil)
+			rec := httptest.NewRecorder()
+
+			newHandler()(rec, req)
+
+			assert.Equal(t, http.StatusBadRequest, rec.Code, query)
+			assert.Contains(t, rec.Body.String(), "INVALID_SAMPLING", query)
+		}
+	})
+}
+
+func TestHandler_GetHistoryBuckets(t *testing.T) {
+	from := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	// Snapshots in the first and last of four minutes, newest first
+	snapshots := []*domain.PriceSnapshot{
+		{Symbol: "BTCUSDT", Price: decimal.NewFromInt(43300), Timestamp: from.Add(3*time.Minute + 30*time.Second)},
+		{Symbol: "BTCUSDT", Price: decimal.NewFromInt(43020), Timestamp: from.Add(40 * time.Second)},
+		{Symbol: "BTCUSDT", Price: decimal.NewFromInt(42980), Timestamp: from.Add(10 * time.Second)},
+	}
+
+	const window = "from=2024-01-15T10:00:00Z&to=2024-01-15T10:04:00Z&"
+	get := func(query string) *httptest.ResponseRecorder {
 		handler := httpAdapter.NewHandler(
 			&mockSymbolService{},
-			&mockSnapshotService{},
+			&mockSnapshotService{snapshots: snapshots},
 			&mockMetricsService{},
 			&mockExchangeClient{},
 			newTestLogger(),
 		)
+		req := httptest.NewRequest(http.MethodGet, "/history?symbol=BTCUSDT&"+query, nil)
+		rec := httptest.NewRecorder()
+		handler.GetHistory(rec, req)
+		return rec
+	}
 
-		req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	type item struct {
+		Price  string `json:"price"`
+		Ts     string `json:"ts"`
+		Filled bool   `json:"filled"`
+	}
+	var response struct {
+		Buckets domain.HistoryBuckets `json:"buckets"`
+		Items   []item                `json:"items"`
+	}
+
+	t.Run("linear fill flags interpolated buckets", func(t *testing.T) {
+		rec := get(window + "step=1m&fill=linear")
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Equal(t, domain.HistoryBuckets{Step: "1m0s", Fill: domain.FillLinear, Gaps: 2, Filled: 2}, response.Buckets)
+		assert.Equal(t, []item{
+			{Price: "43300", Ts: "2024-01-15T10:03:00Z"},
+			{Price: "43200", Ts: "2024-01-15T10:02:00Z", Filled: true},
+			{Price: "43100", Ts: "2024-01-15T10:01:00Z", Filled: true},
+			{Price: "43000", Ts: "2024-01-15T10:00:00Z"},
+		}, response.Items)
+	})
+
+	t.Run("no fill leaves gaps out", func(t *testing.T) {
+		rec := get(window + "step=1m")
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Equal(t, domain.FillNone, response.Buckets.Fill)
+		assert.Equal(t, 2, response.Buckets.Gaps)
+		assert.Zero(t, response.Buckets.Filled)
+		assert.Len(t, response.Items, 2)
+	})
+
+	t.Run("csv has a filled column", func(t *testing.T) {
+		rec := get(window + "step=1m&fill=prev&format=csv")
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), "BTCUSDT,43000,,2024-01-15T10:01:00Z,true")
+	})
+
+	t.Run("rejects invalid parameters", func(t *testing.T) {
+		for _, query := range []string{
+			window + "fill=prev",
+			window + "step=1ms",
+			window + "step=1m&fill=spline",
+			window + "step=1m&points=50",
+			"to=2024-01-15T10:04:00Z&step=1m",
+			"from=2024-01-14T10:00:00Z&to=2024-01-15T10:04:00Z&step=1s",
+			"from=2024-01-15T10:00:00Z&to=2024-01-15T10:00:00Z&step=1m",
+		} {
+			rec := get(query)
+
+			assert.Equal(t, http.StatusBadRequest, rec.Code, query)
+			assert.Contains(t, rec.Body.String(), "INVALID_STEP", query)
+		}
+	})
+}
+
+func TestHandler_GetHistoryRollup(t *testing.T) {
+	from := time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)
+	snapshots := []*domain.PriceSnapshot{
+		{Symbol: "BTCUSDT", Price: decimal.NewFromInt(43000), Timestamp: from.Add(time.Hour)},
+	}
+
+	get := func(candles *mockCandleService, query string) *httptest.ResponseRecorder {
+		handler := httpAdapter.NewHandler(
+			&mockSymbolService{},
+			&mockSnapshotService{snapshots: snapshots},
+			&mockMetricsService{},
+			&mockExchangeClient{},
+			newTestLogger(),
+			httpAdapter.WithCandles(candles),
+		)
+		req := httptest.NewRequest(http.MethodGet, "/history?symbol=BTCUSDT&"+query, nil)
 		rec := httptest.NewRecorder()
+		handler.GetHistory(rec, req)
+		return rec
+	}
 
-		handler.GetMetrics(rec, req)
+	var response struct {
+		Source domain.HistorySource `json:"source"`
+		Items  []struct {
+			Price string `json:"price"`
+			Ts    string `json:"ts"`
+		} `json:"items"`
+	}
+
+	t.Run("averages long ranges from candle closes", func(t *testing.T) {
+		candles := &mockCandleService{candles: []*domain.Candle{
+			{Symbol: "BTCUSDT", OpenTime: from, Close: decimal.NewFromInt(42000)},
+			{Symbol: "BTCUSDT", OpenTime: from.Add(time.Hour), Close: decimal.NewFromInt(44000)},
+			{Symbol: "BTCUSDT", OpenTime: from.Add(24 * time.Hour), Close: decimal.NewFromInt(45000)},
+		}}
+		rec := get(candles, "from=2024-01-10T00:00:00Z&to=2024-01-12T00:00:00Z&step=24h")
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Equal(t, domain.HistorySourceRollup, response.Source)
+		assert.Equal(t, domain.CandleInterval1d, candles.interval, "the coarsest interval dividing the step")
+		require.Len(t, response.Items, 2)
+		assert.Equal(t, "45000", response.Items[0].Price)
+		assert.Equal(t, "43000", response.Items[1].Price)
+	})
+
+	t.Run("picks an interval the range is aligned to", func(t *testing.T) {
+		candles := &mockCandleService{candles: []*domain.Candle{
+			{Symbol: "BTCUSDT", OpenTime: from.Add(30 * time.Minute), Close: decimal.NewFromInt(42000)},
+		}}
+		rec := get(candles, "from=2024-01-10T00:30:00Z&to=2024-01-12T00:30:00Z&step=24h")
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Equal(t, domain.HistorySourceRollup, response.Source)
+		assert.Equal(t, domain.CandleInterval5m, candles.interval)
+	})
+
+	t.Run("reads short ranges from snapshots", func(t *testing.T) {
+		candles := &mockCandleService{}
+		rec := get(candles, "from=2024-01-10T00:00:00Z&to=2024-01-10T12:00:00Z&step=1h")
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Equal(t, domain.HistorySourceRaw, response.Source)
+		assert.Empty(t, candles.interval)
+	})
+
+	t.Run("falls back to snapshots without candles", func(t *testing.T) {
+		rec := get(&mockCandleService{}, "from=2024-01-10T00:00:00Z&to=2024-01-12T00:00:00Z&step=1h")
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Equal(t, domain.HistorySourceRaw, response.Source)
+		require.Len(t, response.Items, 1)
+		assert.Equal(t, "43000", response.Items[0].Price)
+	})
+}
+
+func TestHandler_GetHistoryTimezone(t *testing.T) {
+	newHandler := func() *httpAdapter.Handler {
+		return httpAdapter.NewHandler(
+			&mockSymbolService{},
+			&mockSnapshotService{snapshots: []*domain.PriceSnapshot{
+				{Symbol: "BTCUSDT", Price: decimal.RequireFromString("43123.45"), Timestamp: time.Date(2024, 7, 15, 21, 30, 0, 0, time.UTC)},
+			}},
+			&mockMetricsService{},
+			&mockExchangeClient{},
+			newTestLogger(),
+		)
+	}
+
+	t.Run("adds local timestamps next to UTC", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/history?symbol=BTCUSDT&tz=Europe/Kyiv", nil)
+		rec := httptest.NewRecorder()
+
+		newHandler().GetHistory(rec, req)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"symbol": "BTCUSDT", "source": "raw", "tz": "Europe/Kyiv",
+			"limit": 100, "from": null, "to": null, "order": "desc", "count": 1, "has_more": false, "next_cursor": null,
+			"oldest_ts": "2024-07-15T21:30:00Z", "newest_ts": "2024-07-15T21:30:00Z",
+			"total_count": 1, "oldest": "2024-07-15T21:30:00Z", "newest": "2024-07-15T21:30:00Z", "items": [
+			{"price": "43123.45", "ts": "2024-07-15T21:30:00Z", "ts_local": "2024-07-16T00:30:00+03:00"}
+		]}`, rec.Body.String())
+	})
+
+	t.Run("adds a local time CSV column only with a zone", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/history?symbol=BTCUSDT&fields=ts,ts_local&format=csv", nil)
+		rec := httptest.NewRecorder()
+		newHandler().GetHistory(rec, req)
+		assert.Equal(t, "ts\n2024-07-15T21:30:00Z\n", rec.Body.String())
+
+		req = httptest.NewRequest(http.MethodGet, "/history?symbol=BTCUSDT&fields=ts,ts_local&format=csv&tz=America/New_York", nil)
+		rec = httptest.NewRecorder()
+		newHandler().GetHistory(rec, req)
+		assert.Equal(t, "ts,ts_local\n2024-07-15T21:30:00Z,2024-07-15T17:30:00-04:00\n", rec.Body.String())
+	})
+
+	t.Run("returns 400 for an unknown zone", func(t *testing.T) {
+		for _, tz := range []string{"Mars/Olympus", "Local"} {
+			req := httptest.NewRequest(http.MethodGet, "/history?symbol=BTCUSDT&tz="+tz, nil)
+			rec := httptest.NewRecorder()
+
+			newHandler().GetHistory(rec, req)
+
+			assert.Equal(t, http.StatusBadRequest, rec.Code, tz)
+			assert.Contains(t, rec.Body.String(), "INVALID_TIMEZONE", tz)
+		}
+	})
+}
+
+func TestHandler_GetHistoryOrder(t *testing.T) {
+	base := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	newHandler := func(svc *mockSnapshotService) *httpAdapter.Handler {
+		return httpAdapter.NewHandler(&mockSymbolService{}, svc, &mockMetricsService{}, &mockExchangeClient{}, newTestLogger())
+	}
+
+	t.Run("returns the window oldest first", func(t *testing.T) {
+		svc := &mockSnapshotService{snapshots: []*domain.PriceSnapshot{
+			{Symbol: "BTCUSDT", Price: decimal.RequireFromString("43002"), Timestamp: base.Add(2 * time.Minute)},
+			{Symbol: "BTCUSDT", Price: decimal.RequireFromString("43001"), Timestamp: base.Add(time.Minute)},
+		}}
+		req := httptest.NewRequest(http.MethodGet, "/history?symbol=BTCUSDT&from=2024-01-15T10:00:00Z&to=2024-01-15T11:00:00Z&order=asc", nil)
+		rec := httptest.NewRecorder()
+
+		newHandler(svc).GetHistory(rec, req)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, base, svc.from)
+		assert.Equal(t, base.Add(time.Hour), svc.to)
+
+		var response httpAdapter.HistoryResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.EqualValues(t, "asc", response.Order)
+		require.Len(t, response.Items, 2)
+		assert.Equal(t, "43001", response.Items[0].Price)
+		assert.Equal(t, "43002", response.Items[1].Price)
+		assert.Equal(t, "2024-01-15T10:01:00Z", *response.OldestTs)
+	})
+
+	t.Run("returns 400 for an unknown order", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/history?symbol=BTCUSDT&order=newest", nil)
+		rec := httptest.NewRecorder()
+
+		newHandler(&mockSnapshotService{}).GetHistory(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "INVALID_ORDER")
+	})
+}
+
+func TestHandler_GetHistoryCaching(t *testing.T) {
+	newest := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	newHandler := func(svc *mockSnapshotService) *httpAdapter.Handler {
+		return httpAdapter.NewHandler(
+			&mockSymbolService{},
+			svc,
+			&mockMetricsService{},
+			&mockExchangeClient{},
+			newTestLogger(),
+		)
+	}
+	closedWindow := func() *mockSnapshotService {
+		return &mockSnapshotService{
+			snapshots: []*domain.PriceSnapshot{
+				{Symbol: "BTCUSDT", Price: decimal.NewFromInt(43100), Timestamp: newest.Add(-time.Minute)},
+				{Symbol: "BTCUSDT", Price: decimal.NewFromInt(43123), Timestamp: newest.Add(500 * time.Millisecond)},
+			},
+		}
+	}
+	const closedURL = "/history?symbol=BTCUSDT&from=2024-01-15T00:00:00Z&to=2024-01-16T00:00:00Z"
+
+	t.Run("caches a closed range", func(t *testing.T) {
+		svc := closedWindow()
+		req := httptest.NewRequest(http.MethodGet, closedURL, nil)
+		rec := httptest.NewRecorder()
+
+		newHandler(svc).GetHistory(rec, req)
 
 		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "public, max-age=86400, immutable", rec.Header().Get("Cache-Control"))
+		assert.Equal(t, "Mon, 15 Jan 2024 10:30:00 GMT", rec.Header().Get("Last-Modified"))
+		assert.Equal(t, time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC), svc.from)
+		assert.Equal(t, time.Date(2024, 1, 16, 0, 0, 0, 0, time.UTC), svc.to)
+	})
 
-		var response domain.Metrics
-		err := json.Unmarshal(rec.Body.Bytes(), &response)
-		require.NoError(t, err)
-		assert.Equal(t, float64(3600), response.Uptime)
-		assert.Equal(t, 5, response.TrackedSymbols)
-		assert.Equal(t, "healthy", response.DatabaseStatus)
+	t.Run("returns 304 when the copy is current", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, closedURL, nil)
+		req.Header.Set("If-Modified-Since", "Mon, 15 Jan 2024 10:30:00 GMT")
+		rec := httptest.NewRecorder()
+
+		newHandler(closedWindow()).GetHistory(rec, req)
+
+		assert.Equal(t, http.StatusNotModified, rec.Code)
+		assert.Empty(t, rec.Body.String())
+	})
+
+	t.Run("returns the window when the copy is stale", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, closedURL, nil)
+		req.Header.Set("If-Modified-Since", "Mon, 15 Jan 2024 10:29:59 GMT")
+		rec := httptest.NewRecorder()
+
+		newHandler(closedWindow()).GetHistory(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("keeps a range without an end uncached", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/history?symbol=BTCUSDT&from=2024-01-15T00:00:00Z", nil)
+		rec := httptest.NewRecorder()
+
+		newHandler(closedWindow()).GetHistory(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "no-store", rec.Header().Get("Cache-Control"))
+		assert.Empty(t, rec.Header().Get("Last-Modified"))
+	})
+
+	t.Run("keeps a range ending in the future uncached", func(t *testing.T) {
+		to := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+		req := httptest.NewRequest(http.MethodGet, "/history?symbol=BTCUSDT&to="+to, nil)
+		rec := httptest.NewRecorder()
+
+		newHandler(closedWindow()).GetHistory(rec, req)
+
+		assert.Equal(t, "no-store", rec.Header().Get("Cache-Control"))
+	})
+
+	t.Run("caches a window that just ended only briefly", func(t *testing.T) {
+		to := time.Now().Add(-time.Millisecond).UTC().Format(time.RFC3339Nano)
+		req := httptest.NewRequest(http.MethodGet, "/history?symbol=BTCUSDT&from=2024-01-15T00:00:00Z&to="+to, nil)
+		rec := httptest.NewRecorder()
+
+		newHandler(closedWindow()).GetHistory(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "public, max-age=10", rec.Header().Get("Cache-Control"))
+		assert.Empty(t, rec.Header().Get("Last-Modified"))
+	})
+
+	t.Run("waits for the configured grace before caching for good", func(t *testing.T) {
+		to := time.Now().Add(-10 * time.Minute).UTC().Format(time.RFC3339)
+		url := "/history?symbol=BTCUSDT&from=2024-01-15T00:00:00Z&to=" + to
+
+		rec := httptest.NewRecorder()
+		newHandler(closedWindow()).GetHistory(rec, httptest.NewRequest(http.MethodGet, url, nil))
+		assert.Equal(t, "public, max-age=86400, immutable", rec.Header().Get("Cache-Control"))
+
+		handler := httpAdapter.NewHandler(
+			&mockSymbolService{},
+			closedWindow(),
+			&mockMetricsService{},
+			&mockExchangeClient{},
+			newTestLogger(),
+			httpAdapter.WithHistoryCacheGrace(time.Hour),
+		)
+		rec = httptest.NewRecorder()
+		handler.GetHistory(rec, httptest.NewRequest(http.MethodGet, url, nil))
+		assert.Equal(t, "public, max-age=10", rec.Header().Get("Cache-Control"))
+	})
+
+	t.Run("keeps the latest history uncached", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/history?symbol=BTCUSDT", nil)
+		rec := httptest.NewRecorder()
+
+		newHandler(closedWindow()).GetHistory(rec, req)
+
+		assert.Equal(t, "no-store", rec.Header().Get("Cache-Control"))
+	})
+
+	t.Run("omits Last-Modified for an empty closed range", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, closedURL, nil)
+		rec := httptest.NewRecorder()
+
+		newHandler(&mockSnapshotService{}).GetHistory(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "public, max-age=86400, immutable", rec.Header().Get("Cache-Control"))
+		assert.Empty(t, rec.Header().Get("Last-Modified"))
+	})
+
+	t.Run("returns 400 for an unparsable bound", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/history?symbol=BTCUSDT&to=yesterday", nil)
+		rec := httptest.NewRecorder()
+
+		newHandler(closedWindow()).GetHistory(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "INVALID_TIME_RANGE")
+	})
+}
+
+func TestHandler_GetMetrics(t *testing.T) {
+	t.Run("returns metrics", func(t *testing.T) {
+		handler := httpAdapter.NewHandler(
+			&mockSymbolService{},
+			&mockSnapshotService{},
+			&mockMetricsService{},
+			&mockExchangeClient{},
+			newTestLogger(),
+		)
+
+		req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+		rec := httptest.NewRecorder()
+
+		handler.GetMetrics(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var response domain.Metrics
+		err := json.Unmarshal(rec.Body.Bytes(), &response)
+		require.NoError(t, err)
+		assert.Equal(t, float64(3600), response.Uptime)
+		assert.Equal(t, 5, response.TrackedSymbols)
+		assert.Equal(t, "healthy", response.DatabaseStatus)
+	})
+}
+
+func TestHandler_GetPrices_PriceFormat(t *testing.T) {
+	now := time.Now()
+	snapshots := []*domain.PriceSnapshot{
+		{ID: 1, Symbol: "BTCUSDT", Price: decimal.RequireFromString("43123.45"), Timestamp: now},
+	}
+
+	t.Run("serializes prices as strings by default", func(t *testing.T) {
+		handler := httpAdapter.NewHandler(
+			&mockSymbolService{},
+			&mockSnapshotService{snapshots: snapshots},
+			&mockMetricsService{},
+			&mockExchangeClient{},
+			newTestLogger(),
+		)
+
+		req := httptest.NewRequest(http.MethodGet, "/prices?symbols=BTCUSDT", nil)
+		rec := httptest.NewRecorder()
+
+		handler.GetPrices(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"price":"43123.45"`)
+		assert.NotContains(t, rec.Body.String(), "price_num")
+	})
+
+	t.Run("serializes prices as fixed-decimal numbers", func(t *testing.T) {
+		handler := httpAdapter.NewHandler(
+			&mockSymbolService{},
+			&mockSnapshotService{snapshots: snapshots},
+			&mockMetricsService{},
+			&mockExchangeClient{},
+			newTestLogger(),
+			httpAdapter.WithPriceFormat(httpAdapter.PriceFormatNumber, 2),
+		)
+
+		req := httptest.NewRequest(http.MethodGet, "/prices?symbols=BTCUSDT", nil)
+		rec := httptest.NewRecorder()
+
+		handler.GetPrices(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"price":43123.45`)
+	})
+
+	t.Run("serializes both string and number fields", func(t *testing.T) {
+		handler := httpAdapter.NewHandler(
+			&mockSymbolService{},
+			&mockSnapshotService{snapshots: snapshots},
+			&mockMetricsService{},
+			&mockExchangeClient{},
+			newTestLogger(),
+			httpAdapter.WithPriceFormat(httpAdapter.PriceFormatBoth, 4),
+		)
+
+		req := httptest.NewRequest(http.MethodGet, "/prices?symbols=BTCUSDT", nil)
+		rec := httptest.NewRecorder()
+
+		handler.GetPrices(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"price":"43123.45"`)
+		assert.Contains(t, rec.Body.String(), `"price_num":43123.4500`)
+	})
+}
+
+func TestHandler_ContentNegotiation(t *testing.T) {
+	now := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	newHandler := func() *httpAdapter.Handler {
+		return httpAdapter.NewHandler(
+			&mockSymbolService{},
+			&mockSnapshotService{snapshots: []*domain.PriceSnapshot{
+				{ID: 1, Symbol: "BTCUSDT", Price: decimal.RequireFromString("43123.45"), Timestamp: now},
+				{ID: 2, Symbol: "BTCUSDT", Price: decimal.RequireFromString("43100"), Timestamp: now.Add(-time.Minute)},
+			}},
+			&mockMetricsService{},
+			&mockExchangeClient{},
+			newTestLogger(),
+		)
+	}
+
+	t.Run("renders CSV from format parameter", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/history?symbol=BTCUSDT&format=csv", nil)
+		rec := httptest.NewRecorder()
+
+		newHandler().GetHistory(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "text/csv", rec.Header().Get("Content-Type"))
+		assert.Equal(t,
+			"symbol,price,price_num,ts\n"+
+				"BTCUSDT,43123.45,,2024-01-15T10:30:00Z\n"+
+				"BTCUSDT,43100,,2024-01-15T10:29:00Z\n",
+			rec.Body.String())
+	})
+
+	t.Run("renders NDJSON from Accept header", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/prices?symbols=BTCUSDT", nil)
+		req.Header.Set("Accept", "text/html;q=0.9, application/x-ndjson")
+		rec := httptest.NewRecorder()
+
+		newHandler().GetPrices(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/x-ndjson", rec.Header().Get("Content-Type"))
+		lines := bytes.Split(bytes.TrimSpace(rec.Body.Bytes()), []byte("\n"))
+		assert.Len(t, lines, 2)
+	})
+
+	t.Run("falls back to JSON for unsupported Accept", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/prices?symbols=BTCUSDT", nil)
+		req.Header.Set("Accept", "text/html")
+		rec := httptest.NewRecorder()
+
+		newHandler().GetPrices(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	})
+
+	t.Run("returns 406 for unknown format", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/prices?symbols=BTCUSDT&format=xml", nil)
+		rec := httptest.NewRecorder()
+
+		newHandler().GetPrices(rec, req)
+
+		assert.Equal(t, http.StatusNotAcceptable, rec.Code)
+	})
+
+	t.Run("returns 406 for CSV of non-tabular response", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/metrics?format=csv", nil)
+		rec := httptest.NewRecorder()
+
+		newHandler().GetMetrics(rec, req)
+
+		assert.Equal(t, http.StatusNotAcceptable, rec.Code)
+	})
+
+	t.Run("renders metrics in Prometheus format", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/metrics?format=prometheus", nil)
+		rec := httptest.NewRecorder()
+
+		newHandler().GetMetrics(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "text/plain; version=0.0.4; charset=utf-8", rec.Header().Get("Content-Type"))
+		assert.Contains(t, rec.Body.String(), `price_snapshot_polls_total{result="success"} 100`)
+	})
+
+	t.Run("negotiates OpenMetrics from Accept", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+		req.Header.Set("Accept", "application/openmetrics-text; version=1.0.0, text/plain; q=0.5")
+		rec := httptest.NewRecorder()
+
+		newHandler().GetMetrics(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/openmetrics-text; version=1.0.0; charset=utf-8", rec.Header().Get("Content-Type"))
+		assert.Contains(t, rec.Body.String(), "# EOF\n")
+	})
+
+	t.Run("serves Prometheus format at /metrics/prometheus", func(t *testing.T) {
+		router := httpAdapter.NewRouter(newHandler(), newTestLogger())
+
+		req := httptest.NewRequest(http.MethodGet, "/metrics/prometheus", nil)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "text/plain; version=0.0.4; charset=utf-8", rec.Header().Get("Content-Type"))
+		assert.Contains(t, rec.Body.String(), `price_snapshot_polls_total{result="success"} 100`)
+
+		req = httptest.NewRequest(http.MethodGet, "/metrics/prometheus", nil)
+		req.Header.Set("Accept", "application/openmetrics-text; version=1.0.0")
+		rec = httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, "application/openmetrics-text; version=1.0.0; charset=utf-8", rec.Header().Get("Content-Type"))
+	})
+
+	t.Run("returns 406 for Prometheus format of other responses", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/prices?symbols=BTCUSDT&format=prometheus", nil)
+		rec := httptest.NewRecorder()
+
+		newHandler().GetPrices(rec, req)
+
+		assert.Equal(t, http.StatusNotAcceptable, rec.Code)
+	})
+}
+
+type mockPrecisionService struct {
+	precisions map[string]*domain.SymbolPrecision
+	calls      int
+}
+
+func (m *mockPrecisionService) GetPrecisions(ctx context.Context, symbols []string) map[string]*domain.SymbolPrecision {
+	m.calls++
+	return m.precisions
+}
+
+func TestHandler_PricePrecision(t *testing.T) {
+	now := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	newHandler := func(precision *mockPrecisionService) *httpAdapter.Handler {
+		return httpAdapter.NewHandler(
+			&mockSymbolService{},
+			&mockSnapshotService{snapshots: []*domain.PriceSnapshot{
+				{ID: 1, Symbol: "BTCUSDT", Price: decimal.RequireFromString("43123.45"), Timestamp: now},
+				{ID: 2, Symbol: "NEWUSDT", Price: decimal.RequireFromString("1.5"), Timestamp: now},
+			}},
+			&mockMetricsService{},
+			&mockExchangeClient{},
+			newTestLogger(),
+			httpAdapter.WithPrecision(precision),
+		)
+	}
+	precision := func() *mockPrecisionService {
+		return &mockPrecisionService{precisions: map[string]*domain.SymbolPrecision{
+			"BTCUSDT": {Symbol: "BTCUSDT", TickSize: decimal.RequireFromString("0.01"), DisplayDecimals: 2},
+		}}
+	}
+
+	t.Run("adds tick size and display decimals", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/prices?symbols=BTCUSDT,NEWUSDT&fields=symbol,price,ts,tick_size,display_decimals", nil)
+		rec := httptest.NewRecorder()
+
+		newHandler(precision()).GetPrices(rec, req)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"prices": [
+			{"symbol": "BTCUSDT", "price": "43123.45", "ts": "2024-01-15T10:30:00Z", "tick_size": "0.01", "display_decimals": 2},
+			{"symbol": "NEWUSDT", "price": "1.5", "ts": "2024-01-15T10:30:00Z"}
+		]}`, rec.Body.String())
+	})
+
+	t.Run("renders hints as CSV columns", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/prices?symbols=BTCUSDT&fields=symbol,tick_size,display_decimals&format=csv", nil)
+		rec := httptest.NewRecorder()
+
+		newHandler(precision()).GetPrices(rec, req)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t,
+			"symbol,tick_size,display_decimals\n"+
+				"BTCUSDT,0.01,2\n"+
+				"NEWUSDT,,\n",
+			rec.Body.String())
+	})
+
+	t.Run("skips lookup when no hint is selected", func(t *testing.T) {
+		svc := precision()
+		req := httptest.NewRequest(http.MethodGet, "/prices?symbols=BTCUSDT&fields=symbol,price", nil)
+		rec := httptest.NewRecorder()
+
+		newHandler(svc).GetPrices(rec, req)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, 0, svc.calls)
+		assert.NotContains(t, rec.Body.String(), "tick_size")
+	})
+}
+
+func TestHandler_GetPrices_Previous(t *testing.T) {
+	now := time.Now().UTC()
+	fetchLatency := int64(212)
+	newHandler := func() *httpAdapter.Handler {
+		return httpAdapter.NewHandler(
+			&mockSymbolService{},
+			&mockSnapshotService{
+				snapshots: []*domain.PriceSnapshot{
+					{Symbol: "BTCUSDT", Price: decimal.RequireFromString("43123.45"), Timestamp: now.Add(-1500 * time.Millisecond), FetchLatencyMs: &fetchLatency, Exchange: "binance"},
+					{Symbol: "ETHUSDT", Price: decimal.RequireFromString("2345.67"), Timestamp: now},
+				},
+				previous: map[string]*domain.PriceSnapshot{
+					"BTCUSDT": {Symbol: "BTCUSDT", Price: decimal.RequireFromString("43000"), Timestamp: now.Add(-time.Minute)},
+				},
+			},
+			&mockMetricsService{},
+			&mockExchangeClient{},
+			newTestLogger(),
+		)
+	}
+
+	t.Run("reports the age, fetch latency and exchange of each price", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/prices?symbols=BTCUSDT,ETHUSDT", nil)
+		rec := httptest.NewRecorder()
+
+		newHandler().GetPrices(rec, req)
+
+		var response struct {
+			Prices []map[string]interface{} `json:"prices"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		require.Len(t, response.Prices, 2)
+		assert.GreaterOrEqual(t, response.Prices[0]["ts_age_ms"], float64(1500))
+		assert.Equal(t, float64(212), response.Prices[0]["fetch_latency_ms"])
+		assert.NotContains(t, response.Prices[1], "fetch_latency_ms")
+		assert.Equal(t, "binance", response.Prices[0]["exchange"])
+		assert.NotContains(t, response.Prices[1], "exchange")
+		assert.NotContains(t, response.Prices[0], "prev_price")
+	})
+
+	t.Run("adds the previous price and delta", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/prices?symbols=BTCUSDT,ETHUSDT&include=previous", nil)
+		rec := httptest.NewRecorder()
+
+		newHandler().GetPrices(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var response struct {
+			Prices []map[string]interface{} `json:"prices"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		require.Len(t, response.Prices, 2)
+
+		btc := response.Prices[0]
+		assert.Equal(t, "43000", btc["prev_price"])
+		assert.Equal(t, now.Add(-time.Minute).Format(time.RFC3339), btc["prev_ts"])
+		assert.Equal(t, "123.45", btc["delta"])
+		assert.Equal(t, "0.29", btc["delta_pct"])
+
+		// A single snapshot has nothing to compare with
+		assert.NotContains(t, response.Prices[1], "prev_price")
+	})
+
+	t.Run("adds previous columns to CSV only when included", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/prices?symbols=BTCUSDT&fields=symbol,delta_pct&format=csv", nil)
+		rec := httptest.NewRecorder()
+		newHandler().GetPrices(rec, req)
+		assert.Equal(t, "symbol\nBTCUSDT\nETHUSDT\n", rec.Body.String())
+
+		req = httptest.NewRequest(http.MethodGet, "/prices?symbols=BTCUSDT&fields=symbol,delta_pct&format=csv&include=previous", nil)
+		rec = httptest.NewRecorder()
+		newHandler().GetPrices(rec, req)
+		assert.Equal(t, "symbol,delta_pct\nBTCUSDT,0.29\nETHUSDT,\n", rec.Body.String())
+	})
+
+	t.Run("returns 400 for an unknown include", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/prices?symbols=BTCUSDT&include=history", nil)
+		rec := httptest.NewRecorder()
+
+		newHandler().GetPrices(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "INVALID_INCLUDE")
+	})
+}
+
+func TestHandler_GetPrices_Invert(t *testing.T) {
+	now := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	btc := &domain.PriceSnapshot{ID: 1, Symbol: "BTCUSDT", Price: decimal.RequireFromString("43123.45"), Timestamp: now}
+	newHandler := func() *httpAdapter.Handler {
+		return httpAdapter.NewHandler(
+			&mockSymbolService{},
+			&mockSnapshotService{
+				latest: func(symbols []string) ([]*domain.PriceSnapshot, []string) {
+					var prices []*domain.PriceSnapshot
+					var missing []string
+					for _, symbol := range symbols {
+						if symbol == "BTCUSDT" {
+							prices = append(prices, btc)
+						} else {
+							missing = append(missing, symbol)
+						}
+					}
+					return prices, missing
+				},
+				previous: map[string]*domain.PriceSnapshot{
+					"BTCUSDT": {Symbol: "BTCUSDT", Price: decimal.RequireFromString("40000"), Timestamp: now.Add(-time.Hour)},
+				},
+			},
+			&mockMetricsService{},
+			&mockExchangeClient{},
+			newTestLogger(),
+		)
+	}
+
+	t.Run("inverts with invert=true", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/prices?symbols=BTCUSDT&invert=true&fields=symbol,price,inverted_from,prev_price", nil)
+		rec := httptest.NewRecorder()
+
+		newHandler().GetPrices(rec, req)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"prices": [
+			{"symbol": "USDTBTC", "price": "0.000023189239", "inverted_from": "BTCUSDT"}
+		]}`, rec.Body.String())
+	})
+
+	t.Run("serves a requested inverse pair", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/prices?symbols=USDTBTC,DOGEUSDT&include=previous&fields=symbol,price,inverted_from,prev_price,delta", nil)
+		rec := httptest.NewRecorder()
+
+		newHandler().GetPrices(rec, req)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"prices": [
+			{"symbol": "USDTBTC", "price": "0.000023189239", "inverted_from": "BTCUSDT", "prev_price": "0.000025", "delta": "-0.000001810761"}
+		], "missing": ["DOGEUSDT"]}`, rec.Body.String())
+	})
+
+	t.Run("adds the inverted_from column to CSV only when used", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/prices?symbols=USDTBTC&format=csv&fields=symbol,price,inverted_from", nil)
+		rec := httptest.NewRecorder()
+		newHandler().GetPrices(rec, req)
+		assert.Equal(t, "symbol,price,inverted_from\nUSDTBTC,0.000023189239,BTCUSDT\n", rec.Body.String())
+
+		req = httptest.NewRequest(http.MethodGet, "/prices?symbols=BTCUSDT&format=csv&fields=symbol,price,inverted_from", nil)
+		rec = httptest.NewRecorder()
+		newHandler().GetPrices(rec, req)
+		assert.Equal(t, "symbol,price\nBTCUSDT,43123.45\n", rec.Body.String())
+	})
+
+	t.Run("returns 400 for a non-boolean invert", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/prices?symbols=BTCUSDT&invert=maybe", nil)
+		rec := httptest.NewRecorder()
+
+		newHandler().GetPrices(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
+
+func TestHandler_GetPrices_TrackMissing(t *testing.T) {
+	now := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	newHandler := func(symbols *mockSymbolService) *httpAdapter.Handler {
+		return httpAdapter.NewHandler(
+			symbols,
+			&mockSnapshotService{
+				snapshots: []*domain.PriceSnapshot{
+					{ID: 1, Symbol: "BTCUSDT", Price: decimal.RequireFromString("43123.45"), Timestamp: now},
+				},
+				missing: []string{"SOLUSDT"},
+			},
+			&mockMetricsService{},
+			&mockExchangeClient{prices: []*domain.Price{
+				{Symbol: "SOLUSDT", Price: decimal.RequireFromString("98.7"), FetchedAt: now.Add(time.Second)},
+			}},
+			newTestLogger(),
+		)
+	}
+
+	t.Run("registers missing symbols and returns live prices", func(t *testing.T) {
+		symbols := &mockSymbolService{}
+		req := httptest.NewRequest(http.MethodGet, "/prices?symbols=BTCUSDT,SOLUSDT&track_missing=true&fields=symbol,price,ts", nil)
+		rec := httptest.NewRecorder()
+
+		newHandler(symbols).GetPrices(rec, req)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"prices": [
+			{"symbol": "BTCUSDT", "price": "43123.45", "ts": "2024-01-15T10:30:00Z"},
+			{"symbol": "SOLUSDT", "price": "98.7", "ts": "2024-01-15T10:30:01Z", "tracked": false, "newly_added": true}
+		]}`, rec.Body.String())
+		require.Len(t, symbols.symbols, 1)
+		assert.Equal(t, "SOLUSDT", symbols.symbols[0].Name)
+	})
+
+	t.Run("stores the prices with a symbol poller", func(t *testing.T) {
+		poller := &mockSymbolPoller{prices: map[string]decimal.Decimal{"SOLUSDT": decimal.RequireFromString("98.9")}}
+		handler := httpAdapter.NewHandler(
+			&mockSymbolService{},
+			&mockSnapshotService{
+				snapshots: []*domain.PriceSnapshot{
+					{ID: 1, Symbol: "BTCUSDT", Price: decimal.RequireFromString("43123.45"), Timestamp: now},
+				},
+				missing: []string{"SOLUSDT", "DOGEUSDT"},
+			},
+			&mockMetricsService{},
+			&mockExchangeClient{},
+			newTestLogger(),
+			httpAdapter.WithSymbolPoller(poller),
+		)
+		req := httptest.NewRequest(http.MethodGet, "/prices?symbols=BTCUSDT,SOLUSDT,DOGEUSDT&track_missing=true&fields=symbol,price,ts", nil)
+		rec := httptest.NewRecorder()
+
+		handler.GetPrices(rec, req)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"prices": [
+			{"symbol": "BTCUSDT", "price": "43123.45", "ts": "2024-01-15T10:30:00Z"},
+			{"symbol": "SOLUSDT", "price": "98.9", "ts": "2024-01-15T10:30:02Z", "tracked": true, "newly_added": true}
+		], "missing": ["DOGEUSDT"]}`, rec.Body.String())
+		assert.Equal(t, []string{"SOLUSDT", "DOGEUSDT"}, poller.polled)
+	})
+
+	t.Run("keeps symbols over quota missing", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/prices?symbols=BTCUSDT,SOLUSDT&track_missing=true", nil)
+		rec := httptest.NewRecorder()
+
+		newHandler(&mockSymbolService{addErr: domain.ErrSymbolQuota}).GetPrices(rec, req)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		var resp httpAdapter.PricesResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.Len(t, resp.Prices, 1)
+		assert.Equal(t, []string{"SOLUSDT"}, resp.Missing)
+	})
+
+	t.Run("does not track by default", func(t *testing.T) {
+		symbols := &mockSymbolService{}
+		req := httptest.NewRequest(http.MethodGet, "/prices?symbols=BTCUSDT,SOLUSDT", nil)
+		rec := httptest.NewRecorder()
+
+		newHandler(symbols).GetPrices(rec, req)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Empty(t, symbols.symbols)
+		assert.Contains(t, rec.Body.String(), `"missing":["SOLUSDT"]`)
+	})
+
+	t.Run("rejects invalid flag", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/prices?symbols=BTCUSDT&track_missing=maybe", nil)
+		rec := httptest.NewRecorder()
+
+		newHandler(&mockSymbolService{}).GetPrices(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
+
+type mockJobService struct {
+	jobs       []*domain.Job
+	enqueueErr error
+}
+
+func (m *mockJobService) Enqueue(ctx context.Context, jobType domain.JobType, payload json.RawMessage, runAt time.Time) (*domain.Job, error) {
+	if m.enqueueErr != nil {
+		return nil, m.enqueueErr
+	}
+	job, err := domain.NewJob(jobType, payload, runAt, 3)
+	if err != nil {
+		return nil, err
+	}
+	job.ID = int64(len(m.jobs) + 1)
+	m.jobs = append(m.jobs, job)
+	return job, nil
+}
+
+func (m *mockJobService) GetJob(ctx context.Context, id int64) (*domain.Job, error) {
+	for _, j := range m.jobs {
+		if j.ID == id {
+			return j, nil
+		}
+	}
+	return nil, domain.ErrJobNotFound
+}
+
+func (m *mockJobService) ListJobs(ctx context.Context, status domain.JobStatus, limit int) ([]*domain.Job, error) {
+	return m.jobs, nil
+}
+
+func (m *mockJobService) CancelJob(ctx context.Context, id int64) (*domain.Job, error) {
+	job, err := m.GetJob(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if job.Finished() {
+		return nil, domain.ErrJobFinished
+	}
+	job.Status = domain.JobStatusCancelled
+	return job, nil
+}
+
+func (m *mockJobService) ResumeJob(ctx context.Context, id int64) (*domain.Job, error) {
+	job, err := m.GetJob(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if job.Status != domain.JobStatusCancelled && job.Status != domain.JobStatusFailed {
+		return nil, domain.ErrJobNotResumable
+	}
+	job.Status = domain.JobStatusPending
+	return job, nil
+}
+
+func TestHandler_Jobs(t *testing.T) {
+	newRouter := func(jobs *mockJobService) http.Handler {
+		handler := httpAdapter.NewHandler(
+			&mockSymbolService{},
+			&mockSnapshotService{},
+			&mockMetricsService{},
+			&mockExchangeClient{},
+			newTestLogger(),
+			httpAdapter.WithJobs(jobs, 2<<20),
+		)
+		return httpAdapter.NewRouter(handler, newTestLogger())
+	}
+
+	importBody := func(rows int) *bytes.Buffer {
+		body := bytes.NewBufferString(`{"type": "import", "payload": {"snapshots": [`)
+		for i := 0; i < rows; i++ {
+			if i > 0 {
+				body.WriteString(",")
+			}
+			fmt.Fprintf(body, `{"symbol": "BTCUSDT", "price": "43123.45", "ts": "2024-01-15T10:%02d:%02dZ"}`, i/60%60, i%60)
+		}
+		body.WriteString(`]}}`)
+		return body
+	}
+
+	t.Run("accepts imports larger than other request bodies", func(t *testing.T) {
+		body := importBody(20000)
+		require.Greater(t, body.Len(), 1<<20)
+
+		rec := httptest.NewRecorder()
+		newRouter(&mockJobService{}).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/admin/jobs", body))
+
+		assert.Equal(t, http.StatusAccepted, rec.Code)
+	})
+
+	t.Run("rejects job bodies over the job limit", func(t *testing.T) {
+		body := importBody(40000)
+		require.Greater(t, body.Len(), 2<<20)
+
+		rec := httptest.NewRecorder()
+		newRouter(&mockJobService{}).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/admin/jobs", body))
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "body exceeds 2097152 bytes")
+	})
+
+	t.Run("enqueues and fetches a job", func(t *testing.T) {
+		router := newRouter(&mockJobService{})
+
+		body := bytes.NewBufferString(`{"type": "backfill", "payload": {"symbol": "BTCUSDT"}}`)
+		req := httptest.NewRequest(http.MethodPost, "/admin/jobs", body)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		require.Equal(t, http.StatusAccepted, rec.Code)
+		var created domain.Job
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+		assert.Equal(t, domain.JobTypeBackfill, created.Type)
+		assert.Equal(t, domain.JobStatusPending, created.Status)
+
+		req = httptest.NewRequest(http.MethodGet, "/admin/jobs/1", nil)
+		rec = httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("returns 400 for unknown job type", func(t *testing.T) {
+		router := newRouter(&mockJobService{})
+
+		body := bytes.NewBufferString(`{"type": "reticulate"}`)
+		req := httptest.NewRequest(http.MethodPost, "/admin/jobs", body)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("returns 404 for unknown job", func(t *testing.T) {
+		router := newRouter(&mockJobService{})
+
+		req := httptest.NewRequest(http.MethodGet, "/admin/jobs/42", nil)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("cancels and resumes a job", func(t *testing.T) {
+		jobs := &mockJobService{}
+		_, err := jobs.Enqueue(context.Background(), domain.JobTypeBackfill, nil, time.Time{})
+		require.NoError(t, err)
+		router := newRouter(jobs)
+
+		req := httptest.NewRequest(http.MethodDelete, "/admin/jobs/1", nil)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		var cancelled domain.Job
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &cancelled))
+		assert.Equal(t, domain.JobStatusCancelled, cancelled.Status)
+
+		req = httptest.NewRequest(http.MethodDelete, "/admin/jobs/1", nil)
+		rec = httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusConflict, rec.Code)
+
+		req = httptest.NewRequest(http.MethodPost, "/admin/jobs/1/resume", nil)
+		rec = httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusAccepted, rec.Code)
+	})
+
+	t.Run("lists jobs", func(t *testing.T) {
+		jobs := &mockJobService{}
+		_, err := jobs.Enqueue(context.Background(), domain.JobTypeRevalidate, nil, time.Time{})
+		require.NoError(t, err)
+		router := newRouter(jobs)
+
+		req := httptest.NewRequest(http.MethodGet, "/admin/jobs", nil)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var response map[string][]domain.Job
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Len(t, response["jobs"], 1)
+	})
+}
+
+type mockTickerService struct {
+	stats map[string]*domain.TickerStats
+}
+
+func (m *mockTickerService) GetTicker24h(ctx context.Context, symbol string) (*domain.TickerStats, error) {
+	if stats, ok := m.stats[symbol]; ok {
+		return stats, nil
+	}
+	return nil, domain.ErrSymbolNotFound
+}
+
+func TestHandler_Ticker24h(t *testing.T) {
+	now := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	ticker := &mockTickerService{stats: map[string]*domain.TickerStats{
+		"BTCUSDT": {
+			Symbol:             "BTCUSDT",
+			High:               decimal.RequireFromString("44000"),
+			Low:                decimal.RequireFromString("42500.5"),
+			Volume:             decimal.RequireFromString("18234.12"),
+			PriceChangePercent: decimal.RequireFromString("-1.25"),
+			Timestamp:          now,
+		},
+	}}
+
+	newRouter := func(opts ...httpAdapter.HandlerOption) http.Handler {
+		handler := httpAdapter.NewHandler(
+			&mockSymbolService{},
+			&mockSnapshotService{},
+			&mockMetricsService{},
+			&mockExchangeClient{},
+			newTestLogger(),
+			opts...,
+		)
+		return httpAdapter.NewRouter(handler, newTestLogger())
+	}
+
+	t.Run("returns latest stats", func(t *testing.T) {
+		router := newRouter(httpAdapter.WithTicker(ticker))
+
+		req := httptest.NewRequest(http.MethodGet, "/ticker24h?symbol=BTCUSDT", nil)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		var resp httpAdapter.Ticker24hResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.Equal(t, "44000", resp.High)
+		assert.Equal(t, "18234.12", resp.Volume)
+		assert.Equal(t, "-1.25", resp.PriceChangePercent)
+		assert.Equal(t, "2024-01-15T10:30:00Z", resp.Timestamp)
+	})
+
+	t.Run("returns 400 without symbol", func(t *testing.T) {
+		router := newRouter(httpAdapter.WithTicker(ticker))
+
+		req := httptest.NewRequest(http.MethodGet, "/ticker24h", nil)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("not routed when disabled", func(t *testing.T) {
+		router := newRouter()
+
+		req := httptest.NewRequest(http.MethodGet, "/ticker24h?symbol=BTCUSDT", nil)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
+
+type mockCandleService struct {
+	candles  []*domain.Candle
+	interval domain.CandleInterval
+	from, to time.Time
+	fill     domain.FillMode
+}
+
+func (m *mockCandleService) GetCandles(ctx context.Context, symbol string, interval domain.CandleInterval, from, to time.Time, fill domain.FillMode) ([]*domain.Candle, error) {
+	if symbol != "BTCUSDT" {
+		return nil, domain.ErrSymbolNotFound
+	}
+	m.interval, m.from, m.to, m.fill = interval, from, to, fill
+	return m.candles, nil
+}
+
+func (m *mockCandleService) Aggregate(ctx context.Context) (*domain.CandleAggregation, error) {
+	return &domain.CandleAggregation{}, nil
+}
+
+func TestHandler_GetCandles(t *testing.T) {
+	open := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	newRouter := func(opts ...httpAdapter.HandlerOption) http.Handler {
+		handler := httpAdapter.NewHandler(
+			&mockSymbolService{},
+			&mockSnapshotService{},
+			&mockMetricsService{},
+			&mockExchangeClient{},
+			newTestLogger(),
+			opts...,
+		)
+		return httpAdapter.NewRouter(handler, newTestLogger())
+	}
+	newService := func() *mockCandleService {
+		return &mockCandleService{candles: []*domain.Candle{
+			{
+				Symbol:    "BTCUSDT",
+				OpenTime:  open,
+				CloseTime: open.Add(5 * time.Minute),
+				Open:      decimal.RequireFromString("43000"),
+				High:      decimal.RequireFromString("43200.5"),
+				Low:       decimal.RequireFromString("42950"),
+				Close:     decimal.RequireFromString("43100"),
+				Samples:   5,
+			},
+			{
+				Symbol:    "BTCUSDT",
+				OpenTime:  open.Add(5 * time.Minute),
+				CloseTime: open.Add(10 * time.Minute),
+				Open:      decimal.RequireFromString("43100"),
+				High:      decimal.RequireFromString("43100"),
+				Low:       decimal.RequireFromString("43100"),
+				Close:     decimal.RequireFromString("43100"),
+				Filled:    true,
+			},
+		}}
+	}
+
+	t.Run("returns candles", func(t *testing.T) {
+		candles := newService()
+		router := newRouter(httpAdapter.WithCandles(candles))
+
+		req := httptest.NewRequest(http.MethodGet,
+			"/candles?symbol=btcusdt&interval=5m&fill=prev&from=2024-01-15T10:02:00Z&to=2024-01-15T10:10:00Z", nil)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		var resp httpAdapter.CandlesResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.Equal(t, "BTCUSDT", resp.Symbol)
+		assert.Equal(t, domain.CandleInterval5m, resp.Interval)
+		assert.Equal(t, domain.FillPrev, resp.Fill)
+		require.Len(t, resp.Candles, 2)
+		assert.Equal(t, "2024-01-15T10:00:00Z", resp.Candles[0].OpenTime)
+		assert.Equal(t, "2024-01-15T10:05:00Z", resp.Candles[0].CloseTime)
+		assert.Equal(t, "43200.5", resp.Candles[0].High)
+		assert.Equal(t, 5, resp.Candles[0].Samples)
+		assert.True(t, resp.Candles[1].Filled)
+
+		// from is aligned down to the interval
+		assert.Equal(t, open, candles.from)
+	})
+
+	t.Run("defaults to the last 100 hourly candles", func(t *testing.T) {
+		candles := newService()
+		router := newRouter(httpAdapter.WithCandles(candles))
+
+		req := httptest.NewRequest(http.MethodGet, "/candles?symbol=BTCUSDT&to=2024-01-15T10:00:00Z", nil)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, domain.CandleInterval1h, candles.interval)
+		assert.Equal(t, domain.FillNone, candles.fill)
+		assert.Equal(t, open.Add(-100*time.Hour), candles.from)
+	})
+
+	t.Run("returns csv", func(t *testing.T) {
+		router := newRouter(httpAdapter.WithCandles(newService()))
+
+		req := httptest.NewRequest(http.MethodGet, "/candles?symbol=BTCUSDT&interval=5m&format=csv", nil)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		body := rec.Body.String()
+		assert.Contains(t, body, "open_time,close_time,open,high,low,close,samples,filled\n")
+		assert.Contains(t, body, "2024-01-15T10:00:00Z,2024-01-15T10:05:00Z,43000,43200.5,42950,43100,5,false\n")
+	})
+
+	t.Run("rejects bad parameters", func(t *testing.T) {
+		router := newRouter(httpAdapter.WithCandles(newService()))
+
+		for _, tc := range []struct {
+			query string
+			code  string
+		}{
+			{"interval=3m", "INVALID_INTERVAL"},
+			{"fill=spline", "INVALID_FILL"},
+			{"from=2024-01-15T10:00:00Z&to=2024-01-15T09:00:00Z", "INVALID_TIME_RANGE"},
+			{"interval=1m&from=2024-01-01T00:00:00Z&to=2024-01-15T00:00:00Z", "INVALID_TIME_RANGE"},
+		} {
+			req := httptest.NewRequest(http.MethodGet, "/candles?symbol=BTCUSDT&"+tc.query, nil)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusBadRequest, rec.Code, tc.query)
+			assert.Contains(t, rec.Body.String(), tc.code, tc.query)
+		}
+	})
+
+	t.Run("returns 404 for unknown symbol", func(t *testing.T) {
+		router := newRouter(httpAdapter.WithCandles(newService()))
+
+		req := httptest.NewRequest(http.MethodGet, "/candles?symbol=ETHUSDT", nil)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("not routed when disabled", func(t *testing.T) {
+		router := newRouter()
+
+		req := httptest.NewRequest(http.MethodGet, "/candles?symbol=BTCUSDT", nil)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
+
+func TestHandler_SymbolTags(t *testing.T) {
+	now := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	newRouter := func() (http.Handler, *mockSymbolService) {
+		symbols := &mockSymbolService{symbols: []*domain.Symbol{
+			{ID: 1, Name: "BTCUSDT", Active: true, Tags: []string{"layer1"}},
+			{ID: 2, Name: "USDCUSDT", Active: true, Tags: []string{"stablecoin"}},
+		}}
+		handler := httpAdapter.NewHandler(
+			symbols,
+			&mockSnapshotService{snapshots: []*domain.PriceSnapshot{
+				{ID: 1, Symbol: "BTCUSDT", Price: decimal.RequireFromString("43123.45"), Timestamp: now},
+			}},
+			&mockMetricsService{},
+			&mockExchangeClient{},
+			newTestLogger(),
+		)
+		return httpAdapter.NewRouter(handler, newTestLogger()), symbols
+	}
+
+	t.Run("filters symbols by tag", func(t *testing.T) {
+		router, _ := newRouter()
+
+		req := httptest.NewRequest(http.MethodGet, "/symbols?tag=Layer1", nil)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		var resp httpAdapter.SymbolsResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.Equal(t, []string{"BTCUSDT"}, resp.Symbols)
+	})
+
+	t.Run("accepts tag without symbols on prices", func(t *testing.T) {
+		router, _ := newRouter()
+
+		req := httptest.NewRequest(http.MethodGet, "/prices?tag=layer1", nil)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), "BTCUSDT")
+	})
+
+	t.Run("returns empty prices when no listed symbol has the tag", func(t *testing.T) {
+		router, _ := newRouter()
+
+		req := httptest.NewRequest(http.MethodGet, "/prices?symbols=BTCUSDT&tag=stablecoin", nil)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"prices": []}`, rec.Body.String())
+	})
+
+	t.Run("replaces and removes tags", func(t *testing.T) {
+		router, symbols := newRouter()
+
+		body := bytes.NewBufferString(`{"tags": ["pow", "Layer1"]}`)
+		req := httptest.NewRequest(http.MethodPut, "/symbols/BTCUSDT/tags", body)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, []string{"layer1", "pow"}, symbols.symbols[0].Tags)
+
+		req = httptest.NewRequest(http.MethodDelete, "/symbols/BTCUSDT/tags/pow", nil)
+		rec = httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, []string{"layer1"}, symbols.symbols[0].Tags)
+	})
+
+	t.Run("rejects invalid tags", func(t *testing.T) {
+		router, _ := newRouter()
+
+		body := bytes.NewBufferString(`{"tags": ["not a tag"]}`)
+		req := httptest.NewRequest(http.MethodPut, "/symbols/BTCUSDT/tags", body)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
+
+func TestHandler_DeleteSymbol(t *testing.T) {
+	newRouter := func(symbols *mockSymbolService) http.Handler {
+		handler := httpAdapter.NewHandler(
+			symbols,
+			&mockSnapshotService{},
+			&mockMetricsService{},
+			&mockExchangeClient{},
+			newTestLogger(),
+		)
+		return httpAdapter.NewRouter(handler, newTestLogger())
+	}
+
+	t.Run("keeps the history by default", func(t *testing.T) {
+		symbols := &mockSymbolService{}
+
+		req := httptest.NewRequest(http.MethodDelete, "/symbols/BTCUSDT", nil)
+		rec := httptest.NewRecorder()
+		newRouter(symbols).ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		assert.Equal(t, []string{"BTCUSDT"}, symbols.removed)
+		assert.Empty(t, symbols.purged)
+	})
+
+	t.Run("purges with purge=true", func(t *testing.T) {
+		symbols := &mockSymbolService{}
+
+		req := httptest.NewRequest(http.MethodDelete, "/symbols/BTCUSDT?purge=true", nil)
+		rec := httptest.NewRecorder()
+		newRouter(symbols).ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		assert.Empty(t, symbols.removed)
+		assert.Equal(t, []string{"BTCUSDT"}, symbols.purged)
+	})
+
+	t.Run("returns 404 for an unknown symbol", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodDelete, "/symbols/NOPEUSDT", nil)
+		rec := httptest.NewRecorder()
+		newRouter(&mockSymbolService{removeErr: domain.ErrSymbolNotFound}).ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("returns 400 for a non-boolean purge", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodDelete, "/symbols/BTCUSDT?purge=all", nil)
+		rec := httptest.NewRecorder()
+		newRouter(&mockSymbolService{}).ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
+
+func TestHandler_UpdateSymbol(t *testing.T) {
+	newRouter := func() (http.Handler, *mockSymbolService) {
+		symbols := &mockSymbolService{symbols: []*domain.Symbol{
+			{ID: 1, Name: "BTCUSDT", Active: true},
+		}}
+		handler := httpAdapter.NewHandler(
+			symbols,
+			&mockSnapshotService{},
+			&mockMetricsService{},
+			&mockExchangeClient{},
+			newTestLogger(),
+		)
+		return httpAdapter.NewRouter(handler, newTestLogger()), symbols
+	}
+
+	t.Run("deactivates and activates a symbol", func(t *testing.T) {
+		router, symbols := newRouter()
+
+		req := httptest.NewRequest(http.MethodPatch, "/symbols/BTCUSDT", bytes.NewBufferString(`{"active": false}`))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.False(t, symbols.symbols[0].Active)
+		assert.Contains(t, rec.Body.String(), `"active":false`)
+
+		req = httptest.NewRequest(http.MethodPatch, "/symbols/BTCUSDT", bytes.NewBufferString(`{"active": true}`))
+		rec = httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.True(t, symbols.symbols[0].Active)
+	})
+
+	t.Run("requires active", func(t *testing.T) {
+		router, _ := newRouter()
+
+		req := httptest.NewRequest(http.MethodPatch, "/symbols/BTCUSDT", bytes.NewBufferString(`{}`))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"field":"active"`)
+	})
+
+	t.Run("returns 404 for an untracked symbol", func(t *testing.T) {
+		router, _ := newRouter()
+
+		req := httptest.NewRequest(http.MethodPatch, "/symbols/ETHUSDT", bytes.NewBufferString(`{"active": false}`))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
+
+func TestHandler_UpdateSymbols(t *testing.T) {
+	newRouter := func() (http.Handler, *mockSymbolService) {
+		symbols := &mockSymbolService{symbols: []*domain.Symbol{
+			{ID: 1, Name: "BTCUSDT", Active: true},
+			{ID: 2, Name: "ETHUSDT", Active: true},
+			{ID: 3, Name: "SOLUSDT", Active: false},
+		}}
+		handler := httpAdapter.NewHandler(
+			symbols,
+			&mockSnapshotService{},
+			&mockMetricsService{},
+			&mockExchangeClient{},
+			newTestLogger(),
+		)
+		return httpAdapter.NewRouter(handler, newTestLogger()), symbols
+	}
+
+	t.Run("deactivates every listed symbol", func(t *testing.T) {
+		router, symbols := newRouter()
+
+		body := bytes.NewBufferString(`{"symbols": ["BTCUSDT", "SOLUSDT"], "active": false}`)
+		req := httptest.NewRequest(http.MethodPatch, "/symbols/batch", body)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"applied": true, "results": [
+			{"symbol": "BTCUSDT", "outcome": "deactivated"},
+			{"symbol": "SOLUSDT", "outcome": "unchanged"}
+		]}`, rec.Body.String())
+		assert.False(t, symbols.symbols[0].Active)
+		assert.True(t, symbols.symbols[1].Active)
+	})
+
+	t.Run("changes nothing when a symbol fails", func(t *testing.T) {
+		router, symbols := newRouter()
+
+		body := bytes.NewBufferString(`{"symbols": ["BTCUSDT", "NOPEUSDT"], "active": false}`)
+		req := httptest.NewRequest(http.MethodPatch, "/symbols/batch", body)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		require.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+		var resp domain.SymbolBatch
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.False(t, resp.Applied)
+		require.Len(t, resp.Results, 2)
+		assert.Equal(t, domain.SymbolBatchSkipped, resp.Results[0].Outcome)
+		assert.Equal(t, domain.SymbolBatchNotFound, resp.Results[1].Outcome)
+		assert.True(t, symbols.symbols[0].Active)
+	})
+
+	t.Run("requires symbols and active", func(t *testing.T) {
+		router, _ := newRouter()
+
+		req := httptest.NewRequest(http.MethodPatch, "/symbols/batch", bytes.NewBufferString(`{}`))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"field":"symbols"`)
+		assert.Contains(t, rec.Body.String(), `"field":"active"`)
+	})
+
+	t.Run("rejects too many symbols", func(t *testing.T) {
+		router, _ := newRouter()
+
+		names := make([]string, domain.MaxSymbolBatch+1)
+		for i := range names {
+			names[i] = fmt.Sprintf("SYM%dUSDT", i)
+		}
+		body, err := json.Marshal(map[string]interface{}{"symbols": names, "active": false})
+		require.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPatch, "/symbols/batch", bytes.NewReader(body))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "BATCH_TOO_LARGE")
+	})
+}
+
+func TestHandler_SymbolEvents(t *testing.T) {
+	created := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	symbols := &mockSymbolService{
+		symbols: []*domain.Symbol{{ID: 1, Name: "BTCUSDT", Active: true}},
+		events: []*domain.SymbolEvent{
+			{ID: 2, Symbol: "ETHUSDT", Type: domain.SymbolEventRemoved, CreatedAt: created.Add(time.Hour)},
+			{ID: 1, Symbol: "ETHUSDT", Type: domain.SymbolEventAdded, CreatedAt: created},
+		},
+	}
+	handler := httpAdapter.NewHandler(
+		symbols,
+		&mockSnapshotService{},
+		&mockMetricsService{},
+		&mockExchangeClient{},
+		newTestLogger(),
+	)
+	router := httpAdapter.NewRouter(handler, newTestLogger())
+
+	t.Run("returns history of a removed symbol", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/symbols/ethusdt/events", nil)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		var resp httpAdapter.SymbolEventsResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.Equal(t, "ETHUSDT", resp.Symbol)
+		require.Len(t, resp.Events, 2)
+		assert.Equal(t, domain.SymbolEventRemoved, resp.Events[0].Type)
+	})
+
+	t.Run("respects limit", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/symbols/ETHUSDT/events?limit=1", nil)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		var resp httpAdapter.SymbolEventsResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.Len(t, resp.Events, 1)
+	})
+
+	t.Run("returns empty history for tracked symbol", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/symbols/BTCUSDT/events", nil)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"symbol": "BTCUSDT", "events": []}`, rec.Body.String())
+	})
+
+	t.Run("returns 404 for unknown symbol", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/symbols/XRPUSDT/events", nil)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
+
+func TestHandler_GetSymbol(t *testing.T) {
+	updated := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	symbols := &mockSymbolService{symbols: []*domain.Symbol{
+		{ID: 1, Name: "BTCUSDT", Active: true, Tags: []string{}, Metadata: &domain.CoinMetadata{
+			Name:      "Bitcoin",
+			LogoURL:   "https://img/btc.png",
+			MarketCap: decimal.RequireFromString("845123456789"),
+			UpdatedAt: updated,
+		}},
+		{ID: 2, Name: "ETHUSDT", Active: true, Tags: []string{}},
+	}}
+	handler := httpAdapter.NewHandler(
+		symbols,
+		&mockSnapshotService{},
+		&mockMetricsService{},
+		&mockExchangeClient{},
+		newTestLogger(),
+	)
+	router := httpAdapter.NewRouter(handler, newTestLogger())
+
+	t.Run("includes coin metadata", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/symbols/BTCUSDT", nil)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		var resp map[string]json.RawMessage
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.JSONEq(t, `{
+			"name": "Bitcoin",
+			"logo_url": "https://img/btc.png",
+			"market_cap": "845123456789",
+			"updated_at": "2024-01-15T00:00:00Z"
+		}`, string(resp["metadata"]))
+	})
+
+	t.Run("omits metadata until enriched", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/symbols/ETHUSDT", nil)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.NotContains(t, rec.Body.String(), "metadata")
+	})
+
+	t.Run("returns 404 for untracked symbol", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/symbols/XRPUSDT", nil)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
+
+type mockRetentionService struct {
+	report *domain.RetentionReport
+	err    error
+}
+
+func (m *mockRetentionService) Report(ctx context.Context) (*domain.RetentionReport, error) {
+	return m.report, m.err
+}
+
+func TestHandler_RetentionReport(t *testing.T) {
+	newRouter := func(opts ...httpAdapter.HandlerOption) http.Handler {
+		handler := httpAdapter.NewHandler(
+			&mockSymbolService{},
+			&mockSnapshotService{},
+			&mockMetricsService{},
+			&mockExchangeClient{},
+			newTestLogger(),
+			opts...,
+		)
+		return httpAdapter.NewRouter(handler, newTestLogger())
+	}
+
+	t.Run("returns report", func(t *testing.T) {
+		reachedAt := time.Date(2024, 1, 25, 10, 30, 0, 0, time.UTC)
+		router := newRouter(httpAdapter.WithRetention(&mockRetentionService{report: &domain.RetentionReport{
+			TotalRows:          604800,
+			BytesPerDay:        7257600,
+			DiskThresholdBytes: 145152000,
+			ThresholdReachedAt: &reachedAt,
+			Symbols:            []domain.SymbolGrowth{{Symbol: "BTCUSDT", RowsPerDay: 28800}},
+		}}))
+
+		req := httptest.NewRequest(http.MethodGet, "/admin/retention", nil)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		var resp domain.RetentionReport
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.Equal(t, int64(7257600), resp.BytesPerDay)
+		require.NotNil(t, resp.ThresholdReachedAt)
+		assert.True(t, resp.ThresholdReachedAt.Equal(reachedAt))
+		require.Len(t, resp.Symbols, 1)
+		assert.Equal(t, "BTCUSDT", resp.Symbols[0].Symbol)
+	})
+
+	t.Run("returns 500 on failure", func(t *testing.T) {
+		router := newRouter(httpAdapter.WithRetention(&mockRetentionService{err: domain.ErrInternal}))
+
+		req := httptest.NewRequest(http.MethodGet, "/admin/retention", nil)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	})
+
+	t.Run("not routed when disabled", func(t *testing.T) {
+		router := newRouter()
+
+		req := httptest.NewRequest(http.MethodGet, "/admin/retention", nil)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
+
+func TestHandler_FieldSelection(t *testing.T) {
+	now := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	newRouter := func(opts ...httpAdapter.HandlerOption) http.Handler {
+		handler := httpAdapter.NewHandler(
+			&mockSymbolService{},
+			&mockSnapshotService{snapshots: []*domain.PriceSnapshot{
+				{ID: 1, Symbol: "BTCUSDT", Price: decimal.RequireFromString("43123.45"), Timestamp: now},
+			}},
+			&mockMetricsService{},
+			&mockExchangeClient{},
+			newTestLogger(),
+			opts...,
+		)
+		return httpAdapter.NewRouter(handler, newTestLogger())
+	}
+
+	get := func(router http.Handler, target string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("prices keep only selected fields", func(t *testing.T) {
+		rec := get(newRouter(), "/prices?symbols=BTCUSDT&fields=symbol,price")
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"prices":[{"symbol":"BTCUSDT","price":"43123.45"}]}`, rec.Body.String())
+	})
+
+	t.Run("history keeps only selected fields", func(t *testing.T) {
+		rec := get(newRouter(), "/history?symbol=BTCUSDT&fields=ts")
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"symbol":"BTCUSDT","source":"raw","limit":100,"from":null,"to":null,"order":"desc","count":1,"has_more":false,"next_cursor":null,"oldest_ts":"2024-01-15T10:30:00Z","newest_ts":"2024-01-15T10:30:00Z","total_count":1,"oldest":"2024-01-15T10:30:00Z","newest":"2024-01-15T10:30:00Z","items":[{"ts":"2024-01-15T10:30:00Z"}]}`, rec.Body.String())
+	})
+
+	t.Run("price_num selectable on its own", func(t *testing.T) {
+		router := newRouter(httpAdapter.WithPriceFormat(httpAdapter.PriceFormatBoth, 2))
+		rec := get(router, "/prices?symbols=BTCUSDT&fields=symbol,price_num")
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"prices":[{"symbol":"BTCUSDT","price_num":43123.45}]}`, rec.Body.String())
+	})
+
+	t.Run("csv columns follow selection", func(t *testing.T) {
+		rec := get(newRouter(), "/history?symbol=BTCUSDT&fields=price,symbol&format=csv")
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "symbol,price\nBTCUSDT,43123.45\n", rec.Body.String())
+	})
+
+	t.Run("ndjson rows follow selection", func(t *testing.T) {
+		rec := get(newRouter(), "/prices?symbols=BTCUSDT&fields=price&format=ndjson")
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, `{"price":"43123.45"}`+"\n", rec.Body.String())
+	})
+
+	t.Run("rejects unknown field", func(t *testing.T) {
+		rec := get(newRouter(), "/prices?symbols=BTCUSDT&fields=symbol,volume")
+
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+		var resp httpAdapter.ErrorResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.Equal(t, "INVALID_FIELDS", resp.Code)
+	})
+}
+
+type mockMigrationStatusProvider struct {
+	status *domain.MigrationStatus
+	err    error
+}
+
+func (m *mockMigrationStatusProvider) MigrationStatus(ctx context.Context) (*domain.MigrationStatus, error) {
+	return m.status, m.err
+}
+
+func TestHandler_MigrationStatus(t *testing.T) {
+	newRouter := func(opts ...httpAdapter.HandlerOption) http.Handler {
+		handler := httpAdapter.NewHandler(
+			&mockSymbolService{},
+			&mockSnapshotService{},
+			&mockMetricsService{},
+			&mockExchangeClient{},
+			newTestLogger(),
+			opts...,
+		)
+		return httpAdapter.NewRouter(handler, newTestLogger())
+	}
+
+	t.Run("returns status", func(t *testing.T) {
+		router := newRouter(httpAdapter.WithMigrations(&mockMigrationStatusProvider{
+			status: domain.NewMigrationStatus(4, true, 5),
+		}))
+
+		req := httptest.NewRequest(http.MethodGet, "/admin/migrations", nil)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"version":4,"dirty":true,"latest":5,"state":"dirty"}`, rec.Body.String())
+	})
+
+	t.Run("returns 500 on failure", func(t *testing.T) {
+		router := newRouter(httpAdapter.WithMigrations(&mockMigrationStatusProvider{err: domain.ErrInternal}))
+
+		req := httptest.NewRequest(http.MethodGet, "/admin/migrations", nil)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	})
+
+	t.Run("not routed when disabled", func(t *testing.T) {
+		router := newRouter()
+
+		req := httptest.NewRequest(http.MethodGet, "/admin/migrations", nil)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
+
+type mockIngestService struct {
+	received []domain.IngestedSnapshot
+	result   *domain.IngestResult
+	err      error
+}
+
+func (m *mockIngestService) Ingest(ctx context.Context, snapshots []domain.IngestedSnapshot) (*domain.IngestResult, error) {
+	m.received = snapshots
+	return m.result, m.err
+}
+
+func TestHandler_Ingest(t *testing.T) {
+	newRouter := func(svc *mockIngestService) http.Handler {
+		handler := httpAdapter.NewHandler(
+			&mockSymbolService{},
+			&mockSnapshotService{},
+			&mockMetricsService{},
+			&mockExchangeClient{},
+			newTestLogger(),
+			httpAdapter.WithIngest(svc, []string{"key-a", "key-b"}),
+		)
+		return httpAdapter.NewRouter(handler, newTestLogger())
+	}
+	body := `{"snapshots":[{"symbol":"btcusdt","price":"42150.12345678","timestamp":"2024-01-15T10:30:00Z","source":"desk-feed"}]}`
+	request := func(router http.Handler, apiKey, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/ingest", bytes.NewBufferString(body))
+		if apiKey != "" {
+			req.Header.Set(httpAdapter.APIKeyHeader, apiKey)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("stores pushed snapshots", func(t *testing.T) {
+		svc := &mockIngestService{result: &domain.IngestResult{Accepted: 1}}
+
+		rec := request(newRouter(svc), "key-b", body)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		var resp domain.IngestResult
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.Equal(t, domain.IngestResult{Accepted: 1}, resp)
+		require.Len(t, svc.received, 1)
+		assert.Equal(t, "42150.12345678", svc.received[0].Price.String())
+		assert.Equal(t, "desk-feed", svc.received[0].Source)
+	})
+
+	t.Run("rejects missing or unknown API keys", func(t *testing.T) {
+		svc := &mockIngestService{}
+		router := newRouter(svc)
+
+		assert.Equal(t, http.StatusUnauthorized, request(router, "", body).Code)
+		assert.Equal(t, http.StatusUnauthorized, request(router, "key-c", body).Code)
+		assert.Nil(t, svc.received)
+	})
+
+	t.Run("reports validation errors", func(t *testing.T) {
+		svc := &mockIngestService{err: fmt.Errorf("snapshot 0: %w: timestamp is in the future", domain.ErrInvalidIngest)}
+
+		rec := request(newRouter(svc), "key-a", body)
+
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+		var resp httpAdapter.ErrorResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.Equal(t, "INVALID_SNAPSHOT", resp.Code)
+		assert.Contains(t, resp.Details, "timestamp is in the future")
+	})
+
+	t.Run("rejects malformed bodies", func(t *testing.T) {
+		rec := request(newRouter(&mockIngestService{}), "key-a", `{"snapshots":`)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("is not served without ingestion", func(t *testing.T) {
+		handler := httpAdapter.NewHandler(
+			&mockSymbolService{},
+			&mockSnapshotService{},
+			&mockMetricsService{},
+			&mockExchangeClient{},
+			newTestLogger(),
+		)
+
+		rec := request(httpAdapter.NewRouter(handler, newTestLogger()), "key-a", body)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
+
+func TestHandler_GetPollerSymbols(t *testing.T) {
+	polledAt := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	fast := domain.NewPollSymbols("3f2504e0-4f89-41d3-9a0c-0305e82c3301", "fast", polledAt)
+	fast.Included = []string{"BTCUSDT", "ETHUSDT"}
+	fast.Exclude("ETHUSDT", domain.PollExclusionNoPrice, "")
+	fast.Exclude("SOLUSDT", domain.PollExclusionOtherGroup, "")
+	fast.Exclude("DOGEUSDT", domain.PollExclusionInactive, "")
+
+	newRouter := func() http.Handler {
+		handler := httpAdapter.NewHandler(
+			&mockSymbolService{},
+			&mockSnapshotService{},
+			&mockMetricsService{pollSymbols: []*domain.PollSymbols{fast}},
+			&mockExchangeClient{},
+			newTestLogger(),
+		)
+		return httpAdapter.NewRouter(handler, newTestLogger())
+	}
+
+	t.Run("returns the most recent runs", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/admin/poller/symbols", nil)
+		rec := httptest.NewRecorder()
+		newRouter().ServeHTTP(rec, req)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		var resp httpAdapter.PollerSymbolsResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		require.Len(t, resp.Runs, 1)
+		assert.Equal(t, "3f2504e0-4f89-41d3-9a0c-0305e82c3301", resp.Runs[0].RunID)
+		assert.Equal(t, "fast", resp.Runs[0].Group)
+		assert.Equal(t, []string{"BTCUSDT", "ETHUSDT"}, resp.Runs[0].Included)
+		assert.Len(t, resp.Runs[0].Excluded, 3)
+	})
+
+	t.Run("narrows runs to one symbol", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/admin/poller/symbols?symbol=solusdt", nil)
+		rec := httptest.NewRecorder()
+		newRouter().ServeHTTP(rec, req)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		var resp httpAdapter.PollerSymbolsResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		require.Len(t, resp.Runs, 1)
+		assert.Empty(t, resp.Runs[0].Included)
+		assert.Equal(t, []domain.PollExclusion{{Symbol: "SOLUSDT", Reason: domain.PollExclusionOtherGroup}}, resp.Runs[0].Excluded)
+	})
+}
+
+func TestHandler_DeleteSnapshots(t *testing.T) {
+	newRouter := func(snapshots *mockSnapshotService) http.Handler {
+		handler := httpAdapter.NewHandler(
+			&mockSymbolService{},
+			snapshots,
+			&mockMetricsService{},
+			&mockExchangeClient{},
+			newTestLogger(),
+		)
+		return httpAdapter.NewRouter(handler, newTestLogger())
+	}
+
+	t.Run("deletes a range", func(t *testing.T) {
+		snapshots := &mockSnapshotService{}
+		req := httptest.NewRequest(http.MethodDelete, "/admin/snapshots?symbol=BTCUSDT&from=2024-01-15T10:00:00Z&to=2024-01-15T11:00:00Z", nil)
+		rec := httptest.NewRecorder()
+		newRouter(snapshots).ServeHTTP(rec, req)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.False(t, snapshots.dryRun)
+		assert.Equal(t, time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC), snapshots.from)
+		assert.Equal(t, time.Date(2024, 1, 15, 11, 0, 0, 0, time.UTC), snapshots.to)
+		assert.JSONEq(t, `{
+			"symbol": "BTCUSDT",
+			"from": "2024-01-15T10:00:00Z",
+			"to": "2024-01-15T11:00:00Z",
+			"dry_run": false,
+			"deleted": 120,
+			"kept_compressed": 10
+		}`, rec.Body.String())
+	})
+
+	t.Run("reports a dry run", func(t *testing.T) {
+		snapshots := &mockSnapshotService{}
+		req := httptest.NewRequest(http.MethodDelete, "/admin/snapshots?symbol=BTCUSDT&from=2024-01-15T10:00:00Z&to=2024-01-15T11:00:00Z&dry_run=true", nil)
+		rec := httptest.NewRecorder()
+		newRouter(snapshots).ServeHTTP(rec, req)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.True(t, snapshots.dryRun)
+		assert.Contains(t, rec.Body.String(), `"dry_run":true`)
+	})
+
+	t.Run("rejects invalid parameters", func(t *testing.T) {
+		for _, query := range []string{
+			"from=2024-01-15T10:00:00Z&to=2024-01-15T11:00:00Z",
+			"symbol=BTCUSDT&to=2024-01-15T11:00:00Z",
+			"symbol=BTCUSDT&from=yesterday&to=2024-01-15T11:00:00Z",
+			"symbol=BTCUSDT&from=2024-01-15T10:00:00Z&to=2024-01-15T11:00:00Z&dry_run=maybe",
+		} {
+			snapshots := &mockSnapshotService{}
+			req := httptest.NewRequest(http.MethodDelete, "/admin/snapshots?"+query, nil)
+			rec := httptest.NewRecorder()
+			newRouter(snapshots).ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusBadRequest, rec.Code, query)
+			assert.True(t, snapshots.from.IsZero(), query)
+		}
+	})
+
+	t.Run("returns 404 for an untracked symbol", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodDelete, "/admin/snapshots?symbol=NOPEUSDT&from=2024-01-15T10:00:00Z&to=2024-01-15T11:00:00Z", nil)
+		rec := httptest.NewRecorder()
+		newRouter(&mockSnapshotService{err: domain.ErrSymbolNotFound}).ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
+
+func TestHandler_PollRuns(t *testing.T) {
+	const runID = "3f2504e0-4f89-41d3-9a0c-0305e82c3301"
+	stored := []*domain.PriceSnapshot{
+		{ID: 1, Symbol: "BTCUSDT", Price: decimal.RequireFromString("42000"), PollRunID: runID},
+		{ID: 2, Symbol: "ETHUSDT", Price: decimal.RequireFromString("2500"), PollRunID: runID},
+	}
+
+	newRouter := func(snapshots *mockSnapshotService) http.Handler {
+		handler := httpAdapter.NewHandler(
+			&mockSymbolService{},
+			snapshots,
+			&mockMetricsService{},
+			&mockExchangeClient{},
+			newTestLogger(),
+		)
+		return httpAdapter.NewRouter(handler, newTestLogger())
+	}
+
+	t.Run("returns the snapshots of a run", func(t *testing.T) {
+		snapshots := &mockSnapshotService{snapshots: stored}
+		req := httptest.NewRequest(http.MethodGet, "/admin/poller/runs/3F2504E0-4F89-41D3-9A0C-0305E82C3301", nil)
+		rec := httptest.NewRecorder()
+		newRouter(snapshots).ServeHTTP(rec, req)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, runID, snapshots.runID)
+
+		var resp struct {
+			RunID     string `json:"run_id"`
+			Snapshots []struct {
+				Symbol    string `json:"symbol"`
+				PollRunID string `json:"poll_run_id"`
+			} `json:"snapshots"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.Equal(t, runID, resp.RunID)
+		require.Len(t, resp.Snapshots, 2)
+		assert.Equal(t, runID, resp.Snapshots[0].PollRunID)
+	})
+
+	t.Run("deletes the snapshots of a run", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodDelete, "/admin/poller/runs/"+runID, nil)
+		rec := httptest.NewRecorder()
+		newRouter(&mockSnapshotService{snapshots: stored}).ServeHTTP(rec, req)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"run_id": "`+runID+`", "deleted": 2}`, rec.Body.String())
+	})
+
+	t.Run("returns 404 for an unknown run", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/admin/poller/runs/"+runID, nil)
+		rec := httptest.NewRecorder()
+		newRouter(&mockSnapshotService{}).ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.Contains(t, rec.Body.String(), "POLL_RUN_NOT_FOUND")
+	})
+
+	t.Run("rejects an invalid run id", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodDelete, "/admin/poller/runs/42", nil)
+		rec := httptest.NewRecorder()
+		newRouter(&mockSnapshotService{snapshots: stored}).ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "INVALID_POLL_RUN_ID")
+	})
+}
+
+type mockSymbolPoller struct {
+	prices map[string]decimal.Decimal
+	err    error
+	polled []string
+}
+
+func (m *mockSymbolPoller) PollSymbol(ctx context.Context, name string) (*domain.PriceSnapshot, error) {
+	m.polled = append(m.polled, name)
+	if m.err != nil {
+		return nil, m.err
+	}
+	price, ok := m.prices[name]
+	if !ok {
+		return nil, domain.ErrSymbolNotFound
+	}
+	return &domain.PriceSnapshot{
+		ID:        int64(len(m.polled)),
+		Symbol:    name,
+		Price:     price,
+		Timestamp: time.Date(2024, 1, 15, 10, 30, 2, 0, time.UTC),
+		Exchange:  "binance",
+	}, nil
+}
+
+func TestHandler_PollSymbol(t *testing.T) {
+	newRouter := func(opts ...httpAdapter.HandlerOption) http.Handler {
+		handler := httpAdapter.NewHandler(
+			&mockSymbolService{},
+			&mockSnapshotService{},
+			&mockMetricsService{},
+			&mockExchangeClient{},
+			newTestLogger(),
+			opts...,
+		)
+		return httpAdapter.NewRouter(handler, newTestLogger())
+	}
+
+	t.Run("stores a snapshot of the symbol", func(t *testing.T) {
+		poller := &mockSymbolPoller{prices: map[string]decimal.Decimal{"BTCUSDT": decimal.RequireFromString("42000")}}
+		req := httptest.NewRequest(http.MethodPost, "/admin/poller/symbols/btcusdt/poll", nil)
+		rec := httptest.NewRecorder()
+		newRouter(httpAdapter.WithSymbolPoller(poller)).ServeHTTP(rec, req)
+
+		require.Equal(t, http.StatusCreated, rec.Code)
+		assert.Equal(t, []string{"BTCUSDT"}, poller.polled)
+
+		var resp struct {
+			Symbol string `json:"symbol"`
+			Price  string `json:"price"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.Equal(t, "BTCUSDT", resp.Symbol)
+		assert.Equal(t, "42000", resp.Price)
+	})
+
+	t.Run("returns 404 for an untracked symbol", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/admin/poller/symbols/NOPEUSDT/poll", nil)
+		rec := httptest.NewRecorder()
+		newRouter(httpAdapter.WithSymbolPoller(&mockSymbolPoller{})).ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.Contains(t, rec.Body.String(), "SYMBOL_NOT_FOUND")
+	})
+
+	t.Run("returns 503 when the exchange is unavailable", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/admin/poller/symbols/BTCUSDT/poll", nil)
+		rec := httptest.NewRecorder()
+		newRouter(httpAdapter.WithSymbolPoller(&mockSymbolPoller{err: domain.ErrExchangeUnavailable})).ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	})
+
+	t.Run("is not served without a poller", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/admin/poller/symbols/BTCUSDT/poll", nil)
+		rec := httptest.NewRecorder()
+		newRouter().ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("polls symbols added through the API", func(t *testing.T) {
+		poller := &mockSymbolPoller{prices: map[string]decimal.Decimal{"ETHUSDT": decimal.RequireFromString("2500")}}
+		req := httptest.NewRequest(http.MethodPost, "/symbols", bytes.NewBufferString(`{"symbol": "ETHUSDT"}`))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		newRouter(httpAdapter.WithSymbolPoller(poller)).ServeHTTP(rec, req)
+
+		require.Equal(t, http.StatusCreated, rec.Code)
+		assert.Equal(t, []string{"ETHUSDT"}, poller.polled)
+	})
+
+	t.Run("adds the symbol when its first poll fails", func(t *testing.T) {
+		poller := &mockSymbolPoller{err: domain.ErrExchangeUnavailable}
+		req := httptest.NewRequest(http.MethodPost, "/symbols", bytes.NewBufferString(`{"symbol": "ETHUSDT"}`))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		newRouter(httpAdapter.WithSymbolPoller(poller)).ServeHTTP(rec, req)
+
+		require.Equal(t, http.StatusCreated, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"name":"ETHUSDT"`)
+	})
+}
+
+func TestHandler_Dashboard(t *testing.T) {
+	newRouter := func(opts ...httpAdapter.HandlerOption) http.Handler {
+		handler := httpAdapter.NewHandler(
+			&mockSymbolService{},
+			&mockSnapshotService{},
+			&mockMetricsService{},
+			&mockExchangeClient{},
+			newTestLogger(),
+			opts...,
+		)
+		return httpAdapter.NewRouter(handler, newTestLogger())
+	}
+
+	t.Run("serves the dashboard", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/ui", nil)
+		rec := httptest.NewRecorder()
+
+		newRouter(httpAdapter.WithUI()).ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "text/html; charset=utf-8", rec.Header().Get("Content-Type"))
+		assert.Contains(t, rec.Body.String(), "<title>Price Snapshot Service</title>")
+	})
+
+	t.Run("is not served when disabled", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/ui", nil)
+		rec := httptest.NewRecorder()
+
+		newRouter().ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
+
+type mockAlertBacktestService struct {
+	rule     domain.AlertRule
+	from, to time.Time
+	err      error
+}
+
+func (m *mockAlertBacktestService) Backtest(ctx context.Context, rule domain.AlertRule, from, to time.Time) (*domain.AlertBacktest, error) {
+	m.rule, m.from, m.to = rule, from, to
+	if m.err != nil {
+		return nil, m.err
+	}
+	backtest := domain.NewAlertBacktest(rule, from, to)
+	backtest.Observe(&domain.PriceSnapshot{Price: decimal.NewFromInt(69000), Timestamp: from})
+	backtest.Observe(&domain.PriceSnapshot{Price: decimal.NewFromInt(70100), Timestamp: from.Add(time.Minute)})
+	return backtest, nil
+}
+
+func TestHandler_BacktestAlert(t *testing.T) {
+	newRouter := func(svc *mockAlertBacktestService) http.Handler {
+		handler := httpAdapter.NewHandler(
+			&mockSymbolService{},
+			&mockSnapshotService{},
+			&mockMetricsService{},
+			&mockExchangeClient{},
+			newTestLogger(),
+			httpAdapter.WithAlertBacktest(svc),
+		)
+		return httpAdapter.NewRouter(handler, newTestLogger())
+	}
+	post := func(router http.Handler, target, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, target, bytes.NewBufferString(body))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+	const rule = `{"symbol": "BTCUSDT", "condition": "crosses_above", "threshold": "70000"}`
+
+	t.Run("returns when the rule would have fired", func(t *testing.T) {
+		svc := &mockAlertBacktestService{}
+
+		rec := post(newRouter(svc), "/alerts/backtest?from=2024-01-15T00:00:00Z&to=2024-01-16T00:00:00Z", rule)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{
+			"rule": {"symbol": "BTCUSDT", "condition": "crosses_above", "threshold": "70000"},
+			"from": "2024-01-15T00:00:00Z",
+			"to": "2024-01-16T00:00:00Z",
+			"snapshots": 2,
+			"firings": [{"at": "2024-01-15T00:01:00Z", "price": "70100", "reference": "69000"}]
+		}`, rec.Body.String())
+		assert.Equal(t, time.Date(2024, 1, 16, 0, 0, 0, 0, time.UTC), svc.to)
+	})
+
+	t.Run("defaults to to now", func(t *testing.T) {
+		svc := &mockAlertBacktestService{}
+
+		rec := post(newRouter(svc), "/alerts/backtest?from=2024-01-15T00:00:00Z", rule)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.WithinDuration(t, time.Now(), svc.to, time.Minute)
+	})
+
+	t.Run("requires from", func(t *testing.T) {
+		rec := post(newRouter(&mockAlertBacktestService{}), "/alerts/backtest", rule)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "INVALID_TIME_RANGE")
+	})
+
+	t.Run("returns 400 for an invalid rule", func(t *testing.T) {
+		svc := &mockAlertBacktestService{err: fmt.Errorf("%w: unknown condition", domain.ErrInvalidAlertRule)}
+
+		rec := post(newRouter(svc), "/alerts/backtest?from=2024-01-15T00:00:00Z", `{"symbol": "BTCUSDT", "condition": "sideways"}`)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "INVALID_ALERT_RULE")
+		assert.Contains(t, rec.Body.String(), "unknown condition")
+	})
+
+	t.Run("returns 400 for an unparsable window", func(t *testing.T) {
+		rec := post(newRouter(&mockAlertBacktestService{}), "/alerts/backtest?from=2024-01-15T00:00:00Z", `{"symbol": "BTCUSDT", "condition": "moves_percent", "threshold": "5", "window": "soon"}`)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "INVALID_ALERT_RULE")
+	})
+
+	t.Run("returns 404 for an unknown symbol", func(t *testing.T) {
+		rec := post(newRouter(&mockAlertBacktestService{err: domain.ErrSymbolNotFound}), "/alerts/backtest?from=2024-01-15T00:00:00Z", rule)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
+
+type mockAlertService struct {
+	alerts     []*domain.Alert
+	rule       domain.AlertRule
+	webhookURL string
+	ttl        time.Duration
+	deleted    int64
+	err        error
+}
+
+func (m *mockAlertService) CreateAlert(ctx context.Context, rule domain.AlertRule, webhookURL string) (*domain.Alert, error) {
+	m.rule, m.webhookURL = rule, webhookURL
+	if m.err != nil {
+		return nil, m.err
+	}
+	alert, err := domain.NewAlert(rule, webhookURL)
+	if err != nil {
+		return nil, err
+	}
+	alert.ID = 7
+	alert.CreatedAt = time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	return alert, nil
+}
+
+func (m *mockAlertService) CreateWatch(ctx context.Context, symbol string, price decimal.Decimal, webhookURL string, ttl time.Duration) (*domain.Alert, error) {
+	m.rule = domain.AlertRule{Symbol: symbol, Condition: domain.AlertCrosses, Threshold: price}
+	m.webhookURL, m.ttl = webhookURL, ttl
+	if m.err != nil {
+		return nil, m.err
+	}
+	watch, err := domain.NewWatch(symbol, price, webhookURL, ttl)
+	if err != nil {
+		return nil, err
+	}
+	watch.ID = 8
+	watch.CreatedAt = time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	expiresAt := watch.CreatedAt.Add(ttl)
+	watch.ExpiresAt = &expiresAt
+	return watch, nil
+}
+
+func (m *mockAlertService) ListAlerts(ctx context.Context) ([]*domain.Alert, error) {
+	return m.alerts, m.err
+}
+
+func (m *mockAlertService) DeleteAlert(ctx context.Context, id int64) error {
+	m.deleted = id
+	return m.err
+}
+
+func (m *mockAlertService) Evaluate(ctx context.Context, snapshots []*domain.PriceSnapshot) {}
+
+func TestHandler_Alerts(t *testing.T) {
+	newRouter := func(svc *mockAlertService, opts ...httpAdapter.HandlerOption) http.Handler {
+		handler := httpAdapter.NewHandler(
+			&mockSymbolService{},
+			&mockSnapshotService{},
+			&mockMetricsService{},
+			&mockExchangeClient{},
+			newTestLogger(),
+			append(opts, httpAdapter.WithAlerts(svc))...,
+		)
+		return httpAdapter.NewRouter(handler, newTestLogger())
+	}
+	serve := func(router http.Handler, method, target, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, bytes.NewBufferString(body))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("creates an alert", func(t *testing.T) {
+		svc := &mockAlertService{}
+
+		rec := serve(newRouter(svc), http.MethodPost, "/alerts", `{
+			"symbol": "btcusdt",
+			"condition": "moves_percent",
+			"threshold": "5",
+			"window": "1h",
+			"webhook_url": "https://hooks.example.com/btc"
+		}`)
+
+		require.Equal(t, http.StatusCreated, rec.Code)
+		assert.JSONEq(t, `{
+			"id": 7,
+			"rule": {"symbol": "BTCUSDT", "condition": "moves_percent", "threshold": "5", "window": "1h0m0s"},
+			"webhook_url": "https://hooks.example.com/btc",
+			"firings": 0,
+			"created_at": "2024-01-15T00:00:00Z"
+		}`, rec.Body.String())
+		assert.Equal(t, time.Hour, svc.rule.Window)
+		assert.Equal(t, "https://hooks.example.com/btc", svc.webhookURL)
+	})
+
+	t.Run("resolves symbol aliases", func(t *testing.T) {
+		svc := &mockAlertService{}
+		aliases, err := domain.NewSymbolNormalizer([]string{"XBTUSDT=BTCUSDT"})
+		require.NoError(t, err)
+
+		rec := serve(newRouter(svc, httpAdapter.WithSymbolNormalizer(aliases)), http.MethodPost, "/alerts",
+			`{"symbol": "xbtusdt", "condition": "crosses_above", "threshold": "70000", "webhook_url": "https://hooks.example.com/btc"}`)
+
+		require.Equal(t, http.StatusCreated, rec.Code)
+		assert.Equal(t, "BTCUSDT", svc.rule.Symbol)
+	})
+
+	t.Run("returns 400 for an invalid webhook", func(t *testing.T) {
+		rec := serve(newRouter(&mockAlertService{}), http.MethodPost, "/alerts",
+			`{"symbol": "BTCUSDT", "condition": "crosses_above", "threshold": "70000", "webhook_url": "hooks.example.com"}`)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "INVALID_ALERT_RULE")
+		assert.Contains(t, rec.Body.String(), "webhook_url")
+	})
+
+	t.Run("returns 404 for an untracked symbol", func(t *testing.T) {
+		rec := serve(newRouter(&mockAlertService{err: domain.ErrSymbolNotFound}), http.MethodPost, "/alerts",
+			`{"symbol": "BTCUSDT", "condition": "crosses_above", "threshold": "70000", "webhook_url": "https://hooks.example.com/btc"}`)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("lists alerts with their state", func(t *testing.T) {
+		svc := &mockAlertService{alerts: []*domain.Alert{{
+			ID:         7,
+			Rule:       domain.AlertRule{Symbol: "BTCUSDT", Condition: domain.AlertCrossesAbove, Threshold: decimal.NewFromInt(70000)},
+			WebhookURL: "https://hooks.example.com/btc",
+			Firings:    1,
+			LastFiring: &domain.AlertFiring{
+				At:        time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC),
+				Price:     decimal.NewFromInt(70100),
+				Reference: decimal.NewFromInt(69900),
+			},
+			Delivery:      domain.AlertDeliveryPending,
+			DeliveryError: "unexpected response status: 502",
+			CreatedAt:     time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
+		}}}
+
+		rec := serve(newRouter(svc), http.MethodGet, "/alerts", "")
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"alerts": [{
+			"id": 7,
+			"rule": {"symbol": "BTCUSDT", "condition": "crosses_above", "threshold": "70000"},
+			"webhook_url": "https://hooks.example.com/btc",
+			"firings": 1,
+			"last_firing": {"at": "2024-01-15T12:00:00Z", "price": "70100", "reference": "69900"},
+			"delivery": "pending",
+			"delivery_error": "unexpected response status: 502",
+			"created_at": "2024-01-15T00:00:00Z"
+		}]}`, rec.Body.String())
+	})
+
+	t.Run("lists no alerts as an empty array", func(t *testing.T) {
+		rec := serve(newRouter(&mockAlertService{}), http.MethodGet, "/alerts", "")
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"alerts": []}`, rec.Body.String())
+	})
+
+	t.Run("creates a watch", func(t *testing.T) {
+		svc := &mockAlertService{}
+
+		rec := serve(newRouter(svc), http.MethodPost, "/watch",
+			`{"symbol": "btc/usdt", "price": "100000", "webhook_url": "https://hooks.example.com/btc", "ttl": "6h"}`)
+
+		require.Equal(t, http.StatusCreated, rec.Code)
+		assert.JSONEq(t, `{
+			"id": 8,
+			"rule": {"symbol": "BTCUSDT", "condition": "crosses", "threshold": "100000"},
+			"webhook_url": "https://hooks.example.com/btc",
+			"firings": 0,
+			"once": true,
+			"expires_at": "2024-01-15T06:00:00Z",
+			"created_at": "2024-01-15T00:00:00Z"
+		}`, rec.Body.String())
+		assert.Equal(t, "BTCUSDT", svc.rule.Symbol)
+		assert.Equal(t, 6*time.Hour, svc.ttl)
+	})
+
+	t.Run("returns 400 for an unparsable ttl", func(t *testing.T) {
+		rec := serve(newRouter(&mockAlertService{}), http.MethodPost, "/watch",
+			`{"symbol": "BTCUSDT", "price": "100000", "webhook_url": "https://hooks.example.com/btc", "ttl": "a day"}`)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "INVALID_ALERT_RULE")
+	})
+
+	t.Run("returns 400 for a ttl past the maximum", func(t *testing.T) {
+		rec := serve(newRouter(&mockAlertService{}), http.MethodPost, "/watch",
+			`{"symbol": "BTCUSDT", "price": "100000", "webhook_url": "https://hooks.example.com/btc", "ttl": "2000h"}`)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "ttl must be positive")
+	})
+
+	t.Run("deletes an alert", func(t *testing.T) {
+		svc := &mockAlertService{}
+
+		rec := serve(newRouter(svc), http.MethodDelete, "/alerts/7", "")
+
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		assert.Equal(t, int64(7), svc.deleted)
+	})
+
+	t.Run("returns 404 for an unknown alert", func(t *testing.T) {
+		rec := serve(newRouter(&mockAlertService{err: domain.ErrAlertNotFound}), http.MethodDelete, "/alerts/7", "")
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.Contains(t, rec.Body.String(), "ALERT_NOT_FOUND")
+	})
+}
+
+type recordedRequest struct {
+	route, method string
+	code          int
+}
+
+type mockRequestRecorder struct {
+	requests []recordedRequest
+}
+
+func (m *mockRequestRecorder) RecordHTTPRequest(route, method string, code int, duration time.Duration) {
+	m.requests = append(m.requests, recordedRequest{route: route, method: method, code: code})
+}
+
+func TestRequestMetricsMiddleware(t *testing.T) {
+	recorder := &mockRequestRecorder{}
+	handler := httpAdapter.NewHandler(
+		&mockSymbolService{symbols: []*domain.Symbol{{ID: 1, Name: "BTCUSDT", Active: true}}},
+		&mockSnapshotService{},
+		&mockMetricsService{},
+		&mockExchangeClient{},
+		newTestLogger(),
+		httpAdapter.WithRequestMetrics(recorder),
+	)
+	router := httpAdapter.NewRouter(handler, newTestLogger())
+
+	for _, path := range []string{"/symbols/BTCUSDT", "/symbols/ETHUSDT", "/nope"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	assert.Equal(t, []recordedRequest{
+		{route: "/symbols/{symbol}", method: "GET", code: http.StatusOK},
+		{route: "/symbols/{symbol}", method: "GET", code: http.StatusNotFound},
+		{route: "other", method: "GET", code: http.StatusNotFound},
+	}, recorder.requests)
+}
+
+type mockUsageService struct {
+	recorded     []string
+	counts       []domain.UsageCount
+	from, to     time.Time
+	summaryFrom  time.Time
+	summaryTo    time.Time
+	summarizeErr error
+}
+
+func (m *mockUsageService) RecordUsage(key, endpoint string) {
+	m.recorded = append(m.recorded, key+" "+endpoint)
+}
+
+func (m *mockUsageService) Flush(ctx context.Context) (int, error) {
+	return 0, nil
+}
+
+func (m *mockUsageService) GetUsage(ctx context.Context, from, to time.Time) ([]domain.UsageCount, error) {
+	m.from, m.to = from, to
+	if to.Before(from) {
+		return nil, domain.ErrInvalidTimeRange
+	}
+	return m.counts, nil
+}
+
+func (m *mockUsageService) Summarize(ctx context.Context, from, to time.Time) (*domain.UsageSummary, error) {
+	m.summaryFrom, m.summaryTo = from, to
+	if m.summarizeErr != nil {
+		return nil, m.summarizeErr
+	}
+	return domain.SummarizeUsage(from, to, m.counts), nil
+}
+
+func TestHandler_Usage(t *testing.T) {
+	day := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	counts := []domain.UsageCount{
+		{Day: day, Key: "key:9f86d081884c7d65", Endpoint: "GET /prices", Requests: 42},
+		{Day: day, Key: "anonymous", Endpoint: "GET /symbols", Requests: 3},
+	}
+
+	newRouter := func(usage *mockUsageService) http.Handler {
+		handler := httpAdapter.NewHandler(
+			&mockSymbolService{symbols: []*domain.Symbol{{ID: 1, Name: "BTCUSDT", Active: true}}},
+			&mockSnapshotService{},
+			&mockMetricsService{},
+			&mockExchangeClient{},
+			newTestLogger(),
+			httpAdapter.WithUsage(usage, []string{"test"}),
+		)
+		return httpAdapter.NewRouter(handler, newTestLogger())
+	}
+
+	t.Run("counts requests per key and endpoint", func(t *testing.T) {
+		usage := &mockUsageService{}
+		router := newRouter(usage)
+
+		req := httptest.NewRequest(http.MethodGet, "/symbols/BTCUSDT", nil)
+		req.Header.Set(httpAdapter.APIKeyHeader, "test")
+		router.ServeHTTP(httptest.NewRecorder(), req)
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/symbols", nil))
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/health", nil))
+		unknown := httptest.NewRequest(http.MethodGet, "/symbols", nil)
+		unknown.Header.Set(httpAdapter.APIKeyHeader, "made-up")
+		router.ServeHTTP(httptest.NewRecorder(), unknown)
+
+		assert.Equal(t, []string{
+			"key:9f86d081884c7d65 GET /symbols/{symbol}",
+			"anonymous GET /symbols",
+			"anonymous GET /symbols",
+		}, usage.recorded)
+	})
+
+	t.Run("returns daily counts", func(t *testing.T) {
+		usage := &mockUsageService{counts: counts}
+		req := httptest.NewRequest(http.MethodGet, "/admin/usage?from=2024-01-01&to=2024-01-31", nil)
+		rec := httptest.NewRecorder()
+		newRouter(usage).ServeHTTP(rec, req)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"from": "2024-01-01", "to": "2024-01-31", "usage": [
+			{"day": "2024-01-15T00:00:00Z", "key": "key:9f86d081884c7d65", "endpoint": "GET /prices", "requests": 42},
+			{"day": "2024-01-15T00:00:00Z", "key": "anonymous", "endpoint": "GET /symbols", "requests": 3}
+		]}`, rec.Body.String())
+		assert.Equal(t, time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC), usage.to)
+	})
+
+	t.Run("requires a valid from", func(t *testing.T) {
+		for _, query := range []string{"", "?from=2024-01", "?from=2024-01-01&to=tomorrow", "?from=2024-02-01&to=2024-01-01"} {
+			rec := httptest.NewRecorder()
+			newRouter(&mockUsageService{}).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/usage"+query, nil))
+
+			assert.Equal(t, http.StatusBadRequest, rec.Code, query)
+			assert.Contains(t, rec.Body.String(), "INVALID_TIME_RANGE", query)
+		}
+	})
+
+	t.Run("summarizes a month", func(t *testing.T) {
+		usage := &mockUsageService{counts: counts}
+		req := httptest.NewRequest(http.MethodGet, "/admin/usage/summary?month=2024-01", nil)
+		rec := httptest.NewRecorder()
+		newRouter(usage).ServeHTTP(rec, req)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), usage.summaryFrom)
+		assert.Equal(t, time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), usage.summaryTo)
+
+		var summary domain.UsageSummary
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &summary))
+		assert.Equal(t, int64(45), summary.Requests)
+		require.Len(t, summary.Keys, 2)
+		assert.Equal(t, "key:9f86d081884c7d65", summary.Keys[0].Key)
+	})
+
+	t.Run("rejects an invalid month", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		newRouter(&mockUsageService{}).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/usage/summary?month=January", nil))
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("is not served when disabled", func(t *testing.T) {
+		handler := httpAdapter.NewHandler(
+			&mockSymbolService{},
+			&mockSnapshotService{},
+			&mockMetricsService{},
+			&mockExchangeClient{},
+			newTestLogger(),
+		)
+		rec := httptest.NewRecorder()
+		httpAdapter.NewRouter(handler, newTestLogger()).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/usage?from=2024-01-01", nil))
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
 	})
 }
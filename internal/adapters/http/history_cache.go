@@ -0,0 +1,127 @@
+package http
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+)
+
+const (
+	// closedHistoryCacheControl lets browsers and CDNs keep a history window
+	// that ended longer than the grace period ago, since no new snapshots
+	// land in it anymore
+	closedHistoryCacheControl = "public, max-age=86400, immutable"
+
+	// settlingHistoryCacheControl briefly caches a window that has ended but
+	// may still receive buffered, in-flight or pushed snapshots
+	settlingHistoryCacheControl = "public, max-age=10"
+
+	// liveHistoryCacheControl keeps ranges that are still filling up out of
+	// every cache
+	liveHistoryCacheControl = "no-store"
+
+	// DefaultHistoryCacheGrace is the shortest time after its end before a
+	// history window is cached as immutable
+	DefaultHistoryCacheGrace = 5 * time.Minute
+)
+
+// WithHistoryCacheGrace sets how long after its end a history window may
+// still receive snapshots, e.g. the poll interval plus the write buffer
+// interval. Windows are only cached as immutable once it has passed; it is
+// never shorter than DefaultHistoryCacheGrace.
+func WithHistoryCacheGrace(grace time.Duration) HandlerOption {
+	return func(h *Handler) {
+		h.historyCacheGrace = max(grace, DefaultHistoryCacheGrace)
+	}
+}
+
+// historyRange is the optional time window of a /history request
+type historyRange struct {
+	from, to time.Time
+	// set reports whether from or to was given at all
+	set bool
+	// ended reports whether the window ended before the request
+	ended bool
+	// closed reports whether the window ended more than the grace period
+	// before the request
+	closed bool
+}
+
+// parseHistoryRange reads the RFC 3339 from and to parameters. A missing
+// from reaches back to the first snapshot and a missing to is the present,
+// which keeps the range live. A window is closed once grace has passed
+// since its end.
+func parseHistoryRange(r *http.Request, now time.Time, grace time.Duration) (historyRange, error) {
+	rng := historyRange{to: now}
+
+	from, ok, err := parseTimeParam(r, "from")
+	if err != nil {
+		return rng, err
+	}
+	if ok {
+		rng.from, rng.set = from, true
+	}
+
+	to, ok, err := parseTimeParam(r, "to")
+	if err != nil {
+		return rng, err
+	}
+	if ok {
+		rng.to, rng.set = to, true
+		rng.ended = to.Before(now)
+		rng.closed = to.Before(now.Add(-grace))
+	}
+
+	return rng, nil
+}
+
+// parseTimeParam reads an optional RFC 3339 query parameter
+func parseTimeParam(r *http.Request, name string) (time.Time, bool, error) {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return time.Time{}, false, nil
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return time.Time{}, false, domain.ErrInvalidTimeRange
+	}
+	return t, true, nil
+}
+
+// writeHistoryCaching sets the caching headers of a history response and
+// reports whether the client's copy is still current, in which case a 304
+// has been written. Last-Modified is the newest snapshot in the window
+func writeHistoryCaching(w http.ResponseWriter, r *http.Request, rng historyRange, history []*domain.PriceSnapshot) bool {
+	if !rng.closed {
+		if rng.ended {
+			w.Header().Set("Cache-Control", settlingHistoryCacheControl)
+		} else {
+			w.Header().Set("Cache-Control", liveHistoryCacheControl)
+		}
+		return false
+	}
+
+	w.Header().Set("Cache-Control", closedHistoryCacheControl)
+	w.Header().Add("Vary", "Accept")
+
+	var newest time.Time
+	for _, snap := range history {
+		if snap.Timestamp.After(newest) {
+			newest = snap.Timestamp
+		}
+	}
+	if newest.IsZero() {
+		return false
+	}
+
+	// HTTP dates have second precision
+	newest = newest.UTC().Truncate(time.Second)
+	w.Header().Set("Last-Modified", newest.Format(http.TimeFormat))
+
+	if since, err := http.ParseTime(r.Header.Get("If-Modified-Since")); err == nil && !newest.After(since) {
+		w.WriteHeader(http.StatusNotModified)
+		return true
+	}
+	return false
+}
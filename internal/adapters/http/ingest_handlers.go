@@ -0,0 +1,75 @@
+package http
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+)
+
+// WithIngest enables POST /ingest for callers presenting one of the API keys
+func WithIngest(svc ports.IngestService, apiKeys []string) HandlerOption {
+	return func(h *Handler) {
+		h.ingest = svc
+		h.ingestKeys = apiKeys
+	}
+}
+
+// IngestRequest represents the request body for pushing snapshots
+type IngestRequest struct {
+	Snapshots []domain.IngestedSnapshot `json:"snapshots" validate:"required"`
+}
+
+// Ingest stores snapshots pushed by an external source
+func (h *Handler) Ingest(w http.ResponseWriter, r *http.Request) {
+	var req IngestRequest
+
+	if err := decodeRequest(w, r, maxRequestBodyBytes, &req); err != nil {
+		respondInvalidRequest(w, err)
+		return
+	}
+
+	result, err := h.ingest.Ingest(r.Context(), req.Snapshots)
+	if errors.Is(err, domain.ErrInvalidIngest) {
+		respondJSON(w, http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid snapshot",
+			Code:    "INVALID_SNAPSHOT",
+			Details: err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		handleDomainError(w, err)
+		return
+	}
+
+	respond(w, r, http.StatusOK, result)
+}
+
+// RequireAPIKey rejects requests whose X-API-Key header is not one of keys
+// with 401
+func RequireAPIKey(keys []string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !validAPIKey(r.Header.Get(APIKeyHeader), keys) {
+				respondErrorWithCode(w, http.StatusUnauthorized, "missing or invalid API key", "UNAUTHORIZED")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// validAPIKey compares key against every configured key in constant time
+func validAPIKey(key string, keys []string) bool {
+	if key == "" {
+		return false
+	}
+	valid := 0
+	for _, k := range keys {
+		valid |= subtle.ConstantTimeCompare([]byte(key), []byte(k))
+	}
+	return valid == 1
+}
@@ -0,0 +1,134 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+)
+
+// WithJobs enables the /admin/jobs endpoints backed by the given job
+// service. Job bodies may be up to maxBodyBytes, DefaultMaxJobBodyBytes
+// when it is not positive.
+func WithJobs(jobs ports.JobService, maxBodyBytes int64) HandlerOption {
+	return func(h *Handler) {
+		h.jobs = jobs
+		h.maxJobBodyBytes = maxBodyBytes
+		if maxBodyBytes <= 0 {
+			h.maxJobBodyBytes = DefaultMaxJobBodyBytes
+		}
+	}
+}
+
+// JobsResponse represents a list of jobs
+type JobsResponse struct {
+	Jobs []*domain.Job `json:"jobs"`
+}
+
+// ListJobs returns recent jobs, optionally filtered by ?status=
+func (h *Handler) ListJobs(w http.ResponseWriter, r *http.Request) {
+	status := domain.JobStatus(r.URL.Query().Get("status"))
+
+	limit := 100
+	if limitParam := r.URL.Query().Get("limit"); limitParam != "" {
+		if l, err := strconv.Atoi(limitParam); err == nil && l > 0 && l <= 1000 {
+			limit = l
+		}
+	}
+
+	jobs, err := h.jobs.ListJobs(r.Context(), status, limit)
+	if err != nil {
+		handleDomainError(w, err)
+		return
+	}
+
+	if jobs == nil {
+		jobs = []*domain.Job{}
+	}
+
+	respond(w, r, http.StatusOK, JobsResponse{Jobs: jobs})
+}
+
+// GetJob returns a single job with its progress
+func (h *Handler) GetJob(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid job id")
+		return
+	}
+
+	job, err := h.jobs.GetJob(r.Context(), id)
+	if err != nil {
+		handleDomainError(w, err)
+		return
+	}
+
+	respond(w, r, http.StatusOK, job)
+}
+
+// CreateJobRequest represents the request body for enqueueing a job
+type CreateJobRequest struct {
+	Type    domain.JobType  `json:"type" validate:"required"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+	RunAt   *time.Time      `json:"run_at,omitempty"`
+}
+
+// CreateJob enqueues an ad-hoc or scheduled job
+func (h *Handler) CreateJob(w http.ResponseWriter, r *http.Request) {
+	var req CreateJobRequest
+
+	if err := decodeRequest(w, r, h.maxJobBodyBytes, &req); err != nil {
+		respondInvalidRequest(w, err)
+		return
+	}
+
+	var runAt time.Time
+	if req.RunAt != nil {
+		runAt = *req.RunAt
+	}
+
+	job, err := h.jobs.Enqueue(r.Context(), req.Type, req.Payload, runAt)
+	if err != nil {
+		handleDomainError(w, err)
+		return
+	}
+
+	respond(w, r, http.StatusAccepted, job)
+}
+
+// CancelJob cancels a pending or running job, keeping its progress
+func (h *Handler) CancelJob(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid job id")
+		return
+	}
+
+	job, err := h.jobs.CancelJob(r.Context(), id)
+	if err != nil {
+		handleDomainError(w, err)
+		return
+	}
+
+	respond(w, r, http.StatusOK, job)
+}
+
+// ResumeJob requeues a cancelled or failed job from its recorded progress
+func (h *Handler) ResumeJob(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid job id")
+		return
+	}
+
+	job, err := h.jobs.ResumeJob(r.Context(), id)
+	if err != nil {
+		handleDomainError(w, err)
+		return
+	}
+
+	respond(w, r, http.StatusAccepted, job)
+}
@@ -0,0 +1,26 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+)
+
+// WithMigrations enables the /admin/migrations endpoint backed by the given provider
+func WithMigrations(migrations ports.MigrationStatusProvider) HandlerOption {
+	return func(h *Handler) {
+		h.migrations = migrations
+	}
+}
+
+// GetMigrationStatus returns the applied schema version and dirty flag
+func (h *Handler) GetMigrationStatus(w http.ResponseWriter, r *http.Request) {
+	status, err := h.migrations.MigrationStatus(r.Context())
+	if err != nil {
+		h.logger.Error("failed to get migration status", "error", err)
+		handleDomainError(w, err)
+		return
+	}
+
+	respond(w, r, http.StatusOK, status)
+}
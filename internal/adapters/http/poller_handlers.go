@@ -0,0 +1,94 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+)
+
+// WithSymbolPoller enables polling a single symbol on demand: the admin
+// poll trigger, a first snapshot for symbols added through the API, and
+// stored prices for symbols registered by ?track_missing=true
+func WithSymbolPoller(poller ports.SymbolPoller) HandlerOption {
+	return func(h *Handler) {
+		h.poller = poller
+	}
+}
+
+// PollerSymbolsResponse lists the effective symbol list of each poller
+// group's most recent poll
+type PollerSymbolsResponse struct {
+	Runs []*domain.PollSymbols `json:"runs"`
+}
+
+// GetPollerSymbols returns which symbols the most recent polls included and
+// why the others were excluded. ?symbol= narrows every run to one symbol.
+func (h *Handler) GetPollerSymbols(w http.ResponseWriter, r *http.Request) {
+	runs := h.metricsSvc.GetPollSymbols()
+
+	if symbol := h.symbols.Normalize(r.URL.Query().Get("symbol")); symbol != "" {
+		filtered := make([]*domain.PollSymbols, len(runs))
+		for i, run := range runs {
+			filtered[i] = run.ForSymbol(symbol)
+		}
+		runs = filtered
+	}
+
+	respond(w, r, http.StatusOK, PollerSymbolsResponse{Runs: runs})
+}
+
+// GetPollRun returns the snapshots stored by the poll with the given run ID
+func (h *Handler) GetPollRun(w http.ResponseWriter, r *http.Request) {
+	runID, ok := domain.NormalizePollRunID(r.PathValue("id"))
+	if !ok {
+		respondErrorWithCode(w, http.StatusBadRequest, "invalid poll run id", "INVALID_POLL_RUN_ID")
+		return
+	}
+
+	run, err := h.snapshotSvc.GetPollRun(r.Context(), runID)
+	if err != nil {
+		handleDomainError(w, err)
+		return
+	}
+
+	respond(w, r, http.StatusOK, run)
+}
+
+// DeletePollRunResponse reports the snapshots removed with a poll run
+type DeletePollRunResponse struct {
+	RunID   string `json:"run_id"`
+	Deleted int64  `json:"deleted"`
+}
+
+// DeletePollRun removes the snapshots stored by the poll with the given
+// run ID, e.g. after the exchange returned bad prices
+func (h *Handler) DeletePollRun(w http.ResponseWriter, r *http.Request) {
+	runID, ok := domain.NormalizePollRunID(r.PathValue("id"))
+	if !ok {
+		respondErrorWithCode(w, http.StatusBadRequest, "invalid poll run id", "INVALID_POLL_RUN_ID")
+		return
+	}
+
+	deleted, err := h.snapshotSvc.DeletePollRun(r.Context(), runID)
+	if err != nil {
+		handleDomainError(w, err)
+		return
+	}
+
+	respond(w, r, http.StatusOK, DeletePollRunResponse{RunID: runID, Deleted: deleted})
+}
+
+// PollSymbol fetches a tracked symbol's current price and stores it as a
+// snapshot right away, without waiting for the next poll
+func (h *Handler) PollSymbol(w http.ResponseWriter, r *http.Request) {
+	symbol := h.symbols.Normalize(r.PathValue("symbol"))
+
+	snap, err := h.poller.PollSymbol(r.Context(), symbol)
+	if err != nil {
+		handleDomainError(w, err)
+		return
+	}
+
+	respond(w, r, http.StatusCreated, snap)
+}
@@ -0,0 +1,50 @@
+package http
+
+import (
+	"encoding/json"
+
+	"github.com/shopspring/decimal"
+)
+
+// PriceFormat controls how prices are serialized in API responses
+type PriceFormat string
+
+const (
+	// PriceFormatString serializes prices as exact decimal strings (default)
+	PriceFormatString PriceFormat = "string"
+
+	// PriceFormatNumber serializes prices as JSON numbers with fixed decimals
+	PriceFormatNumber PriceFormat = "number"
+
+	// PriceFormatBoth serializes the exact string in "price" and the
+	// fixed-decimal number in "price_num"
+	PriceFormatBoth PriceFormat = "both"
+)
+
+// priceFormatter renders decimal prices according to the configured format
+type priceFormatter struct {
+	format   PriceFormat
+	decimals int32
+}
+
+// render returns the value for the "price" field and, in "both" mode,
+// the value for the "price_num" field
+func (f priceFormatter) render(price decimal.Decimal) (interface{}, *json.Number) {
+	switch f.format {
+	case PriceFormatNumber:
+		return json.Number(price.StringFixed(f.decimals)), nil
+	case PriceFormatBoth:
+		num := json.Number(price.StringFixed(f.decimals))
+		return price.String(), &num
+	default:
+		return price.String(), nil
+	}
+}
+
+// numberString returns the textual form of an optional number
+func numberString(n *json.Number) string {
+	if n == nil {
+		return ""
+	}
+	return n.String()
+}
@@ -0,0 +1,72 @@
+package http
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+)
+
+// APIKeyHeader carries the API key requests are rate limited by
+const APIKeyHeader = "X-API-Key"
+
+// WithRateLimiter limits requests per API key, or per client IP for
+// requests without one of keys
+func WithRateLimiter(limiter ports.RateLimiter, keys []string) HandlerOption {
+	return func(h *Handler) {
+		h.rateLimiter = limiter
+		h.rateLimitKeys = keys
+	}
+}
+
+// RateLimitMiddleware rejects requests over the limit of their API key or
+// client IP with 429 and reports the remaining quota in X-RateLimit-*
+// headers. Only keys listed in keys get their own limit; requests with any
+// other key are limited by client IP. Health probes are not limited, and
+// requests are let through when the counters cannot be reached.
+func RateLimitMiddleware(limiter ports.RateLimiter, keys []string, logger *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/health" || r.URL.Path == "/ready" || r.Method == http.MethodOptions {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			decision, err := limiter.Allow(r.Context(), rateLimitKey(r, keys))
+			if err != nil {
+				logger.Warn("rate limit check failed, allowing request", "path", r.URL.Path, "error", err)
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Set("X-RateLimit-Limit", strconv.Itoa(decision.Limit))
+			w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(decision.Remaining))
+			w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(decision.ResetAt.Unix(), 10))
+
+			if !decision.Allowed {
+				retryAfter := max(int(math.Ceil(time.Until(decision.ResetAt).Seconds())), 1)
+				w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+				respondErrorWithCode(w, http.StatusTooManyRequests, "rate limit exceeded", "RATE_LIMIT_EXCEEDED")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// rateLimitKey identifies the caller. API keys are hashed so the raw key is
+// never stored with the counters. Unknown keys are ignored, so a client
+// cannot escape its IP limit by sending a new key with each request.
+func rateLimitKey(r *http.Request, keys []string) string {
+	if apiKey := r.Header.Get(APIKeyHeader); validAPIKey(apiKey, keys) {
+		sum := sha256.Sum256([]byte(apiKey))
+		return "key:" + hex.EncodeToString(sum[:])
+	}
+	return "ip:" + ClientIP(r)
+}
@@ -0,0 +1,113 @@
+package http_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	httpAdapter "github.com/prxgr4mmer/price-snapshot-service/internal/adapters/http"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+)
+
+type mockRateLimiter struct {
+	limit  int
+	counts map[string]int
+	err    error
+}
+
+func (m *mockRateLimiter) Allow(ctx context.Context, key string) (*domain.RateLimitDecision, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	if m.counts == nil {
+		m.counts = make(map[string]int)
+	}
+	m.counts[key]++
+	return domain.NewRateLimitDecision(m.limit, m.counts[key], time.Now().Add(30*time.Second)), nil
+}
+
+var testAPIKeys = []string{"key-a", "key-b"}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	request := func(handler http.Handler, path, apiKey string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.RemoteAddr = "203.0.113.7:5555"
+		if apiKey != "" {
+			req.Header.Set(httpAdapter.APIKeyHeader, apiKey)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("rejects requests over the limit", func(t *testing.T) {
+		handler := httpAdapter.RateLimitMiddleware(&mockRateLimiter{limit: 2}, testAPIKeys, newTestLogger())(next)
+
+		first := request(handler, "/prices", "key-a")
+		require.Equal(t, http.StatusOK, first.Code)
+		assert.Equal(t, "2", first.Header().Get("X-RateLimit-Limit"))
+		assert.Equal(t, "1", first.Header().Get("X-RateLimit-Remaining"))
+
+		assert.Equal(t, http.StatusOK, request(handler, "/prices", "key-a").Code)
+
+		rejected := request(handler, "/prices", "key-a")
+		assert.Equal(t, http.StatusTooManyRequests, rejected.Code)
+		assert.Equal(t, "0", rejected.Header().Get("X-RateLimit-Remaining"))
+		assert.NotEmpty(t, rejected.Header().Get("Retry-After"))
+		assert.Contains(t, rejected.Body.String(), "RATE_LIMIT_EXCEEDED")
+	})
+
+	t.Run("counts keys and client IPs separately", func(t *testing.T) {
+		limiter := &mockRateLimiter{limit: 1}
+		handler := httpAdapter.RateLimitMiddleware(limiter, testAPIKeys, newTestLogger())(next)
+
+		assert.Equal(t, http.StatusOK, request(handler, "/prices", "key-a").Code)
+		assert.Equal(t, http.StatusOK, request(handler, "/prices", "key-b").Code)
+		assert.Equal(t, http.StatusOK, request(handler, "/prices", "").Code)
+
+		require.Len(t, limiter.counts, 3)
+		assert.Contains(t, limiter.counts, "ip:203.0.113.7")
+		for key := range limiter.counts {
+			assert.NotContains(t, key, "key-a", "raw API keys must not be stored")
+			if strings.HasPrefix(key, "key:") {
+				assert.Len(t, key, len("key:")+64)
+			}
+		}
+	})
+
+	t.Run("limits unknown keys by client IP", func(t *testing.T) {
+		limiter := &mockRateLimiter{limit: 1}
+		handler := httpAdapter.RateLimitMiddleware(limiter, testAPIKeys, newTestLogger())(next)
+
+		assert.Equal(t, http.StatusOK, request(handler, "/prices", "random-1").Code)
+		assert.Equal(t, http.StatusTooManyRequests, request(handler, "/prices", "random-2").Code)
+		assert.Equal(t, map[string]int{"ip:203.0.113.7": 2}, limiter.counts)
+	})
+
+	t.Run("does not limit health probes", func(t *testing.T) {
+		limiter := &mockRateLimiter{limit: 0}
+		handler := httpAdapter.RateLimitMiddleware(limiter, testAPIKeys, newTestLogger())(next)
+
+		assert.Equal(t, http.StatusOK, request(handler, "/health", "").Code)
+		assert.Equal(t, http.StatusOK, request(handler, "/ready", "").Code)
+		assert.Empty(t, limiter.counts)
+	})
+
+	t.Run("allows requests when counters are unavailable", func(t *testing.T) {
+		handler := httpAdapter.RateLimitMiddleware(&mockRateLimiter{err: errors.New("db down")}, testAPIKeys, newTestLogger())(next)
+
+		rec := request(handler, "/prices", "key-a")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Empty(t, rec.Header().Get("X-RateLimit-Limit"))
+	})
+}
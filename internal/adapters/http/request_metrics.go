@@ -0,0 +1,48 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+)
+
+// unroutedRoute labels requests answered before or without a route match,
+// such as rate-limited requests and unknown paths
+const unroutedRoute = "other"
+
+// WithRequestMetrics records the latency of every request
+func WithRequestMetrics(recorder ports.HTTPRequestRecorder) HandlerOption {
+	return func(h *Handler) {
+		h.requests = recorder
+	}
+}
+
+// RequestMetricsMiddleware records each request's latency under the route
+// pattern it was served by, e.g. /symbols/{symbol}, so that paths with
+// parameters share a series
+func RequestMetricsMiddleware(recorder ports.HTTPRequestRecorder) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			wrapped := &responseWriter{
+				ResponseWriter: w,
+				status:         http.StatusOK,
+			}
+
+			next.ServeHTTP(wrapped, r)
+
+			// The router sets the pattern on the request it was handed
+			route := r.Pattern
+			if _, path, ok := strings.Cut(route, " "); ok {
+				route = path
+			}
+			if route == "" {
+				route = unroutedRoute
+			}
+
+			recorder.RecordHTTPRequest(route, r.Method, wrapped.status, time.Since(start))
+		})
+	}
+}
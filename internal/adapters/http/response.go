@@ -1,6 +1,7 @@
 package http
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"net/http"
@@ -10,11 +11,13 @@ import (
 
 // Response helpers for consistent JSON responses
 
-// ErrorResponse represents an error response
+// ErrorResponse represents an error response. Fields lists the invalid
+// fields of a rejected request body.
 type ErrorResponse struct {
-	Error   string `json:"error"`
-	Code    string `json:"code,omitempty"`
-	Details string `json:"details,omitempty"`
+	Error   string       `json:"error"`
+	Code    string       `json:"code,omitempty"`
+	Details string       `json:"details,omitempty"`
+	Fields  []FieldError `json:"fields,omitempty"`
 }
 
 // respondJSON sends a JSON response with the given status code
@@ -26,6 +29,35 @@ func respondJSON(w http.ResponseWriter, status int, data interface{}) {
 	}
 }
 
+// respond sends a successful response encoded in the negotiated format
+func respond(w http.ResponseWriter, r *http.Request, status int, data interface{}) {
+	enc, ok := negotiateEncoder(r)
+	if !ok {
+		respondErrorWithCode(w, http.StatusNotAcceptable, "unsupported response format", "UNSUPPORTED_FORMAT")
+		return
+	}
+	respondWith(w, enc, status, data)
+}
+
+// respondWith sends a successful response encoded by enc
+func respondWith(w http.ResponseWriter, enc Encoder, status int, data interface{}) {
+	// Encode into a buffer first so that a payload the encoder cannot
+	// represent still produces a clean error response
+	var buf bytes.Buffer
+	if err := enc.Encode(&buf, data); err != nil {
+		if errors.Is(err, errNotTabular) || errors.Is(err, errNotMetrics) {
+			respondErrorWithCode(w, http.StatusNotAcceptable, "response is not available in the requested format", "UNSUPPORTED_FORMAT")
+			return
+		}
+		respondErrorWithCode(w, http.StatusInternalServerError, "failed to encode response", "INTERNAL_ERROR")
+		return
+	}
+
+	w.Header().Set("Content-Type", enc.ContentType())
+	w.WriteHeader(status)
+	w.Write(buf.Bytes())
+}
+
 // respondError sends an error response
 func respondError(w http.ResponseWriter, status int, message string) {
 	respondJSON(w, status, ErrorResponse{Error: message})
@@ -45,21 +77,60 @@ func handleDomainError(w http.ResponseWriter, err error) {
 	case errors.Is(err, domain.ErrSymbolNotFound):
 		respondErrorWithCode(w, http.StatusNotFound, "symbol not found", "SYMBOL_NOT_FOUND")
 
+	case errors.Is(err, domain.ErrInvalidTag):
+		respondErrorWithCode(w, http.StatusBadRequest, "invalid tag", "INVALID_TAG")
+
 	case errors.Is(err, domain.ErrSymbolExists):
 		respondErrorWithCode(w, http.StatusConflict, "symbol already exists", "SYMBOL_EXISTS")
 
+	case errors.Is(err, domain.ErrSymbolQuota):
+		respondErrorWithCode(w, http.StatusUnprocessableEntity, err.Error(), "SYMBOL_QUOTA_EXCEEDED")
+
 	case errors.Is(err, domain.ErrSnapshotNotFound):
 		respondErrorWithCode(w, http.StatusNotFound, "snapshot not found", "SNAPSHOT_NOT_FOUND")
 
+	case errors.Is(err, domain.ErrPollRunNotFound):
+		respondErrorWithCode(w, http.StatusNotFound, "poll run not found", "POLL_RUN_NOT_FOUND")
+
+	case errors.Is(err, domain.ErrInvalidAlertRule):
+		respondErrorWithCode(w, http.StatusBadRequest, "invalid alert rule", "INVALID_ALERT_RULE")
+
+	case errors.Is(err, domain.ErrAlertNotFound):
+		respondErrorWithCode(w, http.StatusNotFound, "alert not found", "ALERT_NOT_FOUND")
+
+	case errors.Is(err, domain.ErrInvalidTimeRange):
+		respondErrorWithCode(w, http.StatusBadRequest, "invalid time range", "INVALID_TIME_RANGE")
+
+	case errors.Is(err, domain.ErrInvalidCursor):
+		respondErrorWithCode(w, http.StatusBadRequest, "invalid cursor", "INVALID_CURSOR")
+
+	case errors.Is(err, domain.ErrTickerStatsNotFound):
+		respondErrorWithCode(w, http.StatusNotFound, "ticker stats not found", "TICKER_STATS_NOT_FOUND")
+
 	case errors.Is(err, domain.ErrExchangeUnavailable):
 		respondErrorWithCode(w, http.StatusServiceUnavailable, "exchange service unavailable", "EXCHANGE_UNAVAILABLE")
 
 	case errors.Is(err, domain.ErrRateLimited):
 		respondErrorWithCode(w, http.StatusTooManyRequests, "rate limited by exchange", "RATE_LIMITED")
 
+	case errors.Is(err, domain.ErrUnknownExchange):
+		respondErrorWithCode(w, http.StatusBadRequest, err.Error(), "UNKNOWN_EXCHANGE")
+
 	case errors.Is(err, domain.ErrInvalidResponse):
 		respondErrorWithCode(w, http.StatusBadGateway, "invalid response from exchange", "INVALID_EXCHANGE_RESPONSE")
 
+	case errors.Is(err, domain.ErrJobNotFound):
+		respondErrorWithCode(w, http.StatusNotFound, "job not found", "JOB_NOT_FOUND")
+
+	case errors.Is(err, domain.ErrInvalidJobType):
+		respondErrorWithCode(w, http.StatusBadRequest, "invalid job type", "INVALID_JOB_TYPE")
+
+	case errors.Is(err, domain.ErrJobFinished):
+		respondErrorWithCode(w, http.StatusConflict, "job already finished", "JOB_FINISHED")
+
+	case errors.Is(err, domain.ErrJobNotResumable):
+		respondErrorWithCode(w, http.StatusConflict, "job cannot be resumed", "JOB_NOT_RESUMABLE")
+
 	case errors.Is(err, domain.ErrDatabaseConnection):
 		respondErrorWithCode(w, http.StatusServiceUnavailable, "database connection error", "DATABASE_ERROR")
 
@@ -0,0 +1,25 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+)
+
+// WithRetention enables the /admin/retention report backed by the given service
+func WithRetention(retention ports.RetentionService) HandlerOption {
+	return func(h *Handler) {
+		h.retention = retention
+	}
+}
+
+// GetRetentionReport returns storage growth rates and the disk threshold forecast
+func (h *Handler) GetRetentionReport(w http.ResponseWriter, r *http.Request) {
+	report, err := h.retention.Report(r.Context())
+	if err != nil {
+		handleDomainError(w, err)
+		return
+	}
+
+	respond(w, r, http.StatusOK, report)
+}
@@ -11,11 +11,19 @@ func NewRouter(h *Handler, logger *slog.Logger) http.Handler {
 
 	// Health check
 	mux.HandleFunc("GET /health", h.Health)
+	mux.HandleFunc("GET /ready", h.Ready)
 
 	// Symbols management
 	mux.HandleFunc("GET /symbols", h.ListSymbols)
 	mux.HandleFunc("POST /symbols", h.CreateSymbol)
+	mux.HandleFunc("GET /symbols/stale", h.ListStaleSymbols)
+	mux.HandleFunc("GET /symbols/{symbol}", h.GetSymbol)
 	mux.HandleFunc("DELETE /symbols/{symbol}", h.DeleteSymbol)
+	mux.HandleFunc("PATCH /symbols/{symbol}", h.UpdateSymbol)
+	mux.HandleFunc("PATCH /symbols/batch", h.UpdateSymbols)
+	mux.HandleFunc("PUT /symbols/{symbol}/tags", h.SetSymbolTags)
+	mux.HandleFunc("DELETE /symbols/{symbol}/tags/{tag}", h.DeleteSymbolTag)
+	mux.HandleFunc("GET /symbols/{symbol}/events", h.GetSymbolEvents)
 
 	// Prices
 	mux.HandleFunc("GET /prices", h.GetPrices)
@@ -23,15 +31,98 @@ func NewRouter(h *Handler, logger *slog.Logger) http.Handler {
 	// History
 	mux.HandleFunc("GET /history", h.GetHistory)
 
+	// Snapshot ingestion from push-based sources
+	if h.ingest != nil {
+		mux.Handle("POST /ingest", RequireAPIKey(h.ingestKeys)(http.HandlerFunc(h.Ingest)))
+	}
+
+	// Price alerts and one-shot watches with webhooks
+	if h.alerts != nil {
+		mux.HandleFunc("GET /alerts", h.ListAlerts)
+		mux.HandleFunc("POST /alerts", h.CreateAlert)
+		mux.HandleFunc("DELETE /alerts/{id}", h.DeleteAlert)
+		mux.HandleFunc("POST /watch", h.CreateWatch)
+	}
+
+	// Alert rule backtesting
+	if h.backtests != nil {
+		mux.HandleFunc("POST /alerts/backtest", h.BacktestAlert)
+	}
+
+	// 24h ticker stats
+	if h.ticker != nil {
+		mux.HandleFunc("GET /ticker24h", h.GetTicker24h)
+	}
+
+	// OHLC candles
+	if h.candles != nil {
+		mux.HandleFunc("GET /candles", h.GetCandles)
+	}
+
 	// Metrics
 	mux.HandleFunc("GET /metrics", h.GetMetrics)
+	mux.HandleFunc("GET /metrics/prometheus", h.GetPrometheusMetrics)
+
+	// Admin jobs
+	if h.jobs != nil {
+		mux.HandleFunc("GET /admin/jobs", h.ListJobs)
+		mux.HandleFunc("POST /admin/jobs", h.CreateJob)
+		mux.HandleFunc("GET /admin/jobs/{id}", h.GetJob)
+		mux.HandleFunc("DELETE /admin/jobs/{id}", h.CancelJob)
+		mux.HandleFunc("POST /admin/jobs/{id}/resume", h.ResumeJob)
+	}
+
+	// Admin poller symbol selection and poll runs
+	mux.HandleFunc("GET /admin/poller/symbols", h.GetPollerSymbols)
+	mux.HandleFunc("GET /admin/poller/runs/{id}", h.GetPollRun)
+	mux.HandleFunc("DELETE /admin/poller/runs/{id}", h.DeletePollRun)
+	if h.poller != nil {
+		mux.HandleFunc("POST /admin/poller/symbols/{symbol}/poll", h.PollSymbol)
+	}
+
+	// Admin snapshot range deletion
+	mux.HandleFunc("DELETE /admin/snapshots", h.DeleteSnapshots)
+
+	// Admin schema migration status
+	if h.migrations != nil {
+		mux.HandleFunc("GET /admin/migrations", h.GetMigrationStatus)
+	}
+
+	// Admin API usage statistics
+	if h.usage != nil {
+		mux.HandleFunc("GET /admin/usage", h.GetUsage)
+		mux.HandleFunc("GET /admin/usage/summary", h.GetUsageSummary)
+	}
+
+	// Admin storage growth report
+	if h.retention != nil {
+		mux.HandleFunc("GET /admin/retention", h.GetRetentionReport)
+	}
+
+	// Dashboard
+	if h.ui {
+		mux.HandleFunc("GET /ui", h.Dashboard)
+	}
 
 	// Apply middleware chain (order matters: outer -> inner)
 	var handler http.Handler = mux
 	handler = ContentTypeMiddleware(handler)
+	if h.faults != nil {
+		handler = FaultInjectionMiddleware(h.faults, logger)(handler)
+	}
+	if h.usage != nil {
+		handler = UsageMiddleware(h.usage, h.usageKeys)(handler)
+	}
+	if h.rateLimiter != nil {
+		handler = RateLimitMiddleware(h.rateLimiter, h.rateLimitKeys, logger)(handler)
+	}
 	handler = CORSMiddleware(handler)
+	if h.requests != nil {
+		handler = RequestMetricsMiddleware(h.requests)(handler)
+	}
 	handler = RecoveryMiddleware(logger)(handler)
 	handler = LoggingMiddleware(logger)(handler)
+	handler = ClientIPMiddleware(h.trustedProxies)(handler)
 
 	return handler
 }
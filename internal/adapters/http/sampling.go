@@ -0,0 +1,48 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+)
+
+const (
+	minSamplePoints = 3
+	maxSamplePoints = 5000
+
+	// maxSampledRows caps the snapshots read for a sampled history; a
+	// longer window is sampled from its newest rows
+	maxSampledRows = 100000
+)
+
+// parseSampling reads the optional ?points= and ?algorithm= downsampling
+// parameters, e.g. points=500&algorithm=lttb. It returns nil without
+// points; the algorithm defaults to lttb.
+func parseSampling(r *http.Request) (*domain.HistorySampling, error) {
+	q := r.URL.Query()
+	pointsParam := q.Get("points")
+	algorithm := domain.SamplingAlgorithm(q.Get("algorithm"))
+
+	if pointsParam == "" {
+		if algorithm != "" {
+			return nil, fmt.Errorf("algorithm requires points")
+		}
+		return nil, nil
+	}
+
+	points, err := strconv.Atoi(pointsParam)
+	if err != nil || points < minSamplePoints || points > maxSamplePoints {
+		return nil, fmt.Errorf("points must be between %d and %d", minSamplePoints, maxSamplePoints)
+	}
+
+	switch algorithm {
+	case "", domain.SamplingLTTB:
+		algorithm = domain.SamplingLTTB
+	default:
+		return nil, fmt.Errorf("unknown sampling algorithm %q", algorithm)
+	}
+
+	return &domain.HistorySampling{Algorithm: algorithm, Points: points}, nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"net"
 	"net/http"
 	"time"
 
@@ -26,8 +27,18 @@ func NewServer(
 	metricsSvc ports.MetricsService,
 	exchange ports.ExchangeClient,
 	logger *slog.Logger,
-) *Server {
-	handler := NewHandler(symbolSvc, snapshotSvc, metricsSvc, exchange, logger)
+	opts ...HandlerOption,
+) (*Server, error) {
+	trustedProxies, err := ParseTrustedProxies(cfg.TrustedProxies)
+	if err != nil {
+		return nil, err
+	}
+
+	opts = append([]HandlerOption{
+		WithPriceFormat(PriceFormat(cfg.PriceFormat), cfg.PriceDecimals),
+		WithTrustedProxies(trustedProxies),
+	}, opts...)
+	handler := NewHandler(symbolSvc, snapshotSvc, metricsSvc, exchange, logger, opts...)
 	router := NewRouter(handler, logger)
 
 	return &Server{
@@ -37,17 +48,54 @@ func NewServer(
 			ReadTimeout:  cfg.ReadTimeout,
 			WriteTimeout: cfg.WriteTimeout,
 			IdleTimeout:  cfg.IdleTimeout,
+			Protocols:    protocols(cfg),
 		},
 		config: cfg,
 		logger: logger.With("component", "http_server"),
-	}
+	}, nil
+}
+
+// protocols returns the protocols the server accepts. HTTP/2 is only
+// negotiated over TLS; h2c accepts cleartext HTTP/2 (prior knowledge) and is
+// meant for deployments where a trusted proxy terminates TLS.
+func protocols(cfg config.ServerConfig) *http.Protocols {
+	p := new(http.Protocols)
+	p.SetHTTP1(true)
+	p.SetHTTP2(cfg.HTTP2)
+	p.SetUnencryptedHTTP2(cfg.H2C)
+	return p
 }
 
 // Start starts the HTTP server
 func (s *Server) Start() error {
-	s.logger.Info("starting http server", "addr", s.server.Addr)
+	ln, err := net.Listen("tcp", s.server.Addr)
+	if err != nil {
+		return fmt.Errorf("http server error: %w", err)
+	}
+	return s.Serve(ln)
+}
+
+// Serve accepts connections on the listener, using TLS when configured
+func (s *Server) Serve(ln net.Listener) error {
+	s.logger.Info("starting http server",
+		"addr", ln.Addr().String(),
+		"tls", s.config.TLSEnabled(),
+		"http2", s.config.HTTP2 && s.config.TLSEnabled(),
+		"h2c", s.config.H2C,
+	)
+
+	if s.config.H2C && len(s.config.TrustedProxies) == 0 {
+		s.logger.Warn("h2c enabled without trusted proxies; cleartext HTTP/2 is exposed to clients directly")
+	}
+
+	var err error
+	if s.config.TLSEnabled() {
+		err = s.server.ServeTLS(ln, s.config.TLSCertFile, s.config.TLSKeyFile)
+	} else {
+		err = s.server.Serve(ln)
+	}
 
-	if err := s.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+	if err != nil && err != http.ErrServerClosed {
 		return fmt.Errorf("http server error: %w", err)
 	}
 	return nil
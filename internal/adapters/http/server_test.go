@@ -0,0 +1,80 @@
+package http_test
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	httpAdapter "github.com/prxgr4mmer/price-snapshot-service/internal/adapters/http"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/config"
+)
+
+func TestServer_H2C(t *testing.T) {
+	serve := func(t *testing.T, h2c bool) string {
+		server, err := httpAdapter.NewServer(
+			config.ServerConfig{
+				ReadTimeout:  5 * time.Second,
+				WriteTimeout: 5 * time.Second,
+				HTTP2:        true,
+				H2C:          h2c,
+			},
+			&mockSymbolService{},
+			&mockSnapshotService{},
+			&mockMetricsService{},
+			&mockExchangeClient{},
+			newTestLogger(),
+		)
+		require.NoError(t, err)
+
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+
+		go server.Serve(ln)
+		t.Cleanup(func() { server.Shutdown(context.Background()) })
+
+		return "http://" + ln.Addr().String()
+	}
+
+	h2cClient := func() *http.Client {
+		protocols := new(http.Protocols)
+		protocols.SetUnencryptedHTTP2(true)
+		return &http.Client{Transport: &http.Transport{Protocols: protocols}, Timeout: 5 * time.Second}
+	}
+
+	t.Run("serves cleartext HTTP/2 when enabled", func(t *testing.T) {
+		url := serve(t, true)
+
+		resp, err := h2cClient().Get(url + "/health")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, 2, resp.ProtoMajor)
+	})
+
+	t.Run("still serves HTTP/1.1 when enabled", func(t *testing.T) {
+		url := serve(t, true)
+
+		resp, err := http.Get(url + "/health")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, 1, resp.ProtoMajor)
+	})
+
+	t.Run("rejects cleartext HTTP/2 when disabled", func(t *testing.T) {
+		url := serve(t, false)
+
+		resp, err := h2cClient().Get(url + "/health")
+		if err == nil {
+			resp.Body.Close()
+		}
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,75 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+)
+
+// DeleteSnapshots removes one symbol's snapshots in [?from=, ?to=), e.g.
+// after a bad import or an exchange glitch. With ?dry_run=true it only
+// reports what would be removed. Every call is audit logged.
+func (h *Handler) DeleteSnapshots(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	symbol := q.Get("symbol")
+	if symbol == "" {
+		respondError(w, http.StatusBadRequest, "symbol is required")
+		return
+	}
+
+	from, fromSet, err := parseTimeParam(r, "from")
+	if err != nil {
+		handleDomainError(w, err)
+		return
+	}
+	to, toSet, err := parseTimeParam(r, "to")
+	if err != nil {
+		handleDomainError(w, err)
+		return
+	}
+	if !fromSet || !toSet {
+		respondErrorWithCode(w, http.StatusBadRequest, "from and to are required", "INVALID_TIME_RANGE")
+		return
+	}
+
+	dryRun := false
+	if param := q.Get("dry_run"); param != "" {
+		dryRun, err = strconv.ParseBool(param)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "dry_run must be true or false")
+			return
+		}
+	}
+
+	result, err := h.snapshotSvc.DeleteSnapshotRange(r.Context(), symbol, from, to, dryRun)
+	if err != nil {
+		handleDomainError(w, err)
+		return
+	}
+
+	h.auditSnapshotDeletion(r, result)
+	respond(w, r, http.StatusOK, result)
+}
+
+// auditSnapshotDeletion logs who removed which snapshots. Dry runs are
+// logged too, so an investigation shows what was looked at before a delete.
+func (h *Handler) auditSnapshotDeletion(r *http.Request, result *domain.SnapshotRangeDeletion) {
+	msg := "snapshots deleted"
+	if result.DryRun {
+		msg = "snapshot deletion dry run"
+	}
+
+	h.logger.Warn(msg,
+		"audit", true,
+		"client_ip", ClientIP(r),
+		"user_agent", r.UserAgent(),
+		"symbol", result.Symbol,
+		"from", result.From,
+		"to", result.To,
+		"dry_run", result.DryRun,
+		"deleted", result.Deleted,
+		"kept_compressed", result.KeptCompressed,
+	)
+}
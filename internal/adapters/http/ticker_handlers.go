@@ -0,0 +1,49 @@
+package http
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+)
+
+// WithTicker enables the /ticker24h endpoint backed by the given ticker service
+func WithTicker(ticker ports.TickerService) HandlerOption {
+	return func(h *Handler) {
+		h.ticker = ticker
+	}
+}
+
+// Ticker24hResponse represents a symbol's latest 24h statistics
+type Ticker24hResponse struct {
+	Symbol             string `json:"symbol"`
+	High               string `json:"high"`
+	Low                string `json:"low"`
+	Volume             string `json:"volume"`
+	PriceChangePercent string `json:"price_change_percent"`
+	Timestamp          string `json:"ts"`
+}
+
+// GetTicker24h returns the latest captured 24h stats for a symbol
+func (h *Handler) GetTicker24h(w http.ResponseWriter, r *http.Request) {
+	symbol := r.URL.Query().Get("symbol")
+	if symbol == "" {
+		respondError(w, http.StatusBadRequest, "symbol parameter is required")
+		return
+	}
+
+	stats, err := h.ticker.GetTicker24h(r.Context(), symbol)
+	if err != nil {
+		handleDomainError(w, err)
+		return
+	}
+
+	respond(w, r, http.StatusOK, Ticker24hResponse{
+		Symbol:             stats.Symbol,
+		High:               stats.High.String(),
+		Low:                stats.Low.String(),
+		Volume:             stats.Volume.String(),
+		PriceChangePercent: stats.PriceChangePercent.String(),
+		Timestamp:          stats.Timestamp.Format(time.RFC3339),
+	})
+}
@@ -0,0 +1,33 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// parseTimezone reads the optional ?tz= IANA time zone, e.g. Europe/Kyiv.
+// "Local" is refused because it would leak the server's own zone.
+func parseTimezone(r *http.Request) (*time.Location, error) {
+	name := r.URL.Query().Get("tz")
+	if name == "" {
+		return nil, nil
+	}
+	if name == "Local" {
+		return nil, fmt.Errorf("unknown time zone %q", name)
+	}
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown time zone %q", name)
+	}
+	return loc, nil
+}
+
+// localTimestamp formats t in loc, or returns "" without a requested zone
+func localTimestamp(t time.Time, loc *time.Location) string {
+	if loc == nil {
+		return ""
+	}
+	return t.In(loc).Format(time.RFC3339)
+}
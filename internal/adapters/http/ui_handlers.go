@@ -0,0 +1,25 @@
+package http
+
+import (
+	_ "embed"
+	"net/http"
+)
+
+//go:embed ui/index.html
+var dashboard []byte
+
+// WithUI enables the /ui dashboard
+func WithUI() HandlerOption {
+	return func(h *Handler) {
+		h.ui = true
+	}
+}
+
+// Dashboard serves the single-page dashboard. It is static and reads
+// everything it shows from the public API
+func (h *Handler) Dashboard(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(dashboard)
+}
@@ -0,0 +1,132 @@
+package http
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+)
+
+// usageKeyFingerprintLen is how many hex digits of an API key's SHA-256
+// identify it in usage statistics
+const usageKeyFingerprintLen = 16
+
+// WithUsage counts requests per API key in keys and endpoint and enables
+// the /admin/usage reports backed by the given service
+func WithUsage(usage ports.UsageService, keys []string) HandlerOption {
+	return func(h *Handler) {
+		h.usage = usage
+		h.usageKeys = keys
+	}
+}
+
+// UsageMiddleware counts each request under the API key it was made with
+// and the route pattern it was served by. Requests with a key not listed in
+// keys count as anonymous. Health probes are not counted.
+func UsageMiddleware(recorder ports.UsageRecorder, keys []string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r)
+
+			if r.URL.Path == "/health" || r.URL.Path == "/ready" || r.Method == http.MethodOptions {
+				return
+			}
+
+			// The router sets the pattern on the request it was handed
+			endpoint := r.Pattern
+			if endpoint == "" {
+				endpoint = r.Method + " " + unroutedRoute
+			}
+			recorder.RecordUsage(usageKey(r, keys), endpoint)
+		})
+	}
+}
+
+// usageKey identifies the API key of a request by a fingerprint of its
+// hash, so the raw key is never stored. Unknown keys are not fingerprinted,
+// so made-up keys cannot grow the counts without bound.
+func usageKey(r *http.Request, keys []string) string {
+	apiKey := r.Header.Get(APIKeyHeader)
+	if !validAPIKey(apiKey, keys) {
+		return domain.AnonymousUsageKey
+	}
+	sum := sha256.Sum256([]byte(apiKey))
+	return "key:" + hex.EncodeToString(sum[:])[:usageKeyFingerprintLen]
+}
+
+// UsageResponse lists the daily request counts of a range of days
+type UsageResponse struct {
+	From  string              `json:"from"`
+	To    string              `json:"to"`
+	Usage []domain.UsageCount `json:"usage"`
+}
+
+// GetUsage returns the requests per API key and endpoint for each day from
+// ?from= through ?to=, both YYYY-MM-DD in UTC. to defaults to today.
+func (h *Handler) GetUsage(w http.ResponseWriter, r *http.Request) {
+	from, ok := parseUsageDay(r, "from")
+	if !ok || from.IsZero() {
+		respondErrorWithCode(w, http.StatusBadRequest, "from must be a date such as 2024-01-15", "INVALID_TIME_RANGE")
+		return
+	}
+	to, ok := parseUsageDay(r, "to")
+	if !ok {
+		respondErrorWithCode(w, http.StatusBadRequest, "to must be a date such as 2024-01-15", "INVALID_TIME_RANGE")
+		return
+	}
+	if to.IsZero() {
+		to = domain.UsageDay(time.Now())
+	}
+
+	usage, err := h.usage.GetUsage(r.Context(), from, to)
+	if err != nil {
+		handleDomainError(w, err)
+		return
+	}
+
+	respond(w, r, http.StatusOK, UsageResponse{
+		From:  from.Format(time.DateOnly),
+		To:    to.Format(time.DateOnly),
+		Usage: usage,
+	})
+}
+
+// GetUsageSummary returns the requests of each API key in the calendar
+// month ?month=, YYYY-MM in UTC, the current month by default
+func (h *Handler) GetUsageSummary(w http.ResponseWriter, r *http.Request) {
+	month := domain.UsageMonth(time.Now())
+	if param := strings.TrimSpace(r.URL.Query().Get("month")); param != "" {
+		parsed, err := time.Parse("2006-01", param)
+		if err != nil {
+			respondErrorWithCode(w, http.StatusBadRequest, "month must be a month such as 2024-01", "INVALID_TIME_RANGE")
+			return
+		}
+		month = parsed
+	}
+
+	summary, err := h.usage.Summarize(r.Context(), month, month.AddDate(0, 1, 0))
+	if err != nil {
+		handleDomainError(w, err)
+		return
+	}
+
+	respond(w, r, http.StatusOK, summary)
+}
+
+// parseUsageDay reads an optional YYYY-MM-DD query parameter, reporting
+// false when it is malformed
+func parseUsageDay(r *http.Request, name string) (time.Time, bool) {
+	v := strings.TrimSpace(r.URL.Query().Get(name))
+	if v == "" {
+		return time.Time{}, true
+	}
+	day, err := time.Parse(time.DateOnly, v)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return day, true
+}
@@ -0,0 +1,626 @@
+package kraken
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+	"github.com/prxgr4mmer/price-snapshot-service/pkg/retry"
+)
+
+const (
+	// DefaultBaseURL is the public Kraken spot REST API
+	DefaultBaseURL = "https://api.kraken.com"
+
+	tickerPath     = "/0/public/Ticker"
+	ohlcPath       = "/0/public/OHLC"
+	assetPairsPath = "/0/public/AssetPairs"
+	timePath       = "/0/public/Time"
+
+	// maxCandles is the most candles Kraken returns per interval; older
+	// ones cannot be fetched
+	maxCandles = 720
+)
+
+// assetAliases maps the asset codes symbols use to Kraken's own codes
+var assetAliases = map[string]string{
+	"BTC":  "XBT",
+	"DOGE": "XDG",
+}
+
+// intervals maps supported candle intervals to Kraken intervals in minutes
+var intervals = map[string]time.Duration{
+	"1m":  time.Minute,
+	"5m":  5 * time.Minute,
+	"15m": 15 * time.Minute,
+	"1h":  time.Hour,
+	"4h":  4 * time.Hour,
+	"1d":  24 * time.Hour,
+}
+
+// Client implements the ExchangeClient interface for the Kraken public spot
+// API. Symbols are named as on Binance, e.g. BTCUSD, and mapped to Kraken
+// pair names such as XBTUSD.
+type Client struct {
+	httpClient *http.Client
+	baseURL    string
+	retryConf  retry.Config
+	errors     ports.ExchangeErrorRecorder
+	retries    ports.ExchangeRetryRecorder
+	logger     *slog.Logger
+}
+
+// ClientOption configures the client
+type ClientOption func(*Client)
+
+// WithBaseURL sets the API base URL
+func WithBaseURL(url string) ClientOption {
+	return func(c *Client) {
+		if url != "" {
+			c.baseURL = strings.TrimRight(url, "/")
+		}
+	}
+}
+
+// WithTimeout sets the HTTP client timeout
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(c *Client) {
+		c.httpClient.Timeout = timeout
+	}
+}
+
+// WithTransport sets the HTTP transport used for requests. A nil transport
+// keeps the default.
+func WithTransport(transport http.RoundTripper) ClientOption {
+	return func(c *Client) {
+		if transport != nil {
+			c.httpClient.Transport = transport
+		}
+	}
+}
+
+// WithRetry configures retry behavior
+func WithRetry(maxRetries int, backoff time.Duration) ClientOption {
+	return func(c *Client) {
+		c.retryConf.MaxRetries = maxRetries
+		c.retryConf.InitialBackoff = backoff
+	}
+}
+
+// WithRetryJitter sets how retry backoffs are randomized
+func WithRetryJitter(mode retry.JitterMode) ClientOption {
+	return func(c *Client) {
+		c.retryConf.JitterMode = mode
+	}
+}
+
+// WithErrorRecorder reports every failed request attempt, classified by kind
+func WithErrorRecorder(recorder ports.ExchangeErrorRecorder) ClientOption {
+	return func(c *Client) {
+		c.errors = recorder
+	}
+}
+
+// WithRetryRecorder reports every retry, classified by the kind of failure
+// retried, and the attempts made by every call
+func WithRetryRecorder(recorder ports.ExchangeRetryRecorder) ClientOption {
+	return func(c *Client) {
+		c.retries = recorder
+	}
+}
+
+// WithLogger sets the logger
+func WithLogger(logger *slog.Logger) ClientOption {
+	return func(c *Client) {
+		c.logger = logger.With("component", "kraken_client")
+	}
+}
+
+// NewClient creates a new Kraken client
+func NewClient(opts ...ClientOption) *Client {
+	c := &Client{
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+		baseURL:   DefaultBaseURL,
+		retryConf: retry.DefaultConfig(),
+		logger:    slog.Default().With("component", "kraken_client"),
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// PairName returns the Kraken pair name of a symbol, e.g. XBTUSD for BTCUSD
+func PairName(symbol string) string {
+	for asset, alias := range assetAliases {
+		if rest, ok := strings.CutPrefix(symbol, asset); ok {
+			symbol = alias + rest
+		}
+		if rest, ok := strings.CutSuffix(symbol, asset); ok {
+			symbol = rest + alias
+		}
+	}
+	return symbol
+}
+
+// SymbolName returns the symbol of a Kraken pair given by its websocket
+// name, e.g. BTCUSD for XBT/USD
+func SymbolName(wsname string) string {
+	base, quote, _ := strings.Cut(wsname, "/")
+	return symbolAsset(base) + symbolAsset(quote)
+}
+
+// symbolAsset returns the asset code symbols use for a Kraken asset code
+func symbolAsset(code string) string {
+	for asset, alias := range assetAliases {
+		if code == alias {
+			return asset
+		}
+	}
+	return code
+}
+
+// legacyPairName returns the pair name of a result keyed by one of Kraken's
+// legacy pair names, e.g. XBTUSD for XXBTZUSD, and the key itself otherwise
+func legacyPairName(key string) string {
+	if len(key) == 8 && strings.ContainsRune("XZ", rune(key[0])) && strings.ContainsRune("XZ", rune(key[4])) {
+		return key[1:4] + key[5:]
+	}
+	return key
+}
+
+// retryConfig returns the retry behavior for a call of an operation made
+// with ctx, which makes a single attempt when the caller asked for one
+func (c *Client) retryConfig(ctx context.Context, operation string) retry.Config {
+	conf := c.retryConf
+	if domain.SingleExchangeAttempt(ctx) {
+		conf.MaxRetries = 0
+	}
+
+	if c.retries != nil {
+		conf.OnRetry = func(_ int, err error) {
+			if kind := classifyError(err); kind != "" {
+				c.retries.RecordExchangeRetry(operation, kind)
+			}
+		}
+		conf.OnDone = func(attempts int, err error) {
+			if !errors.Is(err, context.Canceled) {
+				c.retries.RecordExchangeAttempts(operation, attempts)
+			}
+		}
+	}
+	return conf
+}
+
+// observed wraps a request attempt so that its failure, if any, is
+// classified and reported to the error recorder under the operation name
+func (c *Client) observed(operation string, fn func(ctx context.Context) error) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		err := fn(ctx)
+		if err != nil && c.errors != nil {
+			if kind := classifyError(err); kind != "" {
+				c.errors.RecordExchangeError(operation, kind)
+			}
+		}
+		return err
+	}
+}
+
+// response is the envelope of every Kraken API response
+type response struct {
+	Error  []string        `json:"error"`
+	Result json.RawMessage `json:"result"`
+}
+
+// get sends a GET request and decodes the result of a successful response
+// into v. Kraken reports most failures as errors in the envelope of a 200
+// response; rate limits and unavailability are returned as retryable.
+func (c *Client) get(ctx context.Context, path string, query url.Values, v interface{}) error {
+	u := c.baseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		c.logger.Debug("request failed, will retry", "path", path, "error", err)
+		return retry.NewRetryableError(err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		c.logger.Warn("rate limited by exchange", "path", path)
+		return retry.NewRetryableError(domain.ErrRateLimited)
+	case resp.StatusCode >= 500:
+		c.logger.Warn("exchange server error", "path", path, "status", resp.StatusCode)
+		return retry.NewRetryableError(&statusError{status: resp.StatusCode})
+	case resp.StatusCode != http.StatusOK:
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		c.logger.Error("unexpected response", "path", path, "status", resp.StatusCode, "body", string(body))
+		return domain.ErrInvalidResponse
+	}
+
+	var envelope response
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return fmt.Errorf("%w: %w", errDecode, err)
+	}
+	if len(envelope.Error) > 0 {
+		err := apiError(envelope.Error)
+		if retry.IsRetryable(err) {
+			c.logger.Warn("exchange error", "path", path, "error", err)
+		}
+		return err
+	}
+
+	if err := json.Unmarshal(envelope.Result, v); err != nil {
+		return fmt.Errorf("%w: %w", errDecode, err)
+	}
+	return nil
+}
+
+// tickerResponse is the subset of a Kraken ticker we use. Arrays hold the
+// value of today and of the last 24 hours; c is the last trade's price and
+// volume, and o today's opening price.
+type tickerResponse struct {
+	Close  []string `json:"c"`
+	Volume []string `json:"v"`
+	Low    []string `json:"l"`
+	High   []string `json:"h"`
+	Open   string   `json:"o"`
+}
+
+// tickers fetches the tickers of symbols, keyed by symbol. Tickers Kraken
+// returns under a key that cannot be matched to a symbol are left out.
+func (c *Client) tickers(ctx context.Context, operation string, symbols []string) (map[string]tickerResponse, error) {
+	bySymbol := make(map[string]string, len(symbols))
+	pairs := make([]string, len(symbols))
+	for i, symbol := range symbols {
+		pairs[i] = PairName(symbol)
+		bySymbol[pairs[i]] = symbol
+	}
+
+	var result map[string]tickerResponse
+	err := retry.Do(ctx, c.retryConfig(ctx, operation), c.observed(operation, func(ctx context.Context) error {
+		var tickers map[string]tickerResponse
+		if err := c.get(ctx, tickerPath, url.Values{"pair": {strings.Join(pairs, ",")}}, &tickers); err != nil {
+			return err
+		}
+
+		result = make(map[string]tickerResponse, len(tickers))
+		for key, ticker := range tickers {
+			symbol, ok := bySymbol[key]
+			if !ok {
+				symbol, ok = bySymbol[legacyPairName(key)]
+			}
+			if !ok && len(symbols) == 1 {
+				symbol, ok = symbols[0], true
+			}
+			if !ok {
+				c.logger.Warn("unmatched ticker", "pair", key)
+				continue
+			}
+			result[symbol] = ticker
+		}
+		return nil
+	}))
+	return result, err
+}
+
+// GetPrice fetches the current price for a single symbol
+func (c *Client) GetPrice(ctx context.Context, symbol string) (*domain.Price, error) {
+	prices, err := c.prices(ctx, "price", []string{symbol})
+	if err != nil {
+		return nil, err
+	}
+	if len(prices) == 0 {
+		return nil, domain.ErrInvalidSymbol
+	}
+	return prices[0], nil
+}
+
+// GetPrices fetches current prices for multiple symbols in one request
+func (c *Client) GetPrices(ctx context.Context, symbols []string) ([]*domain.Price, error) {
+	if len(symbols) == 0 {
+		return nil, nil
+	}
+	return c.prices(ctx, "prices", symbols)
+}
+
+func (c *Client) prices(ctx context.Context, operation string, symbols []string) ([]*domain.Price, error) {
+	tickers, err := c.tickers(ctx, operation, symbols)
+	if err != nil {
+		return nil, err
+	}
+	fetchedAt := time.Now().UTC()
+
+	result := make([]*domain.Price, 0, len(tickers))
+	for _, symbol := range symbols {
+		ticker, ok := tickers[symbol]
+		if !ok {
+			continue
+		}
+		if len(ticker.Close) == 0 {
+			c.logger.Warn("ticker without price", "symbol", symbol)
+			continue
+		}
+		price, err := decimal.NewFromString(ticker.Close[0])
+		if err != nil {
+			c.logger.Warn("invalid price format", "symbol", symbol, "price", ticker.Close[0])
+			continue
+		}
+		result = append(result, &domain.Price{
+			Symbol:    symbol,
+			Price:     price,
+			FetchedAt: fetchedAt,
+		})
+	}
+	return result, nil
+}
+
+// ValidateSymbol checks if a symbol exists on Kraken
+func (c *Client) ValidateSymbol(ctx context.Context, symbol string) (bool, error) {
+	_, err := c.GetPrice(ctx, symbol)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidSymbol) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// GetTickerStats fetches 24h statistics for multiple symbols. Kraken has no
+// rolling 24h open, so the price change is relative to today's UTC open.
+func (c *Client) GetTickerStats(ctx context.Context, symbols []string) ([]*domain.TickerStats, error) {
+	if len(symbols) == 0 {
+		return nil, nil
+	}
+
+	tickers, err := c.tickers(ctx, "ticker24h", symbols)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*domain.TickerStats, 0, len(tickers))
+	for _, symbol := range symbols {
+		ticker, ok := tickers[symbol]
+		if !ok {
+			continue
+		}
+		stats, err := parseTicker(symbol, ticker)
+		if err != nil {
+			c.logger.Warn("invalid 24h ticker", "symbol", symbol, "error", err)
+			continue
+		}
+		result = append(result, stats)
+	}
+	return result, nil
+}
+
+func parseTicker(symbol string, t tickerResponse) (*domain.TickerStats, error) {
+	if len(t.Close) < 1 || len(t.High) < 2 || len(t.Low) < 2 || len(t.Volume) < 2 {
+		return nil, domain.ErrInvalidResponse
+	}
+
+	values := make([]decimal.Decimal, 5)
+	for i, str := range []string{t.High[1], t.Low[1], t.Volume[1], t.Close[0], t.Open} {
+		v, err := decimal.NewFromString(str)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = v
+	}
+
+	open := values[4]
+	change := decimal.Zero
+	if open.IsPositive() {
+		change = values[3].Sub(open).Div(open).Mul(decimal.NewFromInt(100)).Round(4)
+	}
+
+	return &domain.TickerStats{
+		Symbol:             symbol,
+		High:               values[0],
+		Low:                values[1],
+		Volume:             values[2],
+		PriceChangePercent: change,
+	}, nil
+}
+
+// GetCandles fetches historical candles for a symbol between from and to.
+// Kraken only serves the latest 720 candles of each interval, so older
+// ranges return what is left of them.
+func (c *Client) GetCandles(ctx context.Context, symbol, interval string, from, to time.Time, limit int) ([]*domain.Candle, error) {
+	step, ok := intervals[interval]
+	if !ok {
+		return nil, fmt.Errorf("unsupported interval %q", interval)
+	}
+	if limit <= 0 || limit > maxCandles {
+		limit = maxCandles
+	}
+
+	var result []*domain.Candle
+
+	err := retry.Do(ctx, c.retryConfig(ctx, "klines"), c.observed("klines", func(ctx context.Context) error {
+		q := url.Values{}
+		q.Set("pair", PairName(symbol))
+		q.Set("interval", strconv.Itoa(int(step.Minutes())))
+		q.Set("since", strconv.FormatInt(from.Unix()-1, 10))
+
+		// The result holds the candles under the pair's key next to "last"
+		var ohlc map[string]json.RawMessage
+		if err := c.get(ctx, ohlcPath, q, &ohlc); err != nil {
+			return err
+		}
+
+		result = nil
+		for key, raw := range ohlc {
+			if key == "last" {
+				continue
+			}
+
+			// Each candle is [time, open, high, low, close, vwap, volume, count]
+			var rows [][]json.RawMessage
+			if err := json.Unmarshal(raw, &rows); err != nil {
+				return fmt.Errorf("%w: %w", errDecode, err)
+			}
+			for _, row := range rows {
+				candle, err := parseCandle(symbol, step, row)
+				if err != nil {
+					c.logger.Warn("invalid candle", "symbol", symbol, "error", err)
+					continue
+				}
+				if candle.OpenTime.Before(from) || candle.OpenTime.After(to) {
+					continue
+				}
+				result = append(result, candle)
+			}
+		}
+
+		sort.Slice(result, func(i, j int) bool { return result[i].OpenTime.Before(result[j].OpenTime) })
+		if len(result) > limit {
+			result = result[:limit]
+		}
+		return nil
+	}))
+
+	return result, err
+}
+
+func parseCandle(symbol string, step time.Duration, row []json.RawMessage) (*domain.Candle, error) {
+	if len(row) < 5 {
+		return nil, domain.ErrInvalidResponse
+	}
+
+	var openTime int64
+	if err := json.Unmarshal(row[0], &openTime); err != nil {
+		return nil, err
+	}
+
+	prices := make([]decimal.Decimal, 4)
+	for i := range prices {
+		var str string
+		if err := json.Unmarshal(row[i+1], &str); err != nil {
+			return nil, err
+		}
+		price, err := decimal.NewFromString(str)
+		if err != nil {
+			return nil, err
+		}
+		prices[i] = price
+	}
+
+	open := time.Unix(openTime, 0).UTC()
+	return &domain.Candle{
+		Symbol:    symbol,
+		OpenTime:  open,
+		CloseTime: open.Add(step - time.Millisecond),
+		Open:      prices[0],
+		High:      prices[1],
+		Low:       prices[2],
+		Close:     prices[3],
+	}, nil
+}
+
+// assetPairResponse is the subset of a Kraken asset pair we use
+type assetPairResponse struct {
+	WSName   string `json:"wsname"`
+	TickSize string `json:"tick_size"`
+	Decimals int32  `json:"pair_decimals"`
+	Status   string `json:"status"`
+}
+
+// assetPairs lists every pair Kraken offers
+func (c *Client) assetPairs(ctx context.Context) (map[string]assetPairResponse, error) {
+	var pairs map[string]assetPairResponse
+	err := retry.Do(ctx, c.retryConfig(ctx, "exchange_info"), c.observed("exchange_info", func(ctx context.Context) error {
+		pairs = nil
+		return c.get(ctx, assetPairsPath, nil, &pairs)
+	}))
+	return pairs, err
+}
+
+// ListTradingSymbols returns the symbols of all online pairs
+func (c *Client) ListTradingSymbols(ctx context.Context) ([]string, error) {
+	pairs, err := c.assetPairs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]string, 0, len(pairs))
+	for _, p := range pairs {
+		if p.Status == "online" && p.WSName != "" {
+			result = append(result, SymbolName(p.WSName))
+		}
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+// GetSymbolPrecisions returns the price tick size of the given symbols, or
+// of every listed pair when none are given. Pairs without a tick size fall
+// back to their price decimals.
+func (c *Client) GetSymbolPrecisions(ctx context.Context, symbols []string) ([]*domain.SymbolPrecision, error) {
+	pairs, err := c.assetPairs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	wanted := make(map[string]bool, len(symbols))
+	for _, s := range symbols {
+		wanted[s] = true
+	}
+
+	precisions := make([]*domain.SymbolPrecision, 0, len(pairs))
+	for _, p := range pairs {
+		symbol := SymbolName(p.WSName)
+		if p.WSName == "" || (len(wanted) > 0 && !wanted[symbol]) {
+			continue
+		}
+		tickSize, err := decimal.NewFromString(p.TickSize)
+		if err != nil {
+			tickSize = decimal.New(1, -p.Decimals)
+		}
+		if precision, err := domain.NewSymbolPrecision(symbol, tickSize); err == nil {
+			precisions = append(precisions, precision)
+		}
+	}
+	return precisions, nil
+}
+
+// Ping checks if the Kraken API is reachable
+func (c *Client) Ping(ctx context.Context) error {
+	return retry.Do(ctx, c.retryConfig(ctx, "ping"), c.observed("ping", func(ctx context.Context) error {
+		var serverTime json.RawMessage
+		return c.get(ctx, timePath, nil, &serverTime)
+	}))
+}
+
+// Ensure Client implements ExchangeClient
+var _ ports.ExchangeClient = (*Client)(nil)
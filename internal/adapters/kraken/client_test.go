@@ -0,0 +1,201 @@
+package kraken_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/adapters/kraken"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports/exchangetest"
+)
+
+func TestClient_Conformance(t *testing.T) {
+	exchangetest.Run(t, func(t *testing.T, s *exchangetest.Scenario) ports.ExchangeClient {
+		server := httptest.NewServer(fakeKraken(s))
+		t.Cleanup(server.Close)
+
+		return kraken.NewClient(
+			kraken.WithBaseURL(server.URL),
+			kraken.WithRetry(exchangetest.MaxRetries, time.Millisecond),
+		)
+	})
+}
+
+// fakeKraken serves the ticker and time endpoints of the Kraken API as the
+// scenario directs. Like Kraken, it reports rate limits and unknown pairs
+// in the error list of a 200 response.
+func fakeKraken(s *exchangetest.Scenario) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch s.Next() {
+		case exchangetest.FailRateLimited:
+			w.Write([]byte(`{"error":["EAPI:Rate limit exceeded"]}`))
+			return
+		case exchangetest.FailServerError:
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		case exchangetest.FailMalformed:
+			w.Write([]byte(`{"error":[],"result":`))
+			return
+		case exchangetest.FailHang:
+			s.Hang(r.Context())
+			return
+		}
+
+		switch r.URL.Path {
+		case "/0/public/Time":
+			w.Write([]byte(`{"error":[],"result":{"unixtime":1705312800,"rfc1123":"Mon, 15 Jan 24 10:00:00 +0000"}}`))
+
+		case "/0/public/Ticker":
+			tickers := make(map[string]interface{})
+			for _, pair := range strings.Split(r.URL.Query().Get("pair"), ",") {
+				price, ok := s.Price(strings.Replace(pair, "XBT", "BTC", 1))
+				if !ok {
+					w.Write([]byte(`{"error":["EQuery:Unknown asset pair"]}`))
+					return
+				}
+				tickers[pair] = map[string]interface{}{"c": []string{price, "1.0"}}
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"error": []string{}, "result": tickers})
+
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+}
+
+func TestPairName(t *testing.T) {
+	for symbol, want := range map[string]string{
+		"BTCUSD":  "XBTUSD",
+		"ETHBTC":  "ETHXBT",
+		"DOGEUSD": "XDGUSD",
+		"DOGEBTC": "XDGXBT",
+		"SOLEUR":  "SOLEUR",
+	} {
+		assert.Equal(t, want, kraken.PairName(symbol), symbol)
+	}
+
+	assert.Equal(t, "BTCUSD", kraken.SymbolName("XBT/USD"))
+	assert.Equal(t, "DOGEBTC", kraken.SymbolName("XDG/XBT"))
+	assert.Equal(t, "SOLEUR", kraken.SymbolName("SOL/EUR"))
+}
+
+func TestClient_GetPrices_LegacyPairNames(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "XBTUSD,ETHUSD,SOLUSD", r.URL.Query().Get("pair"))
+		w.Write([]byte(`{"error":[],"result":{
+			"XXBTZUSD":{"c":["43123.40000","0.01"]},
+			"XETHZUSD":{"c":["2345.67","0.5"]},
+			"SOLUSD":{"c":["98.12","3"]}
+		}}`))
+	}))
+	defer server.Close()
+
+	client := kraken.NewClient(kraken.WithBaseURL(server.URL))
+	prices, err := client.GetPrices(context.Background(), []string{"BTCUSD", "ETHUSD", "SOLUSD"})
+	require.NoError(t, err)
+
+	require.Len(t, prices, 3)
+	assert.Equal(t, "BTCUSD", prices[0].Symbol)
+	assert.Equal(t, "43123.4", prices[0].Price.String())
+	assert.Equal(t, "ETHUSD", prices[1].Symbol)
+	assert.Equal(t, "SOLUSD", prices[2].Symbol)
+}
+
+func TestClient_GetTickerStats(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"error":[],"result":{"XETHZUSD":{
+			"c":["2100.00","1"],"o":"2000.00",
+			"h":["2150.00","2200.00"],"l":["1990.00","1950.00"],"v":["500.5","1234.5"]
+		}}}`))
+	}))
+	defer server.Close()
+
+	client := kraken.NewClient(kraken.WithBaseURL(server.URL))
+	stats, err := client.GetTickerStats(context.Background(), []string{"ETHUSD"})
+	require.NoError(t, err)
+
+	require.Len(t, stats, 1)
+	assert.Equal(t, "ETHUSD", stats[0].Symbol)
+	assert.Equal(t, "2200", stats[0].High.String(), "24h values, not today's")
+	assert.Equal(t, "1950", stats[0].Low.String())
+	assert.Equal(t, "1234.5", stats[0].Volume.String())
+	assert.Equal(t, "5", stats[0].PriceChangePercent.String())
+}
+
+func TestClient_GetCandles(t *testing.T) {
+	var query map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/0/public/OHLC", r.URL.Path)
+		query = map[string]string{
+			"pair":     r.URL.Query().Get("pair"),
+			"interval": r.URL.Query().Get("interval"),
+			"since":    r.URL.Query().Get("since"),
+		}
+		w.Write([]byte(`{"error":[],"result":{"XXBTZUSD":[
+			[1705312800,"43000.0","43110.0","42900.0","43100.0","43050.0","8.25",120],
+			[1705312860,"43100.0","43200.0","42990.5","43150.25","43120.0","12.5",98],
+			[1705312920,"43150.25","43160.0","43140.0","43155.0","43150.0","1.5",12]
+		],"last":1705312860}}`))
+	}))
+	defer server.Close()
+
+	client := kraken.NewClient(kraken.WithBaseURL(server.URL))
+	from := time.Unix(1705312800, 0).UTC()
+
+	candles, err := client.GetCandles(context.Background(), "BTCUSD", "1m", from, from.Add(time.Minute), 0)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{"pair": "XBTUSD", "interval": "1", "since": "1705312799"}, query)
+	require.Len(t, candles, 2, "candles after to are left out")
+	assert.Equal(t, from, candles[0].OpenTime)
+	assert.Equal(t, "43000", candles[0].Open.String())
+	assert.Equal(t, "43100", candles[0].Close.String())
+	assert.Equal(t, "42990.5", candles[1].Low.String())
+	assert.Equal(t, from.Add(2*time.Minute-time.Millisecond), candles[1].CloseTime)
+}
+
+func TestClient_AssetPairs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/0/public/AssetPairs", r.URL.Path)
+		w.Write([]byte(`{"error":[],"result":{
+			"XXBTZUSD":{"altname":"XBTUSD","wsname":"XBT/USD","tick_size":"0.1","pair_decimals":1,"status":"online"},
+			"XETHZEUR":{"altname":"ETHEUR","wsname":"ETH/EUR","pair_decimals":2,"status":"online"},
+			"LUNAUSD":{"altname":"LUNAUSD","wsname":"LUNA/USD","tick_size":"0.0001","pair_decimals":4,"status":"delisted"}
+		}}`))
+	}))
+	defer server.Close()
+
+	client := kraken.NewClient(kraken.WithBaseURL(server.URL))
+
+	symbols, err := client.ListTradingSymbols(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"BTCUSD", "ETHEUR"}, symbols)
+
+	precisions, err := client.GetSymbolPrecisions(context.Background(), []string{"ETHEUR"})
+	require.NoError(t, err)
+	require.Len(t, precisions, 1)
+	assert.Equal(t, "ETHEUR", precisions[0].Symbol)
+	assert.Equal(t, "0.01", precisions[0].TickSize.String(), "falls back to the pair's price decimals")
+}
+
+func TestClient_UnknownPair(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"error":["EQuery:Unknown asset pair"]}`))
+	}))
+	defer server.Close()
+
+	client := kraken.NewClient(kraken.WithBaseURL(server.URL))
+	_, err := client.GetPrices(context.Background(), []string{"NOPEUSD"})
+	assert.ErrorIs(t, err, domain.ErrInvalidSymbol)
+}
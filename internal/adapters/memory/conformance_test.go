@@ -0,0 +1,18 @@
+package memory_test
+
+import (
+	"testing"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/adapters/memory"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports/repotest"
+)
+
+func TestRepositories_Conformance(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) repotest.Repositories {
+		store := memory.NewStore()
+		return repotest.Repositories{
+			Symbols:   memory.NewSymbolRepository(store),
+			Snapshots: memory.NewSnapshotRepository(store),
+		}
+	})
+}
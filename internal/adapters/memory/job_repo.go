@@ -0,0 +1,247 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+)
+
+// JobRepository implements the ports.JobRepository interface
+type JobRepository struct {
+	store *Store
+}
+
+// NewJobRepository creates a new in-memory job repository
+func NewJobRepository(store *Store) ports.JobRepository {
+	return &JobRepository{store: store}
+}
+
+// Create enqueues a new job
+func (r *JobRepository) Create(ctx context.Context, job *domain.Job) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.nextJobID++
+	job.ID = r.store.nextJobID
+
+	stored := copyJob(job)
+	stored.Progress = domain.JobProgress{}
+	stored.Attempts = 0
+	stored.Error = ""
+	stored.StartedAt, stored.FinishedAt = nil, nil
+	r.store.jobs[job.ID] = stored
+	return nil
+}
+
+// GetByID retrieves a job by its ID
+func (r *JobRepository) GetByID(ctx context.Context, id int64) (*domain.Job, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	job, ok := r.store.jobs[id]
+	if !ok {
+		return nil, domain.ErrJobNotFound
+	}
+	return copyJob(job), nil
+}
+
+// List returns the most recent jobs, optionally filtered by status
+func (r *JobRepository) List(ctx context.Context, status domain.JobStatus, limit int) ([]*domain.Job, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+	if limit > 1000 {
+		limit = 1000
+	}
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var jobs []*domain.Job
+	for _, job := range r.store.jobs {
+		if status == "" || job.Status == status {
+			jobs = append(jobs, copyJob(job))
+		}
+	}
+	slices.SortFunc(jobs, func(a, b *domain.Job) int {
+		if c := b.CreatedAt.Compare(a.CreatedAt); c != 0 {
+			return c
+		}
+		return cmp.Compare(b.ID, a.ID)
+	})
+	if len(jobs) > limit {
+		jobs = jobs[:limit]
+	}
+	return jobs, nil
+}
+
+// ClaimNext marks the next due pending job, or a running job not updated
+// for staleAfter, as running. Stale jobs that have used up their attempts
+// are failed instead. Returns nil when no job is due.
+func (r *JobRepository) ClaimNext(ctx context.Context, staleAfter time.Duration) (*domain.Job, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	claimedAt := now()
+	var next *domain.Job
+	for _, job := range r.store.jobs {
+		due := job.Status == domain.JobStatusPending && !job.ScheduledAt.After(claimedAt)
+		stale := job.Status == domain.JobStatusRunning && job.UpdatedAt.Before(claimedAt.Add(-staleAfter))
+		if stale && job.Attempts >= job.MaxAttempts {
+			finishedAt := claimedAt
+			job.Status = domain.JobStatusFailed
+			job.Error = domain.JobLeaseExpiredError
+			job.FinishedAt = &finishedAt
+			continue
+		}
+		if !due && !stale {
+			continue
+		}
+		if next == nil || job.ScheduledAt.Before(next.ScheduledAt) ||
+			(job.ScheduledAt.Equal(next.ScheduledAt) && job.ID < next.ID) {
+			next = job
+		}
+	}
+	if next == nil {
+		return nil, nil
+	}
+
+	next.Status = domain.JobStatusRunning
+	next.Attempts++
+	next.StartedAt = &claimedAt
+	next.UpdatedAt = claimedAt
+	next.Error = ""
+	return copyJob(next), nil
+}
+
+// Heartbeat renews the lease of a running job's attempt
+func (r *JobRepository) Heartbeat(ctx context.Context, id int64, attempt int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	job, err := r.leased(id, attempt)
+	if err != nil {
+		return err
+	}
+	job.UpdatedAt = now()
+	return nil
+}
+
+// UpdateProgress stores the progress of a running job's attempt
+func (r *JobRepository) UpdateProgress(ctx context.Context, id int64, attempt int, progress domain.JobProgress) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	job, err := r.leased(id, attempt)
+	if err != nil {
+		return err
+	}
+	job.Progress = progress
+	job.UpdatedAt = now()
+	return nil
+}
+
+// Finish stores a job's final status, error and schedule
+func (r *JobRepository) Finish(ctx context.Context, job *domain.Job) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	stored, err := r.leased(job.ID, job.Attempts)
+	if errors.Is(err, domain.ErrJobCancelled) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	stored.Status = job.Status
+	stored.Progress = job.Progress
+	stored.Error = job.Error
+	stored.ScheduledAt = job.ScheduledAt
+	stored.FinishedAt = copyTime(job.FinishedAt)
+	return nil
+}
+
+// Cancel marks a pending or running job as cancelled
+func (r *JobRepository) Cancel(ctx context.Context, id int64) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	job, ok := r.store.jobs[id]
+	if !ok {
+		return domain.ErrJobNotFound
+	}
+	if job.Status != domain.JobStatusPending && job.Status != domain.JobStatusRunning {
+		return domain.ErrJobFinished
+	}
+
+	finishedAt := now()
+	job.Status = domain.JobStatusCancelled
+	job.FinishedAt = &finishedAt
+	return nil
+}
+
+// Requeue makes a cancelled or failed job pending again, keeping its progress
+func (r *JobRepository) Requeue(ctx context.Context, id int64) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	job, ok := r.store.jobs[id]
+	if !ok {
+		return domain.ErrJobNotFound
+	}
+	if job.Status != domain.JobStatusCancelled && job.Status != domain.JobStatusFailed {
+		return domain.ErrJobNotResumable
+	}
+
+	job.Status = domain.JobStatusPending
+	job.Attempts = 0
+	job.Error = ""
+	job.ScheduledAt = now()
+	job.FinishedAt = nil
+	return nil
+}
+
+// leased returns a job that is running the given attempt, or why it is
+// not. The caller holds the store lock.
+func (r *JobRepository) leased(id int64, attempt int) (*domain.Job, error) {
+	job, ok := r.store.jobs[id]
+	switch {
+	case !ok:
+		return nil, domain.ErrJobNotFound
+	case job.Status == domain.JobStatusCancelled:
+		return nil, domain.ErrJobCancelled
+	case job.Status != domain.JobStatusRunning || job.Attempts != attempt:
+		return nil, domain.ErrJobLeaseLost
+	}
+	return job, nil
+}
+
+// copyJob returns a copy of a job that shares nothing with it
+func copyJob(job *domain.Job) *domain.Job {
+	c := *job
+	if job.Payload != nil {
+		c.Payload = append(json.RawMessage(nil), job.Payload...)
+	}
+	c.StartedAt = copyTime(job.StartedAt)
+	c.FinishedAt = copyTime(job.FinishedAt)
+	return &c
+}
+
+// copyTime returns a copy of an optional time
+func copyTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	c := *t
+	return &c
+}
+
+// Ensure JobRepository implements ports.JobRepository
+var _ ports.JobRepository = (*JobRepository)(nil)
@@ -0,0 +1,453 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+)
+
+// SnapshotRepository implements the ports.SnapshotRepository interface.
+// Snapshots are never compressed, so there are no chunks to merge into
+// history reads.
+type SnapshotRepository struct {
+	store *Store
+}
+
+// NewSnapshotRepository creates a new in-memory snapshot repository
+func NewSnapshotRepository(store *Store) ports.SnapshotRepository {
+	return &SnapshotRepository{store: store}
+}
+
+// Create stores a new price snapshot. A polled snapshot whose run already
+// stored its symbol is not stored again; the stored snapshot's ID is
+// returned.
+func (r *SnapshotRepository) Create(ctx context.Context, snapshot *domain.PriceSnapshot) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if err := r.store.checkSnapshot(snapshot); err != nil {
+		return fmt.Errorf("failed to create snapshot: %w", err)
+	}
+	r.store.storePolled(snapshot)
+	return nil
+}
+
+// CreateBatch stores multiple snapshots atomically. Like Create, it skips
+// snapshots their poll run already stored, so a retried write is a no-op.
+func (r *SnapshotRepository) CreateBatch(ctx context.Context, snapshots []*domain.PriceSnapshot) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, snapshot := range snapshots {
+		if err := r.store.checkSnapshot(snapshot); err != nil {
+			return fmt.Errorf("failed to create snapshot for %s: %w", snapshot.Symbol, err)
+		}
+	}
+	for _, snapshot := range snapshots {
+		r.store.storePolled(snapshot)
+	}
+	return nil
+}
+
+// CreateBatchPartial stores every snapshot it can, reporting those of
+// unknown symbols without discarding the others
+func (r *SnapshotRepository) CreateBatchPartial(ctx context.Context, snapshots []*domain.PriceSnapshot) (*domain.BatchWriteResult, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	result := &domain.BatchWriteResult{}
+	for _, snapshot := range snapshots {
+		if err := r.store.checkSnapshot(snapshot); err != nil {
+			snapshot.ID = 0
+			result.Failed = append(result.Failed, domain.BatchWriteFailure{
+				Symbol: snapshot.Symbol,
+				Error:  err.Error(),
+			})
+			continue
+		}
+		r.store.storePolled(snapshot)
+		result.Written++
+	}
+	return result, nil
+}
+
+// CreateBatchIngested stores pushed snapshots atomically. A snapshot whose
+// symbol, timestamp and source are already stored is skipped, which makes
+// retried pushes idempotent.
+func (r *SnapshotRepository) CreateBatchIngested(ctx context.Context, snapshots []*domain.PriceSnapshot) (int, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, snapshot := range snapshots {
+		if err := r.store.checkSnapshot(snapshot); err != nil {
+			return 0, fmt.Errorf("failed to ingest snapshot for %s: %w", snapshot.Symbol, err)
+		}
+	}
+
+	stored := 0
+	for _, snapshot := range snapshots {
+		if snapshot.Source != "" && r.store.ingested(snapshot) {
+			continue
+		}
+		ingested := *snapshot
+		ingested.FetchLatencyMs = nil
+		ingested.PollRunID = ""
+		r.store.insertSnapshot(&ingested)
+		stored++
+	}
+	return stored, nil
+}
+
+// GetLatestBySymbol returns the most recent snapshot for a symbol
+func (r *SnapshotRepository) GetLatestBySymbol(ctx context.Context, symbolName string) (*domain.PriceSnapshot, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	timeline := r.store.snapshots[symbolName]
+	if len(timeline) == 0 {
+		return nil, domain.ErrSnapshotNotFound
+	}
+	return copySnapshot(timeline[len(timeline)-1]), nil
+}
+
+// GetLatestBySymbols returns the most recent snapshot for multiple symbols,
+// ordered by symbol
+func (r *SnapshotRepository) GetLatestBySymbols(ctx context.Context, symbolNames []string) ([]*domain.PriceSnapshot, error) {
+	return r.nthNewest(symbolNames, 0), nil
+}
+
+// GetPreviousBySymbols returns the snapshot before the most recent one for
+// multiple symbols, ordered by symbol
+func (r *SnapshotRepository) GetPreviousBySymbols(ctx context.Context, symbolNames []string) ([]*domain.PriceSnapshot, error) {
+	return r.nthNewest(symbolNames, 1), nil
+}
+
+// nthNewest returns the snapshot n places before the newest of each named
+// symbol that has one, ordered by symbol
+func (r *SnapshotRepository) nthNewest(symbolNames []string, n int) []*domain.PriceSnapshot {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	wanted := make(map[string]bool, len(symbolNames))
+	for _, name := range symbolNames {
+		wanted[name] = true
+	}
+
+	var snapshots []*domain.PriceSnapshot
+	for _, name := range r.store.symbolNames() {
+		timeline := r.store.snapshots[name]
+		if wanted[name] && len(timeline) > n {
+			snapshots = append(snapshots, copySnapshot(timeline[len(timeline)-1-n]))
+		}
+	}
+	return snapshots
+}
+
+// GetHistory returns historical snapshots for a symbol, newest first,
+// continuing after before when it is set
+func (r *SnapshotRepository) GetHistory(ctx context.Context, symbolName string, before *domain.HistoryCursor, limit int) ([]*domain.PriceSnapshot, error) {
+	return r.newest(symbolName, func(*domain.PriceSnapshot) bool { return true }, before, limit), nil
+}
+
+// GetHistoryBetween returns snapshots within a time range, both ends
+// included, newest first, continuing after before when it is set
+func (r *SnapshotRepository) GetHistoryBetween(ctx context.Context, symbolName string, from, to time.Time, before *domain.HistoryCursor, limit int) ([]*domain.PriceSnapshot, error) {
+	return r.newest(symbolName, func(s *domain.PriceSnapshot) bool {
+		return !s.Timestamp.Before(from) && !s.Timestamp.After(to)
+	}, before, limit), nil
+}
+
+// newest returns up to limit of a symbol's snapshots matching keep, newest
+// first, continuing after before when it is set
+func (r *SnapshotRepository) newest(symbolName string, keep func(*domain.PriceSnapshot) bool, before *domain.HistoryCursor, limit int) []*domain.PriceSnapshot {
+	if limit <= 0 {
+		limit = 100
+	}
+	if limit > domain.MaxHistoryLimit {
+		limit = domain.MaxHistoryLimit
+	}
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	timeline := r.store.snapshots[symbolName]
+	var snapshots []*domain.PriceSnapshot
+	for i := len(timeline) - 1; i >= 0 && len(snapshots) < limit; i-- {
+		s := timeline[i]
+		if (before == nil || before.Follows(s)) && keep(s) {
+			snapshots = append(snapshots, copySnapshot(s))
+		}
+	}
+	return snapshots
+}
+
+// EachBetween calls fn with a symbol's snapshots in [from, to), oldest
+// first. The range is copied out first, so fn may use the repositories.
+func (r *SnapshotRepository) EachBetween(ctx context.Context, symbolName string, from, to time.Time, fn func(*domain.PriceSnapshot) error) error {
+	r.store.mu.RLock()
+	var snapshots []*domain.PriceSnapshot
+	for _, s := range r.store.snapshots[symbolName] {
+		if inRange(s, from, to) {
+			snapshots = append(snapshots, copySnapshot(s))
+		}
+	}
+	r.store.mu.RUnlock()
+
+	for _, s := range snapshots {
+		if err := fn(s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetPriceChanges returns the price movement of each active symbol between
+// its first snapshot since the given time and its latest snapshot
+func (r *SnapshotRepository) GetPriceChanges(ctx context.Context, since time.Time) ([]*domain.PriceChange, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var changes []*domain.PriceChange
+	for _, name := range r.store.symbolNames() {
+		symbol := r.store.symbolByName(name)
+		if symbol == nil || !symbol.Active {
+			continue
+		}
+
+		timeline := r.store.snapshots[name]
+		last := timeline[len(timeline)-1]
+		if last.Timestamp.Before(since) {
+			continue
+		}
+		first := last
+		for _, s := range timeline {
+			if !s.Timestamp.Before(since) {
+				first = s
+				break
+			}
+		}
+		changes = append(changes, domain.NewPriceChange(name, first.Price, last.Price, first.Timestamp, last.Timestamp))
+	}
+	return changes, nil
+}
+
+// Count returns total number of snapshots
+func (r *SnapshotRepository) Count(ctx context.Context) (int64, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var count int64
+	for _, timeline := range r.store.snapshots {
+		count += int64(len(timeline))
+	}
+	return count, nil
+}
+
+// CountBySymbol returns number of snapshots for a symbol
+func (r *SnapshotRepository) CountBySymbol(ctx context.Context, symbolName string) (int64, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return int64(len(r.store.snapshots[symbolName])), nil
+}
+
+// GetHistorySummary returns the number of snapshots stored for a symbol and
+// the timestamps of its oldest and newest
+func (r *SnapshotRepository) GetHistorySummary(ctx context.Context, symbolName string) (*domain.HistorySummary, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	timeline := r.store.snapshots[symbolName]
+	summary := &domain.HistorySummary{TotalCount: int64(len(timeline))}
+	if len(timeline) > 0 {
+		oldest, newest := timeline[0].Timestamp, timeline[len(timeline)-1].Timestamp
+		summary.Oldest, summary.Newest = &oldest, &newest
+	}
+	return summary, nil
+}
+
+// CountPerSymbol returns the number of snapshots of every symbol that has
+// any, ordered by symbol
+func (r *SnapshotRepository) CountPerSymbol(ctx context.Context) ([]domain.SymbolSnapshotCount, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var counts []domain.SymbolSnapshotCount
+	for _, name := range r.store.symbolNames() {
+		counts = append(counts, domain.SymbolSnapshotCount{Symbol: name, Count: int64(len(r.store.snapshots[name]))})
+	}
+	return counts, nil
+}
+
+// Prune removes snapshots older than the given time
+func (r *SnapshotRepository) Prune(ctx context.Context, olderThan time.Time) (int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var deleted int64
+	for _, name := range r.store.symbolNames() {
+		deleted += r.store.removeSnapshots(name, func(s *domain.PriceSnapshot) bool {
+			return s.Timestamp.Before(olderThan)
+		})
+	}
+	return deleted, nil
+}
+
+// CompressBySymbol compresses nothing: snapshots are kept in memory as
+// stored
+func (r *SnapshotRepository) CompressBySymbol(ctx context.Context, symbolName string, before time.Time, width time.Duration) (int, int64, error) {
+	return 0, 0, nil
+}
+
+// PruneBySymbol removes a single symbol's snapshots older than the given time
+func (r *SnapshotRepository) PruneBySymbol(ctx context.Context, symbolName string, olderThan time.Time) (int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	return r.store.removeSnapshots(symbolName, func(s *domain.PriceSnapshot) bool {
+		return s.Timestamp.Before(olderThan)
+	}), nil
+}
+
+// CountRange counts a symbol's snapshots in [from, to) as DeleteRange
+// would delete them
+func (r *SnapshotRepository) CountRange(ctx context.Context, symbolName string, from, to time.Time) (*domain.SnapshotRangeDeletion, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var count int64
+	for _, s := range r.store.snapshots[symbolName] {
+		if inRange(s, from, to) {
+			count++
+		}
+	}
+
+	return &domain.SnapshotRangeDeletion{
+		Symbol:  symbolName,
+		From:    from,
+		To:      to,
+		DryRun:  true,
+		Deleted: count,
+	}, nil
+}
+
+// DeleteRange removes a symbol's snapshots in [from, to). There are no
+// locks to hold briefly, so batchSize is ignored.
+func (r *SnapshotRepository) DeleteRange(ctx context.Context, symbolName string, from, to time.Time, batchSize int) (*domain.SnapshotRangeDeletion, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	deleted := r.store.removeSnapshots(symbolName, func(s *domain.PriceSnapshot) bool {
+		return inRange(s, from, to)
+	})
+
+	return &domain.SnapshotRangeDeletion{
+		Symbol:  symbolName,
+		From:    from,
+		To:      to,
+		Deleted: deleted,
+	}, nil
+}
+
+// GetPollRun returns the snapshots a poll stored, ordered by symbol
+func (r *SnapshotRepository) GetPollRun(ctx context.Context, runID string) ([]*domain.PriceSnapshot, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var snapshots []*domain.PriceSnapshot
+	for _, s := range r.store.runs[runID] {
+		snapshots = append(snapshots, copySnapshot(s))
+	}
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].Symbol < snapshots[j].Symbol
+	})
+	return snapshots, nil
+}
+
+// DeletePollRun removes the snapshots a poll stored
+func (r *SnapshotRepository) DeletePollRun(ctx context.Context, runID string) (int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var names []string
+	for _, s := range r.store.runs[runID] {
+		names = append(names, s.Symbol)
+	}
+
+	var deleted int64
+	for _, name := range names {
+		deleted += r.store.removeSnapshots(name, func(s *domain.PriceSnapshot) bool {
+			return s.PollRunID == runID
+		})
+	}
+	return deleted, nil
+}
+
+// GetStorageStats returns per-symbol snapshot counts. Nothing is on disk,
+// so TotalBytes is zero.
+func (r *SnapshotRepository) GetStorageStats(ctx context.Context, since time.Time) (*domain.StorageStats, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	stats := &domain.StorageStats{}
+	for _, name := range r.store.symbolNames() {
+		timeline := r.store.snapshots[name]
+		storage := domain.SymbolStorage{
+			Symbol: name,
+			Rows:   int64(len(timeline)),
+			Oldest: timeline[0].Timestamp,
+		}
+		for _, s := range timeline {
+			if !s.Timestamp.Before(since) {
+				storage.RecentRows++
+			}
+		}
+		stats.Symbols = append(stats.Symbols, storage)
+	}
+	return stats, nil
+}
+
+// checkSnapshot rejects a snapshot of a symbol that is not stored. The
+// caller holds the lock.
+func (s *Store) checkSnapshot(snapshot *domain.PriceSnapshot) error {
+	if _, ok := s.symbols[snapshot.SymbolID]; !ok {
+		return fmt.Errorf("%w: %d", errUnknownSymbol, snapshot.SymbolID)
+	}
+	return nil
+}
+
+// storePolled stores a snapshot unless its poll run already stored its
+// symbol, in which case the stored snapshot's ID is returned in it. The
+// caller holds the write lock.
+func (s *Store) storePolled(snapshot *domain.PriceSnapshot) {
+	if snapshot.PollRunID != "" {
+		if stored, ok := s.runs[snapshot.PollRunID][snapshot.SymbolID]; ok {
+			snapshot.ID = stored.ID
+			return
+		}
+	}
+	s.insertSnapshot(snapshot)
+}
+
+// ingested reports whether a snapshot of the same symbol, timestamp and
+// source is stored. The caller holds the lock.
+func (s *Store) ingested(snapshot *domain.PriceSnapshot) bool {
+	for _, stored := range s.snapshots[snapshot.Symbol] {
+		if stored.Source == snapshot.Source && stored.Timestamp.Equal(snapshot.Timestamp) {
+			return true
+		}
+	}
+	return false
+}
+
+// inRange reports whether a snapshot was taken in [from, to)
+func inRange(s *domain.PriceSnapshot, from, to time.Time) bool {
+	return !s.Timestamp.Before(from) && s.Timestamp.Before(to)
+}
+
+// Ensure SnapshotRepository implements ports.SnapshotRepository
+var _ ports.SnapshotRepository = (*SnapshotRepository)(nil)
@@ -0,0 +1,184 @@
+// Package memory implements the repositories in process memory. It backs
+// STORAGE_DRIVER=memory, where a restart loses every symbol, snapshot and
+// job on purpose, and tests that need working repositories without a
+// database.
+package memory
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+)
+
+// errUnknownSymbol is a snapshot of a symbol ID that is not stored, which
+// the postgres foreign key rejects too
+var errUnknownSymbol = errors.New("snapshot references an unknown symbol")
+
+// Store holds the data of one in-memory database. Its repositories share
+// it, so deleting a symbol removes its snapshots as the postgres cascade
+// does. It is safe for concurrent use.
+type Store struct {
+	mu sync.RWMutex
+
+	symbols      map[int64]*domain.Symbol
+	nextSymbolID int64
+
+	// snapshots holds each symbol's snapshots ordered by timestamp, then
+	// ID, and runs the snapshot each poll run stored per symbol ID
+	snapshots      map[string][]*domain.PriceSnapshot
+	runs           map[string]map[int64]*domain.PriceSnapshot
+	nextSnapshotID int64
+
+	events      []*domain.SymbolEvent
+	cursors     map[string]int64
+	nextEventID int64
+
+	jobs      map[int64]*domain.Job
+	nextJobID int64
+}
+
+// NewStore creates an empty in-memory store
+func NewStore() *Store {
+	return &Store{
+		symbols:   make(map[int64]*domain.Symbol),
+		snapshots: make(map[string][]*domain.PriceSnapshot),
+		runs:      make(map[string]map[int64]*domain.PriceSnapshot),
+		cursors:   make(map[string]int64),
+		jobs:      make(map[int64]*domain.Job),
+	}
+}
+
+// AnalyzeSnapshots reports the number of stored snapshots. There are no
+// planner statistics or dead rows to maintain in memory.
+func (s *Store) AnalyzeSnapshots(ctx context.Context) ([]domain.TableStats, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var rows int64
+	for _, snapshots := range s.snapshots {
+		rows += int64(len(snapshots))
+	}
+	return []domain.TableStats{{Table: "snapshots", LiveRows: rows}}, nil
+}
+
+// insertSnapshot stores a copy of snapshot in its symbol's timeline and
+// assigns its ID. The caller holds the write lock.
+func (s *Store) insertSnapshot(snapshot *domain.PriceSnapshot) {
+	s.nextSnapshotID++
+	snapshot.ID = s.nextSnapshotID
+
+	stored := copySnapshot(snapshot)
+	stored.Timestamp = stored.Timestamp.UTC()
+	if stored.TimestampSource == "" {
+		stored.TimestampSource = domain.TimestampSourcePoll
+	}
+
+	timeline := s.snapshots[stored.Symbol]
+	i := sort.Search(len(timeline), func(i int) bool {
+		return stored.Timestamp.Before(timeline[i].Timestamp)
+	})
+	timeline = append(timeline, nil)
+	copy(timeline[i+1:], timeline[i:])
+	timeline[i] = stored
+	s.snapshots[stored.Symbol] = timeline
+
+	if stored.PollRunID != "" {
+		run := s.runs[stored.PollRunID]
+		if run == nil {
+			run = make(map[int64]*domain.PriceSnapshot)
+			s.runs[stored.PollRunID] = run
+		}
+		run[stored.SymbolID] = stored
+	}
+}
+
+// removeSnapshots drops a symbol's snapshots matching drop and returns how
+// many were removed. The caller holds the write lock.
+func (s *Store) removeSnapshots(symbolName string, drop func(*domain.PriceSnapshot) bool) int64 {
+	timeline := s.snapshots[symbolName]
+	kept := timeline[:0]
+	var removed int64
+	for _, snap := range timeline {
+		if !drop(snap) {
+			kept = append(kept, snap)
+			continue
+		}
+		removed++
+		if snap.PollRunID != "" {
+			delete(s.runs[snap.PollRunID], snap.SymbolID)
+			if len(s.runs[snap.PollRunID]) == 0 {
+				delete(s.runs, snap.PollRunID)
+			}
+		}
+	}
+	clear(timeline[len(kept):])
+
+	if len(kept) == 0 {
+		delete(s.snapshots, symbolName)
+	} else {
+		s.snapshots[symbolName] = kept
+	}
+	return removed
+}
+
+// symbolNames returns the names of the symbols with snapshots, sorted. The
+// caller holds the lock.
+func (s *Store) symbolNames() []string {
+	names := make([]string, 0, len(s.snapshots))
+	for name := range s.snapshots {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// copySymbol returns a copy of a symbol that shares nothing with it
+func copySymbol(symbol *domain.Symbol) *domain.Symbol {
+	c := *symbol
+	c.Tags = append([]string{}, symbol.Tags...)
+	if symbol.Metadata != nil {
+		metadata := *symbol.Metadata
+		c.Metadata = &metadata
+	}
+	if symbol.DeletedAt != nil {
+		deletedAt := *symbol.DeletedAt
+		c.DeletedAt = &deletedAt
+	}
+	return &c
+}
+
+// copySnapshot returns a copy of a snapshot that shares nothing with it
+func copySnapshot(snapshot *domain.PriceSnapshot) *domain.PriceSnapshot {
+	c := *snapshot
+	if snapshot.FetchLatencyMs != nil {
+		latency := *snapshot.FetchLatencyMs
+		c.FetchLatencyMs = &latency
+	}
+	if snapshot.IngestLatencyMs != nil {
+		latency := *snapshot.IngestLatencyMs
+		c.IngestLatencyMs = &latency
+	}
+	return &c
+}
+
+// copySnapshots returns copies of snapshots
+func copySnapshots(snapshots []*domain.PriceSnapshot) []*domain.PriceSnapshot {
+	copied := make([]*domain.PriceSnapshot, len(snapshots))
+	for i, snap := range snapshots {
+		copied[i] = copySnapshot(snap)
+	}
+	return copied
+}
+
+// now returns the current time as stored timestamps are, in UTC
+func now() time.Time {
+	return time.Now().UTC()
+}
+
+// Ensure Store implements ports.SnapshotTableAnalyzer
+var _ ports.SnapshotTableAnalyzer = (*Store)(nil)
@@ -0,0 +1,110 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+)
+
+// SymbolEventRepository implements the ports.SymbolEventRepository interface
+type SymbolEventRepository struct {
+	store *Store
+}
+
+// NewSymbolEventRepository creates a new in-memory symbol event repository
+func NewSymbolEventRepository(store *Store) ports.SymbolEventRepository {
+	return &SymbolEventRepository{store: store}
+}
+
+// Create records a symbol change
+func (r *SymbolEventRepository) Create(ctx context.Context, event *domain.SymbolEvent) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.nextEventID++
+	event.ID = r.store.nextEventID
+
+	stored := *event
+	r.store.events = append(r.store.events, &stored)
+	return nil
+}
+
+// ListBySymbol returns a symbol's most recent events, newest first
+func (r *SymbolEventRepository) ListBySymbol(ctx context.Context, symbolName string, limit int) ([]*domain.SymbolEvent, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+	if limit > 1000 {
+		limit = 1000
+	}
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var events []*domain.SymbolEvent
+	for _, e := range r.store.events {
+		if e.Symbol == symbolName {
+			event := *e
+			events = append(events, &event)
+		}
+	}
+	slices.SortStableFunc(events, func(a, b *domain.SymbolEvent) int {
+		if c := b.CreatedAt.Compare(a.CreatedAt); c != 0 {
+			return c
+		}
+		return cmp.Compare(b.ID, a.ID)
+	})
+	if len(events) > limit {
+		events = events[:limit]
+	}
+	return events, nil
+}
+
+// ListAfter returns up to limit events of the given types recorded after
+// afterID, oldest first
+func (r *SymbolEventRepository) ListAfter(ctx context.Context, afterID int64, types []domain.SymbolEventType, limit int) ([]*domain.SymbolEvent, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	// Events are appended in ID order
+	var events []*domain.SymbolEvent
+	for _, e := range r.store.events {
+		if len(events) == limit {
+			break
+		}
+		if e.ID > afterID && slices.Contains(types, e.Type) {
+			event := *e
+			events = append(events, &event)
+		}
+	}
+	return events, nil
+}
+
+// DeliveryCursor returns the ID of the last event delivered to subscriber,
+// starting a new subscriber at the latest event
+func (r *SymbolEventRepository) DeliveryCursor(ctx context.Context, subscriber string) (int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	id, ok := r.store.cursors[subscriber]
+	if !ok {
+		id = r.store.nextEventID
+		r.store.cursors[subscriber] = id
+	}
+	return id, nil
+}
+
+// SetDeliveryCursor records eventID as delivered to subscriber
+func (r *SymbolEventRepository) SetDeliveryCursor(ctx context.Context, subscriber string, eventID int64) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.cursors[subscriber] = max(r.store.cursors[subscriber], eventID)
+	return nil
+}
+
+// Ensure SymbolEventRepository implements ports.SymbolEventRepository
+var _ ports.SymbolEventRepository = (*SymbolEventRepository)(nil)
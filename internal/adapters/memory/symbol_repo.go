@@ -0,0 +1,261 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sort"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+)
+
+// SymbolRepository implements the ports.SymbolRepository interface
+type SymbolRepository struct {
+	store *Store
+}
+
+// NewSymbolRepository creates a new in-memory symbol repository
+func NewSymbolRepository(store *Store) ports.SymbolRepository {
+	return &SymbolRepository{store: store}
+}
+
+// Create adds a new symbol to track. A soft deleted symbol of the same
+// name is revived with its ID, so its history carries on.
+func (r *SymbolRepository) Create(ctx context.Context, symbol *domain.Symbol) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if existing := r.store.symbolByName(symbol.Name); existing != nil {
+		if existing.DeletedAt == nil {
+			return domain.ErrSymbolExists
+		}
+		existing.Exchange = symbol.Exchange
+		existing.Active = symbol.Active
+		existing.Tags = append([]string{}, symbol.Tags...)
+		existing.UpdatedAt = symbol.UpdatedAt
+		existing.DeletedAt = nil
+		symbol.ID = existing.ID
+		return nil
+	}
+
+	r.store.nextSymbolID++
+	symbol.ID = r.store.nextSymbolID
+	r.store.symbols[symbol.ID] = copySymbol(symbol)
+	return nil
+}
+
+// GetByName retrieves a symbol by its name
+func (r *SymbolRepository) GetByName(ctx context.Context, name string) (*domain.Symbol, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	symbol := r.store.symbolByName(name)
+	if symbol == nil || symbol.DeletedAt != nil {
+		return nil, domain.ErrSymbolNotFound
+	}
+	return copySymbol(symbol), nil
+}
+
+// GetByID retrieves a symbol by its ID
+func (r *SymbolRepository) GetByID(ctx context.Context, id int64) (*domain.Symbol, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	symbol, ok := r.store.symbols[id]
+	if !ok || symbol.DeletedAt != nil {
+		return nil, domain.ErrSymbolNotFound
+	}
+	return copySymbol(symbol), nil
+}
+
+// List returns all tracked symbols
+func (r *SymbolRepository) List(ctx context.Context) ([]*domain.Symbol, error) {
+	return r.list(func(s *domain.Symbol) bool { return s.DeletedAt == nil }), nil
+}
+
+// ListIncludingDeleted returns tracked and soft deleted symbols
+func (r *SymbolRepository) ListIncludingDeleted(ctx context.Context) ([]*domain.Symbol, error) {
+	return r.list(func(s *domain.Symbol) bool { return true }), nil
+}
+
+// ListActive returns only active symbols
+func (r *SymbolRepository) ListActive(ctx context.Context) ([]*domain.Symbol, error) {
+	return r.list(func(s *domain.Symbol) bool { return s.Active && s.DeletedAt == nil }), nil
+}
+
+// ListByTag returns symbols carrying the given tag
+func (r *SymbolRepository) ListByTag(ctx context.Context, tag string) ([]*domain.Symbol, error) {
+	return r.list(func(s *domain.Symbol) bool {
+		return s.DeletedAt == nil && slices.Contains(s.Tags, tag)
+	}), nil
+}
+
+// ListPage returns a page of symbols ordered by name, optionally carrying a tag
+func (r *SymbolRepository) ListPage(ctx context.Context, tag string, after *domain.SymbolCursor, limit int) ([]*domain.Symbol, error) {
+	symbols := r.list(func(s *domain.Symbol) bool {
+		return s.DeletedAt == nil &&
+			(tag == "" || slices.Contains(s.Tags, tag)) &&
+			(after == nil || s.Name > after.Name)
+	})
+	if len(symbols) > limit {
+		symbols = symbols[:limit]
+	}
+	return symbols, nil
+}
+
+// SoftDelete removes a tracked symbol but keeps its snapshots. It is
+// deactivated, so lookups of active symbols leave it out too.
+func (r *SymbolRepository) SoftDelete(ctx context.Context, name string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	symbol := r.store.symbolByName(name)
+	if symbol == nil || symbol.DeletedAt != nil {
+		return domain.ErrSymbolNotFound
+	}
+
+	deletedAt := now()
+	symbol.Active = false
+	symbol.DeletedAt = &deletedAt
+	symbol.UpdatedAt = deletedAt
+	return nil
+}
+
+// Delete removes a symbol by name, tracked or soft deleted, together with
+// its snapshots
+func (r *SymbolRepository) Delete(ctx context.Context, name string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	symbol := r.store.symbolByName(name)
+	if symbol == nil {
+		return domain.ErrSymbolNotFound
+	}
+
+	delete(r.store.symbols, symbol.ID)
+	r.store.removeSnapshots(name, func(*domain.PriceSnapshot) bool { return true })
+	return nil
+}
+
+// Update modifies an existing symbol
+func (r *SymbolRepository) Update(ctx context.Context, symbol *domain.Symbol) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	existing, ok := r.store.symbols[symbol.ID]
+	if !ok || existing.DeletedAt != nil {
+		return domain.ErrSymbolNotFound
+	}
+	if other := r.store.symbolByName(symbol.Name); other != nil && other.ID != symbol.ID {
+		return domain.ErrSymbolExists
+	}
+
+	existing.Name = symbol.Name
+	existing.Exchange = symbol.Exchange
+	existing.Active = symbol.Active
+	existing.Tags = append([]string{}, symbol.Tags...)
+	existing.UpdatedAt = now()
+	return nil
+}
+
+// SetActive sets the active flag of the named tracked symbols and returns
+// the names whose flag changed. Nothing is changed when any of them is not
+// tracked.
+func (r *SymbolRepository) SetActive(ctx context.Context, names []string, active bool) ([]string, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	symbols := make([]*domain.Symbol, 0, len(names))
+	for _, name := range names {
+		symbol := r.store.symbolByName(name)
+		if symbol == nil || symbol.DeletedAt != nil {
+			return nil, domain.ErrSymbolNotFound
+		}
+		symbols = append(symbols, symbol)
+	}
+
+	var changed []string
+	updatedAt := now()
+	for _, symbol := range symbols {
+		if symbol.Active == active {
+			continue
+		}
+		symbol.Active = active
+		symbol.UpdatedAt = updatedAt
+		changed = append(changed, symbol.Name)
+	}
+	return changed, nil
+}
+
+// UpdateMetadata stores a symbol's coin metadata
+func (r *SymbolRepository) UpdateMetadata(ctx context.Context, name string, metadata *domain.CoinMetadata) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	symbol := r.store.symbolByName(name)
+	if symbol == nil || symbol.DeletedAt != nil {
+		return domain.ErrSymbolNotFound
+	}
+
+	stored := *metadata
+	symbol.Metadata = &stored
+	return nil
+}
+
+// Count returns total number of symbols
+func (r *SymbolRepository) Count(ctx context.Context) (int, error) {
+	return len(r.list(func(s *domain.Symbol) bool { return s.DeletedAt == nil })), nil
+}
+
+// CountActive returns number of active symbols
+func (r *SymbolRepository) CountActive(ctx context.Context) (int, error) {
+	return len(r.list(func(s *domain.Symbol) bool { return s.Active && s.DeletedAt == nil })), nil
+}
+
+// Exists checks if a symbol exists
+func (r *SymbolRepository) Exists(ctx context.Context, name string) (bool, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	symbol := r.store.symbolByName(name)
+	return symbol != nil && symbol.DeletedAt == nil, nil
+}
+
+// ExistsIncludingDeleted checks if a symbol is tracked or soft deleted
+func (r *SymbolRepository) ExistsIncludingDeleted(ctx context.Context, name string) (bool, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return r.store.symbolByName(name) != nil, nil
+}
+
+// list returns copies of the symbols matching keep, ordered by name
+func (r *SymbolRepository) list(keep func(*domain.Symbol) bool) []*domain.Symbol {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var symbols []*domain.Symbol
+	for _, s := range r.store.symbols {
+		if keep(s) {
+			symbols = append(symbols, copySymbol(s))
+		}
+	}
+	sort.Slice(symbols, func(i, j int) bool {
+		return symbols[i].Name < symbols[j].Name
+	})
+	return symbols
+}
+
+// symbolByName returns the stored symbol of a name, tracked or soft
+// deleted, or nil. The caller holds the lock.
+func (s *Store) symbolByName(name string) *domain.Symbol {
+	for _, symbol := range s.symbols {
+		if symbol.Name == name {
+			return symbol
+		}
+	}
+	return nil
+}
+
+// Ensure SymbolRepository implements ports.SymbolRepository
+var _ ports.SymbolRepository = (*SymbolRepository)(nil)
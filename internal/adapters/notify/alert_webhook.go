@@ -0,0 +1,30 @@
+package notify
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+)
+
+// AlertWebhookNotifier posts alert firings as JSON to each alert's webhook
+type AlertWebhookNotifier struct {
+	httpClient *http.Client
+}
+
+// NewAlertWebhookNotifier creates a notifier whose calls give up after timeout
+func NewAlertWebhookNotifier(timeout time.Duration) *AlertWebhookNotifier {
+	return &AlertWebhookNotifier{
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+// NotifyAlert posts the notification as JSON
+func (n *AlertWebhookNotifier) NotifyAlert(ctx context.Context, url string, notification *domain.AlertNotification) error {
+	return postJSON(ctx, n.httpClient, url, notification)
+}
+
+// Ensure AlertWebhookNotifier implements ports.AlertNotifier
+var _ ports.AlertNotifier = (*AlertWebhookNotifier)(nil)
@@ -0,0 +1,76 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+)
+
+// EmailNotifier sends digests as plain-text email over SMTP
+type EmailNotifier struct {
+	addr     string
+	username string
+	password string
+	from     string
+	to       []string
+}
+
+// NewEmailNotifier creates a notifier sending through the SMTP server at addr (host:port).
+// Authentication is skipped when username is empty.
+func NewEmailNotifier(addr, username, password, from string, to []string) *EmailNotifier {
+	return &EmailNotifier{
+		addr:     addr,
+		username: username,
+		password: password,
+		from:     from,
+		to:       to,
+	}
+}
+
+// Name identifies the channel in logs
+func (n *EmailNotifier) Name() string {
+	return "email"
+}
+
+// SendDigest sends the digest as a plain-text email
+func (n *EmailNotifier) SendDigest(ctx context.Context, digest *domain.PriceDigest) error {
+	var auth smtp.Auth
+	if n.username != "" {
+		host, _, err := net.SplitHostPort(n.addr)
+		if err != nil {
+			return fmt.Errorf("invalid smtp address: %w", err)
+		}
+		auth = smtp.PlainAuth("", n.username, n.password, host)
+	}
+
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "From: %s\r\n", n.from)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(n.to, ", "))
+	fmt.Fprintf(&msg, "Subject: Price digest (%s)\r\n", digest.Window)
+	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	msg.WriteString(strings.ReplaceAll(formatDigest(digest), "\n", "\r\n"))
+
+	// net/smtp has no context support; run the send so cancellation is honoured
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- smtp.SendMail(n.addr, auth, n.from, n.to, []byte(msg.String()))
+	}()
+
+	select {
+	case err := <-errCh:
+		if err != nil {
+			return fmt.Errorf("failed to send email: %w", err)
+		}
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Ensure EmailNotifier implements ports.Notifier
+var _ ports.Notifier = (*EmailNotifier)(nil)
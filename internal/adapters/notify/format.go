@@ -0,0 +1,40 @@
+package notify
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+)
+
+// formatDigest renders a digest as human-readable text
+func formatDigest(digest *domain.PriceDigest) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Price digest for the last %s (%s)\n", digest.Window, digest.GeneratedAt.Format(time.RFC3339))
+
+	b.WriteString("\nTop gainers:\n")
+	writeChanges(&b, digest.Gainers)
+
+	b.WriteString("\nTop losers:\n")
+	writeChanges(&b, digest.Losers)
+
+	return b.String()
+}
+
+func writeChanges(b *strings.Builder, changes []*domain.PriceChange) {
+	if len(changes) == 0 {
+		b.WriteString("  none\n")
+		return
+	}
+
+	for _, c := range changes {
+		sign := ""
+		if c.ChangePercent.IsPositive() {
+			sign = "+"
+		}
+		fmt.Fprintf(b, "  %s %s%s%% (%s -> %s)\n",
+			c.Symbol, sign, c.ChangePercent.StringFixed(2), c.OpenPrice, c.ClosePrice)
+	}
+}
@@ -0,0 +1,39 @@
+package notify
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+)
+
+// SlackNotifier posts digests to a Slack incoming webhook
+type SlackNotifier struct {
+	webhookURL string
+	httpClient *http.Client
+}
+
+// NewSlackNotifier creates a notifier posting to a Slack incoming webhook
+func NewSlackNotifier(webhookURL string, timeout time.Duration) *SlackNotifier {
+	return &SlackNotifier{
+		webhookURL: webhookURL,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+// Name identifies the channel in logs
+func (n *SlackNotifier) Name() string {
+	return "slack"
+}
+
+// SendDigest posts the digest as a plain-text Slack message
+func (n *SlackNotifier) SendDigest(ctx context.Context, digest *domain.PriceDigest) error {
+	return postJSON(ctx, n.httpClient, n.webhookURL, map[string]string{
+		"text": formatDigest(digest),
+	})
+}
+
+// Ensure SlackNotifier implements ports.Notifier
+var _ ports.Notifier = (*SlackNotifier)(nil)
@@ -0,0 +1,40 @@
+package notify
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+)
+
+// SymbolEventWebhookNotifier posts symbol event digests to an HTTP endpoint
+// in the encoder's format
+type SymbolEventWebhookNotifier struct {
+	url        string
+	encoder    ports.SymbolEventEncoder
+	httpClient *http.Client
+}
+
+// NewSymbolEventWebhookNotifier creates a notifier posting digests encoded
+// by encoder to url
+func NewSymbolEventWebhookNotifier(url string, encoder ports.SymbolEventEncoder, timeout time.Duration) *SymbolEventWebhookNotifier {
+	return &SymbolEventWebhookNotifier{
+		url:        url,
+		encoder:    encoder,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+// NotifySymbolEvents encodes and posts the digest
+func (n *SymbolEventWebhookNotifier) NotifySymbolEvents(ctx context.Context, digest *domain.SymbolEventDigest) error {
+	data, err := n.encoder.EncodeSymbolEvents(ctx, digest)
+	if err != nil {
+		return err
+	}
+	return post(ctx, n.httpClient, n.url, n.encoder.ContentType(), data)
+}
+
+// Ensure SymbolEventWebhookNotifier implements ports.SymbolEventNotifier
+var _ ports.SymbolEventNotifier = (*SymbolEventWebhookNotifier)(nil)
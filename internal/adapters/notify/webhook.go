@@ -0,0 +1,71 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+)
+
+// WebhookNotifier posts digests as JSON to an HTTP endpoint
+type WebhookNotifier struct {
+	url        string
+	httpClient *http.Client
+}
+
+// NewWebhookNotifier creates a notifier posting to url
+func NewWebhookNotifier(url string, timeout time.Duration) *WebhookNotifier {
+	return &WebhookNotifier{
+		url:        url,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+// Name identifies the channel in logs
+func (n *WebhookNotifier) Name() string {
+	return "webhook"
+}
+
+// SendDigest posts the digest as JSON
+func (n *WebhookNotifier) SendDigest(ctx context.Context, digest *domain.PriceDigest) error {
+	return postJSON(ctx, n.httpClient, n.url, digest)
+}
+
+// postJSON sends body as JSON and treats any non-2xx status as an error
+func postJSON(ctx context.Context, client *http.Client, url string, body interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to encode payload: %w", err)
+	}
+	return post(ctx, client, url, "application/json", data)
+}
+
+// post sends data of the given content type and treats any non-2xx status
+// as an error
+func post(ctx context.Context, client *http.Client, url, contentType string, data []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected response status: %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// Ensure WebhookNotifier implements ports.Notifier
+var _ ports.Notifier = (*WebhookNotifier)(nil)
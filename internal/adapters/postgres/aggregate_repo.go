@@ -0,0 +1,220 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+)
+
+// AggregateRepository implements the ports.AggregateRepository interface
+// over the raw snapshots table
+type AggregateRepository struct {
+	db *DB
+}
+
+// NewAggregateRepository creates a new PostgreSQL aggregate repository
+func NewAggregateRepository(db *DB) ports.AggregateRepository {
+	return &AggregateRepository{db: db}
+}
+
+// MinMaxAvg returns the lowest, highest and average price in the window
+func (r *AggregateRepository) MinMaxAvg(ctx context.Context, symbolName string, from, to time.Time) (*domain.PriceStats, error) {
+	query := `
+		SELECT COUNT(*), MIN(price)::text, MAX(price)::text, AVG(price)::text
+		FROM snapshots
+		WHERE symbol = $1 AND timestamp >= $2 AND timestamp < $3
+	`
+
+	var (
+		count                  int64
+		minStr, maxStr, avgStr *string
+	)
+	err := r.db.Pool.QueryRow(ctx, query, symbolName, from, to).Scan(&count, &minStr, &maxStr, &avgStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get price stats: %w", err)
+	}
+	if count == 0 {
+		return nil, domain.ErrNoSnapshots
+	}
+
+	stats := &domain.PriceStats{Symbol: symbolName, From: from, To: to, Count: count}
+	if stats.Min, err = parseDecimal(minStr); err != nil {
+		return nil, err
+	}
+	if stats.Max, err = parseDecimal(maxStr); err != nil {
+		return nil, err
+	}
+	if stats.Avg, err = parseDecimal(avgStr); err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// Candles buckets snapshots into OHLC candles of the given interval
+func (r *AggregateRepository) Candles(ctx context.Context, symbolName string, from, to time.Time, interval time.Duration) ([]*domain.Candle, error) {
+	if interval <= 0 {
+		return nil, fmt.Errorf("candle interval must be positive")
+	}
+
+	query := `
+		SELECT
+			date_bin(make_interval(secs => $4), timestamp, TIMESTAMPTZ 'epoch') AS bucket,
+			((array_agg(price ORDER BY timestamp ASC))[1])::text,
+			MAX(price)::text,
+			MIN(price)::text,
+			((array_agg(price ORDER BY timestamp DESC))[1])::text
+		FROM snapshots
+		WHERE symbol = $1 AND timestamp >= $2 AND timestamp < $3
+		GROUP BY bucket
+		ORDER BY bucket
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, symbolName, from, to, interval.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get candles: %w", err)
+	}
+	defer rows.Close()
+
+	var candles []*domain.Candle
+	for rows.Next() {
+		var (
+			openTime                     time.Time
+			openStr, highStr, lowStr, cl string
+		)
+		if err := rows.Scan(&openTime, &openStr, &highStr, &lowStr, &cl); err != nil {
+			return nil, fmt.Errorf("failed to scan candle: %w", err)
+		}
+
+		c := &domain.Candle{
+			Symbol:    symbolName,
+			OpenTime:  openTime,
+			CloseTime: openTime.Add(interval),
+		}
+		for _, field := range []struct {
+			dst *decimal.Decimal
+			src string
+		}{{&c.Open, openStr}, {&c.High, highStr}, {&c.Low, lowStr}, {&c.Close, cl}} {
+			if *field.dst, err = decimal.NewFromString(field.src); err != nil {
+				return nil, fmt.Errorf("failed to parse price: %w", err)
+			}
+		}
+		candles = append(candles, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating candles: %w", err)
+	}
+
+	if len(candles) == 0 {
+		return nil, domain.ErrNoSnapshots
+	}
+
+	return candles, nil
+}
+
+// Change returns the first and last price in the window
+func (r *AggregateRepository) Change(ctx context.Context, symbolName string, from, to time.Time) (*domain.PriceChange, error) {
+	query := `
+		WITH window_snapshots AS (
+			SELECT price, timestamp
+			FROM snapshots
+			WHERE symbol = $1 AND timestamp >= $2 AND timestamp < $3
+		), first AS (
+			SELECT price, timestamp FROM window_snapshots ORDER BY timestamp ASC LIMIT 1
+		), last AS (
+			SELECT price, timestamp FROM window_snapshots ORDER BY timestamp DESC LIMIT 1
+		)
+		SELECT first.price::text, last.price::text, first.timestamp, last.timestamp
+		FROM first, last
+	`
+
+	var (
+		openStr, closeStr string
+		openAt, closeAt   time.Time
+	)
+	err := r.db.Pool.QueryRow(ctx, query, symbolName, from, to).Scan(&openStr, &closeStr, &openAt, &closeAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrNoSnapshots
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get price change: %w", err)
+	}
+
+	open, err := decimal.NewFromString(openStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse price: %w", err)
+	}
+	closePrice, err := decimal.NewFromString(closeStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse price: %w", err)
+	}
+
+	return domain.NewPriceChange(symbolName, open, closePrice, openAt, closeAt), nil
+}
+
+// Percentiles returns the snapshot prices at the given percentiles. Each
+// result is an actual snapshot price (discrete percentile), so no precision
+// is lost to interpolation.
+func (r *AggregateRepository) Percentiles(ctx context.Context, symbolName string, from, to time.Time, percentiles []float64) ([]domain.PricePercentile, error) {
+	if err := domain.ValidatePercentiles(percentiles); err != nil {
+		return nil, err
+	}
+	if len(percentiles) == 0 {
+		return nil, nil
+	}
+
+	query := `
+		SELECT COUNT(*), (percentile_disc($4::float8[]) WITHIN GROUP (ORDER BY price))::text[]
+		FROM snapshots
+		WHERE symbol = $1 AND timestamp >= $2 AND timestamp < $3
+	`
+
+	var (
+		count  int64
+		prices []string
+	)
+	err := r.db.Pool.QueryRow(ctx, query, symbolName, from, to, percentiles).Scan(&count, &prices)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get price percentiles: %w", err)
+	}
+	if count == 0 {
+		return nil, domain.ErrNoSnapshots
+	}
+	if len(prices) != len(percentiles) {
+		return nil, fmt.Errorf("expected %d percentiles, got %d", len(percentiles), len(prices))
+	}
+
+	result := make([]domain.PricePercentile, len(percentiles))
+	for i, p := range percentiles {
+		price, err := decimal.NewFromString(prices[i])
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse price: %w", err)
+		}
+		result[i] = domain.PricePercentile{Percentile: p, Price: price}
+	}
+
+	return result, nil
+}
+
+// parseDecimal parses a nullable numeric read as text
+func parseDecimal(s *string) (decimal.Decimal, error) {
+	if s == nil {
+		return decimal.Zero, nil
+	}
+	d, err := decimal.NewFromString(*s)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("failed to parse price: %w", err)
+	}
+	return d, nil
+}
+
+// Ensure AggregateRepository implements ports.AggregateRepository
+var _ ports.AggregateRepository = (*AggregateRepository)(nil)
@@ -0,0 +1,206 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+)
+
+// AlertRepository implements the ports.AlertRepository interface
+type AlertRepository struct {
+	db *DB
+}
+
+// NewAlertRepository creates a new PostgreSQL alert repository
+func NewAlertRepository(db *DB) ports.AlertRepository {
+	return &AlertRepository{db: db}
+}
+
+const alertColumns = `
+	id, symbol, condition, threshold, window_seconds, webhook_url, firings,
+	last_firing, COALESCE(delivery, ''), COALESCE(delivery_error, ''), once, expires_at, created_at
+`
+
+// Create stores a new alert on a tracked symbol
+func (r *AlertRepository) Create(ctx context.Context, alert *domain.Alert) error {
+	query := `
+		INSERT INTO alerts (symbol_id, symbol, condition, threshold, window_seconds, webhook_url, once, expires_at, created_at)
+		SELECT id, name, $2, $3, $4, $5, $6, $7, $8
+		FROM symbols
+		WHERE name = $1 AND deleted_at IS NULL
+		RETURNING id
+	`
+
+	err := r.db.Pool.QueryRow(ctx, query,
+		alert.Rule.Symbol,
+		alert.Rule.Condition,
+		alert.Rule.Threshold.String(),
+		int64(alert.Rule.Window/time.Second),
+		alert.WebhookURL,
+		alert.Once,
+		alert.ExpiresAt,
+		alert.CreatedAt,
+	).Scan(&alert.ID)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.ErrSymbolNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create alert: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves an alert by its ID
+func (r *AlertRepository) GetByID(ctx context.Context, id int64) (*domain.Alert, error) {
+	query := `SELECT ` + alertColumns + ` FROM alerts WHERE id = $1`
+
+	alert, err := scanAlert(r.db.Pool.QueryRow(ctx, query, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrAlertNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get alert: %w", err)
+	}
+
+	return alert, nil
+}
+
+// List returns every alert, oldest first
+func (r *AlertRepository) List(ctx context.Context) ([]*domain.Alert, error) {
+	query := `SELECT ` + alertColumns + ` FROM alerts ORDER BY id`
+
+	rows, err := r.db.Pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list alerts: %w", err)
+	}
+	defer rows.Close()
+
+	var alerts []*domain.Alert
+	for rows.Next() {
+		alert, err := scanAlert(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan alert: %w", err)
+		}
+		alerts = append(alerts, alert)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating alerts: %w", err)
+	}
+
+	return alerts, nil
+}
+
+// Delete removes an alert
+func (r *AlertRepository) Delete(ctx context.Context, id int64) error {
+	result, err := r.db.Pool.Exec(ctx, `DELETE FROM alerts WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete alert: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrAlertNotFound
+	}
+
+	return nil
+}
+
+// RecordFiring counts a firing and stores it as the alert's latest
+func (r *AlertRepository) RecordFiring(ctx context.Context, id int64, firing domain.AlertFiring) error {
+	data, err := json.Marshal(firing)
+	if err != nil {
+		return fmt.Errorf("failed to encode alert firing: %w", err)
+	}
+
+	query := `
+		UPDATE alerts
+		SET firings = firings + 1, last_firing = $1, delivery = $2, delivery_error = NULL
+		WHERE id = $3
+	`
+
+	result, err := r.db.Pool.Exec(ctx, query, data, domain.AlertDeliveryPending, id)
+	if err != nil {
+		return fmt.Errorf("failed to record alert firing: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrAlertNotFound
+	}
+
+	return nil
+}
+
+// RecordDelivery stores the state of the latest firing's delivery
+func (r *AlertRepository) RecordDelivery(ctx context.Context, id int64, delivery domain.AlertDelivery, deliveryErr string) error {
+	query := `UPDATE alerts SET delivery = $1, delivery_error = NULLIF($2, '') WHERE id = $3`
+
+	result, err := r.db.Pool.Exec(ctx, query, delivery, deliveryErr, id)
+	if err != nil {
+		return fmt.Errorf("failed to record alert delivery: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrAlertNotFound
+	}
+
+	return nil
+}
+
+func scanAlert(row pgx.Row) (*domain.Alert, error) {
+	var alert domain.Alert
+	var threshold string
+	var windowSeconds int64
+	var lastFiring []byte
+
+	err := row.Scan(
+		&alert.ID,
+		&alert.Rule.Symbol,
+		&alert.Rule.Condition,
+		&threshold,
+		&windowSeconds,
+		&alert.WebhookURL,
+		&alert.Firings,
+		&lastFiring,
+		&alert.Delivery,
+		&alert.DeliveryError,
+		&alert.Once,
+		&alert.ExpiresAt,
+		&alert.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if alert.Rule.Threshold, err = decimal.NewFromString(threshold); err != nil {
+		return nil, fmt.Errorf("failed to parse alert threshold: %w", err)
+	}
+	alert.Rule.Window = time.Duration(windowSeconds) * time.Second
+	alert.CreatedAt = alert.CreatedAt.UTC()
+	if alert.ExpiresAt != nil {
+		expiresAt := alert.ExpiresAt.UTC()
+		alert.ExpiresAt = &expiresAt
+	}
+
+	if len(lastFiring) > 0 {
+		var firing domain.AlertFiring
+		if err := json.Unmarshal(lastFiring, &firing); err != nil {
+			return nil, fmt.Errorf("failed to decode alert firing: %w", err)
+		}
+		alert.LastFiring = &firing
+	}
+
+	return &alert, nil
+}
+
+// Ensure AlertRepository implements ports.AlertRepository
+var _ ports.AlertRepository = (*AlertRepository)(nil)
@@ -0,0 +1,143 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+)
+
+// CandleRepository implements the ports.CandleRepository interface
+type CandleRepository struct {
+	db *DB
+}
+
+// NewCandleRepository creates a new PostgreSQL candle repository
+func NewCandleRepository(db *DB) ports.CandleRepository {
+	return &CandleRepository{db: db}
+}
+
+// candleUpsert replaces candles rebuilt by an aggregation run
+const candleUpsert = `
+	ON CONFLICT (symbol_id, resolution, open_time) DO UPDATE SET
+		open = EXCLUDED.open,
+		high = EXCLUDED.high,
+		low = EXCLUDED.low,
+		close = EXCLUDED.close,
+		samples = EXCLUDED.samples
+`
+
+// Aggregate rebuilds every symbol's candles of interval opening in
+// [from, to). Buckets are aligned to the Unix epoch, so days start at
+// midnight UTC.
+func (r *CandleRepository) Aggregate(ctx context.Context, interval domain.CandleInterval, from, to time.Time) (int64, error) {
+	width := interval.Duration().Seconds()
+
+	source, rollup := interval.Source()
+	if !rollup {
+		query := `
+			INSERT INTO candles (symbol_id, symbol, resolution, open_time, open, high, low, close, samples)
+			SELECT symbol_id, symbol, $1, bucket,
+				(array_agg(price ORDER BY timestamp))[1],
+				MAX(price),
+				MIN(price),
+				(array_agg(price ORDER BY timestamp DESC))[1],
+				COUNT(*)
+			FROM (
+				SELECT symbol_id, symbol, price, timestamp,
+					to_timestamp(floor(extract(epoch FROM timestamp)::double precision / $2) * $2) AS bucket
+				FROM snapshots
+				WHERE timestamp >= $3 AND timestamp < $4
+			) s
+			GROUP BY symbol_id, symbol, bucket
+		` + candleUpsert
+
+		result, err := r.db.Pool.Exec(ctx, query, string(interval), width, from, to)
+		if err != nil {
+			return 0, fmt.Errorf("failed to aggregate %s candles: %w", interval, err)
+		}
+		return result.RowsAffected(), nil
+	}
+
+	query := `
+		INSERT INTO candles (symbol_id, symbol, resolution, open_time, open, high, low, close, samples)
+		SELECT symbol_id, symbol, $1, bucket,
+			(array_agg(open ORDER BY open_time))[1],
+			MAX(high),
+			MIN(low),
+			(array_agg(close ORDER BY open_time DESC))[1],
+			SUM(samples)
+		FROM (
+			SELECT symbol_id, symbol, open_time, open, high, low, close, samples,
+				to_timestamp(floor(extract(epoch FROM open_time)::double precision / $2) * $2) AS bucket
+			FROM candles
+			WHERE resolution = $5 AND open_time >= $3 AND open_time < $4
+		) c
+		GROUP BY symbol_id, symbol, bucket
+	` + candleUpsert
+
+	result, err := r.db.Pool.Exec(ctx, query, string(interval), width, from, to, string(source))
+	if err != nil {
+		return 0, fmt.Errorf("failed to roll up %s candles: %w", interval, err)
+	}
+	return result.RowsAffected(), nil
+}
+
+// List returns a symbol's candles of interval opening in [from, to),
+// oldest first
+func (r *CandleRepository) List(ctx context.Context, symbolName string, interval domain.CandleInterval, from, to time.Time, limit int) ([]*domain.Candle, error) {
+	query := `
+		SELECT symbol, open_time, open, high, low, close, samples
+		FROM candles
+		WHERE symbol = $1 AND resolution = $2 AND open_time >= $3 AND open_time < $4
+		ORDER BY open_time
+		LIMIT $5
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, symbolName, string(interval), from, to, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list candles: %w", err)
+	}
+	defer rows.Close()
+
+	width := interval.Duration()
+	var candles []*domain.Candle
+	for rows.Next() {
+		var c domain.Candle
+		var open, high, low, closing string
+		if err := rows.Scan(&c.Symbol, &c.OpenTime, &open, &high, &low, &closing, &c.Samples); err != nil {
+			return nil, fmt.Errorf("failed to scan candle: %w", err)
+		}
+
+		for _, f := range []struct {
+			dst *decimal.Decimal
+			src string
+		}{
+			{&c.Open, open},
+			{&c.High, high},
+			{&c.Low, low},
+			{&c.Close, closing},
+		} {
+			if *f.dst, err = decimal.NewFromString(f.src); err != nil {
+				return nil, fmt.Errorf("failed to parse candle: %w", err)
+			}
+		}
+
+		c.OpenTime = c.OpenTime.UTC()
+		c.CloseTime = c.OpenTime.Add(width)
+		candles = append(candles, &c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list candles: %w", err)
+	}
+
+	return candles, nil
+}
+
+// Ensure CandleRepository implements ports.CandleRepository
+var _ ports.CandleRepository = (*CandleRepository)(nil)
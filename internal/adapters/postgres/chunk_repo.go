@@ -0,0 +1,441 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+)
+
+// chunkScanWindow bounds how much of a compressed range EachBetween holds
+// in memory at once
+const chunkScanWindow = 24 * time.Hour
+
+// CompressBySymbol packs a symbol's polled snapshots older than before into
+// chunks, one per timestamp source and width-aligned bucket, and deletes
+// the packed rows. Each bucket is compressed in its own transaction. Rows
+// that cannot be packed, e.g. prices too large for a scaled int64, are left
+// in place.
+func (r *SnapshotRepository) CompressBySymbol(ctx context.Context, symbolName string, before time.Time, width time.Duration) (chunks int, rows int64, err error) {
+	oldestQuery := `
+		SELECT MIN(timestamp)
+		FROM snapshots
+		WHERE symbol = $1 AND source IS NULL AND timestamp >= $2 AND timestamp < $3
+	`
+
+	next := time.Time{}
+	for {
+		var oldest *time.Time
+		if err := r.db.Pool.QueryRow(ctx, oldestQuery, symbolName, next, before).Scan(&oldest); err != nil {
+			return chunks, rows, fmt.Errorf("failed to find uncompressed snapshots: %w", err)
+		}
+		if oldest == nil {
+			return chunks, rows, nil
+		}
+
+		start := oldest.Truncate(width)
+		end := start.Add(width)
+		if end.After(before) {
+			return chunks, rows, nil
+		}
+
+		n, packed, err := r.compressBucket(ctx, symbolName, start, end)
+		if err != nil {
+			return chunks, rows, err
+		}
+		chunks += n
+		rows += packed
+		next = end
+	}
+}
+
+// compressBucket packs one bucket of a symbol's polled snapshots
+func (r *SnapshotRepository) compressBucket(ctx context.Context, symbolName string, start, end time.Time) (int, int64, error) {
+	selectQuery := `
+		SELECT id, symbol_id, symbol, price, timestamp, ts_source
+		FROM snapshots
+		WHERE symbol = $1 AND source IS NULL AND timestamp >= $2 AND timestamp < $3
+		ORDER BY ts_source, timestamp, id
+		FOR UPDATE
+	`
+	insertQuery := `
+		INSERT INTO snapshot_chunks (symbol_id, symbol, ts_source, start_ts, end_ts, count, scale, data, ids)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+	deleteQuery := `DELETE FROM snapshots WHERE id = ANY($1)`
+
+	var chunks int
+	var rows int64
+	err := r.db.WithTx(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		chunks, rows = 0, 0
+
+		result, err := tx.Query(ctx, selectQuery, symbolName, start, end)
+		if err != nil {
+			return fmt.Errorf("failed to read snapshots to compress: %w", err)
+		}
+		snapshots, err := scanSnapshots(result)
+		if err != nil {
+			return err
+		}
+
+		for _, run := range splitByTimestampSource(snapshots) {
+			chunk, err := domain.EncodePriceChunk(run)
+			if errors.Is(err, domain.ErrUncompressible) {
+				r.db.logger.Warn("leaving snapshots uncompressed",
+					"symbol", symbolName, "from", start, "to", end, "error", err)
+				continue
+			}
+			if err != nil {
+				return err
+			}
+
+			if _, err := tx.Exec(ctx, insertQuery,
+				chunk.SymbolID,
+				chunk.Symbol,
+				chunk.TimestampSource,
+				chunk.Start,
+				chunk.End,
+				chunk.Count,
+				chunk.Scale,
+				chunk.Data,
+				chunk.IDs,
+			); err != nil {
+				return fmt.Errorf("failed to store snapshot chunk: %w", err)
+			}
+
+			ids := make([]int64, len(run))
+			for i, s := range run {
+				ids[i] = s.ID
+			}
+			if _, err := tx.Exec(ctx, deleteQuery, ids); err != nil {
+				return fmt.Errorf("failed to delete compressed snapshots: %w", err)
+			}
+
+			chunks++
+			rows += int64(len(run))
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return chunks, rows, nil
+}
+
+// chunkHistory returns up to limit of a symbol's compressed snapshots in
+// [from, to] that follow before, newest first. A nil from, to or before
+// leaves that end open.
+func (r *SnapshotRepository) chunkHistory(ctx context.Context, symbolName string, from, to *time.Time, before *domain.HistoryCursor, limit int) ([]*domain.PriceSnapshot, error) {
+	query := `
+		SELECT id, symbol_id, symbol, ts_source, start_ts, end_ts, count, scale, data, ids
+		FROM snapshot_chunks
+		WHERE symbol = $1
+			AND ($2::timestamptz IS NULL OR end_ts >= $2)
+			AND ($3::timestamptz IS NULL OR start_ts <= $3)
+		ORDER BY end_ts DESC
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, symbolName, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get compressed history: %w", err)
+	}
+	defer rows.Close()
+
+	var snapshots []*domain.PriceSnapshot
+	for rows.Next() {
+		chunk, err := scanChunk(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		// Chunks come newest end first, so once limit snapshots newer than
+		// this chunk's end are collected, no later chunk can displace them
+		if len(snapshots) >= limit && chunk.End.Before(snapshots[limit-1].Timestamp) {
+			break
+		}
+
+		decoded, err := chunk.Snapshots()
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range decoded {
+			if (from == nil || !s.Timestamp.Before(*from)) && (to == nil || !s.Timestamp.After(*to)) &&
+				(before == nil || before.Follows(s)) {
+				snapshots = append(snapshots, s)
+			}
+		}
+		sortNewestFirst(snapshots)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating snapshot chunks: %w", err)
+	}
+
+	if len(snapshots) > limit {
+		snapshots = snapshots[:limit]
+	}
+	return snapshots, nil
+}
+
+// newestCompressed returns up to limit of each symbol's newest compressed
+// snapshots, newest first, leaving out symbols with no chunks
+func (r *SnapshotRepository) newestCompressed(ctx context.Context, symbolNames []string, limit int) (map[string][]*domain.PriceSnapshot, error) {
+	newest := make(map[string][]*domain.PriceSnapshot)
+	for _, name := range symbolNames {
+		snapshots, err := r.chunkHistory(ctx, name, nil, nil, nil, limit)
+		if err != nil {
+			return nil, err
+		}
+		if len(snapshots) > 0 {
+			newest[name] = snapshots
+		}
+	}
+	return newest, nil
+}
+
+// missingSymbols returns the names in symbolNames that none of snapshots
+// belong to
+func missingSymbols(symbolNames []string, snapshots []*domain.PriceSnapshot) []string {
+	found := make(map[string]bool, len(snapshots))
+	for _, s := range snapshots {
+		found[s.Symbol] = true
+	}
+
+	var missing []string
+	for _, name := range symbolNames {
+		if !found[name] {
+			missing = append(missing, name)
+		}
+	}
+	return missing
+}
+
+// compressedSpan returns the time covered by a symbol's chunks that overlap
+// [from, to); ok is false when there are none
+func (r *SnapshotRepository) compressedSpan(ctx context.Context, symbolName string, from, to time.Time) (start, end time.Time, ok bool, err error) {
+	query := `
+		SELECT MIN(start_ts), MAX(end_ts)
+		FROM snapshot_chunks
+		WHERE symbol = $1 AND end_ts >= $2 AND start_ts < $3
+	`
+
+	var minStart, maxEnd *time.Time
+	if err := r.db.Pool.QueryRow(ctx, query, symbolName, from, to).Scan(&minStart, &maxEnd); err != nil {
+		return time.Time{}, time.Time{}, false, fmt.Errorf("failed to get compressed span: %w", err)
+	}
+	if minStart == nil {
+		return time.Time{}, time.Time{}, false, nil
+	}
+	return *minStart, *maxEnd, true, nil
+}
+
+// mergedBetween returns a symbol's stored and compressed snapshots in
+// [from, to), oldest first
+func (r *SnapshotRepository) mergedBetween(ctx context.Context, symbolName string, from, to time.Time) ([]*domain.PriceSnapshot, error) {
+	query := `
+		SELECT id, symbol_id, symbol, ts_source, start_ts, end_ts, count, scale, data, ids
+		FROM snapshot_chunks
+		WHERE symbol = $1 AND end_ts >= $2 AND start_ts < $3
+		ORDER BY start_ts
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, symbolName, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get snapshot chunks: %w", err)
+	}
+	defer rows.Close()
+
+	var snapshots []*domain.PriceSnapshot
+	for rows.Next() {
+		chunk, err := scanChunk(rows)
+		if err != nil {
+			return nil, err
+		}
+		decoded, err := chunk.Snapshots()
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range decoded {
+			if !s.Timestamp.Before(from) && s.Timestamp.Before(to) {
+				snapshots = append(snapshots, s)
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating snapshot chunks: %w", err)
+	}
+
+	err = r.eachRowBetween(ctx, symbolName, from, to, func(s *domain.PriceSnapshot) error {
+		snapshots = append(snapshots, s)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(snapshots, func(i, j int) bool {
+		return snapshots[i].Timestamp.Before(snapshots[j].Timestamp)
+	})
+	return snapshots, nil
+}
+
+// countChunked returns the number of compressed snapshots, optionally for
+// a single symbol
+func (r *SnapshotRepository) countChunked(ctx context.Context, symbolName *string) (int64, error) {
+	query := `SELECT COALESCE(SUM(count), 0) FROM snapshot_chunks WHERE $1::text IS NULL OR symbol = $1`
+
+	var count int64
+	if err := r.db.Pool.QueryRow(ctx, query, symbolName).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count compressed snapshots: %w", err)
+	}
+	return count, nil
+}
+
+// summarizeChunks returns the number of a symbol's compressed snapshots
+// and the time span their chunks cover
+func (r *SnapshotRepository) summarizeChunks(ctx context.Context, symbolName string) (*domain.HistorySummary, error) {
+	query := `SELECT COALESCE(SUM(count), 0), MIN(start_ts), MAX(end_ts) FROM snapshot_chunks WHERE symbol = $1`
+
+	summary := &domain.HistorySummary{}
+	if err := r.db.Pool.QueryRow(ctx, query, symbolName).Scan(&summary.TotalCount, &summary.Oldest, &summary.Newest); err != nil {
+		return nil, fmt.Errorf("failed to summarize compressed snapshots: %w", err)
+	}
+	return summary, nil
+}
+
+// pruneChunks removes chunks that ended before olderThan, optionally for a
+// single symbol, and returns the number of snapshots they held. A chunk
+// straddling the cutoff is kept whole until it has fully expired.
+func (r *SnapshotRepository) pruneChunks(ctx context.Context, symbolName *string, olderThan time.Time) (int64, error) {
+	query := `
+		WITH pruned AS (
+			DELETE FROM snapshot_chunks
+			WHERE ($1::text IS NULL OR symbol = $1) AND end_ts < $2
+			RETURNING count
+		)
+		SELECT COALESCE(SUM(count), 0) FROM pruned
+	`
+
+	var deleted int64
+	if err := r.db.Pool.QueryRow(ctx, query, symbolName, olderThan).Scan(&deleted); err != nil {
+		return 0, fmt.Errorf("failed to prune snapshot chunks: %w", err)
+	}
+	return deleted, nil
+}
+
+// rangeChunks counts the snapshots of a symbol's chunks that lie wholly
+// inside [from, to) and of those straddling it
+func (r *SnapshotRepository) rangeChunks(ctx context.Context, symbolName string, from, to time.Time) (inside, straddling int64, err error) {
+	query := `
+		SELECT
+			COALESCE(SUM(count) FILTER (WHERE start_ts >= $2 AND end_ts < $3), 0),
+			COALESCE(SUM(count) FILTER (WHERE start_ts < $2 OR end_ts >= $3), 0)
+		FROM snapshot_chunks
+		WHERE symbol = $1 AND end_ts >= $2 AND start_ts < $3
+	`
+
+	if err := r.db.Pool.QueryRow(ctx, query, symbolName, from, to).Scan(&inside, &straddling); err != nil {
+		return 0, 0, fmt.Errorf("failed to count snapshot chunks in range: %w", err)
+	}
+	return inside, straddling, nil
+}
+
+// deleteRangeChunks removes a symbol's chunks that lie wholly inside
+// [from, to) and returns the number of snapshots they held
+func (r *SnapshotRepository) deleteRangeChunks(ctx context.Context, symbolName string, from, to time.Time) (int64, error) {
+	query := `
+		WITH deleted AS (
+			DELETE FROM snapshot_chunks
+			WHERE symbol = $1 AND start_ts >= $2 AND end_ts < $3
+			RETURNING count
+		)
+		SELECT COALESCE(SUM(count), 0) FROM deleted
+	`
+
+	var deleted int64
+	if err := r.db.Pool.QueryRow(ctx, query, symbolName, from, to).Scan(&deleted); err != nil {
+		return 0, fmt.Errorf("failed to delete snapshot chunks in range: %w", err)
+	}
+	return deleted, nil
+}
+
+func scanChunk(rows pgx.Rows) (*domain.PriceChunk, error) {
+	var c domain.PriceChunk
+	if err := rows.Scan(&c.ID, &c.SymbolID, &c.Symbol, &c.TimestampSource, &c.Start, &c.End, &c.Count, &c.Scale, &c.Data, &c.IDs); err != nil {
+		return nil, fmt.Errorf("failed to scan snapshot chunk: %w", err)
+	}
+	return &c, nil
+}
+
+// scanSnapshots reads id, symbol_id, symbol, price, timestamp and ts_source
+// rows and closes them
+func scanSnapshots(rows pgx.Rows) ([]*domain.PriceSnapshot, error) {
+	defer rows.Close()
+
+	var snapshots []*domain.PriceSnapshot
+	for rows.Next() {
+		var s domain.PriceSnapshot
+		var priceStr string
+
+		if err := rows.Scan(&s.ID, &s.SymbolID, &s.Symbol, &priceStr, &s.Timestamp, &s.TimestampSource); err != nil {
+			return nil, fmt.Errorf("failed to scan snapshot: %w", err)
+		}
+
+		price, err := decimal.NewFromString(priceStr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse price: %w", err)
+		}
+		s.Price = price
+
+		snapshots = append(snapshots, &s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating snapshots: %w", err)
+	}
+	return snapshots, nil
+}
+
+// splitByTimestampSource splits snapshots ordered by timestamp source into
+// one run per source
+func splitByTimestampSource(snapshots []*domain.PriceSnapshot) [][]*domain.PriceSnapshot {
+	var runs [][]*domain.PriceSnapshot
+	for i, s := range snapshots {
+		if i == 0 || s.TimestampSource != snapshots[i-1].TimestampSource {
+			runs = append(runs, nil)
+		}
+		runs[len(runs)-1] = append(runs[len(runs)-1], s)
+	}
+	return runs
+}
+
+// mergeNewest combines two newest-first histories, keeping the newest limit
+func mergeNewest(a, b []*domain.PriceSnapshot, limit int) []*domain.PriceSnapshot {
+	if len(b) == 0 {
+		return a
+	}
+	merged := append(append(make([]*domain.PriceSnapshot, 0, len(a)+len(b)), a...), b...)
+	sortNewestFirst(merged)
+	if len(merged) > limit {
+		merged = merged[:limit]
+	}
+	return merged
+}
+
+// sortNewestFirst orders snapshots like history queries: by timestamp,
+// then ID, descending
+func sortNewestFirst(snapshots []*domain.PriceSnapshot) {
+	sort.SliceStable(snapshots, func(i, j int) bool {
+		if !snapshots[i].Timestamp.Equal(snapshots[j].Timestamp) {
+			return snapshots[i].Timestamp.After(snapshots[j].Timestamp)
+		}
+		return snapshots[i].ID > snapshots[j].ID
+	})
+}
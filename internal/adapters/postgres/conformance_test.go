@@ -0,0 +1,46 @@
+package postgres_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/adapters/postgres"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/config"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports/repotest"
+)
+
+// TestRepositories_Conformance runs the storage conformance suite against
+// the database at TEST_DATABASE_URL. The database is migrated and emptied,
+// so never point it at one holding data worth keeping.
+func TestRepositories_Conformance(t *testing.T) {
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+
+	repotest.Run(t, func(t *testing.T) repotest.Repositories {
+		ctx := context.Background()
+		logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+		db, err := postgres.NewDB(ctx, config.DatabaseConfig{URL: url, MaxOpenConns: 4}, nil, logger)
+		require.NoError(t, err)
+		t.Cleanup(db.Close)
+
+		db.SetMigrationsPath("file://../../../migrations")
+		require.NoError(t, db.Migrate(domain.MigrationPolicyFail))
+
+		_, err = db.Pool.Exec(ctx, "TRUNCATE symbols, snapshots, snapshot_chunks RESTART IDENTITY CASCADE")
+		require.NoError(t, err)
+
+		return repotest.Repositories{
+			Symbols:   postgres.NewSymbolRepository(db),
+			Snapshots: postgres.NewSnapshotRepository(db),
+		}
+	})
+}
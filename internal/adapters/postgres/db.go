@@ -2,27 +2,55 @@ package postgres
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"os"
 
 	"github.com/golang-migrate/migrate/v4"
-	_ "github.com/golang-migrate/migrate/v4/database/postgres"
+	migratepg "github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/source"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/multitracer"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
 
 	"github.com/prxgr4mmer/price-snapshot-service/internal/config"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
 )
 
 // DB wraps the PostgreSQL connection pool
 type DB struct {
 	Pool           *pgxpool.Pool
 	config         config.DatabaseConfig
+	connConfig     *pgx.ConnConfig
+	failover       *failoverWatcher
 	logger         *slog.Logger
 	migrationsPath string
 }
 
-// NewDB creates a new PostgreSQL connection pool
-func NewDB(ctx context.Context, cfg config.DatabaseConfig, logger *slog.Logger) (*DB, error) {
+// DBOption configures the connection pool
+type DBOption func(*pgxpool.Config)
+
+// WithPrepareConn sets a hook run before a pooled connection serves a
+// query; see pgxpool.Config.PrepareConn
+func WithPrepareConn(prepare func(context.Context, *pgx.Conn) (bool, error)) DBOption {
+	return func(c *pgxpool.Config) {
+		c.PrepareConn = prepare
+	}
+}
+
+// NewDB creates a new PostgreSQL connection pool. When a slow query
+// threshold is configured, statements exceeding it are logged and reported
+// to slowQueries, which may be nil.
+//
+// The URL may list several hosts, e.g. the members of a Patroni cluster.
+// Connections then go to the writable one: target_session_attrs defaults
+// to read-write, and the pool reconnects when the primary fails over.
+func NewDB(ctx context.Context, cfg config.DatabaseConfig, slowQueries ports.SlowQueryRecorder, logger *slog.Logger, opts ...DBOption) (*DB, error) {
 	poolConfig, err := pgxpool.ParseConfig(cfg.URL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse database URL: %w", err)
@@ -34,6 +62,21 @@ func NewDB(ctx context.Context, cfg config.DatabaseConfig, logger *slog.Logger)
 	poolConfig.MaxConnLifetime = cfg.ConnMaxLifetime
 	poolConfig.MaxConnIdleTime = cfg.ConnMaxIdleTime
 
+	if len(poolConfig.ConnConfig.Fallbacks) > 0 && poolConfig.ConnConfig.ValidateConnect == nil {
+		poolConfig.ConnConfig.ValidateConnect = pgconn.ValidateConnectTargetSessionAttrsReadWrite
+	}
+
+	failover := newFailoverWatcher(cfg.FailoverProbeInterval, logger.With("component", "postgres"))
+	tracers := []pgx.QueryTracer{failover}
+	if cfg.SlowQueryThreshold > 0 {
+		tracers = append(tracers, newSlowQueryTracer(cfg.SlowQueryThreshold, slowQueries, logger.With("component", "postgres")))
+	}
+	poolConfig.ConnConfig.Tracer = multitracer.New(tracers...)
+
+	for _, opt := range opts {
+		opt(poolConfig)
+	}
+
 	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create connection pool: %w", err)
@@ -44,15 +87,20 @@ func NewDB(ctx context.Context, cfg config.DatabaseConfig, logger *slog.Logger)
 		pool.Close()
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
+	failover.watch(pool)
 
 	logger.Info("database connection established",
+		"hosts", 1+len(poolConfig.ConnConfig.Fallbacks),
 		"max_conns", cfg.MaxOpenConns,
 		"min_conns", cfg.MaxIdleConns,
+		"slow_query_threshold", cfg.SlowQueryThreshold,
 	)
 
 	return &DB{
 		Pool:           pool,
 		config:         cfg,
+		connConfig:     poolConfig.ConnConfig,
+		failover:       failover,
 		logger:         logger.With("component", "postgres"),
 		migrationsPath: "file://migrations",
 	}, nil
@@ -63,21 +111,59 @@ func (db *DB) SetMigrationsPath(path string) {
 	db.migrationsPath = path
 }
 
-// Migrate runs database migrations
-func (db *DB) Migrate() error {
-	db.logger.Info("running database migrations", "path", db.migrationsPath)
+// Migrate runs database migrations. A dirty schema or one newer than the
+// known migrations is handled according to the policy instead of migrating.
+func (db *DB) Migrate(policy domain.MigrationPolicy) error {
+	db.logger.Info("running database migrations", "path", db.migrationsPath, "policy", policy)
 
-	m, err := migrate.New(db.migrationsPath, db.config.URL)
+	m, err := db.migrator()
 	if err != nil {
-		return fmt.Errorf("failed to create migrator: %w", err)
+		return err
 	}
 	defer m.Close()
 
+	latest, err := db.latestMigration()
+	if err != nil {
+		return err
+	}
+
+	version, dirty, err := m.Version()
+	if err != nil && err != migrate.ErrNilVersion {
+		return fmt.Errorf("failed to get migration version: %w", err)
+	}
+
+	status := domain.NewMigrationStatus(version, dirty, latest)
+	switch status.State {
+	case domain.MigrationStateDirty:
+		switch policy {
+		case domain.MigrationPolicyWarn:
+			db.logger.Warn("database schema is dirty, skipping migrations", "version", version)
+			return nil
+		case domain.MigrationPolicyForce:
+			db.logger.Warn("database schema is dirty, forcing clean state", "version", version)
+			if err := m.Force(int(version)); err != nil {
+				return fmt.Errorf("failed to force migration version: %w", err)
+			}
+		default:
+			return fmt.Errorf("%w at version %d; fix it manually or set DB_MIGRATION_POLICY", domain.ErrSchemaDirty, version)
+		}
+
+	case domain.MigrationStateAhead:
+		if policy == domain.MigrationPolicyFail {
+			return fmt.Errorf("%w: version %d, latest known %d", domain.ErrSchemaAhead, version, latest)
+		}
+		db.logger.Warn("database schema is newer than this binary, skipping migrations",
+			"version", version,
+			"latest", latest,
+		)
+		return nil
+	}
+
 	if err := m.Up(); err != nil && err != migrate.ErrNoChange {
 		return fmt.Errorf("failed to run migrations: %w", err)
 	}
 
-	version, dirty, err := m.Version()
+	version, dirty, err = m.Version()
 	if err != nil && err != migrate.ErrNilVersion {
 		return fmt.Errorf("failed to get migration version: %w", err)
 	}
@@ -90,13 +176,82 @@ func (db *DB) Migrate() error {
 	return nil
 }
 
+// migrator opens the migrations over a connection made like the pool's, so
+// a multi-host URL migrates the primary
+func (db *DB) migrator() (*migrate.Migrate, error) {
+	driver, err := migratepg.WithInstance(stdlib.OpenDB(*db.connConfig), &migratepg.Config{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create migrator: %w", err)
+	}
+
+	m, err := migrate.NewWithDatabaseInstance(db.migrationsPath, "postgres", driver)
+	if err != nil {
+		driver.Close()
+		return nil, fmt.Errorf("failed to create migrator: %w", err)
+	}
+	return m, nil
+}
+
+// MigrationStatus returns the applied schema version against the latest
+// migration shipped with the binary
+func (db *DB) MigrationStatus(ctx context.Context) (*domain.MigrationStatus, error) {
+	latest, err := db.latestMigration()
+	if err != nil {
+		return nil, err
+	}
+
+	var version int64
+	var dirty bool
+	err = db.Pool.QueryRow(ctx, `SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&version, &dirty)
+
+	var pgErr *pgconn.PgError
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		// No migration applied yet
+	case errors.As(err, &pgErr) && pgErr.Code == "42P01":
+		// schema_migrations does not exist yet
+	case err != nil:
+		return nil, fmt.Errorf("failed to get migration version: %w", err)
+	}
+
+	return domain.NewMigrationStatus(uint(version), dirty, latest), nil
+}
+
+// latestMigration returns the highest migration version in the migrations source
+func (db *DB) latestMigration() (uint, error) {
+	src, err := source.Open(db.migrationsPath)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open migrations source: %w", err)
+	}
+	defer src.Close()
+
+	version, err := src.First()
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	for {
+		next, err := src.Next(version)
+		if errors.Is(err, os.ErrNotExist) {
+			return version, nil
+		}
+		if err != nil {
+			return 0, fmt.Errorf("failed to read migrations: %w", err)
+		}
+		version = next
+	}
+}
+
 // MigrateDown rolls back all migrations
 func (db *DB) MigrateDown() error {
 	db.logger.Info("rolling back migrations", "path", db.migrationsPath)
 
-	m, err := migrate.New(db.migrationsPath, db.config.URL)
+	m, err := db.migrator()
 	if err != nil {
-		return fmt.Errorf("failed to create migrator: %w", err)
+		return err
 	}
 	defer m.Close()
 
@@ -110,9 +265,15 @@ func (db *DB) MigrateDown() error {
 // Close closes the database connection pool
 func (db *DB) Close() {
 	db.logger.Info("closing database connection")
+	db.failover.close()
 	db.Pool.Close()
 }
 
+// Failover returns the primary failover in progress, or nil
+func (db *DB) Failover() *domain.DatabaseFailover {
+	return db.failover.Failover()
+}
+
 // Ping checks if the database is reachable
 func (db *DB) Ping(ctx context.Context) error {
 	return db.Pool.Ping(ctx)
@@ -122,3 +283,25 @@ func (db *DB) Ping(ctx context.Context) error {
 func (db *DB) Stats() *pgxpool.Stat {
 	return db.Pool.Stat()
 }
+
+// PoolStats returns connection pool usage
+func (db *DB) PoolStats() *domain.DatabasePoolStats {
+	stat := db.Pool.Stat()
+	return &domain.DatabasePoolStats{
+		MaxConns:               stat.MaxConns(),
+		TotalConns:             stat.TotalConns(),
+		IdleConns:              stat.IdleConns(),
+		AcquiredConns:          stat.AcquiredConns(),
+		AcquireCount:           stat.AcquireCount(),
+		EmptyAcquireCount:      stat.EmptyAcquireCount(),
+		AcquireDurationSeconds: stat.AcquireDuration().Seconds(),
+	}
+}
+
+// Ensure DB implements ports.MigrationStatusProvider, ports.FailoverMonitor
+// and ports.PoolStatsProvider
+var (
+	_ ports.MigrationStatusProvider = (*DB)(nil)
+	_ ports.FailoverMonitor         = (*DB)(nil)
+	_ ports.PoolStatsProvider       = (*DB)(nil)
+)
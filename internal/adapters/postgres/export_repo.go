@@ -0,0 +1,61 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+)
+
+// ExportRepository implements the ports.SnapshotExporter interface with
+// COPY, which streams rows without building them up in memory
+type ExportRepository struct {
+	db *DB
+}
+
+// NewExportRepository creates a new PostgreSQL snapshot exporter
+func NewExportRepository(db *DB) ports.SnapshotExporter {
+	return &ExportRepository{db: db}
+}
+
+// ExportCSV writes a symbol's snapshots in the window [from, to) to w as CSV
+func (r *ExportRepository) ExportCSV(ctx context.Context, symbolName string, from, to time.Time, w io.Writer) (int64, error) {
+	// COPY takes no bind parameters, so the values are inlined as literals
+	query := fmt.Sprintf(`
+		COPY (
+			SELECT symbol, price, timestamp, ts_source, source
+			FROM snapshots
+			WHERE symbol = %s AND timestamp >= %s AND timestamp < %s
+			ORDER BY timestamp
+		) TO STDOUT WITH (FORMAT csv, HEADER)
+	`, quoteLiteral(symbolName), timestampLiteral(from), timestampLiteral(to))
+
+	conn, err := r.db.Pool.Acquire(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	defer conn.Release()
+
+	tag, err := conn.Conn().PgConn().CopyTo(ctx, w, query)
+	if err != nil {
+		return 0, fmt.Errorf("failed to export snapshots: %w", err)
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+// quoteLiteral quotes s as a SQL string literal. It relies on
+// standard_conforming_strings, the default since PostgreSQL 9.1.
+func quoteLiteral(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+func timestampLiteral(t time.Time) string {
+	return quoteLiteral(t.UTC().Format(time.RFC3339Nano)) + "::timestamptz"
+}
+
+// Ensure ExportRepository implements ports.SnapshotExporter
+var _ ports.SnapshotExporter = (*ExportRepository)(nil)
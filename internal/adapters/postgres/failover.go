@@ -0,0 +1,185 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+)
+
+// SQLSTATE codes seen while the primary is going away or has been demoted
+const (
+	sqlStateReadOnlyTransaction = "25006"
+	sqlStateAdminShutdown       = "57P01"
+	sqlStateCrashShutdown       = "57P02"
+	sqlStateCannotConnectNow    = "57P03"
+	sqlStateClassConnection     = "08"
+)
+
+// failoverWatcher notices when the primary stops accepting writes, e.g.
+// during a Patroni switchover, and reconnects the pool. New connections
+// are checked against target_session_attrs, so a multi-host DSN lands on
+// the new primary once one is promoted. It implements pgx.QueryTracer and
+// pgxpool.AcquireTracer to see every query and connection error.
+type failoverWatcher struct {
+	pool          atomic.Pointer[pgxpool.Pool]
+	probeInterval time.Duration
+	logger        *slog.Logger
+
+	mu       sync.Mutex
+	failover *domain.DatabaseFailover
+	closed   chan struct{}
+}
+
+func newFailoverWatcher(probeInterval time.Duration, logger *slog.Logger) *failoverWatcher {
+	return &failoverWatcher{
+		probeInterval: probeInterval,
+		logger:        logger,
+		closed:        make(chan struct{}),
+	}
+}
+
+// watch starts reconnecting pool on failover errors
+func (w *failoverWatcher) watch(pool *pgxpool.Pool) {
+	w.pool.Store(pool)
+}
+
+// TraceQueryStart is a no-op
+func (w *failoverWatcher) TraceQueryStart(ctx context.Context, _ *pgx.Conn, _ pgx.TraceQueryStartData) context.Context {
+	return ctx
+}
+
+// TraceQueryEnd starts a failover when the query failed because the
+// primary went away
+func (w *failoverWatcher) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	if isFailoverError(data.Err) {
+		w.begin(data.Err)
+	}
+}
+
+// TraceAcquireStart is a no-op
+func (w *failoverWatcher) TraceAcquireStart(ctx context.Context, _ *pgxpool.Pool, _ pgxpool.TraceAcquireStartData) context.Context {
+	return ctx
+}
+
+// TraceAcquireEnd starts a failover when no connection could be opened
+func (w *failoverWatcher) TraceAcquireEnd(ctx context.Context, _ *pgxpool.Pool, data pgxpool.TraceAcquireEndData) {
+	if isFailoverError(data.Err) {
+		w.begin(data.Err)
+	}
+}
+
+// Failover returns the failover in progress, or nil
+func (w *failoverWatcher) Failover() *domain.DatabaseFailover {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.failover == nil {
+		return nil
+	}
+	failover := *w.failover
+	return &failover
+}
+
+// begin drops the pooled connections, which may still point at the old
+// primary, and probes until a writable primary answers
+func (w *failoverWatcher) begin(cause error) {
+	pool := w.pool.Load()
+	if pool == nil {
+		return
+	}
+
+	w.mu.Lock()
+	if w.failover != nil {
+		w.mu.Unlock()
+		return
+	}
+	w.failover = &domain.DatabaseFailover{Since: time.Now().UTC(), Reason: cause.Error()}
+	w.mu.Unlock()
+
+	w.logger.Warn("database failover detected, reconnecting", "error", cause)
+	pool.Reset()
+	go w.probe(pool)
+}
+
+// probe checks the primary every probe interval until it accepts writes
+func (w *failoverWatcher) probe(pool *pgxpool.Pool) {
+	ticker := time.NewTicker(w.probeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.closed:
+			return
+		case <-ticker.C:
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), w.probeInterval)
+		var inRecovery bool
+		err := pool.QueryRow(ctx, `SELECT pg_is_in_recovery()`).Scan(&inRecovery)
+		cancel()
+
+		switch {
+		case err != nil:
+			w.logger.Debug("database still unavailable", "error", err)
+		case inRecovery:
+			// Reached a standby; reconnect until the new primary is found
+			w.logger.Debug("database host is a standby, reconnecting")
+			pool.Reset()
+		default:
+			w.mu.Lock()
+			since := w.failover.Since
+			w.failover = nil
+			w.mu.Unlock()
+
+			w.logger.Info("database failover completed", "duration", time.Since(since))
+			return
+		}
+	}
+}
+
+// close stops probing
+func (w *failoverWatcher) close() {
+	close(w.closed)
+}
+
+// isFailoverError reports whether err means the primary is unreachable,
+// shutting down or no longer writable
+func isFailoverError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case sqlStateReadOnlyTransaction, sqlStateAdminShutdown, sqlStateCrashShutdown, sqlStateCannotConnectNow:
+			return true
+		}
+		return strings.HasPrefix(pgErr.Code, sqlStateClassConnection)
+	}
+
+	var connectErr *pgconn.ConnectError
+	var netErr *net.OpError
+	return errors.As(err, &connectErr) ||
+		errors.As(err, &netErr) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// Ensure failoverWatcher traces queries and acquires
+var (
+	_ pgx.QueryTracer       = (*failoverWatcher)(nil)
+	_ pgxpool.AcquireTracer = (*failoverWatcher)(nil)
+)
@@ -0,0 +1,331 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+)
+
+// JobRepository implements the ports.JobRepository interface
+type JobRepository struct {
+	db *DB
+}
+
+// NewJobRepository creates a new PostgreSQL job repository
+func NewJobRepository(db *DB) ports.JobRepository {
+	return &JobRepository{db: db}
+}
+
+const jobColumns = `
+	id, type, status, payload, progress, attempts, max_attempts,
+	COALESCE(error, ''), scheduled_at, started_at, finished_at, created_at, updated_at
+`
+
+// Create enqueues a new job
+func (r *JobRepository) Create(ctx context.Context, job *domain.Job) error {
+	query := `
+		INSERT INTO jobs (type, status, payload, max_attempts, scheduled_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id
+	`
+
+	var payload []byte
+	if len(job.Payload) > 0 {
+		payload = job.Payload
+	}
+
+	err := r.db.Pool.QueryRow(ctx, query,
+		job.Type,
+		job.Status,
+		payload,
+		job.MaxAttempts,
+		job.ScheduledAt,
+		job.CreatedAt,
+		job.UpdatedAt,
+	).Scan(&job.ID)
+
+	if err != nil {
+		return fmt.Errorf("failed to create job: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a job by its ID
+func (r *JobRepository) GetByID(ctx context.Context, id int64) (*domain.Job, error) {
+	query := `SELECT ` + jobColumns + ` FROM jobs WHERE id = $1`
+
+	job, err := scanJob(r.db.Pool.QueryRow(ctx, query, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrJobNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job: %w", err)
+	}
+
+	return job, nil
+}
+
+// List returns the most recent jobs, optionally filtered by status
+func (r *JobRepository) List(ctx context.Context, status domain.JobStatus, limit int) ([]*domain.Job, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+	if limit > 1000 {
+		limit = 1000
+	}
+
+	query := `
+		SELECT ` + jobColumns + `
+		FROM jobs
+		WHERE $1::text = '' OR status = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, string(status), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list jobs: %w", err)
+	}
+	defer rows.Close()
+
+	var jobs []*domain.Job
+	for rows.Next() {
+		job, err := scanJob(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan job: %w", err)
+		}
+		jobs = append(jobs, job)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating jobs: %w", err)
+	}
+
+	return jobs, nil
+}
+
+// ClaimNext atomically marks the next due pending job, or a running job
+// whose updated_at has not moved for staleAfter, as running. The
+// updated_at trigger turns every heartbeat and progress update into a
+// lease renewal. Stale jobs that have used up their attempts are failed
+// in the same statement rather than claimed, so a job that keeps killing
+// its worker is not retried forever. Returns nil when no job is due.
+func (r *JobRepository) ClaimNext(ctx context.Context, staleAfter time.Duration) (*domain.Job, error) {
+	query := `
+		WITH expired AS (
+			UPDATE jobs
+			SET status = 'failed', error = $2, finished_at = NOW()
+			WHERE status = 'running' AND attempts >= max_attempts
+				AND updated_at < NOW() - make_interval(secs => $1)
+		)
+		UPDATE jobs
+		SET status = 'running', attempts = attempts + 1, started_at = NOW(), error = NULL
+		WHERE id = (
+			SELECT id FROM jobs
+			WHERE (status = 'pending' AND scheduled_at <= NOW())
+				OR (status = 'running' AND attempts < max_attempts
+					AND updated_at < NOW() - make_interval(secs => $1))
+			ORDER BY scheduled_at, id
+			FOR UPDATE SKIP LOCKED
+			LIMIT 1
+		)
+		RETURNING ` + jobColumns
+
+	job, err := scanJob(r.db.Pool.QueryRow(ctx, query, staleAfter.Seconds(), domain.JobLeaseExpiredError))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim job: %w", err)
+	}
+
+	return job, nil
+}
+
+// Heartbeat renews the lease of a running job's attempt
+func (r *JobRepository) Heartbeat(ctx context.Context, id int64, attempt int) error {
+	query := `UPDATE jobs SET updated_at = NOW() WHERE id = $1 AND status = 'running' AND attempts = $2`
+
+	result, err := r.db.Pool.Exec(ctx, query, id, attempt)
+	if err != nil {
+		return fmt.Errorf("failed to record job heartbeat: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return r.leaseError(ctx, id)
+	}
+
+	return nil
+}
+
+// UpdateProgress stores the progress of a running job's attempt
+func (r *JobRepository) UpdateProgress(ctx context.Context, id int64, attempt int, progress domain.JobProgress) error {
+	data, err := json.Marshal(progress)
+	if err != nil {
+		return fmt.Errorf("failed to encode job progress: %w", err)
+	}
+
+	query := `UPDATE jobs SET progress = $1 WHERE id = $2 AND status = 'running' AND attempts = $3`
+
+	result, err := r.db.Pool.Exec(ctx, query, data, id, attempt)
+	if err != nil {
+		return fmt.Errorf("failed to update job progress: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return r.leaseError(ctx, id)
+	}
+
+	return nil
+}
+
+// Finish stores a job's final status, error and schedule
+func (r *JobRepository) Finish(ctx context.Context, job *domain.Job) error {
+	query := `
+		UPDATE jobs
+		SET status = $1, progress = $2, error = NULLIF($3, ''), scheduled_at = $4, finished_at = $5
+		WHERE id = $6 AND status = 'running' AND attempts = $7
+	`
+
+	progress, err := json.Marshal(job.Progress)
+	if err != nil {
+		return fmt.Errorf("failed to encode job progress: %w", err)
+	}
+
+	result, err := r.db.Pool.Exec(ctx, query,
+		job.Status,
+		progress,
+		job.Error,
+		job.ScheduledAt,
+		job.FinishedAt,
+		job.ID,
+		job.Attempts,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to finish job: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		if err := r.leaseError(ctx, job.ID); !errors.Is(err, domain.ErrJobCancelled) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Cancel marks a pending or running job as cancelled
+func (r *JobRepository) Cancel(ctx context.Context, id int64) error {
+	query := `
+		UPDATE jobs
+		SET status = 'cancelled', finished_at = NOW()
+		WHERE id = $1 AND status IN ('pending', 'running')
+	`
+
+	result, err := r.db.Pool.Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to cancel job: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return r.missingOr(ctx, id, domain.ErrJobFinished)
+	}
+
+	return nil
+}
+
+// Requeue makes a cancelled or failed job pending again, keeping its progress
+func (r *JobRepository) Requeue(ctx context.Context, id int64) error {
+	query := `
+		UPDATE jobs
+		SET status = 'pending', attempts = 0, error = NULL, scheduled_at = NOW(), finished_at = NULL
+		WHERE id = $1 AND status IN ('cancelled', 'failed')
+	`
+
+	result, err := r.db.Pool.Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to requeue job: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return r.missingOr(ctx, id, domain.ErrJobNotResumable)
+	}
+
+	return nil
+}
+
+// missingOr returns ErrJobNotFound if the job does not exist, otherwise fallback
+func (r *JobRepository) missingOr(ctx context.Context, id int64, fallback error) error {
+	var exists bool
+	if err := r.db.Pool.QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM jobs WHERE id = $1)`, id).Scan(&exists); err != nil {
+		return fmt.Errorf("failed to check job existence: %w", err)
+	}
+	if !exists {
+		return domain.ErrJobNotFound
+	}
+	return fallback
+}
+
+// leaseError explains why an update fenced on a running attempt matched
+// no row: the job is gone, was cancelled, or another worker claimed it
+func (r *JobRepository) leaseError(ctx context.Context, id int64) error {
+	var status domain.JobStatus
+	err := r.db.Pool.QueryRow(ctx, `SELECT status FROM jobs WHERE id = $1`, id).Scan(&status)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.ErrJobNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to check job status: %w", err)
+	}
+	if status == domain.JobStatusCancelled {
+		return domain.ErrJobCancelled
+	}
+	return domain.ErrJobLeaseLost
+}
+
+func scanJob(row pgx.Row) (*domain.Job, error) {
+	var job domain.Job
+	var payload, progress []byte
+
+	err := row.Scan(
+		&job.ID,
+		&job.Type,
+		&job.Status,
+		&payload,
+		&progress,
+		&job.Attempts,
+		&job.MaxAttempts,
+		&job.Error,
+		&job.ScheduledAt,
+		&job.StartedAt,
+		&job.FinishedAt,
+		&job.CreatedAt,
+		&job.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(payload) > 0 {
+		job.Payload = json.RawMessage(payload)
+	}
+
+	if len(progress) > 0 {
+		if err := json.Unmarshal(progress, &job.Progress); err != nil {
+			return nil, fmt.Errorf("failed to decode job progress: %w", err)
+		}
+	}
+
+	return &job, nil
+}
+
+// Ensure JobRepository implements ports.JobRepository
+var _ ports.JobRepository = (*JobRepository)(nil)
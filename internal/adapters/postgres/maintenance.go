@@ -0,0 +1,45 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+)
+
+// snapshotTables are the tables holding snapshots, raw and compressed
+var snapshotTables = []string{"snapshots", "snapshot_chunks"}
+
+// AnalyzeSnapshots runs ANALYZE on the snapshot tables so the planner sees
+// their current size after a large prune or import. It returns the
+// tables' statistics as they were before, whose dead rows estimate what
+// VACUUM can reclaim.
+func (db *DB) AnalyzeSnapshots(ctx context.Context) ([]domain.TableStats, error) {
+	query := `
+		SELECT n_live_tup, n_dead_tup, GREATEST(last_analyze, last_autoanalyze)
+		FROM pg_stat_user_tables
+		WHERE relname = $1
+	`
+
+	stats := make([]domain.TableStats, 0, len(snapshotTables))
+	for _, table := range snapshotTables {
+		s := domain.TableStats{Table: table}
+		err := db.Pool.QueryRow(ctx, query, table).Scan(&s.LiveRows, &s.DeadRows, &s.LastAnalyzed)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("failed to read statistics of %s: %w", table, err)
+		}
+
+		if _, err := db.Pool.Exec(ctx, "ANALYZE "+pgx.Identifier{table}.Sanitize()); err != nil {
+			return nil, fmt.Errorf("failed to analyze %s: %w", table, err)
+		}
+		stats = append(stats, s)
+	}
+	return stats, nil
+}
+
+// Ensure DB implements ports.SnapshotTableAnalyzer
+var _ ports.SnapshotTableAnalyzer = (*DB)(nil)
@@ -0,0 +1,57 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+)
+
+// RateLimitRepository implements the ports.RateLimitRepository interface
+type RateLimitRepository struct {
+	db *DB
+}
+
+// NewRateLimitRepository creates a new PostgreSQL rate limit repository
+func NewRateLimitRepository(db *DB) ports.RateLimitRepository {
+	return &RateLimitRepository{db: db}
+}
+
+// Increment counts a request against key in the window starting at
+// windowStart and returns the window's count
+func (r *RateLimitRepository) Increment(ctx context.Context, key string, windowStart time.Time) (int, error) {
+	query := `
+		WITH expired AS (
+			DELETE FROM rate_limit_counters
+			WHERE key = $1 AND window_start < $2
+		)
+		INSERT INTO rate_limit_counters (key, window_start, count)
+		VALUES ($1, $2, 1)
+		ON CONFLICT (key, window_start)
+		DO UPDATE SET count = rate_limit_counters.count + 1
+		RETURNING count
+	`
+
+	var count int
+	if err := r.db.Pool.QueryRow(ctx, query, key, windowStart).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to increment rate limit counter: %w", err)
+	}
+
+	return count, nil
+}
+
+// DeleteBefore removes counters of windows that started before the cutoff
+func (r *RateLimitRepository) DeleteBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	query := `DELETE FROM rate_limit_counters WHERE window_start < $1`
+
+	result, err := r.db.Pool.Exec(ctx, query, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete rate limit counters: %w", err)
+	}
+
+	return result.RowsAffected(), nil
+}
+
+// Ensure RateLimitRepository implements ports.RateLimitRepository
+var _ ports.RateLimitRepository = (*RateLimitRepository)(nil)
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/jackc/pgx/v5"
@@ -23,11 +24,17 @@ func NewSnapshotRepository(db *DB) ports.SnapshotRepository {
 	return &SnapshotRepository{db: db}
 }
 
-// Create stores a new price snapshot
+// Create stores a new price snapshot. A polled snapshot whose run already
+// stored its symbol is not stored again; the stored row's ID is returned.
 func (r *SnapshotRepository) Create(ctx context.Context, snapshot *domain.PriceSnapshot) error {
 	query := `
-		INSERT INTO snapshots (symbol_id, symbol, price, timestamp)
-		VALUES ($1, $2, $3, $4)
+		INSERT INTO snapshots (
+			symbol_id, symbol, price, timestamp, ts_source, fetch_latency_ms, poll_run_id, exchange,
+			ingest_mode, ingest_latency_ms, pipeline_version
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		ON CONFLICT (poll_run_id, symbol_id) WHERE poll_run_id IS NOT NULL
+		DO UPDATE SET poll_run_id = EXCLUDED.poll_run_id
 		RETURNING id
 	`
 
@@ -36,6 +43,13 @@ func (r *SnapshotRepository) Create(ctx context.Context, snapshot *domain.PriceS
 		snapshot.Symbol,
 		snapshot.Price,
 		snapshot.Timestamp,
+		timestampSource(snapshot),
+		snapshot.FetchLatencyMs,
+		pollRunID(snapshot),
+		exchangeName(snapshot),
+		ingestMode(snapshot),
+		snapshot.IngestLatencyMs,
+		pipelineVersion(snapshot),
 	).Scan(&snapshot.ID)
 
 	if err != nil {
@@ -45,48 +59,165 @@ func (r *SnapshotRepository) Create(ctx context.Context, snapshot *domain.PriceS
 	return nil
 }
 
-// CreateBatch stores multiple snapshots atomically
+// CreateBatch stores multiple snapshots atomically. Like Create, it skips
+// snapshots their poll run already stored, so a retried write is a no-op.
 func (r *SnapshotRepository) CreateBatch(ctx context.Context, snapshots []*domain.PriceSnapshot) error {
 	if len(snapshots) == 0 {
 		return nil
 	}
 
-	tx, err := r.db.Pool.Begin(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
+	query := `
+		INSERT INTO snapshots (
+			symbol_id, symbol, price, timestamp, ts_source, fetch_latency_ms, poll_run_id, exchange,
+			ingest_mode, ingest_latency_ms, pipeline_version
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		ON CONFLICT (poll_run_id, symbol_id) WHERE poll_run_id IS NOT NULL
+		DO UPDATE SET poll_run_id = EXCLUDED.poll_run_id
+		RETURNING id
+	`
+
+	return r.db.WithTx(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		for _, snapshot := range snapshots {
+			err := tx.QueryRow(ctx, query,
+				snapshot.SymbolID,
+				snapshot.Symbol,
+				snapshot.Price,
+				snapshot.Timestamp,
+				timestampSource(snapshot),
+				snapshot.FetchLatencyMs,
+				pollRunID(snapshot),
+				exchangeName(snapshot),
+				ingestMode(snapshot),
+				snapshot.IngestLatencyMs,
+				pipelineVersion(snapshot),
+			).Scan(&snapshot.ID)
+
+			if err != nil {
+				return fmt.Errorf("failed to create snapshot for %s: %w", snapshot.Symbol, err)
+			}
+		}
+		return nil
+	})
+}
+
+// CreateBatchPartial stores every snapshot it can. Each row is inserted
+// under its own savepoint, so a row violating a constraint is rolled back
+// and reported without discarding the others.
+func (r *SnapshotRepository) CreateBatchPartial(ctx context.Context, snapshots []*domain.PriceSnapshot) (*domain.BatchWriteResult, error) {
+	if len(snapshots) == 0 {
+		return &domain.BatchWriteResult{}, nil
 	}
-	defer tx.Rollback(ctx)
 
 	query := `
-		INSERT INTO snapshots (symbol_id, symbol, price, timestamp)
-		VALUES ($1, $2, $3, $4)
+		INSERT INTO snapshots (
+			symbol_id, symbol, price, timestamp, ts_source, fetch_latency_ms, poll_run_id, exchange,
+			ingest_mode, ingest_latency_ms, pipeline_version
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		ON CONFLICT (poll_run_id, symbol_id) WHERE poll_run_id IS NOT NULL
+		DO UPDATE SET poll_run_id = EXCLUDED.poll_run_id
 		RETURNING id
 	`
 
-	for _, snapshot := range snapshots {
-		err := tx.QueryRow(ctx, query,
-			snapshot.SymbolID,
-			snapshot.Symbol,
-			snapshot.Price,
-			snapshot.Timestamp,
-		).Scan(&snapshot.ID)
-
-		if err != nil {
-			return fmt.Errorf("failed to create snapshot for %s: %w", snapshot.Symbol, err)
+	var result *domain.BatchWriteResult
+	err := r.db.WithTx(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		result = &domain.BatchWriteResult{}
+
+		for _, snapshot := range snapshots {
+			savepoint, err := tx.Begin(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to create savepoint: %w", err)
+			}
+
+			err = savepoint.QueryRow(ctx, query,
+				snapshot.SymbolID,
+				snapshot.Symbol,
+				snapshot.Price,
+				snapshot.Timestamp,
+				timestampSource(snapshot),
+				snapshot.FetchLatencyMs,
+				pollRunID(snapshot),
+				exchangeName(snapshot),
+				ingestMode(snapshot),
+				snapshot.IngestLatencyMs,
+				pipelineVersion(snapshot),
+			).Scan(&snapshot.ID)
+
+			if err != nil {
+				if rbErr := savepoint.Rollback(ctx); rbErr != nil {
+					return fmt.Errorf("failed to roll back snapshot for %s: %w", snapshot.Symbol, rbErr)
+				}
+				// A cancelled or timed out batch is not a row failure
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				snapshot.ID = 0
+				result.Failed = append(result.Failed, domain.BatchWriteFailure{
+					Symbol: snapshot.Symbol,
+					Error:  err.Error(),
+				})
+				continue
+			}
+
+			if err := savepoint.Commit(ctx); err != nil {
+				return fmt.Errorf("failed to release savepoint: %w", err)
+			}
+			result.Written++
 		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	if err := tx.Commit(ctx); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
+	return result, nil
+}
+
+// CreateBatchIngested stores pushed snapshots atomically. A snapshot whose
+// symbol, timestamp and source are already stored is skipped, which makes
+// retried pushes idempotent.
+func (r *SnapshotRepository) CreateBatchIngested(ctx context.Context, snapshots []*domain.PriceSnapshot) (int, error) {
+	if len(snapshots) == 0 {
+		return 0, nil
 	}
 
-	return nil
+	query := `
+		INSERT INTO snapshots (symbol_id, symbol, price, timestamp, ts_source, source)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (symbol, timestamp, source) WHERE source IS NOT NULL DO NOTHING
+	`
+
+	stored := 0
+	err := r.db.WithTx(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		stored = 0
+		for _, snapshot := range snapshots {
+			tag, err := tx.Exec(ctx, query,
+				snapshot.SymbolID,
+				snapshot.Symbol,
+				snapshot.Price,
+				snapshot.Timestamp,
+				timestampSource(snapshot),
+				snapshot.Source,
+			)
+			if err != nil {
+				return fmt.Errorf("failed to ingest snapshot for %s: %w", snapshot.Symbol, err)
+			}
+			stored += int(tag.RowsAffected())
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return stored, nil
 }
 
 // GetLatestBySymbol returns the most recent snapshot for a symbol
 func (r *SnapshotRepository) GetLatestBySymbol(ctx context.Context, symbolName string) (*domain.PriceSnapshot, error) {
 	query := `
-		SELECT id, symbol_id, symbol, price, timestamp
+		SELECT id, symbol_id, symbol, price, timestamp, ts_source, fetch_latency_ms, COALESCE(exchange, '')
 		FROM snapshots
 		WHERE symbol = $1
 		ORDER BY timestamp DESC
@@ -102,10 +233,20 @@ func (r *SnapshotRepository) GetLatestBySymbol(ctx context.Context, symbolName s
 		&snapshot.Symbol,
 		&priceStr,
 		&snapshot.Timestamp,
+		&snapshot.TimestampSource,
+		&snapshot.FetchLatencyMs,
+		&snapshot.Exchange,
 	)
 
 	if errors.Is(err, pgx.ErrNoRows) {
-		return nil, domain.ErrSnapshotNotFound
+		compressed, err := r.chunkHistory(ctx, symbolName, nil, nil, nil, 1)
+		if err != nil {
+			return nil, err
+		}
+		if len(compressed) == 0 {
+			return nil, domain.ErrSnapshotNotFound
+		}
+		return compressed[0], nil
 	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get latest snapshot: %w", err)
@@ -126,8 +267,9 @@ func (r *SnapshotRepository) GetLatestBySymbols(ctx context.Context, symbolNames
 	}
 
 	query := `
+		-- name: latest_by_symbols
 		SELECT DISTINCT ON (symbol)
-			id, symbol_id, symbol, price, timestamp
+			id, symbol_id, symbol, price, timestamp, ts_source, fetch_latency_ms, COALESCE(exchange, '')
 		FROM snapshots
 		WHERE symbol = ANY($1)
 		ORDER BY symbol, timestamp DESC
@@ -144,7 +286,7 @@ func (r *SnapshotRepository) GetLatestBySymbols(ctx context.Context, symbolNames
 		var s domain.PriceSnapshot
 		var priceStr string
 
-		if err := rows.Scan(&s.ID, &s.SymbolID, &s.Symbol, &priceStr, &s.Timestamp); err != nil {
+		if err := rows.Scan(&s.ID, &s.SymbolID, &s.Symbol, &priceStr, &s.Timestamp, &s.TimestampSource, &s.FetchLatencyMs, &s.Exchange); err != nil {
 			return nil, fmt.Errorf("failed to scan snapshot: %w", err)
 		}
 
@@ -160,29 +302,52 @@ func (r *SnapshotRepository) GetLatestBySymbols(ctx context.Context, symbolNames
 		return nil, fmt.Errorf("error iterating snapshots: %w", err)
 	}
 
+	// Symbols whose rows have all been compressed fall back to their
+	// newest chunk
+	missing := missingSymbols(symbolNames, snapshots)
+	if len(missing) == 0 {
+		return snapshots, nil
+	}
+	compressed, err := r.newestCompressed(ctx, missing, 1)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range missing {
+		if c, ok := compressed[name]; ok {
+			snapshots = append(snapshots, c[0])
+		}
+	}
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].Symbol < snapshots[j].Symbol
+	})
+
 	return snapshots, nil
 }
 
-// GetHistory returns historical snapshots for a symbol
-func (r *SnapshotRepository) GetHistory(ctx context.Context, symbolName string, limit int) ([]*domain.PriceSnapshot, error) {
-	if limit <= 0 {
-		limit = 100
-	}
-	if limit > 1000 {
-		limit = 1000
+// GetPreviousBySymbols returns the snapshot before the most recent one for
+// multiple symbols. The lateral lookup walks each symbol's
+// (symbol, timestamp) index for two rows instead of scanning its history.
+func (r *SnapshotRepository) GetPreviousBySymbols(ctx context.Context, symbolNames []string) ([]*domain.PriceSnapshot, error) {
+	if len(symbolNames) == 0 {
+		return nil, nil
 	}
 
 	query := `
-		SELECT id, symbol_id, symbol, price, timestamp
-		FROM snapshots
-		WHERE symbol = $1
-		ORDER BY timestamp DESC
-		LIMIT $2
+		-- name: previous_by_symbols
+		SELECT p.id, p.symbol_id, p.symbol, p.price, p.timestamp, p.ts_source
+		FROM unnest($1::text[]) AS s(symbol)
+		CROSS JOIN LATERAL (
+			SELECT id, symbol_id, symbol, price, timestamp, ts_source
+			FROM snapshots
+			WHERE symbol = s.symbol
+			ORDER BY timestamp DESC
+			OFFSET 1 LIMIT 1
+		) p
 	`
 
-	rows, err := r.db.Pool.Query(ctx, query, symbolName, limit)
+	rows, err := r.db.Pool.Query(ctx, query, symbolNames)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get history: %w", err)
+		return nil, fmt.Errorf("failed to get previous snapshots: %w", err)
 	}
 	defer rows.Close()
 
@@ -191,7 +356,7 @@ func (r *SnapshotRepository) GetHistory(ctx context.Context, symbolName string,
 		var s domain.PriceSnapshot
 		var priceStr string
 
-		if err := rows.Scan(&s.ID, &s.SymbolID, &s.Symbol, &priceStr, &s.Timestamp); err != nil {
+		if err := rows.Scan(&s.ID, &s.SymbolID, &s.Symbol, &priceStr, &s.Timestamp, &s.TimestampSource); err != nil {
 			return nil, fmt.Errorf("failed to scan snapshot: %w", err)
 		}
 
@@ -207,54 +372,264 @@ func (r *SnapshotRepository) GetHistory(ctx context.Context, symbolName string,
 		return nil, fmt.Errorf("error iterating snapshots: %w", err)
 	}
 
+	// A symbol with fewer than two rows left takes its previous snapshot
+	// from its chunks: the newest compressed one older than its latest
+	missing := missingSymbols(symbolNames, snapshots)
+	if len(missing) == 0 {
+		return snapshots, nil
+	}
+	latest, err := r.GetLatestBySymbols(ctx, missing)
+	if err != nil {
+		return nil, err
+	}
+	compressed, err := r.newestCompressed(ctx, missing, 2)
+	if err != nil {
+		return nil, err
+	}
+	for _, l := range latest {
+		for _, c := range compressed[l.Symbol] {
+			if c.Timestamp.Before(l.Timestamp) {
+				snapshots = append(snapshots, c)
+				break
+			}
+		}
+	}
+
 	return snapshots, nil
 }
 
-// GetHistoryBetween returns snapshots within a time range
-func (r *SnapshotRepository) GetHistoryBetween(ctx context.Context, symbolName string, from, to time.Time, limit int) ([]*domain.PriceSnapshot, error) {
+// GetHistory returns historical snapshots for a symbol, newest first,
+// continuing after before when it is set
+func (r *SnapshotRepository) GetHistory(ctx context.Context, symbolName string, before *domain.HistoryCursor, limit int) ([]*domain.PriceSnapshot, error) {
 	if limit <= 0 {
 		limit = 100
 	}
-	if limit > 1000 {
-		limit = 1000
+	if limit > domain.MaxHistoryLimit {
+		limit = domain.MaxHistoryLimit
 	}
 
+	beforeTs, beforeID := cursorArgs(before)
 	query := `
-		SELECT id, symbol_id, symbol, price, timestamp
+		SELECT id, symbol_id, symbol, price, timestamp, ts_source
 		FROM snapshots
-		WHERE symbol = $1 AND timestamp >= $2 AND timestamp <= $3
-		ORDER BY timestamp DESC
+		WHERE symbol = $1
+			AND ($2::timestamptz IS NULL OR (timestamp, id) < ($2, $3::bigint))
+		ORDER BY timestamp DESC, id DESC
 		LIMIT $4
 	`
 
-	rows, err := r.db.Pool.Query(ctx, query, symbolName, from, to, limit)
+	rows, err := r.db.Pool.Query(ctx, query, symbolName, beforeTs, beforeID, limit)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get history between: %w", err)
+		return nil, fmt.Errorf("failed to get history: %w", err)
+	}
+	snapshots, err := scanSnapshots(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	var to *time.Time
+	if before != nil {
+		to = &before.Timestamp
+	}
+	compressed, err := r.chunkHistory(ctx, symbolName, nil, to, before, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return mergeNewest(snapshots, compressed, limit), nil
+}
+
+// EachBetween streams a symbol's snapshots in [from, to), oldest first.
+// Where the range has compressed chunks, it is read a day at a time with
+// stored rows merged in.
+func (r *SnapshotRepository) EachBetween(ctx context.Context, symbolName string, from, to time.Time, fn func(*domain.PriceSnapshot) error) error {
+	start, end, ok, err := r.compressedSpan(ctx, symbolName, from, to)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return r.eachRowBetween(ctx, symbolName, from, to, fn)
+	}
+
+	// The span covers end itself
+	end = end.Add(time.Microsecond)
+	if start.Before(from) {
+		start = from
+	}
+	if end.After(to) {
+		end = to
+	}
+
+	if err := r.eachRowBetween(ctx, symbolName, from, start, fn); err != nil {
+		return err
+	}
+	for windowStart := start; windowStart.Before(end); windowStart = windowStart.Add(chunkScanWindow) {
+		windowEnd := windowStart.Add(chunkScanWindow)
+		if windowEnd.After(end) {
+			windowEnd = end
+		}
+
+		snapshots, err := r.mergedBetween(ctx, symbolName, windowStart, windowEnd)
+		if err != nil {
+			return err
+		}
+		for _, s := range snapshots {
+			if err := fn(s); err != nil {
+				return err
+			}
+		}
+	}
+	return r.eachRowBetween(ctx, symbolName, end, to, fn)
+}
+
+// eachRowBetween streams a symbol's stored rows in [from, to), oldest first
+func (r *SnapshotRepository) eachRowBetween(ctx context.Context, symbolName string, from, to time.Time, fn func(*domain.PriceSnapshot) error) error {
+	if !from.Before(to) {
+		return nil
+	}
+
+	query := `
+		SELECT id, symbol_id, symbol, price, timestamp, ts_source
+		FROM snapshots
+		WHERE symbol = $1 AND timestamp >= $2 AND timestamp < $3
+		ORDER BY timestamp
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, symbolName, from, to)
+	if err != nil {
+		return fmt.Errorf("failed to scan snapshots between: %w", err)
 	}
 	defer rows.Close()
 
-	var snapshots []*domain.PriceSnapshot
 	for rows.Next() {
 		var s domain.PriceSnapshot
 		var priceStr string
 
-		if err := rows.Scan(&s.ID, &s.SymbolID, &s.Symbol, &priceStr, &s.Timestamp); err != nil {
-			return nil, fmt.Errorf("failed to scan snapshot: %w", err)
+		if err := rows.Scan(&s.ID, &s.SymbolID, &s.Symbol, &priceStr, &s.Timestamp, &s.TimestampSource); err != nil {
+			return fmt.Errorf("failed to scan snapshot: %w", err)
 		}
 
 		s.Price, err = decimal.NewFromString(priceStr)
+		if err != nil {
+			return fmt.Errorf("failed to parse price: %w", err)
+		}
+
+		if err := fn(&s); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating snapshots: %w", err)
+	}
+
+	return nil
+}
+
+// GetHistoryBetween returns snapshots within a time range, newest first,
+// continuing after before when it is set
+func (r *SnapshotRepository) GetHistoryBetween(ctx context.Context, symbolName string, from, to time.Time, before *domain.HistoryCursor, limit int) ([]*domain.PriceSnapshot, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+	if limit > domain.MaxHistoryLimit {
+		limit = domain.MaxHistoryLimit
+	}
+
+	query := `
+		SELECT id, symbol_id, symbol, price, timestamp, ts_source
+		FROM snapshots
+		WHERE symbol = $1 AND timestamp >= $2 AND timestamp <= $3
+			AND ($4::timestamptz IS NULL OR (timestamp, id) < ($4, $5::bigint))
+		ORDER BY timestamp DESC, id DESC
+		LIMIT $6
+	`
+
+	beforeTs, beforeID := cursorArgs(before)
+	rows, err := r.db.Pool.Query(ctx, query, symbolName, from, to, beforeTs, beforeID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get history between: %w", err)
+	}
+	snapshots, err := scanSnapshots(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	if before != nil && before.Timestamp.Before(to) {
+		to = before.Timestamp
+	}
+	compressed, err := r.chunkHistory(ctx, symbolName, &from, &to, before, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return mergeNewest(snapshots, compressed, limit), nil
+}
+
+// cursorArgs returns the query arguments of a history cursor, both NULL
+// when there is none
+func cursorArgs(before *domain.HistoryCursor) (*time.Time, *int64) {
+	if before == nil {
+		return nil, nil
+	}
+	return &before.Timestamp, &before.ID
+}
+
+// GetPriceChanges returns the price movement of each active symbol between
+// its first snapshot since the given time and its latest snapshot
+func (r *SnapshotRepository) GetPriceChanges(ctx context.Context, since time.Time) ([]*domain.PriceChange, error) {
+	query := `
+		-- name: price_changes
+		WITH first AS (
+			SELECT DISTINCT ON (symbol) symbol, price, timestamp
+			FROM snapshots
+			WHERE timestamp >= $1
+			ORDER BY symbol, timestamp ASC
+		), last AS (
+			SELECT DISTINCT ON (symbol) symbol, price, timestamp
+			FROM snapshots
+			WHERE timestamp >= $1
+			ORDER BY symbol, timestamp DESC
+		)
+		SELECT first.symbol, first.price, last.price, first.timestamp, last.timestamp
+		FROM first
+		JOIN last ON last.symbol = first.symbol
+		JOIN symbols ON symbols.name = first.symbol AND symbols.active
+		ORDER BY first.symbol
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get price changes: %w", err)
+	}
+	defer rows.Close()
+
+	var changes []*domain.PriceChange
+	for rows.Next() {
+		var symbol, openStr, closeStr string
+		var from, to time.Time
+
+		if err := rows.Scan(&symbol, &openStr, &closeStr, &from, &to); err != nil {
+			return nil, fmt.Errorf("failed to scan price change: %w", err)
+		}
+
+		open, err := decimal.NewFromString(openStr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse price: %w", err)
+		}
+		closePrice, err := decimal.NewFromString(closeStr)
 		if err != nil {
 			return nil, fmt.Errorf("failed to parse price: %w", err)
 		}
 
-		snapshots = append(snapshots, &s)
+		changes = append(changes, domain.NewPriceChange(symbol, open, closePrice, from, to))
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating snapshots: %w", err)
+		return nil, fmt.Errorf("error iterating price changes: %w", err)
 	}
 
-	return snapshots, nil
+	return changes, nil
 }
 
 // Count returns total number of snapshots
@@ -266,7 +641,12 @@ func (r *SnapshotRepository) Count(ctx context.Context) (int64, error) {
 		return 0, fmt.Errorf("failed to count snapshots: %w", err)
 	}
 
-	return count, nil
+	compressed, err := r.countChunked(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	return count + compressed, nil
 }
 
 // CountBySymbol returns number of snapshots for a symbol
@@ -278,7 +658,43 @@ func (r *SnapshotRepository) CountBySymbol(ctx context.Context, symbolName strin
 		return 0, fmt.Errorf("failed to count snapshots by symbol: %w", err)
 	}
 
-	return count, nil
+	compressed, err := r.countChunked(ctx, &symbolName)
+	if err != nil {
+		return 0, err
+	}
+
+	return count + compressed, nil
+}
+
+// GetHistorySummary returns the number of snapshots stored for a symbol,
+// raw and compressed, and the timestamps of its oldest and newest
+func (r *SnapshotRepository) GetHistorySummary(ctx context.Context, symbolName string) (*domain.HistorySummary, error) {
+	query := `SELECT COUNT(*), MIN(timestamp), MAX(timestamp) FROM snapshots WHERE symbol = $1`
+
+	summary := &domain.HistorySummary{}
+	if err := r.db.Pool.QueryRow(ctx, query, symbolName).Scan(&summary.TotalCount, &summary.Oldest, &summary.Newest); err != nil {
+		return nil, fmt.Errorf("failed to summarize history: %w", err)
+	}
+
+	compressed, err := r.summarizeChunks(ctx, symbolName)
+	if err != nil {
+		return nil, err
+	}
+
+	summary.TotalCount += compressed.TotalCount
+	if compressed.Oldest != nil && (summary.Oldest == nil || compressed.Oldest.Before(*summary.Oldest)) {
+		summary.Oldest = compressed.Oldest
+	}
+	if compressed.Newest != nil && (summary.Newest == nil || compressed.Newest.After(*summary.Newest)) {
+		summary.Newest = compressed.Newest
+	}
+	for _, t := range []*time.Time{summary.Oldest, summary.Newest} {
+		if t != nil {
+			*t = t.UTC()
+		}
+	}
+
+	return summary, nil
 }
 
 // Prune removes snapshots older than the given time
@@ -290,8 +706,286 @@ func (r *SnapshotRepository) Prune(ctx context.Context, olderThan time.Time) (in
 		return 0, fmt.Errorf("failed to prune snapshots: %w", err)
 	}
 
+	compressed, err := r.pruneChunks(ctx, nil, olderThan)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected() + compressed, nil
+}
+
+// PruneBySymbol removes a single symbol's snapshots older than the given time
+func (r *SnapshotRepository) PruneBySymbol(ctx context.Context, symbolName string, olderThan time.Time) (int64, error) {
+	query := `DELETE FROM snapshots WHERE symbol = $1 AND timestamp < $2`
+
+	result, err := r.db.Pool.Exec(ctx, query, symbolName, olderThan)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune snapshots for %s: %w", symbolName, err)
+	}
+
+	compressed, err := r.pruneChunks(ctx, &symbolName, olderThan)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected() + compressed, nil
+}
+
+// CountPerSymbol returns the number of snapshots of every symbol that has
+// any, compressed ones included, ordered by symbol
+func (r *SnapshotRepository) CountPerSymbol(ctx context.Context) ([]domain.SymbolSnapshotCount, error) {
+	query := `
+		SELECT symbol, SUM(count)::bigint
+		FROM (
+			SELECT symbol, COUNT(*) AS count FROM snapshots GROUP BY symbol
+			UNION ALL
+			SELECT symbol, SUM(count) AS count FROM snapshot_chunks GROUP BY symbol
+		) c
+		GROUP BY symbol
+		ORDER BY symbol
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count snapshots per symbol: %w", err)
+	}
+	defer rows.Close()
+
+	var counts []domain.SymbolSnapshotCount
+	for rows.Next() {
+		var c domain.SymbolSnapshotCount
+		if err := rows.Scan(&c.Symbol, &c.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan snapshot count: %w", err)
+		}
+		counts = append(counts, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating snapshot counts: %w", err)
+	}
+
+	return counts, nil
+}
+
+// GetStorageStats returns the total on-disk size of the snapshots and
+// snapshot_chunks tables, including indexes and TOAST, along with
+// per-symbol snapshot counts. Compressed snapshots count as recent only
+// when their whole chunk started at or after since.
+func (r *SnapshotRepository) GetStorageStats(ctx context.Context, since time.Time) (*domain.StorageStats, error) {
+	var stats domain.StorageStats
+
+	sizeQuery := `SELECT pg_total_relation_size('snapshots') + pg_total_relation_size('snapshot_chunks')`
+	if err := r.db.Pool.QueryRow(ctx, sizeQuery).Scan(&stats.TotalBytes); err != nil {
+		return nil, fmt.Errorf("failed to get snapshots table size: %w", err)
+	}
+
+	query := `
+		SELECT symbol, SUM(rows)::bigint, SUM(recent_rows)::bigint, MIN(oldest)
+		FROM (
+			SELECT symbol, COUNT(*) AS rows, COUNT(*) FILTER (WHERE timestamp >= $1) AS recent_rows, MIN(timestamp) AS oldest
+			FROM snapshots
+			GROUP BY symbol
+			UNION ALL
+			SELECT symbol, SUM(count), COALESCE(SUM(count) FILTER (WHERE start_ts >= $1), 0), MIN(start_ts)
+			FROM snapshot_chunks
+			GROUP BY symbol
+		) s
+		GROUP BY symbol
+		ORDER BY symbol
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get storage stats: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var s domain.SymbolStorage
+		if err := rows.Scan(&s.Symbol, &s.Rows, &s.RecentRows, &s.Oldest); err != nil {
+			return nil, fmt.Errorf("failed to scan storage stats: %w", err)
+		}
+		stats.Symbols = append(stats.Symbols, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating storage stats: %w", err)
+	}
+
+	return &stats, nil
+}
+
+// CountRange counts a symbol's snapshots in [from, to) as DeleteRange
+// would delete them
+func (r *SnapshotRepository) CountRange(ctx context.Context, symbolName string, from, to time.Time) (*domain.SnapshotRangeDeletion, error) {
+	query := `SELECT COUNT(*) FROM snapshots WHERE symbol = $1 AND timestamp >= $2 AND timestamp < $3`
+
+	var count int64
+	if err := r.db.Pool.QueryRow(ctx, query, symbolName, from, to).Scan(&count); err != nil {
+		return nil, fmt.Errorf("failed to count snapshots in range: %w", err)
+	}
+
+	inside, straddling, err := r.rangeChunks(ctx, symbolName, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.SnapshotRangeDeletion{
+		Symbol:         symbolName,
+		From:           from,
+		To:             to,
+		DryRun:         true,
+		Deleted:        count + inside,
+		KeptCompressed: straddling,
+	}, nil
+}
+
+// DeleteRange removes a symbol's snapshots in [from, to). Rows are deleted
+// batchSize at a time, each batch in its own statement, so a large range
+// does not hold locks or bloat one transaction.
+func (r *SnapshotRepository) DeleteRange(ctx context.Context, symbolName string, from, to time.Time, batchSize int) (*domain.SnapshotRangeDeletion, error) {
+	query := `
+		DELETE FROM snapshots
+		WHERE id IN (
+			SELECT id FROM snapshots
+			WHERE symbol = $1 AND timestamp >= $2 AND timestamp < $3
+			LIMIT $4
+		)
+	`
+
+	var deleted int64
+	for {
+		result, err := r.db.Pool.Exec(ctx, query, symbolName, from, to, batchSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to delete snapshots in range after %d rows: %w", deleted, err)
+		}
+		deleted += result.RowsAffected()
+		if result.RowsAffected() < int64(batchSize) {
+			break
+		}
+	}
+
+	compressed, err := r.deleteRangeChunks(ctx, symbolName, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	_, straddling, err := r.rangeChunks(ctx, symbolName, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.SnapshotRangeDeletion{
+		Symbol:         symbolName,
+		From:           from,
+		To:             to,
+		Deleted:        deleted + compressed,
+		KeptCompressed: straddling,
+	}, nil
+}
+
+// GetPollRun returns the snapshots a poll stored, ordered by symbol
+func (r *SnapshotRepository) GetPollRun(ctx context.Context, runID string) ([]*domain.PriceSnapshot, error) {
+	query := `
+		SELECT id, symbol_id, symbol, price, timestamp, ts_source, fetch_latency_ms, COALESCE(exchange, '')
+		FROM snapshots
+		WHERE poll_run_id = $1
+		ORDER BY symbol
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, runID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get poll run: %w", err)
+	}
+	defer rows.Close()
+
+	var snapshots []*domain.PriceSnapshot
+	for rows.Next() {
+		snapshot := domain.PriceSnapshot{PollRunID: runID}
+		var priceStr string
+		if err := rows.Scan(
+			&snapshot.ID,
+			&snapshot.SymbolID,
+			&snapshot.Symbol,
+			&priceStr,
+			&snapshot.Timestamp,
+			&snapshot.TimestampSource,
+			&snapshot.FetchLatencyMs,
+			&snapshot.Exchange,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan snapshot: %w", err)
+		}
+
+		snapshot.Price, err = decimal.NewFromString(priceStr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse price: %w", err)
+		}
+		snapshots = append(snapshots, &snapshot)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating poll run: %w", err)
+	}
+
+	return snapshots, nil
+}
+
+// DeletePollRun removes the snapshots a poll stored
+func (r *SnapshotRepository) DeletePollRun(ctx context.Context, runID string) (int64, error) {
+	query := `DELETE FROM snapshots WHERE poll_run_id = $1`
+
+	result, err := r.db.Pool.Exec(ctx, query, runID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete poll run %s: %w", runID, err)
+	}
+
 	return result.RowsAffected(), nil
 }
 
+// timestampSource returns the snapshot's timestamp source, defaulting to poll
+func timestampSource(snapshot *domain.PriceSnapshot) domain.TimestampSource {
+	if snapshot.TimestampSource == "" {
+		return domain.TimestampSourcePoll
+	}
+	return snapshot.TimestampSource
+}
+
+// pollRunID returns the snapshot's poll run ID, or nil for snapshots that
+// were not polled
+func pollRunID(snapshot *domain.PriceSnapshot) *string {
+	if snapshot.PollRunID == "" {
+		return nil
+	}
+	return &snapshot.PollRunID
+}
+
+// exchangeName returns the exchange the snapshot was polled from, or nil
+// for snapshots that were not polled
+func exchangeName(snapshot *domain.PriceSnapshot) *string {
+	if snapshot.Exchange == "" {
+		return nil
+	}
+	return &snapshot.Exchange
+}
+
+// ingestMode returns how the snapshot was ingested, or nil for snapshots
+// that were neither polled nor streamed
+func ingestMode(snapshot *domain.PriceSnapshot) *string {
+	if snapshot.IngestMode == "" {
+		return nil
+	}
+	mode := string(snapshot.IngestMode)
+	return &mode
+}
+
+// pipelineVersion returns the version of the service that built the
+// snapshot, or nil when it was not recorded
+func pipelineVersion(snapshot *domain.PriceSnapshot) *string {
+	if snapshot.PipelineVersion == "" {
+		return nil
+	}
+	return &snapshot.PipelineVersion
+}
+
 // Ensure SnapshotRepository implements ports.SnapshotRepository
 var _ ports.SnapshotRepository = (*SnapshotRepository)(nil)
@@ -0,0 +1,162 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+)
+
+// SymbolEventRepository implements the ports.SymbolEventRepository interface
+type SymbolEventRepository struct {
+	db *DB
+}
+
+// NewSymbolEventRepository creates a new PostgreSQL symbol event repository
+func NewSymbolEventRepository(db *DB) ports.SymbolEventRepository {
+	return &SymbolEventRepository{db: db}
+}
+
+// Create records a symbol change
+func (r *SymbolEventRepository) Create(ctx context.Context, event *domain.SymbolEvent) error {
+	query := `
+		INSERT INTO symbol_events (symbol, type, details, created_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id
+	`
+
+	err := r.db.Pool.QueryRow(ctx, query,
+		event.Symbol,
+		event.Type,
+		event.Details,
+		event.CreatedAt,
+	).Scan(&event.ID)
+
+	if err != nil {
+		return fmt.Errorf("failed to create symbol event: %w", err)
+	}
+
+	return nil
+}
+
+// ListBySymbol returns a symbol's most recent events, newest first
+func (r *SymbolEventRepository) ListBySymbol(ctx context.Context, symbolName string, limit int) ([]*domain.SymbolEvent, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+	if limit > 1000 {
+		limit = 1000
+	}
+
+	query := `
+		SELECT id, symbol, type, details, created_at
+		FROM symbol_events
+		WHERE symbol = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, symbolName, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list symbol events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []*domain.SymbolEvent
+	for rows.Next() {
+		var e domain.SymbolEvent
+		if err := rows.Scan(&e.ID, &e.Symbol, &e.Type, &e.Details, &e.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan symbol event: %w", err)
+		}
+		events = append(events, &e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating symbol events: %w", err)
+	}
+
+	return events, nil
+}
+
+// ListAfter returns up to limit events of the given types recorded after
+// afterID, oldest first
+func (r *SymbolEventRepository) ListAfter(ctx context.Context, afterID int64, types []domain.SymbolEventType, limit int) ([]*domain.SymbolEvent, error) {
+	names := make([]string, len(types))
+	for i, t := range types {
+		names[i] = string(t)
+	}
+
+	query := `
+		SELECT id, symbol, type, details, created_at
+		FROM symbol_events
+		WHERE id > $1 AND type = ANY($2)
+		ORDER BY id
+		LIMIT $3
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, afterID, names, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list symbol events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []*domain.SymbolEvent
+	for rows.Next() {
+		var e domain.SymbolEvent
+		if err := rows.Scan(&e.ID, &e.Symbol, &e.Type, &e.Details, &e.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan symbol event: %w", err)
+		}
+		events = append(events, &e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating symbol events: %w", err)
+	}
+
+	return events, nil
+}
+
+// DeliveryCursor returns the ID of the last event delivered to subscriber,
+// starting a new subscriber at the latest event
+func (r *SymbolEventRepository) DeliveryCursor(ctx context.Context, subscriber string) (int64, error) {
+	query := `
+		WITH created AS (
+			INSERT INTO symbol_event_cursors (subscriber, last_event_id)
+			SELECT $1, COALESCE(MAX(id), 0) FROM symbol_events
+			ON CONFLICT (subscriber) DO NOTHING
+			RETURNING last_event_id
+		)
+		SELECT last_event_id FROM created
+		UNION ALL
+		SELECT last_event_id FROM symbol_event_cursors WHERE subscriber = $1
+		LIMIT 1
+	`
+
+	var id int64
+	if err := r.db.Pool.QueryRow(ctx, query, subscriber).Scan(&id); err != nil {
+		return 0, fmt.Errorf("failed to get symbol event cursor: %w", err)
+	}
+
+	return id, nil
+}
+
+// SetDeliveryCursor records eventID as delivered to subscriber
+func (r *SymbolEventRepository) SetDeliveryCursor(ctx context.Context, subscriber string, eventID int64) error {
+	query := `
+		INSERT INTO symbol_event_cursors (subscriber, last_event_id, updated_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (subscriber) DO UPDATE
+		SET last_event_id = GREATEST(symbol_event_cursors.last_event_id, EXCLUDED.last_event_id),
+			updated_at = NOW()
+	`
+
+	if _, err := r.db.Pool.Exec(ctx, query, subscriber, eventID); err != nil {
+		return fmt.Errorf("failed to set symbol event cursor: %w", err)
+	}
+
+	return nil
+}
+
+// Ensure SymbolEventRepository implements ports.SymbolEventRepository
+var _ ports.SymbolEventRepository = (*SymbolEventRepository)(nil)
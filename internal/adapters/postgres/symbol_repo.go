@@ -4,8 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
 
 	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
 	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
@@ -21,21 +23,31 @@ func NewSymbolRepository(db *DB) ports.SymbolRepository {
 	return &SymbolRepository{db: db}
 }
 
-// Create adds a new symbol to track
+// Create adds a new symbol to track. A soft deleted symbol of the same
+// name is revived with its ID, so its history carries on.
 func (r *SymbolRepository) Create(ctx context.Context, symbol *domain.Symbol) error {
 	query := `
-		INSERT INTO symbols (name, active, created_at, updated_at)
-		VALUES ($1, $2, $3, $4)
+		INSERT INTO symbols (name, exchange, active, tags, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (name) DO UPDATE
+		SET exchange = EXCLUDED.exchange, active = EXCLUDED.active, tags = EXCLUDED.tags,
+			updated_at = EXCLUDED.updated_at, deleted_at = NULL
+		WHERE symbols.deleted_at IS NOT NULL
 		RETURNING id
 	`
 
 	err := r.db.Pool.QueryRow(ctx, query,
 		symbol.Name,
+		symbol.Exchange,
 		symbol.Active,
+		tagsOrEmpty(symbol.Tags),
 		symbol.CreatedAt,
 		symbol.UpdatedAt,
 	).Scan(&symbol.ID)
 
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.ErrSymbolExists
+	}
 	if err != nil {
 		return fmt.Errorf("failed to create symbol: %w", err)
 	}
@@ -46,20 +58,12 @@ func (r *SymbolRepository) Create(ctx context.Context, symbol *domain.Symbol) er
 // GetByName retrieves a symbol by its name
 func (r *SymbolRepository) GetByName(ctx context.Context, name string) (*domain.Symbol, error) {
 	query := `
-		SELECT id, name, active, created_at, updated_at
+		SELECT ` + symbolColumns + `
 		FROM symbols
-		WHERE name = $1
+		WHERE name = $1 AND deleted_at IS NULL
 	`
 
-	var symbol domain.Symbol
-	err := r.db.Pool.QueryRow(ctx, query, name).Scan(
-		&symbol.ID,
-		&symbol.Name,
-		&symbol.Active,
-		&symbol.CreatedAt,
-		&symbol.UpdatedAt,
-	)
-
+	symbol, err := scanSymbol(r.db.Pool.QueryRow(ctx, query, name))
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, domain.ErrSymbolNotFound
 	}
@@ -67,26 +71,18 @@ func (r *SymbolRepository) GetByName(ctx context.Context, name string) (*domain.
 		return nil, fmt.Errorf("failed to get symbol: %w", err)
 	}
 
-	return &symbol, nil
+	return symbol, nil
 }
 
 // GetByID retrieves a symbol by its ID
 func (r *SymbolRepository) GetByID(ctx context.Context, id int64) (*domain.Symbol, error) {
 	query := `
-		SELECT id, name, active, created_at, updated_at
+		SELECT ` + symbolColumns + `
 		FROM symbols
-		WHERE id = $1
+		WHERE id = $1 AND deleted_at IS NULL
 	`
 
-	var symbol domain.Symbol
-	err := r.db.Pool.QueryRow(ctx, query, id).Scan(
-		&symbol.ID,
-		&symbol.Name,
-		&symbol.Active,
-		&symbol.CreatedAt,
-		&symbol.UpdatedAt,
-	)
-
+	symbol, err := scanSymbol(r.db.Pool.QueryRow(ctx, query, id))
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, domain.ErrSymbolNotFound
 	}
@@ -94,13 +90,44 @@ func (r *SymbolRepository) GetByID(ctx context.Context, id int64) (*domain.Symbo
 		return nil, fmt.Errorf("failed to get symbol: %w", err)
 	}
 
-	return &symbol, nil
+	return symbol, nil
 }
 
 // List returns all tracked symbols
 func (r *SymbolRepository) List(ctx context.Context) ([]*domain.Symbol, error) {
 	query := `
-		SELECT id, name, active, created_at, updated_at
+		SELECT ` + symbolColumns + `
+		FROM symbols
+		WHERE deleted_at IS NULL
+		ORDER BY name
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list symbols: %w", err)
+	}
+	defer rows.Close()
+
+	var symbols []*domain.Symbol
+	for rows.Next() {
+		s, err := scanSymbol(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan symbol: %w", err)
+		}
+		symbols = append(symbols, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating symbols: %w", err)
+	}
+
+	return symbols, nil
+}
+
+// ListIncludingDeleted returns tracked and soft deleted symbols
+func (r *SymbolRepository) ListIncludingDeleted(ctx context.Context) ([]*domain.Symbol, error) {
+	query := `
+		SELECT ` + symbolColumns + `
 		FROM symbols
 		ORDER BY name
 	`
@@ -113,11 +140,11 @@ func (r *SymbolRepository) List(ctx context.Context) ([]*domain.Symbol, error) {
 
 	var symbols []*domain.Symbol
 	for rows.Next() {
-		var s domain.Symbol
-		if err := rows.Scan(&s.ID, &s.Name, &s.Active, &s.CreatedAt, &s.UpdatedAt); err != nil {
+		s, err := scanSymbol(rows)
+		if err != nil {
 			return nil, fmt.Errorf("failed to scan symbol: %w", err)
 		}
-		symbols = append(symbols, &s)
+		symbols = append(symbols, s)
 	}
 
 	if err := rows.Err(); err != nil {
@@ -130,9 +157,9 @@ func (r *SymbolRepository) List(ctx context.Context) ([]*domain.Symbol, error) {
 // ListActive returns only active symbols
 func (r *SymbolRepository) ListActive(ctx context.Context) ([]*domain.Symbol, error) {
 	query := `
-		SELECT id, name, active, created_at, updated_at
+		SELECT ` + symbolColumns + `
 		FROM symbols
-		WHERE active = TRUE
+		WHERE active = TRUE AND deleted_at IS NULL
 		ORDER BY name
 	`
 
@@ -144,11 +171,81 @@ func (r *SymbolRepository) ListActive(ctx context.Context) ([]*domain.Symbol, er
 
 	var symbols []*domain.Symbol
 	for rows.Next() {
-		var s domain.Symbol
-		if err := rows.Scan(&s.ID, &s.Name, &s.Active, &s.CreatedAt, &s.UpdatedAt); err != nil {
+		s, err := scanSymbol(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan symbol: %w", err)
+		}
+		symbols = append(symbols, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating symbols: %w", err)
+	}
+
+	return symbols, nil
+}
+
+// ListByTag returns symbols carrying the given tag
+func (r *SymbolRepository) ListByTag(ctx context.Context, tag string) ([]*domain.Symbol, error) {
+	query := `
+		SELECT ` + symbolColumns + `
+		FROM symbols
+		WHERE tags @> ARRAY[$1::text] AND deleted_at IS NULL
+		ORDER BY name
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, tag)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list symbols by tag: %w", err)
+	}
+	defer rows.Close()
+
+	var symbols []*domain.Symbol
+	for rows.Next() {
+		s, err := scanSymbol(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan symbol: %w", err)
+		}
+		symbols = append(symbols, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating symbols: %w", err)
+	}
+
+	return symbols, nil
+}
+
+// ListPage returns a page of symbols ordered by name, optionally carrying a tag
+func (r *SymbolRepository) ListPage(ctx context.Context, tag string, after *domain.SymbolCursor, limit int) ([]*domain.Symbol, error) {
+	var afterName *string
+	if after != nil {
+		afterName = &after.Name
+	}
+
+	query := `
+		SELECT ` + symbolColumns + `
+		FROM symbols
+		WHERE ($1 = '' OR tags @> ARRAY[$1::text])
+			AND ($2::text IS NULL OR name > $2)
+			AND deleted_at IS NULL
+		ORDER BY name
+		LIMIT $3
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, tag, afterName, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list symbol page: %w", err)
+	}
+	defer rows.Close()
+
+	var symbols []*domain.Symbol
+	for rows.Next() {
+		s, err := scanSymbol(rows)
+		if err != nil {
 			return nil, fmt.Errorf("failed to scan symbol: %w", err)
 		}
-		symbols = append(symbols, &s)
+		symbols = append(symbols, s)
 	}
 
 	if err := rows.Err(); err != nil {
@@ -158,7 +255,29 @@ func (r *SymbolRepository) ListActive(ctx context.Context) ([]*domain.Symbol, er
 	return symbols, nil
 }
 
-// Delete removes a symbol by name
+// SoftDelete removes a tracked symbol but keeps its snapshots. It is
+// deactivated, so joins on active symbols leave it out too.
+func (r *SymbolRepository) SoftDelete(ctx context.Context, name string) error {
+	query := `
+		UPDATE symbols
+		SET active = FALSE, deleted_at = NOW(), updated_at = NOW()
+		WHERE name = $1 AND deleted_at IS NULL
+	`
+
+	result, err := r.db.Pool.Exec(ctx, query, name)
+	if err != nil {
+		return fmt.Errorf("failed to soft delete symbol: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrSymbolNotFound
+	}
+
+	return nil
+}
+
+// Delete removes a symbol by name, tracked or soft deleted; its snapshots
+// are removed by cascade
 func (r *SymbolRepository) Delete(ctx context.Context, name string) error {
 	query := `DELETE FROM symbols WHERE name = $1`
 
@@ -178,11 +297,11 @@ func (r *SymbolRepository) Delete(ctx context.Context, name string) error {
 func (r *SymbolRepository) Update(ctx context.Context, symbol *domain.Symbol) error {
 	query := `
 		UPDATE symbols
-		SET name = $1, active = $2, updated_at = NOW()
-		WHERE id = $3
+		SET name = $1, exchange = $2, active = $3, tags = $4, updated_at = NOW()
+		WHERE id = $5 AND deleted_at IS NULL
 	`
 
-	result, err := r.db.Pool.Exec(ctx, query, symbol.Name, symbol.Active, symbol.ID)
+	result, err := r.db.Pool.Exec(ctx, query, symbol.Name, symbol.Exchange, symbol.Active, tagsOrEmpty(symbol.Tags), symbol.ID)
 	if err != nil {
 		return fmt.Errorf("failed to update symbol: %w", err)
 	}
@@ -194,9 +313,88 @@ func (r *SymbolRepository) Update(ctx context.Context, symbol *domain.Symbol) er
 	return nil
 }
 
+// SetActive sets the active flag of the named tracked symbols in one
+// transaction and returns the names whose flag changed. The symbols are
+// locked first, so nothing is changed when any of them is not tracked.
+func (r *SymbolRepository) SetActive(ctx context.Context, names []string, active bool) ([]string, error) {
+	var changed []string
+	err := r.db.WithTx(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		changed = nil
+
+		var found int
+		err := tx.QueryRow(ctx, `
+			SELECT COUNT(*) FROM (
+				SELECT id FROM symbols
+				WHERE name = ANY($1) AND deleted_at IS NULL
+				FOR UPDATE
+			) locked
+		`, names).Scan(&found)
+		if err != nil {
+			return fmt.Errorf("failed to lock symbols: %w", err)
+		}
+		unique := make(map[string]bool, len(names))
+		for _, name := range names {
+			unique[name] = true
+		}
+		if found < len(unique) {
+			return domain.ErrSymbolNotFound
+		}
+
+		rows, err := tx.Query(ctx, `
+			UPDATE symbols
+			SET active = $2, updated_at = NOW()
+			WHERE name = ANY($1) AND deleted_at IS NULL AND active <> $2
+			RETURNING name
+		`, names, active)
+		if err != nil {
+			return fmt.Errorf("failed to update symbols: %w", err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var name string
+			if err := rows.Scan(&name); err != nil {
+				return fmt.Errorf("failed to scan symbol name: %w", err)
+			}
+			changed = append(changed, name)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return changed, nil
+}
+
+// UpdateMetadata stores a symbol's coin metadata
+func (r *SymbolRepository) UpdateMetadata(ctx context.Context, name string, metadata *domain.CoinMetadata) error {
+	query := `
+		UPDATE symbols
+		SET display_name = $1, logo_url = $2, market_cap = $3, metadata_updated_at = $4
+		WHERE name = $5 AND deleted_at IS NULL
+	`
+
+	result, err := r.db.Pool.Exec(ctx, query,
+		metadata.Name,
+		metadata.LogoURL,
+		metadata.MarketCap,
+		metadata.UpdatedAt,
+		name,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update symbol metadata: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrSymbolNotFound
+	}
+
+	return nil
+}
+
 // Count returns total number of symbols
 func (r *SymbolRepository) Count(ctx context.Context) (int, error) {
-	query := `SELECT COUNT(*) FROM symbols`
+	query := `SELECT COUNT(*) FROM symbols WHERE deleted_at IS NULL`
 
 	var count int
 	if err := r.db.Pool.QueryRow(ctx, query).Scan(&count); err != nil {
@@ -208,7 +406,7 @@ func (r *SymbolRepository) Count(ctx context.Context) (int, error) {
 
 // CountActive returns number of active symbols
 func (r *SymbolRepository) CountActive(ctx context.Context) (int, error) {
-	query := `SELECT COUNT(*) FROM symbols WHERE active = TRUE`
+	query := `SELECT COUNT(*) FROM symbols WHERE active = TRUE AND deleted_at IS NULL`
 
 	var count int
 	if err := r.db.Pool.QueryRow(ctx, query).Scan(&count); err != nil {
@@ -220,6 +418,18 @@ func (r *SymbolRepository) CountActive(ctx context.Context) (int, error) {
 
 // Exists checks if a symbol exists
 func (r *SymbolRepository) Exists(ctx context.Context, name string) (bool, error) {
+	query := `SELECT EXISTS(SELECT 1 FROM symbols WHERE name = $1 AND deleted_at IS NULL)`
+
+	var exists bool
+	if err := r.db.Pool.QueryRow(ctx, query, name).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to check symbol existence: %w", err)
+	}
+
+	return exists, nil
+}
+
+// ExistsIncludingDeleted checks if a symbol is tracked or soft deleted
+func (r *SymbolRepository) ExistsIncludingDeleted(ctx context.Context, name string) (bool, error) {
 	query := `SELECT EXISTS(SELECT 1 FROM symbols WHERE name = $1)`
 
 	var exists bool
@@ -230,5 +440,50 @@ func (r *SymbolRepository) Exists(ctx context.Context, name string) (bool, error
 	return exists, nil
 }
 
+// symbolColumns are the columns read by scanSymbol, in order
+const symbolColumns = `id, name, exchange, active, tags, display_name, logo_url, market_cap, metadata_updated_at, created_at, updated_at, deleted_at`
+
+// scanSymbol reads a row of symbolColumns. Metadata is left nil until the
+// symbol has been enriched.
+func scanSymbol(row pgx.Row) (*domain.Symbol, error) {
+	var (
+		s                           domain.Symbol
+		name, logoURL, marketCapStr *string
+		updatedAt                   *time.Time
+	)
+	err := row.Scan(&s.ID, &s.Name, &s.Exchange, &s.Active, &s.Tags, &name, &logoURL, &marketCapStr, &updatedAt, &s.CreatedAt, &s.UpdatedAt, &s.DeletedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if updatedAt == nil {
+		return &s, nil
+	}
+
+	s.Metadata = &domain.CoinMetadata{UpdatedAt: *updatedAt}
+	if name != nil {
+		s.Metadata.Name = *name
+	}
+	if logoURL != nil {
+		s.Metadata.LogoURL = *logoURL
+	}
+	if marketCapStr != nil {
+		s.Metadata.MarketCap, err = decimal.NewFromString(*marketCapStr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse market cap: %w", err)
+		}
+	}
+
+	return &s, nil
+}
+
+// tagsOrEmpty stores nil tags as an empty array rather than NULL
+func tagsOrEmpty(tags []string) []string {
+	if tags == nil {
+		return []string{}
+	}
+	return tags
+}
+
 // Ensure SymbolRepository implements ports.SymbolRepository
 var _ ports.SymbolRepository = (*SymbolRepository)(nil)
@@ -0,0 +1,119 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+)
+
+// TickerStatsRepository implements the ports.TickerStatsRepository interface
+type TickerStatsRepository struct {
+	db *DB
+}
+
+// NewTickerStatsRepository creates a new PostgreSQL ticker stats repository
+func NewTickerStatsRepository(db *DB) ports.TickerStatsRepository {
+	return &TickerStatsRepository{db: db}
+}
+
+// CreateBatch stores multiple ticker stats atomically
+func (r *TickerStatsRepository) CreateBatch(ctx context.Context, stats []*domain.TickerStats) error {
+	if len(stats) == 0 {
+		return nil
+	}
+
+	query := `
+		INSERT INTO ticker_stats (symbol_id, symbol, high, low, volume, price_change_percent, timestamp)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id
+	`
+
+	return r.db.WithTx(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		for _, s := range stats {
+			err := tx.QueryRow(ctx, query,
+				s.SymbolID,
+				s.Symbol,
+				s.High,
+				s.Low,
+				s.Volume,
+				s.PriceChangePercent,
+				s.Timestamp,
+			).Scan(&s.ID)
+
+			if err != nil {
+				return fmt.Errorf("failed to create ticker stats for %s: %w", s.Symbol, err)
+			}
+		}
+		return nil
+	})
+}
+
+// GetLatestBySymbol returns the most recent ticker stats for a symbol
+func (r *TickerStatsRepository) GetLatestBySymbol(ctx context.Context, symbolName string) (*domain.TickerStats, error) {
+	query := `
+		SELECT id, symbol_id, symbol, high, low, volume, price_change_percent, timestamp
+		FROM ticker_stats
+		WHERE symbol = $1
+		ORDER BY timestamp DESC
+		LIMIT 1
+	`
+
+	var stats domain.TickerStats
+	var high, low, volume, change string
+
+	err := r.db.Pool.QueryRow(ctx, query, symbolName).Scan(
+		&stats.ID,
+		&stats.SymbolID,
+		&stats.Symbol,
+		&high,
+		&low,
+		&volume,
+		&change,
+		&stats.Timestamp,
+	)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrTickerStatsNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest ticker stats: %w", err)
+	}
+
+	for _, f := range []struct {
+		dst *decimal.Decimal
+		src string
+	}{
+		{&stats.High, high},
+		{&stats.Low, low},
+		{&stats.Volume, volume},
+		{&stats.PriceChangePercent, change},
+	} {
+		if *f.dst, err = decimal.NewFromString(f.src); err != nil {
+			return nil, fmt.Errorf("failed to parse ticker stats: %w", err)
+		}
+	}
+
+	return &stats, nil
+}
+
+// Prune removes ticker stats older than the given time
+func (r *TickerStatsRepository) Prune(ctx context.Context, olderThan time.Time) (int64, error) {
+	query := `DELETE FROM ticker_stats WHERE timestamp < $1`
+
+	result, err := r.db.Pool.Exec(ctx, query, olderThan)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune ticker stats: %w", err)
+	}
+
+	return result.RowsAffected(), nil
+}
+
+// Ensure TickerStatsRepository implements ports.TickerStatsRepository
+var _ ports.TickerStatsRepository = (*TickerStatsRepository)(nil)
@@ -0,0 +1,95 @@
+package postgres
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+)
+
+// queryNamePrefix marks a query name comment at the top of a statement
+const queryNamePrefix = "-- name:"
+
+// maxUnnamedQueryLen bounds the statement text used to name a query that
+// carries no name comment
+const maxUnnamedQueryLen = 60
+
+// slowQueryTracer logs and counts statements that take longer than the
+// threshold. It implements pgx.QueryTracer.
+type slowQueryTracer struct {
+	threshold time.Duration
+	recorder  ports.SlowQueryRecorder
+	logger    *slog.Logger
+}
+
+// slowQueryKey carries the start of a traced query through its context
+type slowQueryKey struct{}
+
+type slowQueryStart struct {
+	sql   string
+	start time.Time
+}
+
+func newSlowQueryTracer(threshold time.Duration, recorder ports.SlowQueryRecorder, logger *slog.Logger) *slowQueryTracer {
+	return &slowQueryTracer{
+		threshold: threshold,
+		recorder:  recorder,
+		logger:    logger,
+	}
+}
+
+// TraceQueryStart records when the query started
+func (t *slowQueryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	return context.WithValue(ctx, slowQueryKey{}, slowQueryStart{sql: data.SQL, start: time.Now()})
+}
+
+// TraceQueryEnd logs the query when it exceeded the threshold
+func (t *slowQueryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	started, ok := ctx.Value(slowQueryKey{}).(slowQueryStart)
+	if !ok {
+		return
+	}
+
+	duration := time.Since(started.start)
+	if duration < t.threshold {
+		return
+	}
+
+	name := queryName(started.sql)
+	attrs := []any{
+		"query", name,
+		"duration", duration,
+		"rows", data.CommandTag.RowsAffected(),
+	}
+	if data.Err != nil {
+		attrs = append(attrs, "error", data.Err)
+	}
+	t.logger.Warn("slow query", attrs...)
+
+	if t.recorder != nil {
+		t.recorder.RecordSlowQuery(name)
+	}
+}
+
+// queryName returns the name given by a leading "-- name:" comment, or the
+// start of the statement with whitespace collapsed
+func queryName(sql string) string {
+	sql = strings.TrimSpace(sql)
+	if rest, ok := strings.CutPrefix(sql, queryNamePrefix); ok {
+		name, _, _ := strings.Cut(rest, "\n")
+		return strings.TrimSpace(name)
+	}
+
+	name := strings.Join(strings.Fields(sql), " ")
+	if len(name) > maxUnnamedQueryLen {
+		name = name[:maxUnnamedQueryLen]
+	}
+	return name
+}
+
+// Ensure slowQueryTracer implements pgx.QueryTracer
+var _ pgx.QueryTracer = (*slowQueryTracer)(nil)
@@ -0,0 +1,82 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/prxgr4mmer/price-snapshot-service/pkg/retry"
+)
+
+// SQLSTATE codes of transient conflicts that succeed when the transaction
+// is run again
+const (
+	sqlStateSerializationFailure = "40001"
+	sqlStateDeadlockDetected     = "40P01"
+)
+
+// TxFunc runs statements inside a transaction. It may be called more than
+// once when the transaction is retried, so it must not keep state across calls.
+type TxFunc func(ctx context.Context, tx pgx.Tx) error
+
+// WithTx runs fn in a transaction with the configured statement timeout,
+// committing when fn succeeds and rolling back otherwise. Serialization
+// failures and deadlocks are retried with backoff.
+func (db *DB) WithTx(ctx context.Context, fn TxFunc) error {
+	return db.WithTxOptions(ctx, pgx.TxOptions{}, fn)
+}
+
+// WithTxOptions is WithTx with explicit transaction options, e.g. a
+// serializable isolation level
+func (db *DB) WithTxOptions(ctx context.Context, opts pgx.TxOptions, fn TxFunc) error {
+	cfg := retry.DefaultConfig()
+	cfg.MaxRetries = db.config.TxMaxRetries
+
+	return retry.Do(ctx, cfg, func(ctx context.Context) error {
+		err := db.runTx(ctx, opts, fn)
+		if isTxConflict(err) {
+			db.logger.Debug("transaction conflict, will retry", "error", err)
+			return retry.NewRetryableError(err)
+		}
+		return err
+	})
+}
+
+// runTx runs a single transaction attempt
+func (db *DB) runTx(ctx context.Context, opts pgx.TxOptions, fn TxFunc) error {
+	tx, err := db.Pool.BeginTx(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if timeout := db.config.StatementTimeout; timeout > 0 {
+		ms := strconv.FormatInt(timeout.Milliseconds(), 10)
+		if _, err := tx.Exec(ctx, `SELECT set_config('statement_timeout', $1, true)`, ms); err != nil {
+			return fmt.Errorf("failed to set statement timeout: %w", err)
+		}
+	}
+
+	if err := fn(ctx, tx); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// isTxConflict reports whether err is a transient transaction conflict
+func isTxConflict(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+	return pgErr.Code == sqlStateSerializationFailure || pgErr.Code == sqlStateDeadlockDetected
+}
@@ -0,0 +1,88 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+)
+
+// UsageRepository implements the ports.UsageRepository interface
+type UsageRepository struct {
+	db *DB
+}
+
+// NewUsageRepository creates a new PostgreSQL API usage repository
+func NewUsageRepository(db *DB) ports.UsageRepository {
+	return &UsageRepository{db: db}
+}
+
+// Add adds counts to the stored counts of the same day, key and endpoint
+// in one statement
+func (r *UsageRepository) Add(ctx context.Context, counts []domain.UsageCount) error {
+	if len(counts) == 0 {
+		return nil
+	}
+
+	days := make([]time.Time, len(counts))
+	keys := make([]string, len(counts))
+	endpoints := make([]string, len(counts))
+	requests := make([]int64, len(counts))
+	for i, c := range counts {
+		days[i] = c.Day
+		keys[i] = c.Key
+		endpoints[i] = c.Endpoint
+		requests[i] = c.Requests
+	}
+
+	query := `
+		INSERT INTO api_usage (day, key, endpoint, requests)
+		SELECT * FROM unnest($1::date[], $2::text[], $3::text[], $4::bigint[])
+		ON CONFLICT (day, key, endpoint)
+		DO UPDATE SET requests = api_usage.requests + EXCLUDED.requests
+	`
+
+	if _, err := r.db.Pool.Exec(ctx, query, days, keys, endpoints, requests); err != nil {
+		return fmt.Errorf("failed to add usage counts: %w", err)
+	}
+
+	return nil
+}
+
+// List returns the counts of the days from through to, ordered by day, key
+// and endpoint
+func (r *UsageRepository) List(ctx context.Context, from, to time.Time) ([]domain.UsageCount, error) {
+	query := `
+		SELECT day, key, endpoint, requests
+		FROM api_usage
+		WHERE day BETWEEN $1::date AND $2::date
+		ORDER BY day, key, endpoint
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, domain.UsageDay(from), domain.UsageDay(to))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list usage counts: %w", err)
+	}
+	defer rows.Close()
+
+	var counts []domain.UsageCount
+	for rows.Next() {
+		var c domain.UsageCount
+		if err := rows.Scan(&c.Day, &c.Key, &c.Endpoint, &c.Requests); err != nil {
+			return nil, fmt.Errorf("failed to scan usage count: %w", err)
+		}
+		c.Day = c.Day.UTC()
+		counts = append(counts, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating usage counts: %w", err)
+	}
+
+	return counts, nil
+}
+
+// Ensure UsageRepository implements ports.UsageRepository
+var _ ports.UsageRepository = (*UsageRepository)(nil)
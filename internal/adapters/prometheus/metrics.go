@@ -0,0 +1,434 @@
+// Package prometheus renders operational metrics in the Prometheus text and
+// OpenMetrics exposition formats and generates alert rules for them.
+package prometheus
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+)
+
+// ContentType is the media type of the text exposition format
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// OpenMetricsContentType is the media type of the OpenMetrics format
+const OpenMetricsContentType = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+
+// Metric names exposed by the service. Alert rules refer to these, so
+// renaming one is a breaking change for deployed monitoring.
+const (
+	MetricTargetInfo             = "target_info"
+	MetricBuildInfo              = "price_snapshot_build_info"
+	MetricConfigInfo             = "price_snapshot_config_info"
+	MetricUptime                 = "price_snapshot_uptime_seconds"
+	MetricTrackedSymbols         = "price_snapshot_tracked_symbols"
+	MetricActiveSymbols          = "price_snapshot_active_symbols"
+	MetricSymbolLimit            = "price_snapshot_symbol_limit"
+	MetricSnapshots              = "price_snapshot_snapshots"
+	MetricLastPollTimestamp      = "price_snapshot_last_poll_timestamp_seconds"
+	MetricLastPollDuration       = "price_snapshot_last_poll_duration_seconds"
+	MetricPolls                  = "price_snapshot_polls_total"
+	MetricPrunedSnapshots        = "price_snapshot_pruned_snapshots_total"
+	MetricPartialPolls           = "price_snapshot_partial_polls_total"
+	MetricSnapshotWriteFailures  = "price_snapshot_snapshot_write_failures_total"
+	MetricDatabaseUp             = "price_snapshot_database_up"
+	MetricExchangeUp             = "price_snapshot_exchange_up"
+	MetricExchangeErrors         = "price_snapshot_exchange_errors_total"
+	MetricExchangeRetries        = "price_snapshot_exchange_retries_total"
+	MetricExchangeAttempts       = "price_snapshot_exchange_call_attempts"
+	MetricExchangeWeightLimit    = "price_snapshot_exchange_weight_limit"
+	MetricExchangeWeightUsed     = "price_snapshot_exchange_weight_used"
+	MetricExchangeBudgetWaits    = "price_snapshot_exchange_budget_waits_total"
+	MetricExchangeBudgetShed     = "price_snapshot_exchange_budget_shed_total"
+	MetricSlowQueries            = "price_snapshot_slow_queries_total"
+	MetricGroupPolls             = "price_snapshot_poller_group_polls_total"
+	MetricGroupLastPollTimestamp = "price_snapshot_poller_group_last_poll_timestamp_seconds"
+	MetricPollStageRuns          = "price_snapshot_poll_stage_runs_total"
+	MetricPollStageTimeouts      = "price_snapshot_poll_stage_timeouts_total"
+	MetricPollStageLastDuration  = "price_snapshot_poll_stage_last_duration_seconds"
+	MetricPollStageMaxDuration   = "price_snapshot_poll_stage_max_duration_seconds"
+	MetricPollDuration           = "price_snapshot_poll_duration_seconds"
+	MetricHTTPRequestDuration    = "price_snapshot_http_request_duration_seconds"
+	MetricDBPoolConns            = "price_snapshot_db_pool_connections"
+	MetricDBPoolMaxConns         = "price_snapshot_db_pool_max_connections"
+	MetricDBPoolAcquires         = "price_snapshot_db_pool_acquires_total"
+	MetricDBPoolEmptyAcquires    = "price_snapshot_db_pool_empty_acquires_total"
+	MetricDBPoolAcquireDuration  = "price_snapshot_db_pool_acquire_duration_seconds_total"
+	MetricSymbolSnapshots        = "price_snapshot_symbol_snapshots"
+	MetricGoroutines             = "price_snapshot_runtime_goroutines"
+	MetricHeapAlloc              = "price_snapshot_runtime_heap_alloc_bytes"
+	MetricHeapSys                = "price_snapshot_runtime_heap_sys_bytes"
+	MetricGCCycles               = "price_snapshot_runtime_gc_cycles_total"
+	MetricGCPause                = "price_snapshot_runtime_gc_pause_seconds_total"
+	MetricLastGCPause            = "price_snapshot_runtime_last_gc_pause_seconds"
+	MetricOpenFDs                = "price_snapshot_runtime_open_fds"
+)
+
+// WriteMetrics writes m in the Prometheus text exposition format
+func WriteMetrics(w io.Writer, m *domain.Metrics) error {
+	e := newExpositionWriter(w, m.Labels, false)
+	writeMetrics(e, m)
+	return e.err
+}
+
+// WriteOpenMetrics writes m in the OpenMetrics text format
+func WriteOpenMetrics(w io.Writer, m *domain.Metrics) error {
+	e := newExpositionWriter(w, m.Labels, true)
+	writeMetrics(e, m)
+	e.printf("# EOF\n")
+	return e.err
+}
+
+// writeMetrics writes every metric family of m. The constant labels of m
+// are attached to each sample and also exported as target_info.
+func writeMetrics(e *expositionWriter, m *domain.Metrics) {
+	if len(m.Labels) > 0 {
+		e.family(MetricTargetInfo, "info", "Labels of the service instance.")
+		e.sample(MetricTargetInfo, nil, 1)
+	}
+
+	if m.Build != nil {
+		e.family(MetricBuildInfo, "info", "Build information of the running binary.")
+		e.sample(MetricBuildInfo, []string{
+			"version", m.Build.Version,
+			"revision", m.Build.Revision,
+			"go_version", m.Build.GoVersion,
+		}, 1)
+	}
+
+	if m.Config != nil {
+		e.family(MetricConfigInfo, "info", "Effective configuration of the instance.")
+		e.sample(MetricConfigInfo, []string{
+			"poller_mode", m.Config.PollerMode,
+			"poll_interval_seconds", strconv.FormatFloat(m.Config.PollInterval, 'f', -1, 64),
+			"retention_days", strconv.Itoa(m.Config.RetentionDays),
+			"max_history_limit", strconv.Itoa(m.Config.MaxHistoryLimit),
+		}, 1)
+	}
+
+	e.family(MetricUptime, "gauge", "Seconds since the service started.")
+	e.sample(MetricUptime, nil, m.Uptime)
+
+	e.family(MetricTrackedSymbols, "gauge", "Symbols tracked, active or not.")
+	e.sample(MetricTrackedSymbols, nil, float64(m.TrackedSymbols))
+
+	e.family(MetricActiveSymbols, "gauge", "Symbols currently polled.")
+	e.sample(MetricActiveSymbols, nil, float64(m.ActiveSymbols))
+
+	if m.SymbolLimits != nil {
+		e.family(MetricSymbolLimit, "gauge", "Configured cap on symbols by kind.")
+		if m.SymbolLimits.MaxTracked > 0 {
+			e.sample(MetricSymbolLimit, []string{"kind", "tracked"}, float64(m.SymbolLimits.MaxTracked))
+		}
+		if m.SymbolLimits.MaxActive > 0 {
+			e.sample(MetricSymbolLimit, []string{"kind", "active"}, float64(m.SymbolLimits.MaxActive))
+		}
+	}
+
+	e.family(MetricSnapshots, "gauge", "Price snapshots stored.")
+	e.sample(MetricSnapshots, nil, float64(m.TotalSnapshots))
+
+	if m.LastPollTime != nil {
+		e.family(MetricLastPollTimestamp, "gauge", "Unix time of the last poll, successful or not.")
+		e.sample(MetricLastPollTimestamp, nil, float64(m.LastPollTime.UnixMilli())/1000)
+	}
+
+	e.family(MetricLastPollDuration, "gauge", "Duration of the last poll.")
+	e.sample(MetricLastPollDuration, nil, m.LastPollDuration/1000)
+
+	if m.PollDuration != nil {
+		e.family(MetricPollDuration, "histogram", "Duration of polls, successful or not.")
+		e.histogram(MetricPollDuration, nil, m.PollDuration)
+	}
+
+	e.family(MetricPolls, "counter", "Polls by result.")
+	e.sample(MetricPolls, []string{"result", "success"}, float64(m.PollSuccessCount))
+	e.sample(MetricPolls, []string{"result", "error"}, float64(m.PollErrorCount))
+
+	e.family(MetricPrunedSnapshots, "counter", "Snapshots deleted by retention pruning.")
+	e.sample(MetricPrunedSnapshots, nil, float64(m.PrunedSnapshots))
+
+	e.family(MetricPartialPolls, "counter", "Polls that stored some snapshots but not all.")
+	e.sample(MetricPartialPolls, nil, float64(m.PartialPolls))
+
+	e.family(MetricSnapshotWriteFailures, "counter", "Snapshots rejected by partial batch writes.")
+	e.sample(MetricSnapshotWriteFailures, nil, float64(m.SnapshotWriteFailures))
+
+	e.family(MetricDatabaseUp, "gauge", "Whether the database is reachable.")
+	e.sample(MetricDatabaseUp, nil, up(m.DatabaseStatus))
+
+	e.family(MetricExchangeUp, "gauge", "Whether the exchange is reachable.")
+	e.sample(MetricExchangeUp, nil, up(m.ExchangeStatus))
+
+	if len(m.SymbolSnapshots) > 0 {
+		e.family(MetricSymbolSnapshots, "gauge", "Price snapshots stored by symbol.")
+		for _, s := range m.SymbolSnapshots {
+			e.sample(MetricSymbolSnapshots, []string{"symbol", s.Symbol}, float64(s.Count))
+		}
+	}
+
+	if p := m.DatabasePool; p != nil {
+		e.family(MetricDBPoolConns, "gauge", "Database pool connections by state.")
+		e.sample(MetricDBPoolConns, []string{"state", "idle"}, float64(p.IdleConns))
+		e.sample(MetricDBPoolConns, []string{"state", "acquired"}, float64(p.AcquiredConns))
+		e.sample(MetricDBPoolConns, []string{"state", "total"}, float64(p.TotalConns))
+
+		e.family(MetricDBPoolMaxConns, "gauge", "Maximum size of the database pool.")
+		e.sample(MetricDBPoolMaxConns, nil, float64(p.MaxConns))
+
+		e.family(MetricDBPoolAcquires, "counter", "Connections acquired from the database pool.")
+		e.sample(MetricDBPoolAcquires, nil, float64(p.AcquireCount))
+
+		e.family(MetricDBPoolEmptyAcquires, "counter", "Acquires that waited because the database pool was empty.")
+		e.sample(MetricDBPoolEmptyAcquires, nil, float64(p.EmptyAcquireCount))
+
+		e.family(MetricDBPoolAcquireDuration, "counter", "Time spent acquiring database pool connections.")
+		e.sample(MetricDBPoolAcquireDuration, nil, p.AcquireDurationSeconds)
+	}
+
+	if len(m.HTTPRequests) > 0 {
+		e.family(MetricHTTPRequestDuration, "histogram", "Duration of HTTP requests by route, method and status code.")
+		for _, r := range m.HTTPRequests {
+			e.histogram(MetricHTTPRequestDuration, []string{
+				"route", r.Route,
+				"method", r.Method,
+				"code", strconv.Itoa(r.Code),
+			}, r.Duration)
+		}
+	}
+
+	if len(m.ExchangeErrors) > 0 {
+		e.family(MetricExchangeErrors, "counter", "Failed exchange request attempts by operation and kind.")
+		for _, c := range m.ExchangeErrors {
+			e.sample(MetricExchangeErrors, []string{"operation", c.Operation, "kind", string(c.Kind)}, float64(c.Count))
+		}
+	}
+
+	if len(m.ExchangeRetries) > 0 {
+		e.family(MetricExchangeRetries, "counter", "Retried exchange requests by operation and the kind of failure retried.")
+		for _, r := range m.ExchangeRetries {
+			e.sample(MetricExchangeRetries, []string{"operation", r.Operation, "reason", string(r.Reason)}, float64(r.Count))
+		}
+	}
+
+	if len(m.ExchangeAttempts) > 0 {
+		e.family(MetricExchangeAttempts, "histogram", "Exchange request attempts per call by operation, retries included.")
+		for _, a := range m.ExchangeAttempts {
+			e.histogram(MetricExchangeAttempts, []string{"operation", a.Operation}, a.Attempts)
+		}
+	}
+
+	if b := m.ExchangeBudget; b != nil {
+		e.family(MetricExchangeWeightLimit, "gauge", "Exchange request weight the service may use per budget window.")
+		e.sample(MetricExchangeWeightLimit, nil, float64(b.Limit))
+
+		e.family(MetricExchangeWeightUsed, "gauge", "Exchange request weight used in the current budget window, as counted or reported by the exchange.")
+		e.sample(MetricExchangeWeightUsed, nil, float64(b.Used))
+
+		if len(b.Priorities) > 0 {
+			e.family(MetricExchangeBudgetWaits, "counter", "Exchange calls that waited for a later budget window by priority.")
+			for _, p := range b.Priorities {
+				e.sample(MetricExchangeBudgetWaits, []string{"priority", p.Priority}, float64(p.Waited))
+			}
+
+			e.family(MetricExchangeBudgetShed, "counter", "Exchange calls shed because their deadline came before the budget freed up, by priority.")
+			for _, p := range b.Priorities {
+				e.sample(MetricExchangeBudgetShed, []string{"priority", p.Priority}, float64(p.Shed))
+			}
+		}
+	}
+
+	if len(m.SlowQueries) > 0 {
+		e.family(MetricSlowQueries, "counter", "Database statements slower than the slow query threshold by query.")
+		for _, q := range m.SlowQueries {
+			e.sample(MetricSlowQueries, []string{"query", q.Query}, float64(q.Count))
+		}
+	}
+
+	if len(m.PollerGroups) > 0 {
+		e.family(MetricGroupPolls, "counter", "Polls by poller group and result.")
+		for _, g := range m.PollerGroups {
+			e.sample(MetricGroupPolls, []string{"group", g.Name, "result", "success"}, float64(g.PollSuccessCount))
+			e.sample(MetricGroupPolls, []string{"group", g.Name, "result", "error"}, float64(g.PollErrorCount))
+		}
+
+		e.family(MetricGroupLastPollTimestamp, "gauge", "Unix time of the last poll by poller group.")
+		for _, g := range m.PollerGroups {
+			if g.LastPollTime != nil {
+				e.sample(MetricGroupLastPollTimestamp, []string{"group", g.Name}, float64(g.LastPollTime.UnixMilli())/1000)
+			}
+		}
+	}
+
+	if len(m.PollStages) > 0 {
+		e.family(MetricPollStageRuns, "counter", "Poll stage runs.")
+		for _, s := range m.PollStages {
+			e.sample(MetricPollStageRuns, []string{"stage", string(s.Stage)}, float64(s.Count))
+		}
+
+		e.family(MetricPollStageTimeouts, "counter", "Poll stages that ran out of their budget.")
+		for _, s := range m.PollStages {
+			e.sample(MetricPollStageTimeouts, []string{"stage", string(s.Stage)}, float64(s.Timeouts))
+		}
+
+		e.family(MetricPollStageLastDuration, "gauge", "Duration of the last run of a poll stage.")
+		for _, s := range m.PollStages {
+			e.sample(MetricPollStageLastDuration, []string{"stage", string(s.Stage)}, s.LastDurationMs/1000)
+		}
+
+		e.family(MetricPollStageMaxDuration, "gauge", "Longest run of a poll stage.")
+		for _, s := range m.PollStages {
+			e.sample(MetricPollStageMaxDuration, []string{"stage", string(s.Stage)}, s.MaxDurationMs/1000)
+		}
+	}
+
+	if r := m.Runtime; r != nil {
+		e.family(MetricGoroutines, "gauge", "Goroutines that currently exist.")
+		e.sample(MetricGoroutines, nil, float64(r.Goroutines))
+
+		e.family(MetricHeapAlloc, "gauge", "Bytes of allocated heap objects.")
+		e.sample(MetricHeapAlloc, nil, float64(r.HeapAllocBytes))
+
+		e.family(MetricHeapSys, "gauge", "Bytes of heap memory obtained from the OS.")
+		e.sample(MetricHeapSys, nil, float64(r.HeapSysBytes))
+
+		e.family(MetricGCCycles, "counter", "Completed GC cycles.")
+		e.sample(MetricGCCycles, nil, float64(r.GCCycles))
+
+		e.family(MetricGCPause, "counter", "Time spent in GC stop-the-world pauses.")
+		e.sample(MetricGCPause, nil, r.GCPauseTotalMs/1000)
+
+		e.family(MetricLastGCPause, "gauge", "Duration of the last GC pause.")
+		e.sample(MetricLastGCPause, nil, r.LastGCPauseMs/1000)
+
+		if r.OpenFDs != nil {
+			e.family(MetricOpenFDs, "gauge", "Open file descriptors of the process.")
+			e.sample(MetricOpenFDs, nil, float64(*r.OpenFDs))
+		}
+	}
+}
+
+// up converts a health status into a 0/1 gauge value
+func up(status string) float64 {
+	if status == "healthy" {
+		return 1
+	}
+	return 0
+}
+
+// expositionWriter writes metric families, keeping the first write error
+type expositionWriter struct {
+	w           io.Writer
+	constLabels []string
+	openMetrics bool
+	err         error
+}
+
+// newExpositionWriter creates a writer that appends the constant labels,
+// sorted by name, to every sample
+func newExpositionWriter(w io.Writer, constLabels map[string]string, openMetrics bool) *expositionWriter {
+	names := make([]string, 0, len(constLabels))
+	for name := range constLabels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	labels := make([]string, 0, 2*len(names))
+	for _, name := range names {
+		labels = append(labels, name, constLabels[name])
+	}
+
+	return &expositionWriter{w: w, constLabels: labels, openMetrics: openMetrics}
+}
+
+// family writes the metadata of a metric family given its sample name.
+// OpenMetrics names counter families without the _total suffix and info
+// families without _info; the Prometheus text format has no info type, so
+// info metrics are written as gauges.
+func (e *expositionWriter) family(name, metricType, help string) {
+	if e.openMetrics {
+		switch metricType {
+		case "counter":
+			name = strings.TrimSuffix(name, "_total")
+		case "info":
+			name = strings.TrimSuffix(name, "_info")
+		}
+	} else if metricType == "info" {
+		metricType = "gauge"
+	}
+	e.printf("# HELP %s %s\n# TYPE %s %s\n", name, help, name, metricType)
+}
+
+// sample writes one sample; labels alternate between names and values. A
+// constant label is skipped when the sample sets a label of the same name.
+func (e *expositionWriter) sample(name string, labels []string, value float64) {
+	var b strings.Builder
+	b.WriteString(name)
+
+	n := 0
+	writeLabel := func(label, value string) {
+		if n == 0 {
+			b.WriteByte('{')
+		} else {
+			b.WriteByte(',')
+		}
+		b.WriteString(label)
+		b.WriteString(`="`)
+		b.WriteString(escapeLabelValue(value))
+		b.WriteByte('"')
+		n++
+	}
+
+	for i := 0; i+1 < len(labels); i += 2 {
+		writeLabel(labels[i], labels[i+1])
+	}
+	for i := 0; i+1 < len(e.constLabels); i += 2 {
+		if !hasLabel(labels, e.constLabels[i]) {
+			writeLabel(e.constLabels[i], e.constLabels[i+1])
+		}
+	}
+	if n > 0 {
+		b.WriteByte('}')
+	}
+
+	e.printf("%s %s\n", b.String(), strconv.FormatFloat(value, 'f', -1, 64))
+}
+
+// histogram writes the bucket, sum and count samples of a histogram
+func (e *expositionWriter) histogram(name string, labels []string, h *domain.Histogram) {
+	for i, bound := range h.Bounds {
+		le := strconv.FormatFloat(bound, 'f', -1, 64)
+		e.sample(name+"_bucket", append(labels[:len(labels):len(labels)], "le", le), float64(h.Counts[i]))
+	}
+	e.sample(name+"_bucket", append(labels[:len(labels):len(labels)], "le", "+Inf"), float64(h.Count))
+	e.sample(name+"_sum", labels, h.Sum)
+	e.sample(name+"_count", labels, float64(h.Count))
+}
+
+// hasLabel reports whether alternating name/value labels include name
+func hasLabel(labels []string, name string) bool {
+	for i := 0; i < len(labels); i += 2 {
+		if labels[i] == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *expositionWriter) printf(format string, args ...interface{}) {
+	if e.err != nil {
+		return
+	}
+	_, e.err = fmt.Fprintf(e.w, format, args...)
+}
+
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// escapeLabelValue escapes backslashes, quotes and newlines in a label value
+func escapeLabelValue(v string) string {
+	return labelValueEscaper.Replace(v)
+}
@@ -0,0 +1,233 @@
+package prometheus_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/adapters/prometheus"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+)
+
+func TestWriteMetrics(t *testing.T) {
+	lastPoll := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	m := &domain.Metrics{
+		Uptime:           3600.5,
+		TrackedSymbols:   5,
+		ActiveSymbols:    3,
+		SymbolLimits:     &domain.SymbolLimits{MaxActive: 50},
+		TotalSnapshots:   1000,
+		LastPollTime:     &lastPoll,
+		LastPollDuration: 150,
+		PollSuccessCount: 100,
+		PollErrorCount:   2,
+		PartialPolls:     1,
+		DatabaseStatus:   "healthy",
+		ExchangeStatus:   "unhealthy",
+		ExchangeErrors: []domain.ExchangeErrorCount{
+			{Operation: "prices", Kind: domain.ExchangeErrorRateLimited, Count: 3},
+		},
+		ExchangeRetries: []domain.ExchangeRetryCount{
+			{Operation: "prices", Reason: domain.ExchangeErrorRateLimited, Count: 2},
+		},
+		ExchangeAttempts: []domain.ExchangeCallAttempts{
+			{Operation: "prices", Attempts: &domain.Histogram{Bounds: []float64{1, 2}, Counts: []int64{5, 6}, Count: 7, Sum: 10}},
+		},
+		SlowQueries: []domain.SlowQueryCount{
+			{Query: "latest_by_symbols", Count: 4},
+		},
+		PollerGroups: []domain.PollerGroupMetrics{
+			{Name: `fa"st`, PollSuccessCount: 7},
+		},
+		PollStages: []domain.PollStageMetrics{
+			{Stage: domain.PollStageFetch, Count: 10, LastDurationMs: 250, MaxDurationMs: 1500, Timeouts: 1},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, prometheus.WriteMetrics(&buf, m))
+	out := buf.String()
+
+	assert.Contains(t, out, "# TYPE price_snapshot_polls_total counter\n")
+	assert.Contains(t, out, "price_snapshot_uptime_seconds 3600.5\n")
+	assert.Contains(t, out, `price_snapshot_symbol_limit{kind="active"} 50`+"\n")
+	assert.NotContains(t, out, `price_snapshot_symbol_limit{kind="tracked"}`)
+	assert.Contains(t, out, "price_snapshot_last_poll_timestamp_seconds 1705314600\n")
+	assert.Contains(t, out, "price_snapshot_last_poll_duration_seconds 0.15\n")
+	assert.Contains(t, out, `price_snapshot_polls_total{result="error"} 2`+"\n")
+	assert.Contains(t, out, "price_snapshot_partial_polls_total 1\n")
+	assert.Contains(t, out, "price_snapshot_database_up 1\n")
+	assert.Contains(t, out, "price_snapshot_exchange_up 0\n")
+	assert.Contains(t, out, `price_snapshot_exchange_errors_total{operation="prices",kind="rate_limited"} 3`+"\n")
+	assert.Contains(t, out, `price_snapshot_exchange_retries_total{operation="prices",reason="rate_limited"} 2`+"\n")
+	assert.Contains(t, out, "# TYPE price_snapshot_exchange_call_attempts histogram\n")
+	assert.Contains(t, out, `price_snapshot_exchange_call_attempts_bucket{operation="prices",le="2"} 6`+"\n")
+	assert.Contains(t, out, `price_snapshot_exchange_call_attempts_count{operation="prices"} 7`+"\n")
+	assert.Contains(t, out, `price_snapshot_slow_queries_total{query="latest_by_symbols"} 4`+"\n")
+	assert.Contains(t, out, `price_snapshot_poller_group_polls_total{group="fa\"st",result="success"} 7`+"\n")
+	assert.Contains(t, out, `price_snapshot_poll_stage_timeouts_total{stage="fetch"} 1`+"\n")
+	assert.Contains(t, out, `price_snapshot_poll_stage_max_duration_seconds{stage="fetch"} 1.5`+"\n")
+}
+
+func TestWriteMetrics_Runtime(t *testing.T) {
+	fds := 42
+	m := &domain.Metrics{
+		Runtime: &domain.RuntimeStats{
+			Goroutines:     25,
+			HeapAllocBytes: 8388608,
+			HeapSysBytes:   16777216,
+			GCCycles:       12,
+			GCPauseTotalMs: 3.5,
+			LastGCPauseMs:  0.25,
+			OpenFDs:        &fds,
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, prometheus.WriteMetrics(&buf, m))
+	out := buf.String()
+
+	assert.Contains(t, out, "price_snapshot_runtime_goroutines 25\n")
+	assert.Contains(t, out, "price_snapshot_runtime_heap_alloc_bytes 8388608\n")
+	assert.Contains(t, out, "price_snapshot_runtime_heap_sys_bytes 16777216\n")
+	assert.Contains(t, out, "# TYPE price_snapshot_runtime_gc_cycles_total counter\n")
+	assert.Contains(t, out, "price_snapshot_runtime_gc_cycles_total 12\n")
+	assert.Contains(t, out, "price_snapshot_runtime_gc_pause_seconds_total 0.0035\n")
+	assert.Contains(t, out, "price_snapshot_runtime_last_gc_pause_seconds 0.00025\n")
+	assert.Contains(t, out, "price_snapshot_runtime_open_fds 42\n")
+}
+
+func TestWriteMetrics_ExchangeBudget(t *testing.T) {
+	m := &domain.Metrics{
+		ExchangeBudget: &domain.ExchangeBudgetStats{
+			Limit:         6000,
+			Used:          4120,
+			WindowSeconds: 60,
+			Priorities: []domain.ExchangePriorityStats{
+				{Priority: "backfill", Waited: 12, Shed: 3},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, prometheus.WriteMetrics(&buf, m))
+	out := buf.String()
+
+	assert.Contains(t, out, "price_snapshot_exchange_weight_limit 6000\n")
+	assert.Contains(t, out, "price_snapshot_exchange_weight_used 4120\n")
+	assert.Contains(t, out, "# TYPE price_snapshot_exchange_budget_waits_total counter\n")
+	assert.Contains(t, out, `price_snapshot_exchange_budget_waits_total{priority="backfill"} 12`+"\n")
+	assert.Contains(t, out, `price_snapshot_exchange_budget_shed_total{priority="backfill"} 3`+"\n")
+}
+
+func TestWriteMetrics_Histograms(t *testing.T) {
+	polls := domain.NewHistogram([]float64{0.5, 1})
+	polls.Observe(0.25)
+	polls.Observe(2)
+	requests := domain.NewHistogram([]float64{0.1})
+	requests.Observe(0.05)
+
+	m := &domain.Metrics{
+		PollDuration: polls,
+		HTTPRequests: []domain.HTTPRequestMetrics{
+			{Route: "/symbols/{symbol}", Method: "GET", Code: 200, Duration: requests},
+		},
+		DatabasePool: &domain.DatabasePoolStats{
+			MaxConns:               10,
+			TotalConns:             4,
+			IdleConns:              3,
+			AcquiredConns:          1,
+			AcquireCount:           120,
+			EmptyAcquireCount:      2,
+			AcquireDurationSeconds: 0.75,
+		},
+		SymbolSnapshots: []domain.SymbolSnapshotCount{
+			{Symbol: "BTCUSDT", Count: 600},
+		},
+		Labels: map[string]string{"environment": "prod"},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, prometheus.WriteMetrics(&buf, m))
+	out := buf.String()
+
+	assert.Contains(t, out, "# TYPE price_snapshot_poll_duration_seconds histogram\n")
+	assert.Contains(t, out, `price_snapshot_poll_duration_seconds_bucket{le="0.5",environment="prod"} 1`+"\n")
+	assert.Contains(t, out, `price_snapshot_poll_duration_seconds_bucket{le="1",environment="prod"} 1`+"\n")
+	assert.Contains(t, out, `price_snapshot_poll_duration_seconds_bucket{le="+Inf",environment="prod"} 2`+"\n")
+	assert.Contains(t, out, `price_snapshot_poll_duration_seconds_sum{environment="prod"} 2.25`+"\n")
+	assert.Contains(t, out, `price_snapshot_poll_duration_seconds_count{environment="prod"} 2`+"\n")
+	assert.Contains(t, out, `price_snapshot_http_request_duration_seconds_bucket{route="/symbols/{symbol}",method="GET",code="200",le="0.1",environment="prod"} 1`+"\n")
+	assert.Contains(t, out, `price_snapshot_http_request_duration_seconds_count{route="/symbols/{symbol}",method="GET",code="200",environment="prod"} 1`+"\n")
+	assert.Contains(t, out, `price_snapshot_db_pool_connections{state="acquired",environment="prod"} 1`+"\n")
+	assert.Contains(t, out, `price_snapshot_db_pool_max_connections{environment="prod"} 10`+"\n")
+	assert.Contains(t, out, `price_snapshot_db_pool_empty_acquires_total{environment="prod"} 2`+"\n")
+	assert.Contains(t, out, `price_snapshot_symbol_snapshots{symbol="BTCUSDT",environment="prod"} 600`+"\n")
+}
+
+func TestWriteMetrics_ConstantLabels(t *testing.T) {
+	m := &domain.Metrics{
+		PollSuccessCount: 5,
+		Build:            &domain.BuildInfo{Version: "1.4.0", Revision: "abc123", GoVersion: "go1.22.0"},
+		Labels:           map[string]string{"region": "eu", "environment": "prod", "result": "ignored"},
+		Config:           &domain.EffectiveConfig{PollerMode: "interval", PollInterval: 30, RetentionDays: 7, MaxHistoryLimit: 1000},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, prometheus.WriteMetrics(&buf, m))
+	out := buf.String()
+
+	assert.Contains(t, out, "# TYPE target_info gauge\n")
+	assert.Contains(t, out, `target_info{environment="prod",region="eu",result="ignored"} 1`+"\n")
+	assert.Contains(t, out, `price_snapshot_build_info{version="1.4.0",revision="abc123",go_version="go1.22.0",environment="prod",region="eu",result="ignored"} 1`+"\n")
+	assert.Contains(t, out, `price_snapshot_config_info{poller_mode="interval",poll_interval_seconds="30",retention_days="7",max_history_limit="1000",environment="prod",region="eu",result="ignored"} 1`+"\n")
+	assert.Contains(t, out, `price_snapshot_uptime_seconds{environment="prod",region="eu",result="ignored"} 0`+"\n")
+	// A metric's own label wins over a constant label of the same name
+	assert.Contains(t, out, `price_snapshot_polls_total{result="success",environment="prod",region="eu"} 5`+"\n")
+	assert.NotContains(t, out, "# EOF")
+}
+
+func TestWriteOpenMetrics(t *testing.T) {
+	m := &domain.Metrics{
+		PollSuccessCount: 5,
+		Build:            &domain.BuildInfo{Version: "1.4.0", GoVersion: "go1.22.0"},
+		Labels:           map[string]string{"environment": "prod"},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, prometheus.WriteOpenMetrics(&buf, m))
+	out := buf.String()
+
+	assert.Contains(t, out, "# TYPE target info\n")
+	assert.Contains(t, out, `target_info{environment="prod"} 1`+"\n")
+	assert.Contains(t, out, "# TYPE price_snapshot_build info\n")
+	assert.Contains(t, out, "# TYPE price_snapshot_polls counter\n")
+	assert.Contains(t, out, `price_snapshot_polls_total{result="success",environment="prod"} 5`+"\n")
+	assert.Contains(t, out, "# TYPE price_snapshot_uptime_seconds gauge\n")
+	assert.True(t, strings.HasSuffix(out, "# EOF\n"))
+}
+
+func TestWriteMetrics_OmitsEmptyFamilies(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, prometheus.WriteMetrics(&buf, &domain.Metrics{}))
+	out := buf.String()
+
+	assert.NotContains(t, out, "price_snapshot_last_poll_timestamp_seconds")
+	assert.NotContains(t, out, "price_snapshot_exchange_errors_total")
+	assert.NotContains(t, out, "price_snapshot_slow_queries_total")
+	assert.NotContains(t, out, "target_info")
+	assert.NotContains(t, out, "price_snapshot_build_info")
+	assert.NotContains(t, out, "price_snapshot_config_info")
+	assert.NotContains(t, out, "price_snapshot_poll_stage")
+	assert.NotContains(t, out, "price_snapshot_symbol_limit")
+	assert.NotContains(t, out, "price_snapshot_runtime")
+	assert.NotContains(t, out, "price_snapshot_poll_duration_seconds_bucket")
+	assert.NotContains(t, out, "price_snapshot_http_request_duration_seconds")
+	assert.NotContains(t, out, "price_snapshot_db_pool")
+	assert.NotContains(t, out, "price_snapshot_symbol_snapshots")
+	assert.Contains(t, out, `price_snapshot_polls_total{result="success"} 0`+"\n")
+}
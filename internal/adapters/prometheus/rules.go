@@ -0,0 +1,169 @@
+package prometheus
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// RuleOptions tailors the generated alert rules to a deployment
+type RuleOptions struct {
+	// Job is the Prometheus job label the service is scraped under
+	Job string
+
+	// PollInterval is the configured poller interval; staleness windows
+	// are derived from it
+	PollInterval time.Duration
+}
+
+// Rule is a single Prometheus alerting rule
+type Rule struct {
+	Alert       string
+	Expr        string
+	For         time.Duration
+	Severity    string
+	Summary     string
+	Description string
+}
+
+// minStaleWindow keeps the staleness window wide enough to span several
+// scrapes even with very short poll intervals
+const minStaleWindow = 5 * time.Minute
+
+// Rules returns the recommended alert rules for the exposed metrics
+func Rules(opts RuleOptions) []Rule {
+	sel := func(matchers ...string) string {
+		all := append([]string{fmt.Sprintf("job=%q", opts.Job)}, matchers...)
+		return "{" + strings.Join(all, ",") + "}"
+	}
+
+	staleWindow := 3 * opts.PollInterval
+	if staleWindow < minStaleWindow {
+		staleWindow = minStaleWindow
+	}
+
+	return []Rule{
+		{
+			Alert:       "PriceSnapshotDown",
+			Expr:        "up" + sel() + " == 0",
+			For:         2 * time.Minute,
+			Severity:    "critical",
+			Summary:     "Price snapshot service is down",
+			Description: "{{ $labels.instance }} has not been scraped successfully for 2 minutes.",
+		},
+		{
+			Alert: "PriceSnapshotStalePrices",
+			Expr: fmt.Sprintf("increase(%s%s[%s]) == 0",
+				MetricPolls, sel(`result="success"`), promDuration(staleWindow)),
+			For:         time.Minute,
+			Severity:    "critical",
+			Summary:     "Prices are stale",
+			Description: fmt.Sprintf("{{ $labels.instance }} has not completed a successful poll in %s.", promDuration(staleWindow)),
+		},
+		{
+			Alert: "PriceSnapshotPollFailures",
+			Expr: fmt.Sprintf("sum by (instance) (rate(%s%s[15m])) / sum by (instance) (rate(%s%s[15m])) > 0.25",
+				MetricPolls, sel(`result="error"`), MetricPolls, sel()),
+			For:         10 * time.Minute,
+			Severity:    "warning",
+			Summary:     "Polls are failing",
+			Description: "More than 25% of polls on {{ $labels.instance }} failed over the last 15 minutes.",
+		},
+		{
+			Alert:       "PriceSnapshotPollStageTimeouts",
+			Expr:        fmt.Sprintf("increase(%s%s[15m]) > 0", MetricPollStageTimeouts, sel()),
+			Severity:    "warning",
+			Summary:     "Poll stage exceeded its budget",
+			Description: "The {{ $labels.stage }} stage on {{ $labels.instance }} ran out of its budget in the last 15 minutes.",
+		},
+		{
+			Alert:       "PriceSnapshotWriteFailures",
+			Expr:        fmt.Sprintf("increase(%s%s[15m]) > 0", MetricSnapshotWriteFailures, sel()),
+			Severity:    "warning",
+			Summary:     "Snapshots are being rejected",
+			Description: "{{ $labels.instance }} stored polls only partially in the last 15 minutes; check the logs for rejected symbols.",
+		},
+		{
+			Alert:       "PriceSnapshotExchangeDown",
+			Expr:        MetricExchangeUp + sel() + " == 0",
+			For:         5 * time.Minute,
+			Severity:    "critical",
+			Summary:     "Exchange is unreachable",
+			Description: "{{ $labels.instance }} cannot reach the exchange.",
+		},
+		{
+			Alert: "PriceSnapshotExchangeHostFailures",
+			Expr: fmt.Sprintf("sum by (instance, kind) (increase(%s%s[10m])) > 5",
+				MetricExchangeErrors, sel(`kind=~"timeout|network|server_error|blocked"`)),
+			Severity:    "warning",
+			Summary:     "Exchange hosts are failing over",
+			Description: "{{ $labels.instance }} saw {{ $value }} {{ $labels.kind }} failures in 10 minutes; failing hosts are put into cooldown.",
+		},
+		{
+			Alert: "PriceSnapshotExchangeRateLimited",
+			Expr: fmt.Sprintf("sum by (instance) (increase(%s%s[10m])) > 0",
+				MetricExchangeErrors, sel(`kind="rate_limited"`)),
+			Severity:    "warning",
+			Summary:     "Exchange is rate limiting requests",
+			Description: "{{ $labels.instance }} received 429 responses in the last 10 minutes.",
+		},
+		{
+			Alert: "PriceSnapshotExchangeBudgetShedding",
+			Expr: fmt.Sprintf("sum by (instance, priority) (increase(%s%s[10m])) > 0",
+				MetricExchangeBudgetShed, sel()),
+			Severity:    "warning",
+			Summary:     "Exchange calls are shed by the request budget",
+			Description: "{{ $labels.instance }} shed {{ $value }} {{ $labels.priority }} exchange calls in 10 minutes because the request weight budget was used up.",
+		},
+		{
+			Alert:       "PriceSnapshotDatabaseDown",
+			Expr:        MetricDatabaseUp + sel() + " == 0",
+			For:         2 * time.Minute,
+			Severity:    "critical",
+			Summary:     "Database is unreachable",
+			Description: "{{ $labels.instance }} cannot reach the database.",
+		},
+	}
+}
+
+// WriteRules writes rules as a Prometheus rule file with a single group
+func WriteRules(w io.Writer, group string, rules []Rule) error {
+	var b strings.Builder
+	b.WriteString("groups:\n")
+	fmt.Fprintf(&b, "  - name: %s\n", yamlQuote(group))
+	b.WriteString("    rules:\n")
+
+	for _, r := range rules {
+		fmt.Fprintf(&b, "      - alert: %s\n", r.Alert)
+		fmt.Fprintf(&b, "        expr: %s\n", yamlQuote(r.Expr))
+		if r.For > 0 {
+			fmt.Fprintf(&b, "        for: %s\n", promDuration(r.For))
+		}
+		b.WriteString("        labels:\n")
+		fmt.Fprintf(&b, "          severity: %s\n", r.Severity)
+		b.WriteString("        annotations:\n")
+		fmt.Fprintf(&b, "          summary: %s\n", yamlQuote(r.Summary))
+		fmt.Fprintf(&b, "          description: %s\n", yamlQuote(r.Description))
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// yamlQuote renders s as a single-quoted YAML scalar
+func yamlQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// promDuration formats d in Prometheus duration notation (e.g. 90s, 5m, 1h)
+func promDuration(d time.Duration) string {
+	switch {
+	case d%time.Hour == 0:
+		return fmt.Sprintf("%dh", d/time.Hour)
+	case d%time.Minute == 0:
+		return fmt.Sprintf("%dm", d/time.Minute)
+	default:
+		return fmt.Sprintf("%ds", (d+time.Second-1)/time.Second)
+	}
+}
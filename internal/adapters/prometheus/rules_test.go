@@ -0,0 +1,75 @@
+package prometheus_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/adapters/prometheus"
+)
+
+func TestRules(t *testing.T) {
+	rules := prometheus.Rules(prometheus.RuleOptions{Job: "snapshots", PollInterval: 5 * time.Minute})
+
+	byName := make(map[string]prometheus.Rule)
+	for _, r := range rules {
+		byName[r.Alert] = r
+		assert.Contains(t, r.Expr, `job="snapshots"`, r.Alert)
+		assert.NotEmpty(t, r.Severity, r.Alert)
+	}
+
+	require.Contains(t, byName, "PriceSnapshotStalePrices")
+	assert.Contains(t, byName["PriceSnapshotStalePrices"].Expr, prometheus.MetricPolls)
+	assert.Contains(t, byName["PriceSnapshotStalePrices"].Expr, "[15m]")
+
+	require.Contains(t, byName, "PriceSnapshotPollFailures")
+	require.Contains(t, byName, "PriceSnapshotExchangeHostFailures")
+	assert.Contains(t, byName["PriceSnapshotExchangeHostFailures"].Expr, prometheus.MetricExchangeErrors)
+	require.Contains(t, byName, "PriceSnapshotExchangeBudgetShedding")
+	assert.Contains(t, byName["PriceSnapshotExchangeBudgetShedding"].Expr, prometheus.MetricExchangeBudgetShed)
+}
+
+func TestRules_StaleWindowHasFloor(t *testing.T) {
+	rules := prometheus.Rules(prometheus.RuleOptions{Job: "snapshots", PollInterval: 5 * time.Second})
+
+	for _, r := range rules {
+		if r.Alert == "PriceSnapshotStalePrices" {
+			assert.Contains(t, r.Expr, "[5m]")
+			return
+		}
+	}
+	t.Fatal("stale prices rule not generated")
+}
+
+func TestWriteRules(t *testing.T) {
+	var buf bytes.Buffer
+	err := prometheus.WriteRules(&buf, "snapshots", []prometheus.Rule{{
+		Alert:       "Example",
+		Expr:        `up{job="x"} == 0`,
+		For:         90 * time.Second,
+		Severity:    "critical",
+		Summary:     "it's down",
+		Description: "{{ $labels.instance }} is down",
+	}})
+	require.NoError(t, err)
+
+	expected := strings.Join([]string{
+		"groups:",
+		"  - name: 'snapshots'",
+		"    rules:",
+		"      - alert: Example",
+		`        expr: 'up{job="x"} == 0'`,
+		"        for: 90s",
+		"        labels:",
+		"          severity: critical",
+		"        annotations:",
+		"          summary: 'it''s down'",
+		"          description: '{{ $labels.instance }} is down'",
+		"",
+	}, "\n")
+	assert.Equal(t, expected, buf.String())
+}
@@ -0,0 +1,73 @@
+package schema
+
+import (
+	"context"
+	"encoding/binary"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+)
+
+// SymbolEventDigestAvro is the Avro schema of symbol event digests. Event
+// details vary by type, so they are carried as a JSON object string.
+const SymbolEventDigestAvro = `{"type":"record","name":"SymbolEventDigest","namespace":"io.pricesnapshot.events","fields":[` +
+	`{"name":"events","type":{"type":"array","items":{"type":"record","name":"SymbolEvent","fields":[` +
+	`{"name":"id","type":"long"},` +
+	`{"name":"symbol","type":"string"},` +
+	`{"name":"type","type":"string"},` +
+	`{"name":"details","type":["null","string"],"default":null},` +
+	`{"name":"created_at","type":{"type":"long","logicalType":"timestamp-micros"}}]}}},` +
+	`{"name":"sent_at","type":{"type":"long","logicalType":"timestamp-micros"}}]}`
+
+// AvroEncoder encodes digests as Avro binary framed with their registry
+// schema ID
+type AvroEncoder struct {
+	registry *Registry
+	subject  string
+}
+
+// ContentType is the media type of encoded digests
+func (e *AvroEncoder) ContentType() string {
+	return "application/vnd.apache.avro+binary"
+}
+
+// EncodeSymbolEvents serializes a digest as SymbolEventDigestAvro
+func (e *AvroEncoder) EncodeSymbolEvents(ctx context.Context, digest *domain.SymbolEventDigest) ([]byte, error) {
+	id, err := e.registry.SchemaID(ctx, e.subject, "", SymbolEventDigestAvro)
+	if err != nil {
+		return nil, err
+	}
+
+	// Arrays are written as one block of all items and an empty block
+	buf := make([]byte, 0, 64*len(digest.Events))
+	if len(digest.Events) > 0 {
+		buf = binary.AppendVarint(buf, int64(len(digest.Events)))
+		for _, event := range digest.Events {
+			details, err := detailsJSON(event)
+			if err != nil {
+				return nil, err
+			}
+
+			buf = binary.AppendVarint(buf, event.ID)
+			buf = appendAvroString(buf, event.Symbol)
+			buf = appendAvroString(buf, string(event.Type))
+			if details == "" {
+				buf = binary.AppendVarint(buf, 0)
+			} else {
+				buf = binary.AppendVarint(buf, 1)
+				buf = appendAvroString(buf, details)
+			}
+			buf = binary.AppendVarint(buf, event.CreatedAt.UnixMicro())
+		}
+	}
+	buf = binary.AppendVarint(buf, 0)
+	buf = binary.AppendVarint(buf, digest.SentAt.UnixMicro())
+
+	return frame(id, buf), nil
+}
+
+// appendAvroString appends s as an Avro string: its zigzag length, then
+// its bytes
+func appendAvroString(buf []byte, s string) []byte {
+	buf = binary.AppendVarint(buf, int64(len(s)))
+	return append(buf, s...)
+}
@@ -0,0 +1,68 @@
+package schema
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+)
+
+// NewSymbolEventEncoder returns the encoder for format. Avro and Protobuf
+// payloads are registered under subject with registry, which may be nil
+// for JSON.
+func NewSymbolEventEncoder(format domain.EventFormat, registry *Registry, subject string) (ports.SymbolEventEncoder, error) {
+	if format.Schemaless() {
+		return JSONEncoder{}, nil
+	}
+	if registry == nil {
+		return nil, fmt.Errorf("%s events need a schema registry", format)
+	}
+
+	switch format {
+	case domain.EventFormatAvro:
+		return &AvroEncoder{registry: registry, subject: subject}, nil
+	case domain.EventFormatProtobuf:
+		return &ProtobufEncoder{registry: registry, subject: subject}, nil
+	default:
+		return nil, fmt.Errorf("unknown event format %q", format)
+	}
+}
+
+// JSONEncoder encodes digests as schemaless JSON
+type JSONEncoder struct{}
+
+// ContentType is the media type of encoded digests
+func (JSONEncoder) ContentType() string {
+	return "application/json"
+}
+
+// EncodeSymbolEvents serializes a digest as JSON
+func (JSONEncoder) EncodeSymbolEvents(ctx context.Context, digest *domain.SymbolEventDigest) ([]byte, error) {
+	data, err := json.Marshal(digest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode payload: %w", err)
+	}
+	return data, nil
+}
+
+// detailsJSON returns an event's details as a JSON object, or "" when it
+// has none
+func detailsJSON(event *domain.SymbolEvent) (string, error) {
+	if len(event.Details) == 0 {
+		return "", nil
+	}
+	data, err := json.Marshal(event.Details)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode details of event %d: %w", event.ID, err)
+	}
+	return string(data), nil
+}
+
+// Ensure the encoders implement ports.SymbolEventEncoder
+var (
+	_ ports.SymbolEventEncoder = JSONEncoder{}
+	_ ports.SymbolEventEncoder = (*AvroEncoder)(nil)
+	_ ports.SymbolEventEncoder = (*ProtobufEncoder)(nil)
+)
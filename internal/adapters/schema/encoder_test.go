@@ -0,0 +1,161 @@
+package schema_test
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/adapters/schema"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+)
+
+// registryServer is a schema registry that assigns ID 42 and records
+// registrations
+func registryServer(t *testing.T, registrations *[]map[string]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/subjects/symbol-events-value/versions", r.URL.Path)
+
+		var body map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		*registrations = append(*registrations, body)
+
+		w.Header().Set("Content-Type", "application/vnd.schemaregistry.v1+json")
+		w.Write([]byte(`{"id": 42}`))
+	}))
+}
+
+func testDigest() *domain.SymbolEventDigest {
+	created := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	return &domain.SymbolEventDigest{
+		Events: []*domain.SymbolEvent{
+			{ID: 1, Symbol: "BTCUSDT", Type: domain.SymbolEventAdded, CreatedAt: created},
+			{ID: 2, Symbol: "ETHUSDT", Type: domain.SymbolEventTagsChanged, Details: map[string]interface{}{"to": []string{"l1"}}, CreatedAt: created},
+		},
+		SentAt: created.Add(time.Minute),
+	}
+}
+
+// readVarint reads a zigzag varint off the front of data
+func readVarint(t *testing.T, data *[]byte) int64 {
+	v, n := binary.Varint(*data)
+	require.Positive(t, n)
+	*data = (*data)[n:]
+	return v
+}
+
+func readAvroString(t *testing.T, data *[]byte) string {
+	n := readVarint(t, data)
+	s := string((*data)[:n])
+	*data = (*data)[n:]
+	return s
+}
+
+func TestAvroEncoder(t *testing.T) {
+	var registrations []map[string]string
+	server := registryServer(t, &registrations)
+	defer server.Close()
+
+	encoder, err := schema.NewSymbolEventEncoder(domain.EventFormatAvro, schema.NewRegistry(server.URL, time.Second), "symbol-events-value")
+	require.NoError(t, err)
+	assert.Equal(t, "application/vnd.apache.avro+binary", encoder.ContentType())
+
+	digest := testDigest()
+	data, err := encoder.EncodeSymbolEvents(context.Background(), digest)
+	require.NoError(t, err)
+
+	_, err = encoder.EncodeSymbolEvents(context.Background(), digest)
+	require.NoError(t, err)
+	require.Len(t, registrations, 1, "the schema ID is cached")
+	assert.Equal(t, schema.SymbolEventDigestAvro, registrations[0]["schema"])
+	assert.Empty(t, registrations[0]["schemaType"])
+
+	assert.Equal(t, []byte{0, 0, 0, 0, 42}, data[:5])
+	data = data[5:]
+
+	require.Equal(t, int64(2), readVarint(t, &data))
+	for i, event := range digest.Events {
+		assert.Equal(t, event.ID, readVarint(t, &data))
+		assert.Equal(t, event.Symbol, readAvroString(t, &data))
+		assert.Equal(t, string(event.Type), readAvroString(t, &data))
+		if i == 0 {
+			assert.Equal(t, int64(0), readVarint(t, &data), "no details is null")
+		} else {
+			assert.Equal(t, int64(1), readVarint(t, &data))
+			assert.JSONEq(t, `{"to": ["l1"]}`, readAvroString(t, &data))
+		}
+		assert.Equal(t, event.CreatedAt.UnixMicro(), readVarint(t, &data))
+	}
+	assert.Equal(t, int64(0), readVarint(t, &data))
+	assert.Equal(t, digest.SentAt.UnixMicro(), readVarint(t, &data))
+	assert.Empty(t, data)
+}
+
+func TestProtobufEncoder(t *testing.T) {
+	var registrations []map[string]string
+	server := registryServer(t, &registrations)
+	defer server.Close()
+
+	encoder, err := schema.NewSymbolEventEncoder(domain.EventFormatProtobuf, schema.NewRegistry(server.URL, time.Second), "symbol-events-value")
+	require.NoError(t, err)
+
+	digest := testDigest()
+	digest.Events = digest.Events[:1]
+	data, err := encoder.EncodeSymbolEvents(context.Background(), digest)
+	require.NoError(t, err)
+	require.Len(t, registrations, 1)
+	assert.Equal(t, "PROTOBUF", registrations[0]["schemaType"])
+
+	// Magic byte, schema ID, message index 0
+	assert.Equal(t, []byte{0, 0, 0, 0, 42, 0}, data[:6])
+
+	event := []byte{0x08, 1, 0x12, 7}
+	event = append(event, "BTCUSDT"...)
+	event = append(event, 0x1a, 5)
+	event = append(event, "added"...)
+	event = append(event, 0x28)
+	event = binary.AppendUvarint(event, uint64(digest.Events[0].CreatedAt.UnixMicro()))
+
+	want := []byte{0x0a, byte(len(event))}
+	want = append(want, event...)
+	want = append(want, 0x10)
+	want = binary.AppendUvarint(want, uint64(digest.SentAt.UnixMicro()))
+	assert.Equal(t, want, data[6:])
+}
+
+func TestNewSymbolEventEncoder(t *testing.T) {
+	t.Run("encodes JSON without a registry", func(t *testing.T) {
+		encoder, err := schema.NewSymbolEventEncoder(domain.EventFormatJSON, nil, "")
+		require.NoError(t, err)
+		assert.Equal(t, "application/json", encoder.ContentType())
+
+		data, err := encoder.EncodeSymbolEvents(context.Background(), testDigest())
+		require.NoError(t, err)
+		assert.Contains(t, string(data), `"symbol":"BTCUSDT"`)
+	})
+
+	t.Run("requires a registry for schema'd formats", func(t *testing.T) {
+		_, err := schema.NewSymbolEventEncoder(domain.EventFormatAvro, nil, "symbol-events-value")
+		assert.Error(t, err)
+	})
+
+	t.Run("fails when the registry rejects the schema", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusConflict)
+		}))
+		defer server.Close()
+
+		encoder, err := schema.NewSymbolEventEncoder(domain.EventFormatAvro, schema.NewRegistry(server.URL, time.Second), "symbol-events-value")
+		require.NoError(t, err)
+
+		_, err = encoder.EncodeSymbolEvents(context.Background(), testDigest())
+		assert.ErrorIs(t, err, domain.ErrInvalidResponse)
+	})
+}
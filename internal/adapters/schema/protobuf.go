@@ -0,0 +1,97 @@
+package schema
+
+import (
+	"context"
+	"encoding/binary"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+)
+
+// SymbolEventDigestProto is the Protobuf schema of symbol event digests.
+// Event details vary by type, so they are carried as a JSON object string.
+const SymbolEventDigestProto = `syntax = "proto3";
+package pricesnapshot.events;
+
+message SymbolEventDigest {
+  repeated SymbolEvent events = 1;
+  int64 sent_at_micros = 2;
+}
+
+message SymbolEvent {
+  int64 id = 1;
+  string symbol = 2;
+  string type = 3;
+  string details_json = 4;
+  int64 created_at_micros = 5;
+}
+`
+
+const (
+	protoVarint = 0
+	protoBytes  = 2
+)
+
+// ProtobufEncoder encodes digests as SymbolEventDigest messages framed with
+// their registry schema ID
+type ProtobufEncoder struct {
+	registry *Registry
+	subject  string
+}
+
+// ContentType is the media type of encoded digests
+func (e *ProtobufEncoder) ContentType() string {
+	return "application/x-protobuf"
+}
+
+// EncodeSymbolEvents serializes a digest as SymbolEventDigestProto
+func (e *ProtobufEncoder) EncodeSymbolEvents(ctx context.Context, digest *domain.SymbolEventDigest) ([]byte, error) {
+	id, err := e.registry.SchemaID(ctx, e.subject, "PROTOBUF", SymbolEventDigestProto)
+	if err != nil {
+		return nil, err
+	}
+
+	// After the schema ID comes the path to the message in the schema;
+	// SymbolEventDigest is the first message, written as a single 0
+	buf := make([]byte, 0, 64*len(digest.Events))
+	buf = append(buf, 0)
+
+	for _, event := range digest.Events {
+		details, err := detailsJSON(event)
+		if err != nil {
+			return nil, err
+		}
+
+		var msg []byte
+		msg = appendProtoInt(msg, 1, event.ID)
+		msg = appendProtoString(msg, 2, event.Symbol)
+		msg = appendProtoString(msg, 3, string(event.Type))
+		msg = appendProtoString(msg, 4, details)
+		msg = appendProtoInt(msg, 5, event.CreatedAt.UnixMicro())
+
+		buf = binary.AppendUvarint(buf, 1<<3|protoBytes)
+		buf = binary.AppendUvarint(buf, uint64(len(msg)))
+		buf = append(buf, msg...)
+	}
+	buf = appendProtoInt(buf, 2, digest.SentAt.UnixMicro())
+
+	return frame(id, buf), nil
+}
+
+// appendProtoInt appends an int64 field, leaving out the zero default
+func appendProtoInt(buf []byte, field int, v int64) []byte {
+	if v == 0 {
+		return buf
+	}
+	buf = binary.AppendUvarint(buf, uint64(field)<<3|protoVarint)
+	return binary.AppendUvarint(buf, uint64(v))
+}
+
+// appendProtoString appends a string field, leaving out the empty default
+func appendProtoString(buf []byte, field int, s string) []byte {
+	if s == "" {
+		return buf
+	}
+	buf = binary.AppendUvarint(buf, uint64(field)<<3|protoBytes)
+	buf = binary.AppendUvarint(buf, uint64(len(s)))
+	return append(buf, s...)
+}
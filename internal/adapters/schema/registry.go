@@ -0,0 +1,107 @@
+package schema
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+)
+
+const (
+	registryContentType = "application/vnd.schemaregistry.v1+json"
+
+	// magicByte starts every payload framed with a registry schema ID
+	magicByte = 0
+)
+
+// Registry registers schemas with a Confluent-compatible schema registry
+// and caches the IDs it assigns
+type Registry struct {
+	baseURL    string
+	httpClient *http.Client
+
+	mu  sync.Mutex
+	ids map[string]int32
+}
+
+// NewRegistry creates a registry client for baseURL
+func NewRegistry(baseURL string, timeout time.Duration) *Registry {
+	return &Registry{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{Timeout: timeout},
+		ids:        make(map[string]int32),
+	}
+}
+
+// registerRequest is the body of a schema registration
+type registerRequest struct {
+	Schema     string `json:"schema"`
+	SchemaType string `json:"schemaType,omitempty"`
+}
+
+// SchemaID registers schema under subject, unless it already was, and
+// returns its ID. Registering a schema the subject already has returns the
+// existing ID; an incompatible schema is rejected by the registry.
+func (r *Registry) SchemaID(ctx context.Context, subject, schemaType, schema string) (int32, error) {
+	key := subject + "\x00" + schema
+
+	r.mu.Lock()
+	id, ok := r.ids[key]
+	r.mu.Unlock()
+	if ok {
+		return id, nil
+	}
+
+	body, err := json.Marshal(registerRequest{Schema: schema, SchemaType: schemaType})
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode schema: %w", err)
+	}
+
+	endpoint := r.baseURL + "/subjects/" + url.PathEscape(subject) + "/versions"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", registryContentType)
+	req.Header.Set("Accept", registryContentType)
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to register schema: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("%w: schema registry returned status %d for subject %s", domain.ErrInvalidResponse, resp.StatusCode, subject)
+	}
+
+	var registered struct {
+		ID int32 `json:"id"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&registered); err != nil {
+		return 0, fmt.Errorf("%w: %v", domain.ErrInvalidResponse, err)
+	}
+
+	r.mu.Lock()
+	r.ids[key] = registered.ID
+	r.mu.Unlock()
+
+	return registered.ID, nil
+}
+
+// frame prefixes payload with the registry wire format header: the magic
+// byte and the big-endian schema ID
+func frame(id int32, payload []byte) []byte {
+	framed := make([]byte, 5, 5+len(payload))
+	framed[0] = magicByte
+	binary.BigEndian.PutUint32(framed[1:], uint32(id))
+	return append(framed, payload...)
+}
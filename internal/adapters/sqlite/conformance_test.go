@@ -0,0 +1,43 @@
+package sqlite_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/adapters/sqlite"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports/repotest"
+)
+
+// openDB opens and migrates a database file in a fresh temporary directory
+func openDB(t *testing.T) *sqlite.DB {
+	t.Helper()
+	return openDBAt(t, filepath.Join(t.TempDir(), "snapshots.db"))
+}
+
+// openDBAt opens and migrates the database file at path
+func openDBAt(t *testing.T, path string) *sqlite.DB {
+	t.Helper()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	db, err := sqlite.Open(context.Background(), path, logger)
+	require.NoError(t, err)
+	t.Cleanup(db.Close)
+
+	require.NoError(t, db.Migrate())
+	return db
+}
+
+func TestRepositories_Conformance(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) repotest.Repositories {
+		db := openDB(t)
+		return repotest.Repositories{
+			Symbols:   sqlite.NewSymbolRepository(db),
+			Snapshots: sqlite.NewSnapshotRepository(db),
+		}
+	})
+}
@@ -0,0 +1,168 @@
+// Package sqlite implements the repositories on a SQLite database file. It
+// backs STORAGE_DRIVER=sqlite, which runs the service without PostgreSQL,
+// e.g. for local development or an edge deployment with a single instance.
+// The driver is pure Go, so the binary needs no cgo or system library.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"time"
+
+	"github.com/golang-migrate/migrate/v4"
+	migratesqlite "github.com/golang-migrate/migrate/v4/database/sqlite"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+)
+
+// migrations are the SQLite schema migrations, kept apart from the
+// PostgreSQL ones as the dialects differ
+//
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+// busyTimeout is how long a statement waits for another process holding
+// the database file, e.g. a backup, before failing
+const busyTimeout = 5 * time.Second
+
+// DB wraps the SQLite database
+type DB struct {
+	db     *sql.DB
+	path   string
+	logger *slog.Logger
+}
+
+// Open opens the SQLite database at path, creating the file if it does not
+// exist. SQLite takes one writer at a time, so the service uses a single
+// connection and its statements queue up in database/sql instead of
+// failing with SQLITE_BUSY.
+func Open(ctx context.Context, path string, logger *slog.Logger) (*DB, error) {
+	pragmas := url.Values{}
+	pragmas.Add("_pragma", "foreign_keys(1)")
+	pragmas.Add("_pragma", "journal_mode(WAL)")
+	pragmas.Add("_pragma", fmt.Sprintf("busy_timeout(%d)", busyTimeout.Milliseconds()))
+
+	db, err := sql.Open("sqlite", "file:"+path+"?"+pragmas.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sqlite database: %w", err)
+	}
+	db.SetMaxOpenConns(1)
+
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to open sqlite database %s: %w", path, err)
+	}
+
+	logger.Info("sqlite database opened", "path", path)
+
+	return &DB{
+		db:     db,
+		path:   path,
+		logger: logger.With("component", "sqlite"),
+	}, nil
+}
+
+// Migrate applies the embedded migrations. A dirty schema is never
+// migrated: each migration runs in a transaction, so one is only left
+// dirty by a crash and needs fixing by hand.
+func (db *DB) Migrate() error {
+	db.logger.Info("running database migrations", "path", db.path)
+
+	src, err := iofs.New(migrations, "migrations")
+	if err != nil {
+		return fmt.Errorf("failed to read migrations: %w", err)
+	}
+	defer src.Close()
+
+	driver, err := migratesqlite.WithInstance(db.db, &migratesqlite.Config{})
+	if err != nil {
+		return fmt.Errorf("failed to create migration driver: %w", err)
+	}
+
+	// The migrator is not closed: that would close the database with it
+	m, err := migrate.NewWithInstance("iofs", src, "sqlite", driver)
+	if err != nil {
+		return fmt.Errorf("failed to create migrator: %w", err)
+	}
+
+	version, dirty, err := m.Version()
+	if err != nil && !errors.Is(err, migrate.ErrNilVersion) {
+		return fmt.Errorf("failed to get migration version: %w", err)
+	}
+	if dirty {
+		return fmt.Errorf("%w at version %d", domain.ErrSchemaDirty, version)
+	}
+
+	if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return fmt.Errorf("failed to run migrations: %w", err)
+	}
+
+	db.logger.Info("database migrations completed")
+	return nil
+}
+
+// Close closes the database
+func (db *DB) Close() {
+	db.db.Close()
+}
+
+// AnalyzeSnapshots runs ANALYZE on the snapshots table so the query
+// planner picks its indexes by the table's current size. SQLite keeps no
+// dead row counts, so only the live rows are reported.
+func (db *DB) AnalyzeSnapshots(ctx context.Context) ([]domain.TableStats, error) {
+	s := domain.TableStats{Table: "snapshots"}
+	if err := db.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM snapshots`).Scan(&s.LiveRows); err != nil {
+		return nil, fmt.Errorf("failed to count snapshots: %w", err)
+	}
+
+	if _, err := db.db.ExecContext(ctx, `ANALYZE snapshots`); err != nil {
+		return nil, fmt.Errorf("failed to analyze snapshots: %w", err)
+	}
+	return []domain.TableStats{s}, nil
+}
+
+// withTx runs fn in a transaction, committing when fn succeeds and rolling
+// back otherwise
+func (db *DB) withTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// rowsAffected returns the number of rows a statement changed
+func rowsAffected(result sql.Result) (int64, error) {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read affected rows: %w", err)
+	}
+	return affected, nil
+}
+
+// isUniqueViolation reports whether err is a UNIQUE constraint failure
+func isUniqueViolation(err error) bool {
+	var sqliteErr *sqlite.Error
+	return errors.As(err, &sqliteErr) && sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_UNIQUE
+}
+
+// Ensure DB implements ports.SnapshotTableAnalyzer
+var _ ports.SnapshotTableAnalyzer = (*DB)(nil)
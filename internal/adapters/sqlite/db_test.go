@@ -0,0 +1,28 @@
+package sqlite_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/adapters/sqlite"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+)
+
+func TestDB_MigrateKeepsData(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "snapshots.db")
+
+	symbols := sqlite.NewSymbolRepository(openDBAt(t, path))
+	symbol, err := domain.NewSymbol("BTCUSDT")
+	require.NoError(t, err)
+	require.NoError(t, symbols.Create(ctx, symbol))
+
+	// Reopening an up to date database is a no-op migration
+	stored, err := sqlite.NewSymbolRepository(openDBAt(t, path)).GetByName(ctx, "BTCUSDT")
+	require.NoError(t, err)
+	assert.Equal(t, symbol.ID, stored.ID)
+}
@@ -0,0 +1,343 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+)
+
+// JobRepository implements the ports.JobRepository interface. SQLite has
+// no updated_at trigger here, so every update sets updated_at itself,
+// which makes heartbeats and progress updates renew the lease.
+type JobRepository struct {
+	db *DB
+}
+
+// NewJobRepository creates a new SQLite job repository
+func NewJobRepository(db *DB) ports.JobRepository {
+	return &JobRepository{db: db}
+}
+
+const jobColumns = `
+	id, type, status, payload, progress, attempts, max_attempts,
+	COALESCE(error, ''), scheduled_at, started_at, finished_at, created_at, updated_at
+`
+
+// Create enqueues a new job
+func (r *JobRepository) Create(ctx context.Context, job *domain.Job) error {
+	query := `
+		INSERT INTO jobs (type, status, payload, max_attempts, scheduled_at, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		RETURNING id
+	`
+
+	var payload sql.NullString
+	if len(job.Payload) > 0 {
+		payload = sql.NullString{String: string(job.Payload), Valid: true}
+	}
+
+	err := r.db.db.QueryRowContext(ctx, query,
+		string(job.Type),
+		string(job.Status),
+		payload,
+		job.MaxAttempts,
+		micros(job.ScheduledAt),
+		micros(job.CreatedAt),
+		micros(job.UpdatedAt),
+	).Scan(&job.ID)
+
+	if err != nil {
+		return fmt.Errorf("failed to create job: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a job by its ID
+func (r *JobRepository) GetByID(ctx context.Context, id int64) (*domain.Job, error) {
+	query := `SELECT ` + jobColumns + ` FROM jobs WHERE id = ?`
+
+	job, err := scanJob(r.db.db.QueryRowContext(ctx, query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrJobNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job: %w", err)
+	}
+
+	return job, nil
+}
+
+// List returns the most recent jobs, optionally filtered by status
+func (r *JobRepository) List(ctx context.Context, status domain.JobStatus, limit int) ([]*domain.Job, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+	if limit > 1000 {
+		limit = 1000
+	}
+
+	query := `
+		SELECT ` + jobColumns + `
+		FROM jobs
+		WHERE ?1 = '' OR status = ?1
+		ORDER BY created_at DESC, id DESC
+		LIMIT ?2
+	`
+
+	rows, err := r.db.db.QueryContext(ctx, query, string(status), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list jobs: %w", err)
+	}
+	defer rows.Close()
+
+	var jobs []*domain.Job
+	for rows.Next() {
+		job, err := scanJob(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan job: %w", err)
+		}
+		jobs = append(jobs, job)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating jobs: %w", err)
+	}
+
+	return jobs, nil
+}
+
+// ClaimNext marks the next due pending job, or a running job whose
+// updated_at has not moved for staleAfter, as running. Stale jobs that have
+// used up their attempts are failed in the same transaction rather than
+// claimed, so a job that keeps killing its worker is not retried forever.
+// Returns nil when no job is due.
+func (r *JobRepository) ClaimNext(ctx context.Context, staleAfter time.Duration) (*domain.Job, error) {
+	var job *domain.Job
+	err := r.db.withTx(ctx, func(tx *sql.Tx) error {
+		claimedAt := micros(now())
+		staleBefore := claimedAt - staleAfter.Microseconds()
+
+		_, err := tx.ExecContext(ctx, `
+			UPDATE jobs
+			SET status = 'failed', error = ?, finished_at = ?, updated_at = ?
+			WHERE status = 'running' AND attempts >= max_attempts AND updated_at < ?
+		`, domain.JobLeaseExpiredError, claimedAt, claimedAt, staleBefore)
+		if err != nil {
+			return err
+		}
+
+		job, err = scanJob(tx.QueryRowContext(ctx, `
+			UPDATE jobs
+			SET status = 'running', attempts = attempts + 1, started_at = ?1, updated_at = ?1, error = NULL
+			WHERE id = (
+				SELECT id FROM jobs
+				WHERE (status = 'pending' AND scheduled_at <= ?1)
+					OR (status = 'running' AND attempts < max_attempts AND updated_at < ?2)
+				ORDER BY scheduled_at, id
+				LIMIT 1
+			)
+			RETURNING `+jobColumns, claimedAt, staleBefore))
+		if errors.Is(err, sql.ErrNoRows) {
+			job = nil
+			return nil
+		}
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim job: %w", err)
+	}
+
+	return job, nil
+}
+
+// Heartbeat renews the lease of a running job's attempt
+func (r *JobRepository) Heartbeat(ctx context.Context, id int64, attempt int) error {
+	query := `UPDATE jobs SET updated_at = ? WHERE id = ? AND status = 'running' AND attempts = ?`
+
+	result, err := r.db.db.ExecContext(ctx, query, micros(now()), id, attempt)
+	if err != nil {
+		return fmt.Errorf("failed to record job heartbeat: %w", err)
+	}
+
+	return r.leased(ctx, result, id)
+}
+
+// UpdateProgress stores the progress of a running job's attempt
+func (r *JobRepository) UpdateProgress(ctx context.Context, id int64, attempt int, progress domain.JobProgress) error {
+	data, err := json.Marshal(progress)
+	if err != nil {
+		return fmt.Errorf("failed to encode job progress: %w", err)
+	}
+
+	query := `UPDATE jobs SET progress = ?, updated_at = ? WHERE id = ? AND status = 'running' AND attempts = ?`
+
+	result, err := r.db.db.ExecContext(ctx, query, string(data), micros(now()), id, attempt)
+	if err != nil {
+		return fmt.Errorf("failed to update job progress: %w", err)
+	}
+
+	return r.leased(ctx, result, id)
+}
+
+// Finish stores a job's final status, error and schedule
+func (r *JobRepository) Finish(ctx context.Context, job *domain.Job) error {
+	query := `
+		UPDATE jobs
+		SET status = ?, progress = ?, error = NULLIF(?, ''), scheduled_at = ?, finished_at = ?, updated_at = ?
+		WHERE id = ? AND status = 'running' AND attempts = ?
+	`
+
+	progress, err := json.Marshal(job.Progress)
+	if err != nil {
+		return fmt.Errorf("failed to encode job progress: %w", err)
+	}
+
+	result, err := r.db.db.ExecContext(ctx, query,
+		string(job.Status),
+		string(progress),
+		job.Error,
+		micros(job.ScheduledAt),
+		nullMicros(job.FinishedAt),
+		micros(now()),
+		job.ID,
+		job.Attempts,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to finish job: %w", err)
+	}
+
+	if err := r.leased(ctx, result, job.ID); !errors.Is(err, domain.ErrJobCancelled) {
+		return err
+	}
+
+	return nil
+}
+
+// Cancel marks a pending or running job as cancelled
+func (r *JobRepository) Cancel(ctx context.Context, id int64) error {
+	query := `
+		UPDATE jobs
+		SET status = 'cancelled', finished_at = ?1, updated_at = ?1
+		WHERE id = ?2 AND status IN ('pending', 'running')
+	`
+
+	result, err := r.db.db.ExecContext(ctx, query, micros(now()), id)
+	if err != nil {
+		return fmt.Errorf("failed to cancel job: %w", err)
+	}
+
+	return r.changedOr(ctx, result, id, domain.ErrJobFinished)
+}
+
+// Requeue makes a cancelled or failed job pending again, keeping its progress
+func (r *JobRepository) Requeue(ctx context.Context, id int64) error {
+	query := `
+		UPDATE jobs
+		SET status = 'pending', attempts = 0, error = NULL, scheduled_at = ?1, finished_at = NULL, updated_at = ?1
+		WHERE id = ?2 AND status IN ('cancelled', 'failed')
+	`
+
+	result, err := r.db.db.ExecContext(ctx, query, micros(now()), id)
+	if err != nil {
+		return fmt.Errorf("failed to requeue job: %w", err)
+	}
+
+	return r.changedOr(ctx, result, id, domain.ErrJobNotResumable)
+}
+
+// changedOr returns nil when a statement changed the job, otherwise
+// ErrJobNotFound if the job does not exist and fallback if it does
+func (r *JobRepository) changedOr(ctx context.Context, result sql.Result, id int64, fallback error) error {
+	affected, err := rowsAffected(result)
+	if err != nil || affected > 0 {
+		return err
+	}
+
+	var exists bool
+	if err := r.db.db.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM jobs WHERE id = ?)`, id).Scan(&exists); err != nil {
+		return fmt.Errorf("failed to check job existence: %w", err)
+	}
+	if !exists {
+		return domain.ErrJobNotFound
+	}
+	return fallback
+}
+
+// leased returns nil when an update fenced on a running attempt changed
+// the job, otherwise why it matched no row: the job is gone, was
+// cancelled, or another worker claimed it
+func (r *JobRepository) leased(ctx context.Context, result sql.Result, id int64) error {
+	affected, err := rowsAffected(result)
+	if err != nil || affected > 0 {
+		return err
+	}
+
+	var status domain.JobStatus
+	err = r.db.db.QueryRowContext(ctx, `SELECT status FROM jobs WHERE id = ?`, id).Scan(&status)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.ErrJobNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to check job status: %w", err)
+	}
+	if status == domain.JobStatusCancelled {
+		return domain.ErrJobCancelled
+	}
+	return domain.ErrJobLeaseLost
+}
+
+func scanJob(row scanner) (*domain.Job, error) {
+	var (
+		job                               domain.Job
+		payload, progress                 sql.NullString
+		scheduledAt, createdAt, updatedAt int64
+		startedAt, finishedAt             sql.NullInt64
+	)
+
+	err := row.Scan(
+		&job.ID,
+		&job.Type,
+		&job.Status,
+		&payload,
+		&progress,
+		&job.Attempts,
+		&job.MaxAttempts,
+		&job.Error,
+		&scheduledAt,
+		&startedAt,
+		&finishedAt,
+		&createdAt,
+		&updatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	job.ScheduledAt = timeAt(scheduledAt)
+	job.StartedAt = nullTimeAt(startedAt)
+	job.FinishedAt = nullTimeAt(finishedAt)
+	job.CreatedAt = timeAt(createdAt)
+	job.UpdatedAt = timeAt(updatedAt)
+
+	if payload.Valid && payload.String != "" {
+		job.Payload = json.RawMessage(payload.String)
+	}
+
+	if progress.Valid && progress.String != "" {
+		if err := json.Unmarshal([]byte(progress.String), &job.Progress); err != nil {
+			return nil, fmt.Errorf("failed to decode job progress: %w", err)
+		}
+	}
+
+	return &job, nil
+}
+
+// Ensure JobRepository implements ports.JobRepository
+var _ ports.JobRepository = (*JobRepository)(nil)
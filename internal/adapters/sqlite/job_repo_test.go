@@ -0,0 +1,101 @@
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/adapters/sqlite"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+)
+
+func TestJobRepository_ClaimNextReclaimsStaleJobs(t *testing.T) {
+	ctx := context.Background()
+	repo := sqlite.NewJobRepository(openDB(t))
+
+	job, err := domain.NewJob(domain.JobTypePrune, nil, time.Time{}, 3)
+	require.NoError(t, err)
+	require.NoError(t, repo.Create(ctx, job))
+
+	claimed, err := repo.ClaimNext(ctx, time.Hour)
+	require.NoError(t, err)
+	require.NotNil(t, claimed)
+	assert.Equal(t, 1, claimed.Attempts)
+
+	again, err := repo.ClaimNext(ctx, time.Hour)
+	require.NoError(t, err)
+	assert.Nil(t, again, "a running job within its lease is not claimed")
+
+	require.NoError(t, repo.Heartbeat(ctx, job.ID, claimed.Attempts))
+	time.Sleep(time.Millisecond)
+
+	reclaimed, err := repo.ClaimNext(ctx, time.Microsecond)
+	require.NoError(t, err)
+	require.NotNil(t, reclaimed, "a running job past its lease is claimed again")
+	assert.Equal(t, job.ID, reclaimed.ID)
+	assert.Equal(t, 2, reclaimed.Attempts)
+
+	require.NoError(t, repo.Cancel(ctx, job.ID))
+	assert.ErrorIs(t, repo.Heartbeat(ctx, job.ID, reclaimed.Attempts), domain.ErrJobCancelled)
+}
+
+func TestJobRepository_FencesStaleAttempts(t *testing.T) {
+	ctx := context.Background()
+	repo := sqlite.NewJobRepository(openDB(t))
+
+	job, err := domain.NewJob(domain.JobTypePrune, nil, time.Time{}, 3)
+	require.NoError(t, err)
+	require.NoError(t, repo.Create(ctx, job))
+
+	first, err := repo.ClaimNext(ctx, time.Hour)
+	require.NoError(t, err)
+	time.Sleep(time.Millisecond)
+	second, err := repo.ClaimNext(ctx, time.Microsecond)
+	require.NoError(t, err)
+	require.NotNil(t, second)
+
+	// The first worker is still alive and does not know it lost the job
+	assert.ErrorIs(t, repo.Heartbeat(ctx, job.ID, first.Attempts), domain.ErrJobLeaseLost)
+	assert.ErrorIs(t, repo.UpdateProgress(ctx, job.ID, first.Attempts, domain.JobProgress{Processed: 5}), domain.ErrJobLeaseLost)
+	first.Status = domain.JobStatusSucceeded
+	assert.ErrorIs(t, repo.Finish(ctx, first), domain.ErrJobLeaseLost)
+
+	second.Progress = domain.JobProgress{Processed: 2}
+	require.NoError(t, repo.UpdateProgress(ctx, job.ID, second.Attempts, second.Progress))
+	second.Status = domain.JobStatusSucceeded
+	require.NoError(t, repo.Finish(ctx, second))
+
+	stored, err := repo.GetByID(ctx, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.JobStatusSucceeded, stored.Status)
+	assert.Equal(t, int64(2), stored.Progress.Processed)
+}
+
+func TestJobRepository_ClaimNextFailsStaleJobsOutOfAttempts(t *testing.T) {
+	ctx := context.Background()
+	repo := sqlite.NewJobRepository(openDB(t))
+
+	job, err := domain.NewJob(domain.JobTypePrune, nil, time.Time{}, 1)
+	require.NoError(t, err)
+	require.NoError(t, repo.Create(ctx, job))
+
+	claimed, err := repo.ClaimNext(ctx, time.Hour)
+	require.NoError(t, err)
+	require.NotNil(t, claimed)
+	time.Sleep(time.Millisecond)
+
+	// The worker died during the only attempt
+	reclaimed, err := repo.ClaimNext(ctx, time.Microsecond)
+	require.NoError(t, err)
+	assert.Nil(t, reclaimed)
+
+	stored, err := repo.GetByID(ctx, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.JobStatusFailed, stored.Status)
+	assert.Equal(t, 1, stored.Attempts)
+	assert.Equal(t, domain.JobLeaseExpiredError, stored.Error)
+	assert.NotNil(t, stored.FinishedAt)
+}
@@ -0,0 +1,9 @@
+-- Crypto Snapshot Service - Rollback SQLite Schema
+-- Drops all tables created in the up migration
+
+-- Drop tables (snapshots before symbols due to the foreign key)
+DROP TABLE IF EXISTS jobs;
+DROP TABLE IF EXISTS symbol_event_cursors;
+DROP TABLE IF EXISTS symbol_events;
+DROP TABLE IF EXISTS snapshots;
+DROP TABLE IF EXISTS symbols;
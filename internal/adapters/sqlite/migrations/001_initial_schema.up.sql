@@ -0,0 +1,85 @@
+-- Crypto Snapshot Service - SQLite Schema
+-- Creates the tables of the sqlite storage driver: symbols, snapshots,
+-- symbol events and jobs. Timestamps are stored as Unix microseconds, the
+-- precision PostgreSQL keeps, so they compare and sort as integers. Prices
+-- are decimal strings, so no precision is lost.
+
+-- Symbols table: stores tracked cryptocurrency symbols; tags is a JSON array
+CREATE TABLE IF NOT EXISTS symbols (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL UNIQUE,
+    exchange TEXT NOT NULL,
+    active INTEGER NOT NULL DEFAULT 1,
+    tags TEXT NOT NULL DEFAULT '[]',
+    display_name TEXT,
+    logo_url TEXT,
+    market_cap TEXT,
+    metadata_updated_at INTEGER,
+    created_at INTEGER NOT NULL,
+    updated_at INTEGER NOT NULL,
+    deleted_at INTEGER
+);
+
+-- Snapshots table: stores price snapshots
+CREATE TABLE IF NOT EXISTS snapshots (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    symbol_id INTEGER NOT NULL REFERENCES symbols(id) ON DELETE CASCADE,
+    symbol TEXT NOT NULL,
+    price TEXT NOT NULL,
+    timestamp INTEGER NOT NULL,
+    ts_source TEXT NOT NULL DEFAULT 'poll',
+    source TEXT,
+    fetch_latency_ms INTEGER,
+    poll_run_id TEXT,
+    exchange TEXT,
+    ingest_mode TEXT,
+    ingest_latency_ms INTEGER,
+    pipeline_version TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_snapshots_symbol_timestamp ON snapshots(symbol, timestamp DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_snapshots_timestamp ON snapshots(timestamp);
+CREATE INDEX IF NOT EXISTS idx_snapshots_symbol_id ON snapshots(symbol_id);
+
+-- A poll run stores each symbol once, and a pushed price once per source
+CREATE UNIQUE INDEX IF NOT EXISTS idx_snapshots_poll_run ON snapshots(poll_run_id, symbol_id)
+    WHERE poll_run_id IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_snapshots_source ON snapshots(symbol, timestamp, source)
+    WHERE source IS NOT NULL;
+
+-- Symbol events table: records symbol changes; details is a JSON object
+CREATE TABLE IF NOT EXISTS symbol_events (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    symbol TEXT NOT NULL,
+    type TEXT NOT NULL,
+    details TEXT,
+    created_at INTEGER NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_symbol_events_symbol_created ON symbol_events(symbol, created_at DESC, id DESC);
+
+-- Symbol event cursors table: the last event delivered to each subscriber
+CREATE TABLE IF NOT EXISTS symbol_event_cursors (
+    subscriber TEXT PRIMARY KEY,
+    last_event_id INTEGER NOT NULL,
+    updated_at INTEGER NOT NULL
+);
+
+-- Jobs table: background job queue; payload and progress are JSON
+CREATE TABLE IF NOT EXISTS jobs (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    type TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending',
+    payload TEXT,
+    progress TEXT,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    max_attempts INTEGER NOT NULL DEFAULT 3,
+    error TEXT,
+    scheduled_at INTEGER NOT NULL,
+    started_at INTEGER,
+    finished_at INTEGER,
+    created_at INTEGER NOT NULL,
+    updated_at INTEGER NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_jobs_status_scheduled ON jobs(status, scheduled_at, id);
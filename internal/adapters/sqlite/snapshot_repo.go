@@ -0,0 +1,651 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+)
+
+// SnapshotRepository implements the ports.SnapshotRepository interface.
+// Snapshots are never compressed, so there are no chunks to merge into
+// history reads.
+type SnapshotRepository struct {
+	db *DB
+}
+
+// NewSnapshotRepository creates a new SQLite snapshot repository
+func NewSnapshotRepository(db *DB) ports.SnapshotRepository {
+	return &SnapshotRepository{db: db}
+}
+
+// insertPolled inserts a snapshot unless its poll run already stored its
+// symbol, returning the stored row's ID either way
+const insertPolled = `
+	INSERT INTO snapshots (
+		symbol_id, symbol, price, timestamp, ts_source, fetch_latency_ms, poll_run_id, exchange,
+		ingest_mode, ingest_latency_ms, pipeline_version
+	)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	ON CONFLICT (poll_run_id, symbol_id) WHERE poll_run_id IS NOT NULL
+	DO UPDATE SET poll_run_id = excluded.poll_run_id
+	RETURNING id
+`
+
+// insertPolledArgs returns the arguments of insertPolled for a snapshot
+func insertPolledArgs(snapshot *domain.PriceSnapshot) []any {
+	return []any{
+		snapshot.SymbolID,
+		snapshot.Symbol,
+		snapshot.Price.String(),
+		micros(snapshot.Timestamp),
+		timestampSource(snapshot),
+		nullInt64(snapshot.FetchLatencyMs),
+		nullString(snapshot.PollRunID),
+		nullString(snapshot.Exchange),
+		nullString(string(snapshot.IngestMode)),
+		nullInt64(snapshot.IngestLatencyMs),
+		nullString(snapshot.PipelineVersion),
+	}
+}
+
+// Create stores a new price snapshot. A polled snapshot whose run already
+// stored its symbol is not stored again; the stored row's ID is returned.
+func (r *SnapshotRepository) Create(ctx context.Context, snapshot *domain.PriceSnapshot) error {
+	err := r.db.db.QueryRowContext(ctx, insertPolled, insertPolledArgs(snapshot)...).Scan(&snapshot.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create snapshot: %w", err)
+	}
+
+	return nil
+}
+
+// CreateBatch stores multiple snapshots atomically. Like Create, it skips
+// snapshots their poll run already stored, so a retried write is a no-op.
+func (r *SnapshotRepository) CreateBatch(ctx context.Context, snapshots []*domain.PriceSnapshot) error {
+	if len(snapshots) == 0 {
+		return nil
+	}
+
+	return r.db.withTx(ctx, func(tx *sql.Tx) error {
+		for _, snapshot := range snapshots {
+			err := tx.QueryRowContext(ctx, insertPolled, insertPolledArgs(snapshot)...).Scan(&snapshot.ID)
+			if err != nil {
+				return fmt.Errorf("failed to create snapshot for %s: %w", snapshot.Symbol, err)
+			}
+		}
+		return nil
+	})
+}
+
+// CreateBatchPartial stores every snapshot it can. Each row is inserted
+// under its own savepoint, so a row violating a constraint is rolled back
+// and reported without discarding the others.
+func (r *SnapshotRepository) CreateBatchPartial(ctx context.Context, snapshots []*domain.PriceSnapshot) (*domain.BatchWriteResult, error) {
+	if len(snapshots) == 0 {
+		return &domain.BatchWriteResult{}, nil
+	}
+
+	result := &domain.BatchWriteResult{}
+	err := r.db.withTx(ctx, func(tx *sql.Tx) error {
+		for _, snapshot := range snapshots {
+			if _, err := tx.ExecContext(ctx, `SAVEPOINT snapshot`); err != nil {
+				return fmt.Errorf("failed to create savepoint: %w", err)
+			}
+
+			err := tx.QueryRowContext(ctx, insertPolled, insertPolledArgs(snapshot)...).Scan(&snapshot.ID)
+			if err != nil {
+				if _, rbErr := tx.ExecContext(ctx, `ROLLBACK TO snapshot`); rbErr != nil {
+					return fmt.Errorf("failed to roll back snapshot for %s: %w", snapshot.Symbol, rbErr)
+				}
+				// A cancelled or timed out batch is not a row failure
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				snapshot.ID = 0
+				result.Failed = append(result.Failed, domain.BatchWriteFailure{
+					Symbol: snapshot.Symbol,
+					Error:  err.Error(),
+				})
+			} else {
+				result.Written++
+			}
+
+			if _, err := tx.ExecContext(ctx, `RELEASE snapshot`); err != nil {
+				return fmt.Errorf("failed to release savepoint: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// CreateBatchIngested stores pushed snapshots atomically. A snapshot whose
+// symbol, timestamp and source are already stored is skipped, which makes
+// retried pushes idempotent.
+func (r *SnapshotRepository) CreateBatchIngested(ctx context.Context, snapshots []*domain.PriceSnapshot) (int, error) {
+	if len(snapshots) == 0 {
+		return 0, nil
+	}
+
+	query := `
+		INSERT INTO snapshots (symbol_id, symbol, price, timestamp, ts_source, source)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT (symbol, timestamp, source) WHERE source IS NOT NULL DO NOTHING
+	`
+
+	stored := 0
+	err := r.db.withTx(ctx, func(tx *sql.Tx) error {
+		for _, snapshot := range snapshots {
+			result, err := tx.ExecContext(ctx, query,
+				snapshot.SymbolID,
+				snapshot.Symbol,
+				snapshot.Price.String(),
+				micros(snapshot.Timestamp),
+				timestampSource(snapshot),
+				nullString(snapshot.Source),
+			)
+			if err != nil {
+				return fmt.Errorf("failed to ingest snapshot for %s: %w", snapshot.Symbol, err)
+			}
+			affected, err := rowsAffected(result)
+			if err != nil {
+				return err
+			}
+			stored += int(affected)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return stored, nil
+}
+
+// GetLatestBySymbol returns the most recent snapshot for a symbol
+func (r *SnapshotRepository) GetLatestBySymbol(ctx context.Context, symbolName string) (*domain.PriceSnapshot, error) {
+	query := `
+		SELECT ` + snapshotColumns + `
+		FROM snapshots
+		WHERE symbol = ?
+		ORDER BY timestamp DESC, id DESC
+		LIMIT 1
+	`
+
+	snapshot, err := scanSnapshot(r.db.db.QueryRowContext(ctx, query, symbolName))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrSnapshotNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest snapshot: %w", err)
+	}
+
+	return snapshot, nil
+}
+
+// GetLatestBySymbols returns the most recent snapshot for multiple symbols,
+// ordered by symbol
+func (r *SnapshotRepository) GetLatestBySymbols(ctx context.Context, symbolNames []string) ([]*domain.PriceSnapshot, error) {
+	return r.nthNewest(ctx, symbolNames, 0, "failed to get latest snapshots")
+}
+
+// GetPreviousBySymbols returns the snapshot before the most recent one for
+// multiple symbols, ordered by symbol
+func (r *SnapshotRepository) GetPreviousBySymbols(ctx context.Context, symbolNames []string) ([]*domain.PriceSnapshot, error) {
+	return r.nthNewest(ctx, symbolNames, 1, "failed to get previous snapshots")
+}
+
+// nthNewest returns the snapshot n places before the newest of each named
+// symbol that has one, ordered by symbol. Each symbol's lookup walks its
+// (symbol, timestamp) index instead of scanning its history.
+func (r *SnapshotRepository) nthNewest(ctx context.Context, symbolNames []string, n int, failure string) ([]*domain.PriceSnapshot, error) {
+	if len(symbolNames) == 0 {
+		return nil, nil
+	}
+
+	names, err := json.Marshal(symbolNames)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode symbols: %w", err)
+	}
+
+	query := `
+		SELECT ` + snapshotColumns + `
+		FROM (SELECT DISTINCT value AS name FROM json_each(?)) n
+		JOIN snapshots ON snapshots.id = (
+			SELECT id FROM snapshots
+			WHERE symbol = n.name
+			ORDER BY timestamp DESC, id DESC
+			LIMIT 1 OFFSET ?
+		)
+		ORDER BY symbol
+	`
+
+	return r.query(ctx, failure, query, string(names), n)
+}
+
+// GetHistory returns historical snapshots for a symbol, newest first,
+// continuing after before when it is set
+func (r *SnapshotRepository) GetHistory(ctx context.Context, symbolName string, before *domain.HistoryCursor, limit int) ([]*domain.PriceSnapshot, error) {
+	query := `
+		SELECT ` + snapshotColumns + `
+		FROM snapshots
+		WHERE symbol = ?1
+			AND (?2 IS NULL OR (timestamp, id) < (?2, ?3))
+		ORDER BY timestamp DESC, id DESC
+		LIMIT ?4
+	`
+
+	beforeTs, beforeID := cursorArgs(before)
+	return r.query(ctx, "failed to get history", query, symbolName, beforeTs, beforeID, historyLimit(limit))
+}
+
+// GetHistoryBetween returns snapshots within a time range, both ends
+// included, newest first, continuing after before when it is set
+func (r *SnapshotRepository) GetHistoryBetween(ctx context.Context, symbolName string, from, to time.Time, before *domain.HistoryCursor, limit int) ([]*domain.PriceSnapshot, error) {
+	query := `
+		SELECT ` + snapshotColumns + `
+		FROM snapshots
+		WHERE symbol = ?1 AND timestamp >= ?2 AND timestamp <= ?3
+			AND (?4 IS NULL OR (timestamp, id) < (?4, ?5))
+		ORDER BY timestamp DESC, id DESC
+		LIMIT ?6
+	`
+
+	beforeTs, beforeID := cursorArgs(before)
+	return r.query(ctx, "failed to get history between", query,
+		symbolName, micros(from), micros(to), beforeTs, beforeID, historyLimit(limit))
+}
+
+// EachBetween calls fn with a symbol's snapshots in [from, to), oldest
+// first. The range is read out first: the database has one connection,
+// which fn may need to use the repositories.
+func (r *SnapshotRepository) EachBetween(ctx context.Context, symbolName string, from, to time.Time, fn func(*domain.PriceSnapshot) error) error {
+	query := `
+		SELECT ` + snapshotColumns + `
+		FROM snapshots
+		WHERE symbol = ? AND timestamp >= ? AND timestamp < ?
+		ORDER BY timestamp, id
+	`
+
+	snapshots, err := r.query(ctx, "failed to scan snapshots between", query, symbolName, micros(from), micros(to))
+	if err != nil {
+		return err
+	}
+
+	for _, s := range snapshots {
+		if err := fn(s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetPriceChanges returns the price movement of each active symbol between
+// its first snapshot since the given time and its latest snapshot
+func (r *SnapshotRepository) GetPriceChanges(ctx context.Context, since time.Time) ([]*domain.PriceChange, error) {
+	query := `
+		WITH ranked AS (
+			SELECT symbol, price, timestamp,
+				ROW_NUMBER() OVER (PARTITION BY symbol ORDER BY timestamp, id) AS first_rank,
+				ROW_NUMBER() OVER (PARTITION BY symbol ORDER BY timestamp DESC, id DESC) AS last_rank
+			FROM snapshots
+			WHERE timestamp >= ?
+		)
+		SELECT first.symbol, first.price, last.price, first.timestamp, last.timestamp
+		FROM ranked first
+		JOIN ranked last ON last.symbol = first.symbol AND last.last_rank = 1
+		JOIN symbols ON symbols.name = first.symbol AND symbols.active = 1
+		WHERE first.first_rank = 1
+		ORDER BY first.symbol
+	`
+
+	rows, err := r.db.db.QueryContext(ctx, query, micros(since))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get price changes: %w", err)
+	}
+	defer rows.Close()
+
+	var changes []*domain.PriceChange
+	for rows.Next() {
+		var symbol, openStr, closeStr string
+		var from, to int64
+
+		if err := rows.Scan(&symbol, &openStr, &closeStr, &from, &to); err != nil {
+			return nil, fmt.Errorf("failed to scan price change: %w", err)
+		}
+
+		open, err := parsePrice(openStr)
+		if err != nil {
+			return nil, err
+		}
+		closePrice, err := parsePrice(closeStr)
+		if err != nil {
+			return nil, err
+		}
+
+		changes = append(changes, domain.NewPriceChange(symbol, open, closePrice, timeAt(from), timeAt(to)))
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating price changes: %w", err)
+	}
+
+	return changes, nil
+}
+
+// Count returns total number of snapshots
+func (r *SnapshotRepository) Count(ctx context.Context) (int64, error) {
+	var count int64
+	if err := r.db.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM snapshots`).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count snapshots: %w", err)
+	}
+
+	return count, nil
+}
+
+// CountBySymbol returns number of snapshots for a symbol
+func (r *SnapshotRepository) CountBySymbol(ctx context.Context, symbolName string) (int64, error) {
+	query := `SELECT COUNT(*) FROM snapshots WHERE symbol = ?`
+
+	var count int64
+	if err := r.db.db.QueryRowContext(ctx, query, symbolName).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count snapshots by symbol: %w", err)
+	}
+
+	return count, nil
+}
+
+// GetHistorySummary returns the number of snapshots stored for a symbol and
+// the timestamps of its oldest and newest
+func (r *SnapshotRepository) GetHistorySummary(ctx context.Context, symbolName string) (*domain.HistorySummary, error) {
+	query := `SELECT COUNT(*), MIN(timestamp), MAX(timestamp) FROM snapshots WHERE symbol = ?`
+
+	summary := &domain.HistorySummary{}
+	var oldest, newest sql.NullInt64
+	if err := r.db.db.QueryRowContext(ctx, query, symbolName).Scan(&summary.TotalCount, &oldest, &newest); err != nil {
+		return nil, fmt.Errorf("failed to summarize history: %w", err)
+	}
+	summary.Oldest, summary.Newest = nullTimeAt(oldest), nullTimeAt(newest)
+
+	return summary, nil
+}
+
+// CountPerSymbol returns the number of snapshots of every symbol that has
+// any, ordered by symbol
+func (r *SnapshotRepository) CountPerSymbol(ctx context.Context) ([]domain.SymbolSnapshotCount, error) {
+	query := `SELECT symbol, COUNT(*) FROM snapshots GROUP BY symbol ORDER BY symbol`
+
+	rows, err := r.db.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count snapshots per symbol: %w", err)
+	}
+	defer rows.Close()
+
+	var counts []domain.SymbolSnapshotCount
+	for rows.Next() {
+		var c domain.SymbolSnapshotCount
+		if err := rows.Scan(&c.Symbol, &c.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan snapshot count: %w", err)
+		}
+		counts = append(counts, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating snapshot counts: %w", err)
+	}
+
+	return counts, nil
+}
+
+// Prune removes snapshots older than the given time
+func (r *SnapshotRepository) Prune(ctx context.Context, olderThan time.Time) (int64, error) {
+	result, err := r.db.db.ExecContext(ctx, `DELETE FROM snapshots WHERE timestamp < ?`, micros(olderThan))
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune snapshots: %w", err)
+	}
+
+	return rowsAffected(result)
+}
+
+// CompressBySymbol compresses nothing: SQLite has no chunk table, so
+// snapshots are kept as stored
+func (r *SnapshotRepository) CompressBySymbol(ctx context.Context, symbolName string, before time.Time, width time.Duration) (int, int64, error) {
+	return 0, 0, nil
+}
+
+// PruneBySymbol removes a single symbol's snapshots older than the given time
+func (r *SnapshotRepository) PruneBySymbol(ctx context.Context, symbolName string, olderThan time.Time) (int64, error) {
+	query := `DELETE FROM snapshots WHERE symbol = ? AND timestamp < ?`
+
+	result, err := r.db.db.ExecContext(ctx, query, symbolName, micros(olderThan))
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune snapshots for %s: %w", symbolName, err)
+	}
+
+	return rowsAffected(result)
+}
+
+// CountRange counts a symbol's snapshots in [from, to) as DeleteRange
+// would delete them
+func (r *SnapshotRepository) CountRange(ctx context.Context, symbolName string, from, to time.Time) (*domain.SnapshotRangeDeletion, error) {
+	query := `SELECT COUNT(*) FROM snapshots WHERE symbol = ? AND timestamp >= ? AND timestamp < ?`
+
+	var count int64
+	if err := r.db.db.QueryRowContext(ctx, query, symbolName, micros(from), micros(to)).Scan(&count); err != nil {
+		return nil, fmt.Errorf("failed to count snapshots in range: %w", err)
+	}
+
+	return &domain.SnapshotRangeDeletion{
+		Symbol:  symbolName,
+		From:    from,
+		To:      to,
+		DryRun:  true,
+		Deleted: count,
+	}, nil
+}
+
+// DeleteRange removes a symbol's snapshots in [from, to). Rows are deleted
+// batchSize at a time, each batch in its own statement, so a large range
+// does not keep other writers waiting.
+func (r *SnapshotRepository) DeleteRange(ctx context.Context, symbolName string, from, to time.Time, batchSize int) (*domain.SnapshotRangeDeletion, error) {
+	query := `
+		DELETE FROM snapshots
+		WHERE id IN (
+			SELECT id FROM snapshots
+			WHERE symbol = ? AND timestamp >= ? AND timestamp < ?
+			LIMIT ?
+		)
+	`
+
+	var deleted int64
+	for {
+		result, err := r.db.db.ExecContext(ctx, query, symbolName, micros(from), micros(to), batchSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to delete snapshots in range after %d rows: %w", deleted, err)
+		}
+		affected, err := rowsAffected(result)
+		if err != nil {
+			return nil, err
+		}
+		deleted += affected
+		if affected < int64(batchSize) {
+			break
+		}
+	}
+
+	return &domain.SnapshotRangeDeletion{
+		Symbol:  symbolName,
+		From:    from,
+		To:      to,
+		Deleted: deleted,
+	}, nil
+}
+
+// GetPollRun returns the snapshots a poll stored, ordered by symbol
+func (r *SnapshotRepository) GetPollRun(ctx context.Context, runID string) ([]*domain.PriceSnapshot, error) {
+	query := `
+		SELECT ` + snapshotColumns + `
+		FROM snapshots
+		WHERE poll_run_id = ?
+		ORDER BY symbol
+	`
+
+	return r.query(ctx, "failed to get poll run", query, runID)
+}
+
+// DeletePollRun removes the snapshots a poll stored
+func (r *SnapshotRepository) DeletePollRun(ctx context.Context, runID string) (int64, error) {
+	result, err := r.db.db.ExecContext(ctx, `DELETE FROM snapshots WHERE poll_run_id = ?`, runID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete poll run %s: %w", runID, err)
+	}
+
+	return rowsAffected(result)
+}
+
+// GetStorageStats returns the size of the database file along with
+// per-symbol snapshot counts. The file also holds the other tables, which
+// are small next to the snapshots.
+func (r *SnapshotRepository) GetStorageStats(ctx context.Context, since time.Time) (*domain.StorageStats, error) {
+	var stats domain.StorageStats
+
+	sizeQuery := `SELECT page_count * page_size FROM pragma_page_count(), pragma_page_size()`
+	if err := r.db.db.QueryRowContext(ctx, sizeQuery).Scan(&stats.TotalBytes); err != nil {
+		return nil, fmt.Errorf("failed to get database size: %w", err)
+	}
+
+	query := `
+		SELECT symbol, COUNT(*), SUM(timestamp >= ?), MIN(timestamp)
+		FROM snapshots
+		GROUP BY symbol
+		ORDER BY symbol
+	`
+
+	rows, err := r.db.db.QueryContext(ctx, query, micros(since))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get storage stats: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var s domain.SymbolStorage
+		var oldest int64
+		if err := rows.Scan(&s.Symbol, &s.Rows, &s.RecentRows, &oldest); err != nil {
+			return nil, fmt.Errorf("failed to scan storage stats: %w", err)
+		}
+		s.Oldest = timeAt(oldest)
+		stats.Symbols = append(stats.Symbols, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating storage stats: %w", err)
+	}
+
+	return &stats, nil
+}
+
+// query runs a query of snapshotColumns and returns its snapshots
+func (r *SnapshotRepository) query(ctx context.Context, failure string, query string, args ...any) ([]*domain.PriceSnapshot, error) {
+	rows, err := r.db.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", failure, err)
+	}
+	defer rows.Close()
+
+	var snapshots []*domain.PriceSnapshot
+	for rows.Next() {
+		s, err := scanSnapshot(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan snapshot: %w", err)
+		}
+		snapshots = append(snapshots, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating snapshots: %w", err)
+	}
+
+	return snapshots, nil
+}
+
+// snapshotColumns are the columns read by scanSnapshot, in order
+const snapshotColumns = `
+	snapshots.id, symbol_id, symbol, price, timestamp, ts_source, COALESCE(source, ''), fetch_latency_ms,
+	COALESCE(poll_run_id, ''), COALESCE(exchange, ''), COALESCE(ingest_mode, ''), ingest_latency_ms,
+	COALESCE(pipeline_version, '')
+`
+
+// scanSnapshot reads a row of snapshotColumns
+func scanSnapshot(row scanner) (*domain.PriceSnapshot, error) {
+	var (
+		s                           domain.PriceSnapshot
+		price                       string
+		timestamp                   int64
+		fetchLatency, ingestLatency sql.NullInt64
+	)
+	err := row.Scan(
+		&s.ID,
+		&s.SymbolID,
+		&s.Symbol,
+		&price,
+		&timestamp,
+		&s.TimestampSource,
+		&s.Source,
+		&fetchLatency,
+		&s.PollRunID,
+		&s.Exchange,
+		&s.IngestMode,
+		&ingestLatency,
+		&s.PipelineVersion,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	s.Timestamp = timeAt(timestamp)
+	s.FetchLatencyMs = int64At(fetchLatency)
+	s.IngestLatencyMs = int64At(ingestLatency)
+	if s.Price, err = parsePrice(price); err != nil {
+		return nil, err
+	}
+
+	return &s, nil
+}
+
+// historyLimit returns a history page size within the allowed range
+func historyLimit(limit int) int {
+	if limit <= 0 {
+		return 100
+	}
+	return min(limit, domain.MaxHistoryLimit)
+}
+
+// cursorArgs returns the query arguments of a history cursor, both NULL
+// when there is none
+func cursorArgs(before *domain.HistoryCursor) (sql.NullInt64, sql.NullInt64) {
+	if before == nil {
+		return sql.NullInt64{}, sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: micros(before.Timestamp), Valid: true}, sql.NullInt64{Int64: before.ID, Valid: true}
+}
+
+// timestampSource returns the snapshot's timestamp source, defaulting to poll
+func timestampSource(snapshot *domain.PriceSnapshot) string {
+	if snapshot.TimestampSource == "" {
+		return string(domain.TimestampSourcePoll)
+	}
+	return string(snapshot.TimestampSource)
+}
+
+// Ensure SnapshotRepository implements ports.SnapshotRepository
+var _ ports.SnapshotRepository = (*SnapshotRepository)(nil)
@@ -0,0 +1,169 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+)
+
+// SymbolEventRepository implements the ports.SymbolEventRepository interface
+type SymbolEventRepository struct {
+	db *DB
+}
+
+// NewSymbolEventRepository creates a new SQLite symbol event repository
+func NewSymbolEventRepository(db *DB) ports.SymbolEventRepository {
+	return &SymbolEventRepository{db: db}
+}
+
+// Create records a symbol change
+func (r *SymbolEventRepository) Create(ctx context.Context, event *domain.SymbolEvent) error {
+	query := `
+		INSERT INTO symbol_events (symbol, type, details, created_at)
+		VALUES (?, ?, ?, ?)
+		RETURNING id
+	`
+
+	var details sql.NullString
+	if event.Details != nil {
+		data, err := json.Marshal(event.Details)
+		if err != nil {
+			return fmt.Errorf("failed to encode symbol event details: %w", err)
+		}
+		details = sql.NullString{String: string(data), Valid: true}
+	}
+
+	err := r.db.db.QueryRowContext(ctx, query,
+		event.Symbol,
+		string(event.Type),
+		details,
+		micros(event.CreatedAt),
+	).Scan(&event.ID)
+
+	if err != nil {
+		return fmt.Errorf("failed to create symbol event: %w", err)
+	}
+
+	return nil
+}
+
+// ListBySymbol returns a symbol's most recent events, newest first
+func (r *SymbolEventRepository) ListBySymbol(ctx context.Context, symbolName string, limit int) ([]*domain.SymbolEvent, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+	if limit > 1000 {
+		limit = 1000
+	}
+
+	query := `
+		SELECT id, symbol, type, details, created_at
+		FROM symbol_events
+		WHERE symbol = ?
+		ORDER BY created_at DESC, id DESC
+		LIMIT ?
+	`
+
+	return r.list(ctx, query, symbolName, limit)
+}
+
+// ListAfter returns up to limit events of the given types recorded after
+// afterID, oldest first
+func (r *SymbolEventRepository) ListAfter(ctx context.Context, afterID int64, types []domain.SymbolEventType, limit int) ([]*domain.SymbolEvent, error) {
+	names, err := json.Marshal(types)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode symbol event types: %w", err)
+	}
+
+	query := `
+		SELECT id, symbol, type, details, created_at
+		FROM symbol_events
+		WHERE id > ? AND type IN (SELECT value FROM json_each(?))
+		ORDER BY id
+		LIMIT ?
+	`
+
+	return r.list(ctx, query, afterID, string(names), limit)
+}
+
+// DeliveryCursor returns the ID of the last event delivered to subscriber,
+// starting a new subscriber at the latest event
+func (r *SymbolEventRepository) DeliveryCursor(ctx context.Context, subscriber string) (int64, error) {
+	var id int64
+	err := r.db.withTx(ctx, func(tx *sql.Tx) error {
+		// WHERE true keeps SQLite from parsing ON CONFLICT as part of the SELECT
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO symbol_event_cursors (subscriber, last_event_id, updated_at)
+			SELECT ?, COALESCE(MAX(id), 0), ? FROM symbol_events WHERE true
+			ON CONFLICT (subscriber) DO NOTHING
+		`, subscriber, micros(now()))
+		if err != nil {
+			return err
+		}
+
+		return tx.QueryRowContext(ctx, `SELECT last_event_id FROM symbol_event_cursors WHERE subscriber = ?`, subscriber).Scan(&id)
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get symbol event cursor: %w", err)
+	}
+
+	return id, nil
+}
+
+// SetDeliveryCursor records eventID as delivered to subscriber
+func (r *SymbolEventRepository) SetDeliveryCursor(ctx context.Context, subscriber string, eventID int64) error {
+	query := `
+		INSERT INTO symbol_event_cursors (subscriber, last_event_id, updated_at)
+		VALUES (?, ?, ?)
+		ON CONFLICT (subscriber) DO UPDATE
+		SET last_event_id = MAX(symbol_event_cursors.last_event_id, excluded.last_event_id),
+			updated_at = excluded.updated_at
+	`
+
+	if _, err := r.db.db.ExecContext(ctx, query, subscriber, eventID, micros(now())); err != nil {
+		return fmt.Errorf("failed to set symbol event cursor: %w", err)
+	}
+
+	return nil
+}
+
+// list runs a query of symbol events and returns them
+func (r *SymbolEventRepository) list(ctx context.Context, query string, args ...any) ([]*domain.SymbolEvent, error) {
+	rows, err := r.db.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list symbol events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []*domain.SymbolEvent
+	for rows.Next() {
+		var (
+			e         domain.SymbolEvent
+			details   sql.NullString
+			createdAt int64
+		)
+		if err := rows.Scan(&e.ID, &e.Symbol, &e.Type, &details, &createdAt); err != nil {
+			return nil, fmt.Errorf("failed to scan symbol event: %w", err)
+		}
+		e.CreatedAt = timeAt(createdAt)
+		if details.Valid {
+			if err := json.Unmarshal([]byte(details.String), &e.Details); err != nil {
+				return nil, fmt.Errorf("failed to decode symbol event details: %w", err)
+			}
+		}
+		events = append(events, &e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating symbol events: %w", err)
+	}
+
+	return events, nil
+}
+
+// Ensure SymbolEventRepository implements ports.SymbolEventRepository
+var _ ports.SymbolEventRepository = (*SymbolEventRepository)(nil)
@@ -0,0 +1,402 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+)
+
+// SymbolRepository implements the ports.SymbolRepository interface
+type SymbolRepository struct {
+	db *DB
+}
+
+// NewSymbolRepository creates a new SQLite symbol repository
+func NewSymbolRepository(db *DB) ports.SymbolRepository {
+	return &SymbolRepository{db: db}
+}
+
+// Create adds a new symbol to track. A soft deleted symbol of the same
+// name is revived with its ID, so its history carries on.
+func (r *SymbolRepository) Create(ctx context.Context, symbol *domain.Symbol) error {
+	query := `
+		INSERT INTO symbols (name, exchange, active, tags, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT (name) DO UPDATE
+		SET exchange = excluded.exchange, active = excluded.active, tags = excluded.tags,
+			updated_at = excluded.updated_at, deleted_at = NULL
+		WHERE symbols.deleted_at IS NOT NULL
+		RETURNING id
+	`
+
+	err := r.db.db.QueryRowContext(ctx, query,
+		symbol.Name,
+		symbol.Exchange,
+		symbol.Active,
+		encodeTags(symbol.Tags),
+		micros(symbol.CreatedAt),
+		micros(symbol.UpdatedAt),
+	).Scan(&symbol.ID)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.ErrSymbolExists
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create symbol: %w", err)
+	}
+
+	return nil
+}
+
+// GetByName retrieves a symbol by its name
+func (r *SymbolRepository) GetByName(ctx context.Context, name string) (*domain.Symbol, error) {
+	query := `
+		SELECT ` + symbolColumns + `
+		FROM symbols
+		WHERE name = ? AND deleted_at IS NULL
+	`
+
+	symbol, err := scanSymbol(r.db.db.QueryRowContext(ctx, query, name))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrSymbolNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get symbol: %w", err)
+	}
+
+	return symbol, nil
+}
+
+// GetByID retrieves a symbol by its ID
+func (r *SymbolRepository) GetByID(ctx context.Context, id int64) (*domain.Symbol, error) {
+	query := `
+		SELECT ` + symbolColumns + `
+		FROM symbols
+		WHERE id = ? AND deleted_at IS NULL
+	`
+
+	symbol, err := scanSymbol(r.db.db.QueryRowContext(ctx, query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrSymbolNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get symbol: %w", err)
+	}
+
+	return symbol, nil
+}
+
+// List returns all tracked symbols
+func (r *SymbolRepository) List(ctx context.Context) ([]*domain.Symbol, error) {
+	return r.list(ctx, "failed to list symbols", `
+		SELECT `+symbolColumns+`
+		FROM symbols
+		WHERE deleted_at IS NULL
+		ORDER BY name
+	`)
+}
+
+// ListIncludingDeleted returns tracked and soft deleted symbols
+func (r *SymbolRepository) ListIncludingDeleted(ctx context.Context) ([]*domain.Symbol, error) {
+	return r.list(ctx, "failed to list symbols", `
+		SELECT `+symbolColumns+`
+		FROM symbols
+		ORDER BY name
+	`)
+}
+
+// ListActive returns only active symbols
+func (r *SymbolRepository) ListActive(ctx context.Context) ([]*domain.Symbol, error) {
+	return r.list(ctx, "failed to list active symbols", `
+		SELECT `+symbolColumns+`
+		FROM symbols
+		WHERE active = 1 AND deleted_at IS NULL
+		ORDER BY name
+	`)
+}
+
+// ListByTag returns symbols carrying the given tag
+func (r *SymbolRepository) ListByTag(ctx context.Context, tag string) ([]*domain.Symbol, error) {
+	return r.list(ctx, "failed to list symbols by tag", `
+		SELECT `+symbolColumns+`
+		FROM symbols
+		WHERE EXISTS (SELECT 1 FROM json_each(symbols.tags) WHERE value = ?) AND deleted_at IS NULL
+		ORDER BY name
+	`, tag)
+}
+
+// ListPage returns a page of symbols ordered by name, optionally carrying a tag
+func (r *SymbolRepository) ListPage(ctx context.Context, tag string, after *domain.SymbolCursor, limit int) ([]*domain.Symbol, error) {
+	var afterName sql.NullString
+	if after != nil {
+		afterName = sql.NullString{String: after.Name, Valid: true}
+	}
+
+	return r.list(ctx, "failed to list symbol page", `
+		SELECT `+symbolColumns+`
+		FROM symbols
+		WHERE (?1 = '' OR EXISTS (SELECT 1 FROM json_each(symbols.tags) WHERE value = ?1))
+			AND (?2 IS NULL OR name > ?2)
+			AND deleted_at IS NULL
+		ORDER BY name
+		LIMIT ?3
+	`, tag, afterName, limit)
+}
+
+// SoftDelete removes a tracked symbol but keeps its snapshots. It is
+// deactivated, so lookups of active symbols leave it out too.
+func (r *SymbolRepository) SoftDelete(ctx context.Context, name string) error {
+	query := `
+		UPDATE symbols
+		SET active = 0, deleted_at = ?1, updated_at = ?1
+		WHERE name = ?2 AND deleted_at IS NULL
+	`
+
+	result, err := r.db.db.ExecContext(ctx, query, micros(now()), name)
+	if err != nil {
+		return fmt.Errorf("failed to soft delete symbol: %w", err)
+	}
+
+	return symbolChanged(result)
+}
+
+// Delete removes a symbol by name, tracked or soft deleted; its snapshots
+// are removed by cascade
+func (r *SymbolRepository) Delete(ctx context.Context, name string) error {
+	result, err := r.db.db.ExecContext(ctx, `DELETE FROM symbols WHERE name = ?`, name)
+	if err != nil {
+		return fmt.Errorf("failed to delete symbol: %w", err)
+	}
+
+	return symbolChanged(result)
+}
+
+// Update modifies an existing symbol
+func (r *SymbolRepository) Update(ctx context.Context, symbol *domain.Symbol) error {
+	query := `
+		UPDATE symbols
+		SET name = ?, exchange = ?, active = ?, tags = ?, updated_at = ?
+		WHERE id = ? AND deleted_at IS NULL
+	`
+
+	result, err := r.db.db.ExecContext(ctx, query,
+		symbol.Name,
+		symbol.Exchange,
+		symbol.Active,
+		encodeTags(symbol.Tags),
+		micros(now()),
+		symbol.ID,
+	)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return domain.ErrSymbolExists
+		}
+		return fmt.Errorf("failed to update symbol: %w", err)
+	}
+
+	return symbolChanged(result)
+}
+
+// SetActive sets the active flag of the named tracked symbols in one
+// transaction and returns the names whose flag changed. Nothing is
+// changed when any of them is not tracked.
+func (r *SymbolRepository) SetActive(ctx context.Context, names []string, active bool) ([]string, error) {
+	var changed []string
+	err := r.db.withTx(ctx, func(tx *sql.Tx) error {
+		changed = nil
+		seen := make(map[string]bool, len(names))
+		for _, name := range names {
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+
+			var current bool
+			err := tx.QueryRowContext(ctx, `SELECT active FROM symbols WHERE name = ? AND deleted_at IS NULL`, name).Scan(&current)
+			if errors.Is(err, sql.ErrNoRows) {
+				return domain.ErrSymbolNotFound
+			}
+			if err != nil {
+				return fmt.Errorf("failed to get symbol: %w", err)
+			}
+			if current == active {
+				continue
+			}
+
+			_, err = tx.ExecContext(ctx, `UPDATE symbols SET active = ?, updated_at = ? WHERE name = ?`, active, micros(now()), name)
+			if err != nil {
+				return fmt.Errorf("failed to update symbols: %w", err)
+			}
+			changed = append(changed, name)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return changed, nil
+}
+
+// UpdateMetadata stores a symbol's coin metadata
+func (r *SymbolRepository) UpdateMetadata(ctx context.Context, name string, metadata *domain.CoinMetadata) error {
+	query := `
+		UPDATE symbols
+		SET display_name = ?, logo_url = ?, market_cap = ?, metadata_updated_at = ?
+		WHERE name = ? AND deleted_at IS NULL
+	`
+
+	result, err := r.db.db.ExecContext(ctx, query,
+		metadata.Name,
+		metadata.LogoURL,
+		metadata.MarketCap.String(),
+		micros(metadata.UpdatedAt),
+		name,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update symbol metadata: %w", err)
+	}
+
+	return symbolChanged(result)
+}
+
+// Count returns total number of symbols
+func (r *SymbolRepository) Count(ctx context.Context) (int, error) {
+	query := `SELECT COUNT(*) FROM symbols WHERE deleted_at IS NULL`
+
+	var count int
+	if err := r.db.db.QueryRowContext(ctx, query).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count symbols: %w", err)
+	}
+
+	return count, nil
+}
+
+// CountActive returns number of active symbols
+func (r *SymbolRepository) CountActive(ctx context.Context) (int, error) {
+	query := `SELECT COUNT(*) FROM symbols WHERE active = 1 AND deleted_at IS NULL`
+
+	var count int
+	if err := r.db.db.QueryRowContext(ctx, query).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count active symbols: %w", err)
+	}
+
+	return count, nil
+}
+
+// Exists checks if a symbol exists
+func (r *SymbolRepository) Exists(ctx context.Context, name string) (bool, error) {
+	query := `SELECT EXISTS(SELECT 1 FROM symbols WHERE name = ? AND deleted_at IS NULL)`
+
+	var exists bool
+	if err := r.db.db.QueryRowContext(ctx, query, name).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to check symbol existence: %w", err)
+	}
+
+	return exists, nil
+}
+
+// ExistsIncludingDeleted checks if a symbol is tracked or soft deleted
+func (r *SymbolRepository) ExistsIncludingDeleted(ctx context.Context, name string) (bool, error) {
+	query := `SELECT EXISTS(SELECT 1 FROM symbols WHERE name = ?)`
+
+	var exists bool
+	if err := r.db.db.QueryRowContext(ctx, query, name).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to check symbol existence: %w", err)
+	}
+
+	return exists, nil
+}
+
+// list runs a query of symbolColumns and returns its symbols
+func (r *SymbolRepository) list(ctx context.Context, failure string, query string, args ...any) ([]*domain.Symbol, error) {
+	rows, err := r.db.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", failure, err)
+	}
+	defer rows.Close()
+
+	var symbols []*domain.Symbol
+	for rows.Next() {
+		s, err := scanSymbol(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan symbol: %w", err)
+		}
+		symbols = append(symbols, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating symbols: %w", err)
+	}
+
+	return symbols, nil
+}
+
+// symbolColumns are the columns read by scanSymbol, in order
+const symbolColumns = `id, name, exchange, active, tags, display_name, logo_url, market_cap, metadata_updated_at, created_at, updated_at, deleted_at`
+
+// scanner is a row to scan, of *sql.Row or *sql.Rows
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+// scanSymbol reads a row of symbolColumns. Metadata is left nil until the
+// symbol has been enriched.
+func scanSymbol(row scanner) (*domain.Symbol, error) {
+	var (
+		s                            domain.Symbol
+		tags                         string
+		name, logoURL, marketCap     sql.NullString
+		metadataUpdatedAt, deletedAt sql.NullInt64
+		createdAt, updatedAt         int64
+	)
+	err := row.Scan(&s.ID, &s.Name, &s.Exchange, &s.Active, &tags, &name, &logoURL, &marketCap, &metadataUpdatedAt, &createdAt, &updatedAt, &deletedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	s.CreatedAt = timeAt(createdAt)
+	s.UpdatedAt = timeAt(updatedAt)
+	s.DeletedAt = nullTimeAt(deletedAt)
+	if s.Tags, err = decodeTags(tags); err != nil {
+		return nil, err
+	}
+
+	if !metadataUpdatedAt.Valid {
+		return &s, nil
+	}
+
+	s.Metadata = &domain.CoinMetadata{
+		Name:      name.String,
+		LogoURL:   logoURL.String,
+		UpdatedAt: timeAt(metadataUpdatedAt.Int64),
+	}
+	if marketCap.Valid {
+		s.Metadata.MarketCap, err = decimal.NewFromString(marketCap.String)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse market cap: %w", err)
+		}
+	}
+
+	return &s, nil
+}
+
+// symbolChanged returns ErrSymbolNotFound when a statement changed no symbol
+func symbolChanged(result sql.Result) error {
+	affected, err := rowsAffected(result)
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return domain.ErrSymbolNotFound
+	}
+	return nil
+}
+
+// Ensure SymbolRepository implements ports.SymbolRepository
+var _ ports.SymbolRepository = (*SymbolRepository)(nil)
@@ -0,0 +1,90 @@
+package sqlite
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// micros returns a time as it is stored: Unix microseconds
+func micros(t time.Time) int64 {
+	return t.UnixMicro()
+}
+
+// nullMicros returns an optional time as it is stored, NULL when unset
+func nullMicros(t *time.Time) sql.NullInt64 {
+	if t == nil {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: micros(*t), Valid: true}
+}
+
+// timeAt returns the UTC time of stored Unix microseconds
+func timeAt(us int64) time.Time {
+	return time.UnixMicro(us).UTC()
+}
+
+// nullTimeAt returns the time of an optional stored time, nil when NULL
+func nullTimeAt(us sql.NullInt64) *time.Time {
+	if !us.Valid {
+		return nil
+	}
+	t := timeAt(us.Int64)
+	return &t
+}
+
+// nullInt64 returns an optional integer as it is stored, NULL when unset
+func nullInt64(v *int64) sql.NullInt64 {
+	if v == nil {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: *v, Valid: true}
+}
+
+// int64At returns an optional stored integer, nil when NULL
+func int64At(v sql.NullInt64) *int64 {
+	if !v.Valid {
+		return nil
+	}
+	return &v.Int64
+}
+
+// nullString stores an empty string as NULL
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+// parsePrice parses a stored decimal string
+func parsePrice(s string) (decimal.Decimal, error) {
+	price, err := decimal.NewFromString(s)
+	if err != nil {
+		return decimal.Decimal{}, fmt.Errorf("failed to parse price: %w", err)
+	}
+	return price, nil
+}
+
+// encodeTags stores tags as a JSON array, nil tags as an empty one
+func encodeTags(tags []string) string {
+	if tags == nil {
+		tags = []string{}
+	}
+	data, _ := json.Marshal(tags)
+	return string(data)
+}
+
+// decodeTags reads a stored JSON array of tags
+func decodeTags(data string) ([]string, error) {
+	var tags []string
+	if err := json.Unmarshal([]byte(data), &tags); err != nil {
+		return nil, fmt.Errorf("failed to decode tags: %w", err)
+	}
+	return tags, nil
+}
+
+// now returns the current time as stored timestamps are, in UTC
+func now() time.Time {
+	return time.Now().UTC()
+}
@@ -0,0 +1,126 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/prxgr4mmer/price-snapshot-service/pkg/vault"
+)
+
+// secretKeys are the variables that may hold credentials. Each can also be
+// read from the file named by its _FILE variable, as mounted by Docker and
+// Kubernetes secrets, or from Vault.
+var secretKeys = []string{
+	"DATABASE_URL",
+	"EXCHANGE_API_KEY",
+	"EXCHANGE_API_SECRET",
+	"COINGECKO_API_KEY",
+	"INGEST_API_KEYS",
+	"SERVER_API_KEYS",
+	"DIGEST_WEBHOOK_URL",
+	"DIGEST_SLACK_WEBHOOK_URL",
+	"DIGEST_SMTP_PASSWORD",
+	"SYMBOL_EVENTS_WEBHOOK_URL",
+	"VAULT_TOKEN",
+}
+
+// secretEnv holds the secrets read by Load from files and Vault; they are
+// looked up before the environment
+var secretEnv map[string]string
+
+// VaultConfig holds HashiCorp Vault secret loading configuration
+type VaultConfig struct {
+	Addr  string
+	Token string
+
+	// SecretPath is the secret holding the variables, e.g.
+	// "secret/data/price-snapshot-service"
+	SecretPath string
+	Timeout    time.Duration
+}
+
+// Enabled reports whether secrets are read from Vault
+func (c VaultConfig) Enabled() bool {
+	return c.Addr != ""
+}
+
+// Client returns a client for the configured Vault server
+func (c VaultConfig) Client() *vault.Client {
+	return vault.New(c.Addr, c.Token, vault.WithHTTPClient(&http.Client{Timeout: c.Timeout}))
+}
+
+// loadSecrets reads each secret set through its _FILE variable, then the
+// remaining ones from Vault when VAULT_ADDR is set. A variable set directly
+// wins over Vault, but may not be combined with its _FILE variable.
+func loadSecrets() (map[string]string, error) {
+	secrets := make(map[string]string)
+	for _, key := range secretKeys {
+		path := os.Getenv(key + "_FILE")
+		if path == "" {
+			continue
+		}
+		if os.Getenv(key) != "" {
+			return nil, fmt.Errorf("set either %s or %s_FILE, not both", key, key)
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s_FILE: %w", key, err)
+		}
+		secrets[key] = strings.TrimRight(string(data), "\r\n")
+	}
+
+	cfg := loadVaultConfig(secrets)
+	if !cfg.Enabled() {
+		return secrets, nil
+	}
+	if cfg.Token == "" || cfg.SecretPath == "" {
+		return nil, fmt.Errorf("vault requires VAULT_TOKEN and VAULT_SECRET_PATH")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Timeout)
+	defer cancel()
+
+	values, err := cfg.Client().ReadSecret(ctx, cfg.SecretPath)
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range secretKeys {
+		if _, ok := secrets[key]; ok || os.Getenv(key) != "" || key == "VAULT_TOKEN" {
+			continue
+		}
+		if value, ok := values[key]; ok {
+			secrets[key] = value
+		}
+	}
+
+	return secrets, nil
+}
+
+// lookupEnv returns a secret read by Load, or else the environment variable
+func lookupEnv(key string) string {
+	if value, ok := secretEnv[key]; ok {
+		return value
+	}
+	return os.Getenv(key)
+}
+
+// loadVaultConfig reads the Vault settings, with the token possibly read
+// from VAULT_TOKEN_FILE into secrets
+func loadVaultConfig(secrets map[string]string) VaultConfig {
+	token := os.Getenv("VAULT_TOKEN")
+	if token == "" {
+		token = secrets["VAULT_TOKEN"]
+	}
+
+	return VaultConfig{
+		Addr:       os.Getenv("VAULT_ADDR"),
+		Token:      token,
+		SecretPath: os.Getenv("VAULT_SECRET_PATH"),
+		Timeout:    getEnvDuration("VAULT_TIMEOUT", 5*time.Second),
+	}
+}
@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// PriceStats summarizes a symbol's prices over a window
+type PriceStats struct {
+	Symbol string          `json:"symbol"`
+	From   time.Time       `json:"from"`
+	To     time.Time       `json:"to"`
+	Count  int64           `json:"count"`
+	Min    decimal.Decimal `json:"min"`
+	Max    decimal.Decimal `json:"max"`
+	Avg    decimal.Decimal `json:"avg"`
+}
+
+// PricePercentile is the price at or below which the given fraction of a
+// window's snapshots fall
+type PricePercentile struct {
+	Percentile float64         `json:"percentile"`
+	Price      decimal.Decimal `json:"price"`
+}
+
+// ValidatePercentiles checks that every percentile is a fraction in [0, 1]
+func ValidatePercentiles(percentiles []float64) error {
+	for _, p := range percentiles {
+		if p < 0 || p > 1 {
+			return ErrInvalidPercentile
+		}
+	}
+	return nil
+}
@@ -0,0 +1,16 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+)
+
+func TestValidatePercentiles(t *testing.T) {
+	assert.NoError(t, domain.ValidatePercentiles(nil))
+	assert.NoError(t, domain.ValidatePercentiles([]float64{0, 0.5, 0.99, 1}))
+	assert.ErrorIs(t, domain.ValidatePercentiles([]float64{0.5, 1.5}), domain.ErrInvalidPercentile)
+	assert.ErrorIs(t, domain.ValidatePercentiles([]float64{-0.1}), domain.ErrInvalidPercentile)
+}
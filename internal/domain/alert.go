@@ -0,0 +1,336 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+var (
+	// ErrInvalidAlertRule marks an alert rule that cannot be evaluated
+	ErrInvalidAlertRule = errors.New("invalid alert rule")
+
+	// ErrAlertNotFound is returned for an alert that is not registered
+	ErrAlertNotFound = errors.New("alert not found")
+)
+
+// MaxBacktestRange is the longest window an alert rule can be replayed over
+const MaxBacktestRange = 31 * 24 * time.Hour
+
+const (
+	// DefaultWatchTTL is how long a watch waits to fire when no TTL is given
+	DefaultWatchTTL = 24 * time.Hour
+
+	// MaxWatchTTL is the longest a watch can wait to fire
+	MaxWatchTTL = 30 * 24 * time.Hour
+)
+
+// AlertCondition is what an alert rule watches for
+type AlertCondition string
+
+const (
+	// AlertCrossesAbove fires when the price moves from below the
+	// threshold to at or above it
+	AlertCrossesAbove AlertCondition = "crosses_above"
+
+	// AlertCrossesBelow fires when the price moves from above the
+	// threshold to at or below it
+	AlertCrossesBelow AlertCondition = "crosses_below"
+
+	// AlertCrosses fires when the price moves from either side of the
+	// threshold to at or past it
+	AlertCrosses AlertCondition = "crosses"
+
+	// AlertMovesPercent fires when the price has moved by at least the
+	// threshold, in percent and either way, within the rule's window
+	AlertMovesPercent AlertCondition = "moves_percent"
+)
+
+// AlertRule is a condition on a symbol's price
+type AlertRule struct {
+	Symbol    string
+	Condition AlertCondition
+	Threshold decimal.Decimal
+
+	// Window is how far back a percentage move is measured; only used by
+	// AlertMovesPercent
+	Window time.Duration
+}
+
+// alertRuleJSON is the wire form of an alert rule, with the window as a
+// Go duration string such as "1h"
+type alertRuleJSON struct {
+	Symbol    string          `json:"symbol"`
+	Condition AlertCondition  `json:"condition"`
+	Threshold decimal.Decimal `json:"threshold"`
+	Window    string          `json:"window,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler
+func (r AlertRule) MarshalJSON() ([]byte, error) {
+	wire := alertRuleJSON{Symbol: r.Symbol, Condition: r.Condition, Threshold: r.Threshold}
+	if r.Window > 0 {
+		wire.Window = r.Window.String()
+	}
+	return json.Marshal(wire)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (r *AlertRule) UnmarshalJSON(data []byte) error {
+	var wire alertRuleJSON
+	if err := json.Unmarshal(data, &wire); err != nil {
+		return err
+	}
+
+	var window time.Duration
+	if wire.Window != "" {
+		d, err := time.ParseDuration(wire.Window)
+		if err != nil {
+			return fmt.Errorf("%w: window %q", ErrInvalidAlertRule, wire.Window)
+		}
+		window = d
+	}
+
+	*r = AlertRule{Symbol: wire.Symbol, Condition: wire.Condition, Threshold: wire.Threshold, Window: window}
+	return nil
+}
+
+// Normalize canonicalizes the rule's symbol
+func (r *AlertRule) Normalize() {
+	r.Symbol = NormalizeSymbol(r.Symbol)
+}
+
+// Validate checks that the rule can be evaluated
+func (r AlertRule) Validate() error {
+	if r.Symbol == "" {
+		return fmt.Errorf("%w: symbol is required", ErrInvalidAlertRule)
+	}
+
+	switch r.Condition {
+	case AlertCrossesAbove, AlertCrossesBelow, AlertCrosses:
+		if !r.Threshold.IsPositive() {
+			return fmt.Errorf("%w: threshold must be a positive price", ErrInvalidAlertRule)
+		}
+	case AlertMovesPercent:
+		if !r.Threshold.IsPositive() {
+			return fmt.Errorf("%w: threshold must be a positive percentage", ErrInvalidAlertRule)
+		}
+		if r.Window <= 0 {
+			return fmt.Errorf("%w: window is required for %s", ErrInvalidAlertRule, r.Condition)
+		}
+	default:
+		return fmt.Errorf("%w: unknown condition %q", ErrInvalidAlertRule, r.Condition)
+	}
+	return nil
+}
+
+// AlertFiring is a point at which a rule fired
+type AlertFiring struct {
+	At    time.Time       `json:"at"`
+	Price decimal.Decimal `json:"price"`
+
+	// Reference is the price the rule compared against: the previous price
+	// for a crossing, the price at the start of the window for a move
+	Reference     decimal.Decimal  `json:"reference"`
+	ChangePercent *decimal.Decimal `json:"change_percent,omitempty"`
+}
+
+// AlertEvaluator runs a rule over a symbol's snapshots in time order. It
+// keeps the state a rule needs between snapshots, so the same evaluation
+// serves replays of history and live prices.
+type AlertEvaluator struct {
+	rule     AlertRule
+	previous *PriceSnapshot
+
+	// window holds the snapshots of the last rule.Window for percentage
+	// moves, oldest first
+	window []*PriceSnapshot
+}
+
+// NewAlertEvaluator creates an evaluator for a valid rule
+func NewAlertEvaluator(rule AlertRule) *AlertEvaluator {
+	return &AlertEvaluator{rule: rule}
+}
+
+// Observe feeds the next snapshot to the rule and returns the firing it
+// causes, if any. A crossing fires once per crossing; after a move fires,
+// the window restarts at that snapshot so the next firing needs a new move.
+func (e *AlertEvaluator) Observe(snap *PriceSnapshot) *AlertFiring {
+	previous := e.previous
+	e.previous = snap
+
+	switch e.rule.Condition {
+	case AlertCrossesAbove:
+		if previous != nil && previous.Price.LessThan(e.rule.Threshold) && snap.Price.GreaterThanOrEqual(e.rule.Threshold) {
+			return &AlertFiring{At: snap.Timestamp, Price: snap.Price, Reference: previous.Price}
+		}
+	case AlertCrossesBelow:
+		if previous != nil && previous.Price.GreaterThan(e.rule.Threshold) && snap.Price.LessThanOrEqual(e.rule.Threshold) {
+			return &AlertFiring{At: snap.Timestamp, Price: snap.Price, Reference: previous.Price}
+		}
+	case AlertCrosses:
+		if previous != nil && crossed(previous.Price, snap.Price, e.rule.Threshold) {
+			return &AlertFiring{At: snap.Timestamp, Price: snap.Price, Reference: previous.Price}
+		}
+	case AlertMovesPercent:
+		return e.observeMove(snap)
+	}
+	return nil
+}
+
+// crossed reports whether a price moved from one side of threshold to at or
+// past it
+func crossed(previous, current, threshold decimal.Decimal) bool {
+	return (previous.LessThan(threshold) && current.GreaterThanOrEqual(threshold)) ||
+		(previous.GreaterThan(threshold) && current.LessThanOrEqual(threshold))
+}
+
+func (e *AlertEvaluator) observeMove(snap *PriceSnapshot) *AlertFiring {
+	cutoff := snap.Timestamp.Add(-e.rule.Window)
+	drop := 0
+	for drop < len(e.window) && e.window[drop].Timestamp.Before(cutoff) {
+		drop++
+	}
+	e.window = append(e.window[drop:], snap)
+
+	start := e.window[0]
+	change := PercentChange(start.Price, snap.Price, PercentPlaces, RoundHalfUp)
+	if change.Abs().LessThan(e.rule.Threshold) {
+		return nil
+	}
+
+	e.window = []*PriceSnapshot{snap}
+	return &AlertFiring{At: snap.Timestamp, Price: snap.Price, Reference: start.Price, ChangePercent: &change}
+}
+
+// AlertBacktest reports when a rule would have fired over a past window
+type AlertBacktest struct {
+	Rule      AlertRule     `json:"rule"`
+	From      time.Time     `json:"from"`
+	To        time.Time     `json:"to"`
+	Snapshots int           `json:"snapshots"`
+	Firings   []AlertFiring `json:"firings"`
+
+	evaluator *AlertEvaluator
+}
+
+// NewAlertBacktest starts replaying a valid rule over [from, to)
+func NewAlertBacktest(rule AlertRule, from, to time.Time) *AlertBacktest {
+	return &AlertBacktest{
+		Rule:      rule,
+		From:      from,
+		To:        to,
+		Firings:   []AlertFiring{},
+		evaluator: NewAlertEvaluator(rule),
+	}
+}
+
+// Observe replays the next snapshot, oldest first
+func (b *AlertBacktest) Observe(snap *PriceSnapshot) {
+	b.Snapshots++
+	if firing := b.evaluator.Observe(snap); firing != nil {
+		b.Firings = append(b.Firings, *firing)
+	}
+}
+
+// AlertDelivery is the state of the webhook call for an alert's last firing
+type AlertDelivery string
+
+const (
+	// AlertDeliveryPending means the firing is queued or being retried
+	AlertDeliveryPending AlertDelivery = "pending"
+
+	// AlertDeliveryDelivered means the webhook accepted the firing
+	AlertDeliveryDelivered AlertDelivery = "delivered"
+
+	// AlertDeliveryFailed means every attempt to call the webhook failed
+	AlertDeliveryFailed AlertDelivery = "failed"
+)
+
+// Alert is a registered rule whose firings on live prices are posted to a
+// webhook
+type Alert struct {
+	ID         int64     `json:"id"`
+	Rule       AlertRule `json:"rule"`
+	WebhookURL string    `json:"webhook_url"`
+
+	// Firings counts how often the rule fired; LastFiring is the latest
+	Firings    int64        `json:"firings"`
+	LastFiring *AlertFiring `json:"last_firing,omitempty"`
+
+	// Delivery is the state of the webhook call for LastFiring, with the
+	// error of the last failed attempt
+	Delivery      AlertDelivery `json:"delivery,omitempty"`
+	DeliveryError string        `json:"delivery_error,omitempty"`
+
+	// Once marks a watch: it fires at most once and is removed after its
+	// webhook call, or at ExpiresAt if it has not fired by then
+	Once      bool       `json:"once,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// NewAlert registers a rule that posts its firings to webhookURL, which
+// must be an absolute http or https URL
+func NewAlert(rule AlertRule, webhookURL string) (*Alert, error) {
+	rule.Normalize()
+	if err := rule.Validate(); err != nil {
+		return nil, err
+	}
+
+	u, err := url.Parse(webhookURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("%w: webhook_url must be an http or https URL", ErrInvalidAlertRule)
+	}
+
+	return &Alert{
+		Rule:       rule,
+		WebhookURL: webhookURL,
+		CreatedAt:  time.Now().UTC(),
+	}, nil
+}
+
+// NewWatch registers a one-shot alert that posts to webhookURL the first
+// time the symbol's price crosses price, either way, within ttl. A zero ttl
+// means DefaultWatchTTL.
+func NewWatch(symbol string, price decimal.Decimal, webhookURL string, ttl time.Duration) (*Alert, error) {
+	if ttl == 0 {
+		ttl = DefaultWatchTTL
+	}
+	if ttl < 0 || ttl > MaxWatchTTL {
+		return nil, fmt.Errorf("%w: ttl must be positive and at most %s", ErrInvalidAlertRule, MaxWatchTTL)
+	}
+
+	alert, err := NewAlert(AlertRule{Symbol: symbol, Condition: AlertCrosses, Threshold: price}, webhookURL)
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := alert.CreatedAt.Add(ttl)
+	alert.Once = true
+	alert.ExpiresAt = &expiresAt
+	return alert, nil
+}
+
+// Expired reports whether the alert's TTL ran out by now
+func (a *Alert) Expired(now time.Time) bool {
+	return a.ExpiresAt != nil && !now.Before(*a.ExpiresAt)
+}
+
+// Spent reports whether a watch already fired and only awaits its webhook
+// call
+func (a *Alert) Spent() bool {
+	return a.Once && a.Firings > 0
+}
+
+// AlertNotification is the body posted to an alert's webhook when it fires
+type AlertNotification struct {
+	AlertID int64       `json:"alert_id"`
+	Rule    AlertRule   `json:"rule"`
+	Firing  AlertFiring `json:"firing"`
+}
@@ -0,0 +1,165 @@
+package domain_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+)
+
+// replay runs a rule over prices one minute apart and returns the firings
+func replay(rule domain.AlertRule, prices ...string) []domain.AlertFiring {
+	start := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	backtest := domain.NewAlertBacktest(rule, start, start.Add(time.Hour))
+	for i, p := range prices {
+		backtest.Observe(&domain.PriceSnapshot{
+			Symbol:    rule.Symbol,
+			Price:     decimal.RequireFromString(p),
+			Timestamp: start.Add(time.Duration(i) * time.Minute),
+		})
+	}
+	return backtest.Firings
+}
+
+func TestAlertEvaluator(t *testing.T) {
+	t.Run("fires once per upward crossing", func(t *testing.T) {
+		rule := domain.AlertRule{Symbol: "BTCUSDT", Condition: domain.AlertCrossesAbove, Threshold: decimal.NewFromInt(70000)}
+
+		firings := replay(rule, "69000", "70000", "71000", "69500", "70500")
+
+		require.Len(t, firings, 2)
+		assert.Equal(t, "70000", firings[0].Price.String())
+		assert.Equal(t, "69000", firings[0].Reference.String())
+		assert.Equal(t, "70500", firings[1].Price.String())
+	})
+
+	t.Run("does not fire when starting above the threshold", func(t *testing.T) {
+		rule := domain.AlertRule{Symbol: "BTCUSDT", Condition: domain.AlertCrossesAbove, Threshold: decimal.NewFromInt(70000)}
+
+		assert.Empty(t, replay(rule, "71000", "72000"))
+	})
+
+	t.Run("fires on a downward crossing", func(t *testing.T) {
+		rule := domain.AlertRule{Symbol: "BTCUSDT", Condition: domain.AlertCrossesBelow, Threshold: decimal.NewFromInt(60000)}
+
+		firings := replay(rule, "61000", "60500", "59999", "58000")
+
+		require.Len(t, firings, 1)
+		assert.Equal(t, "59999", firings[0].Price.String())
+	})
+
+	t.Run("fires on a crossing either way", func(t *testing.T) {
+		rule := domain.AlertRule{Symbol: "BTCUSDT", Condition: domain.AlertCrosses, Threshold: decimal.NewFromInt(100000)}
+
+		firings := replay(rule, "99000", "100000", "101000", "99999")
+
+		require.Len(t, firings, 2)
+		assert.Equal(t, "100000", firings[0].Price.String())
+		assert.Equal(t, "99999", firings[1].Price.String())
+		assert.Equal(t, "101000", firings[1].Reference.String())
+	})
+
+	t.Run("fires on a move within the window", func(t *testing.T) {
+		rule := domain.AlertRule{Symbol: "BTCUSDT", Condition: domain.AlertMovesPercent, Threshold: decimal.NewFromInt(5), Window: 2 * time.Minute}
+
+		// 100 -> 106 is 6% within two minutes; the window then restarts at
+		// 106, so 106 -> 100 (-5.66%) fires again
+		firings := replay(rule, "100", "103", "106", "104", "100")
+
+		require.Len(t, firings, 2)
+		assert.Equal(t, "100", firings[0].Reference.String())
+		assert.Equal(t, "6", firings[0].ChangePercent.String())
+		assert.Equal(t, "-5.66", firings[1].ChangePercent.String())
+	})
+
+	t.Run("ignores moves spread over more than the window", func(t *testing.T) {
+		rule := domain.AlertRule{Symbol: "BTCUSDT", Condition: domain.AlertMovesPercent, Threshold: decimal.NewFromInt(5), Window: time.Minute}
+
+		assert.Empty(t, replay(rule, "100", "103", "106", "109"))
+	})
+}
+
+func TestAlertRule_Validate(t *testing.T) {
+	valid := []domain.AlertRule{
+		{Symbol: "BTCUSDT", Condition: domain.AlertCrossesAbove, Threshold: decimal.NewFromInt(70000)},
+		{Symbol: "BTCUSDT", Condition: domain.AlertMovesPercent, Threshold: decimal.NewFromInt(5), Window: time.Hour},
+	}
+	for _, rule := range valid {
+		assert.NoError(t, rule.Validate(), rule.Condition)
+	}
+
+	invalid := []domain.AlertRule{
+		{Condition: domain.AlertCrossesAbove, Threshold: decimal.NewFromInt(1)},
+		{Symbol: "BTCUSDT", Condition: "sideways", Threshold: decimal.NewFromInt(1)},
+		{Symbol: "BTCUSDT", Condition: domain.AlertCrossesBelow},
+		{Symbol: "BTCUSDT", Condition: domain.AlertMovesPercent, Threshold: decimal.NewFromInt(5)},
+	}
+	for _, rule := range invalid {
+		assert.ErrorIs(t, rule.Validate(), domain.ErrInvalidAlertRule, rule)
+	}
+}
+
+func TestAlertRule_JSON(t *testing.T) {
+	var rule domain.AlertRule
+	require.NoError(t, json.Unmarshal([]byte(`{"symbol":"BTCUSDT","condition":"moves_percent","threshold":"5","window":"1h"}`), &rule))
+	assert.Equal(t, time.Hour, rule.Window)
+	assert.Equal(t, "5", rule.Threshold.String())
+
+	data, err := json.Marshal(rule)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"symbol":"BTCUSDT","condition":"moves_percent","threshold":"5","window":"1h0m0s"}`, string(data))
+
+	err = json.Unmarshal([]byte(`{"symbol":"BTCUSDT","window":"an hour"}`), &rule)
+	assert.ErrorIs(t, err, domain.ErrInvalidAlertRule)
+}
+
+func TestNewAlert(t *testing.T) {
+	rule := domain.AlertRule{Symbol: " btcusdt", Condition: domain.AlertCrossesAbove, Threshold: decimal.NewFromInt(70000)}
+
+	alert, err := domain.NewAlert(rule, "https://hooks.example.com/btc")
+	require.NoError(t, err)
+	assert.Equal(t, "BTCUSDT", alert.Rule.Symbol)
+	assert.Equal(t, "https://hooks.example.com/btc", alert.WebhookURL)
+	assert.NotZero(t, alert.CreatedAt)
+
+	for _, url := range []string{"", "hooks.example.com/btc", "ftp://hooks.example.com", "https://"} {
+		_, err := domain.NewAlert(rule, url)
+		assert.ErrorIs(t, err, domain.ErrInvalidAlertRule, url)
+	}
+
+	_, err = domain.NewAlert(domain.AlertRule{Symbol: "BTCUSDT", Condition: domain.AlertMovesPercent, Threshold: decimal.NewFromInt(5)}, "https://hooks.example.com/btc")
+	assert.ErrorIs(t, err, domain.ErrInvalidAlertRule)
+}
+
+func TestNewWatch(t *testing.T) {
+	watch, err := domain.NewWatch("btcusdt", decimal.NewFromInt(100000), "https://hooks.example.com/btc", 6*time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, domain.AlertRule{Symbol: "BTCUSDT", Condition: domain.AlertCrosses, Threshold: decimal.NewFromInt(100000)}, watch.Rule)
+	assert.True(t, watch.Once)
+	require.NotNil(t, watch.ExpiresAt)
+	assert.Equal(t, watch.CreatedAt.Add(6*time.Hour), *watch.ExpiresAt)
+
+	assert.False(t, watch.Expired(watch.CreatedAt))
+	assert.True(t, watch.Expired(*watch.ExpiresAt))
+
+	assert.False(t, watch.Spent())
+	watch.Firings = 1
+	assert.True(t, watch.Spent())
+
+	defaulted, err := domain.NewWatch("BTCUSDT", decimal.NewFromInt(100000), "https://hooks.example.com/btc", 0)
+	require.NoError(t, err)
+	assert.Equal(t, defaulted.CreatedAt.Add(domain.DefaultWatchTTL), *defaulted.ExpiresAt)
+
+	for _, ttl := range []time.Duration{-time.Hour, domain.MaxWatchTTL + time.Hour} {
+		_, err := domain.NewWatch("BTCUSDT", decimal.NewFromInt(100000), "https://hooks.example.com/btc", ttl)
+		assert.ErrorIs(t, err, domain.ErrInvalidAlertRule, ttl)
+	}
+
+	_, err = domain.NewWatch("BTCUSDT", decimal.Zero, "https://hooks.example.com/btc", time.Hour)
+	assert.ErrorIs(t, err, domain.ErrInvalidAlertRule)
+}
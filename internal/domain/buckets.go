@@ -0,0 +1,139 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// FillMode selects how the empty buckets of a bucketed history, gaps left
+// by weekends, exchange maintenance or missed polls, are filled
+type FillMode string
+
+const (
+	// FillNone leaves gaps out
+	FillNone FillMode = "none"
+
+	// FillPrev carries the price of the last bucket before the gap forward
+	FillPrev FillMode = "prev"
+
+	// FillLinear interpolates between the buckets on either side of the gap
+	FillLinear FillMode = "linear"
+)
+
+// Valid reports whether the fill mode is known
+func (m FillMode) Valid() bool {
+	switch m {
+	case FillNone, FillPrev, FillLinear:
+		return true
+	}
+	return false
+}
+
+// PriceBucket is the average price of the snapshots from Start up to the
+// next bucket. A bucket without snapshots is a gap: its Count is zero, and
+// Filled is set once a price was filled in for it.
+type PriceBucket struct {
+	Start  time.Time
+	Price  decimal.Decimal
+	Count  int
+	Filled bool
+}
+
+// HistoryBuckets describes how a history was bucketed: of the buckets,
+// Gaps had no snapshots and Filled of those were filled in with Fill
+type HistoryBuckets struct {
+	Step   string   `json:"step"`
+	Fill   FillMode `json:"fill"`
+	Gaps   int      `json:"gaps"`
+	Filled int      `json:"filled"`
+}
+
+// BucketCount returns the number of step-wide buckets covering from to to
+func BucketCount(from, to time.Time, step time.Duration) int {
+	span := to.Sub(from)
+	if span <= 0 || step <= 0 {
+		return 0
+	}
+	n := int(span / step)
+	if span%step != 0 {
+		n++
+	}
+	return n
+}
+
+// BucketAverages averages snapshots into step-wide buckets aligned to from,
+// one per step up to to, in time order. Snapshots outside the window are
+// ignored; buckets without any are returned as gaps.
+func BucketAverages(snapshots []*PriceSnapshot, from, to time.Time, step time.Duration) []PriceBucket {
+	buckets := make([]PriceBucket, BucketCount(from, to, step))
+	sums := make([]decimal.Decimal, len(buckets))
+	for i := range buckets {
+		buckets[i].Start = from.Add(time.Duration(i) * step)
+	}
+
+	for _, s := range snapshots {
+		if s.Timestamp.Before(from) || !s.Timestamp.Before(to) {
+			continue
+		}
+		i := int(s.Timestamp.Sub(from) / step)
+		sums[i] = sums[i].Add(s.Price)
+		buckets[i].Count++
+	}
+
+	for i := range buckets {
+		if buckets[i].Count > 0 {
+			buckets[i].Price = sums[i].Div(decimal.NewFromInt(int64(buckets[i].Count)))
+		}
+	}
+	return buckets
+}
+
+// FillGaps fills the gaps of time-ordered buckets with mode and returns the
+// buckets that have a price. Gaps before the first priced bucket cannot be
+// filled, nor with FillLinear those after the last; they are left out like
+// every gap with FillNone.
+func FillGaps(buckets []PriceBucket, mode FillMode) []PriceBucket {
+	result := make([]PriceBucket, 0, len(buckets))
+	prev := -1
+	for i, b := range buckets {
+		if b.Count > 0 {
+			result = append(result, b)
+			prev = i
+			continue
+		}
+		if prev < 0 {
+			continue
+		}
+
+		switch mode {
+		case FillPrev:
+			b.Price = buckets[prev].Price
+		case FillLinear:
+			next := nextPriced(buckets, i)
+			if next < 0 {
+				continue
+			}
+			// Move the gap's share of the way from the previous priced
+			// bucket to the next; multiplying first keeps it exact
+			rise := buckets[next].Price.Sub(buckets[prev].Price).Mul(decimal.NewFromInt(int64(i - prev)))
+			b.Price = buckets[prev].Price.Add(rise.Div(decimal.NewFromInt(int64(next - prev))))
+		default:
+			continue
+		}
+		b.Filled = true
+		result = append(result, b)
+	}
+	return result
+}
+
+// nextPriced returns the index of the first bucket after i with snapshots,
+// or -1 when there is none
+func nextPriced(buckets []PriceBucket, i int) int {
+	for j := i + 1; j < len(buckets); j++ {
+		if buckets[j].Count > 0 {
+			return j
+		}
+	}
+	return -1
+}
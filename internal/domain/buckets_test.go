@@ -0,0 +1,81 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+)
+
+func TestBucketAverages(t *testing.T) {
+	from := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	at := func(offset time.Duration, price int64) *domain.PriceSnapshot {
+		return &domain.PriceSnapshot{Price: decimal.NewFromInt(price), Timestamp: from.Add(offset)}
+	}
+
+	buckets := domain.BucketAverages([]*domain.PriceSnapshot{
+		at(-time.Second, 1),
+		at(10*time.Second, 100),
+		at(50*time.Second, 110),
+		at(3*time.Minute, 130),
+		at(4*time.Minute, 1),
+	}, from, from.Add(4*time.Minute), time.Minute)
+
+	require.Len(t, buckets, 4)
+	assert.Equal(t, from, buckets[0].Start)
+	assert.Equal(t, 2, buckets[0].Count)
+	assert.Equal(t, "105", buckets[0].Price.String())
+	assert.Zero(t, buckets[1].Count)
+	assert.Zero(t, buckets[2].Count)
+	assert.Equal(t, from.Add(3*time.Minute), buckets[3].Start)
+	assert.Equal(t, "130", buckets[3].Price.String())
+
+	assert.Equal(t, 3, domain.BucketCount(from, from.Add(150*time.Second), time.Minute))
+}
+
+func TestFillGaps(t *testing.T) {
+	from := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	buckets := func() []domain.PriceBucket {
+		prices := []int64{0, 100, 0, 0, 130, 0}
+		result := make([]domain.PriceBucket, len(prices))
+		for i, p := range prices {
+			result[i].Start = from.Add(time.Duration(i) * time.Minute)
+			if p > 0 {
+				result[i].Price = decimal.NewFromInt(p)
+				result[i].Count = 1
+			}
+		}
+		return result
+	}
+	prices := func(buckets []domain.PriceBucket) ([]string, []bool) {
+		var values []string
+		var filled []bool
+		for _, b := range buckets {
+			values = append(values, b.Price.String())
+			filled = append(filled, b.Filled)
+		}
+		return values, filled
+	}
+
+	t.Run("none leaves gaps out", func(t *testing.T) {
+		values, filled := prices(domain.FillGaps(buckets(), domain.FillNone))
+		assert.Equal(t, []string{"100", "130"}, values)
+		assert.Equal(t, []bool{false, false}, filled)
+	})
+
+	t.Run("prev carries the last price forward", func(t *testing.T) {
+		values, filled := prices(domain.FillGaps(buckets(), domain.FillPrev))
+		assert.Equal(t, []string{"100", "100", "100", "130", "130"}, values)
+		assert.Equal(t, []bool{false, true, true, false, true}, filled)
+	})
+
+	t.Run("linear interpolates between known prices", func(t *testing.T) {
+		values, filled := prices(domain.FillGaps(buckets(), domain.FillLinear))
+		assert.Equal(t, []string{"100", "110", "120", "130"}, values)
+		assert.Equal(t, []bool{false, true, true, false}, filled)
+	})
+}
@@ -0,0 +1,128 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Candle represents OHLC prices over a time interval
+type Candle struct {
+	Symbol    string          `json:"symbol"`
+	OpenTime  time.Time       `json:"open_time"`
+	CloseTime time.Time       `json:"close_time"`
+	Open      decimal.Decimal `json:"open"`
+	High      decimal.Decimal `json:"high"`
+	Low       decimal.Decimal `json:"low"`
+	Close     decimal.Decimal `json:"close"`
+
+	// Samples is the number of snapshots an aggregated candle was built from
+	Samples int `json:"samples,omitempty"`
+
+	// Filled marks a candle made up for an interval without snapshots
+	Filled bool `json:"filled,omitempty"`
+}
+
+// CandleInterval is the width of the candles aggregated from snapshots
+type CandleInterval string
+
+// Candle intervals aggregated from snapshots
+const (
+	CandleInterval1m CandleInterval = "1m"
+	CandleInterval5m CandleInterval = "5m"
+	CandleInterval1h CandleInterval = "1h"
+	CandleInterval1d CandleInterval = "1d"
+)
+
+// CandleIntervals lists the aggregated intervals from finest to coarsest;
+// each is built from the one before it
+var CandleIntervals = []CandleInterval{
+	CandleInterval1m,
+	CandleInterval5m,
+	CandleInterval1h,
+	CandleInterval1d,
+}
+
+var candleIntervalDurations = map[CandleInterval]time.Duration{
+	CandleInterval1m: time.Minute,
+	CandleInterval5m: 5 * time.Minute,
+	CandleInterval1h: time.Hour,
+	CandleInterval1d: 24 * time.Hour,
+}
+
+// Valid reports whether candles are aggregated at the interval
+func (i CandleInterval) Valid() bool {
+	_, ok := candleIntervalDurations[i]
+	return ok
+}
+
+// Duration returns the width of the interval
+func (i CandleInterval) Duration() time.Duration {
+	return candleIntervalDurations[i]
+}
+
+// Source returns the finer interval whose candles this one is rolled up
+// from, or false for the finest interval, which is built from snapshots
+func (i CandleInterval) Source() (CandleInterval, bool) {
+	for n, interval := range CandleIntervals {
+		if interval == i && n > 0 {
+			return CandleIntervals[n-1], true
+		}
+	}
+	return "", false
+}
+
+// CandleAggregation reports a candle aggregation run: the candles of each
+// interval rebuilt between From and To
+type CandleAggregation struct {
+	From       time.Time                `json:"from"`
+	To         time.Time                `json:"to"`
+	Candles    map[CandleInterval]int64 `json:"candles"`
+	DurationMs int64                    `json:"duration_ms"`
+}
+
+// FillCandleGaps returns time-ordered candles of symbol with one candle for
+// every interval from from to to, filling the intervals without one with
+// mode. A filled candle is flat at the filled price, taken from the closes
+// of the candles around the gap, and has Filled set. With FillNone the
+// candles are returned as they are.
+func FillCandleGaps(candles []*Candle, symbol string, from, to time.Time, interval time.Duration, mode FillMode) []*Candle {
+	if mode == FillNone || len(candles) == 0 {
+		return candles
+	}
+
+	buckets := make([]PriceBucket, BucketCount(from, to, interval))
+	byBucket := make(map[int]*Candle, len(candles))
+	for i := range buckets {
+		buckets[i].Start = from.Add(time.Duration(i) * interval)
+	}
+	for _, c := range candles {
+		i := int(c.OpenTime.Sub(from) / interval)
+		if c.OpenTime.Before(from) || i >= len(buckets) {
+			continue
+		}
+		buckets[i].Price = c.Close
+		buckets[i].Count = max(c.Samples, 1)
+		byBucket[i] = c
+	}
+
+	filled := FillGaps(buckets, mode)
+	result := make([]*Candle, 0, len(filled))
+	for _, b := range filled {
+		if !b.Filled {
+			result = append(result, byBucket[int(b.Start.Sub(from)/interval)])
+			continue
+		}
+		result = append(result, &Candle{
+			Symbol:    symbol,
+			OpenTime:  b.Start,
+			CloseTime: b.Start.Add(interval),
+			Open:      b.Price,
+			High:      b.Price,
+			Low:       b.Price,
+			Close:     b.Price,
+			Filled:    true,
+		})
+	}
+	return result
+}
@@ -0,0 +1,71 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+)
+
+func TestCandleInterval_Source(t *testing.T) {
+	_, ok := domain.CandleInterval1m.Source()
+	assert.False(t, ok)
+
+	source, ok := domain.CandleInterval1h.Source()
+	require.True(t, ok)
+	assert.Equal(t, domain.CandleInterval5m, source)
+
+	assert.False(t, domain.CandleInterval("4h").Valid())
+	assert.Equal(t, 24*time.Hour, domain.CandleInterval1d.Duration())
+}
+
+func TestFillCandleGaps(t *testing.T) {
+	from := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	candle := func(minute int, open, close int64) *domain.Candle {
+		start := from.Add(time.Duration(minute) * time.Minute)
+		return &domain.Candle{
+			Symbol:    "BTCUSDT",
+			OpenTime:  start,
+			CloseTime: start.Add(time.Minute),
+			Open:      decimal.NewFromInt(open),
+			High:      decimal.NewFromInt(max(open, close)),
+			Low:       decimal.NewFromInt(min(open, close)),
+			Close:     decimal.NewFromInt(close),
+			Samples:   2,
+		}
+	}
+	candles := []*domain.Candle{candle(1, 95, 100), candle(4, 125, 130)}
+
+	t.Run("none keeps the candles", func(t *testing.T) {
+		filled := domain.FillCandleGaps(candles, "BTCUSDT", from, from.Add(6*time.Minute), time.Minute, domain.FillNone)
+		assert.Equal(t, candles, filled)
+	})
+
+	t.Run("linear adds flat candles between closes", func(t *testing.T) {
+		filled := domain.FillCandleGaps(candles, "BTCUSDT", from, from.Add(6*time.Minute), time.Minute, domain.FillLinear)
+
+		require.Len(t, filled, 4)
+		assert.Same(t, candles[0], filled[0])
+		assert.Same(t, candles[1], filled[3])
+		for i, want := range []string{"110", "120"} {
+			c := filled[i+1]
+			assert.True(t, c.Filled)
+			assert.Equal(t, from.Add(time.Duration(i+2)*time.Minute), c.OpenTime)
+			assert.Equal(t, want, c.Open.String())
+			assert.Equal(t, want, c.Close.String())
+			assert.Zero(t, c.Samples)
+		}
+	})
+
+	t.Run("prev carries the close past the last candle", func(t *testing.T) {
+		filled := domain.FillCandleGaps(candles, "BTCUSDT", from, from.Add(6*time.Minute), time.Minute, domain.FillPrev)
+
+		require.Len(t, filled, 5)
+		assert.True(t, filled[4].Filled)
+		assert.Equal(t, "130", filled[4].High.String())
+	})
+}
@@ -0,0 +1,153 @@
+package domain
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ErrUncompressible marks snapshots that cannot be packed into a price chunk
+var ErrUncompressible = errors.New("snapshots cannot be compressed")
+
+// maxScaledPrice bounds the scaled prices of a chunk so that the delta
+// between any two of them still fits an int64
+var maxScaledPrice = big.NewInt(1 << 62)
+
+// PriceChunk is a run of one symbol's snapshots packed for storage. Prices
+// are scaled to integers by 10^Scale and stored, like the timestamps, as
+// varint deltas from the previous snapshot; the first delta is the first
+// price itself. Snapshots keep their row IDs in IDs, encoded the same way,
+// so history cursors page through compressed rows like stored ones.
+type PriceChunk struct {
+	ID              int64
+	SymbolID        int64
+	Symbol          string
+	TimestampSource TimestampSource
+
+	// Start and End are the timestamps of the first and last snapshot
+	Start time.Time
+	End   time.Time
+	Count int
+	Scale int32
+	Data  []byte
+
+	// IDs is empty for chunks written before row IDs were kept; their
+	// snapshots get synthetic IDs derived from the chunk ID
+	IDs []byte
+}
+
+// chunkIDBits is how many low bits of a synthetic snapshot ID hold its
+// position in the chunk
+const chunkIDBits = 24
+
+// EncodePriceChunk packs snapshots of a single symbol and timestamp source,
+// oldest first, into a chunk
+func EncodePriceChunk(snapshots []*PriceSnapshot) (*PriceChunk, error) {
+	if len(snapshots) == 0 {
+		return nil, fmt.Errorf("%w: no snapshots", ErrUncompressible)
+	}
+
+	first := snapshots[0]
+	chunk := &PriceChunk{
+		SymbolID:        first.SymbolID,
+		Symbol:          first.Symbol,
+		TimestampSource: first.TimestampSource,
+		Start:           first.Timestamp,
+		End:             snapshots[len(snapshots)-1].Timestamp,
+		Count:           len(snapshots),
+	}
+
+	for _, s := range snapshots {
+		if s.Symbol != chunk.Symbol || s.TimestampSource != chunk.TimestampSource {
+			return nil, fmt.Errorf("%w: mixed symbols or timestamp sources", ErrUncompressible)
+		}
+		if -s.Price.Exponent() > chunk.Scale {
+			chunk.Scale = -s.Price.Exponent()
+		}
+	}
+
+	buf := make([]byte, 0, len(snapshots)*4)
+	ids := make([]byte, 0, len(snapshots)*2)
+	prevTime, prevPrice, prevID := chunk.Start, int64(0), int64(0)
+	for _, s := range snapshots {
+		step := s.Timestamp.Sub(prevTime)
+		if step < 0 {
+			return nil, fmt.Errorf("%w: snapshots out of order", ErrUncompressible)
+		}
+
+		scaled := s.Price.Shift(chunk.Scale).BigInt()
+		if scaled.CmpAbs(maxScaledPrice) >= 0 {
+			return nil, fmt.Errorf("%w: price %s out of range", ErrUncompressible, s.Price)
+		}
+
+		buf = binary.AppendUvarint(buf, uint64(step.Microseconds()))
+		buf = binary.AppendVarint(buf, scaled.Int64()-prevPrice)
+		ids = binary.AppendVarint(ids, s.ID-prevID)
+		prevTime, prevPrice, prevID = s.Timestamp, scaled.Int64(), s.ID
+	}
+	chunk.Data = buf
+	chunk.IDs = ids
+
+	return chunk, nil
+}
+
+// Snapshots unpacks the chunk, oldest first. Snapshots of a chunk without
+// IDs get negative IDs that are stable for the chunk and unique across
+// chunks, so they still order consistently within a timestamp.
+func (c *PriceChunk) Snapshots() ([]*PriceSnapshot, error) {
+	snapshots := make([]*PriceSnapshot, 0, c.Count)
+	data, ids := c.Data, c.IDs
+	ts, price, id := c.Start, int64(0), int64(0)
+
+	for i := 0; i < c.Count; i++ {
+		step, n := binary.Uvarint(data)
+		if n <= 0 {
+			return nil, fmt.Errorf("corrupt price chunk %s@%s: timestamp %d", c.Symbol, c.Start.Format(time.RFC3339), i)
+		}
+		data = data[n:]
+
+		delta, n := binary.Varint(data)
+		if n <= 0 {
+			return nil, fmt.Errorf("corrupt price chunk %s@%s: price %d", c.Symbol, c.Start.Format(time.RFC3339), i)
+		}
+		data = data[n:]
+
+		if len(c.IDs) > 0 {
+			idDelta, n := binary.Varint(ids)
+			if n <= 0 {
+				return nil, fmt.Errorf("corrupt price chunk %s@%s: id %d", c.Symbol, c.Start.Format(time.RFC3339), i)
+			}
+			ids = ids[n:]
+			id += idDelta
+		} else {
+			id = -(c.ID<<chunkIDBits | int64(i+1))
+		}
+
+		ts = ts.Add(time.Duration(step) * time.Microsecond)
+		price += delta
+		snapshots = append(snapshots, &PriceSnapshot{
+			ID:              id,
+			SymbolID:        c.SymbolID,
+			Symbol:          c.Symbol,
+			Price:           decimal.New(price, -c.Scale),
+			Timestamp:       ts,
+			TimestampSource: c.TimestampSource,
+		})
+	}
+
+	return snapshots, nil
+}
+
+// CompressionResult summarizes a compression run
+type CompressionResult struct {
+	Cutoff     time.Time        `json:"cutoff"`
+	Chunks     int64            `json:"chunks"`
+	Rows       int64            `json:"rows"`
+	BySymbol   map[string]int64 `json:"by_symbol"`
+	Failed     []string         `json:"failed,omitempty"`
+	DurationMs int64            `json:"duration_ms"`
+}
@@ -0,0 +1,185 @@
+package domain_test
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+)
+
+func TestEncodePriceChunk(t *testing.T) {
+	start := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	snap := func(offset time.Duration, price string) *domain.PriceSnapshot {
+		return &domain.PriceSnapshot{
+			SymbolID:        7,
+			Symbol:          "BTCUSDT",
+			Price:           decimal.RequireFromString(price),
+			Timestamp:       start.Add(offset),
+			TimestampSource: domain.TimestampSourcePoll,
+		}
+	}
+
+	t.Run("round-trips prices and timestamps", func(t *testing.T) {
+		snapshots := []*domain.PriceSnapshot{
+			snap(0, "43123.45"),
+			snap(time.Second, "43123.45"),
+			snap(2*time.Second+1500*time.Microsecond, "43120.1"),
+			snap(3*time.Second, "43200.12345678"),
+		}
+
+		chunk, err := domain.EncodePriceChunk(snapshots)
+		require.NoError(t, err)
+		assert.Equal(t, start, chunk.Start)
+		assert.Equal(t, start.Add(3*time.Second), chunk.End)
+		assert.Equal(t, 4, chunk.Count)
+		assert.Equal(t, int32(8), chunk.Scale)
+
+		decoded, err := chunk.Snapshots()
+		require.NoError(t, err)
+		require.Len(t, decoded, len(snapshots))
+		for i, s := range decoded {
+			assert.True(t, snapshots[i].Price.Equal(s.Price), "price %d: %s", i, s.Price)
+			assert.Equal(t, snapshots[i].Timestamp, s.Timestamp)
+			assert.Equal(t, "BTCUSDT", s.Symbol)
+			assert.Equal(t, int64(7), s.SymbolID)
+			assert.Equal(t, domain.TimestampSourcePoll, s.TimestampSource)
+		}
+	})
+
+	t.Run("stores steady prices in a few bytes each", func(t *testing.T) {
+		var snapshots []*domain.PriceSnapshot
+		for i := 0; i < 3600; i++ {
+			snapshots = append(snapshots, snap(time.Duration(i)*time.Second, decimal.NewFromFloat(43000).Add(decimal.New(int64(i%7), -2)).String()))
+		}
+
+		chunk, err := domain.EncodePriceChunk(snapshots)
+		require.NoError(t, err)
+		assert.Less(t, len(chunk.Data), 5*len(snapshots))
+	})
+
+	t.Run("rejects prices that do not fit", func(t *testing.T) {
+		_, err := domain.EncodePriceChunk([]*domain.PriceSnapshot{snap(0, "1"), snap(time.Second, "0.00000001"), snap(2*time.Second, "99999999999999.99")})
+		assert.ErrorIs(t, err, domain.ErrUncompressible)
+	})
+
+	t.Run("rejects snapshots out of order", func(t *testing.T) {
+		_, err := domain.EncodePriceChunk([]*domain.PriceSnapshot{snap(time.Second, "1"), snap(0, "2")})
+		assert.ErrorIs(t, err, domain.ErrUncompressible)
+	})
+
+	t.Run("rejects mixed timestamp sources", func(t *testing.T) {
+		other := snap(time.Second, "1")
+		other.TimestampSource = domain.TimestampSourceImport
+
+		_, err := domain.EncodePriceChunk([]*domain.PriceSnapshot{snap(0, "1"), other})
+		assert.ErrorIs(t, err, domain.ErrUncompressible)
+	})
+
+	t.Run("keeps row IDs", func(t *testing.T) {
+		snapshots := []*domain.PriceSnapshot{snap(0, "1"), snap(time.Second, "2"), snap(time.Second, "3")}
+		for i, id := range []int64{1042, 1040, 1043} {
+			snapshots[i].ID = id
+		}
+
+		chunk, err := domain.EncodePriceChunk(snapshots)
+		require.NoError(t, err)
+
+		decoded, err := chunk.Snapshots()
+		require.NoError(t, err)
+		require.Len(t, decoded, 3)
+		assert.Equal(t, int64(1042), decoded[0].ID)
+		assert.Equal(t, int64(1040), decoded[1].ID)
+		assert.Equal(t, int64(1043), decoded[2].ID)
+	})
+
+	t.Run("gives snapshots of chunks without IDs stable negative IDs", func(t *testing.T) {
+		chunk, err := domain.EncodePriceChunk([]*domain.PriceSnapshot{snap(0, "1"), snap(0, "2")})
+		require.NoError(t, err)
+		chunk.ID, chunk.IDs = 3, nil
+		other := *chunk
+		other.ID = 4
+
+		first, err := chunk.Snapshots()
+		require.NoError(t, err)
+		again, err := chunk.Snapshots()
+		require.NoError(t, err)
+		fromOther, err := other.Snapshots()
+		require.NoError(t, err)
+
+		seen := make(map[int64]bool)
+		for i, s := range append(first, fromOther...) {
+			assert.Negative(t, s.ID)
+			assert.False(t, seen[s.ID], "snapshot %d reuses ID %d", i, s.ID)
+			seen[s.ID] = true
+		}
+		assert.Equal(t, first[0].ID, again[0].ID)
+		assert.Equal(t, first[1].ID, again[1].ID)
+	})
+
+	t.Run("detects truncated data", func(t *testing.T) {
+		chunk, err := domain.EncodePriceChunk([]*domain.PriceSnapshot{snap(0, "1"), snap(time.Second, "2")})
+		require.NoError(t, err)
+
+		chunk.Data = chunk.Data[:len(chunk.Data)-1]
+		_, err = chunk.Snapshots()
+		assert.Error(t, err)
+	})
+}
+
+func TestPriceChunk_HistoryCursorPagesAcrossCompressedRows(t *testing.T) {
+	ts := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	snap := func(id int64, offset time.Duration) *domain.PriceSnapshot {
+		return &domain.PriceSnapshot{
+			ID:              id,
+			Symbol:          "BTCUSDT",
+			Price:           decimal.NewFromInt(id),
+			Timestamp:       ts.Add(offset),
+			TimestampSource: domain.TimestampSourcePoll,
+		}
+	}
+
+	// Rows 1-4 were compressed; rows 5-7 were stored after them, 5 and 6
+	// at the same timestamp as the newest compressed rows
+	chunk, err := domain.EncodePriceChunk([]*domain.PriceSnapshot{snap(1, 0), snap(2, time.Second), snap(3, 2*time.Second), snap(4, 2*time.Second)})
+	require.NoError(t, err)
+	compressed, err := chunk.Snapshots()
+	require.NoError(t, err)
+	stored := []*domain.PriceSnapshot{snap(5, 2*time.Second), snap(6, 2*time.Second), snap(7, 3*time.Second)}
+
+	// Page newest first, two at a time, the way history merges both sources
+	var paged []int64
+	var cursor *domain.HistoryCursor
+	for page := 0; page < 10; page++ {
+		var candidates []*domain.PriceSnapshot
+		for _, s := range append(append([]*domain.PriceSnapshot{}, stored...), compressed...) {
+			if cursor == nil || cursor.Follows(s) {
+				candidates = append(candidates, s)
+			}
+		}
+		if len(candidates) == 0 {
+			break
+		}
+		sort.Slice(candidates, func(i, j int) bool {
+			if !candidates[i].Timestamp.Equal(candidates[j].Timestamp) {
+				return candidates[i].Timestamp.After(candidates[j].Timestamp)
+			}
+			return candidates[i].ID > candidates[j].ID
+		})
+		if len(candidates) > 2 {
+			candidates = candidates[:2]
+		}
+
+		for _, s := range candidates {
+			paged = append(paged, s.ID)
+		}
+		last := candidates[len(candidates)-1]
+		cursor = &domain.HistoryCursor{Timestamp: last.Timestamp, ID: last.ID}
+	}
+
+	assert.Equal(t, []int64{7, 6, 5, 4, 3, 2, 1}, paged)
+}
@@ -0,0 +1,26 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// CoinMetadata describes a symbol's base asset for display, as reported by
+// a market data provider
+type CoinMetadata struct {
+	Name      string          `json:"name"`
+	LogoURL   string          `json:"logo_url,omitempty"`
+	MarketCap decimal.Decimal `json:"market_cap"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+// MarshalJSON emits the market cap as an exact decimal string
+func (m CoinMetadata) MarshalJSON() ([]byte, error) {
+	type alias CoinMetadata
+	return json.Marshal(struct {
+		alias
+		MarketCap exactDecimal `json:"market_cap"`
+	}{alias(m), exactDecimal(m.MarketCap)})
+}
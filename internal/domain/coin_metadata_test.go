@@ -0,0 +1,41 @@
+package domain_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+)
+
+func TestBaseAsset(t *testing.T) {
+	tests := map[string]string{
+		"BTCUSDT":  "BTC",
+		"ETHBTC":   "ETH",
+		"SOLFDUSD": "SOL",
+		"BNBEUR":   "BNB",
+		"USDCUSDT": "USDC",
+		"BTC":      "BTC",
+		"XYZ123":   "XYZ123",
+	}
+
+	for symbol, want := range tests {
+		assert.Equal(t, want, domain.BaseAsset(symbol), symbol)
+	}
+}
+
+func TestCoinMetadata_MarshalJSON(t *testing.T) {
+	m := domain.CoinMetadata{
+		Name:      "Bitcoin",
+		MarketCap: decimal.RequireFromString("845123456789.12"),
+		UpdatedAt: time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
+	}
+
+	data, err := json.Marshal(m)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"name": "Bitcoin", "market_cap": "845123456789.12", "updated_at": "2024-01-15T00:00:00Z"}`, string(data))
+}
@@ -0,0 +1,81 @@
+package domain
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// ErrInvalidCursor is a page cursor that was not issued by the service
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// HistoryCursor marks the last snapshot of a page of newest-first history.
+// The next page holds the snapshots ordered after it by timestamp, then ID,
+// so snapshots sharing a timestamp are neither skipped nor repeated.
+type HistoryCursor struct {
+	Timestamp time.Time `json:"ts"`
+	ID        int64     `json:"id"`
+}
+
+// HistoryCursorAt returns the cursor continuing history after snap
+func HistoryCursorAt(snap *PriceSnapshot) HistoryCursor {
+	return HistoryCursor{Timestamp: snap.Timestamp, ID: snap.ID}
+}
+
+// Follows reports whether snap belongs on a page after the cursor, i.e. is
+// older, or as old with a lower ID
+func (c HistoryCursor) Follows(snap *PriceSnapshot) bool {
+	if !snap.Timestamp.Equal(c.Timestamp) {
+		return snap.Timestamp.Before(c.Timestamp)
+	}
+	return snap.ID < c.ID
+}
+
+// Encode returns the cursor as an opaque URL-safe string
+func (c HistoryCursor) Encode() string {
+	return encodeCursor(c)
+}
+
+// ParseHistoryCursor decodes a cursor returned by Encode
+func ParseHistoryCursor(s string) (*HistoryCursor, error) {
+	var c HistoryCursor
+	if err := decodeCursor(s, &c); err != nil || c.Timestamp.IsZero() {
+		return nil, ErrInvalidCursor
+	}
+	return &c, nil
+}
+
+// SymbolCursor marks the last symbol of a page of symbols listed by name
+type SymbolCursor struct {
+	Name string `json:"name"`
+}
+
+// Encode returns the cursor as an opaque URL-safe string
+func (c SymbolCursor) Encode() string {
+	return encodeCursor(c)
+}
+
+// ParseSymbolCursor decodes a cursor returned by Encode
+func ParseSymbolCursor(s string) (*SymbolCursor, error) {
+	var c SymbolCursor
+	if err := decodeCursor(s, &c); err != nil || c.Name == "" {
+		return nil, ErrInvalidCursor
+	}
+	return &c, nil
+}
+
+// encodeCursor encodes a cursor as unpadded base64url JSON; clients are not
+// meant to look inside
+func encodeCursor(v interface{}) string {
+	data, _ := json.Marshal(v)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(s string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
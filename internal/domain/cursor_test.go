@@ -0,0 +1,47 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+)
+
+func TestHistoryCursor(t *testing.T) {
+	ts := time.Date(2024, 1, 15, 10, 30, 0, 123456000, time.UTC)
+	cursor := domain.HistoryCursor{Timestamp: ts, ID: 42}
+
+	t.Run("round-trips through its encoding", func(t *testing.T) {
+		parsed, err := domain.ParseHistoryCursor(cursor.Encode())
+
+		require.NoError(t, err)
+		assert.True(t, parsed.Timestamp.Equal(ts))
+		assert.Equal(t, int64(42), parsed.ID)
+	})
+
+	t.Run("rejects cursors it did not issue", func(t *testing.T) {
+		for _, s := range []string{"", "bogus", domain.SymbolCursor{Name: "BTCUSDT"}.Encode()} {
+			_, err := domain.ParseHistoryCursor(s)
+			assert.ErrorIs(t, err, domain.ErrInvalidCursor, s)
+		}
+	})
+
+	t.Run("is followed by older snapshots and lower IDs at its timestamp", func(t *testing.T) {
+		assert.True(t, cursor.Follows(&domain.PriceSnapshot{ID: 99, Timestamp: ts.Add(-time.Second)}))
+		assert.True(t, cursor.Follows(&domain.PriceSnapshot{ID: 41, Timestamp: ts}))
+		assert.False(t, cursor.Follows(&domain.PriceSnapshot{ID: 42, Timestamp: ts}))
+		assert.False(t, cursor.Follows(&domain.PriceSnapshot{ID: 1, Timestamp: ts.Add(time.Second)}))
+	})
+}
+
+func TestSymbolCursor(t *testing.T) {
+	parsed, err := domain.ParseSymbolCursor(domain.SymbolCursor{Name: "ETHUSDT"}.Encode())
+	require.NoError(t, err)
+	assert.Equal(t, "ETHUSDT", parsed.Name)
+
+	_, err = domain.ParseSymbolCursor("e30")
+	assert.ErrorIs(t, err, domain.ErrInvalidCursor)
+}
@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"sort"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// PriceChange represents a symbol's price movement over a window
+type PriceChange struct {
+	Symbol        string          `json:"symbol"`
+	OpenPrice     decimal.Decimal `json:"open_price"`
+	ClosePrice    decimal.Decimal `json:"close_price"`
+	ChangePercent decimal.Decimal `json:"change_percent"`
+	From          time.Time       `json:"from"`
+	To            time.Time       `json:"to"`
+}
+
+// NewPriceChange computes the percentage change between two prices
+func NewPriceChange(symbol string, open, close decimal.Decimal, from, to time.Time) *PriceChange {
+	return &PriceChange{
+		Symbol:        symbol,
+		OpenPrice:     open,
+		ClosePrice:    close,
+		ChangePercent: PercentChange(open, close, PercentPlaces, RoundHalfUp),
+		From:          from,
+		To:            to,
+	}
+}
+
+// PriceDigest summarizes the biggest price movements over a window
+type PriceDigest struct {
+	GeneratedAt time.Time      `json:"generated_at"`
+	Window      string         `json:"window"`
+	Gainers     []*PriceChange `json:"gainers"`
+	Losers      []*PriceChange `json:"losers"`
+}
+
+// NewPriceDigest ranks changes into the top n gainers and losers
+func NewPriceDigest(changes []*PriceChange, window time.Duration, n int) *PriceDigest {
+	sorted := make([]*PriceChange, len(changes))
+	copy(sorted, changes)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].ChangePercent.GreaterThan(sorted[j].ChangePercent)
+	})
+
+	digest := &PriceDigest{
+		GeneratedAt: time.Now().UTC(),
+		Window:      window.String(),
+		Gainers:     []*PriceChange{},
+		Losers:      []*PriceChange{},
+	}
+
+	for _, c := range sorted {
+		if len(digest.Gainers) == n || !c.ChangePercent.IsPositive() {
+			break
+		}
+		digest.Gainers = append(digest.Gainers, c)
+	}
+
+	for i := len(sorted) - 1; i >= 0; i-- {
+		c := sorted[i]
+		if len(digest.Losers) == n || !c.ChangePercent.IsNegative() {
+			break
+		}
+		digest.Losers = append(digest.Losers, c)
+	}
+
+	return digest
+}
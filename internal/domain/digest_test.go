@@ -0,0 +1,45 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPriceDigest(t *testing.T) {
+	now := time.Now().UTC()
+	change := func(symbol, open, close string) *domain.PriceChange {
+		return domain.NewPriceChange(symbol,
+			decimal.RequireFromString(open),
+			decimal.RequireFromString(close),
+			now.Add(-24*time.Hour), now)
+	}
+
+	changes := []*domain.PriceChange{
+		change("BTCUSDT", "100", "110"),
+		change("ETHUSDT", "100", "95"),
+		change("SOLUSDT", "100", "120"),
+		change("XRPUSDT", "100", "100"),
+		change("ADAUSDT", "100", "80"),
+	}
+
+	digest := domain.NewPriceDigest(changes, 24*time.Hour, 2)
+
+	require.Len(t, digest.Gainers, 2)
+	assert.Equal(t, "SOLUSDT", digest.Gainers[0].Symbol)
+	assert.Equal(t, "BTCUSDT", digest.Gainers[1].Symbol)
+
+	require.Len(t, digest.Losers, 2)
+	assert.Equal(t, "ADAUSDT", digest.Losers[0].Symbol)
+	assert.Equal(t, "ETHUSDT", digest.Losers[1].Symbol)
+	assert.True(t, digest.Losers[0].ChangePercent.Equal(decimal.NewFromInt(-20)))
+}
+
+func TestNewPriceChange_ZeroOpen(t *testing.T) {
+	c := domain.NewPriceChange("BTCUSDT", decimal.Zero, decimal.NewFromInt(5), time.Time{}, time.Time{})
+	assert.True(t, c.ChangePercent.IsZero())
+}
@@ -4,22 +4,47 @@ import "errors"
 
 var (
 	// Symbol errors
-	ErrInvalidSymbol  = errors.New("invalid symbol format")
-	ErrSymbolNotFound = errors.New("symbol not found")
-	ErrSymbolExists   = errors.New("symbol already exists")
+	ErrInvalidSymbol          = errors.New("invalid symbol format")
+	ErrSymbolNotFound         = errors.New("symbol not found")
+	ErrSymbolExists           = errors.New("symbol already exists")
+	ErrInvalidTag             = errors.New("invalid tag")
+	ErrSymbolQuota            = errors.New("symbol quota exceeded")
+	ErrInvalidAlias           = errors.New("invalid symbol alias")
+	ErrInvalidSyntheticSymbol = errors.New("invalid synthetic symbol")
 
 	// Snapshot errors
 	ErrSnapshotNotFound = errors.New("snapshot not found")
 	ErrNoSnapshots      = errors.New("no snapshots available")
+	ErrInvalidTimeRange = errors.New("invalid time range")
+	ErrPollRunNotFound  = errors.New("poll run not found")
+
+	// Aggregate errors
+	ErrInvalidPercentile = errors.New("percentile must be between 0 and 1")
+
+	// Ticker stats errors
+	ErrTickerStatsNotFound = errors.New("ticker stats not found")
 
 	// Exchange errors
 	ErrExchangeUnavailable = errors.New("exchange service unavailable")
 	ErrRateLimited         = errors.New("rate limited by exchange")
 	ErrInvalidResponse     = errors.New("invalid response from exchange")
+	ErrUnknownExchange     = errors.New("unknown exchange")
+	ErrExchangeAuth        = errors.New("exchange rejected the API credentials")
+	ErrExchangeBudget      = errors.New("exchange request budget used up")
 
 	// Database errors
 	ErrDatabaseConnection = errors.New("database connection error")
 	ErrDatabaseQuery      = errors.New("database query error")
+	ErrSchemaDirty        = errors.New("database schema is dirty")
+	ErrSchemaAhead        = errors.New("database schema is newer than this binary")
+
+	// Job errors
+	ErrJobNotFound     = errors.New("job not found")
+	ErrInvalidJobType  = errors.New("invalid job type")
+	ErrJobFinished     = errors.New("job already finished")
+	ErrJobNotResumable = errors.New("job cannot be resumed")
+	ErrJobCancelled    = errors.New("job was cancelled")
+	ErrJobLeaseLost    = errors.New("job lease lost to another worker")
 
 	// General errors
 	ErrInternal = errors.New("internal server error")
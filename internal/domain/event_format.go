@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// EventFormat names the serialization of published event payloads
+type EventFormat string
+
+const (
+	EventFormatJSON     EventFormat = "json"
+	EventFormatAvro     EventFormat = "avro"
+	EventFormatProtobuf EventFormat = "protobuf"
+)
+
+// ParseEventFormat parses a format name; empty means JSON
+func ParseEventFormat(name string) (EventFormat, error) {
+	switch f := EventFormat(strings.ToLower(strings.TrimSpace(name))); f {
+	case "":
+		return EventFormatJSON, nil
+	case EventFormatJSON, EventFormatAvro, EventFormatProtobuf:
+		return f, nil
+	default:
+		return "", fmt.Errorf("unknown event format %q, use json, avro or protobuf", name)
+	}
+}
+
+// Schemaless reports whether payloads in the format carry no schema
+// registry ID
+func (f EventFormat) Schemaless() bool {
+	return f == EventFormatJSON
+}
@@ -0,0 +1,19 @@
+package domain
+
+import "context"
+
+type singleExchangeAttemptKey struct{}
+
+// WithSingleExchangeAttempt returns a context whose exchange calls give up
+// after their first attempt instead of retrying, for callers that would
+// rather fail fast than wait out the backoff
+func WithSingleExchangeAttempt(ctx context.Context) context.Context {
+	return context.WithValue(ctx, singleExchangeAttemptKey{}, true)
+}
+
+// SingleExchangeAttempt reports whether exchange calls made with ctx must
+// not be retried
+func SingleExchangeAttempt(ctx context.Context) bool {
+	single, _ := ctx.Value(singleExchangeAttemptKey{}).(bool)
+	return single
+}
@@ -0,0 +1,87 @@
+package domain
+
+import "context"
+
+// ExchangePriority ranks the features competing for the exchange's request
+// weight limit. Each priority may only fill the budget up to its share, so
+// the headroom above a share is kept for the priorities above it.
+type ExchangePriority int
+
+const (
+	// ExchangePriorityBackfill is for bulk history loads, which can wait
+	ExchangePriorityBackfill ExchangePriority = iota
+	// ExchangePriorityValidation is for checking symbols against the exchange
+	ExchangePriorityValidation
+	// ExchangePriorityLive is for calls made while serving an API request,
+	// and for any call that does not set a priority
+	ExchangePriorityLive
+	// ExchangePriorityPoll is for the scheduled price poll, which may use
+	// the whole budget
+	ExchangePriorityPoll
+)
+
+// exchangePriorityShares is the fraction of the budget each priority may fill
+var exchangePriorityShares = map[ExchangePriority]float64{
+	ExchangePriorityBackfill:   0.5,
+	ExchangePriorityValidation: 0.75,
+	ExchangePriorityLive:       0.9,
+	ExchangePriorityPoll:       1,
+}
+
+// Share returns the fraction of the budget calls of this priority may fill
+func (p ExchangePriority) Share() float64 {
+	if share, ok := exchangePriorityShares[p]; ok {
+		return share
+	}
+	return exchangePriorityShares[ExchangePriorityLive]
+}
+
+func (p ExchangePriority) String() string {
+	switch p {
+	case ExchangePriorityBackfill:
+		return "backfill"
+	case ExchangePriorityValidation:
+		return "validation"
+	case ExchangePriorityPoll:
+		return "poll"
+	default:
+		return "live"
+	}
+}
+
+// ExchangeBudgetStats reports the use of the exchange request budget in
+// its current window. Used includes the weight the exchange reported, so it
+// may exceed Limit.
+type ExchangeBudgetStats struct {
+	Limit         int     `json:"limit"`
+	Used          int     `json:"used"`
+	WindowSeconds float64 `json:"window_seconds"`
+
+	// Priorities counts the calls of each priority held back by the budget
+	Priorities []ExchangePriorityStats `json:"priorities,omitempty"`
+}
+
+// ExchangePriorityStats counts the calls of one priority that waited for a
+// later window, and those shed because their deadline came first
+type ExchangePriorityStats struct {
+	Priority string `json:"priority"`
+	Waited   int64  `json:"waited"`
+	Shed     int64  `json:"shed"`
+}
+
+type exchangePriorityKey struct{}
+
+// WithExchangePriority returns a context whose exchange calls are budgeted
+// at priority p
+func WithExchangePriority(ctx context.Context, p ExchangePriority) context.Context {
+	return context.WithValue(ctx, exchangePriorityKey{}, p)
+}
+
+// ExchangePriorityFrom returns the priority set on ctx, or
+// ExchangePriorityLive when there is none
+func ExchangePriorityFrom(ctx context.Context) ExchangePriority {
+	if p, ok := ctx.Value(exchangePriorityKey{}).(ExchangePriority); ok {
+		return p
+	}
+	return ExchangePriorityLive
+}
@@ -0,0 +1,60 @@
+package domain
+
+// ExchangeErrorKind classifies a failed exchange request
+type ExchangeErrorKind string
+
+const (
+	// ExchangeErrorTimeout is a request that exceeded its deadline
+	ExchangeErrorTimeout ExchangeErrorKind = "timeout"
+
+	// ExchangeErrorNetwork is a connection failure other than a timeout
+	ExchangeErrorNetwork ExchangeErrorKind = "network"
+
+	// ExchangeErrorRateLimited is a 429 response
+	ExchangeErrorRateLimited ExchangeErrorKind = "rate_limited"
+
+	// ExchangeErrorServer is a 5xx response
+	ExchangeErrorServer ExchangeErrorKind = "server_error"
+
+	// ExchangeErrorBlocked is a 403 or 451 response from a geo-blocked host
+	ExchangeErrorBlocked ExchangeErrorKind = "blocked"
+
+	// ExchangeErrorInvalidSymbol is a request for a symbol the exchange does not list
+	ExchangeErrorInvalidSymbol ExchangeErrorKind = "invalid_symbol"
+
+	// ExchangeErrorAuth is a signed request whose API key or signature was
+	// rejected
+	ExchangeErrorAuth ExchangeErrorKind = "auth"
+
+	// ExchangeErrorBadStatus is any other unexpected response status
+	ExchangeErrorBadStatus ExchangeErrorKind = "bad_status"
+
+	// ExchangeErrorDecode is a response body that could not be decoded
+	ExchangeErrorDecode ExchangeErrorKind = "decode_error"
+
+	// ExchangeErrorUnknown is a failure that fits no other kind
+	ExchangeErrorUnknown ExchangeErrorKind = "unknown"
+)
+
+// ExchangeErrorCount is the number of failed exchange requests of one kind
+// for one operation
+type ExchangeErrorCount struct {
+	Operation string            `json:"operation"`
+	Kind      ExchangeErrorKind `json:"kind"`
+	Count     int64             `json:"count"`
+}
+
+// ExchangeRetryCount is the number of retries of one operation's requests
+// caused by failures of one kind
+type ExchangeRetryCount struct {
+	Operation string            `json:"operation"`
+	Reason    ExchangeErrorKind `json:"reason"`
+	Count     int64             `json:"count"`
+}
+
+// ExchangeCallAttempts is the distribution of the request attempts made by
+// the calls of one operation, retries included
+type ExchangeCallAttempts struct {
+	Operation string     `json:"operation"`
+	Attempts  *Histogram `json:"attempts"`
+}
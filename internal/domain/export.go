@@ -0,0 +1,97 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidExport marks an export range that cannot be planned
+var ErrInvalidExport = errors.New("invalid export range")
+
+// ExportRange is a closed time window exported in fixed-width buckets, one
+// file per bucket and symbol
+type ExportRange struct {
+	From   time.Time
+	To     time.Time
+	Bucket time.Duration
+}
+
+// ParseExportBucket parses a bucket width such as 1d, 6h or 15m. Go
+// durations have no day unit, so "d" is accepted as 24h.
+func ParseExportBucket(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n <= 0 {
+			return 0, fmt.Errorf("%w: bucket %q", ErrInvalidExport, s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("%w: bucket %q", ErrInvalidExport, s)
+	}
+	return d, nil
+}
+
+// Validate checks that the range has ended by now and that both ends fall on
+// bucket boundaries, so every file covers a whole bucket and a rerun can
+// skip the files that are already written
+func (r ExportRange) Validate(now time.Time) error {
+	switch {
+	case r.Bucket < time.Minute:
+		return fmt.Errorf("%w: bucket must be at least 1m", ErrInvalidExport)
+	case !r.From.Before(r.To):
+		return fmt.Errorf("%w: from must be before to", ErrInvalidExport)
+	case r.To.After(now):
+		return fmt.Errorf("%w: to must not be in the future", ErrInvalidExport)
+	case !r.From.Truncate(r.Bucket).Equal(r.From), !r.To.Truncate(r.Bucket).Equal(r.To):
+		return fmt.Errorf("%w: from and to must fall on %s bucket boundaries", ErrInvalidExport, r.Bucket)
+	}
+	return nil
+}
+
+// Files returns the files of the range for the given symbols, ordered by
+// symbol and then by time
+func (r ExportRange) Files(symbols []string) []ExportFile {
+	layout := "2006-01-02T1504"
+	if r.Bucket%(24*time.Hour) == 0 {
+		layout = "2006-01-02"
+	}
+
+	var files []ExportFile
+	for _, symbol := range symbols {
+		for start := r.From; start.Before(r.To); start = start.Add(r.Bucket) {
+			files = append(files, ExportFile{
+				Symbol: symbol,
+				From:   start,
+				To:     start.Add(r.Bucket),
+				Path:   path.Join(symbol, start.UTC().Format(layout)+".csv.gz"),
+			})
+		}
+	}
+	return files
+}
+
+// ExportFile is the snapshots of one symbol with From <= timestamp < To,
+// stored at Path relative to the export directory
+type ExportFile struct {
+	Symbol string
+	From   time.Time
+	To     time.Time
+	Path   string
+}
+
+// ExportSummary reports the outcome of an export run
+type ExportSummary struct {
+	// Written counts the files created by this run and Skipped those
+	// already present from an earlier one
+	Written int
+	Skipped int
+	Rows    int64
+}
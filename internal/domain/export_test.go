@@ -0,0 +1,90 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+)
+
+func TestParseExportBucket(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Duration
+	}{
+		{"1d", 24 * time.Hour},
+		{"7d", 7 * 24 * time.Hour},
+		{"6h", 6 * time.Hour},
+		{"15m", 15 * time.Minute},
+	}
+	for _, tt := range tests {
+		got, err := domain.ParseExportBucket(tt.in)
+		require.NoError(t, err, tt.in)
+		assert.Equal(t, tt.want, got, tt.in)
+	}
+
+	for _, in := range []string{"", "d", "0d", "-1d", "1w", "0s"} {
+		_, err := domain.ParseExportBucket(in)
+		assert.ErrorIs(t, err, domain.ErrInvalidExport, in)
+	}
+}
+
+func TestExportRange_Validate(t *testing.T) {
+	day := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	now := day.Add(72 * time.Hour)
+
+	tests := []struct {
+		name    string
+		rng     domain.ExportRange
+		wantErr bool
+	}{
+		{"whole days", domain.ExportRange{From: day, To: day.Add(48 * time.Hour), Bucket: 24 * time.Hour}, false},
+		{"hour buckets", domain.ExportRange{From: day.Add(time.Hour), To: day.Add(3 * time.Hour), Bucket: time.Hour}, false},
+		{"empty range", domain.ExportRange{From: day, To: day, Bucket: 24 * time.Hour}, true},
+		{"ends in the future", domain.ExportRange{From: day, To: now.Add(24 * time.Hour), Bucket: 24 * time.Hour}, true},
+		{"from off boundary", domain.ExportRange{From: day.Add(time.Hour), To: day.Add(48 * time.Hour), Bucket: 24 * time.Hour}, true},
+		{"to off boundary", domain.ExportRange{From: day, To: day.Add(30 * time.Minute), Bucket: time.Hour}, true},
+		{"bucket too small", domain.ExportRange{From: day, To: day.Add(time.Minute), Bucket: time.Second}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.rng.Validate(now)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, domain.ErrInvalidExport)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestExportRange_Files(t *testing.T) {
+	day := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+
+	t.Run("names daily files by date", func(t *testing.T) {
+		rng := domain.ExportRange{From: day, To: day.Add(48 * time.Hour), Bucket: 24 * time.Hour}
+
+		files := rng.Files([]string{"BTCUSDT", "ETHUSDT"})
+
+		require.Len(t, files, 4)
+		assert.Equal(t, "BTCUSDT/2024-01-15.csv.gz", files[0].Path)
+		assert.Equal(t, "BTCUSDT/2024-01-16.csv.gz", files[1].Path)
+		assert.Equal(t, "ETHUSDT/2024-01-15.csv.gz", files[2].Path)
+		assert.Equal(t, "ETHUSDT", files[2].Symbol)
+		assert.Equal(t, day.Add(24*time.Hour), files[1].From)
+		assert.Equal(t, day.Add(48*time.Hour), files[1].To)
+	})
+
+	t.Run("names shorter buckets by start time", func(t *testing.T) {
+		rng := domain.ExportRange{From: day, To: day.Add(12 * time.Hour), Bucket: 6 * time.Hour}
+
+		files := rng.Files([]string{"BTCUSDT"})
+
+		require.Len(t, files, 2)
+		assert.Equal(t, "BTCUSDT/2024-01-15T0000.csv.gz", files[0].Path)
+		assert.Equal(t, "BTCUSDT/2024-01-15T0600.csv.gz", files[1].Path)
+	})
+}
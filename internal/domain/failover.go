@@ -0,0 +1,11 @@
+package domain
+
+import "time"
+
+// DatabaseFailover describes a database primary failover in progress: the
+// primary stopped accepting writes at Since and no writable primary has
+// answered yet
+type DatabaseFailover struct {
+	Since  time.Time `json:"since"`
+	Reason string    `json:"reason"`
+}
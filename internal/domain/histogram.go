@@ -0,0 +1,79 @@
+package domain
+
+import "sort"
+
+// PollDurationBuckets are the upper bounds, in seconds, of the poll
+// duration histogram
+var PollDurationBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+// HTTPDurationBuckets are the upper bounds, in seconds, of the HTTP request
+// duration histogram
+var HTTPDurationBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5}
+
+// ExchangeAttemptBuckets are the upper bounds of the exchange request
+// attempts per call histogram
+var ExchangeAttemptBuckets = []float64{1, 2, 3, 4, 5, 10}
+
+// Histogram counts observations into buckets by upper bound. Counts are
+// cumulative: Counts[i] is the number of observations at most Bounds[i],
+// and Count includes those above the last bound.
+type Histogram struct {
+	Bounds []float64 `json:"bounds"`
+	Counts []int64   `json:"counts"`
+	Count  int64     `json:"count"`
+	Sum    float64   `json:"sum"`
+}
+
+// NewHistogram creates an empty histogram with the given ascending bounds
+func NewHistogram(bounds []float64) *Histogram {
+	return &Histogram{
+		Bounds: bounds,
+		Counts: make([]int64, len(bounds)),
+	}
+}
+
+// Observe adds a value to the histogram
+func (h *Histogram) Observe(v float64) {
+	for i := sort.SearchFloat64s(h.Bounds, v); i < len(h.Bounds); i++ {
+		h.Counts[i]++
+	}
+	h.Count++
+	h.Sum += v
+}
+
+// Clone returns a copy of the histogram that does not share its counts
+func (h *Histogram) Clone() *Histogram {
+	c := *h
+	c.Counts = append([]int64(nil), h.Counts...)
+	return &c
+}
+
+// HTTPRequestMetrics is the latency of the HTTP requests of one route,
+// method and status code
+type HTTPRequestMetrics struct {
+	Route    string     `json:"route"`
+	Method   string     `json:"method"`
+	Code     int        `json:"code"`
+	Duration *Histogram `json:"duration_seconds"`
+}
+
+// DatabasePoolStats reports the usage of the database connection pool
+type DatabasePoolStats struct {
+	MaxConns      int32 `json:"max_conns"`
+	TotalConns    int32 `json:"total_conns"`
+	IdleConns     int32 `json:"idle_conns"`
+	AcquiredConns int32 `json:"acquired_conns"`
+
+	// AcquireCount counts connections handed out, EmptyAcquireCount those
+	// that had to wait for a connection and AcquireDurationSeconds the
+	// total time spent waiting
+	AcquireCount           int64   `json:"acquire_count"`
+	EmptyAcquireCount      int64   `json:"empty_acquire_count"`
+	AcquireDurationSeconds float64 `json:"acquire_duration_seconds"`
+}
+
+// SymbolSnapshotCount is the number of snapshots stored for one symbol
+type SymbolSnapshotCount struct {
+	Symbol string `json:"symbol"`
+	Count  int64  `json:"count"`
+}
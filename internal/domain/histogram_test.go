@@ -0,0 +1,25 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+)
+
+func TestHistogram_Observe(t *testing.T) {
+	h := domain.NewHistogram([]float64{0.1, 0.5, 1})
+	for _, v := range []float64{0.05, 0.1, 0.3, 2} {
+		h.Observe(v)
+	}
+
+	assert.Equal(t, []int64{2, 3, 3}, h.Counts)
+	assert.Equal(t, int64(4), h.Count)
+	assert.InDelta(t, 2.45, h.Sum, 1e-9)
+
+	clone := h.Clone()
+	h.Observe(0.01)
+	assert.Equal(t, []int64{2, 3, 3}, clone.Counts)
+	assert.Equal(t, []int64{3, 4, 4}, h.Counts)
+}
@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ErrInvalidIngest is returned for pushed snapshots that fail validation
+var ErrInvalidIngest = errors.New("invalid ingested snapshot")
+
+// MaxIngestBatch is the largest number of snapshots accepted in one push
+const MaxIngestBatch = 1000
+
+// MaxIngestClockSkew is how far in the future a pushed timestamp may be
+const MaxIngestClockSkew = time.Minute
+
+// maxIngestPrice is the exclusive upper bound of a stored price, set by the
+// NUMERIC(24, 8) price column
+var maxIngestPrice = decimal.New(1, 16)
+
+// ingestSource matches a valid source name
+var ingestSource = regexp.MustCompile(`^[a-z0-9][a-z0-9_.-]{0,63}$`)
+
+// IngestedSnapshot is a price snapshot pushed by an external source
+type IngestedSnapshot struct {
+	Symbol    string          `json:"symbol"`
+	Price     decimal.Decimal `json:"price"`
+	Timestamp time.Time       `json:"timestamp"`
+	Source    string          `json:"source"`
+}
+
+// Normalize canonicalizes the symbol and lower-cases the source
+func (s *IngestedSnapshot) Normalize() {
+	s.Symbol = NormalizeSymbol(s.Symbol)
+	s.Source = strings.ToLower(strings.TrimSpace(s.Source))
+	s.Timestamp = s.Timestamp.UTC()
+}
+
+// Validate checks a normalized snapshot. Timestamps may not be zero or
+// more than MaxIngestClockSkew ahead of now.
+func (s *IngestedSnapshot) Validate(now time.Time) error {
+	if err := ValidateSymbolName(s.Symbol); err != nil {
+		return fmt.Errorf("%w: invalid symbol %q", ErrInvalidIngest, s.Symbol)
+	}
+	if !s.Price.IsPositive() || s.Price.GreaterThanOrEqual(maxIngestPrice) {
+		return fmt.Errorf("%w: price must be positive and below %s", ErrInvalidIngest, maxIngestPrice)
+	}
+	if s.Timestamp.IsZero() {
+		return fmt.Errorf("%w: timestamp is required", ErrInvalidIngest)
+	}
+	if s.Timestamp.After(now.Add(MaxIngestClockSkew)) {
+		return fmt.Errorf("%w: timestamp is in the future", ErrInvalidIngest)
+	}
+	if !ingestSource.MatchString(s.Source) {
+		return fmt.Errorf("%w: source must be 1-64 lowercase letters, digits, '_', '.' or '-'", ErrInvalidIngest)
+	}
+	return nil
+}
+
+// IngestResult reports how many pushed snapshots were stored and how many
+// had already been stored by an earlier push
+type IngestResult struct {
+	Accepted   int `json:"accepted"`
+	Duplicates int `json:"duplicates"`
+}
@@ -0,0 +1,47 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+)
+
+func TestIngestedSnapshot_Validate(t *testing.T) {
+	now := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	valid := func() domain.IngestedSnapshot {
+		return domain.IngestedSnapshot{
+			Symbol:    " btcusdt ",
+			Price:     decimal.RequireFromString("42150.5"),
+			Timestamp: now.Add(-time.Minute),
+			Source:    "Desk-Feed",
+		}
+	}
+
+	s := valid()
+	s.Normalize()
+	assert.NoError(t, s.Validate(now))
+	assert.Equal(t, "BTCUSDT", s.Symbol)
+	assert.Equal(t, "desk-feed", s.Source)
+
+	for name, mutate := range map[string]func(*domain.IngestedSnapshot){
+		"invalid symbol":   func(s *domain.IngestedSnapshot) { s.Symbol = "BTC.USDT" },
+		"zero price":       func(s *domain.IngestedSnapshot) { s.Price = decimal.Zero },
+		"negative price":   func(s *domain.IngestedSnapshot) { s.Price = decimal.NewFromInt(-1) },
+		"oversized price":  func(s *domain.IngestedSnapshot) { s.Price = decimal.New(1, 16) },
+		"zero timestamp":   func(s *domain.IngestedSnapshot) { s.Timestamp = time.Time{} },
+		"future timestamp": func(s *domain.IngestedSnapshot) { s.Timestamp = now.Add(2 * time.Minute) },
+		"missing source":   func(s *domain.IngestedSnapshot) { s.Source = "" },
+		"invalid source":   func(s *domain.IngestedSnapshot) { s.Source = "desk feed" },
+	} {
+		t.Run(name, func(t *testing.T) {
+			s := valid()
+			mutate(&s)
+			s.Normalize()
+			assert.ErrorIs(t, s.Validate(now), domain.ErrInvalidIngest)
+		})
+	}
+}
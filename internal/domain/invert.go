@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// minDerivedDigits is the fewest significant digits an inverted or divided
+// price is given, so that deriving from a coarse price such as 0.5 still
+// leaves room for the digits of prices it is compared with
+const minDerivedDigits = 8
+
+// InvertPrice returns 1/price, the price of the inverse pair, rounded to as
+// many significant digits as price has and at least 8: a price known to
+// seven digits does not have a more precise inverse. ok is false for a
+// price that is not positive.
+func InvertPrice(price decimal.Decimal) (inverse decimal.Decimal, ok bool) {
+	return divideSignificant(decimal.NewFromInt(1), price, max(significantDigits(price), minDerivedDigits))
+}
+
+// DividePrice returns numerator/denominator, the price of a pair derived
+// from two pairs quoted in the same asset, rounded to as many significant
+// digits as the more precise of them has and at least 8. ok is false
+// unless both prices are positive.
+func DividePrice(numerator, denominator decimal.Decimal) (quotient decimal.Decimal, ok bool) {
+	digits := max(significantDigits(numerator), significantDigits(denominator), minDerivedDigits)
+	return divideSignificant(numerator, denominator, digits)
+}
+
+// divideSignificant divides two positive numbers, rounding the quotient to
+// the given number of significant digits
+func divideSignificant(numerator, denominator decimal.Decimal, digits int) (decimal.Decimal, bool) {
+	if !numerator.IsPositive() || !denominator.IsPositive() {
+		return decimal.Zero, false
+	}
+
+	// The quotient's leading digit is at most one place below the
+	// difference of the operands' leading digits; place the last kept
+	// digit relative to it
+	guess := leadingExponent(numerator) - leadingExponent(denominator) - 1
+	approx := numerator.DivRound(denominator, int32(digits)-guess+1)
+	places := int32(digits) - 1 - leadingExponent(approx)
+
+	return numerator.DivRound(denominator, places), true
+}
+
+// leadingExponent returns the power of ten of d's first digit
+func leadingExponent(d decimal.Decimal) int32 {
+	return int32(d.NumDigits()) + d.Exponent() - 1
+}
+
+// significantDigits counts the digits of d from its first to its last
+// non-zero one
+func significantDigits(d decimal.Decimal) int {
+	coefficient := strings.TrimRight(d.Coefficient().String(), "0")
+	return len(strings.TrimLeft(coefficient, "-"))
+}
+
+// InvertSymbol returns the name of the inverse of a trading pair, e.g.
+// USDTBTC for BTCUSDT. ok is false when the pair cannot be split into its
+// base and quote assets.
+func InvertSymbol(symbol string) (string, bool) {
+	base, quote, ok := SplitPair(symbol)
+	if !ok {
+		return symbol, false
+	}
+	return quote + base, true
+}
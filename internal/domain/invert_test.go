@@ -0,0 +1,47 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+)
+
+func TestInvertPrice(t *testing.T) {
+	tests := map[string]string{
+		"43123.45":   "0.000023189239",
+		"0.5":        "2",
+		"3":          "0.33333333",
+		"0.00002319": "43122.035",
+		"1":          "1",
+	}
+
+	for price, want := range tests {
+		inverse, ok := domain.InvertPrice(decimal.RequireFromString(price))
+		assert.True(t, ok, price)
+		assert.Equal(t, want, inverse.String(), price)
+	}
+
+	t.Run("rejects prices that are not positive", func(t *testing.T) {
+		_, ok := domain.InvertPrice(decimal.Zero)
+		assert.False(t, ok)
+		_, ok = domain.InvertPrice(decimal.RequireFromString("-2"))
+		assert.False(t, ok)
+	})
+}
+
+func TestInvertSymbol(t *testing.T) {
+	name, ok := domain.InvertSymbol("BTCUSDT")
+	assert.True(t, ok)
+	assert.Equal(t, "USDTBTC", name)
+
+	name, ok = domain.InvertSymbol("USDTBTC")
+	assert.True(t, ok)
+	assert.Equal(t, "BTCUSDT", name)
+
+	name, ok = domain.InvertSymbol("USDT")
+	assert.False(t, ok)
+	assert.Equal(t, "USDT", name)
+}
@@ -0,0 +1,100 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// JobType identifies the kind of work a job performs
+type JobType string
+
+const (
+	JobTypeBackfill     JobType = "backfill"
+	JobTypeImport       JobType = "import"
+	JobTypeRebuildCache JobType = "rebuild-cache"
+	JobTypeRevalidate   JobType = "revalidate"
+	JobTypePrune        JobType = "prune"
+	JobTypeMetadata     JobType = "refresh-metadata"
+	JobTypeAlertWebhook JobType = "alert-webhook"
+	JobTypeMaintenance  JobType = "maintenance"
+)
+
+// JobStatus represents the lifecycle state of a job
+type JobStatus string
+
+const (
+	JobStatusPending   JobStatus = "pending"
+	JobStatusRunning   JobStatus = "running"
+	JobStatusSucceeded JobStatus = "succeeded"
+	JobStatusFailed    JobStatus = "failed"
+	JobStatusCancelled JobStatus = "cancelled"
+)
+
+// JobLeaseExpiredError is the error of a job whose worker stopped sending
+// heartbeats during its last attempt, e.g. because the job crashed the
+// process. Such a job is failed instead of claimed again.
+const JobLeaseExpiredError = "worker stopped responding during the last attempt"
+
+// Job represents a unit of background work tracked in the job queue
+type Job struct {
+	ID          int64           `json:"id"`
+	Type        JobType         `json:"type"`
+	Status      JobStatus       `json:"status"`
+	Payload     json.RawMessage `json:"payload,omitempty"`
+	Progress    JobProgress     `json:"progress"`
+	Attempts    int             `json:"attempts"`
+	MaxAttempts int             `json:"max_attempts"`
+	Error       string          `json:"error,omitempty"`
+	ScheduledAt time.Time       `json:"scheduled_at"`
+	StartedAt   *time.Time      `json:"started_at,omitempty"`
+	FinishedAt  *time.Time      `json:"finished_at,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
+
+// JobProgress records how far a job has advanced
+type JobProgress struct {
+	Processed int64  `json:"processed"`
+	Total     int64  `json:"total,omitempty"`
+	Cursor    string `json:"cursor,omitempty"`
+}
+
+// NewJob creates a pending job scheduled to run at runAt
+func NewJob(jobType JobType, payload json.RawMessage, runAt time.Time, maxAttempts int) (*Job, error) {
+	if !jobType.Valid() {
+		return nil, ErrInvalidJobType
+	}
+
+	now := time.Now().UTC()
+	if runAt.IsZero() {
+		runAt = now
+	}
+
+	return &Job{
+		Type:        jobType,
+		Status:      JobStatusPending,
+		Payload:     payload,
+		MaxAttempts: maxAttempts,
+		ScheduledAt: runAt.UTC(),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}, nil
+}
+
+// Valid reports whether the job type is known
+func (t JobType) Valid() bool {
+	switch t {
+	case JobTypeBackfill, JobTypeImport, JobTypeRebuildCache, JobTypeRevalidate, JobTypePrune, JobTypeMetadata, JobTypeAlertWebhook, JobTypeMaintenance:
+		return true
+	}
+	return false
+}
+
+// Finished reports whether the job reached a terminal state
+func (j *Job) Finished() bool {
+	switch j.Status {
+	case JobStatusSucceeded, JobStatusFailed, JobStatusCancelled:
+		return true
+	}
+	return false
+}
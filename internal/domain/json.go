@@ -0,0 +1,28 @@
+package domain
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/shopspring/decimal"
+)
+
+// exactDecimal marshals a decimal as its exact string form. Unlike
+// decimal.Decimal it ignores decimal.MarshalJSONWithoutQuotes, so a price can
+// never reach a client as a JSON number that it would parse into a float.
+type exactDecimal decimal.Decimal
+
+// MarshalJSON implements json.Marshaler
+func (d exactDecimal) MarshalJSON() ([]byte, error) {
+	return json.Marshal(decimal.Decimal(d).String())
+}
+
+// plainFloat marshals a float64 in positional notation. encoding/json
+// switches to exponent form for very small or very large values, which some
+// dashboards fail to parse.
+type plainFloat float64
+
+// MarshalJSON implements json.Marshaler
+func (f plainFloat) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.FormatFloat(float64(f), 'f', -1, 64)), nil
+}
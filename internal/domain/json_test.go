@@ -0,0 +1,86 @@
+package domain_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPriceJSON_ExactDecimals(t *testing.T) {
+	tests := []struct {
+		name  string
+		price string
+	}{
+		{"SHIBUSDT", "0.00000812"},
+		{"sub-satoshi", "0.000000000000000001"},
+		{"trailing precision", "0.1000000000000000055511151231257827"},
+		{"large", "123456789012345678901234.12345678"},
+		{"integer", "43000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			price := decimal.RequireFromString(tt.price)
+
+			data, err := json.Marshal(domain.Price{Symbol: "SHIBUSDT", Price: price})
+			require.NoError(t, err)
+
+			var raw map[string]interface{}
+			require.NoError(t, json.Unmarshal(data, &raw))
+			assert.Equal(t, tt.price, raw["price"])
+
+			var decoded domain.Price
+			require.NoError(t, json.Unmarshal(data, &decoded))
+			assert.True(t, price.Equal(decoded.Price))
+		})
+	}
+}
+
+func TestPriceJSON_IgnoresWithoutQuotes(t *testing.T) {
+	decimal.MarshalJSONWithoutQuotes = true
+	defer func() { decimal.MarshalJSONWithoutQuotes = false }()
+
+	now := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	snapshot := domain.PriceSnapshot{
+		ID:              1,
+		Symbol:          "SHIBUSDT",
+		Price:           decimal.RequireFromString("0.00000812"),
+		Timestamp:       now,
+		TimestampSource: domain.TimestampSourcePoll,
+	}
+
+	for _, v := range []interface{}{snapshot, &snapshot, domain.Price{Symbol: "SHIBUSDT", Price: snapshot.Price}} {
+		data, err := json.Marshal(v)
+		require.NoError(t, err)
+		assert.Contains(t, string(data), `"price":"0.00000812"`)
+	}
+
+	data, err := json.Marshal(snapshot)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"timestamp":"2024-01-15T10:30:00Z"`)
+	assert.Contains(t, string(data), `"timestamp_source":"poll"`)
+}
+
+func TestMetricsJSON_PositionalFloats(t *testing.T) {
+	metrics := domain.Metrics{
+		Uptime:           1e21,
+		LastPollDuration: 0.0000005,
+		TotalSnapshots:   42,
+	}
+
+	data, err := json.Marshal(metrics)
+	require.NoError(t, err)
+
+	assert.Contains(t, string(data), `"uptime_seconds":1000000000000000000000`)
+	assert.Contains(t, string(data), `"last_poll_duration_ms":0.0000005`)
+	assert.Contains(t, string(data), `"total_snapshots":42`)
+
+	var decoded domain.Metrics
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, metrics, decoded)
+}
@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ErrInvalidMetricLabel is returned for malformed constant metric labels
+var ErrInvalidMetricLabel = errors.New("invalid metric label")
+
+// metricLabelName matches a valid Prometheus label name
+var metricLabelName = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// BuildInfo identifies the running binary
+type BuildInfo struct {
+	Version   string `json:"version"`
+	Revision  string `json:"revision,omitempty"`
+	GoVersion string `json:"go_version"`
+}
+
+// EffectiveConfig is the configuration an instance runs with once defaults
+// are applied, exported so that dashboards spanning a fleet can spot
+// instances configured unlike the rest
+type EffectiveConfig struct {
+	PollerMode      string  `json:"poller_mode"`
+	PollInterval    float64 `json:"poll_interval_seconds"`
+	RetentionDays   int     `json:"retention_days"`
+	MaxHistoryLimit int     `json:"max_history_limit"`
+}
+
+// ParseMetricLabels parses "name=value" pairs into constant labels attached
+// to every exported metric. Names beginning with "__" are reserved by
+// Prometheus.
+func ParseMetricLabels(specs []string) (map[string]string, error) {
+	if len(specs) == 0 {
+		return nil, nil
+	}
+
+	labels := make(map[string]string, len(specs))
+	for _, spec := range specs {
+		name, value, ok := strings.Cut(spec, "=")
+		name = strings.TrimSpace(name)
+		if !ok || !metricLabelName.MatchString(name) || strings.HasPrefix(name, "__") {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidMetricLabel, spec)
+		}
+		if _, dup := labels[name]; dup {
+			return nil, fmt.Errorf("%w: duplicate label %q", ErrInvalidMetricLabel, name)
+		}
+		labels[name] = strings.TrimSpace(value)
+	}
+	return labels, nil
+}
@@ -0,0 +1,31 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+)
+
+func TestParseMetricLabels(t *testing.T) {
+	labels, err := domain.ParseMetricLabels([]string{"environment=prod", " region = eu-west-1", "role="})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"environment": "prod", "region": "eu-west-1", "role": ""}, labels)
+
+	labels, err = domain.ParseMetricLabels(nil)
+	require.NoError(t, err)
+	assert.Nil(t, labels)
+
+	for _, specs := range [][]string{
+		{"environment"},
+		{"1env=prod"},
+		{"env-name=prod"},
+		{"__name__=x"},
+		{"env=a", "env=b"},
+	} {
+		_, err := domain.ParseMetricLabels(specs)
+		assert.ErrorIs(t, err, domain.ErrInvalidMetricLabel, specs)
+	}
+}
@@ -0,0 +1,63 @@
+package domain
+
+// MigrationPolicy decides how startup handles a schema that is dirty or
+// newer than the migrations shipped with the binary
+type MigrationPolicy string
+
+const (
+	// MigrationPolicyFail refuses to start
+	MigrationPolicyFail MigrationPolicy = "fail"
+
+	// MigrationPolicyWarn logs a warning and starts without migrating
+	MigrationPolicyWarn MigrationPolicy = "warn"
+
+	// MigrationPolicyForce clears the dirty flag at the current version and
+	// migrates up; a newer schema is left untouched as with warn
+	MigrationPolicyForce MigrationPolicy = "force"
+)
+
+// MigrationState summarizes the schema version against the binary
+type MigrationState string
+
+const (
+	// MigrationStateCurrent means every known migration is applied
+	MigrationStateCurrent MigrationState = "current"
+
+	// MigrationStatePending means known migrations are not yet applied
+	MigrationStatePending MigrationState = "pending"
+
+	// MigrationStateDirty means a migration failed part-way
+	MigrationStateDirty MigrationState = "dirty"
+
+	// MigrationStateAhead means the schema is newer than the binary knows
+	MigrationStateAhead MigrationState = "ahead"
+)
+
+// MigrationStatus reports the applied schema version against the latest
+// migration known to the running binary
+type MigrationStatus struct {
+	Version uint           `json:"version"`
+	Dirty   bool           `json:"dirty"`
+	Latest  uint           `json:"latest"`
+	State   MigrationState `json:"state"`
+}
+
+// NewMigrationStatus derives the migration state from the applied version
+func NewMigrationStatus(version uint, dirty bool, latest uint) *MigrationStatus {
+	state := MigrationStateCurrent
+	switch {
+	case dirty:
+		state = MigrationStateDirty
+	case version > latest:
+		state = MigrationStateAhead
+	case version < latest:
+		state = MigrationStatePending
+	}
+
+	return &MigrationStatus{
+		Version: version,
+		Dirty:   dirty,
+		Latest:  latest,
+		State:   state,
+	}
+}
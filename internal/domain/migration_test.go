@@ -0,0 +1,34 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewMigrationStatus(t *testing.T) {
+	tests := []struct {
+		name    string
+		version uint
+		dirty   bool
+		latest  uint
+		want    domain.MigrationState
+	}{
+		{"current", 5, false, 5, domain.MigrationStateCurrent},
+		{"pending", 3, false, 5, domain.MigrationStatePending},
+		{"fresh database", 0, false, 5, domain.MigrationStatePending},
+		{"ahead", 7, false, 5, domain.MigrationStateAhead},
+		{"dirty wins over ahead", 7, true, 5, domain.MigrationStateDirty},
+		{"dirty", 4, true, 5, domain.MigrationStateDirty},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := domain.NewMigrationStatus(tt.version, tt.dirty, tt.latest)
+			assert.Equal(t, tt.want, status.State)
+			assert.Equal(t, tt.version, status.Version)
+			assert.Equal(t, tt.latest, status.Latest)
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package domain
+
+import "github.com/shopspring/decimal"
+
+// PercentPlaces is the number of decimal places reported percentages and
+// basis points are rounded to
+const PercentPlaces = 2
+
+// RoundingMode selects how a computed value is rounded to its places
+type RoundingMode int
+
+const (
+	// RoundHalfUp rounds halves away from zero (2.345 -> 2.35, -2.345 -> -2.35)
+	RoundHalfUp RoundingMode = iota
+
+	// RoundHalfEven rounds halves to the even neighbour (2.345 -> 2.34)
+	RoundHalfEven
+
+	// RoundDown truncates toward zero
+	RoundDown
+
+	// RoundUp rounds away from zero
+	RoundUp
+)
+
+var (
+	hundred     = decimal.NewFromInt(100)
+	tenThousand = decimal.NewFromInt(10000)
+	decimalTwo  = decimal.NewFromInt(2)
+)
+
+// Ratio returns a / b rounded to places. A zero b yields zero.
+func Ratio(a, b decimal.Decimal, places int32, mode RoundingMode) decimal.Decimal {
+	if b.IsZero() {
+		return decimal.Zero
+	}
+	return divide(a, b, places, mode)
+}
+
+// PercentChange returns the change from one value to another in percent,
+// rounded to places. A zero from yields zero.
+func PercentChange(from, to decimal.Decimal, places int32, mode RoundingMode) decimal.Decimal {
+	return Ratio(to.Sub(from).Mul(hundred), from, places, mode)
+}
+
+// BasisPoints returns the change from one value to another in basis points
+// (hundredths of a percent), rounded to places. A zero from yields zero.
+func BasisPoints(from, to decimal.Decimal, places int32, mode RoundingMode) decimal.Decimal {
+	return Ratio(to.Sub(from).Mul(tenThousand), from, places, mode)
+}
+
+// divide rounds num / den to places from the exact quotient and remainder,
+// so the result never depends on an intermediate division precision
+func divide(num, den decimal.Decimal, places int32, mode RoundingMode) decimal.Decimal {
+	q, r := num.QuoRem(den, places)
+	if r.IsZero() {
+		return q
+	}
+
+	// q is truncated toward zero; step moves it one unit away from zero
+	step := decimal.New(1, -places)
+	if num.Sign() != den.Sign() {
+		step = step.Neg()
+	}
+
+	// Compare the discarded fraction with one half of a unit
+	half := r.Abs().Mul(decimalTwo).Cmp(den.Abs().Mul(decimal.New(1, -places)))
+
+	switch mode {
+	case RoundDown:
+		return q
+	case RoundUp:
+		return q.Add(step)
+	case RoundHalfEven:
+		if half > 0 || (half == 0 && q.Shift(places).BigInt().Bit(0) == 1) {
+			return q.Add(step)
+		}
+		return q
+	default:
+		if half >= 0 {
+			return q.Add(step)
+		}
+		return q
+	}
+}
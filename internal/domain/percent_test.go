@@ -0,0 +1,67 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+)
+
+func TestRatio_RoundingModes(t *testing.T) {
+	d := decimal.RequireFromString
+
+	tests := []struct {
+		name string
+		a, b string
+		mode domain.RoundingMode
+		want string
+	}{
+		{"half up rounds half away from zero", "2.345", "1", domain.RoundHalfUp, "2.35"},
+		{"half up rounds negative half away from zero", "-2.345", "1", domain.RoundHalfUp, "-2.35"},
+		{"half even rounds half to even", "2.345", "1", domain.RoundHalfEven, "2.34"},
+		{"half even rounds odd half up", "2.355", "1", domain.RoundHalfEven, "2.36"},
+		{"half even rounds above half up", "2.3451", "1", domain.RoundHalfEven, "2.35"},
+		{"down truncates", "2.349", "1", domain.RoundDown, "2.34"},
+		{"down truncates negatives toward zero", "-2.349", "1", domain.RoundDown, "-2.34"},
+		{"up rounds away from zero", "2.341", "1", domain.RoundUp, "2.35"},
+		{"negative divisor", "1", "-3", domain.RoundHalfUp, "-0.33"},
+		{"repeating quotient", "2", "3", domain.RoundHalfUp, "0.67"},
+		{"exact quotient", "1", "4", domain.RoundUp, "0.25"},
+		{"zero divisor", "1", "0", domain.RoundHalfUp, "0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := domain.Ratio(d(tt.a), d(tt.b), 2, tt.mode)
+			assert.True(t, got.Equal(d(tt.want)), "got %s, want %s", got, tt.want)
+		})
+	}
+}
+
+func TestRatio_NoDoubleRounding(t *testing.T) {
+	// Just below one half of a cent: an intermediate 16-digit division
+	// would round this up to 0.005 and then to 0.01
+	a := decimal.RequireFromString("0.00499999999999999999")
+
+	got := domain.Ratio(a, decimal.NewFromInt(1), 2, domain.RoundHalfUp)
+
+	assert.True(t, got.IsZero(), "got %s", got)
+}
+
+func TestPercentChange(t *testing.T) {
+	d := decimal.RequireFromString
+
+	assert.Equal(t, "10", domain.PercentChange(d("100"), d("110"), 2, domain.RoundHalfUp).String())
+	assert.Equal(t, "-33.33", domain.PercentChange(d("3"), d("2"), 2, domain.RoundHalfUp).String())
+	assert.Equal(t, "-33.34", domain.PercentChange(d("3"), d("2"), 2, domain.RoundUp).String())
+	assert.True(t, domain.PercentChange(d("0"), d("5"), 2, domain.RoundHalfUp).IsZero())
+}
+
+func TestBasisPoints(t *testing.T) {
+	d := decimal.RequireFromString
+
+	assert.Equal(t, "125", domain.BasisPoints(d("40000"), d("40500"), 2, domain.RoundHalfUp).String())
+	assert.Equal(t, "-0.25", domain.BasisPoints(d("40000"), d("39999"), 2, domain.RoundHalfUp).String())
+}
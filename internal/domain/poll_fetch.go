@@ -0,0 +1,78 @@
+package domain
+
+// PollFetchMode selects how a poll fetches prices from the exchange
+type PollFetchMode string
+
+const (
+	// PollFetchBulk fetches every symbol's price in one request
+	PollFetchBulk PollFetchMode = "bulk"
+
+	// PollFetchIndividual fetches each symbol's price in its own request,
+	// so one bad symbol only loses its own snapshot
+	PollFetchIndividual PollFetchMode = "individual"
+
+	// PollFetchAuto fetches in bulk unless recent bulk fetches kept failing
+	// and there are few enough symbols to fetch one by one
+	PollFetchAuto PollFetchMode = "auto"
+
+	// PollFetchStream marks a poll that wrote prices pushed by the
+	// exchange's stream. It is recorded on stream-mode runs and cannot be
+	// configured as a fetch mode.
+	PollFetchStream PollFetchMode = "stream"
+)
+
+const (
+	// AutoFetchMaxIndividual is the most symbols auto mode fetches one by
+	// one; more would cost too much exchange request weight
+	AutoFetchMaxIndividual = 20
+
+	// autoFetchWindow is the number of recent polls auto mode looks at
+	autoFetchWindow = 10
+
+	// autoFetchFailures is how many of those polls must have had a failed
+	// bulk fetch for auto mode to fetch one by one
+	autoFetchFailures = 3
+)
+
+// Valid reports whether the fetch mode is known
+func (m PollFetchMode) Valid() bool {
+	switch m {
+	case PollFetchBulk, PollFetchIndividual, PollFetchAuto:
+		return true
+	}
+	return false
+}
+
+// PollFetchHistory remembers which of the recent polls had a failed bulk
+// fetch, for auto mode to choose between bulk and individual fetches. The
+// zero value is an empty history.
+type PollFetchHistory struct {
+	failed [autoFetchWindow]bool
+	next   int
+}
+
+// Record adds a poll's fetch to the history. Individual fetches count as
+// no bulk failure, so failures age out and bulk fetching is tried again
+// after a while.
+func (h *PollFetchHistory) Record(mode PollFetchMode, failed bool) {
+	h.failed[h.next] = mode == PollFetchBulk && failed
+	h.next = (h.next + 1) % autoFetchWindow
+}
+
+// Choose returns the mode auto mode fetches the given number of symbols with
+func (h *PollFetchHistory) Choose(symbols int) PollFetchMode {
+	if symbols > AutoFetchMaxIndividual {
+		return PollFetchBulk
+	}
+
+	failures := 0
+	for _, failed := range h.failed {
+		if failed {
+			failures++
+		}
+	}
+	if failures >= autoFetchFailures {
+		return PollFetchIndividual
+	}
+	return PollFetchBulk
+}
@@ -0,0 +1,57 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+)
+
+func TestPollFetchMode_Valid(t *testing.T) {
+	assert.True(t, domain.PollFetchBulk.Valid())
+	assert.True(t, domain.PollFetchIndividual.Valid())
+	assert.True(t, domain.PollFetchAuto.Valid())
+	assert.False(t, domain.PollFetchMode("batch").Valid())
+}
+
+func TestPollFetchHistory_Choose(t *testing.T) {
+	t.Run("fetches in bulk without failures", func(t *testing.T) {
+		var h domain.PollFetchHistory
+		assert.Equal(t, domain.PollFetchBulk, h.Choose(5))
+	})
+
+	t.Run("fetches individually after repeated bulk failures", func(t *testing.T) {
+		var h domain.PollFetchHistory
+		h.Record(domain.PollFetchBulk, true)
+		h.Record(domain.PollFetchBulk, false)
+		h.Record(domain.PollFetchBulk, true)
+		assert.Equal(t, domain.PollFetchBulk, h.Choose(5))
+
+		h.Record(domain.PollFetchBulk, true)
+		assert.Equal(t, domain.PollFetchIndividual, h.Choose(5))
+	})
+
+	t.Run("keeps fetching in bulk with many symbols", func(t *testing.T) {
+		var h domain.PollFetchHistory
+		for i := 0; i < 3; i++ {
+			h.Record(domain.PollFetchBulk, true)
+		}
+		assert.Equal(t, domain.PollFetchIndividual, h.Choose(domain.AutoFetchMaxIndividual))
+		assert.Equal(t, domain.PollFetchBulk, h.Choose(domain.AutoFetchMaxIndividual+1))
+	})
+
+	t.Run("retries bulk once failures age out", func(t *testing.T) {
+		var h domain.PollFetchHistory
+		for i := 0; i < 3; i++ {
+			h.Record(domain.PollFetchBulk, true)
+		}
+		for i := 0; i < 7; i++ {
+			h.Record(domain.PollFetchIndividual, false)
+		}
+		assert.Equal(t, domain.PollFetchIndividual, h.Choose(5))
+
+		h.Record(domain.PollFetchIndividual, false)
+		assert.Equal(t, domain.PollFetchBulk, h.Choose(5))
+	})
+}
@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"crypto/rand"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// pollRunIDPattern matches a UUID in canonical form
+var pollRunIDPattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
+
+// NewPollRunID returns a random (version 4) UUID identifying one poll. The
+// snapshots a poll stores are tagged with it, so storing them again under
+// the same ID is a no-op.
+func NewPollRunID() string {
+	var b [16]byte
+	// crypto/rand.Read never returns an error
+	_, _ = rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// NormalizePollRunID lowercases a poll run ID and reports whether it is a
+// UUID
+func NormalizePollRunID(id string) (string, bool) {
+	id = strings.ToLower(strings.TrimSpace(id))
+	return id, pollRunIDPattern.MatchString(id)
+}
+
+// PollRun is the data one poll stored
+type PollRun struct {
+	ID        string           `json:"run_id"`
+	Snapshots []*PriceSnapshot `json:"snapshots"`
+}
@@ -0,0 +1,31 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+)
+
+func TestNewPollRunID(t *testing.T) {
+	id := domain.NewPollRunID()
+
+	normalized, ok := domain.NormalizePollRunID(id)
+	assert.True(t, ok)
+	assert.Equal(t, id, normalized)
+	assert.Equal(t, byte('4'), id[14], "version 4")
+	assert.Contains(t, "89ab", string(id[19]), "RFC 4122 variant")
+	assert.NotEqual(t, id, domain.NewPollRunID())
+}
+
+func TestNormalizePollRunID(t *testing.T) {
+	id, ok := domain.NormalizePollRunID(" 3F2504E0-4F89-41D3-9A0C-0305E82C3301 ")
+	assert.True(t, ok)
+	assert.Equal(t, "3f2504e0-4f89-41d3-9a0c-0305e82c3301", id)
+
+	for _, invalid := range []string{"", "42", "3f2504e0-4f89-41d3-9a0c-0305e82c330", "3f2504e04f8941d39a0c0305e82c3301"} {
+		_, ok := domain.NormalizePollRunID(invalid)
+		assert.False(t, ok, invalid)
+	}
+}
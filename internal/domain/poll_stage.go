@@ -0,0 +1,53 @@
+package domain
+
+import "time"
+
+// PollStage identifies one step of a price poll
+type PollStage string
+
+const (
+	PollStageListSymbols PollStage = "list_symbols"
+	PollStageFetch       PollStage = "fetch"
+	PollStageBuild       PollStage = "build"
+	PollStageStore       PollStage = "store"
+	PollStageTickerStats PollStage = "ticker_stats"
+)
+
+// PollBudget bounds the I/O stages of a poll so that one slow stage cannot
+// use up the whole poll timeout. Zero leaves a stage bounded only by the
+// poll itself. Building snapshots is CPU-only and is measured but not bounded.
+type PollBudget struct {
+	ListSymbols time.Duration
+	Fetch       time.Duration
+	Store       time.Duration
+}
+
+// For returns the budget of a stage. Ticker stats are fetched and stored
+// in one stage, so they get the fetch and store budgets combined.
+func (b PollBudget) For(stage PollStage) time.Duration {
+	switch stage {
+	case PollStageListSymbols:
+		return b.ListSymbols
+	case PollStageFetch:
+		return b.Fetch
+	case PollStageStore:
+		return b.Store
+	case PollStageTickerStats:
+		if b.Fetch == 0 || b.Store == 0 {
+			return 0
+		}
+		return b.Fetch + b.Store
+	default:
+		return 0
+	}
+}
+
+// PollStageMetrics reports the durations of one poll stage across polls
+type PollStageMetrics struct {
+	Stage          PollStage `json:"stage"`
+	Count          int64     `json:"count"`
+	LastDurationMs float64   `json:"last_duration_ms"`
+	MaxDurationMs  float64   `json:"max_duration_ms"`
+	AvgDurationMs  float64   `json:"avg_duration_ms"`
+	Timeouts       int64     `json:"timeouts"`
+}
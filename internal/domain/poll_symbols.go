@@ -0,0 +1,86 @@
+package domain
+
+import "time"
+
+// PollExclusionReason explains why a symbol got no snapshot from a poll
+type PollExclusionReason string
+
+const (
+	// PollExclusionInactive is a tracked symbol that is not active
+	PollExclusionInactive PollExclusionReason = "inactive"
+
+	// PollExclusionOtherGroup is an active symbol polled by another poller group
+	PollExclusionOtherGroup PollExclusionReason = "other_group"
+
+	// PollExclusionNoPrice is a polled symbol the exchange returned no price
+	// for, or a synthetic symbol the poll did not price both pairs of
+	PollExclusionNoPrice PollExclusionReason = "no_price"
+
+	// PollExclusionFetchFailed is a symbol whose price could not be fetched
+	// when fetching prices individually
+	PollExclusionFetchFailed PollExclusionReason = "fetch_failed"
+
+	// PollExclusionWriteFailed is a polled symbol whose snapshot was rejected
+	// by a partial write
+	PollExclusionWriteFailed PollExclusionReason = "write_failed"
+)
+
+// PollExclusion is a symbol a poll did not store a snapshot for
+type PollExclusion struct {
+	Symbol string              `json:"symbol"`
+	Reason PollExclusionReason `json:"reason"`
+	Detail string              `json:"detail,omitempty"`
+}
+
+// PollSymbols is the effective symbol list of one poll: the symbols it
+// requested prices for and the tracked symbols it stored nothing for
+type PollSymbols struct {
+	RunID    string          `json:"run_id"`
+	Group    string          `json:"group,omitempty"`
+	PolledAt time.Time       `json:"polled_at"`
+	Included []string        `json:"included"`
+	Excluded []PollExclusion `json:"excluded"`
+	Error    string          `json:"error,omitempty"`
+
+	// FetchMode is how the prices were fetched: bulk, individual or stream
+	FetchMode PollFetchMode `json:"fetch_mode,omitempty"`
+
+	// FetchLatencyMs is how long the exchange took to return the prices
+	FetchLatencyMs *int64 `json:"fetch_latency_ms,omitempty"`
+}
+
+// NewPollSymbols creates an empty effective symbol list for the poll
+// identified by runID
+func NewPollSymbols(runID, group string, polledAt time.Time) *PollSymbols {
+	return &PollSymbols{
+		RunID:    runID,
+		Group:    group,
+		PolledAt: polledAt.UTC(),
+		Included: []string{},
+		Excluded: []PollExclusion{},
+	}
+}
+
+// Exclude records a symbol the poll stored nothing for
+func (p *PollSymbols) Exclude(symbol string, reason PollExclusionReason, detail string) {
+	p.Excluded = append(p.Excluded, PollExclusion{Symbol: symbol, Reason: reason, Detail: detail})
+}
+
+// ForSymbol returns a copy of the list reduced to one symbol
+func (p *PollSymbols) ForSymbol(symbol string) *PollSymbols {
+	filtered := NewPollSymbols(p.RunID, p.Group, p.PolledAt)
+	filtered.Error = p.Error
+	filtered.FetchMode = p.FetchMode
+	filtered.FetchLatencyMs = p.FetchLatencyMs
+	for _, name := range p.Included {
+		if name == symbol {
+			filtered.Included = append(filtered.Included, name)
+		}
+	}
+	for _, e := range p.Excluded {
+		if e.Symbol == symbol {
+			filtered.Excluded = append(filtered.Excluded, e)
+		}
+	}
+	return filtered
+}
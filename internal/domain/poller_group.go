@@ -0,0 +1,186 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrInvalidPollerGroup is returned for malformed poller group definitions
+var ErrInvalidPollerGroup = errors.New("invalid poller group")
+
+// MinPollInterval is the shortest interval a poller may run at
+const MinPollInterval = 5 * time.Second
+
+// SymbolSelector chooses which active symbols a poller group polls. A
+// selector with neither a tag nor symbols is a catch-all.
+type SymbolSelector struct {
+	Tag     string
+	Symbols []string
+}
+
+// CatchAll reports whether the selector matches symbols no other group claims
+func (s SymbolSelector) CatchAll() bool {
+	return s.Tag == "" && len(s.Symbols) == 0
+}
+
+// Matches reports whether an explicit selector selects the symbol. A
+// catch-all selector matches nothing on its own.
+func (s SymbolSelector) Matches(symbol *Symbol) bool {
+	if s.Tag != "" {
+		return symbol.HasTag(s.Tag)
+	}
+	for _, name := range s.Symbols {
+		if name == symbol.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns the selector in its configuration form
+func (s SymbolSelector) String() string {
+	switch {
+	case s.Tag != "":
+		return "tag=" + s.Tag
+	case len(s.Symbols) > 0:
+		return strings.Join(s.Symbols, "|")
+	default:
+		return "*"
+	}
+}
+
+// ParseSymbolSelector parses "*", "tag=<tag>" or a "|"-separated symbol list
+func ParseSymbolSelector(spec string) (SymbolSelector, error) {
+	spec = strings.TrimSpace(spec)
+
+	if spec == "*" {
+		return SymbolSelector{}, nil
+	}
+
+	if tag, ok := strings.CutPrefix(spec, "tag="); ok {
+		normalized, err := NormalizeTag(tag)
+		if err != nil {
+			return SymbolSelector{}, fmt.Errorf("%w: selector tag %q", ErrInvalidPollerGroup, tag)
+		}
+		return SymbolSelector{Tag: normalized}, nil
+	}
+
+	var symbols []string
+	for _, name := range strings.Split(spec, "|") {
+		name = NormalizeSymbol(name)
+		if err := ValidateSymbolName(name); err != nil {
+			return SymbolSelector{}, fmt.Errorf("%w: selector symbol %q", ErrInvalidPollerGroup, name)
+		}
+		symbols = append(symbols, name)
+	}
+
+	return SymbolSelector{Symbols: symbols}, nil
+}
+
+// PollerGroup is a named poller with its own interval and symbol selector
+type PollerGroup struct {
+	Name     string
+	Interval time.Duration
+	Selector SymbolSelector
+}
+
+// ParsePollerGroup parses a "name:interval:selector" definition,
+// e.g. "fast:5s:BTCUSDT|ETHUSDT", "defi:1m:tag=defi" or "slow:5m:*"
+func ParsePollerGroup(spec string) (PollerGroup, error) {
+	parts := strings.SplitN(strings.TrimSpace(spec), ":", 3)
+	if len(parts) != 3 {
+		return PollerGroup{}, fmt.Errorf("%w: %q must be name:interval:selector", ErrInvalidPollerGroup, spec)
+	}
+
+	name, err := NormalizeTag(parts[0])
+	if err != nil {
+		return PollerGroup{}, fmt.Errorf("%w: name %q", ErrInvalidPollerGroup, parts[0])
+	}
+
+	interval, err := time.ParseDuration(strings.TrimSpace(parts[1]))
+	if err != nil || interval < MinPollInterval || interval > 24*time.Hour {
+		return PollerGroup{}, fmt.Errorf("%w: %s interval %q must be between 5s and 24h", ErrInvalidPollerGroup, name, parts[1])
+	}
+
+	selector, err := ParseSymbolSelector(parts[2])
+	if err != nil {
+		return PollerGroup{}, err
+	}
+
+	return PollerGroup{Name: name, Interval: interval, Selector: selector}, nil
+}
+
+// PollerGroups is the set of poller groups running in one process
+type PollerGroups []PollerGroup
+
+// ParsePollerGroups parses group definitions, rejecting duplicate names
+// and more than one catch-all group
+func ParsePollerGroups(specs []string) (PollerGroups, error) {
+	groups := make(PollerGroups, 0, len(specs))
+	seen := make(map[string]bool, len(specs))
+	catchAll := ""
+
+	for _, spec := range specs {
+		group, err := ParsePollerGroup(spec)
+		if err != nil {
+			return nil, err
+		}
+
+		if seen[group.Name] {
+			return nil, fmt.Errorf("%w: duplicate name %q", ErrInvalidPollerGroup, group.Name)
+		}
+		seen[group.Name] = true
+
+		if group.Selector.CatchAll() {
+			if catchAll != "" {
+				return nil, fmt.Errorf("%w: %q and %q are both catch-all", ErrInvalidPollerGroup, catchAll, group.Name)
+			}
+			catchAll = group.Name
+		}
+
+		groups = append(groups, group)
+	}
+
+	return groups, nil
+}
+
+// Select returns the symbols polled by the named group. Explicit groups
+// poll every symbol they match, even if another explicit group matches it
+// too; the catch-all group polls the symbols no explicit group matches.
+func (g PollerGroups) Select(name string, symbols []*Symbol) []*Symbol {
+	var group *PollerGroup
+	for i := range g {
+		if g[i].Name == name {
+			group = &g[i]
+			break
+		}
+	}
+	if group == nil {
+		return nil
+	}
+
+	var selected []*Symbol
+	for _, symbol := range symbols {
+		if group.Selector.CatchAll() {
+			if !g.claimed(symbol) {
+				selected = append(selected, symbol)
+			}
+		} else if group.Selector.Matches(symbol) {
+			selected = append(selected, symbol)
+		}
+	}
+
+	return selected
+}
+
+// claimed reports whether any explicit group selects the symbol
+func (g PollerGroups) claimed(symbol *Symbol) bool {
+	for _, group := range g {
+		if !group.Selector.CatchAll() && group.Selector.Matches(symbol) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,100 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePollerGroup(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		want    domain.PollerGroup
+		wantErr bool
+	}{
+		{
+			name: "symbol list",
+			spec: "fast:5s:btcusdt|ETHUSDT",
+			want: domain.PollerGroup{Name: "fast", Interval: 5 * time.Second, Selector: domain.SymbolSelector{Symbols: []string{"BTCUSDT", "ETHUSDT"}}},
+		},
+		{
+			name: "tag",
+			spec: "defi:1m:tag=DeFi",
+			want: domain.PollerGroup{Name: "defi", Interval: time.Minute, Selector: domain.SymbolSelector{Tag: "defi"}},
+		},
+		{
+			name: "catch-all",
+			spec: "slow:5m:*",
+			want: domain.PollerGroup{Name: "slow", Interval: 5 * time.Minute},
+		},
+		{name: "missing selector", spec: "fast:5s", wantErr: true},
+		{name: "interval too short", spec: "fast:1s:*", wantErr: true},
+		{name: "bad interval", spec: "fast:soon:*", wantErr: true},
+		{name: "bad name", spec: "fa st:5s:*", wantErr: true},
+		{name: "bad symbol", spec: "fast:5s:BTC.USDT", wantErr: true},
+		{name: "bad tag", spec: "fast:5s:tag=", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			group, err := domain.ParsePollerGroup(tt.spec)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, domain.ErrInvalidPollerGroup)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, group)
+		})
+	}
+}
+
+func TestParsePollerGroups(t *testing.T) {
+	t.Run("rejects duplicate names", func(t *testing.T) {
+		_, err := domain.ParsePollerGroups([]string{"fast:5s:BTCUSDT", "fast:10s:ETHUSDT"})
+		assert.ErrorIs(t, err, domain.ErrInvalidPollerGroup)
+	})
+
+	t.Run("rejects two catch-all groups", func(t *testing.T) {
+		_, err := domain.ParsePollerGroups([]string{"slow:5m:*", "slower:1h:*"})
+		assert.ErrorIs(t, err, domain.ErrInvalidPollerGroup)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		groups, err := domain.ParsePollerGroups(nil)
+		require.NoError(t, err)
+		assert.Empty(t, groups)
+	})
+}
+
+func TestPollerGroups_Select(t *testing.T) {
+	groups, err := domain.ParsePollerGroups([]string{
+		"fast:5s:BTCUSDT|ETHUSDT",
+		"defi:1m:tag=defi",
+		"slow:5m:*",
+	})
+	require.NoError(t, err)
+
+	symbols := []*domain.Symbol{
+		{Name: "BTCUSDT", Active: true},
+		{Name: "ETHUSDT", Active: true, Tags: []string{"defi"}},
+		{Name: "UNIUSDT", Active: true, Tags: []string{"defi"}},
+		{Name: "DOGEUSDT", Active: true},
+	}
+
+	names := func(selected []*domain.Symbol) []string {
+		var out []string
+		for _, s := range selected {
+			out = append(out, s.Name)
+		}
+		return out
+	}
+
+	assert.Equal(t, []string{"BTCUSDT", "ETHUSDT"}, names(groups.Select("fast", symbols)))
+	assert.Equal(t, []string{"ETHUSDT", "UNIUSDT"}, names(groups.Select("defi", symbols)))
+	assert.Equal(t, []string{"DOGEUSDT"}, names(groups.Select("slow", symbols)))
+	assert.Empty(t, groups.Select("unknown", symbols))
+}
@@ -0,0 +1,40 @@
+package domain
+
+import "github.com/shopspring/decimal"
+
+// maxDisplayDecimals bounds the decimals derived from a tick size, matching
+// the scale prices are stored with
+const maxDisplayDecimals = 8
+
+// SymbolPrecision describes how a symbol's prices are quoted on the exchange
+type SymbolPrecision struct {
+	Symbol          string          `json:"symbol"`
+	TickSize        decimal.Decimal `json:"tick_size"`
+	DisplayDecimals int32           `json:"display_decimals"`
+}
+
+// NewSymbolPrecision derives the display decimals from the tick size, so a
+// tick of 0.01000000 displays two decimals. A non-positive tick size is
+// rejected.
+func NewSymbolPrecision(symbol string, tickSize decimal.Decimal) (*SymbolPrecision, error) {
+	if !tickSize.IsPositive() {
+		return nil, ErrInvalidResponse
+	}
+
+	return &SymbolPrecision{
+		Symbol:          symbol,
+		TickSize:        tickSize,
+		DisplayDecimals: displayDecimals(tickSize),
+	}, nil
+}
+
+// displayDecimals returns the number of decimals needed to show a multiple
+// of tickSize exactly
+func displayDecimals(tickSize decimal.Decimal) int32 {
+	for d := int32(0); d < maxDisplayDecimals; d++ {
+		if tickSize.Shift(d).IsInteger() {
+			return d
+		}
+	}
+	return maxDisplayDecimals
+}
@@ -0,0 +1,39 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+)
+
+func TestNewSymbolPrecision(t *testing.T) {
+	tests := []struct {
+		tickSize string
+		decimals int32
+	}{
+		{"0.01000000", 2},
+		{"0.00001000", 5},
+		{"1.00000000", 0},
+		{"10", 0},
+		{"0.5", 1},
+		{"0.00000001", 8},
+		{"0.000000001", 8},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tickSize, func(t *testing.T) {
+			p, err := domain.NewSymbolPrecision("BTCUSDT", decimal.RequireFromString(tt.tickSize))
+			require.NoError(t, err)
+			assert.Equal(t, tt.decimals, p.DisplayDecimals)
+		})
+	}
+}
+
+func TestNewSymbolPrecision_RejectsNonPositiveTick(t *testing.T) {
+	_, err := domain.NewSymbolPrecision("BTCUSDT", decimal.Zero)
+	assert.ErrorIs(t, err, domain.ErrInvalidResponse)
+}
@@ -0,0 +1,29 @@
+package domain
+
+import "time"
+
+// RateLimitDecision is the outcome of counting a request against a
+// fixed-window rate limit
+type RateLimitDecision struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	ResetAt   time.Time
+}
+
+// NewRateLimitDecision derives a decision from the request count of the
+// window ending at resetAt
+func NewRateLimitDecision(limit, count int, resetAt time.Time) *RateLimitDecision {
+	return &RateLimitDecision{
+		Allowed:   count <= limit,
+		Limit:     limit,
+		Remaining: max(limit-count, 0),
+		ResetAt:   resetAt,
+	}
+}
+
+// RateLimitWindow returns the start of the fixed window of the given length
+// that contains t
+func RateLimitWindow(t time.Time, window time.Duration) time.Time {
+	return t.Truncate(window)
+}
@@ -0,0 +1,30 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+)
+
+func TestNewRateLimitDecision(t *testing.T) {
+	reset := time.Date(2024, 1, 15, 10, 31, 0, 0, time.UTC)
+
+	within := domain.NewRateLimitDecision(10, 10, reset)
+	assert.True(t, within.Allowed)
+	assert.Equal(t, 0, within.Remaining)
+	assert.Equal(t, reset, within.ResetAt)
+
+	over := domain.NewRateLimitDecision(10, 12, reset)
+	assert.False(t, over.Allowed)
+	assert.Equal(t, 0, over.Remaining)
+}
+
+func TestRateLimitWindow(t *testing.T) {
+	at := time.Date(2024, 1, 15, 10, 30, 42, 0, time.UTC)
+
+	assert.Equal(t, time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC), domain.RateLimitWindow(at, time.Minute))
+	assert.Equal(t, time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC), domain.RateLimitWindow(at, time.Hour))
+}
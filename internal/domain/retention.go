@@ -0,0 +1,164 @@
+package domain
+
+import (
+	"math"
+	"time"
+)
+
+// SymbolStorage holds raw snapshot counts for a single symbol
+type SymbolStorage struct {
+	Symbol     string
+	Rows       int64
+	RecentRows int64
+	Oldest     time.Time
+}
+
+// StorageStats holds raw snapshot storage figures read from the database
+type StorageStats struct {
+	TotalBytes int64
+	Symbols    []SymbolStorage
+}
+
+// TableStats is the planner's view of a table: its estimated live and dead
+// rows and when its statistics were last refreshed, by ANALYZE or
+// autovacuum. LastAnalyzed is nil for a table never analyzed.
+type TableStats struct {
+	Table        string     `json:"table"`
+	LiveRows     int64      `json:"live_rows"`
+	DeadRows     int64      `json:"dead_rows"`
+	LastAnalyzed *time.Time `json:"last_analyzed,omitempty"`
+}
+
+// DeadRatio is the share of the table's rows that are dead, a rough
+// estimate of the bloat VACUUM would reclaim
+func (s TableStats) DeadRatio() float64 {
+	total := s.LiveRows + s.DeadRows
+	if total <= 0 {
+		return 0
+	}
+	return float64(s.DeadRows) / float64(total)
+}
+
+// SymbolGrowth represents the observed storage growth of a single symbol
+type SymbolGrowth struct {
+	Symbol      string    `json:"symbol"`
+	Rows        int64     `json:"rows"`
+	RowsPerDay  float64   `json:"rows_per_day"`
+	BytesPerDay int64     `json:"bytes_per_day"`
+	Oldest      time.Time `json:"oldest"`
+}
+
+// RetentionReport estimates snapshot storage growth and forecasts when the
+// configured disk threshold will be reached at the current polling rate
+type RetentionReport struct {
+	GeneratedAt        time.Time      `json:"generated_at"`
+	Window             string         `json:"window"`
+	TotalRows          int64          `json:"total_rows"`
+	TotalBytes         int64          `json:"total_bytes"`
+	AvgRowBytes        float64        `json:"avg_row_bytes"`
+	RowsPerDay         float64        `json:"rows_per_day"`
+	BytesPerDay        int64          `json:"bytes_per_day"`
+	ExpectedRowsPerDay float64        `json:"expected_rows_per_day"`
+	RetentionDays      int            `json:"retention_days"`
+	SteadyStateBytes   *int64         `json:"steady_state_bytes,omitempty"`
+	DiskThresholdBytes int64          `json:"disk_threshold_bytes,omitempty"`
+	ThresholdReachedAt *time.Time     `json:"threshold_reached_at,omitempty"`
+	Symbols            []SymbolGrowth `json:"symbols"`
+}
+
+// RetentionParams holds the polling and retention settings a report is
+// forecast against
+type RetentionParams struct {
+	Window             time.Duration
+	PollInterval       time.Duration
+	ActiveSymbols      int
+	RetentionDays      int
+	DiskThresholdBytes int64
+}
+
+// NewRetentionReport derives growth rates from the rows written within the
+// window and forecasts storage use. Symbols younger than the window are
+// measured over their actual age so newly added symbols are not understated.
+func NewRetentionReport(stats *StorageStats, params RetentionParams, now time.Time) *RetentionReport {
+	report := &RetentionReport{
+		GeneratedAt:        now,
+		Window:             params.Window.String(),
+		TotalBytes:         stats.TotalBytes,
+		RetentionDays:      params.RetentionDays,
+		DiskThresholdBytes: params.DiskThresholdBytes,
+		Symbols:            make([]SymbolGrowth, 0, len(stats.Symbols)),
+	}
+
+	for _, s := range stats.Symbols {
+		report.TotalRows += s.Rows
+	}
+	if report.TotalRows > 0 {
+		report.AvgRowBytes = float64(stats.TotalBytes) / float64(report.TotalRows)
+	}
+
+	for _, s := range stats.Symbols {
+		span := params.Window
+		if age := now.Sub(s.Oldest); age > 0 && age < span {
+			span = age
+		}
+
+		var rowsPerDay float64
+		if days := span.Hours() / 24; days > 0 {
+			rowsPerDay = float64(s.RecentRows) / days
+		}
+
+		report.RowsPerDay += rowsPerDay
+		report.Symbols = append(report.Symbols, SymbolGrowth{
+			Symbol:      s.Symbol,
+			Rows:        s.Rows,
+			RowsPerDay:  round2(rowsPerDay),
+			BytesPerDay: int64(rowsPerDay * report.AvgRowBytes),
+			Oldest:      s.Oldest,
+		})
+	}
+
+	report.RowsPerDay = round2(report.RowsPerDay)
+	report.AvgRowBytes = round2(report.AvgRowBytes)
+	report.BytesPerDay = int64(report.RowsPerDay * report.AvgRowBytes)
+
+	if params.PollInterval > 0 {
+		polls := float64(24*time.Hour) / float64(params.PollInterval)
+		report.ExpectedRowsPerDay = round2(polls * float64(params.ActiveSymbols))
+	}
+
+	if params.RetentionDays > 0 {
+		steady := int64(float64(report.BytesPerDay) * float64(params.RetentionDays))
+		report.SteadyStateBytes = &steady
+	}
+
+	report.ThresholdReachedAt = report.forecastThreshold(now)
+
+	return report
+}
+
+// forecastThreshold returns when the disk threshold will be reached, or nil
+// when no threshold is set, storage is not growing, or retention caps usage
+// below the threshold
+func (r *RetentionReport) forecastThreshold(now time.Time) *time.Time {
+	if r.DiskThresholdBytes <= 0 {
+		return nil
+	}
+	if r.TotalBytes >= r.DiskThresholdBytes {
+		return &now
+	}
+	if r.BytesPerDay <= 0 {
+		return nil
+	}
+	if r.SteadyStateBytes != nil && *r.SteadyStateBytes < r.DiskThresholdBytes {
+		return nil
+	}
+
+	days := float64(r.DiskThresholdBytes-r.TotalBytes) / float64(r.BytesPerDay)
+	at := now.Add(time.Duration(days * float64(24*time.Hour))).Truncate(time.Second)
+	return &at
+}
+
+// round2 rounds a rate to two decimal places for display
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
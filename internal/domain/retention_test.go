@@ -0,0 +1,105 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRetentionReport(t *testing.T) {
+	now := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	week := 7 * 24 * time.Hour
+
+	stats := &domain.StorageStats{
+		TotalBytes: 72_576_000,
+		Symbols: []domain.SymbolStorage{
+			// Polled every 30s for the full week
+			{Symbol: "BTCUSDT", Rows: 201_600, RecentRows: 201_600, Oldest: now.Add(-30 * 24 * time.Hour)},
+			// Added a day ago, measured over its actual age
+			{Symbol: "ETHUSDT", Rows: 2_880, RecentRows: 2_880, Oldest: now.Add(-24 * time.Hour)},
+			{Symbol: "SOLUSDT", Rows: 400_320, RecentRows: 201_600, Oldest: now.Add(-14 * 24 * time.Hour)},
+		},
+	}
+	params := domain.RetentionParams{
+		Window:        week,
+		PollInterval:  30 * time.Second,
+		ActiveSymbols: 3,
+		RetentionDays: 30,
+	}
+
+	t.Run("computes growth rates", func(t *testing.T) {
+		report := domain.NewRetentionReport(stats, params, now)
+
+		assert.Equal(t, int64(604_800), report.TotalRows)
+		assert.Equal(t, 120.0, report.AvgRowBytes)
+		assert.Equal(t, 60_480.0, report.RowsPerDay)
+		assert.Equal(t, int64(7_257_600), report.BytesPerDay)
+		assert.Equal(t, 8_640.0, report.ExpectedRowsPerDay)
+
+		require.Len(t, report.Symbols, 3)
+		assert.Equal(t, 28_800.0, report.Symbols[0].RowsPerDay)
+		assert.Equal(t, 2_880.0, report.Symbols[1].RowsPerDay)
+		assert.Equal(t, int64(345_600), report.Symbols[1].BytesPerDay)
+
+		require.NotNil(t, report.SteadyStateBytes)
+		assert.Equal(t, int64(217_728_000), *report.SteadyStateBytes)
+		assert.Nil(t, report.ThresholdReachedAt)
+	})
+
+	t.Run("forecasts threshold breach", func(t *testing.T) {
+		p := params
+		p.DiskThresholdBytes = 145_152_000
+
+		report := domain.NewRetentionReport(stats, p, now)
+
+		require.NotNil(t, report.ThresholdReachedAt)
+		assert.Equal(t, now.Add(10*24*time.Hour), *report.ThresholdReachedAt)
+	})
+
+	t.Run("no forecast when retention caps usage below threshold", func(t *testing.T) {
+		p := params
+		p.DiskThresholdBytes = 300_000_000
+
+		report := domain.NewRetentionReport(stats, p, now)
+
+		assert.Nil(t, report.ThresholdReachedAt)
+	})
+
+	t.Run("already over threshold", func(t *testing.T) {
+		p := params
+		p.DiskThresholdBytes = 1_000
+
+		report := domain.NewRetentionReport(stats, p, now)
+
+		require.NotNil(t, report.ThresholdReachedAt)
+		assert.Equal(t, now, *report.ThresholdReachedAt)
+	})
+
+	t.Run("unbounded growth without retention", func(t *testing.T) {
+		p := params
+		p.RetentionDays = 0
+		p.DiskThresholdBytes = 300_000_000
+
+		report := domain.NewRetentionReport(stats, p, now)
+
+		assert.Nil(t, report.SteadyStateBytes)
+		require.NotNil(t, report.ThresholdReachedAt)
+		assert.True(t, report.ThresholdReachedAt.After(now))
+	})
+
+	t.Run("empty table", func(t *testing.T) {
+		report := domain.NewRetentionReport(&domain.StorageStats{}, params, now)
+
+		assert.Zero(t, report.TotalRows)
+		assert.Zero(t, report.BytesPerDay)
+		assert.Empty(t, report.Symbols)
+	})
+}
+
+func TestTableStats_DeadRatio(t *testing.T) {
+	assert.InDelta(t, 0.25, domain.TableStats{LiveRows: 300, DeadRows: 100}.DeadRatio(), 1e-9)
+	assert.Zero(t, domain.TableStats{}.DeadRatio())
+}
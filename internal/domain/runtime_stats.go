@@ -0,0 +1,16 @@
+package domain
+
+// RuntimeStats are Go runtime and process gauges reported with the
+// metrics as basic capacity signals
+type RuntimeStats struct {
+	Goroutines     int     `json:"goroutines"`
+	HeapAllocBytes uint64  `json:"heap_alloc_bytes"`
+	HeapSysBytes   uint64  `json:"heap_sys_bytes"`
+	GCCycles       uint32  `json:"gc_cycles"`
+	GCPauseTotalMs float64 `json:"gc_pause_total_ms"`
+	LastGCPauseMs  float64 `json:"last_gc_pause_ms"`
+
+	// OpenFDs is omitted where the process's open file descriptors cannot
+	// be counted
+	OpenFDs *int `json:"open_fds,omitempty"`
+}
@@ -0,0 +1,85 @@
+package domain
+
+import "math"
+
+// SamplingAlgorithm names a method of downsampling a price history
+type SamplingAlgorithm string
+
+const (
+	// SamplingLTTB is largest-triangle-three-buckets, which keeps the points
+	// that shape a chart, such as spikes and turns, rather than averaging
+	// them away
+	SamplingLTTB SamplingAlgorithm = "lttb"
+)
+
+// HistorySampling describes how a history was downsampled: Rows snapshots
+// were reduced to at most Points
+type HistorySampling struct {
+	Algorithm SamplingAlgorithm `json:"algorithm"`
+	Points    int               `json:"points"`
+	Rows      int               `json:"rows"`
+}
+
+// DownsampleLTTB picks up to points snapshots of a time-ordered series
+// with largest-triangle-three-buckets. The first and last snapshots are
+// always kept and the order is preserved. A series no longer than points,
+// or points below 3, is returned unchanged.
+func DownsampleLTTB(snapshots []*PriceSnapshot, points int) []*PriceSnapshot {
+	n := len(snapshots)
+	if points < 3 || n <= points {
+		return snapshots
+	}
+
+	// Times are taken relative to the first snapshot so that float64 keeps
+	// sub-second precision
+	origin := snapshots[0].Timestamp
+	xs := make([]float64, n)
+	ys := make([]float64, n)
+	for i, s := range snapshots {
+		xs[i] = s.Timestamp.Sub(origin).Seconds()
+		ys[i] = s.Price.InexactFloat64()
+	}
+
+	sampled := make([]*PriceSnapshot, 0, points)
+	sampled = append(sampled, snapshots[0])
+
+	// The first and last snapshots are buckets of their own; the rest are
+	// split evenly between the remaining points
+	every := float64(n-2) / float64(points-2)
+	a := 0
+	for i := 0; i < points-2; i++ {
+		// Average of the next bucket, the third corner of the triangle
+		avgStart := int(math.Floor(float64(i+1)*every)) + 1
+		avgEnd := int(math.Floor(float64(i+2)*every)) + 1
+		if avgEnd > n {
+			avgEnd = n
+		}
+		if avgStart >= avgEnd {
+			avgStart = avgEnd - 1
+		}
+		var avgX, avgY float64
+		for j := avgStart; j < avgEnd; j++ {
+			avgX += xs[j]
+			avgY += ys[j]
+		}
+		avgX /= float64(avgEnd - avgStart)
+		avgY /= float64(avgEnd - avgStart)
+
+		// Keep the point of this bucket spanning the largest triangle with
+		// the last kept point and the next bucket's average
+		start := int(math.Floor(float64(i)*every)) + 1
+		end := int(math.Floor(float64(i+1)*every)) + 1
+		best, bestArea := start, -1.0
+		for j := start; j < end; j++ {
+			area := math.Abs((xs[a]-avgX)*(ys[j]-ys[a]) - (xs[a]-xs[j])*(avgY-ys[a]))
+			if area > bestArea {
+				best, bestArea = j, area
+			}
+		}
+
+		sampled = append(sampled, snapshots[best])
+		a = best
+	}
+
+	return append(sampled, snapshots[n-1])
+}
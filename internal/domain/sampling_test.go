@@ -0,0 +1,58 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+)
+
+func TestDownsampleLTTB(t *testing.T) {
+	start := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	series := func(prices ...int64) []*domain.PriceSnapshot {
+		snapshots := make([]*domain.PriceSnapshot, len(prices))
+		for i, p := range prices {
+			snapshots[i] = &domain.PriceSnapshot{Price: decimal.NewFromInt(p), Timestamp: start.Add(time.Duration(i) * time.Second)}
+		}
+		return snapshots
+	}
+
+	t.Run("keeps the ends and the spikes", func(t *testing.T) {
+		prices := make([]int64, 100)
+		for i := range prices {
+			prices[i] = 100
+		}
+		prices[37] = 150
+		prices[71] = 60
+		snapshots := series(prices...)
+
+		sampled := domain.DownsampleLTTB(snapshots, 10)
+
+		require.Len(t, sampled, 10)
+		assert.Same(t, snapshots[0], sampled[0])
+		assert.Same(t, snapshots[99], sampled[9])
+		assert.Contains(t, sampled, snapshots[37])
+		assert.Contains(t, sampled, snapshots[71])
+		for i := 1; i < len(sampled); i++ {
+			assert.True(t, sampled[i].Timestamp.After(sampled[i-1].Timestamp))
+		}
+	})
+
+	t.Run("returns short series unchanged", func(t *testing.T) {
+		snapshots := series(1, 2, 3)
+
+		assert.Equal(t, snapshots, domain.DownsampleLTTB(snapshots, 3))
+		assert.Equal(t, snapshots, domain.DownsampleLTTB(snapshots, 500))
+	})
+
+	t.Run("handles every point count", func(t *testing.T) {
+		snapshots := series(5, 3, 8, 1, 9, 2, 7, 4, 6, 0, 5)
+		for points := 3; points < len(snapshots); points++ {
+			assert.Len(t, domain.DownsampleLTTB(snapshots, points), points)
+		}
+	})
+}
@@ -1,34 +1,214 @@
 package domain
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/shopspring/decimal"
 )
 
+// TimestampSource identifies where a snapshot's timestamp came from
+type TimestampSource string
+
+const (
+	// TimestampSourcePoll is the start time shared by every snapshot in a poll
+	TimestampSourcePoll TimestampSource = "poll"
+
+	// TimestampSourceFetch is the time the exchange response carrying the price was received
+	TimestampSourceFetch TimestampSource = "fetch"
+
+	// TimestampSourceExchange is the event time reported by the exchange
+	TimestampSourceExchange TimestampSource = "exchange"
+
+	// TimestampSourceImport is a timestamp supplied by an import
+	TimestampSourceImport TimestampSource = "import"
+
+	// TimestampSourceIngest is a timestamp supplied by a pushed snapshot
+	TimestampSourceIngest TimestampSource = "ingest"
+)
+
+// Valid reports whether the timestamp source is known
+func (s TimestampSource) Valid() bool {
+	switch s {
+	case TimestampSourcePoll, TimestampSourceFetch, TimestampSourceExchange, TimestampSourceImport, TimestampSourceIngest:
+		return true
+	}
+	return false
+}
+
+// IngestMode identifies how a snapshot's price reached the service
+type IngestMode string
+
+const (
+	// IngestModePoll is a price requested from the exchange by a poll
+	IngestModePoll IngestMode = "poll"
+
+	// IngestModeStream is a price pushed by the exchange's market stream
+	IngestModeStream IngestMode = "stream"
+)
+
+// SnapshotWriteMode selects how a poll's snapshots are stored
+type SnapshotWriteMode string
+
+const (
+	// SnapshotWriteAtomic stores all of a poll's snapshots or none of them
+	SnapshotWriteAtomic SnapshotWriteMode = "atomic"
+
+	// SnapshotWritePartial stores every snapshot it can and reports the
+	// rows that were rejected
+	SnapshotWritePartial SnapshotWriteMode = "partial"
+)
+
+// BatchWriteResult reports the outcome of a partial batch write
+type BatchWriteResult struct {
+	Written int                 `json:"written"`
+	Failed  []BatchWriteFailure `json:"failed,omitempty"`
+}
+
+// BatchWriteFailure is a single row rejected by a partial batch write
+type BatchWriteFailure struct {
+	Symbol string `json:"symbol"`
+	Error  string `json:"error"`
+}
+
+// HistorySource identifies the data a price history was read from
+type HistorySource string
+
+const (
+	// HistorySourceRaw is history read from the stored snapshots themselves
+	HistorySourceRaw HistorySource = "raw"
+
+	// HistorySourceRollup is history read from aggregated candles
+	HistorySourceRollup HistorySource = "rollup"
+)
+
+// MaxHistoryLimit caps the snapshots a single history query returns
+const MaxHistoryLimit = 1000
+
+// HistorySummary describes everything stored for a symbol, compressed
+// snapshots included: how many snapshots there are and the timestamps of
+// the oldest and newest, which are nil when there are none
+type HistorySummary struct {
+	TotalCount int64
+	Oldest     *time.Time
+	Newest     *time.Time
+}
+
 // PriceSnapshot represents a point-in-time price capture
 type PriceSnapshot struct {
-	ID        int64           `json:"id"`
-	SymbolID  int64           `json:"symbol_id"`
-	Symbol    string          `json:"symbol"`
-	Price     decimal.Decimal `json:"price"`
-	Timestamp time.Time       `json:"timestamp"`
+	ID              int64           `json:"id"`
+	SymbolID        int64           `json:"symbol_id"`
+	Symbol          string          `json:"symbol"`
+	Price           decimal.Decimal `json:"price"`
+	Timestamp       time.Time       `json:"timestamp"`
+	TimestampSource TimestampSource `json:"timestamp_source"`
+
+	// Source names the external system that pushed the snapshot; it is
+	// empty for polled and imported snapshots
+	Source string `json:"source,omitempty"`
+
+	// FetchLatencyMs is how long the exchange took to return the prices of
+	// the poll that stored the snapshot, retries included; nil for
+	// snapshots that were not polled
+	FetchLatencyMs *int64 `json:"fetch_latency_ms,omitempty"`
+
+	// PollRunID identifies the poll that stored the snapshot; it is empty
+	// for snapshots that were not polled
+	PollRunID string `json:"poll_run_id,omitempty"`
+
+	// Exchange names the exchange the snapshot was polled from; it is empty
+	// for snapshots that were not polled
+	Exchange string `json:"exchange,omitempty"`
+
+	// IngestMode, IngestLatencyMs and PipelineVersion record how the
+	// snapshot was ingested, for telling apart the data quality of polled
+	// and streamed prices. IngestLatencyMs is how long before the snapshot
+	// was built its price was observed, see Price.IngestLatencyMs, and
+	// PipelineVersion the version of the service that built it. All are
+	// empty for snapshots that were neither polled nor streamed.
+	IngestMode      IngestMode `json:"ingest_mode,omitempty"`
+	IngestLatencyMs *int64     `json:"ingest_latency_ms,omitempty"`
+	PipelineVersion string     `json:"pipeline_version,omitempty"`
 }
 
 // NewPriceSnapshot creates a new price snapshot
 func NewPriceSnapshot(symbolID int64, symbol string, price decimal.Decimal) *PriceSnapshot {
 	return &PriceSnapshot{
-		SymbolID:  symbolID,
-		Symbol:    symbol,
-		Price:     price,
-		Timestamp: time.Now().UTC(),
+		SymbolID:        symbolID,
+		Symbol:          symbol,
+		Price:           price,
+		Timestamp:       time.Now().UTC(),
+		TimestampSource: TimestampSourcePoll,
+	}
+}
+
+// PriceMove is how a symbol's price moved from its previous snapshot to
+// the latest one
+type PriceMove struct {
+	Previous     *PriceSnapshot
+	Delta        decimal.Decimal
+	DeltaPercent decimal.Decimal
+}
+
+// NewPriceMove compares the latest snapshot with the one before it
+func NewPriceMove(previous, latest *PriceSnapshot) PriceMove {
+	return PriceMove{
+		Previous:     previous,
+		Delta:        latest.Price.Sub(previous.Price),
+		DeltaPercent: PercentChange(previous.Price, latest.Price, PercentPlaces, RoundHalfUp),
 	}
 }
 
+// Age returns how old the snapshot is at now. Timestamps slightly ahead of
+// now, e.g. from a pushed snapshot, count as zero.
+func (s *PriceSnapshot) Age(now time.Time) time.Duration {
+	return max(now.Sub(s.Timestamp), 0)
+}
+
+// MarshalJSON emits the price as an exact decimal string
+func (s PriceSnapshot) MarshalJSON() ([]byte, error) {
+	type alias PriceSnapshot
+	return json.Marshal(struct {
+		alias
+		Price exactDecimal `json:"price"`
+	}{alias(s), exactDecimal(s.Price)})
+}
+
 // Price represents a current price from the exchange
 type Price struct {
 	Symbol string          `json:"symbol"`
 	Price  decimal.Decimal `json:"price"`
+
+	// FetchedAt is when the response carrying the price was received
+	FetchedAt time.Time `json:"fetched_at,omitempty"`
+
+	// EventTime is the exchange-reported time of the price, when available
+	EventTime *time.Time `json:"event_time,omitempty"`
+}
+
+// IngestLatencyMs returns how long before now the price was observed: at
+// the exchange-reported event time when there is one, else when it was
+// fetched. It is nil when neither is known, and negative when the
+// exchange's clock is ahead of the local one.
+func (p *Price) IngestLatencyMs(now time.Time) *int64 {
+	observed := p.FetchedAt
+	if p.EventTime != nil {
+		observed = *p.EventTime
+	}
+	if observed.IsZero() {
+		return nil
+	}
+	ms := now.Sub(observed).Milliseconds()
+	return &ms
+}
+
+// MarshalJSON emits the price as an exact decimal string
+func (p Price) MarshalJSON() ([]byte, error) {
+	type alias Price
+	return json.Marshal(struct {
+		alias
+		Price exactDecimal `json:"price"`
+	}{alias(p), exactDecimal(p.Price)})
 }
 
 // Metrics represents operational metrics
@@ -41,6 +221,113 @@ type Metrics struct {
 	LastPollDuration float64    `json:"last_poll_duration_ms"`
 	PollSuccessCount int64      `json:"poll_success_count"`
 	PollErrorCount   int64      `json:"poll_error_count"`
-	DatabaseStatus   string     `json:"database_status"`
-	ExchangeStatus   string     `json:"exchange_status"`
+	PrunedSnapshots  int64      `json:"pruned_snapshots"`
+	LastPruneTime    *time.Time `json:"last_prune_time,omitempty"`
+
+	// PartialPolls counts polls that stored some snapshots but not all, and
+	// SnapshotWriteFailures the snapshots they could not store
+	PartialPolls          int64 `json:"partial_polls"`
+	SnapshotWriteFailures int64 `json:"snapshot_write_failures"`
+
+	DatabaseStatus string `json:"database_status"`
+	ExchangeStatus string `json:"exchange_status"`
+
+	// ExchangeErrors counts failed exchange request attempts by operation and kind
+	ExchangeErrors []ExchangeErrorCount `json:"exchange_errors,omitempty"`
+
+	// ExchangeRetries counts retried exchange requests by operation and the
+	// kind of failure retried, and ExchangeAttempts the attempts made per
+	// call by operation
+	ExchangeRetries  []ExchangeRetryCount   `json:"exchange_retries,omitempty"`
+	ExchangeAttempts []ExchangeCallAttempts `json:"exchange_attempts,omitempty"`
+
+	// ExchangeBudget reports the use of the exchange request budget when
+	// budgeting is enabled
+	ExchangeBudget *ExchangeBudgetStats `json:"exchange_budget,omitempty"`
+
+	// SlowQueries counts database statements slower than the configured
+	// threshold by query name
+	SlowQueries []SlowQueryCount `json:"slow_queries,omitempty"`
+
+	// PollerGroups reports each named poller group when groups are configured
+	PollerGroups []PollerGroupMetrics `json:"poller_groups,omitempty"`
+
+	// PollStages reports how long each poll stage takes and how often it
+	// exceeds its budget
+	PollStages []PollStageMetrics `json:"poll_stages,omitempty"`
+
+	// PollDuration is the distribution of poll durations
+	PollDuration *Histogram `json:"poll_duration_seconds,omitempty"`
+
+	// HTTPRequests reports request latencies by route, method and status
+	// code, ordered by route
+	HTTPRequests []HTTPRequestMetrics `json:"http_requests,omitempty"`
+
+	// DatabasePool reports connection pool usage when available
+	DatabasePool *DatabasePoolStats `json:"database_pool,omitempty"`
+
+	// SymbolSnapshots counts the stored snapshots of each symbol
+	SymbolSnapshots []SymbolSnapshotCount `json:"symbol_snapshots,omitempty"`
+
+	// SymbolLimits are the configured caps on tracked and active symbols,
+	// omitted when there are none
+	SymbolLimits *SymbolLimits `json:"symbol_limits,omitempty"`
+
+	// Runtime reports Go runtime stats when enabled
+	Runtime *RuntimeStats `json:"runtime,omitempty"`
+
+	// Build identifies the running binary and Labels are the constant
+	// labels attached to every exported metric
+	Build  *BuildInfo        `json:"build,omitempty"`
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Config is the effective configuration of the instance
+	Config *EffectiveConfig `json:"config,omitempty"`
+}
+
+// SlowQueryCount is the number of slow executions of one database query
+type SlowQueryCount struct {
+	Query string `json:"query"`
+	Count int64  `json:"count"`
+}
+
+// PollerGroupMetrics represents the poll activity of a single poller group
+type PollerGroupMetrics struct {
+	Name             string     `json:"name"`
+	LastPollTime     *time.Time `json:"last_poll_time,omitempty"`
+	LastPollDuration float64    `json:"last_poll_duration_ms"`
+	PollSuccessCount int64      `json:"poll_success_count"`
+	PollErrorCount   int64      `json:"poll_error_count"`
+}
+
+// MarshalJSON emits fractional metrics in positional notation
+func (m Metrics) MarshalJSON() ([]byte, error) {
+	type alias Metrics
+	return json.Marshal(struct {
+		alias
+		Uptime           plainFloat `json:"uptime_seconds"`
+		LastPollDuration plainFloat `json:"last_poll_duration_ms"`
+	}{alias(m), plainFloat(m.Uptime), plainFloat(m.LastPollDuration)})
+}
+
+// SnapshotRangeDeletion reports the snapshots removed, or in a dry run the
+// snapshots that would be removed, from one symbol's [From, To) range.
+// Compressed chunks are only removed when they lie wholly inside the
+// range; KeptCompressed counts the snapshots of chunks straddling it.
+type SnapshotRangeDeletion struct {
+	Symbol         string    `json:"symbol"`
+	From           time.Time `json:"from"`
+	To             time.Time `json:"to"`
+	DryRun         bool      `json:"dry_run"`
+	Deleted        int64     `json:"deleted"`
+	KeptCompressed int64     `json:"kept_compressed"`
+}
+
+// PruneResult summarizes a retention prune run
+type PruneResult struct {
+	Cutoff     time.Time        `json:"cutoff"`
+	Deleted    int64            `json:"deleted"`
+	BySymbol   map[string]int64 `json:"by_symbol"`
+	Failed     []string         `json:"failed,omitempty"`
+	DurationMs int64            `json:"duration_ms"`
 }
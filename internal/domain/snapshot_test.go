@@ -0,0 +1,49 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+)
+
+func TestNewPriceMove(t *testing.T) {
+	previous := &domain.PriceSnapshot{Symbol: "BTCUSDT", Price: decimal.RequireFromString("43000")}
+	latest := &domain.PriceSnapshot{Symbol: "BTCUSDT", Price: decimal.RequireFromString("42871")}
+
+	move := domain.NewPriceMove(previous, latest)
+
+	assert.Same(t, previous, move.Previous)
+	assert.Equal(t, "-129", move.Delta.String())
+	assert.Equal(t, "-0.3", move.DeltaPercent.String())
+}
+
+func TestPriceSnapshot_Age(t *testing.T) {
+	now := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+
+	snap := &domain.PriceSnapshot{Timestamp: now.Add(-1500 * time.Millisecond)}
+	assert.Equal(t, 1500*time.Millisecond, snap.Age(now))
+
+	ahead := &domain.PriceSnapshot{Timestamp: now.Add(time.Second)}
+	assert.Zero(t, ahead.Age(now))
+}
+
+func TestPrice_IngestLatencyMs(t *testing.T) {
+	now := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	eventTime := now.Add(-250 * time.Millisecond)
+
+	fetched := &domain.Price{FetchedAt: now.Add(-40 * time.Millisecond)}
+	if assert.NotNil(t, fetched.IngestLatencyMs(now)) {
+		assert.Equal(t, int64(40), *fetched.IngestLatencyMs(now))
+	}
+
+	streamed := &domain.Price{FetchedAt: now.Add(-40 * time.Millisecond), EventTime: &eventTime}
+	if assert.NotNil(t, streamed.IngestLatencyMs(now)) {
+		assert.Equal(t, int64(250), *streamed.IngestLatencyMs(now), "the event time wins over the fetch time")
+	}
+
+	assert.Nil(t, (&domain.Price{}).IngestLatencyMs(now))
+}
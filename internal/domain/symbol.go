@@ -1,23 +1,31 @@
 package domain
 
 import (
+	"sort"
 	"strings"
 	"time"
 	"unicode"
 )
 
-// Symbol represents a tracked cryptocurrency symbol
+// Symbol represents a tracked cryptocurrency symbol. Metadata is set once
+// the symbol has been enriched by a market data provider. Exchange names
+// the exchange the symbol is polled from; empty means the default one.
+// DeletedAt is set once the symbol is removed with its history kept.
 type Symbol struct {
-	ID        int64     `json:"id"`
-	Name      string    `json:"name"`
-	Active    bool      `json:"active"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID        int64         `json:"id"`
+	Name      string        `json:"name"`
+	Exchange  string        `json:"exchange"`
+	Active    bool          `json:"active"`
+	Tags      []string      `json:"tags"`
+	Metadata  *CoinMetadata `json:"metadata,omitempty"`
+	CreatedAt time.Time     `json:"created_at"`
+	UpdatedAt time.Time     `json:"updated_at"`
+	DeletedAt *time.Time    `json:"deleted_at,omitempty"`
 }
 
 // NewSymbol creates a new symbol with validation
 func NewSymbol(name string) (*Symbol, error) {
-	name = strings.ToUpper(strings.TrimSpace(name))
+	name = NormalizeSymbol(name)
 
 	if err := ValidateSymbolName(name); err != nil {
 		return nil, err
@@ -27,6 +35,7 @@ func NewSymbol(name string) (*Symbol, error) {
 	return &Symbol{
 		Name:      name,
 		Active:    true,
+		Tags:      []string{},
 		CreatedAt: now,
 		UpdatedAt: now,
 	}, nil
@@ -63,3 +72,93 @@ func (s *Symbol) Activate() {
 	s.Active = true
 	s.UpdatedAt = time.Now().UTC()
 }
+
+// SymbolLimits caps the number of tracked symbols and, among them, the
+// number of active ones. Zero means no limit.
+type SymbolLimits struct {
+	MaxTracked int `json:"max_tracked,omitempty"`
+	MaxActive  int `json:"max_active,omitempty"`
+}
+
+// IsSet reports whether any limit is configured
+func (l SymbolLimits) IsSet() bool {
+	return l.MaxTracked > 0 || l.MaxActive > 0
+}
+
+// NormalizeTag lowercases and validates a tag.
+// Tags must be 1-32 characters of lowercase letters, digits, '-' or '_'.
+func NormalizeTag(tag string) (string, error) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+
+	if tag == "" || len(tag) > 32 {
+		return "", ErrInvalidTag
+	}
+
+	for _, r := range tag {
+		if !(r >= 'a' && r <= 'z') && !unicode.IsDigit(r) && r != '-' && r != '_' {
+			return "", ErrInvalidTag
+		}
+	}
+
+	return tag, nil
+}
+
+// NormalizeTags normalizes, deduplicates and sorts a list of tags
+func NormalizeTags(tags []string) ([]string, error) {
+	seen := make(map[string]bool, len(tags))
+	normalized := make([]string, 0, len(tags))
+
+	for _, t := range tags {
+		tag, err := NormalizeTag(t)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[tag] {
+			seen[tag] = true
+			normalized = append(normalized, tag)
+		}
+	}
+
+	sort.Strings(normalized)
+	return normalized, nil
+}
+
+// HasTag reports whether the symbol carries the given normalized tag
+func (s *Symbol) HasTag(tag string) bool {
+	for _, t := range s.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// SetTags replaces the symbol's tags
+func (s *Symbol) SetTags(tags []string) error {
+	normalized, err := NormalizeTags(tags)
+	if err != nil {
+		return err
+	}
+	s.Tags = normalized
+	s.UpdatedAt = time.Now().UTC()
+	return nil
+}
+
+// RemoveTag removes a tag from the symbol, reporting whether it was present
+func (s *Symbol) RemoveTag(tag string) bool {
+	for i, t := range s.Tags {
+		if t == tag {
+			s.Tags = append(s.Tags[:i:i], s.Tags[i+1:]...)
+			s.UpdatedAt = time.Now().UTC()
+			return true
+		}
+	}
+	return false
+}
+
+// StaleSymbol is an active symbol with no snapshot since a cutoff.
+// LastSnapshotAt is nil when the symbol has no snapshot at all.
+type StaleSymbol struct {
+	Symbol         string
+	LastSnapshotAt *time.Time
+}
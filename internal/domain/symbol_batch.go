@@ -0,0 +1,65 @@
+package domain
+
+// MaxSymbolBatch is the largest number of symbols accepted in one batch
+// update
+const MaxSymbolBatch = 1000
+
+// SymbolBatchOutcome is what a batch update did, or would have done, to
+// one symbol
+type SymbolBatchOutcome string
+
+const (
+	// SymbolBatchActivated is a paused symbol that was resumed
+	SymbolBatchActivated SymbolBatchOutcome = "activated"
+
+	// SymbolBatchDeactivated is an active symbol that was paused
+	SymbolBatchDeactivated SymbolBatchOutcome = "deactivated"
+
+	// SymbolBatchUnchanged is a symbol already in the requested state
+	SymbolBatchUnchanged SymbolBatchOutcome = "unchanged"
+
+	// SymbolBatchNotFound is a symbol that is not tracked
+	SymbolBatchNotFound SymbolBatchOutcome = "not_found"
+
+	// SymbolBatchQuotaExceeded is a symbol whose activation would exceed
+	// the active symbol cap
+	SymbolBatchQuotaExceeded SymbolBatchOutcome = "quota_exceeded"
+
+	// SymbolBatchSkipped is a valid symbol left as it was because another
+	// symbol of the batch failed
+	SymbolBatchSkipped SymbolBatchOutcome = "skipped"
+)
+
+// SymbolBatchResult is the outcome of a batch update for one symbol
+type SymbolBatchResult struct {
+	Symbol  string             `json:"symbol"`
+	Outcome SymbolBatchOutcome `json:"outcome"`
+	Error   string             `json:"error,omitempty"`
+}
+
+// SymbolBatch reports a batch update of symbols. It is applied to every
+// symbol or to none: Applied is false when any symbol failed, and the
+// others are then reported as skipped.
+type SymbolBatch struct {
+	Applied bool                `json:"applied"`
+	Results []SymbolBatchResult `json:"results"`
+}
+
+// Failed reports whether any symbol of the batch failed
+func (b *SymbolBatch) Failed() bool {
+	for _, r := range b.Results {
+		if r.Error != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// Skip marks every symbol that did not fail as skipped
+func (b *SymbolBatch) Skip() {
+	for i := range b.Results {
+		if b.Results[i].Error == "" {
+			b.Results[i].Outcome = SymbolBatchSkipped
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SymbolEventType identifies a change to a symbol's tracking configuration
+type SymbolEventType string
+
+const (
+	SymbolEventAdded       SymbolEventType = "added"
+	SymbolEventRemoved     SymbolEventType = "removed"
+	SymbolEventActivated   SymbolEventType = "activated"
+	SymbolEventDeactivated SymbolEventType = "deactivated"
+	SymbolEventTagsChanged SymbolEventType = "tags_changed"
+
+	// SymbolEventDelisted records that the exchange no longer trades a
+	// tracked symbol, as detected by warm-up or revalidation
+	SymbolEventDelisted SymbolEventType = "delisted"
+)
+
+// LifecycleEventTypes are the event types that change which symbols are
+// tracked and polled, as opposed to how they are labeled
+var LifecycleEventTypes = []SymbolEventType{
+	SymbolEventAdded,
+	SymbolEventRemoved,
+	SymbolEventActivated,
+	SymbolEventDeactivated,
+	SymbolEventDelisted,
+}
+
+// ErrInvalidSymbolEventType is returned for an unknown symbol event type
+var ErrInvalidSymbolEventType = errors.New("invalid symbol event type")
+
+// Valid reports whether t is a known event type
+func (t SymbolEventType) Valid() bool {
+	switch t {
+	case SymbolEventAdded, SymbolEventRemoved, SymbolEventActivated,
+		SymbolEventDeactivated, SymbolEventTagsChanged, SymbolEventDelisted:
+		return true
+	}
+	return false
+}
+
+// ParseSymbolEventTypes parses event type names such as "added" or
+// "delisted"; none means LifecycleEventTypes
+func ParseSymbolEventTypes(names []string) ([]SymbolEventType, error) {
+	if len(names) == 0 {
+		return LifecycleEventTypes, nil
+	}
+
+	types := make([]SymbolEventType, 0, len(names))
+	for _, name := range names {
+		t := SymbolEventType(strings.ToLower(strings.TrimSpace(name)))
+		if !t.Valid() {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidSymbolEventType, name)
+		}
+		types = append(types, t)
+	}
+	return types, nil
+}
+
+// SymbolEvent records a single change to a symbol. Events are kept after
+// the symbol is removed, so they explain gaps in collected data.
+type SymbolEvent struct {
+	ID        int64                  `json:"id"`
+	Symbol    string                 `json:"symbol"`
+	Type      SymbolEventType        `json:"type"`
+	Details   map[string]interface{} `json:"details,omitempty"`
+	CreatedAt time.Time              `json:"created_at"`
+}
+
+// NewSymbolEvent creates an event of the given type for a symbol
+func NewSymbolEvent(symbol string, eventType SymbolEventType, details map[string]interface{}) *SymbolEvent {
+	return &SymbolEvent{
+		Symbol:    symbol,
+		Type:      eventType,
+		Details:   details,
+		CreatedAt: time.Now().UTC(),
+	}
+}
+
+// NewTagsChangedEvent records a symbol's tags changing from one set to another
+func NewTagsChangedEvent(symbol string, from, to []string) *SymbolEvent {
+	return NewSymbolEvent(symbol, SymbolEventTagsChanged, map[string]interface{}{
+		"from": from,
+		"to":   to,
+	})
+}
+
+// SymbolEventDigest is a batch of symbol events delivered to subscribers,
+// oldest first. Event IDs increase, so a subscriber can skip events it has
+// already seen when a digest is delivered again.
+type SymbolEventDigest struct {
+	Events []*SymbolEvent `json:"events"`
+	SentAt time.Time      `json:"sent_at"`
+}
+
+// NewSymbolEventDigest creates a digest of events
+func NewSymbolEventDigest(events []*SymbolEvent) *SymbolEventDigest {
+	return &SymbolEventDigest{
+		Events: events,
+		SentAt: time.Now().UTC(),
+	}
+}
@@ -0,0 +1,28 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+)
+
+func TestParseSymbolEventTypes(t *testing.T) {
+	types, err := domain.ParseSymbolEventTypes(nil)
+	require.NoError(t, err)
+	assert.Equal(t, domain.LifecycleEventTypes, types)
+	assert.NotContains(t, types, domain.SymbolEventTagsChanged)
+
+	types, err = domain.ParseSymbolEventTypes([]string{" Added", "delisted", "tags_changed"})
+	require.NoError(t, err)
+	assert.Equal(t, []domain.SymbolEventType{
+		domain.SymbolEventAdded,
+		domain.SymbolEventDelisted,
+		domain.SymbolEventTagsChanged,
+	}, types)
+
+	_, err = domain.ParseSymbolEventTypes([]string{"added", "archived"})
+	assert.ErrorIs(t, err, domain.ErrInvalidSymbolEventType)
+}
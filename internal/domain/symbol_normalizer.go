@@ -0,0 +1,113 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// pairSeparators may split a trading pair's base and quote assets, as in
+// BTC/USDT, BTC-USDT or btc_usdt; symbols are tracked without them
+const pairSeparators = "/-_ "
+
+// quoteAssets are the quote currencies stripped from a trading pair to find
+// its base asset, longest first so that e.g. FDUSD wins over USD
+var quoteAssets = []string{
+	"FDUSD", "USDT", "USDC", "BUSD", "TUSD", "USDP",
+	"EUR", "TRY", "BRL", "GBP", "JPY", "DAI", "USD",
+	"BTC", "ETH", "BNB",
+}
+
+// SymbolNormalizer turns the symbol names clients, files and configuration
+// use into the canonical names symbols are tracked under: trimmed, upper
+// case, without pair separators and with aliases such as XBTUSDT resolved
+// to the symbol they stand for. A nil normalizer resolves no aliases.
+type SymbolNormalizer struct {
+	aliases map[string]string
+}
+
+// NewSymbolNormalizer parses "ALIAS=SYMBOL" definitions, e.g.
+// "XBTUSDT=BTCUSDT". Both sides are normalized; an alias may not point at
+// itself, at another alias or be defined twice.
+func NewSymbolNormalizer(aliases []string) (*SymbolNormalizer, error) {
+	n := &SymbolNormalizer{aliases: make(map[string]string, len(aliases))}
+
+	for _, spec := range aliases {
+		alias, target, ok := strings.Cut(spec, "=")
+		if !ok {
+			return nil, fmt.Errorf("%w: %q is not ALIAS=SYMBOL", ErrInvalidAlias, spec)
+		}
+
+		alias, target = NormalizeSymbol(alias), NormalizeSymbol(target)
+		if ValidateSymbolName(alias) != nil || ValidateSymbolName(target) != nil {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidAlias, spec)
+		}
+		if alias == target {
+			return nil, fmt.Errorf("%w: %s is an alias of itself", ErrInvalidAlias, alias)
+		}
+		if _, ok := n.aliases[alias]; ok {
+			return nil, fmt.Errorf("%w: duplicate alias %s", ErrInvalidAlias, alias)
+		}
+		n.aliases[alias] = target
+	}
+
+	for alias, target := range n.aliases {
+		if _, ok := n.aliases[target]; ok {
+			return nil, fmt.Errorf("%w: %s points at alias %s", ErrInvalidAlias, alias, target)
+		}
+	}
+
+	return n, nil
+}
+
+// Normalize returns the canonical name of a symbol
+func (n *SymbolNormalizer) Normalize(name string) string {
+	name = NormalizeSymbol(name)
+	if n != nil {
+		if target, ok := n.aliases[name]; ok {
+			return target
+		}
+	}
+	return name
+}
+
+// NormalizeAll returns the canonical names of symbols, in order
+func (n *SymbolNormalizer) NormalizeAll(names []string) []string {
+	normalized := make([]string, len(names))
+	for i, name := range names {
+		normalized[i] = n.Normalize(name)
+	}
+	return normalized
+}
+
+// NormalizeSymbol trims a symbol name, upper-cases it and drops the
+// separators between its base and quote assets, without resolving aliases
+func NormalizeSymbol(name string) string {
+	name = strings.ToUpper(strings.TrimSpace(name))
+	if !strings.ContainsAny(name, pairSeparators) {
+		return name
+	}
+	return strings.Map(func(r rune) rune {
+		if strings.ContainsRune(pairSeparators, r) {
+			return -1
+		}
+		return r
+	}, name)
+}
+
+// SplitPair splits a normalized trading pair such as BTCUSDT into its base
+// and quote assets. ok is false when no known quote currency matches.
+func SplitPair(symbol string) (base, quote string, ok bool) {
+	for _, quote := range quoteAssets {
+		if base, found := strings.CutSuffix(symbol, quote); found && base != "" {
+			return base, quote, true
+		}
+	}
+	return symbol, "", false
+}
+
+// BaseAsset returns the base asset of a trading pair such as BTCUSDT, or the
+// whole name when no known quote currency matches
+func BaseAsset(symbol string) string {
+	base, _, _ := SplitPair(symbol)
+	return base
+}
@@ -0,0 +1,88 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+)
+
+func TestNormalizeSymbol(t *testing.T) {
+	tests := map[string]string{
+		"BTCUSDT":     "BTCUSDT",
+		" btcusdt ":   "BTCUSDT",
+		"BTC/USDT":    "BTCUSDT",
+		"eth-usdt":    "ETHUSDT",
+		"sol_fdusd":   "SOLFDUSD",
+		"BNB EUR":     "BNBEUR",
+		"":            "",
+		"  ":          "",
+		"1000SATSUSD": "1000SATSUSD",
+	}
+
+	for name, want := range tests {
+		assert.Equal(t, want, domain.NormalizeSymbol(name), name)
+	}
+}
+
+func TestSplitPair(t *testing.T) {
+	tests := []struct {
+		symbol string
+		base   string
+		quote  string
+		ok     bool
+	}{
+		{"BTCUSDT", "BTC", "USDT", true},
+		{"SOLFDUSD", "SOL", "FDUSD", true},
+		{"ETHBTC", "ETH", "BTC", true},
+		{"USDT", "USDT", "", false},
+		{"XYZ123", "XYZ123", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.symbol, func(t *testing.T) {
+			base, quote, ok := domain.SplitPair(tt.symbol)
+			assert.Equal(t, tt.base, base)
+			assert.Equal(t, tt.quote, quote)
+			assert.Equal(t, tt.ok, ok)
+		})
+	}
+}
+
+func TestSymbolNormalizer(t *testing.T) {
+	t.Run("resolves aliases after normalizing", func(t *testing.T) {
+		n, err := domain.NewSymbolNormalizer([]string{"xbt/usdt=BTCUSDT", " XBTEUR = btc-eur "})
+		require.NoError(t, err)
+
+		assert.Equal(t, "BTCUSDT", n.Normalize("XBTUSDT"))
+		assert.Equal(t, "BTCUSDT", n.Normalize("xbt-usdt"))
+		assert.Equal(t, "BTCEUR", n.Normalize("XBTEUR"))
+		assert.Equal(t, "ETHUSDT", n.Normalize("eth/usdt"))
+		assert.Equal(t, []string{"BTCUSDT", "ETHUSDT"}, n.NormalizeAll([]string{"xbtusdt", "ethusdt"}))
+	})
+
+	t.Run("nil normalizer resolves no aliases", func(t *testing.T) {
+		var n *domain.SymbolNormalizer
+		assert.Equal(t, "XBTUSDT", n.Normalize(" xbt/usdt"))
+	})
+
+	t.Run("rejects invalid aliases", func(t *testing.T) {
+		tests := map[string][]string{
+			"missing separator": {"XBTUSDT"},
+			"invalid name":      {"XBT$=BTCUSDT"},
+			"empty target":      {"XBTUSDT="},
+			"self alias":        {"BTC/USDT=BTCUSDT"},
+			"duplicate":         {"XBTUSDT=BTCUSDT", "xbtusdt=ETHUSDT"},
+			"chain":             {"XBTUSDT=BTCUSDT", "BTCUSDT=ETHUSDT"},
+		}
+
+		for name, aliases := range tests {
+			t.Run(name, func(t *testing.T) {
+				_, err := domain.NewSymbolNormalizer(aliases)
+				assert.ErrorIs(t, err, domain.ErrInvalidAlias)
+			})
+		}
+	})
+}
@@ -94,8 +94,14 @@ func TestNewSymbol(t *testing.T) {
 		assert.Equal(t, "ETHUSDT", symbol.Name)
 	})
 
+	t.Run("drops pair separators", func(t *testing.T) {
+		symbol, err := domain.NewSymbol("sol/usdt")
+		require.NoError(t, err)
+		assert.Equal(t, "SOLUSDT", symbol.Name)
+	})
+
 	t.Run("rejects invalid symbol", func(t *testing.T) {
-		_, err := domain.NewSymbol("invalid-symbol")
+		_, err := domain.NewSymbol("invalid.symbol")
 		assert.ErrorIs(t, err, domain.ErrInvalidSymbol)
 	})
 }
@@ -121,3 +127,25 @@ func TestSymbol_Activate(t *testing.T) {
 	symbol.Activate()
 	assert.True(t, symbol.Active)
 }
+
+func TestNormalizeTags(t *testing.T) {
+	tags, err := domain.NormalizeTags([]string{" Layer1", "stablecoin", "layer1", "defi_v2"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"defi_v2", "layer1", "stablecoin"}, tags)
+
+	_, err = domain.NormalizeTags([]string{"layer 1"})
+	assert.ErrorIs(t, err, domain.ErrInvalidTag)
+
+	_, err = domain.NormalizeTags([]string{""})
+	assert.ErrorIs(t, err, domain.ErrInvalidTag)
+}
+
+func TestSymbol_RemoveTag(t *testing.T) {
+	symbol, err := domain.NewSymbol("BTCUSDT")
+	require.NoError(t, err)
+	require.NoError(t, symbol.SetTags([]string{"layer1", "pow"}))
+
+	assert.True(t, symbol.RemoveTag("pow"))
+	assert.False(t, symbol.RemoveTag("pow"))
+	assert.Equal(t, []string{"layer1"}, symbol.Tags)
+}
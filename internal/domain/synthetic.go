@@ -0,0 +1,156 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SyntheticExchange is the exchange of synthetic symbols. Nothing is
+// fetched for them; their prices are derived from other tracked symbols.
+const SyntheticExchange = "synthetic"
+
+// SyntheticSymbol is a symbol priced as Numerator / Denominator, two pairs
+// quoted in the same asset, e.g. ETHBTCSYNTH = ETHUSDT / BTCUSDT
+type SyntheticSymbol struct {
+	Name        string
+	Numerator   string
+	Denominator string
+}
+
+// String returns the definition in its configuration form
+func (s SyntheticSymbol) String() string {
+	return s.Name + "=" + s.Numerator + "/" + s.Denominator
+}
+
+// ParseSyntheticSymbol parses a "NAME=NUMERATOR/DENOMINATOR" definition,
+// e.g. "ETHBTC_SYNTH=ETHUSDT/BTCUSDT". Names are normalized, so the
+// example is tracked as ETHBTCSYNTH.
+func ParseSyntheticSymbol(spec string) (SyntheticSymbol, error) {
+	name, pair, ok := strings.Cut(spec, "=")
+	if !ok {
+		return SyntheticSymbol{}, fmt.Errorf("%w: %q is not NAME=NUMERATOR/DENOMINATOR", ErrInvalidSyntheticSymbol, spec)
+	}
+	numerator, denominator, ok := strings.Cut(pair, "/")
+	if !ok {
+		return SyntheticSymbol{}, fmt.Errorf("%w: %q is not NAME=NUMERATOR/DENOMINATOR", ErrInvalidSyntheticSymbol, spec)
+	}
+
+	s := SyntheticSymbol{
+		Name:        NormalizeSymbol(name),
+		Numerator:   NormalizeSymbol(numerator),
+		Denominator: NormalizeSymbol(denominator),
+	}
+	for _, n := range []string{s.Name, s.Numerator, s.Denominator} {
+		if ValidateSymbolName(n) != nil {
+			return SyntheticSymbol{}, fmt.Errorf("%w: %q", ErrInvalidSyntheticSymbol, spec)
+		}
+	}
+	if s.Numerator == s.Denominator {
+		return SyntheticSymbol{}, fmt.Errorf("%w: %s divides %s by itself", ErrInvalidSyntheticSymbol, s.Name, s.Numerator)
+	}
+	if s.Name == s.Numerator || s.Name == s.Denominator {
+		return SyntheticSymbol{}, fmt.Errorf("%w: %s is derived from itself", ErrInvalidSyntheticSymbol, s.Name)
+	}
+
+	return s, nil
+}
+
+// SyntheticSymbols are the synthetic symbols derived after each poll
+type SyntheticSymbols []SyntheticSymbol
+
+// ParseSyntheticSymbols parses synthetic symbol definitions, rejecting
+// duplicate names and symbols derived from other synthetic symbols
+func ParseSyntheticSymbols(specs []string) (SyntheticSymbols, error) {
+	symbols := make(SyntheticSymbols, 0, len(specs))
+	names := make(map[string]bool, len(specs))
+
+	for _, spec := range specs {
+		s, err := ParseSyntheticSymbol(spec)
+		if err != nil {
+			return nil, err
+		}
+		if names[s.Name] {
+			return nil, fmt.Errorf("%w: duplicate symbol %s", ErrInvalidSyntheticSymbol, s.Name)
+		}
+		names[s.Name] = true
+		symbols = append(symbols, s)
+	}
+
+	for _, s := range symbols {
+		for _, leg := range []string{s.Numerator, s.Denominator} {
+			if names[leg] {
+				return nil, fmt.Errorf("%w: %s is derived from synthetic symbol %s", ErrInvalidSyntheticSymbol, s.Name, leg)
+			}
+		}
+	}
+
+	return symbols, nil
+}
+
+// Derive returns a snapshot of each synthetic symbol in symbolMap whose
+// two pairs both have a snapshot in snapshots. It is priced from them and
+// timestamped with the later one; its ingest latency is that of the staler
+// one. missing lists, for each synthetic symbol left out, the first pair
+// without a usable price.
+func (s SyntheticSymbols) Derive(symbolMap map[string]*Symbol, snapshots []*PriceSnapshot) (derived []*PriceSnapshot, missing map[string]string) {
+	if len(s) == 0 {
+		return nil, nil
+	}
+
+	priced := make(map[string]*PriceSnapshot, len(snapshots))
+	for _, snap := range snapshots {
+		priced[snap.Symbol] = snap
+	}
+
+	missing = make(map[string]string)
+	for _, def := range s {
+		sym, ok := symbolMap[def.Name]
+		if !ok {
+			continue
+		}
+
+		numerator, denominator := priced[def.Numerator], priced[def.Denominator]
+		if numerator == nil {
+			missing[def.Name] = def.Numerator
+			continue
+		}
+		if denominator == nil {
+			missing[def.Name] = def.Denominator
+			continue
+		}
+
+		price, ok := DividePrice(numerator.Price, denominator.Price)
+		if !ok {
+			missing[def.Name] = def.Denominator
+			continue
+		}
+
+		ts, source := numerator.Timestamp, numerator.TimestampSource
+		if denominator.Timestamp.After(ts) {
+			ts, source = denominator.Timestamp, denominator.TimestampSource
+		}
+
+		derived = append(derived, &PriceSnapshot{
+			SymbolID:        sym.ID,
+			Symbol:          def.Name,
+			Price:           price,
+			Timestamp:       ts,
+			TimestampSource: source,
+			PollRunID:       numerator.PollRunID,
+			Exchange:        SyntheticExchange,
+			IngestMode:      numerator.IngestMode,
+			IngestLatencyMs: maxLatency(numerator.IngestLatencyMs, denominator.IngestLatencyMs),
+			PipelineVersion: numerator.PipelineVersion,
+		})
+	}
+
+	return derived, missing
+}
+
+// maxLatency returns the larger of two optional latencies
+func maxLatency(a, b *int64) *int64 {
+	if a == nil || (b != nil && *b > *a) {
+		return b
+	}
+	return a
+}
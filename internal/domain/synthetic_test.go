@@ -0,0 +1,100 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+)
+
+func TestDividePrice(t *testing.T) {
+	tests := []struct {
+		numerator, denominator, want string
+	}{
+		{"2345.67", "43123.45", "0.054394303"},
+		{"43123.45", "2345.67", "18.384278"},
+		{"1", "3", "0.33333333"},
+		{"0.5", "0.25", "2"},
+	}
+
+	for _, tt := range tests {
+		quotient, ok := domain.DividePrice(decimal.RequireFromString(tt.numerator), decimal.RequireFromString(tt.denominator))
+		assert.True(t, ok)
+		assert.Equal(t, tt.want, quotient.String(), tt.numerator+"/"+tt.denominator)
+	}
+
+	t.Run("rejects prices that are not positive", func(t *testing.T) {
+		_, ok := domain.DividePrice(decimal.NewFromInt(1), decimal.Zero)
+		assert.False(t, ok)
+		_, ok = domain.DividePrice(decimal.Zero, decimal.NewFromInt(1))
+		assert.False(t, ok)
+	})
+}
+
+func TestParseSyntheticSymbols(t *testing.T) {
+	t.Run("parses and normalizes definitions", func(t *testing.T) {
+		symbols, err := domain.ParseSyntheticSymbols([]string{"ETHBTC_SYNTH=ETHUSDT/BTCUSDT", " solbtc-synth = sol_usdt / btc-usdt "})
+		require.NoError(t, err)
+		assert.Equal(t, domain.SyntheticSymbols{
+			{Name: "ETHBTCSYNTH", Numerator: "ETHUSDT", Denominator: "BTCUSDT"},
+			{Name: "SOLBTCSYNTH", Numerator: "SOLUSDT", Denominator: "BTCUSDT"},
+		}, symbols)
+	})
+
+	invalid := map[string][]string{
+		"missing pair":       {"ETHBTCSYNTH"},
+		"missing separator":  {"ETHBTCSYNTH=ETHUSDT"},
+		"invalid name":       {"E=ETHUSDT/BTCUSDT"},
+		"same pairs":         {"ETHBTCSYNTH=ETHUSDT/ETHUSDT"},
+		"derived from self":  {"ETHUSDT=ETHUSDT/BTCUSDT"},
+		"duplicate":          {"ETHBTCSYNTH=ETHUSDT/BTCUSDT", "ETHBTC_SYNTH=ETHUSDC/BTCUSDC"},
+		"derived from synth": {"ETHBTCSYNTH=ETHUSDT/BTCUSDT", "X2SYNTH=ETHBTCSYNTH/BNBBTC"},
+	}
+	for name, specs := range invalid {
+		t.Run(name, func(t *testing.T) {
+			_, err := domain.ParseSyntheticSymbols(specs)
+			assert.ErrorIs(t, err, domain.ErrInvalidSyntheticSymbol)
+		})
+	}
+}
+
+func TestSyntheticSymbols_Derive(t *testing.T) {
+	symbols := domain.SyntheticSymbols{
+		{Name: "ETHBTCSYNTH", Numerator: "ETHUSDT", Denominator: "BTCUSDT"},
+		{Name: "SOLBTCSYNTH", Numerator: "SOLUSDT", Denominator: "BTCUSDT"},
+		{Name: "BNBBTCSYNTH", Numerator: "BNBUSDT", Denominator: "BTCUSDT"},
+	}
+	symbolMap := map[string]*domain.Symbol{
+		"ETHBTCSYNTH": {ID: 10, Name: "ETHBTCSYNTH"},
+		"SOLBTCSYNTH": {ID: 11, Name: "SOLBTCSYNTH"},
+	}
+
+	at := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	slow, fast := int64(40), int64(5)
+	snapshots := []*domain.PriceSnapshot{
+		{Symbol: "BTCUSDT", Price: decimal.RequireFromString("43123.45"), Timestamp: at.Add(time.Second), TimestampSource: domain.TimestampSourceFetch, PollRunID: "run", IngestMode: domain.IngestModePoll, IngestLatencyMs: &fast},
+		{Symbol: "ETHUSDT", Price: decimal.RequireFromString("2345.67"), Timestamp: at, TimestampSource: domain.TimestampSourceFetch, PollRunID: "run", IngestMode: domain.IngestModePoll, IngestLatencyMs: &slow},
+		{Symbol: "BNBUSDT", Price: decimal.RequireFromString("310.5"), Timestamp: at, PollRunID: "run"},
+	}
+
+	derived, missing := symbols.Derive(symbolMap, snapshots)
+
+	require.Len(t, derived, 1)
+	snap := derived[0]
+	assert.Equal(t, int64(10), snap.SymbolID)
+	assert.Equal(t, "ETHBTCSYNTH", snap.Symbol)
+	assert.Equal(t, "0.054394303", snap.Price.String())
+	assert.Equal(t, at.Add(time.Second), snap.Timestamp)
+	assert.Equal(t, domain.TimestampSourceFetch, snap.TimestampSource)
+	assert.Equal(t, domain.SyntheticExchange, snap.Exchange)
+	assert.Equal(t, "run", snap.PollRunID)
+	assert.Equal(t, domain.IngestModePoll, snap.IngestMode)
+	assert.Equal(t, &slow, snap.IngestLatencyMs)
+
+	// BNBBTCSYNTH is not tracked, so it is neither derived nor missing
+	assert.Equal(t, map[string]string{"SOLBTCSYNTH": "SOLUSDT"}, missing)
+}
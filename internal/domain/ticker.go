@@ -0,0 +1,19 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// TickerStats represents rolling 24h statistics for a symbol
+type TickerStats struct {
+	ID                 int64           `json:"id"`
+	SymbolID           int64           `json:"symbol_id"`
+	Symbol             string          `json:"symbol"`
+	High               decimal.Decimal `json:"high"`
+	Low                decimal.Decimal `json:"low"`
+	Volume             decimal.Decimal `json:"volume"`
+	PriceChangePercent decimal.Decimal `json:"price_change_percent"`
+	Timestamp          time.Time       `json:"timestamp"`
+}
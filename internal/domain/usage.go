@@ -0,0 +1,102 @@
+package domain
+
+import (
+	"sort"
+	"time"
+)
+
+// AnonymousUsageKey counts the requests made without an API key
+const AnonymousUsageKey = "anonymous"
+
+// MaxUsageRangeDays caps the days a single usage query spans
+const MaxUsageRangeDays = 366
+
+// UsageCount is how many requests one API key made to one endpoint on one
+// UTC day. Endpoint is the method and route pattern, e.g.
+// "GET /symbols/{symbol}", so requests for different symbols share a count.
+type UsageCount struct {
+	Day      time.Time `json:"day"`
+	Key      string    `json:"key"`
+	Endpoint string    `json:"endpoint"`
+	Requests int64     `json:"requests"`
+}
+
+// UsageDay returns the UTC day t is counted in
+func UsageDay(t time.Time) time.Time {
+	y, m, d := t.UTC().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// UsageMonth returns the first day of the UTC month t falls in
+func UsageMonth(t time.Time) time.Time {
+	y, m, _ := t.UTC().Date()
+	return time.Date(y, m, 1, 0, 0, 0, 0, time.UTC)
+}
+
+// UsageSummary totals the requests of each API key over the days from From
+// up to, but not including, To, keys with the most requests first
+type UsageSummary struct {
+	From     time.Time       `json:"from"`
+	To       time.Time       `json:"to"`
+	Requests int64           `json:"requests"`
+	Keys     []UsageKeyTotal `json:"keys"`
+}
+
+// UsageKeyTotal is the requests of one API key, in total and per endpoint,
+// endpoints with the most requests first
+type UsageKeyTotal struct {
+	Key       string               `json:"key"`
+	Requests  int64                `json:"requests"`
+	Endpoints []UsageEndpointTotal `json:"endpoints"`
+}
+
+// UsageEndpointTotal is the requests one API key made to one endpoint
+type UsageEndpointTotal struct {
+	Endpoint string `json:"endpoint"`
+	Requests int64  `json:"requests"`
+}
+
+// SummarizeUsage totals daily counts per key and endpoint. Counts outside
+// [from, to) are left out.
+func SummarizeUsage(from, to time.Time, counts []UsageCount) *UsageSummary {
+	summary := &UsageSummary{From: from.UTC(), To: to.UTC(), Keys: []UsageKeyTotal{}}
+
+	byKey := make(map[string]map[string]int64)
+	for _, c := range counts {
+		if c.Day.Before(from) || !c.Day.Before(to) {
+			continue
+		}
+		endpoints, ok := byKey[c.Key]
+		if !ok {
+			endpoints = make(map[string]int64)
+			byKey[c.Key] = endpoints
+		}
+		endpoints[c.Endpoint] += c.Requests
+		summary.Requests += c.Requests
+	}
+
+	for key, endpoints := range byKey {
+		total := UsageKeyTotal{Key: key, Endpoints: make([]UsageEndpointTotal, 0, len(endpoints))}
+		for endpoint, requests := range endpoints {
+			total.Endpoints = append(total.Endpoints, UsageEndpointTotal{Endpoint: endpoint, Requests: requests})
+			total.Requests += requests
+		}
+		sort.Slice(total.Endpoints, func(i, j int) bool {
+			a, b := total.Endpoints[i], total.Endpoints[j]
+			if a.Requests != b.Requests {
+				return a.Requests > b.Requests
+			}
+			return a.Endpoint < b.Endpoint
+		})
+		summary.Keys = append(summary.Keys, total)
+	}
+	sort.Slice(summary.Keys, func(i, j int) bool {
+		a, b := summary.Keys[i], summary.Keys[j]
+		if a.Requests != b.Requests {
+			return a.Requests > b.Requests
+		}
+		return a.Key < b.Key
+	})
+
+	return summary
+}
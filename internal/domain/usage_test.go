@@ -0,0 +1,61 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUsageDayAndMonth(t *testing.T) {
+	at := time.Date(2024, 2, 29, 23, 30, 0, 0, time.FixedZone("UTC-2", -2*60*60))
+
+	assert.Equal(t, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), domain.UsageDay(at))
+	assert.Equal(t, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), domain.UsageMonth(at))
+	assert.Equal(t, time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), domain.UsageMonth(at.Add(-2*time.Hour)))
+}
+
+func TestSummarizeUsage(t *testing.T) {
+	jan := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	feb := jan.AddDate(0, 1, 0)
+	counts := []domain.UsageCount{
+		{Day: jan, Key: "key:aaaa", Endpoint: "GET /prices", Requests: 10},
+		{Day: jan.AddDate(0, 0, 1), Key: "key:aaaa", Endpoint: "GET /prices", Requests: 5},
+		{Day: jan, Key: "key:aaaa", Endpoint: "GET /history/{symbol}", Requests: 20},
+		{Day: jan.AddDate(0, 0, 30), Key: "anonymous", Endpoint: "GET /prices", Requests: 40},
+		// Outside the month
+		{Day: feb, Key: "key:bbbb", Endpoint: "GET /prices", Requests: 100},
+	}
+
+	summary := domain.SummarizeUsage(jan, feb, counts)
+
+	assert.Equal(t, jan, summary.From)
+	assert.Equal(t, feb, summary.To)
+	assert.Equal(t, int64(75), summary.Requests)
+	require.Len(t, summary.Keys, 2)
+	assert.Equal(t, domain.UsageKeyTotal{
+		Key:       "anonymous",
+		Requests:  40,
+		Endpoints: []domain.UsageEndpointTotal{{Endpoint: "GET /prices", Requests: 40}},
+	}, summary.Keys[0])
+	assert.Equal(t, domain.UsageKeyTotal{
+		Key:      "key:aaaa",
+		Requests: 35,
+		Endpoints: []domain.UsageEndpointTotal{
+			{Endpoint: "GET /history/{symbol}", Requests: 20},
+			{Endpoint: "GET /prices", Requests: 15},
+		},
+	}, summary.Keys[1])
+}
+
+func TestSummarizeUsage_Empty(t *testing.T) {
+	jan := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	summary := domain.SummarizeUsage(jan, jan.AddDate(0, 1, 0), nil)
+
+	assert.Zero(t, summary.Requests)
+	assert.NotNil(t, summary.Keys)
+	assert.Empty(t, summary.Keys)
+}
@@ -0,0 +1,11 @@
+package domain
+
+import "time"
+
+// WarmupReport summarizes the startup verification of tracked symbols
+type WarmupReport struct {
+	CheckedSymbols      int       `json:"checked_symbols"`
+	Verified            bool      `json:"verified"`
+	UnrecognizedSymbols []string  `json:"unrecognized_symbols,omitempty"`
+	CompletedAt         time.Time `json:"completed_at"`
+}
@@ -0,0 +1,26 @@
+package ports
+
+import "time"
+
+// Clock tells the time and schedules ticks. Services and workers that
+// depend on the time take a Clock, so tests can drive them with a fake one.
+type Clock interface {
+	// Now returns the current time
+	Now() time.Time
+
+	// Since returns the time elapsed since t
+	Since(t time.Time) time.Duration
+
+	// NewTicker returns a ticker that ticks every d
+	NewTicker(d time.Duration) Ticker
+}
+
+// Ticker delivers ticks at a fixed interval. Like time.Ticker, it drops
+// ticks for a slow receiver.
+type Ticker interface {
+	// C returns the channel the ticks are delivered on
+	C() <-chan time.Time
+
+	// Stop turns off the ticker
+	Stop()
+}
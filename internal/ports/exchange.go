@@ -2,6 +2,7 @@ package ports
 
 import (
 	"context"
+	"time"
 
 	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
 )
@@ -17,6 +18,90 @@ type ExchangeClient interface {
 	// ValidateSymbol checks if a symbol exists on the exchange
 	ValidateSymbol(ctx context.Context, symbol string) (bool, error)
 
+	// GetCandles fetches historical candles for a symbol between from and to.
+	// interval uses exchange notation (e.g. "1m", "1h").
+	GetCandles(ctx context.Context, symbol, interval string, from, to time.Time, limit int) ([]*domain.Candle, error)
+
+	// GetTickerStats fetches rolling 24h statistics for multiple symbols
+	GetTickerStats(ctx context.Context, symbols []string) ([]*domain.TickerStats, error)
+
+	// ListTradingSymbols returns all symbols currently trading on the exchange
+	ListTradingSymbols(ctx context.Context) ([]string, error)
+
+	// GetSymbolPrecisions returns the price tick size of the given symbols,
+	// or of every listed symbol when none are given
+	GetSymbolPrecisions(ctx context.Context, symbols []string) ([]*domain.SymbolPrecision, error)
+
 	// Ping checks if the exchange is reachable
 	Ping(ctx context.Context) error
 }
+
+// ExchangeRegistry holds the clients of the exchanges symbols are polled
+// from, by name
+type ExchangeRegistry interface {
+	// Client returns the named exchange's client, or the default exchange's
+	// when name is empty. It fails with domain.ErrUnknownExchange for an
+	// exchange that is not configured.
+	Client(name string) (ExchangeClient, error)
+
+	// Default returns the name of the default exchange
+	Default() string
+
+	// Names returns the configured exchange names in sorted order
+	Names() []string
+}
+
+// PriceStream pushes prices from an exchange as they change, instead of
+// having them polled
+type PriceStream interface {
+	// Stream passes each batch of updated prices to fn until ctx is done,
+	// reconnecting and resubscribing whenever the connection drops. fn is
+	// called from a single goroutine.
+	Stream(ctx context.Context, fn func(prices []*domain.Price)) error
+}
+
+// CoinMetadataProvider defines the contract for fetching descriptive coin
+// data from a market data provider
+type CoinMetadataProvider interface {
+	// GetCoinMetadata returns metadata keyed by the given base assets (e.g.
+	// "BTC"), omitting assets the provider does not know
+	GetCoinMetadata(ctx context.Context, assets []string) (map[string]*domain.CoinMetadata, error)
+}
+
+// ExchangeErrorRecorder receives classified exchange request failures
+type ExchangeErrorRecorder interface {
+	// RecordExchangeError counts a failed request attempt for an operation
+	RecordExchangeError(operation string, kind domain.ExchangeErrorKind)
+}
+
+// ExchangeRetryRecorder receives the retries of exchange requests
+type ExchangeRetryRecorder interface {
+	// RecordExchangeRetry counts a retry of an operation's request, by the
+	// kind of failure being retried
+	RecordExchangeRetry(operation string, reason domain.ExchangeErrorKind)
+
+	// RecordExchangeAttempts records the request attempts one call of an
+	// operation made, the first included
+	RecordExchangeAttempts(operation string, attempts int)
+}
+
+// ExchangeBudget shares the exchange's request weight limit between
+// features by priority, read from the request context with
+// domain.ExchangePriorityFrom
+type ExchangeBudget interface {
+	// Acquire reserves weight for a request, waiting while the priority's
+	// share of the current window is used up. It fails when ctx is done, or
+	// at once with domain.ErrExchangeBudget when ctx's deadline comes
+	// before the weight is free.
+	Acquire(ctx context.Context, weight int) error
+
+	// Observe reports the weight the exchange says is used in the current
+	// window, which includes calls made by other processes
+	Observe(used int)
+}
+
+// ExchangeBudgetStatsProvider reports the use of the exchange request budget
+type ExchangeBudgetStatsProvider interface {
+	// BudgetStats returns the current budget statistics
+	BudgetStats() *domain.ExchangeBudgetStats
+}
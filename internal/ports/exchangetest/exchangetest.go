@@ -0,0 +1,118 @@
+// Package exchangetest is a conformance suite for ports.ExchangeClient
+// implementations. An adapter's tests run a fake of its exchange's API that
+// plays the Scenario the suite hands it, and Run checks that the client maps
+// what the fake does onto the ExchangeClient contract: prices parsed
+// exactly, failures surfaced as the domain errors the services handle,
+// transient failures retried and cancellation honored.
+//
+// A new exchange adapter is conformant once its tests pass Run:
+//
+//	func TestConformance(t *testing.T) {
+//		exchangetest.Run(t, func(t *testing.T, s *exchangetest.Scenario) ports.ExchangeClient {
+//			server := httptest.NewServer(fakeExchange(s))
+//			t.Cleanup(server.Close)
+//			return NewClient(WithBaseURL(server.URL), WithRetry(exchangetest.MaxRetries, time.Millisecond))
+//		})
+//	}
+package exchangetest
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+)
+
+// MaxRetries is the number of retries the factory must configure the client
+// with, so the suite can count the attempts it makes. The backoff between
+// them should be negligible.
+const MaxRetries = 2
+
+// Failure is a way the fake exchange answers a request instead of serving it
+type Failure int
+
+const (
+	// FailNone serves the request
+	FailNone Failure = iota
+
+	// FailRateLimited answers with the exchange's rate limit response,
+	// e.g. 429 Too Many Requests
+	FailRateLimited
+
+	// FailServerError answers with a server error, e.g. 503
+	FailServerError
+
+	// FailMalformed answers with a successful status and a body that
+	// cannot be decoded
+	FailMalformed
+
+	// FailHang does not answer until the request is cancelled; the fake
+	// should block in Scenario.Hang
+	FailHang
+)
+
+// Scenario is what the fake exchange must do. It is safe for concurrent use
+// by the fake's handlers.
+type Scenario struct {
+	// Prices are the symbols the exchange lists and their prices in
+	// decimal notation. Requests for any other symbol get the exchange's
+	// unknown symbol response.
+	Prices map[string]string
+
+	// Fail is how the first Failures requests are answered; a negative
+	// Failures fails every request
+	Fail     Failure
+	Failures int
+
+	requests atomic.Int64
+	stopOnce sync.Once
+	stop     chan struct{}
+}
+
+func newScenario(prices map[string]string, fail Failure, failures int) *Scenario {
+	return &Scenario{
+		Prices:   prices,
+		Fail:     fail,
+		Failures: failures,
+		stop:     make(chan struct{}),
+	}
+}
+
+// Next records a request and returns how the fake must answer it
+func (s *Scenario) Next() Failure {
+	n := s.requests.Add(1)
+	if s.Failures < 0 || n <= int64(s.Failures) {
+		return s.Fail
+	}
+	return FailNone
+}
+
+// Price returns the price listed for symbol
+func (s *Scenario) Price(symbol string) (string, bool) {
+	price, ok := s.Prices[symbol]
+	return price, ok
+}
+
+// Requests returns the number of requests the fake has received
+func (s *Scenario) Requests() int {
+	return int(s.requests.Load())
+}
+
+// Hang blocks a handler playing FailHang until ctx, the request's context,
+// is done or the scenario is over
+func (s *Scenario) Hang(ctx context.Context) {
+	select {
+	case <-ctx.Done():
+	case <-s.stop:
+	}
+}
+
+func (s *Scenario) end() {
+	s.stopOnce.Do(func() { close(s.stop) })
+}
+
+// Factory starts a fake exchange playing s and returns a client for it,
+// configured with MaxRetries. The fake is torn down with t.Cleanup.
+type Factory func(t *testing.T, s *Scenario) ports.ExchangeClient
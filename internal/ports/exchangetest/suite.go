@@ -0,0 +1,217 @@
+package exchangetest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+)
+
+// cancelAfter is how long a hanging request runs before it is cancelled
+const cancelAfter = 50 * time.Millisecond
+
+// prices are the listed symbols of every scenario. They include a price
+// below float64's exact range to catch clients that parse through floats.
+func prices() map[string]string {
+	return map[string]string{
+		"BTCUSDT":  "43123.45",
+		"ETHUSDT":  "2345.67",
+		"SHIBUSDT": "0.00000912345678",
+	}
+}
+
+// Run checks that the clients built by newClient conform to the
+// ports.ExchangeClient contract
+func Run(t *testing.T, newClient Factory) {
+	t.Helper()
+
+	play := func(t *testing.T, fail Failure, failures int) *Scenario {
+		s := newScenario(prices(), fail, failures)
+		t.Cleanup(s.end)
+		return s
+	}
+	ctx := context.Background
+
+	t.Run("prices", func(t *testing.T) {
+		t.Run("GetPrice parses the price exactly", func(t *testing.T) {
+			s := play(t, FailNone, 0)
+			client := newClient(t, s)
+
+			price, err := client.GetPrice(ctx(), "SHIBUSDT")
+			require.NoError(t, err)
+			assert.Equal(t, "SHIBUSDT", price.Symbol)
+			assert.True(t, price.Price.Equal(decimal.RequireFromString("0.00000912345678")), "got %s", price.Price)
+			assert.False(t, price.FetchedAt.IsZero(), "FetchedAt must be set")
+		})
+
+		t.Run("GetPrices returns every requested symbol", func(t *testing.T) {
+			s := play(t, FailNone, 0)
+			client := newClient(t, s)
+
+			got, err := client.GetPrices(ctx(), []string{"BTCUSDT", "ETHUSDT", "SHIBUSDT"})
+			require.NoError(t, err)
+
+			bySymbol := make(map[string]*domain.Price, len(got))
+			for _, p := range got {
+				bySymbol[p.Symbol] = p
+			}
+			require.Len(t, bySymbol, 3)
+			for symbol, want := range prices() {
+				require.Contains(t, bySymbol, symbol)
+				assert.True(t, bySymbol[symbol].Price.Equal(decimal.RequireFromString(want)),
+					"%s: got %s, want %s", symbol, bySymbol[symbol].Price, want)
+				assert.False(t, bySymbol[symbol].FetchedAt.IsZero(), "%s: FetchedAt must be set", symbol)
+			}
+		})
+
+		t.Run("GetPrices without symbols makes no request", func(t *testing.T) {
+			s := play(t, FailNone, 0)
+			client := newClient(t, s)
+
+			got, err := client.GetPrices(ctx(), nil)
+			require.NoError(t, err)
+			assert.Empty(t, got)
+			assert.Zero(t, s.Requests())
+		})
+
+		t.Run("ValidateSymbol reports listed symbols", func(t *testing.T) {
+			s := play(t, FailNone, 0)
+			client := newClient(t, s)
+
+			ok, err := client.ValidateSymbol(ctx(), "BTCUSDT")
+			require.NoError(t, err)
+			assert.True(t, ok)
+
+			ok, err = client.ValidateSymbol(ctx(), "NOPEUSDT")
+			require.NoError(t, err, "an unknown symbol is not an error")
+			assert.False(t, ok)
+		})
+
+		t.Run("Ping succeeds", func(t *testing.T) {
+			s := play(t, FailNone, 0)
+			client := newClient(t, s)
+
+			require.NoError(t, client.Ping(ctx()))
+		})
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		t.Run("unknown symbol is ErrInvalidSymbol and not retried", func(t *testing.T) {
+			s := play(t, FailNone, 0)
+			client := newClient(t, s)
+
+			_, err := client.GetPrice(ctx(), "NOPEUSDT")
+			assert.ErrorIs(t, err, domain.ErrInvalidSymbol)
+			assert.Equal(t, 1, s.Requests())
+		})
+
+		t.Run("persistent rate limiting is ErrRateLimited", func(t *testing.T) {
+			s := play(t, FailRateLimited, -1)
+			client := newClient(t, s)
+
+			_, err := client.GetPrice(ctx(), "BTCUSDT")
+			assert.ErrorIs(t, err, domain.ErrRateLimited)
+		})
+
+		t.Run("persistent server errors are ErrExchangeUnavailable", func(t *testing.T) {
+			s := play(t, FailServerError, -1)
+			client := newClient(t, s)
+
+			_, err := client.GetPrice(ctx(), "BTCUSDT")
+			assert.ErrorIs(t, err, domain.ErrExchangeUnavailable)
+
+			_, err = client.GetPrices(ctx(), []string{"BTCUSDT"})
+			assert.ErrorIs(t, err, domain.ErrExchangeUnavailable)
+
+			assert.ErrorIs(t, client.Ping(ctx()), domain.ErrExchangeUnavailable)
+		})
+
+		t.Run("unknown symbol check fails when the exchange is down", func(t *testing.T) {
+			s := play(t, FailServerError, -1)
+			client := newClient(t, s)
+
+			ok, err := client.ValidateSymbol(ctx(), "BTCUSDT")
+			assert.Error(t, err, "an outage must not report symbols as unknown")
+			assert.False(t, ok)
+		})
+
+		t.Run("malformed response is an error", func(t *testing.T) {
+			s := play(t, FailMalformed, -1)
+			client := newClient(t, s)
+
+			_, err := client.GetPrice(ctx(), "BTCUSDT")
+			assert.Error(t, err)
+
+			got, err := client.GetPrices(ctx(), []string{"BTCUSDT"})
+			assert.Error(t, err)
+			assert.Empty(t, got)
+		})
+	})
+
+	t.Run("retries", func(t *testing.T) {
+		for _, tc := range []struct {
+			name string
+			fail Failure
+		}{
+			{"rate limiting", FailRateLimited},
+			{"server errors", FailServerError},
+		} {
+			t.Run("recovers from "+tc.name, func(t *testing.T) {
+				s := play(t, tc.fail, MaxRetries)
+				client := newClient(t, s)
+
+				price, err := client.GetPrice(ctx(), "BTCUSDT")
+				require.NoError(t, err)
+				assert.True(t, price.Price.Equal(decimal.RequireFromString("43123.45")))
+				assert.Equal(t, MaxRetries+1, s.Requests())
+			})
+
+			t.Run("gives up on "+tc.name+" after MaxRetries", func(t *testing.T) {
+				s := play(t, tc.fail, -1)
+				client := newClient(t, s)
+
+				_, err := client.GetPrices(ctx(), []string{"BTCUSDT"})
+				require.Error(t, err)
+				assert.Equal(t, MaxRetries+1, s.Requests())
+			})
+		}
+	})
+
+	t.Run("cancellation", func(t *testing.T) {
+		t.Run("cancelled context fails without retrying", func(t *testing.T) {
+			s := play(t, FailNone, 0)
+			client := newClient(t, s)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			_, err := client.GetPrice(ctx, "BTCUSDT")
+			assert.ErrorIs(t, err, context.Canceled)
+			assert.LessOrEqual(t, s.Requests(), 1)
+		})
+
+		t.Run("deadline interrupts a hanging request", func(t *testing.T) {
+			s := play(t, FailHang, -1)
+			client := newClient(t, s)
+			defer s.end()
+
+			ctx, cancel := context.WithTimeout(context.Background(), cancelAfter)
+			defer cancel()
+
+			start := time.Now()
+			_, err := client.GetPrices(ctx, []string{"BTCUSDT"})
+			elapsed := time.Since(start)
+
+			require.Error(t, err)
+			assert.True(t, errors.Is(err, context.DeadlineExceeded), "got %v", err)
+			assert.Less(t, elapsed, 20*cancelAfter, "the request must return once its context is done")
+			assert.Equal(t, 1, s.Requests(), "a cancelled request must not be retried")
+		})
+	})
+}
@@ -0,0 +1,39 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+)
+
+// Notifier defines the contract for delivering digests to a channel
+type Notifier interface {
+	// Name identifies the channel in logs
+	Name() string
+
+	// SendDigest delivers a price movement digest
+	SendDigest(ctx context.Context, digest *domain.PriceDigest) error
+}
+
+// AlertNotifier defines the contract for posting alert firings to webhooks
+type AlertNotifier interface {
+	// NotifyAlert posts the notification to url
+	NotifyAlert(ctx context.Context, url string, notification *domain.AlertNotification) error
+}
+
+// SymbolEventNotifier defines the contract for posting symbol lifecycle
+// events to subscribers
+type SymbolEventNotifier interface {
+	// NotifySymbolEvents delivers a batch of symbol events
+	NotifySymbolEvents(ctx context.Context, digest *domain.SymbolEventDigest) error
+}
+
+// SymbolEventEncoder defines the contract for serializing symbol event
+// digests for delivery
+type SymbolEventEncoder interface {
+	// ContentType is the media type of encoded digests
+	ContentType() string
+
+	// EncodeSymbolEvents serializes a digest
+	EncodeSymbolEvents(ctx context.Context, digest *domain.SymbolEventDigest) ([]byte, error)
+}
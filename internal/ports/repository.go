@@ -2,6 +2,7 @@ package ports
 
 import (
 	"context"
+	"io"
 	"time"
 
 	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
@@ -24,20 +25,72 @@ type SymbolRepository interface {
 	// ListActive returns only active symbols
 	ListActive(ctx context.Context) ([]*domain.Symbol, error)
 
-	// Delete removes a symbol by name
+	// ListByTag returns symbols carrying the given tag
+	ListByTag(ctx context.Context, tag string) ([]*domain.Symbol, error)
+
+	// ListPage returns up to limit symbols ordered by name, continuing
+	// after after when it is set, restricted to those carrying tag unless
+	// it is empty
+	ListPage(ctx context.Context, tag string, after *domain.SymbolCursor, limit int) ([]*domain.Symbol, error)
+
+	// ListIncludingDeleted returns tracked and soft deleted symbols,
+	// ordered by name
+	ListIncludingDeleted(ctx context.Context) ([]*domain.Symbol, error)
+
+	// SoftDelete removes a tracked symbol but keeps its snapshots. The
+	// symbol is left out of every other lookup, list and count, and
+	// creating it again revives it.
+	SoftDelete(ctx context.Context, name string) error
+
+	// Delete removes a symbol by name, tracked or soft deleted, together
+	// with its snapshots
 	Delete(ctx context.Context, name string) error
 
 	// Update modifies an existing symbol
 	Update(ctx context.Context, symbol *domain.Symbol) error
 
+	// SetActive sets the active flag of the named tracked symbols in one
+	// transaction and returns the names whose flag changed. When any of
+	// them is not tracked nothing is changed, and it fails with
+	// domain.ErrSymbolNotFound.
+	SetActive(ctx context.Context, names []string, active bool) ([]string, error)
+
+	// UpdateMetadata stores a symbol's coin metadata
+	UpdateMetadata(ctx context.Context, name string, metadata *domain.CoinMetadata) error
+
 	// Count returns total number of symbols
 	Count(ctx context.Context) (int, error)
 
 	// CountActive returns number of active symbols
 	CountActive(ctx context.Context) (int, error)
 
-	// Exists checks if a symbol exists
+	// Exists checks if a symbol is tracked
 	Exists(ctx context.Context, name string) (bool, error)
+
+	// ExistsIncludingDeleted checks if a symbol is tracked or was soft
+	// deleted, i.e. whether it may have history
+	ExistsIncludingDeleted(ctx context.Context, name string) (bool, error)
+}
+
+// SymbolEventRepository defines the contract for symbol change history
+type SymbolEventRepository interface {
+	// Create records a symbol change
+	Create(ctx context.Context, event *domain.SymbolEvent) error
+
+	// ListBySymbol returns a symbol's most recent events, newest first
+	ListBySymbol(ctx context.Context, symbolName string, limit int) ([]*domain.SymbolEvent, error)
+
+	// ListAfter returns up to limit events of the given types recorded
+	// after the event with ID afterID, oldest first
+	ListAfter(ctx context.Context, afterID int64, types []domain.SymbolEventType, limit int) ([]*domain.SymbolEvent, error)
+
+	// DeliveryCursor returns the ID of the last event delivered to
+	// subscriber. A new subscriber starts at the latest event, so it is not
+	// sent the whole history.
+	DeliveryCursor(ctx context.Context, subscriber string) (int64, error)
+
+	// SetDeliveryCursor records eventID as delivered to subscriber
+	SetDeliveryCursor(ctx context.Context, subscriber string, eventID int64) error
 }
 
 // SnapshotRepository defines the contract for snapshot persistence
@@ -48,17 +101,41 @@ type SnapshotRepository interface {
 	// CreateBatch stores multiple snapshots atomically
 	CreateBatch(ctx context.Context, snapshots []*domain.PriceSnapshot) error
 
+	// CreateBatchPartial stores every snapshot it can, reporting rows that
+	// were rejected instead of failing the whole batch. An error means
+	// nothing was stored.
+	CreateBatchPartial(ctx context.Context, snapshots []*domain.PriceSnapshot) (*domain.BatchWriteResult, error)
+
+	// CreateBatchIngested stores pushed snapshots atomically, skipping those
+	// whose symbol, timestamp and source are already stored. It returns the
+	// number of snapshots stored.
+	CreateBatchIngested(ctx context.Context, snapshots []*domain.PriceSnapshot) (int, error)
+
 	// GetLatestBySymbol returns the most recent snapshot for a symbol
 	GetLatestBySymbol(ctx context.Context, symbolName string) (*domain.PriceSnapshot, error)
 
 	// GetLatestBySymbols returns the most recent snapshot for multiple symbols
 	GetLatestBySymbols(ctx context.Context, symbolNames []string) ([]*domain.PriceSnapshot, error)
 
-	// GetHistory returns historical snapshots for a symbol
-	GetHistory(ctx context.Context, symbolName string, limit int) ([]*domain.PriceSnapshot, error)
+	// GetPreviousBySymbols returns the snapshot before the most recent one
+	// for multiple symbols, omitting symbols with fewer than two snapshots
+	GetPreviousBySymbols(ctx context.Context, symbolNames []string) ([]*domain.PriceSnapshot, error)
 
-	// GetHistoryBetween returns snapshots within a time range
-	GetHistoryBetween(ctx context.Context, symbolName string, from, to time.Time, limit int) ([]*domain.PriceSnapshot, error)
+	// GetHistory returns historical snapshots for a symbol, newest first,
+	// continuing after before when it is set
+	GetHistory(ctx context.Context, symbolName string, before *domain.HistoryCursor, limit int) ([]*domain.PriceSnapshot, error)
+
+	// EachBetween calls fn with each snapshot in [from, to), oldest first,
+	// without loading the range into memory. An error from fn stops the scan.
+	EachBetween(ctx context.Context, symbolName string, from, to time.Time, fn func(*domain.PriceSnapshot) error) error
+
+	// GetHistoryBetween returns snapshots within a time range, newest
+	// first, continuing after before when it is set
+	GetHistoryBetween(ctx context.Context, symbolName string, from, to time.Time, before *domain.HistoryCursor, limit int) ([]*domain.PriceSnapshot, error)
+
+	// GetPriceChanges returns each symbol's first snapshot since the given
+	// time and its latest snapshot
+	GetPriceChanges(ctx context.Context, since time.Time) ([]*domain.PriceChange, error)
 
 	// Count returns total number of snapshots
 	Count(ctx context.Context) (int64, error)
@@ -66,6 +143,209 @@ type SnapshotRepository interface {
 	// CountBySymbol returns number of snapshots for a symbol
 	CountBySymbol(ctx context.Context, symbolName string) (int64, error)
 
+	// GetHistorySummary returns the number of snapshots stored for a symbol
+	// and the timestamps of its oldest and newest
+	GetHistorySummary(ctx context.Context, symbolName string) (*domain.HistorySummary, error)
+
+	// CountPerSymbol returns the number of snapshots of every symbol that
+	// has any, ordered by symbol
+	CountPerSymbol(ctx context.Context) ([]domain.SymbolSnapshotCount, error)
+
 	// Prune removes snapshots older than the given time
 	Prune(ctx context.Context, olderThan time.Time) (int64, error)
+
+	// CompressBySymbol packs a symbol's polled snapshots older than before
+	// into chunks of the given width, returning the chunks written and the
+	// rows they replaced. History reads include compressed snapshots.
+	CompressBySymbol(ctx context.Context, symbolName string, before time.Time, width time.Duration) (int, int64, error)
+
+	// PruneBySymbol removes a single symbol's snapshots older than the given time
+	PruneBySymbol(ctx context.Context, symbolName string, olderThan time.Time) (int64, error)
+
+	// CountRange counts a symbol's snapshots in [from, to) as DeleteRange
+	// would delete them, without deleting anything
+	CountRange(ctx context.Context, symbolName string, from, to time.Time) (*domain.SnapshotRangeDeletion, error)
+
+	// DeleteRange removes a symbol's snapshots in [from, to), batchSize
+	// rows per statement. Compressed chunks straddling the range are kept.
+	DeleteRange(ctx context.Context, symbolName string, from, to time.Time, batchSize int) (*domain.SnapshotRangeDeletion, error)
+
+	// GetPollRun returns the snapshots the poll with the given run ID
+	// stored. Compressed snapshots are not included.
+	GetPollRun(ctx context.Context, runID string) ([]*domain.PriceSnapshot, error)
+
+	// DeletePollRun removes the snapshots the poll with the given run ID
+	// stored and returns how many were deleted
+	DeletePollRun(ctx context.Context, runID string) (int64, error)
+
+	// GetStorageStats returns the snapshot table size and per-symbol row
+	// counts, including rows written since the given time
+	GetStorageStats(ctx context.Context, since time.Time) (*domain.StorageStats, error)
+}
+
+// SlowQueryRecorder receives database statements that exceeded the slow
+// query threshold
+type SlowQueryRecorder interface {
+	// RecordSlowQuery counts a slow execution of the named query
+	RecordSlowQuery(query string)
+}
+
+// AggregateRepository defines the contract for analytic queries over a
+// symbol's snapshots in the window [from, to). Windows without snapshots
+// return domain.ErrNoSnapshots.
+type AggregateRepository interface {
+	// MinMaxAvg returns the lowest, highest and average price
+	MinMaxAvg(ctx context.Context, symbolName string, from, to time.Time) (*domain.PriceStats, error)
+
+	// Candles buckets snapshots into OHLC candles of the given interval,
+	// aligned to the Unix epoch. Intervals without snapshots are omitted.
+	Candles(ctx context.Context, symbolName string, from, to time.Time, interval time.Duration) ([]*domain.Candle, error)
+
+	// Change returns the first and last price and the change between them
+	Change(ctx context.Context, symbolName string, from, to time.Time) (*domain.PriceChange, error)
+
+	// Percentiles returns the snapshot prices at the given percentiles
+	// (fractions in [0, 1]), in the order requested
+	Percentiles(ctx context.Context, symbolName string, from, to time.Time, percentiles []float64) ([]domain.PricePercentile, error)
+}
+
+// SnapshotExporter defines the contract for streaming snapshots out in bulk
+type SnapshotExporter interface {
+	// ExportCSV writes a symbol's snapshots in the window [from, to) to w as
+	// CSV with a header row, oldest first, and returns the number of rows
+	ExportCSV(ctx context.Context, symbolName string, from, to time.Time, w io.Writer) (int64, error)
+}
+
+// TickerStatsRepository defines the contract for 24h ticker stats persistence
+type TickerStatsRepository interface {
+	// CreateBatch stores multiple ticker stats atomically
+	CreateBatch(ctx context.Context, stats []*domain.TickerStats) error
+
+	// GetLatestBySymbol returns the most recent ticker stats for a symbol
+	GetLatestBySymbol(ctx context.Context, symbolName string) (*domain.TickerStats, error)
+
+	// Prune removes ticker stats older than the given time
+	Prune(ctx context.Context, olderThan time.Time) (int64, error)
+}
+
+// CandleRepository defines the contract for aggregated candle persistence
+type CandleRepository interface {
+	// Aggregate rebuilds every symbol's candles of interval opening in
+	// [from, to), from snapshots for the finest interval and from the
+	// candles of interval.Source() for the others. It returns how many
+	// candles were written.
+	Aggregate(ctx context.Context, interval domain.CandleInterval, from, to time.Time) (int64, error)
+
+	// List returns a symbol's candles of interval opening in [from, to),
+	// oldest first
+	List(ctx context.Context, symbolName string, interval domain.CandleInterval, from, to time.Time, limit int) ([]*domain.Candle, error)
+}
+
+// JobRepository defines the contract for job queue persistence
+type JobRepository interface {
+	// Create enqueues a new job
+	Create(ctx context.Context, job *domain.Job) error
+
+	// GetByID retrieves a job by its ID
+	GetByID(ctx context.Context, id int64) (*domain.Job, error)
+
+	// List returns the most recent jobs, optionally filtered by status
+	List(ctx context.Context, status domain.JobStatus, limit int) ([]*domain.Job, error)
+
+	// ClaimNext atomically marks the next due pending job as running. A
+	// running job without a heartbeat for staleAfter, whose worker died,
+	// is claimed again.
+	ClaimNext(ctx context.Context, staleAfter time.Duration) (*domain.Job, error)
+
+	// Heartbeat records that the worker running a job's attempt is still
+	// alive. Returns ErrJobCancelled when the job was cancelled and
+	// ErrJobLeaseLost when it is no longer running that attempt.
+	Heartbeat(ctx context.Context, id int64, attempt int) error
+
+	// UpdateProgress stores the progress of a running job's attempt, with
+	// the errors of Heartbeat
+	UpdateProgress(ctx context.Context, id int64, attempt int, progress domain.JobProgress) error
+
+	// Finish stores the final status, error and schedule of the job's
+	// attempt in job.Attempts. It does not overwrite a job that was
+	// cancelled meanwhile, and returns ErrJobLeaseLost when another worker
+	// has claimed it since.
+	Finish(ctx context.Context, job *domain.Job) error
+
+	// Cancel marks a pending or running job as cancelled
+	Cancel(ctx context.Context, id int64) error
+
+	// Requeue makes a cancelled or failed job pending again, keeping its progress
+	Requeue(ctx context.Context, id int64) error
+}
+
+// AlertRepository defines the contract for alert persistence
+type AlertRepository interface {
+	// Create stores a new alert on a tracked symbol, assigning its ID.
+	// Returns ErrSymbolNotFound when the symbol is not tracked.
+	Create(ctx context.Context, alert *domain.Alert) error
+
+	// GetByID retrieves an alert by its ID
+	GetByID(ctx context.Context, id int64) (*domain.Alert, error)
+
+	// List returns every alert, oldest first
+	List(ctx context.Context) ([]*domain.Alert, error)
+
+	// Delete removes an alert
+	Delete(ctx context.Context, id int64) error
+
+	// RecordFiring counts a firing and stores it as the alert's latest, with
+	// its delivery pending
+	RecordFiring(ctx context.Context, id int64, firing domain.AlertFiring) error
+
+	// RecordDelivery stores the state of the latest firing's delivery
+	RecordDelivery(ctx context.Context, id int64, delivery domain.AlertDelivery, deliveryErr string) error
+}
+
+// MigrationStatusProvider reports the database schema migration state
+type MigrationStatusProvider interface {
+	// MigrationStatus returns the applied version against the latest known one
+	MigrationStatus(ctx context.Context) (*domain.MigrationStatus, error)
+}
+
+// FailoverMonitor reports whether the database is failing over to a new
+// primary
+type FailoverMonitor interface {
+	// Failover returns the failover in progress, or nil
+	Failover() *domain.DatabaseFailover
+}
+
+// PoolStatsProvider reports database connection pool usage
+type PoolStatsProvider interface {
+	// PoolStats returns the current pool statistics
+	PoolStats() *domain.DatabasePoolStats
+}
+
+// SnapshotTableAnalyzer refreshes the planner statistics of the snapshot
+// tables
+type SnapshotTableAnalyzer interface {
+	// AnalyzeSnapshots runs ANALYZE on the snapshot tables and returns
+	// their statistics from before it ran
+	AnalyzeSnapshots(ctx context.Context) ([]domain.TableStats, error)
+}
+
+// RateLimitRepository defines the contract for shared rate-limit counters
+type RateLimitRepository interface {
+	// Increment counts a request against key in the window starting at
+	// windowStart and returns the window's count. Earlier windows of the key
+	// are dropped.
+	Increment(ctx context.Context, key string, windowStart time.Time) (int, error)
+
+	// DeleteBefore removes counters of windows that started before the cutoff
+	DeleteBefore(ctx context.Context, cutoff time.Time) (int64, error)
+}
+
+// UsageRepository defines the contract for daily API usage counts
+type UsageRepository interface {
+	// Add adds counts to the stored counts of the same day, key and endpoint
+	Add(ctx context.Context, counts []domain.UsageCount) error
+
+	// List returns the counts of the days from through to, ordered by
+	// day, key and endpoint
+	List(ctx context.Context, from, to time.Time) ([]domain.UsageCount, error)
 }
@@ -0,0 +1,34 @@
+// Package repotest is a conformance suite for storage backends. It runs the
+// same scenarios against a backend's SymbolRepository and
+// SnapshotRepository so that every backend gives the services the same
+// semantics: latest-snapshot lookups, time range and prune boundaries,
+// batch atomicity and symbol lifecycle.
+//
+// A backend is conformant once its tests pass Run:
+//
+//	func TestConformance(t *testing.T) {
+//		repotest.Run(t, func(t *testing.T) repotest.Repositories {
+//			store := newEmptyStore(t)
+//			return repotest.Repositories{
+//				Symbols:   NewSymbolRepository(store),
+//				Snapshots: NewSnapshotRepository(store),
+//			}
+//		})
+//	}
+package repotest
+
+import (
+	"testing"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+)
+
+// Repositories are the repositories of one backend, sharing a store
+type Repositories struct {
+	Symbols   ports.SymbolRepository
+	Snapshots ports.SnapshotRepository
+}
+
+// Factory returns repositories on an empty store. Each call must start from
+// an empty store; anything to tear down is registered with t.Cleanup.
+type Factory func(t *testing.T) Repositories
@@ -0,0 +1,633 @@
+package repotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+)
+
+// base is the time scenarios are laid out from. It has no sub-second part,
+// since backends may store timestamps at microsecond precision.
+var base = time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+// fixture seeds one backend's store
+type fixture struct {
+	t     *testing.T
+	ctx   context.Context
+	repos Repositories
+}
+
+// symbol creates a tracked symbol
+func (f *fixture) symbol(name string, active bool, tags ...string) *domain.Symbol {
+	f.t.Helper()
+	s, err := domain.NewSymbol(name)
+	require.NoError(f.t, err)
+	s.Active = active
+	s.Tags = tags
+	require.NoError(f.t, f.repos.Symbols.Create(f.ctx, s))
+	return s
+}
+
+// snapshot builds an unsaved snapshot of symbol at base plus offset
+func snapshot(s *domain.Symbol, price string, offset time.Duration) *domain.PriceSnapshot {
+	return &domain.PriceSnapshot{
+		SymbolID:        s.ID,
+		Symbol:          s.Name,
+		Price:           decimal.RequireFromString(price),
+		Timestamp:       base.Add(offset),
+		TimestampSource: domain.TimestampSourceExchange,
+	}
+}
+
+// store saves snapshots one by one
+func (f *fixture) store(snapshots ...*domain.PriceSnapshot) {
+	f.t.Helper()
+	for _, s := range snapshots {
+		require.NoError(f.t, f.repos.Snapshots.Create(f.ctx, s))
+	}
+}
+
+// prices returns the prices of snapshots in order
+func prices(snapshots []*domain.PriceSnapshot) []string {
+	result := make([]string, len(snapshots))
+	for i, s := range snapshots {
+		result[i] = s.Price.String()
+	}
+	return result
+}
+
+// bySymbol indexes snapshots by symbol, failing on duplicates
+func bySymbol(t *testing.T, snapshots []*domain.PriceSnapshot) map[string]*domain.PriceSnapshot {
+	t.Helper()
+	result := make(map[string]*domain.PriceSnapshot, len(snapshots))
+	for _, s := range snapshots {
+		require.NotContains(t, result, s.Symbol, "one snapshot per symbol")
+		result[s.Symbol] = s
+	}
+	return result
+}
+
+// Run checks that the repositories built by newRepos conform to the
+// ports.SymbolRepository and ports.SnapshotRepository contracts
+func Run(t *testing.T, newRepos Factory) {
+	t.Helper()
+
+	setup := func(t *testing.T) *fixture {
+		return &fixture{t: t, ctx: context.Background(), repos: newRepos(t)}
+	}
+
+	t.Run("symbols", func(t *testing.T) {
+		testSymbols(t, setup)
+	})
+	t.Run("latest", func(t *testing.T) {
+		testLatest(t, setup)
+	})
+	t.Run("history", func(t *testing.T) {
+		testHistory(t, setup)
+	})
+	t.Run("prune", func(t *testing.T) {
+		testPrune(t, setup)
+	})
+	t.Run("batches", func(t *testing.T) {
+		testBatches(t, setup)
+	})
+}
+
+func testSymbols(t *testing.T, setup func(*testing.T) *fixture) {
+	t.Run("create assigns an ID and reads back", func(t *testing.T) {
+		f := setup(t)
+		btc := f.symbol("BTCUSDT", true, "layer1")
+		require.NotZero(t, btc.ID)
+
+		got, err := f.repos.Symbols.GetByName(f.ctx, "BTCUSDT")
+		require.NoError(t, err)
+		assert.Equal(t, btc.ID, got.ID)
+		assert.True(t, got.Active)
+		assert.Equal(t, []string{"layer1"}, got.Tags)
+
+		got, err = f.repos.Symbols.GetByID(f.ctx, btc.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "BTCUSDT", got.Name)
+
+		exists, err := f.repos.Symbols.Exists(f.ctx, "BTCUSDT")
+		require.NoError(t, err)
+		assert.True(t, exists)
+	})
+
+	t.Run("missing symbols are ErrSymbolNotFound", func(t *testing.T) {
+		f := setup(t)
+
+		_, err := f.repos.Symbols.GetByName(f.ctx, "NOPEUSDT")
+		assert.ErrorIs(t, err, domain.ErrSymbolNotFound)
+
+		_, err = f.repos.Symbols.GetByID(f.ctx, 999999)
+		assert.ErrorIs(t, err, domain.ErrSymbolNotFound)
+
+		assert.ErrorIs(t, f.repos.Symbols.Delete(f.ctx, "NOPEUSDT"), domain.ErrSymbolNotFound)
+
+		ghost, err := domain.NewSymbol("NOPEUSDT")
+		require.NoError(t, err)
+		ghost.ID = 999999
+		assert.ErrorIs(t, f.repos.Symbols.Update(f.ctx, ghost), domain.ErrSymbolNotFound)
+
+		exists, err := f.repos.Symbols.Exists(f.ctx, "NOPEUSDT")
+		require.NoError(t, err)
+		assert.False(t, exists)
+	})
+
+	t.Run("names are unique", func(t *testing.T) {
+		f := setup(t)
+		f.symbol("BTCUSDT", true)
+
+		dup, err := domain.NewSymbol("BTCUSDT")
+		require.NoError(t, err)
+		assert.Error(t, f.repos.Symbols.Create(f.ctx, dup))
+
+		count, err := f.repos.Symbols.Count(f.ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+	})
+
+	t.Run("lists are ordered by name and filtered", func(t *testing.T) {
+		f := setup(t)
+		f.symbol("SOLUSDT", true, "layer1")
+		f.symbol("BTCUSDT", true, "layer1")
+		f.symbol("USDCUSDT", false, "stablecoin")
+
+		names := func(symbols []*domain.Symbol, err error) []string {
+			t.Helper()
+			require.NoError(t, err)
+			result := make([]string, len(symbols))
+			for i, s := range symbols {
+				result[i] = s.Name
+			}
+			return result
+		}
+
+		assert.Equal(t, []string{"BTCUSDT", "SOLUSDT", "USDCUSDT"}, names(f.repos.Symbols.List(f.ctx)))
+		assert.Equal(t, []string{"BTCUSDT", "SOLUSDT"}, names(f.repos.Symbols.ListActive(f.ctx)))
+		assert.Equal(t, []string{"USDCUSDT"}, names(f.repos.Symbols.ListByTag(f.ctx, "stablecoin")))
+		assert.Equal(t, []string{"BTCUSDT", "SOLUSDT"}, names(f.repos.Symbols.ListPage(f.ctx, "", nil, 2)))
+		assert.Equal(t, []string{"USDCUSDT"}, names(f.repos.Symbols.ListPage(f.ctx, "", &domain.SymbolCursor{Name: "SOLUSDT"}, 2)))
+		assert.Equal(t, []string{"BTCUSDT", "SOLUSDT"}, names(f.repos.Symbols.ListPage(f.ctx, "layer1", nil, 5)))
+
+		count, err := f.repos.Symbols.CountActive(f.ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+	})
+
+	t.Run("update persists changes", func(t *testing.T) {
+		f := setup(t)
+		btc := f.symbol("BTCUSDT", true)
+
+		btc.Active = false
+		btc.Exchange = "binanceus"
+		btc.Tags = []string{"paused"}
+		btc.UpdatedAt = base
+		require.NoError(t, f.repos.Symbols.Update(f.ctx, btc))
+
+		got, err := f.repos.Symbols.GetByName(f.ctx, "BTCUSDT")
+		require.NoError(t, err)
+		assert.False(t, got.Active)
+		assert.Equal(t, "binanceus", got.Exchange)
+		assert.Equal(t, []string{"paused"}, got.Tags)
+	})
+
+	t.Run("set active changes only symbols in another state", func(t *testing.T) {
+		f := setup(t)
+		f.symbol("BTCUSDT", true)
+		f.symbol("ETHUSDT", false)
+		f.symbol("SOLUSDT", true)
+
+		changed, err := f.repos.Symbols.SetActive(f.ctx, []string{"BTCUSDT", "ETHUSDT"}, false)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"BTCUSDT"}, changed)
+
+		active, err := f.repos.Symbols.ListActive(f.ctx)
+		require.NoError(t, err)
+		require.Len(t, active, 1)
+		assert.Equal(t, "SOLUSDT", active[0].Name)
+	})
+
+	t.Run("set active is all or nothing", func(t *testing.T) {
+		f := setup(t)
+		f.symbol("BTCUSDT", true)
+		f.symbol("ETHUSDT", true)
+		require.NoError(t, f.repos.Symbols.SoftDelete(f.ctx, "ETHUSDT"))
+
+		_, err := f.repos.Symbols.SetActive(f.ctx, []string{"BTCUSDT", "ETHUSDT"}, false)
+		assert.ErrorIs(t, err, domain.ErrSymbolNotFound)
+		_, err = f.repos.Symbols.SetActive(f.ctx, []string{"BTCUSDT", "NOPEUSDT"}, false)
+		assert.ErrorIs(t, err, domain.ErrSymbolNotFound)
+
+		got, err := f.repos.Symbols.GetByName(f.ctx, "BTCUSDT")
+		require.NoError(t, err)
+		assert.True(t, got.Active)
+	})
+
+	t.Run("delete removes the symbol's snapshots", func(t *testing.T) {
+		f := setup(t)
+		btc := f.symbol("BTCUSDT", true)
+		eth := f.symbol("ETHUSDT", true)
+		f.store(snapshot(btc, "43000", 0), snapshot(eth, "2300", 0))
+
+		require.NoError(t, f.repos.Symbols.Delete(f.ctx, "BTCUSDT"))
+
+		_, err := f.repos.Snapshots.GetLatestBySymbol(f.ctx, "BTCUSDT")
+		assert.ErrorIs(t, err, domain.ErrSnapshotNotFound)
+
+		count, err := f.repos.Snapshots.Count(f.ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+	})
+
+	t.Run("soft delete hides the symbol but keeps its snapshots", func(t *testing.T) {
+		f := setup(t)
+		btc := f.symbol("BTCUSDT", true)
+		f.symbol("ETHUSDT", true)
+		f.store(snapshot(btc, "43000", 0))
+
+		require.NoError(t, f.repos.Symbols.SoftDelete(f.ctx, "BTCUSDT"))
+		assert.ErrorIs(t, f.repos.Symbols.SoftDelete(f.ctx, "BTCUSDT"), domain.ErrSymbolNotFound)
+
+		_, err := f.repos.Symbols.GetByName(f.ctx, "BTCUSDT")
+		assert.ErrorIs(t, err, domain.ErrSymbolNotFound)
+		exists, err := f.repos.Symbols.Exists(f.ctx, "BTCUSDT")
+		require.NoError(t, err)
+		assert.False(t, exists)
+		exists, err = f.repos.Symbols.ExistsIncludingDeleted(f.ctx, "BTCUSDT")
+		require.NoError(t, err)
+		assert.True(t, exists)
+
+		active, err := f.repos.Symbols.ListActive(f.ctx)
+		require.NoError(t, err)
+		require.Len(t, active, 1)
+		assert.Equal(t, "ETHUSDT", active[0].Name)
+		count, err := f.repos.Symbols.Count(f.ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+
+		all, err := f.repos.Symbols.ListIncludingDeleted(f.ctx)
+		require.NoError(t, err)
+		require.Len(t, all, 2)
+		assert.Equal(t, "BTCUSDT", all[0].Name)
+		assert.NotNil(t, all[0].DeletedAt)
+		assert.False(t, all[0].Active)
+
+		latest, err := f.repos.Snapshots.GetLatestBySymbol(f.ctx, "BTCUSDT")
+		require.NoError(t, err)
+		assert.Equal(t, "43000", latest.Price.String())
+	})
+
+	t.Run("creating a soft deleted symbol revives it", func(t *testing.T) {
+		f := setup(t)
+		btc := f.symbol("BTCUSDT", true)
+		require.NoError(t, f.repos.Symbols.SoftDelete(f.ctx, "BTCUSDT"))
+
+		again := f.symbol("BTCUSDT", true, "layer1")
+		assert.Equal(t, btc.ID, again.ID)
+
+		got, err := f.repos.Symbols.GetByName(f.ctx, "BTCUSDT")
+		require.NoError(t, err)
+		assert.True(t, got.Active)
+		assert.Nil(t, got.DeletedAt)
+		assert.Equal(t, []string{"layer1"}, got.Tags)
+	})
+
+	t.Run("delete purges a soft deleted symbol", func(t *testing.T) {
+		f := setup(t)
+		btc := f.symbol("BTCUSDT", true)
+		f.store(snapshot(btc, "43000", 0))
+		require.NoError(t, f.repos.Symbols.SoftDelete(f.ctx, "BTCUSDT"))
+
+		require.NoError(t, f.repos.Symbols.Delete(f.ctx, "BTCUSDT"))
+
+		exists, err := f.repos.Symbols.ExistsIncludingDeleted(f.ctx, "BTCUSDT")
+		require.NoError(t, err)
+		assert.False(t, exists)
+		_, err = f.repos.Snapshots.GetLatestBySymbol(f.ctx, "BTCUSDT")
+		assert.ErrorIs(t, err, domain.ErrSnapshotNotFound)
+	})
+}
+
+func testLatest(t *testing.T, setup func(*testing.T) *fixture) {
+	t.Run("latest is the newest by timestamp, not by insertion", func(t *testing.T) {
+		f := setup(t)
+		btc := f.symbol("BTCUSDT", true)
+		f.store(
+			snapshot(btc, "43000", 2*time.Minute),
+			snapshot(btc, "42000", 0),
+			snapshot(btc, "42500", time.Minute),
+		)
+
+		latest, err := f.repos.Snapshots.GetLatestBySymbol(f.ctx, "BTCUSDT")
+		require.NoError(t, err)
+		assert.Equal(t, "43000", latest.Price.String())
+		assert.True(t, latest.Timestamp.Equal(base.Add(2*time.Minute)), "got %s", latest.Timestamp)
+		assert.Equal(t, btc.ID, latest.SymbolID)
+	})
+
+	t.Run("latest of a symbol without snapshots is ErrSnapshotNotFound", func(t *testing.T) {
+		f := setup(t)
+		f.symbol("BTCUSDT", true)
+
+		_, err := f.repos.Snapshots.GetLatestBySymbol(f.ctx, "BTCUSDT")
+		assert.ErrorIs(t, err, domain.ErrSnapshotNotFound)
+	})
+
+	t.Run("latest by symbols returns one per symbol with snapshots", func(t *testing.T) {
+		f := setup(t)
+		btc := f.symbol("BTCUSDT", true)
+		eth := f.symbol("ETHUSDT", true)
+		f.symbol("SOLUSDT", true)
+		f.store(
+			snapshot(btc, "42000", 0),
+			snapshot(eth, "2350.5", 3*time.Minute),
+			snapshot(btc, "43000", time.Minute),
+			snapshot(eth, "2300", time.Minute),
+		)
+
+		latest, err := f.repos.Snapshots.GetLatestBySymbols(f.ctx, []string{"BTCUSDT", "ETHUSDT", "SOLUSDT", "NOPEUSDT"})
+		require.NoError(t, err)
+
+		got := bySymbol(t, latest)
+		require.Len(t, got, 2)
+		assert.Equal(t, "43000", got["BTCUSDT"].Price.String())
+		assert.Equal(t, "2350.5", got["ETHUSDT"].Price.String())
+
+		latest, err = f.repos.Snapshots.GetLatestBySymbols(f.ctx, nil)
+		require.NoError(t, err)
+		assert.Empty(t, latest)
+	})
+
+	t.Run("latest keeps the exchange it was polled from", func(t *testing.T) {
+		f := setup(t)
+		btc := f.symbol("BTCUSDT", true)
+		polled := snapshot(btc, "43000", 0)
+		polled.Exchange = "binance"
+		f.store(polled)
+
+		latest, err := f.repos.Snapshots.GetLatestBySymbol(f.ctx, "BTCUSDT")
+		require.NoError(t, err)
+		assert.Equal(t, "binance", latest.Exchange)
+	})
+
+	t.Run("prices keep their precision", func(t *testing.T) {
+		f := setup(t)
+		shib := f.symbol("SHIBUSDT", true)
+		btc := f.symbol("BTCUSDT", true)
+		f.store(snapshot(shib, "0.00000912", 0), snapshot(btc, "1234567890.12345678", 0))
+
+		latest, err := f.repos.Snapshots.GetLatestBySymbols(f.ctx, []string{"SHIBUSDT", "BTCUSDT"})
+		require.NoError(t, err)
+
+		got := bySymbol(t, latest)
+		assert.True(t, got["SHIBUSDT"].Price.Equal(decimal.RequireFromString("0.00000912")), "got %s", got["SHIBUSDT"].Price)
+		assert.True(t, got["BTCUSDT"].Price.Equal(decimal.RequireFromString("1234567890.12345678")), "got %s", got["BTCUSDT"].Price)
+	})
+
+	t.Run("previous by symbols skips symbols with one snapshot", func(t *testing.T) {
+		f := setup(t)
+		btc := f.symbol("BTCUSDT", true)
+		eth := f.symbol("ETHUSDT", true)
+		f.store(
+			snapshot(btc, "43000", 2*time.Minute),
+			snapshot(btc, "42000", 0),
+			snapshot(btc, "42500", time.Minute),
+			snapshot(eth, "2300", 0),
+		)
+
+		previous, err := f.repos.Snapshots.GetPreviousBySymbols(f.ctx, []string{"BTCUSDT", "ETHUSDT"})
+		require.NoError(t, err)
+
+		got := bySymbol(t, previous)
+		require.Len(t, got, 1)
+		assert.Equal(t, "42500", got["BTCUSDT"].Price.String())
+	})
+}
+
+func testHistory(t *testing.T, setup func(*testing.T) *fixture) {
+	seed := func(t *testing.T) *fixture {
+		f := setup(t)
+		btc := f.symbol("BTCUSDT", true)
+		eth := f.symbol("ETHUSDT", true)
+		for i, price := range []string{"100", "101", "102", "103", "104"} {
+			f.store(snapshot(btc, price, time.Duration(i)*time.Minute))
+		}
+		f.store(snapshot(eth, "2300", time.Minute))
+		return f
+	}
+
+	t.Run("history is newest first and limited", func(t *testing.T) {
+		f := seed(t)
+
+		history, err := f.repos.Snapshots.GetHistory(f.ctx, "BTCUSDT", nil, 3)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"104", "103", "102"}, prices(history))
+	})
+
+	t.Run("history between includes both ends", func(t *testing.T) {
+		f := seed(t)
+
+		history, err := f.repos.Snapshots.GetHistoryBetween(f.ctx, "BTCUSDT", base.Add(time.Minute), base.Add(3*time.Minute), nil, 100)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"103", "102", "101"}, prices(history))
+	})
+
+	t.Run("history continues after a cursor", func(t *testing.T) {
+		f := seed(t)
+
+		first, err := f.repos.Snapshots.GetHistory(f.ctx, "BTCUSDT", nil, 2)
+		require.NoError(t, err)
+		require.Equal(t, []string{"104", "103"}, prices(first))
+
+		cursor := domain.HistoryCursorAt(first[1])
+		next, err := f.repos.Snapshots.GetHistory(f.ctx, "BTCUSDT", &cursor, 2)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"102", "101"}, prices(next))
+
+		between, err := f.repos.Snapshots.GetHistoryBetween(f.ctx, "BTCUSDT", base, base.Add(4*time.Minute), &cursor, 100)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"102", "101", "100"}, prices(between))
+	})
+
+	t.Run("summary spans the stored history", func(t *testing.T) {
+		f := seed(t)
+
+		summary, err := f.repos.Snapshots.GetHistorySummary(f.ctx, "BTCUSDT")
+		require.NoError(t, err)
+		assert.Equal(t, int64(5), summary.TotalCount)
+		require.NotNil(t, summary.Oldest)
+		require.NotNil(t, summary.Newest)
+		assert.True(t, base.Equal(*summary.Oldest), "got %s", summary.Oldest)
+		assert.True(t, base.Add(4*time.Minute).Equal(*summary.Newest), "got %s", summary.Newest)
+	})
+
+	t.Run("summary of a symbol without snapshots is empty", func(t *testing.T) {
+		f := seed(t)
+		f.symbol("SOLUSDT", true)
+
+		summary, err := f.repos.Snapshots.GetHistorySummary(f.ctx, "SOLUSDT")
+		require.NoError(t, err)
+		assert.Zero(t, summary.TotalCount)
+		assert.Nil(t, summary.Oldest)
+		assert.Nil(t, summary.Newest)
+	})
+
+	t.Run("each between is oldest first and excludes the end", func(t *testing.T) {
+		f := seed(t)
+
+		var seen []*domain.PriceSnapshot
+		err := f.repos.Snapshots.EachBetween(f.ctx, "BTCUSDT", base.Add(time.Minute), base.Add(3*time.Minute), func(s *domain.PriceSnapshot) error {
+			seen = append(seen, s)
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"101", "102"}, prices(seen))
+	})
+
+	t.Run("counts are per symbol", func(t *testing.T) {
+		f := seed(t)
+
+		count, err := f.repos.Snapshots.CountBySymbol(f.ctx, "BTCUSDT")
+		require.NoError(t, err)
+		assert.Equal(t, int64(5), count)
+
+		count, err = f.repos.Snapshots.Count(f.ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(6), count)
+
+		counts, err := f.repos.Snapshots.CountPerSymbol(f.ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []domain.SymbolSnapshotCount{
+			{Symbol: "BTCUSDT", Count: 5},
+			{Symbol: "ETHUSDT", Count: 1},
+		}, counts)
+	})
+}
+
+func testPrune(t *testing.T, setup func(*testing.T) *fixture) {
+	seed := func(t *testing.T) *fixture {
+		f := setup(t)
+		btc := f.symbol("BTCUSDT", true)
+		eth := f.symbol("ETHUSDT", true)
+		for _, s := range []*domain.Symbol{btc, eth} {
+			f.store(
+				snapshot(s, "1", -time.Second),
+				snapshot(s, "2", 0),
+				snapshot(s, "3", time.Second),
+			)
+		}
+		return f
+	}
+
+	t.Run("prune deletes only snapshots strictly older than the cutoff", func(t *testing.T) {
+		f := seed(t)
+
+		deleted, err := f.repos.Snapshots.Prune(f.ctx, base)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), deleted)
+
+		history, err := f.repos.Snapshots.GetHistory(f.ctx, "BTCUSDT", nil, 10)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"3", "2"}, prices(history))
+
+		deleted, err = f.repos.Snapshots.Prune(f.ctx, base)
+		require.NoError(t, err)
+		assert.Zero(t, deleted, "pruning again deletes nothing")
+	})
+
+	t.Run("prune by symbol leaves other symbols alone", func(t *testing.T) {
+		f := seed(t)
+
+		deleted, err := f.repos.Snapshots.PruneBySymbol(f.ctx, "BTCUSDT", base.Add(time.Second))
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), deleted)
+
+		count, err := f.repos.Snapshots.CountBySymbol(f.ctx, "BTCUSDT")
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+
+		count, err = f.repos.Snapshots.CountBySymbol(f.ctx, "ETHUSDT")
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), count)
+	})
+}
+
+func testBatches(t *testing.T, setup func(*testing.T) *fixture) {
+	t.Run("batch stores every snapshot", func(t *testing.T) {
+		f := setup(t)
+		btc := f.symbol("BTCUSDT", true)
+		eth := f.symbol("ETHUSDT", true)
+
+		batch := []*domain.PriceSnapshot{snapshot(btc, "43000", 0), snapshot(eth, "2300", 0)}
+		require.NoError(t, f.repos.Snapshots.CreateBatch(f.ctx, batch))
+		for _, s := range batch {
+			assert.NotZero(t, s.ID, "%s: batch assigns IDs", s.Symbol)
+		}
+
+		count, err := f.repos.Snapshots.Count(f.ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), count)
+	})
+
+	t.Run("batch is all or nothing", func(t *testing.T) {
+		f := setup(t)
+		btc := f.symbol("BTCUSDT", true)
+
+		// The second snapshot belongs to a symbol that is not tracked
+		orphan := snapshot(&domain.Symbol{ID: btc.ID + 1000, Name: "NOPEUSDT"}, "1", 0)
+		err := f.repos.Snapshots.CreateBatch(f.ctx, []*domain.PriceSnapshot{snapshot(btc, "43000", 0), orphan})
+		require.Error(t, err)
+
+		count, err := f.repos.Snapshots.Count(f.ctx)
+		require.NoError(t, err)
+		assert.Zero(t, count, "a failed batch stores nothing")
+	})
+
+	t.Run("retried poll batch is not stored twice", func(t *testing.T) {
+		f := setup(t)
+		btc := f.symbol("BTCUSDT", true)
+
+		batch := func() []*domain.PriceSnapshot {
+			s := snapshot(btc, "43000", 0)
+			s.TimestampSource = domain.TimestampSourcePoll
+			s.PollRunID = "run-1"
+			return []*domain.PriceSnapshot{s}
+		}
+		require.NoError(t, f.repos.Snapshots.CreateBatch(f.ctx, batch()))
+		require.NoError(t, f.repos.Snapshots.CreateBatch(f.ctx, batch()))
+
+		count, err := f.repos.Snapshots.Count(f.ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+	})
+
+	t.Run("stores ingest provenance", func(t *testing.T) {
+		f := setup(t)
+		btc := f.symbol("BTCUSDT", true)
+
+		latency := int64(120)
+		s := snapshot(btc, "43000", 0)
+		s.IngestMode = domain.IngestModeStream
+		s.IngestLatencyMs = &latency
+		s.PipelineVersion = "1.4.0"
+		require.NoError(t, f.repos.Snapshots.CreateBatch(f.ctx, []*domain.PriceSnapshot{s}))
+
+		latest, err := f.repos.Snapshots.GetLatestBySymbol(f.ctx, "BTCUSDT")
+		require.NoError(t, err)
+		assert.Equal(t, s.ID, latest.ID)
+	})
+
+	t.Run("empty batch is a no-op", func(t *testing.T) {
+		f := setup(t)
+
+		require.NoError(t, f.repos.Snapshots.CreateBatch(f.ctx, nil))
+	})
+}
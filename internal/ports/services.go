@@ -2,27 +2,60 @@ package ports
 
 import (
 	"context"
+	"encoding/json"
 	"time"
 
+	"github.com/shopspring/decimal"
+
 	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
 )
 
 // SymbolService defines the contract for symbol management
 type SymbolService interface {
-	// AddSymbol adds a new symbol to track
-	AddSymbol(ctx context.Context, name string) (*domain.Symbol, error)
+	// AddSymbol adds a new symbol to track on the named exchange, or on the
+	// default exchange when exchange is empty, optionally with tags
+	AddSymbol(ctx context.Context, name, exchange string, tags ...string) (*domain.Symbol, error)
 
-	// RemoveSymbol stops tracking a symbol
+	// RemoveSymbol stops tracking a symbol, keeping its snapshots
 	RemoveSymbol(ctx context.Context, name string) error
 
+	// PurgeSymbol deletes a symbol, tracked or removed, and its snapshots
+	PurgeSymbol(ctx context.Context, name string) error
+
 	// ListSymbols returns all tracked symbols
 	ListSymbols(ctx context.Context) ([]*domain.Symbol, error)
 
+	// ListSymbolsByTag returns tracked symbols carrying the given tag
+	ListSymbolsByTag(ctx context.Context, tag string) ([]*domain.Symbol, error)
+
+	// ListSymbolsPage returns up to limit tracked symbols ordered by name,
+	// continuing after after when it is set, restricted to those carrying
+	// tag unless it is empty
+	ListSymbolsPage(ctx context.Context, tag string, after *domain.SymbolCursor, limit int) ([]*domain.Symbol, error)
+
+	// SetTags replaces a symbol's tags
+	SetTags(ctx context.Context, name string, tags []string) (*domain.Symbol, error)
+
+	// RemoveTag removes a single tag from a symbol
+	RemoveTag(ctx context.Context, name, tag string) (*domain.Symbol, error)
+
+	// SetActive resumes or pauses polling of a symbol, keeping its history
+	SetActive(ctx context.Context, name string, active bool) (*domain.Symbol, error)
+
+	// SetActiveBatch resumes or pauses polling of several symbols at once.
+	// The batch is applied to every symbol or, when any of them fails, to
+	// none; the result reports each symbol either way.
+	SetActiveBatch(ctx context.Context, names []string, active bool) (*domain.SymbolBatch, error)
+
 	// GetSymbol retrieves a specific symbol
 	GetSymbol(ctx context.Context, name string) (*domain.Symbol, error)
 
 	// SymbolExists checks if a symbol is being tracked
 	SymbolExists(ctx context.Context, name string) (bool, error)
+
+	// ListEvents returns a symbol's change history, newest first. History
+	// is kept for symbols that are no longer tracked.
+	ListEvents(ctx context.Context, name string, limit int) ([]*domain.SymbolEvent, error)
 }
 
 // SnapshotService defines the contract for price queries
@@ -30,8 +63,87 @@ type SnapshotService interface {
 	// GetLatestPrices returns current prices for specified symbols
 	GetLatestPrices(ctx context.Context, symbols []string) ([]*domain.PriceSnapshot, []string, error)
 
-	// GetPriceHistory returns historical prices for a symbol
-	GetPriceHistory(ctx context.Context, symbol string, limit int) ([]*domain.PriceSnapshot, error)
+	// GetPreviousPrices returns the snapshot before the latest one for the
+	// specified symbols, keyed by symbol
+	GetPreviousPrices(ctx context.Context, symbols []string) (map[string]*domain.PriceSnapshot, error)
+
+	// GetPriceHistory returns historical prices for a symbol, newest first,
+	// continuing after before when it is set
+	GetPriceHistory(ctx context.Context, symbol string, before *domain.HistoryCursor, limit int) ([]*domain.PriceSnapshot, error)
+
+	// GetPriceHistoryBetween returns historical prices for a symbol within
+	// a time range, newest first, continuing after before when it is set
+	GetPriceHistoryBetween(ctx context.Context, symbol string, from, to time.Time, before *domain.HistoryCursor, limit int) ([]*domain.PriceSnapshot, error)
+
+	// GetHistorySummary returns how many snapshots are stored for a
+	// symbol and when the oldest and newest were taken
+	GetHistorySummary(ctx context.Context, symbol string) (*domain.HistorySummary, error)
+
+	// GetStaleSymbols returns the active symbols whose latest snapshot is
+	// older than olderThan, or that have none, ordered by symbol
+	GetStaleSymbols(ctx context.Context, olderThan time.Time) ([]*domain.StaleSymbol, error)
+
+	// DeleteSnapshotRange removes a symbol's snapshots in [from, to), or
+	// with dryRun only reports what would be removed
+	DeleteSnapshotRange(ctx context.Context, symbol string, from, to time.Time, dryRun bool) (*domain.SnapshotRangeDeletion, error)
+
+	// GetPollRun returns the snapshots a poll stored
+	GetPollRun(ctx context.Context, runID string) (*domain.PollRun, error)
+
+	// DeletePollRun removes the snapshots a poll stored and returns how
+	// many were deleted
+	DeletePollRun(ctx context.Context, runID string) (int64, error)
+}
+
+// PrecisionService defines the contract for price display hints
+type PrecisionService interface {
+	// GetPrecisions returns the exchange tick size and display decimals of
+	// the given symbols, omitting symbols without known precision
+	GetPrecisions(ctx context.Context, symbols []string) map[string]*domain.SymbolPrecision
+}
+
+// MetadataService defines the contract for symbol metadata enrichment
+type MetadataService interface {
+	// RefreshMetadata updates the coin metadata of every tracked symbol and
+	// returns how many symbols were enriched
+	RefreshMetadata(ctx context.Context) (int, error)
+}
+
+// SymbolEventFeedService defines the contract for delivering symbol
+// lifecycle events to subscribers
+type SymbolEventFeedService interface {
+	// DeliverEvents sends the events recorded since the last delivery and
+	// returns how many were sent
+	DeliverEvents(ctx context.Context) (int, error)
+}
+
+// IngestService defines the contract for storing snapshots pushed by
+// external sources
+type IngestService interface {
+	// Ingest validates and stores pushed snapshots, skipping any that were
+	// already pushed
+	Ingest(ctx context.Context, snapshots []domain.IngestedSnapshot) (*domain.IngestResult, error)
+}
+
+// RateLimiter defines the contract for API request rate limiting
+type RateLimiter interface {
+	// Allow counts a request against key and reports whether it is within
+	// the limit of the current window
+	Allow(ctx context.Context, key string) (*domain.RateLimitDecision, error)
+}
+
+// FaultInjector decides when to fail API requests during resilience testing
+type FaultInjector interface {
+	// ServerError returns the status of an error to respond with, or 0 to
+	// serve the request normally
+	ServerError() int
+}
+
+// HTTPRequestRecorder receives the latencies of served HTTP requests
+type HTTPRequestRecorder interface {
+	// RecordHTTPRequest records a request matched to route, the pattern it
+	// was routed by, that was answered with code after duration
+	RecordHTTPRequest(route, method string, code int, duration time.Duration)
 }
 
 // MetricsService defines the contract for operational metrics
@@ -39,14 +151,34 @@ type MetricsService interface {
 	// GetMetrics returns current operational metrics
 	GetMetrics(ctx context.Context) (*domain.Metrics, error)
 
-	// RecordPollSuccess records a successful poll
-	RecordPollSuccess(duration time.Duration)
+	// RecordPollSuccess records a successful poll by the named poller group
+	// (empty when groups are not configured)
+	RecordPollSuccess(group string, duration time.Duration)
 
-	// RecordPollError records a failed poll
-	RecordPollError(duration time.Duration)
+	// RecordPollError records a failed poll by the named poller group
+	RecordPollError(group string, duration time.Duration)
+
+	// RecordPollStage records the duration of one poll stage and whether it
+	// ran out of its budget
+	RecordPollStage(stage domain.PollStage, duration time.Duration, timedOut bool)
+
+	// RecordSnapshotWriteFailures records snapshots a poll could not store
+	// while storing the rest
+	RecordSnapshotWriteFailures(failed int)
 
 	// GetLastPollTime returns the time of the last poll
 	GetLastPollTime() *time.Time
+
+	// RecordPrune records snapshots deleted for a symbol during a prune run
+	RecordPrune(symbol string, deleted int64)
+
+	// RecordPollSymbols records the effective symbol list of a poll,
+	// replacing the previous one of the same poller group
+	RecordPollSymbols(run *domain.PollSymbols)
+
+	// GetPollSymbols returns the effective symbol list of each poller
+	// group's most recent poll, ordered by group
+	GetPollSymbols() []*domain.PollSymbols
 }
 
 // PollerService defines the contract for price polling orchestration
@@ -55,13 +187,209 @@ type PollerService interface {
 	PollPrices(ctx context.Context) error
 }
 
+// SymbolPoller defines the contract for polling a single symbol on demand
+type SymbolPoller interface {
+	// PollSymbol fetches a tracked symbol's current price from its exchange
+	// and stores it as a snapshot
+	PollSymbol(ctx context.Context, name string) (*domain.PriceSnapshot, error)
+}
+
+// StreamPollerService defines the contract for polling in stream mode,
+// where the exchange pushes prices instead of having them fetched
+type StreamPollerService interface {
+	// StreamPrices buffers prices pushed by the exchange until ctx is done
+	StreamPrices(ctx context.Context) error
+
+	// FlushStream stores the prices buffered since the last flush
+	FlushStream(ctx context.Context) error
+}
+
+// SnapshotBuffer defines the contract for a write-ahead buffer that
+// accumulates polled snapshots and writes them in fewer, larger batches
+type SnapshotBuffer interface {
+	// Flush writes the buffered snapshots and returns how many were written
+	Flush(ctx context.Context) (int, error)
+
+	// Full receives when the buffer holds enough snapshots to be flushed
+	// before the next interval
+	Full() <-chan struct{}
+}
+
+// PrunerService defines the contract for snapshot retention
+type PrunerService interface {
+	// PruneExpired removes snapshots older than the retention period
+	PruneExpired(ctx context.Context) (*domain.PruneResult, error)
+}
+
+// MaintenanceService defines the contract for keeping the snapshot tables'
+// planner statistics fresh
+type MaintenanceService interface {
+	// Analyze refreshes the statistics and logs the tables' bloat estimates
+	Analyze(ctx context.Context) ([]domain.TableStats, error)
+
+	// RowsChanged analyzes the tables when an operation described by reason
+	// deleted or inserted enough rows to mislead the planner
+	RowsChanged(ctx context.Context, reason string, rows int64)
+}
+
+// CompressionService defines the contract for compressing old snapshots
+type CompressionService interface {
+	// CompressOld packs snapshots older than the configured age into chunks
+	CompressOld(ctx context.Context) (*domain.CompressionResult, error)
+}
+
+// RetentionService defines the contract for storage growth reporting
+type RetentionService interface {
+	// Report estimates storage growth and forecasts disk threshold breaches
+	Report(ctx context.Context) (*domain.RetentionReport, error)
+}
+
+// UsageRecorder receives the API requests counted in usage statistics
+type UsageRecorder interface {
+	// RecordUsage counts a request made with the API key identified by key
+	// to endpoint, the method and pattern it was routed by
+	RecordUsage(key, endpoint string)
+}
+
+// UsageService defines the contract for long-term API usage statistics
+type UsageService interface {
+	UsageRecorder
+
+	// Flush stores the requests counted since the last flush and returns
+	// how many daily counts it wrote
+	Flush(ctx context.Context) (int, error)
+
+	// GetUsage returns the daily counts of the days from through to
+	GetUsage(ctx context.Context, from, to time.Time) ([]domain.UsageCount, error)
+
+	// Summarize totals the usage of the days from up to, but not
+	// including, to per key and endpoint
+	Summarize(ctx context.Context, from, to time.Time) (*domain.UsageSummary, error)
+}
+
+// JobService defines the contract for the internal job queue
+type JobService interface {
+	// Enqueue schedules a job to run at runAt (immediately when zero)
+	Enqueue(ctx context.Context, jobType domain.JobType, payload json.RawMessage, runAt time.Time) (*domain.Job, error)
+
+	// GetJob retrieves a job by ID
+	GetJob(ctx context.Context, id int64) (*domain.Job, error)
+
+	// ListJobs returns recent jobs, optionally filtered by status
+	ListJobs(ctx context.Context, status domain.JobStatus, limit int) ([]*domain.Job, error)
+
+	// CancelJob cancels a pending or running job, keeping its progress
+	CancelJob(ctx context.Context, id int64) (*domain.Job, error)
+
+	// ResumeJob requeues a cancelled or failed job from its recorded progress
+	ResumeJob(ctx context.Context, id int64) (*domain.Job, error)
+}
+
+// JobExecutor defines the contract for running queued jobs
+type JobExecutor interface {
+	// RunNext claims and executes the next due job, reporting whether one ran
+	RunNext(ctx context.Context) (bool, error)
+}
+
+// CandleService defines the contract for OHLC candles aggregated from
+// snapshots
+type CandleService interface {
+	// GetCandles returns a tracked symbol's candles of interval opening in
+	// [from, to), oldest first, with gaps filled by fill
+	GetCandles(ctx context.Context, symbol string, interval domain.CandleInterval, from, to time.Time, fill domain.FillMode) ([]*domain.Candle, error)
+
+	// Aggregate rebuilds the candles of every interval from the snapshots
+	// stored since the last run
+	Aggregate(ctx context.Context) (*domain.CandleAggregation, error)
+}
+
+// TickerService defines the contract for 24h ticker statistics
+type TickerService interface {
+	// GetTicker24h returns the latest captured 24h stats for a tracked symbol
+	GetTicker24h(ctx context.Context, symbol string) (*domain.TickerStats, error)
+}
+
+// DigestService defines the contract for price movement digests
+type DigestService interface {
+	// BuildDigest computes the top gainers and losers over the digest window
+	BuildDigest(ctx context.Context) (*domain.PriceDigest, error)
+
+	// SendDigest builds a digest and delivers it to all configured channels
+	SendDigest(ctx context.Context) error
+}
+
+// WarmupService defines the contract for startup warm-up and readiness
+type WarmupService interface {
+	// Warmup verifies tracked symbols against the exchange
+	Warmup(ctx context.Context) (*domain.WarmupReport, error)
+
+	// Ready reports whether warm-up finished and latest prices are primed
+	Ready() bool
+
+	// Report returns the last warm-up report, or nil if warm-up has not run
+	Report() *domain.WarmupReport
+}
+
+// AlertBacktestService defines the contract for replaying alert rules over
+// stored prices
+type AlertBacktestService interface {
+	// Backtest reports when the rule would have fired in [from, to)
+	Backtest(ctx context.Context, rule domain.AlertRule, from, to time.Time) (*domain.AlertBacktest, error)
+}
+
+// AlertService defines the contract for alert rules evaluated against
+// live prices
+type AlertService interface {
+	// CreateAlert registers a rule on a tracked symbol whose firings are
+	// posted to webhookURL
+	CreateAlert(ctx context.Context, rule domain.AlertRule, webhookURL string) (*domain.Alert, error)
+
+	// CreateWatch registers a one-shot alert posting to webhookURL the first
+	// time the symbol's price crosses price, either way, within ttl
+	CreateWatch(ctx context.Context, symbol string, price decimal.Decimal, webhookURL string, ttl time.Duration) (*domain.Alert, error)
+
+	// ListAlerts returns every alert with its latest firing and delivery
+	ListAlerts(ctx context.Context) ([]*domain.Alert, error)
+
+	// DeleteAlert removes an alert
+	DeleteAlert(ctx context.Context, id int64) error
+
+	// Evaluate feeds freshly stored snapshots to the rules of their symbols
+	// and queues a webhook call for each firing
+	Evaluate(ctx context.Context, snapshots []*domain.PriceSnapshot)
+}
+
+// ExportService defines the contract for bulk snapshot export
+type ExportService interface {
+	// Export writes one compressed CSV file per bucket and symbol under dir,
+	// skipping files left complete by an earlier run. No symbols means
+	// every tracked symbol.
+	Export(ctx context.Context, dir string, rng domain.ExportRange, symbols []string) (*domain.ExportSummary, error)
+}
+
+// TokenRenewer defines the contract for keeping a secrets store token alive
+type TokenRenewer interface {
+	// RenewToken extends the token's lease and returns its new TTL, zero
+	// when the token never expires
+	RenewToken(ctx context.Context) (time.Duration, error)
+}
+
 // HealthService defines the contract for health checks
 type HealthService interface {
 	// CheckHealth performs health checks on all dependencies
 	CheckHealth(ctx context.Context) (*HealthStatus, error)
 }
 
-// HealthStatus represents the health of the service
+// Health status values. Each dependency is healthy or unhealthy; the
+// service is degraded when any of them is unhealthy.
+const (
+	HealthHealthy   = "healthy"
+	HealthUnhealthy = "unhealthy"
+	HealthDegraded  = "degraded"
+)
+
+// HealthStatus represents the health of the service. Details holds the
+// error of each unhealthy dependency, keyed "database" or "exchange".
 type HealthStatus struct {
 	Status   string            `json:"status"`
 	Database string            `json:"database"`
@@ -0,0 +1,71 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+)
+
+// AlertBacktestService implements the ports.AlertBacktestService interface
+type AlertBacktestService struct {
+	snapshotRepo ports.SnapshotRepository
+	symbolRepo   ports.SymbolRepository
+	logger       *slog.Logger
+}
+
+// NewAlertBacktestService creates a new alert backtest service
+func NewAlertBacktestService(
+	snapshotRepo ports.SnapshotRepository,
+	symbolRepo ports.SymbolRepository,
+	logger *slog.Logger,
+) *AlertBacktestService {
+	return &AlertBacktestService{
+		snapshotRepo: snapshotRepo,
+		symbolRepo:   symbolRepo,
+		logger:       logger.With("component", "alert_backtest_service"),
+	}
+}
+
+// Backtest replays the symbol's stored snapshots in [from, to) through the
+// rule, oldest first
+func (s *AlertBacktestService) Backtest(ctx context.Context, rule domain.AlertRule, from, to time.Time) (*domain.AlertBacktest, error) {
+	rule.Normalize()
+	if err := rule.Validate(); err != nil {
+		return nil, err
+	}
+
+	if !from.Before(to) {
+		return nil, fmt.Errorf("%w: from must be before to", domain.ErrInvalidTimeRange)
+	}
+	if to.Sub(from) > domain.MaxBacktestRange {
+		return nil, fmt.Errorf("%w: backtests cover at most %s", domain.ErrInvalidTimeRange, domain.MaxBacktestRange)
+	}
+
+	exists, err := s.symbolRepo.ExistsIncludingDeleted(ctx, rule.Symbol)
+	if err != nil {
+		s.logger.Error("failed to check symbol existence", "symbol", rule.Symbol, "error", err)
+		return nil, domain.ErrInternal
+	}
+	if !exists {
+		return nil, domain.ErrSymbolNotFound
+	}
+
+	backtest := domain.NewAlertBacktest(rule, from, to)
+	err = s.snapshotRepo.EachBetween(ctx, rule.Symbol, from, to, func(snap *domain.PriceSnapshot) error {
+		backtest.Observe(snap)
+		return nil
+	})
+	if err != nil {
+		s.logger.Error("failed to replay snapshots", "symbol", rule.Symbol, "error", err)
+		return nil, domain.ErrInternal
+	}
+
+	return backtest, nil
+}
+
+// Ensure AlertBacktestService implements ports.AlertBacktestService
+var _ ports.AlertBacktestService = (*AlertBacktestService)(nil)
@@ -0,0 +1,273 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+)
+
+// AlertService implements the ports.AlertService interface. Each alert's
+// rule runs in an evaluator kept in memory, fed with every polled price of
+// its symbol; evaluators start over when the service restarts.
+type AlertService struct {
+	repo   ports.AlertRepository
+	jobs   ports.JobService
+	logger *slog.Logger
+
+	mu sync.Mutex
+	// live holds the evaluator of each alert by symbol, loaded from the
+	// repository on the first evaluation
+	live   map[string][]*liveAlert
+	loaded bool
+}
+
+// liveAlert is an alert with the evaluator of its rule
+type liveAlert struct {
+	alert     *domain.Alert
+	evaluator *domain.AlertEvaluator
+}
+
+// alertFired is a firing waiting to be recorded and delivered
+type alertFired struct {
+	alert  *domain.Alert
+	firing domain.AlertFiring
+}
+
+// NewAlertService creates a new alert service that queues a job on jobs
+// for each webhook call
+func NewAlertService(repo ports.AlertRepository, jobs ports.JobService, logger *slog.Logger) *AlertService {
+	return &AlertService{
+		repo:   repo,
+		jobs:   jobs,
+		logger: logger.With("component", "alert_service"),
+		live:   make(map[string][]*liveAlert),
+	}
+}
+
+// CreateAlert registers a rule on a tracked symbol whose firings are posted
+// to webhookURL
+func (s *AlertService) CreateAlert(ctx context.Context, rule domain.AlertRule, webhookURL string) (*domain.Alert, error) {
+	alert, err := domain.NewAlert(rule, webhookURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(ctx, alert); err != nil {
+		if errors.Is(err, domain.ErrSymbolNotFound) {
+			return nil, err
+		}
+		s.logger.Error("failed to create alert", "symbol", alert.Rule.Symbol, "error", err)
+		return nil, domain.ErrInternal
+	}
+
+	s.mu.Lock()
+	if s.loaded {
+		s.track(alert)
+	}
+	s.mu.Unlock()
+
+	s.logger.Info("alert created", "alert_id", alert.ID, "symbol", alert.Rule.Symbol, "condition", alert.Rule.Condition)
+	return alert, nil
+}
+
+// CreateWatch registers a watch: a one-shot alert posting to webhookURL the
+// first time the symbol's price crosses price within ttl
+func (s *AlertService) CreateWatch(ctx context.Context, symbol string, price decimal.Decimal, webhookURL string, ttl time.Duration) (*domain.Alert, error) {
+	watch, err := domain.NewWatch(symbol, price, webhookURL, ttl)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(ctx, watch); err != nil {
+		if errors.Is(err, domain.ErrSymbolNotFound) {
+			return nil, err
+		}
+		s.logger.Error("failed to create watch", "symbol", watch.Rule.Symbol, "error", err)
+		return nil, domain.ErrInternal
+	}
+
+	s.mu.Lock()
+	if s.loaded {
+		s.track(watch)
+	}
+	s.mu.Unlock()
+
+	s.logger.Info("watch created", "alert_id", watch.ID, "symbol", watch.Rule.Symbol, "expires_at", watch.ExpiresAt)
+	return watch, nil
+}
+
+// ListAlerts returns every alert with its latest firing and delivery
+func (s *AlertService) ListAlerts(ctx context.Context) ([]*domain.Alert, error) {
+	alerts, err := s.repo.List(ctx)
+	if err != nil {
+		s.logger.Error("failed to list alerts", "error", err)
+		return nil, domain.ErrInternal
+	}
+	return alerts, nil
+}
+
+// DeleteAlert removes an alert
+func (s *AlertService) DeleteAlert(ctx context.Context, id int64) error {
+	if err := s.repo.Delete(ctx, id); err != nil {
+		if errors.Is(err, domain.ErrAlertNotFound) {
+			return err
+		}
+		s.logger.Error("failed to delete alert", "alert_id", id, "error", err)
+		return domain.ErrInternal
+	}
+
+	s.mu.Lock()
+	s.forget(id)
+	s.mu.Unlock()
+
+	s.logger.Info("alert deleted", "alert_id", id)
+	return nil
+}
+
+// Evaluate feeds freshly polled snapshots to the rules of their symbols,
+// oldest first, and queues a webhook call for each firing. Watches whose
+// TTL ran out are removed first. Failures are logged; they never fail the
+// poll.
+func (s *AlertService) Evaluate(ctx context.Context, snapshots []*domain.PriceSnapshot) {
+	fired, expired, err := s.observe(ctx, snapshots, time.Now())
+	if err != nil {
+		s.logger.Error("failed to load alerts", "error", err)
+		return
+	}
+
+	for _, id := range expired {
+		if err := s.repo.Delete(ctx, id); err != nil && !errors.Is(err, domain.ErrAlertNotFound) {
+			s.logger.Error("failed to remove expired watch", "alert_id", id, "error", err)
+			continue
+		}
+		s.logger.Info("watch expired", "alert_id", id)
+	}
+
+	for _, f := range fired {
+		s.fire(ctx, f)
+	}
+}
+
+// observe runs the snapshots through the live evaluators and returns the
+// firings they caused, along with the watches that expired by now. Expired
+// and fired watches stop being evaluated.
+func (s *AlertService) observe(ctx context.Context, snapshots []*domain.PriceSnapshot, now time.Time) ([]alertFired, []int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.loaded {
+		alerts, err := s.repo.List(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, alert := range alerts {
+			// A spent watch only awaits its webhook call
+			if !alert.Spent() {
+				s.track(alert)
+			}
+		}
+		s.loaded = true
+	}
+
+	var expired []int64
+	for _, alerts := range s.live {
+		for _, live := range alerts {
+			if live.alert.Expired(now) {
+				expired = append(expired, live.alert.ID)
+			}
+		}
+	}
+	for _, id := range expired {
+		s.forget(id)
+	}
+
+	ordered := slices.Clone(snapshots)
+	slices.SortStableFunc(ordered, func(a, b *domain.PriceSnapshot) int {
+		return a.Timestamp.Compare(b.Timestamp)
+	})
+
+	var fired []alertFired
+	spent := make(map[int64]bool)
+	for _, snap := range ordered {
+		for _, live := range s.live[snap.Symbol] {
+			if spent[live.alert.ID] {
+				continue
+			}
+			if firing := live.evaluator.Observe(snap); firing != nil {
+				fired = append(fired, alertFired{alert: live.alert, firing: *firing})
+				spent[live.alert.ID] = live.alert.Once
+			}
+		}
+	}
+	for id, once := range spent {
+		if once {
+			s.forget(id)
+		}
+	}
+	return fired, expired, nil
+}
+
+// fire records a firing on its alert and queues the webhook call. An alert
+// removed meanwhile, e.g. with its symbol, stops being evaluated.
+func (s *AlertService) fire(ctx context.Context, f alertFired) {
+	logger := s.logger.With("alert_id", f.alert.ID, "symbol", f.alert.Rule.Symbol)
+
+	if err := s.repo.RecordFiring(ctx, f.alert.ID, f.firing); err != nil {
+		if errors.Is(err, domain.ErrAlertNotFound) {
+			s.mu.Lock()
+			s.forget(f.alert.ID)
+			s.mu.Unlock()
+			return
+		}
+		logger.Error("failed to record alert firing", "error", err)
+		return
+	}
+
+	payload, err := json.Marshal(AlertWebhookPayload{AlertID: f.alert.ID, Firing: f.firing})
+	if err != nil {
+		logger.Error("failed to encode alert webhook payload", "error", err)
+		return
+	}
+
+	if _, err := s.jobs.Enqueue(ctx, domain.JobTypeAlertWebhook, payload, time.Time{}); err != nil {
+		logger.Error("failed to queue alert webhook", "error", err)
+		return
+	}
+
+	logger.Info("alert fired", "condition", f.alert.Rule.Condition, "price", f.firing.Price)
+}
+
+// track starts evaluating an alert; the caller holds mu
+func (s *AlertService) track(alert *domain.Alert) {
+	symbol := alert.Rule.Symbol
+	s.live[symbol] = append(s.live[symbol], &liveAlert{
+		alert:     alert,
+		evaluator: domain.NewAlertEvaluator(alert.Rule),
+	})
+}
+
+// forget stops evaluating an alert; the caller holds mu
+func (s *AlertService) forget(id int64) {
+	for symbol, alerts := range s.live {
+		alerts = slices.DeleteFunc(alerts, func(live *liveAlert) bool {
+			return live.alert.ID == id
+		})
+		if len(alerts) == 0 {
+			delete(s.live, symbol)
+		} else {
+			s.live[symbol] = alerts
+		}
+	}
+}
+
+// Ensure AlertService implements ports.AlertService
+var _ ports.AlertService = (*AlertService)(nil)
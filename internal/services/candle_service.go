@@ -0,0 +1,138 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+)
+
+// maxCandles caps the candles returned by a single query
+const maxCandles = 5000
+
+// CandleService implements the ports.CandleService interface
+type CandleService struct {
+	candleRepo ports.CandleRepository
+	symbolRepo ports.SymbolRepository
+	symbols    *domain.SymbolNormalizer
+	backfill   time.Duration
+	clock      ports.Clock
+	logger     *slog.Logger
+
+	// aggregated holds the end of the last run for each interval
+	mu         sync.Mutex
+	aggregated map[domain.CandleInterval]time.Time
+}
+
+// NewCandleService creates a candle service whose first aggregation after
+// startup reaches back backfill
+func NewCandleService(
+	candleRepo ports.CandleRepository,
+	symbolRepo ports.SymbolRepository,
+	symbols *domain.SymbolNormalizer,
+	backfill time.Duration,
+	clock ports.Clock,
+	logger *slog.Logger,
+) *CandleService {
+	return &CandleService{
+		candleRepo: candleRepo,
+		symbolRepo: symbolRepo,
+		symbols:    symbols,
+		backfill:   backfill,
+		clock:      clock,
+		logger:     logger.With("component", "candle_service"),
+		aggregated: make(map[domain.CandleInterval]time.Time),
+	}
+}
+
+// GetCandles returns a tracked or removed symbol's candles of interval opening in
+// [from, to), oldest first. from is aligned down to the interval. Gaps are
+// filled by fill; at most maxCandles are returned.
+func (s *CandleService) GetCandles(
+	ctx context.Context,
+	symbol string,
+	interval domain.CandleInterval,
+	from, to time.Time,
+	fill domain.FillMode,
+) ([]*domain.Candle, error) {
+	symbol = s.symbols.Normalize(symbol)
+	width := interval.Duration()
+	from = from.UTC().Truncate(width)
+	if !from.Before(to) {
+		return nil, domain.ErrInvalidTimeRange
+	}
+
+	exists, err := s.symbolRepo.ExistsIncludingDeleted(ctx, symbol)
+	if err != nil {
+		s.logger.Error("failed to check symbol existence", "symbol", symbol, "error", err)
+		return nil, domain.ErrInternal
+	}
+	if !exists {
+		return nil, domain.ErrSymbolNotFound
+	}
+
+	candles, err := s.candleRepo.List(ctx, symbol, interval, from, to, maxCandles)
+	if err != nil {
+		s.logger.Error("failed to list candles", "symbol", symbol, "interval", interval, "error", err)
+		return nil, domain.ErrInternal
+	}
+
+	candles = domain.FillCandleGaps(candles, symbol, from, to, width, fill)
+	if len(candles) > maxCandles {
+		candles = candles[:maxCandles]
+	}
+	return candles, nil
+}
+
+// Aggregate rebuilds the candles of every interval, finest first so that
+// each rollup sees fresh candles below it. A run starts at the candle that
+// was still open at the end of the previous one, which is rebuilt until it
+// closes; the first run reaches back the backfill period. Snapshots stored
+// further back later, e.g. by a backfill job, are not aggregated.
+func (s *CandleService) Aggregate(ctx context.Context) (*domain.CandleAggregation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	start := s.clock.Now()
+	now := start.UTC()
+	result := &domain.CandleAggregation{
+		From:    now.Add(-s.backfill),
+		To:      now,
+		Candles: make(map[domain.CandleInterval]int64, len(domain.CandleIntervals)),
+	}
+
+	for _, interval := range domain.CandleIntervals {
+		from, ok := s.aggregated[interval]
+		if !ok {
+			from = now.Add(-s.backfill)
+		}
+		from = from.Truncate(interval.Duration())
+		if from.Before(result.From) {
+			result.From = from
+		}
+
+		n, err := s.candleRepo.Aggregate(ctx, interval, from, now)
+		if err != nil {
+			s.logger.Error("failed to aggregate candles", "interval", interval, "error", err)
+			return result, domain.ErrInternal
+		}
+		result.Candles[interval] = n
+		s.aggregated[interval] = now
+	}
+
+	result.DurationMs = s.clock.Since(start).Milliseconds()
+
+	s.logger.Debug("candles aggregated",
+		"from", result.From,
+		"candles", result.Candles,
+		"duration_ms", result.DurationMs,
+	)
+
+	return result, nil
+}
+
+// Ensure CandleService implements ports.CandleService
+var _ ports.CandleService = (*CandleService)(nil)
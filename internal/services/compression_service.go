@@ -0,0 +1,92 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+)
+
+// CompressionService implements the ports.CompressionService interface
+type CompressionService struct {
+	symbolRepo   ports.SymbolRepository
+	snapshotRepo ports.SnapshotRepository
+	after        time.Duration
+	width        time.Duration
+	logger       *slog.Logger
+}
+
+// NewCompressionService creates a compression service that packs snapshots
+// older than after into chunks spanning width each
+func NewCompressionService(
+	symbolRepo ports.SymbolRepository,
+	snapshotRepo ports.SnapshotRepository,
+	after time.Duration,
+	width time.Duration,
+	logger *slog.Logger,
+) *CompressionService {
+	return &CompressionService{
+		symbolRepo:   symbolRepo,
+		snapshotRepo: snapshotRepo,
+		after:        after,
+		width:        width,
+		logger:       logger.With("component", "compression_service"),
+	}
+}
+
+// CompressOld packs each symbol's snapshots older than the configured age
+// into chunks. The cutoff is aligned to the chunk width so only whole
+// chunks are written. Symbols are compressed one at a time; a failure is
+// recorded and the run moves on.
+func (c *CompressionService) CompressOld(ctx context.Context) (*domain.CompressionResult, error) {
+	start := time.Now()
+	cutoff := start.UTC().Add(-c.after).Truncate(c.width)
+
+	symbols, err := c.symbolRepo.ListIncludingDeleted(ctx)
+	if err != nil {
+		c.logger.Error("failed to list symbols", "error", err)
+		return nil, domain.ErrInternal
+	}
+
+	result := &domain.CompressionResult{
+		Cutoff:   cutoff,
+		BySymbol: make(map[string]int64, len(symbols)),
+	}
+
+	for _, s := range symbols {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		chunks, rows, err := c.snapshotRepo.CompressBySymbol(ctx, s.Name, cutoff, c.width)
+		result.Chunks += int64(chunks)
+		result.Rows += rows
+		result.BySymbol[s.Name] = rows
+		if err != nil {
+			c.logger.Error("failed to compress symbol", "symbol", s.Name, "error", err)
+			result.Failed = append(result.Failed, s.Name)
+			continue
+		}
+
+		if chunks > 0 {
+			c.logger.Info("compressed symbol", "symbol", s.Name, "chunks", chunks, "rows", rows)
+		}
+	}
+
+	result.DurationMs = time.Since(start).Milliseconds()
+
+	c.logger.Info("compression completed",
+		"symbols", len(symbols),
+		"chunks", result.Chunks,
+		"rows", result.Rows,
+		"failed", len(result.Failed),
+		"duration_ms", result.DurationMs,
+	)
+
+	return result, nil
+}
+
+// Ensure CompressionService implements ports.CompressionService
+var _ ports.CompressionService = (*CompressionService)(nil)
@@ -0,0 +1,82 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+)
+
+// digestWindow is the period covered by a price digest
+const digestWindow = 24 * time.Hour
+
+// DigestService implements the ports.DigestService interface
+type DigestService struct {
+	snapshotRepo ports.SnapshotRepository
+	notifiers    []ports.Notifier
+	topN         int
+	logger       *slog.Logger
+}
+
+// NewDigestService creates a new digest service
+func NewDigestService(
+	snapshotRepo ports.SnapshotRepository,
+	notifiers []ports.Notifier,
+	topN int,
+	logger *slog.Logger,
+) *DigestService {
+	if topN < 1 {
+		topN = 1
+	}
+
+	return &DigestService{
+		snapshotRepo: snapshotRepo,
+		notifiers:    notifiers,
+		topN:         topN,
+		logger:       logger.With("component", "digest_service"),
+	}
+}
+
+// BuildDigest computes the top gainers and losers over the last 24 hours
+func (s *DigestService) BuildDigest(ctx context.Context) (*domain.PriceDigest, error) {
+	since := time.Now().UTC().Add(-digestWindow)
+
+	changes, err := s.snapshotRepo.GetPriceChanges(ctx, since)
+	if err != nil {
+		s.logger.Error("failed to get price changes", "error", err)
+		return nil, domain.ErrInternal
+	}
+
+	return domain.NewPriceDigest(changes, digestWindow, s.topN), nil
+}
+
+// SendDigest builds a digest and delivers it to every configured channel.
+// A failing channel does not prevent delivery to the others.
+func (s *DigestService) SendDigest(ctx context.Context) error {
+	digest, err := s.BuildDigest(ctx)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, n := range s.notifiers {
+		if err := n.SendDigest(ctx, digest); err != nil {
+			s.logger.Error("failed to send digest", "channel", n.Name(), "error", err)
+			errs = append(errs, err)
+			continue
+		}
+		s.logger.Info("digest sent",
+			"channel", n.Name(),
+			"gainers", len(digest.Gainers),
+			"losers", len(digest.Losers),
+		)
+	}
+
+	return errors.Join(errs...)
+}
+
+// Ensure DigestService implements ports.DigestService
+var _ ports.DigestService = (*DigestService)(nil)
@@ -0,0 +1,153 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+)
+
+// ExchangeBudget implements the ports.ExchangeBudget interface with a
+// fixed-window weight counter matching the exchange's own limit. A call
+// that would take its priority past its share of the window waits for the
+// next window, so lower priorities cannot use up the headroom kept for the
+// poll; one whose deadline comes first is shed instead.
+type ExchangeBudget struct {
+	limit  int
+	window time.Duration
+	clock  ports.Clock
+	logger *slog.Logger
+
+	mu          sync.Mutex
+	windowStart time.Time
+	used        int
+	waited      map[domain.ExchangePriority]int64
+	shed        map[domain.ExchangePriority]int64
+}
+
+// NewExchangeBudget creates a budget of limit weight per window
+func NewExchangeBudget(limit int, window time.Duration, clock ports.Clock, logger *slog.Logger) *ExchangeBudget {
+	return &ExchangeBudget{
+		limit:  limit,
+		window: window,
+		clock:  clock,
+		logger: logger.With("component", "exchange_budget"),
+		waited: make(map[domain.ExchangePriority]int64),
+		shed:   make(map[domain.ExchangePriority]int64),
+	}
+}
+
+// Acquire reserves weight at the priority set on ctx. A request heavier
+// than a whole share is let through once the window is otherwise unused.
+// A call that could not be sent before ctx's deadline fails at once with
+// domain.ErrExchangeBudget, which is also a domain.ErrRateLimited.
+func (b *ExchangeBudget) Acquire(ctx context.Context, weight int) error {
+	priority := domain.ExchangePriorityFrom(ctx)
+	ceiling := int(float64(b.limit) * priority.Share())
+
+	for waited := false; ; waited = true {
+		wait, ok := b.reserve(weight, ceiling)
+		if ok {
+			return nil
+		}
+
+		if deadline, ok := ctx.Deadline(); ok && deadline.Before(b.clock.Now().Add(wait)) {
+			b.count(b.shed, priority)
+			b.logger.Debug("shedding exchange call over budget",
+				"priority", priority.String(), "weight", weight, "wait", wait)
+			return fmt.Errorf("%w for %s: %w", domain.ErrExchangeBudget, wait.Round(time.Millisecond), domain.ErrRateLimited)
+		}
+		if !waited {
+			b.count(b.waited, priority)
+		}
+
+		b.logger.Debug("waiting for exchange budget",
+			"priority", priority.String(), "weight", weight, "wait", wait)
+
+		ticker := b.clock.NewTicker(wait)
+		select {
+		case <-ctx.Done():
+			ticker.Stop()
+			return ctx.Err()
+		case <-ticker.C():
+			ticker.Stop()
+		}
+	}
+}
+
+// reserve takes weight from the current window if it fits under ceiling,
+// or returns how long until the next window
+func (b *ExchangeBudget) reserve(weight, ceiling int) (time.Duration, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.clock.Now()
+	b.advance(now)
+
+	if b.used+weight <= ceiling || b.used == 0 {
+		b.used += weight
+		return 0, true
+	}
+	return b.windowStart.Add(b.window).Sub(now), false
+}
+
+// Observe raises the weight used in the current window to what the
+// exchange reported
+func (b *ExchangeBudget) Observe(used int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.advance(b.clock.Now())
+	if used > b.used {
+		b.used = used
+	}
+}
+
+// count adds a call of priority to counts
+func (b *ExchangeBudget) count(counts map[domain.ExchangePriority]int64, priority domain.ExchangePriority) {
+	b.mu.Lock()
+	counts[priority]++
+	b.mu.Unlock()
+}
+
+// BudgetStats returns the weight used in the current window and the calls
+// held back so far, by priority from lowest to highest
+func (b *ExchangeBudget) BudgetStats() *domain.ExchangeBudgetStats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.advance(b.clock.Now())
+	stats := &domain.ExchangeBudgetStats{
+		Limit:         b.limit,
+		Used:          b.used,
+		WindowSeconds: b.window.Seconds(),
+	}
+	for p := domain.ExchangePriorityBackfill; p <= domain.ExchangePriorityPoll; p++ {
+		if b.waited[p] > 0 || b.shed[p] > 0 {
+			stats.Priorities = append(stats.Priorities, domain.ExchangePriorityStats{
+				Priority: p.String(),
+				Waited:   b.waited[p],
+				Shed:     b.shed[p],
+			})
+		}
+	}
+	return stats
+}
+
+// advance starts a new window once now has left the current one
+func (b *ExchangeBudget) advance(now time.Time) {
+	if start := domain.RateLimitWindow(now, b.window); start.After(b.windowStart) {
+		b.windowStart = start
+		b.used = 0
+	}
+}
+
+// Ensure ExchangeBudget implements the exchange budget ports
+var (
+	_ ports.ExchangeBudget              = (*ExchangeBudget)(nil)
+	_ ports.ExchangeBudgetStatsProvider = (*ExchangeBudget)(nil)
+)
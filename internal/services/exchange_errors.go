@@ -0,0 +1,59 @@
+package services
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+)
+
+// exchangeErrorKey identifies one labelled exchange error counter
+type exchangeErrorKey struct {
+	operation string
+	kind      domain.ExchangeErrorKind
+}
+
+// ExchangeErrorCounter counts classified exchange failures per operation
+// and kind. It implements ports.ExchangeErrorRecorder.
+type ExchangeErrorCounter struct {
+	mu     sync.Mutex
+	counts map[exchangeErrorKey]int64
+}
+
+// NewExchangeErrorCounter creates an empty exchange error counter
+func NewExchangeErrorCounter() *ExchangeErrorCounter {
+	return &ExchangeErrorCounter{counts: make(map[exchangeErrorKey]int64)}
+}
+
+// RecordExchangeError counts a failed request attempt for an operation
+func (c *ExchangeErrorCounter) RecordExchangeError(operation string, kind domain.ExchangeErrorKind) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.counts[exchangeErrorKey{operation: operation, kind: kind}]++
+}
+
+// Counts returns the non-zero counters ordered by operation and kind
+func (c *ExchangeErrorCounter) Counts() []domain.ExchangeErrorCount {
+	c.mu.Lock()
+	counts := make([]domain.ExchangeErrorCount, 0, len(c.counts))
+	for key, n := range c.counts {
+		counts = append(counts, domain.ExchangeErrorCount{
+			Operation: key.operation,
+			Kind:      key.kind,
+			Count:     n,
+		})
+	}
+	c.mu.Unlock()
+
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Operation != counts[j].Operation {
+			return counts[i].Operation < counts[j].Operation
+		}
+		return counts[i].Kind < counts[j].Kind
+	})
+	return counts
+}
+
+// Ensure ExchangeErrorCounter implements ports.ExchangeErrorRecorder
+var _ ports.ExchangeErrorRecorder = (*ExchangeErrorCounter)(nil)
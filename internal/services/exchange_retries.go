@@ -0,0 +1,97 @@
+package services
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+)
+
+// exchangeRetryKey identifies one labelled exchange retry counter
+type exchangeRetryKey struct {
+	operation string
+	reason    domain.ExchangeErrorKind
+}
+
+// ExchangeRetryCounter counts exchange request retries per operation and
+// reason, and the attempts made per call of each operation. It implements
+// ports.ExchangeRetryRecorder.
+type ExchangeRetryCounter struct {
+	mu       sync.Mutex
+	retries  map[exchangeRetryKey]int64
+	attempts map[string]*domain.Histogram
+}
+
+// NewExchangeRetryCounter creates an empty exchange retry counter
+func NewExchangeRetryCounter() *ExchangeRetryCounter {
+	return &ExchangeRetryCounter{
+		retries:  make(map[exchangeRetryKey]int64),
+		attempts: make(map[string]*domain.Histogram),
+	}
+}
+
+// RecordExchangeRetry counts a retry of an operation's request
+func (c *ExchangeRetryCounter) RecordExchangeRetry(operation string, reason domain.ExchangeErrorKind) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.retries[exchangeRetryKey{operation: operation, reason: reason}]++
+}
+
+// RecordExchangeAttempts records the request attempts of one call
+func (c *ExchangeRetryCounter) RecordExchangeAttempts(operation string, attempts int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	h, ok := c.attempts[operation]
+	if !ok {
+		h = domain.NewHistogram(domain.ExchangeAttemptBuckets)
+		c.attempts[operation] = h
+	}
+	h.Observe(float64(attempts))
+}
+
+// Retries returns the non-zero retry counters ordered by operation and
+// reason
+func (c *ExchangeRetryCounter) Retries() []domain.ExchangeRetryCount {
+	c.mu.Lock()
+	counts := make([]domain.ExchangeRetryCount, 0, len(c.retries))
+	for key, n := range c.retries {
+		counts = append(counts, domain.ExchangeRetryCount{
+			Operation: key.operation,
+			Reason:    key.reason,
+			Count:     n,
+		})
+	}
+	c.mu.Unlock()
+
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Operation != counts[j].Operation {
+			return counts[i].Operation < counts[j].Operation
+		}
+		return counts[i].Reason < counts[j].Reason
+	})
+	return counts
+}
+
+// Attempts returns the attempts per call histogram of each operation
+// called so far, ordered by operation
+func (c *ExchangeRetryCounter) Attempts() []domain.ExchangeCallAttempts {
+	c.mu.Lock()
+	attempts := make([]domain.ExchangeCallAttempts, 0, len(c.attempts))
+	for operation, h := range c.attempts {
+		attempts = append(attempts, domain.ExchangeCallAttempts{
+			Operation: operation,
+			Attempts:  h.Clone(),
+		})
+	}
+	c.mu.Unlock()
+
+	sort.Slice(attempts, func(i, j int) bool {
+		return attempts[i].Operation < attempts[j].Operation
+	})
+	return attempts
+}
+
+// Ensure ExchangeRetryCounter implements ports.ExchangeRetryRecorder
+var _ ports.ExchangeRetryRecorder = (*ExchangeRetryCounter)(nil)
@@ -0,0 +1,48 @@
+package services
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+)
+
+// exchangeSymbols are the symbols polled from one exchange
+type exchangeSymbols struct {
+	exchange string
+	symbols  []*domain.Symbol
+}
+
+// names returns the names of the exchange's symbols
+func (e exchangeSymbols) names() []string {
+	names := make([]string, len(e.symbols))
+	for i, s := range e.symbols {
+		names[i] = s.Name
+	}
+	return names
+}
+
+// byExchange groups symbols by the exchange they are polled from, in
+// exchange name order. Symbols without an exchange belong to the default
+// one; synthetic symbols are not fetched from any and are left out.
+func byExchange(exchanges ports.ExchangeRegistry, symbols []*domain.Symbol) []exchangeSymbols {
+	groups := make(map[string][]*domain.Symbol)
+	for _, s := range symbols {
+		if s.Exchange == domain.SyntheticExchange {
+			continue
+		}
+		name := strings.ToLower(s.Exchange)
+		if name == "" {
+			name = exchanges.Default()
+		}
+		groups[name] = append(groups[name], s)
+	}
+
+	result := make([]exchangeSymbols, 0, len(groups))
+	for name, symbols := range groups {
+		result = append(result, exchangeSymbols{exchange: name, symbols: symbols})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].exchange < result[j].exchange })
+	return result
+}
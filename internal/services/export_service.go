@@ -0,0 +1,124 @@
+package services
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+)
+
+// partSuffix marks a file that is still being written. Files are renamed
+// into place once complete, so a rerun after an interruption redoes only
+// the file that was in progress.
+const partSuffix = ".part"
+
+// ExportService implements the ports.ExportService interface
+type ExportService struct {
+	symbolRepo ports.SymbolRepository
+	exporter   ports.SnapshotExporter
+	now        func() time.Time
+	logger     *slog.Logger
+}
+
+// NewExportService creates a new export service
+func NewExportService(
+	symbolRepo ports.SymbolRepository,
+	exporter ports.SnapshotExporter,
+	logger *slog.Logger,
+) *ExportService {
+	return &ExportService{
+		symbolRepo: symbolRepo,
+		exporter:   exporter,
+		now:        time.Now,
+		logger:     logger.With("component", "export_service"),
+	}
+}
+
+// Export writes one gzipped CSV file per bucket and symbol under dir
+func (s *ExportService) Export(ctx context.Context, dir string, rng domain.ExportRange, symbols []string) (*domain.ExportSummary, error) {
+	if err := rng.Validate(s.now()); err != nil {
+		return nil, err
+	}
+
+	if len(symbols) == 0 {
+		tracked, err := s.symbolRepo.List(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list symbols: %w", err)
+		}
+		for _, sym := range tracked {
+			symbols = append(symbols, sym.Name)
+		}
+	}
+
+	summary := &domain.ExportSummary{}
+	for _, file := range rng.Files(symbols) {
+		target := filepath.Join(dir, filepath.FromSlash(file.Path))
+		if _, err := os.Stat(target); err == nil {
+			summary.Skipped++
+			continue
+		} else if !errors.Is(err, fs.ErrNotExist) {
+			return summary, err
+		}
+
+		rows, err := s.writeFile(ctx, target, file)
+		if err != nil {
+			return summary, fmt.Errorf("failed to export %s: %w", file.Path, err)
+		}
+		summary.Written++
+		summary.Rows += rows
+
+		s.logger.Debug("exported file", "path", file.Path, "rows", rows)
+	}
+
+	s.logger.Info("export finished",
+		"files_written", summary.Written,
+		"files_skipped", summary.Skipped,
+		"rows", summary.Rows,
+	)
+
+	return summary, nil
+}
+
+// writeFile exports one file through a temporary part file that is only
+// renamed to target after it is complete and synced
+func (s *ExportService) writeFile(ctx context.Context, target string, file domain.ExportFile) (int64, error) {
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return 0, err
+	}
+
+	part := target + partSuffix
+	f, err := os.Create(part)
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(part)
+	defer f.Close()
+
+	gz := gzip.NewWriter(f)
+	rows, err := s.exporter.ExportCSV(ctx, file.Symbol, file.From, file.To, gz)
+	if err != nil {
+		return 0, err
+	}
+	if err := gz.Close(); err != nil {
+		return 0, err
+	}
+	if err := f.Sync(); err != nil {
+		return 0, err
+	}
+	if err := f.Close(); err != nil {
+		return 0, err
+	}
+
+	return rows, os.Rename(part, target)
+}
+
+// Ensure ExportService implements ports.ExportService
+var _ ports.ExportService = (*ExportService)(nil)
@@ -0,0 +1,122 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+)
+
+// healthCheckTimeout bounds each dependency check
+const healthCheckTimeout = 5 * time.Second
+
+// HealthService implements the ports.HealthService interface. It checks the
+// database and the exchange and caches the result for the TTL, so frequent
+// health probes and metrics scrapes do not each ping the exchange.
+type HealthService struct {
+	symbolRepo ports.SymbolRepository
+	exchange   ports.ExchangeClient
+	ttl        time.Duration
+	clock      ports.Clock
+	logger     *slog.Logger
+
+	// mu is held for the whole check, so concurrent callers share one
+	mu        sync.Mutex
+	cached    *ports.HealthStatus
+	checkedAt time.Time
+}
+
+// NewHealthService creates a new health service. A zero ttl checks the
+// dependencies on every call.
+func NewHealthService(
+	symbolRepo ports.SymbolRepository,
+	exchange ports.ExchangeClient,
+	ttl time.Duration,
+	clock ports.Clock,
+	logger *slog.Logger,
+) *HealthService {
+	return &HealthService{
+		symbolRepo: symbolRepo,
+		exchange:   exchange,
+		ttl:        ttl,
+		clock:      clock,
+		logger:     logger.With("component", "health_service"),
+	}
+}
+
+// CheckHealth returns the health of the database and the exchange, checked
+// at most once per TTL. It fails only when ctx is done.
+func (s *HealthService) CheckHealth(ctx context.Context) (*ports.HealthStatus, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cached != nil && s.clock.Since(s.checkedAt) < s.ttl {
+		return copyHealthStatus(s.cached), nil
+	}
+
+	var dbErr, exchangeErr error
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		checkCtx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+		defer cancel()
+		// Counting symbols checks both the connection and the schema
+		_, dbErr = s.symbolRepo.Count(checkCtx)
+	}()
+	go func() {
+		defer wg.Done()
+		checkCtx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+		defer cancel()
+		exchangeErr = s.exchange.Ping(checkCtx)
+	}()
+	wg.Wait()
+
+	// A check cut short by the caller says nothing about the dependencies
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	status := &ports.HealthStatus{
+		Status:   ports.HealthHealthy,
+		Database: ports.HealthHealthy,
+		Exchange: ports.HealthHealthy,
+	}
+	if dbErr != nil || exchangeErr != nil {
+		status.Details = make(map[string]string)
+	}
+	if dbErr != nil {
+		status.Database = ports.HealthUnhealthy
+		status.Details["database"] = dbErr.Error()
+	}
+	if exchangeErr != nil {
+		status.Exchange = ports.HealthUnhealthy
+		status.Details["exchange"] = exchangeErr.Error()
+	}
+	if status.Details != nil {
+		status.Status = ports.HealthDegraded
+		s.logger.Warn("service degraded", "details", status.Details)
+	}
+
+	s.cached = status
+	s.checkedAt = s.clock.Now()
+	return copyHealthStatus(status), nil
+}
+
+// copyHealthStatus returns a copy callers may modify without touching the
+// cached status
+func copyHealthStatus(status *ports.HealthStatus) *ports.HealthStatus {
+	c := *status
+	if status.Details != nil {
+		c.Details = make(map[string]string, len(status.Details))
+		for k, v := range status.Details {
+			c.Details[k] = v
+		}
+	}
+	return &c
+}
+
+// Ensure HealthService implements ports.HealthService
+var _ ports.HealthService = (*HealthService)(nil)
@@ -0,0 +1,360 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+)
+
+// CachedHistoryRepository wraps a ports.SnapshotRepository and serves
+// repeated GetHistory and GetHistoryBetween calls, such as those of
+// dashboards refreshing a chart every few seconds, from memory. A write
+// through this wrapper drops the cached results of the symbol whose time
+// range it touches; results are also dropped after the TTL to pick up
+// writes made by other instances.
+type CachedHistoryRepository struct {
+	ports.SnapshotRepository
+
+	ttl        time.Duration
+	maxEntries int
+	logger     *slog.Logger
+
+	mu       sync.Mutex
+	bySymbol map[string]map[historyKey]*historyEntry
+	size     int
+
+	// gens counts the invalidations of each symbol and allGen those of
+	// every symbol, so that a read can tell whether a write invalidated its
+	// symbol while it was in flight
+	gens   map[string]uint64
+	allGen uint64
+}
+
+// historyKey identifies a history read of one symbol. Times are in Unix
+// nanoseconds, zero when unset.
+type historyKey struct {
+	between  bool
+	from, to int64
+	beforeTs int64
+	beforeID int64
+	limit    int
+}
+
+// historyEntry is a cached result and the time range it was read from; a
+// zero bound is open
+type historyEntry struct {
+	snapshots []*domain.PriceSnapshot
+	lo, hi    time.Time
+	storedAt  time.Time
+}
+
+// NewCachedHistoryRepository creates a history cache holding up to
+// maxEntries results for ttl each
+func NewCachedHistoryRepository(repo ports.SnapshotRepository, ttl time.Duration, maxEntries int, logger *slog.Logger) *CachedHistoryRepository {
+	return &CachedHistoryRepository{
+		SnapshotRepository: repo,
+		ttl:                ttl,
+		maxEntries:         maxEntries,
+		logger:             logger.With("component", "history_cache"),
+		bySymbol:           make(map[string]map[historyKey]*historyEntry),
+		gens:               make(map[string]uint64),
+	}
+}
+
+// GetHistory returns historical snapshots for a symbol, from the cache when
+// the same page was read within the TTL
+func (c *CachedHistoryRepository) GetHistory(ctx context.Context, symbolName string, before *domain.HistoryCursor, limit int) ([]*domain.PriceSnapshot, error) {
+	key := historyKey{limit: limit}
+	var hi time.Time
+	if before != nil {
+		key.beforeTs, key.beforeID = before.Timestamp.UnixNano(), before.ID
+		hi = before.Timestamp
+	}
+
+	snapshots, gen, ok := c.get(symbolName, key)
+	if ok {
+		return snapshots, nil
+	}
+
+	snapshots, err := c.SnapshotRepository.GetHistory(ctx, symbolName, before, limit)
+	if err != nil {
+		return nil, err
+	}
+	c.put(symbolName, key, gen, snapshots, time.Time{}, hi)
+	return copySnapshots(snapshots), nil
+}
+
+// GetHistoryBetween returns snapshots within a time range, from the cache
+// when the same page was read within the TTL
+func (c *CachedHistoryRepository) GetHistoryBetween(ctx context.Context, symbolName string, from, to time.Time, before *domain.HistoryCursor, limit int) ([]*domain.PriceSnapshot, error) {
+	key := historyKey{between: true, from: from.UnixNano(), to: to.UnixNano(), limit: limit}
+	if before != nil {
+		key.beforeTs, key.beforeID = before.Timestamp.UnixNano(), before.ID
+	}
+
+	snapshots, gen, ok := c.get(symbolName, key)
+	if ok {
+		return snapshots, nil
+	}
+
+	snapshots, err := c.SnapshotRepository.GetHistoryBetween(ctx, symbolName, from, to, before, limit)
+	if err != nil {
+		return nil, err
+	}
+	c.put(symbolName, key, gen, snapshots, from, to)
+	return copySnapshots(snapshots), nil
+}
+
+// Writes drop the cached results they may change, whether or not they
+// fail, since a failed write may still have been applied
+
+// Create stores a snapshot and invalidates its symbol's cached history
+func (c *CachedHistoryRepository) Create(ctx context.Context, snapshot *domain.PriceSnapshot) error {
+	defer c.invalidateSnapshots([]*domain.PriceSnapshot{snapshot})
+	return c.SnapshotRepository.Create(ctx, snapshot)
+}
+
+// CreateBatch stores snapshots and invalidates their symbols' cached history
+func (c *CachedHistoryRepository) CreateBatch(ctx context.Context, snapshots []*domain.PriceSnapshot) error {
+	defer c.invalidateSnapshots(snapshots)
+	return c.SnapshotRepository.CreateBatch(ctx, snapshots)
+}
+
+// CreateBatchPartial stores snapshots and invalidates their symbols' cached
+// history
+func (c *CachedHistoryRepository) CreateBatchPartial(ctx context.Context, snapshots []*domain.PriceSnapshot) (*domain.BatchWriteResult, error) {
+	defer c.invalidateSnapshots(snapshots)
+	return c.SnapshotRepository.CreateBatchPartial(ctx, snapshots)
+}
+
+// CreateBatchIngested stores pushed snapshots and invalidates their
+// symbols' cached history
+func (c *CachedHistoryRepository) CreateBatchIngested(ctx context.Context, snapshots []*domain.PriceSnapshot) (int, error) {
+	defer c.invalidateSnapshots(snapshots)
+	return c.SnapshotRepository.CreateBatchIngested(ctx, snapshots)
+}
+
+// Prune removes old snapshots and invalidates the cached history reaching
+// back before olderThan
+func (c *CachedHistoryRepository) Prune(ctx context.Context, olderThan time.Time) (int64, error) {
+	defer c.invalidateAll(olderThan)
+	return c.SnapshotRepository.Prune(ctx, olderThan)
+}
+
+// PruneBySymbol removes a symbol's old snapshots and invalidates its cached
+// history reaching back before olderThan
+func (c *CachedHistoryRepository) PruneBySymbol(ctx context.Context, symbolName string, olderThan time.Time) (int64, error) {
+	defer c.invalidate(symbolName, time.Time{}, olderThan)
+	return c.SnapshotRepository.PruneBySymbol(ctx, symbolName, olderThan)
+}
+
+// CompressBySymbol packs a symbol's old snapshots into chunks and
+// invalidates its cached history reaching back before before
+func (c *CachedHistoryRepository) CompressBySymbol(ctx context.Context, symbolName string, before time.Time, width time.Duration) (int, int64, error) {
+	defer c.invalidate(symbolName, time.Time{}, before)
+	return c.SnapshotRepository.CompressBySymbol(ctx, symbolName, before, width)
+}
+
+// DeleteRange removes a symbol's snapshots in [from, to) and invalidates
+// its cached history overlapping the range
+func (c *CachedHistoryRepository) DeleteRange(ctx context.Context, symbolName string, from, to time.Time, batchSize int) (*domain.SnapshotRangeDeletion, error) {
+	defer c.invalidate(symbolName, from, to)
+	return c.SnapshotRepository.DeleteRange(ctx, symbolName, from, to, batchSize)
+}
+
+// DeletePollRun removes a poll's snapshots and drops the whole cache, as
+// the run may cover any symbol
+func (c *CachedHistoryRepository) DeletePollRun(ctx context.Context, runID string) (int64, error) {
+	defer c.invalidateAll(time.Time{})
+	return c.SnapshotRepository.DeletePollRun(ctx, runID)
+}
+
+// get returns a copy of a cached result that has not expired. On a miss it
+// returns the symbol's generation to pass to put with the result read.
+func (c *CachedHistoryRepository) get(symbol string, key historyKey) ([]*domain.PriceSnapshot, uint64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.bySymbol[symbol][key]
+	if !ok || time.Since(entry.storedAt) >= c.ttl {
+		return nil, c.generation(symbol), false
+	}
+	return copySnapshots(entry.snapshots), 0, true
+}
+
+// generation returns how often a symbol's cached results have been
+// invalidated. The caller holds the lock.
+func (c *CachedHistoryRepository) generation(symbol string) uint64 {
+	return c.gens[symbol] + c.allGen
+}
+
+// put caches a result read from [lo, hi], evicting expired results and
+// then the oldest when the cache is full. A result read while a write
+// invalidated the symbol, i.e. whose generation is outdated, may predate
+// the write and is not cached.
+func (c *CachedHistoryRepository) put(symbol string, key historyKey, gen uint64, snapshots []*domain.PriceSnapshot, lo, hi time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.generation(symbol) != gen {
+		return
+	}
+
+	entries := c.bySymbol[symbol]
+	if entries == nil {
+		entries = make(map[historyKey]*historyEntry)
+		c.bySymbol[symbol] = entries
+	}
+	if _, ok := entries[key]; !ok {
+		if c.size >= c.maxEntries {
+			c.evict()
+		}
+		c.size++
+	}
+	entries[key] = &historyEntry{
+		snapshots: copySnapshots(snapshots),
+		lo:        lo,
+		hi:        hi,
+		storedAt:  time.Now(),
+	}
+}
+
+// evict drops expired results, or the oldest one when none has expired
+func (c *CachedHistoryRepository) evict() {
+	var (
+		oldestSymbol string
+		oldestKey    historyKey
+		oldest       *historyEntry
+	)
+	for symbol, entries := range c.bySymbol {
+		for key, entry := range entries {
+			if time.Since(entry.storedAt) >= c.ttl {
+				c.remove(symbol, key)
+				continue
+			}
+			if oldest == nil || entry.storedAt.Before(oldest.storedAt) {
+				oldestSymbol, oldestKey, oldest = symbol, key, entry
+			}
+		}
+	}
+	if c.size >= c.maxEntries && oldest != nil {
+		c.remove(oldestSymbol, oldestKey)
+	}
+}
+
+func (c *CachedHistoryRepository) remove(symbol string, key historyKey) {
+	delete(c.bySymbol[symbol], key)
+	if len(c.bySymbol[symbol]) == 0 {
+		delete(c.bySymbol, symbol)
+	}
+	c.size--
+}
+
+// invalidateSnapshots drops the cached history each snapshot's symbol
+// could return the snapshot in
+func (c *CachedHistoryRepository) invalidateSnapshots(snapshots []*domain.PriceSnapshot) {
+	type span struct{ lo, hi time.Time }
+	spans := make(map[string]span)
+	for _, s := range snapshots {
+		sp, ok := spans[s.Symbol]
+		if !ok {
+			spans[s.Symbol] = span{s.Timestamp, s.Timestamp}
+			continue
+		}
+		if s.Timestamp.Before(sp.lo) {
+			sp.lo = s.Timestamp
+		}
+		if s.Timestamp.After(sp.hi) {
+			sp.hi = s.Timestamp
+		}
+		spans[s.Symbol] = sp
+	}
+
+	for symbol, sp := range spans {
+		c.invalidate(symbol, sp.lo, sp.hi)
+	}
+}
+
+// invalidate drops a symbol's cached results read from a range overlapping
+// [lo, hi]; a zero bound is open
+func (c *CachedHistoryRepository) invalidate(symbol string, lo, hi time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.gens[symbol]++
+
+	for key, entry := range c.bySymbol[symbol] {
+		if overlaps(entry.lo, entry.hi, lo, hi) {
+			c.remove(symbol, key)
+		}
+	}
+}
+
+// invalidateAll drops every symbol's cached results reaching back before
+// hi, or all of them when hi is zero
+func (c *CachedHistoryRepository) invalidateAll(hi time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.allGen++
+
+	for symbol, entries := range c.bySymbol {
+		for key, entry := range entries {
+			if overlaps(entry.lo, entry.hi, time.Time{}, hi) {
+				c.remove(symbol, key)
+			}
+		}
+	}
+}
+
+// overlaps reports whether [aLo, aHi] and [bLo, bHi] share an instant,
+// treating zero bounds as open
+func overlaps(aLo, aHi, bLo, bHi time.Time) bool {
+	if !aHi.IsZero() && !bLo.IsZero() && bLo.After(aHi) {
+		return false
+	}
+	if !bHi.IsZero() && !aLo.IsZero() && bHi.Before(aLo) {
+		return false
+	}
+	return true
+}
+
+// copySnapshots returns copies so callers cannot mutate cached results,
+// e.g. by reversing a page in place
+func copySnapshots(snapshots []*domain.PriceSnapshot) []*domain.PriceSnapshot {
+	if snapshots == nil {
+		return nil
+	}
+	copied := make([]*domain.PriceSnapshot, len(snapshots))
+	for i, s := range snapshots {
+		snap := *s
+		copied[i] = &snap
+	}
+	return copied
+}
+
+// PurgingSymbols wraps a symbol repository so that deleting a symbol,
+// which purges its snapshots, also drops its cached history
+func (c *CachedHistoryRepository) PurgingSymbols(repo ports.SymbolRepository) ports.SymbolRepository {
+	return &historyPurgingSymbols{SymbolRepository: repo, cache: c}
+}
+
+// historyPurgingSymbols invalidates the history cache on symbol deletes
+type historyPurgingSymbols struct {
+	ports.SymbolRepository
+	cache *CachedHistoryRepository
+}
+
+// Delete removes a symbol and its snapshots and invalidates its cached
+// history
+func (r *historyPurgingSymbols) Delete(ctx context.Context, name string) error {
+	defer r.cache.invalidate(name, time.Time{}, time.Time{})
+	return r.SymbolRepository.Delete(ctx, name)
+}
+
+// Ensure CachedHistoryRepository implements ports.SnapshotRepository
+var _ ports.SnapshotRepository = (*CachedHistoryRepository)(nil)
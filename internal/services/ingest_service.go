@@ -0,0 +1,91 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+)
+
+// IngestService implements the ports.IngestService interface
+type IngestService struct {
+	symbolRepo   ports.SymbolRepository
+	snapshotRepo ports.SnapshotRepository
+	logger       *slog.Logger
+}
+
+// NewIngestService creates a new ingest service
+func NewIngestService(
+	symbolRepo ports.SymbolRepository,
+	snapshotRepo ports.SnapshotRepository,
+	logger *slog.Logger,
+) *IngestService {
+	return &IngestService{
+		symbolRepo:   symbolRepo,
+		snapshotRepo: snapshotRepo,
+		logger:       logger.With("component", "ingest_service"),
+	}
+}
+
+// Ingest validates and stores pushed snapshots for tracked symbols. The
+// batch is rejected as a whole if any snapshot is invalid or names an
+// untracked symbol; snapshots pushed before are counted as duplicates.
+func (s *IngestService) Ingest(ctx context.Context, snapshots []domain.IngestedSnapshot) (*domain.IngestResult, error) {
+	if len(snapshots) == 0 {
+		return nil, fmt.Errorf("%w: no snapshots", domain.ErrInvalidIngest)
+	}
+	if len(snapshots) > domain.MaxIngestBatch {
+		return nil, fmt.Errorf("%w: at most %d snapshots per request", domain.ErrInvalidIngest, domain.MaxIngestBatch)
+	}
+
+	now := time.Now()
+	symbolIDs := make(map[string]int64)
+	batch := make([]*domain.PriceSnapshot, 0, len(snapshots))
+	for i, in := range snapshots {
+		in.Normalize()
+		if err := in.Validate(now); err != nil {
+			return nil, fmt.Errorf("snapshot %d: %w", i, err)
+		}
+
+		id, ok := symbolIDs[in.Symbol]
+		if !ok {
+			symbol, err := s.symbolRepo.GetByName(ctx, in.Symbol)
+			if errors.Is(err, domain.ErrSymbolNotFound) {
+				return nil, fmt.Errorf("snapshot %d: %w: symbol %s is not tracked", i, domain.ErrInvalidIngest, in.Symbol)
+			}
+			if err != nil {
+				s.logger.Error("failed to look up symbol", "symbol", in.Symbol, "error", err)
+				return nil, domain.ErrInternal
+			}
+			id = symbol.ID
+			symbolIDs[in.Symbol] = id
+		}
+
+		batch = append(batch, &domain.PriceSnapshot{
+			SymbolID:        id,
+			Symbol:          in.Symbol,
+			Price:           in.Price,
+			Timestamp:       in.Timestamp,
+			TimestampSource: domain.TimestampSourceIngest,
+			Source:          in.Source,
+		})
+	}
+
+	stored, err := s.snapshotRepo.CreateBatchIngested(ctx, batch)
+	if err != nil {
+		s.logger.Error("failed to store ingested snapshots", "count", len(batch), "error", err)
+		return nil, domain.ErrInternal
+	}
+
+	result := &domain.IngestResult{Accepted: stored, Duplicates: len(batch) - stored}
+	s.logger.Debug("ingested snapshots", "accepted", result.Accepted, "duplicates", result.Duplicates)
+
+	return result, nil
+}
+
+// Ensure IngestService implements ports.IngestService
+var _ ports.IngestService = (*IngestService)(nil)
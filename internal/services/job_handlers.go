@@ -0,0 +1,353 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+)
+
+// candleIntervals maps supported exchange kline intervals to their duration
+var candleIntervals = map[string]time.Duration{
+	"1m":  time.Minute,
+	"5m":  5 * time.Minute,
+	"15m": 15 * time.Minute,
+	"1h":  time.Hour,
+	"4h":  4 * time.Hour,
+	"1d":  24 * time.Hour,
+}
+
+// BackfillPayload describes a backfill job
+type BackfillPayload struct {
+	Symbol   string    `json:"symbol"`
+	From     time.Time `json:"from"`
+	To       time.Time `json:"to"`
+	Interval string    `json:"interval,omitempty"`
+}
+
+// NewBackfillJob returns a handler that loads historical close prices from the
+// exchange the symbol is polled from and stores them as snapshots. Progress is
+// tracked by a time cursor, so a retried job continues where the previous
+// attempt stopped.
+func NewBackfillJob(
+	exchanges ports.ExchangeRegistry,
+	symbolRepo ports.SymbolRepository,
+	snapshotRepo ports.SnapshotRepository,
+	symbols *domain.SymbolNormalizer,
+) JobHandler {
+	return func(ctx context.Context, job *domain.Job, report func(domain.JobProgress)) error {
+		var payload BackfillPayload
+		if err := json.Unmarshal(job.Payload, &payload); err != nil {
+			return fmt.Errorf("invalid backfill payload: %w", err)
+		}
+
+		payload.Symbol = symbols.Normalize(payload.Symbol)
+		if payload.Interval == "" {
+			payload.Interval = "1m"
+		}
+		step, ok := candleIntervals[payload.Interval]
+		if !ok {
+			return fmt.Errorf("unsupported interval %q", payload.Interval)
+		}
+		if !payload.From.Before(payload.To) {
+			return fmt.Errorf("backfill range is empty")
+		}
+
+		symbol, err := symbolRepo.GetByName(ctx, payload.Symbol)
+		if err != nil {
+			return err
+		}
+		if symbol.Exchange == domain.SyntheticExchange {
+			return fmt.Errorf("%w: %s is synthetic and has no candles to backfill", domain.ErrInvalidSymbol, symbol.Name)
+		}
+
+		// Symbols without an exchange belong to the default one
+		exchange, err := exchanges.Client(strings.ToLower(symbol.Exchange))
+		if err != nil {
+			return err
+		}
+
+		// Backfills only get the exchange budget the poll and API leave over
+		ctx = domain.WithExchangePriority(ctx, domain.ExchangePriorityBackfill)
+
+		progress := job.Progress
+		progress.Total = int64(payload.To.Sub(payload.From) / step)
+
+		cursor := payload.From
+		if progress.Cursor != "" {
+			if resumed, err := time.Parse(time.RFC3339, progress.Cursor); err == nil {
+				cursor = resumed
+			}
+		}
+
+		for cursor.Before(payload.To) {
+			candles, err := exchange.GetCandles(ctx, symbol.Name, payload.Interval, cursor, payload.To, 0)
+			if err != nil {
+				return err
+			}
+			if len(candles) == 0 {
+				break
+			}
+
+			snapshots := make([]*domain.PriceSnapshot, len(candles))
+			for i, c := range candles {
+				snapshots[i] = &domain.PriceSnapshot{
+					SymbolID:        symbol.ID,
+					Symbol:          symbol.Name,
+					Price:           c.Close,
+					Timestamp:       c.OpenTime,
+					TimestampSource: domain.TimestampSourceExchange,
+				}
+			}
+
+			if err := snapshotRepo.CreateBatch(ctx, snapshots); err != nil {
+				return err
+			}
+
+			cursor = candles[len(candles)-1].OpenTime.Add(step)
+			progress.Processed += int64(len(candles))
+			progress.Cursor = cursor.Format(time.RFC3339)
+			report(progress)
+		}
+
+		return nil
+	}
+}
+
+// ImportPayload describes an import job
+type ImportPayload struct {
+	Snapshots []ImportRow `json:"snapshots"`
+}
+
+// ImportRow is a single price point to import
+type ImportRow struct {
+	Symbol    string          `json:"symbol"`
+	Price     decimal.Decimal `json:"price"`
+	Timestamp time.Time       `json:"ts"`
+}
+
+// importBatchSize is the number of rows written per transaction
+const importBatchSize = 500
+
+// NewImportJob returns a handler that stores externally supplied snapshots for
+// tracked symbols. Progress is tracked by row offset. A finished import is
+// reported to maintenance, which may be nil.
+func NewImportJob(
+	symbolRepo ports.SymbolRepository,
+	snapshotRepo ports.SnapshotRepository,
+	symbols *domain.SymbolNormalizer,
+	maintenance ports.MaintenanceService,
+) JobHandler {
+	return func(ctx context.Context, job *domain.Job, report func(domain.JobProgress)) error {
+		var payload ImportPayload
+		if err := json.Unmarshal(job.Payload, &payload); err != nil {
+			return fmt.Errorf("invalid import payload: %w", err)
+		}
+
+		progress := job.Progress
+		progress.Total = int64(len(payload.Snapshots))
+
+		offset := 0
+		if progress.Cursor != "" {
+			if resumed, err := strconv.Atoi(progress.Cursor); err == nil {
+				offset = resumed
+			}
+		}
+
+		symbolIDs := make(map[string]int64)
+		for offset < len(payload.Snapshots) {
+			end := offset + importBatchSize
+			if end > len(payload.Snapshots) {
+				end = len(payload.Snapshots)
+			}
+
+			batch := make([]*domain.PriceSnapshot, 0, end-offset)
+			for _, row := range payload.Snapshots[offset:end] {
+				name := symbols.Normalize(row.Symbol)
+
+				id, ok := symbolIDs[name]
+				if !ok {
+					symbol, err := symbolRepo.GetByName(ctx, name)
+					if err != nil {
+						return fmt.Errorf("import row for %s: %w", name, err)
+					}
+					id = symbol.ID
+					symbolIDs[name] = id
+				}
+
+				batch = append(batch, &domain.PriceSnapshot{
+					SymbolID:        id,
+					Symbol:          name,
+					Price:           row.Price,
+					Timestamp:       row.Timestamp.UTC(),
+					TimestampSource: domain.TimestampSourceImport,
+				})
+			}
+
+			if err := snapshotRepo.CreateBatch(ctx, batch); err != nil {
+				return err
+			}
+
+			progress.Processed += int64(len(batch))
+			offset = end
+			progress.Cursor = strconv.Itoa(offset)
+			report(progress)
+		}
+
+		if maintenance != nil {
+			maintenance.RowsChanged(ctx, "import", progress.Processed)
+		}
+		return nil
+	}
+}
+
+// NewRebuildCacheJob returns a handler that reloads the symbol cache
+func NewRebuildCacheJob(cache *CachedSymbolRepository) JobHandler {
+	return func(ctx context.Context, job *domain.Job, report func(domain.JobProgress)) error {
+		if cache == nil {
+			return nil
+		}
+
+		loaded, err := cache.Reload(ctx)
+		if err != nil {
+			return err
+		}
+
+		report(domain.JobProgress{Processed: int64(loaded), Total: int64(loaded)})
+		return nil
+	}
+}
+
+// NewRevalidateJob returns a handler that re-verifies tracked symbols against the exchange
+func NewRevalidateJob(warmup ports.WarmupService) JobHandler {
+	return func(ctx context.Context, job *domain.Job, report func(domain.JobProgress)) error {
+		result, err := warmup.Warmup(ctx)
+		if err != nil {
+			return err
+		}
+
+		report(domain.JobProgress{
+			Processed: int64(result.CheckedSymbols),
+			Total:     int64(result.CheckedSymbols),
+		})
+		return nil
+	}
+}
+
+// NewPruneJob returns a handler that runs retention pruning on demand
+func NewPruneJob(pruner ports.PrunerService) JobHandler {
+	return func(ctx context.Context, job *domain.Job, report func(domain.JobProgress)) error {
+		result, err := pruner.PruneExpired(ctx)
+		if err != nil {
+			return err
+		}
+
+		report(domain.JobProgress{Processed: result.Deleted})
+		if len(result.Failed) > 0 {
+			return fmt.Errorf("failed to prune %d symbols", len(result.Failed))
+		}
+		return nil
+	}
+}
+
+// NewMaintenanceJob returns a handler that analyzes the snapshot tables on
+// demand
+func NewMaintenanceJob(maintenance ports.MaintenanceService) JobHandler {
+	return func(ctx context.Context, job *domain.Job, report func(domain.JobProgress)) error {
+		stats, err := maintenance.Analyze(ctx)
+		if err != nil {
+			return err
+		}
+
+		report(domain.JobProgress{Processed: int64(len(stats)), Total: int64(len(stats))})
+		return nil
+	}
+}
+
+// NewRefreshMetadataJob returns a handler that refreshes symbol metadata on demand
+func NewRefreshMetadataJob(metadata ports.MetadataService) JobHandler {
+	return func(ctx context.Context, job *domain.Job, report func(domain.JobProgress)) error {
+		enriched, err := metadata.RefreshMetadata(ctx)
+		if err != nil {
+			return err
+		}
+
+		report(domain.JobProgress{Processed: int64(enriched)})
+		return nil
+	}
+}
+
+// AlertWebhookPayload describes an alert-webhook job
+type AlertWebhookPayload struct {
+	AlertID int64              `json:"alert_id"`
+	Firing  domain.AlertFiring `json:"firing"`
+}
+
+// NewAlertWebhookJob returns a handler that posts an alert firing to the
+// alert's webhook. A failed call fails the job so the queue retries it; the
+// delivery state is only recorded while the firing is the alert's latest.
+// A watch is removed once its call succeeded or ran out of attempts.
+func NewAlertWebhookJob(alerts ports.AlertRepository, notifier ports.AlertNotifier) JobHandler {
+	return func(ctx context.Context, job *domain.Job, report func(domain.JobProgress)) error {
+		var payload AlertWebhookPayload
+		if err := json.Unmarshal(job.Payload, &payload); err != nil {
+			return fmt.Errorf("invalid alert webhook payload: %w", err)
+		}
+
+		alert, err := alerts.GetByID(ctx, payload.AlertID)
+		if errors.Is(err, domain.ErrAlertNotFound) {
+			// Deleted since it fired; nothing left to notify
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		latest := alert.LastFiring != nil && alert.LastFiring.At.Equal(payload.Firing.At)
+		record := func(delivery domain.AlertDelivery, deliveryErr string) error {
+			if !latest {
+				return nil
+			}
+			return alerts.RecordDelivery(ctx, alert.ID, delivery, deliveryErr)
+		}
+
+		notifyErr := notifier.NotifyAlert(ctx, alert.WebhookURL, &domain.AlertNotification{
+			AlertID: alert.ID,
+			Rule:    alert.Rule,
+			Firing:  payload.Firing,
+		})
+		if notifyErr != nil {
+			final := job.Attempts >= job.MaxAttempts
+			if final && alert.Once {
+				return errors.Join(notifyErr, alerts.Delete(ctx, alert.ID))
+			}
+
+			delivery := domain.AlertDeliveryPending
+			if final {
+				delivery = domain.AlertDeliveryFailed
+			}
+			if err := record(delivery, notifyErr.Error()); err != nil {
+				return errors.Join(notifyErr, err)
+			}
+			return notifyErr
+		}
+
+		report(domain.JobProgress{Processed: 1, Total: 1})
+
+		// The webhook has the firing; failing the job now would post it twice
+		if alert.Once {
+			_ = alerts.Delete(ctx, alert.ID)
+		} else {
+			_ = record(domain.AlertDeliveryDelivered, "")
+		}
+		return nil
+	}
+}
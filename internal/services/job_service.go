@@ -0,0 +1,288 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+)
+
+// JobHandler executes a job. It receives the job as claimed (including any
+// progress recorded by an earlier attempt) and reports progress as it goes.
+type JobHandler func(ctx context.Context, job *domain.Job, report func(domain.JobProgress)) error
+
+// JobService implements the ports.JobService and ports.JobExecutor interfaces
+type JobService struct {
+	repo         ports.JobRepository
+	handlers     map[domain.JobType]JobHandler
+	maxAttempts  int
+	retryBackoff time.Duration
+	leaseTimeout time.Duration
+	logger       *slog.Logger
+
+	mu      sync.Mutex
+	running map[int64]context.CancelFunc
+}
+
+// NewJobService creates a new job service. A running job whose worker has
+// not sent a heartbeat for leaseTimeout, e.g. after a crash or restart, is
+// claimed again.
+func NewJobService(
+	repo ports.JobRepository,
+	maxAttempts int,
+	retryBackoff time.Duration,
+	leaseTimeout time.Duration,
+	logger *slog.Logger,
+) *JobService {
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+
+	return &JobService{
+		repo:         repo,
+		handlers:     make(map[domain.JobType]JobHandler),
+		maxAttempts:  maxAttempts,
+		retryBackoff: retryBackoff,
+		leaseTimeout: leaseTimeout,
+		logger:       logger.With("component", "job_service"),
+		running:      make(map[int64]context.CancelFunc),
+	}
+}
+
+// Register sets the handler for a job type. Must be called before the runner starts.
+func (s *JobService) Register(jobType domain.JobType, handler JobHandler) {
+	s.handlers[jobType] = handler
+}
+
+// Enqueue schedules a job to run at runAt (immediately when zero)
+func (s *JobService) Enqueue(ctx context.Context, jobType domain.JobType, payload json.RawMessage, runAt time.Time) (*domain.Job, error) {
+	job, err := domain.NewJob(jobType, payload, runAt, s.maxAttempts)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := s.handlers[jobType]; !ok {
+		return nil, domain.ErrInvalidJobType
+	}
+
+	if err := s.repo.Create(ctx, job); err != nil {
+		s.logger.Error("failed to enqueue job", "type", jobType, "error", err)
+		return nil, domain.ErrInternal
+	}
+
+	s.logger.Info("job enqueued", "id", job.ID, "type", jobType, "scheduled_at", job.ScheduledAt)
+	return job, nil
+}
+
+// GetJob retrieves a job by ID
+func (s *JobService) GetJob(ctx context.Context, id int64) (*domain.Job, error) {
+	job, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, domain.ErrJobNotFound) {
+			return nil, err
+		}
+		s.logger.Error("failed to get job", "id", id, "error", err)
+		return nil, domain.ErrInternal
+	}
+	return job, nil
+}
+
+// ListJobs returns recent jobs, optionally filtered by status
+func (s *JobService) ListJobs(ctx context.Context, status domain.JobStatus, limit int) ([]*domain.Job, error) {
+	jobs, err := s.repo.List(ctx, status, limit)
+	if err != nil {
+		s.logger.Error("failed to list jobs", "error", err)
+		return nil, domain.ErrInternal
+	}
+	return jobs, nil
+}
+
+// CancelJob cancels a pending or running job. A job running in this process
+// has its context cancelled; progress recorded so far is kept for ResumeJob.
+func (s *JobService) CancelJob(ctx context.Context, id int64) (*domain.Job, error) {
+	if err := s.repo.Cancel(ctx, id); err != nil {
+		if errors.Is(err, domain.ErrJobNotFound) || errors.Is(err, domain.ErrJobFinished) {
+			return nil, err
+		}
+		s.logger.Error("failed to cancel job", "id", id, "error", err)
+		return nil, domain.ErrInternal
+	}
+
+	s.mu.Lock()
+	cancel, ok := s.running[id]
+	s.mu.Unlock()
+	if ok {
+		cancel()
+	}
+
+	s.logger.Info("job cancelled", "id", id, "was_running_locally", ok)
+	return s.GetJob(ctx, id)
+}
+
+// ResumeJob requeues a cancelled or failed job from its recorded progress
+func (s *JobService) ResumeJob(ctx context.Context, id int64) (*domain.Job, error) {
+	if err := s.repo.Requeue(ctx, id); err != nil {
+		if errors.Is(err, domain.ErrJobNotFound) || errors.Is(err, domain.ErrJobNotResumable) {
+			return nil, err
+		}
+		s.logger.Error("failed to resume job", "id", id, "error", err)
+		return nil, domain.ErrInternal
+	}
+
+	s.logger.Info("job resumed", "id", id)
+	return s.GetJob(ctx, id)
+}
+
+// RunNext claims and executes the next due job.
+// Returns false when no job was due.
+func (s *JobService) RunNext(ctx context.Context) (bool, error) {
+	job, err := s.repo.ClaimNext(ctx, s.leaseTimeout)
+	if err != nil {
+		return false, err
+	}
+	if job == nil {
+		return false, nil
+	}
+
+	s.execute(ctx, job)
+	return true, nil
+}
+
+func (s *JobService) execute(ctx context.Context, job *domain.Job) {
+	logger := s.logger.With("job_id", job.ID, "type", job.Type, "attempt", job.Attempts)
+	logger.Info("job started")
+
+	jobCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	report := func(progress domain.JobProgress) {
+		job.Progress = progress
+		err := s.repo.UpdateProgress(ctx, job.ID, job.Attempts, progress)
+		if stopped(err) {
+			logger.Info("job cancelled or reclaimed elsewhere, stopping", "reason", err)
+			cancel()
+		} else if err != nil {
+			logger.Warn("failed to record job progress", "error", err)
+		}
+	}
+
+	s.mu.Lock()
+	s.running[job.ID] = cancel
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.running, job.ID)
+		s.mu.Unlock()
+	}()
+
+	heartbeatCtx, stopHeartbeat := context.WithCancel(jobCtx)
+	go s.heartbeat(heartbeatCtx, job, cancel, logger)
+
+	var runErr error
+	handler, ok := s.handlers[job.Type]
+	if !ok {
+		runErr = fmt.Errorf("no handler registered for job type %q", job.Type)
+	} else {
+		runErr = s.run(jobCtx, handler, job, report)
+	}
+	stopHeartbeat()
+
+	// Cancelled through CancelJob, or stopped after losing the lease: the
+	// repository already holds the cancelled state or the attempt that
+	// replaced this one
+	if jobCtx.Err() != nil && ctx.Err() == nil {
+		logger.Info("job stopped after cancellation", "processed", job.Progress.Processed)
+		return
+	}
+
+	now := time.Now().UTC()
+	switch {
+	case runErr == nil:
+		job.Status = domain.JobStatusSucceeded
+		job.Error = ""
+		job.FinishedAt = &now
+		logger.Info("job succeeded", "processed", job.Progress.Processed)
+
+	case job.Attempts < job.MaxAttempts:
+		job.Status = domain.JobStatusPending
+		job.Error = runErr.Error()
+		job.ScheduledAt = now.Add(s.retryBackoff * time.Duration(job.Attempts))
+		logger.Warn("job failed, will retry", "error", runErr, "retry_at", job.ScheduledAt)
+
+	default:
+		job.Status = domain.JobStatusFailed
+		job.Error = runErr.Error()
+		job.FinishedAt = &now
+		logger.Error("job failed", "error", runErr)
+	}
+
+	// Use a fresh context so the outcome is recorded even during shutdown
+	finishCtx, finishCancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer finishCancel()
+
+	if err := s.repo.Finish(finishCtx, job); errors.Is(err, domain.ErrJobLeaseLost) {
+		logger.Warn("job outcome discarded, another worker claimed it", "status", job.Status)
+	} else if err != nil {
+		logger.Error("failed to record job outcome", "error", err)
+	}
+}
+
+// heartbeat renews a job's lease three times per lease timeout until ctx
+// is done, so that a job outliving the timeout is not claimed again. When
+// the job was cancelled through another replica, or another worker has
+// claimed it after all, e.g. after a long pause, it stops the job through
+// cancel.
+func (s *JobService) heartbeat(ctx context.Context, job *domain.Job, cancel context.CancelFunc, logger *slog.Logger) {
+	ticker := time.NewTicker(s.leaseTimeout / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			err := s.repo.Heartbeat(ctx, job.ID, job.Attempts)
+			switch {
+			case err == nil || ctx.Err() != nil:
+			case stopped(err):
+				logger.Info("job cancelled or reclaimed elsewhere, stopping", "reason", err)
+				cancel()
+				return
+			default:
+				logger.Warn("failed to record job heartbeat", "error", err)
+			}
+		}
+	}
+}
+
+// stopped reports whether a fenced job update failed because the attempt
+// must stop: the job was cancelled, possibly through another replica, was
+// deleted, or was claimed by another worker
+func stopped(err error) bool {
+	return errors.Is(err, domain.ErrJobCancelled) ||
+		errors.Is(err, domain.ErrJobNotFound) ||
+		errors.Is(err, domain.ErrJobLeaseLost)
+}
+
+// run invokes the handler, converting a panic into a job failure
+func (s *JobService) run(ctx context.Context, handler JobHandler, job *domain.Job, report func(domain.JobProgress)) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panicked: %v", r)
+		}
+	}()
+	return handler(ctx, job, report)
+}
+
+// Ensure JobService implements the job ports
+var (
+	_ ports.JobService  = (*JobService)(nil)
+	_ ports.JobExecutor = (*JobService)(nil)
+)
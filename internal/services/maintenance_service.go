@@ -0,0 +1,80 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+)
+
+// bloatWarnRatio is the share of dead rows past which a table is reported
+// as bloated
+const bloatWarnRatio = 0.2
+
+// MaintenanceService implements the ports.MaintenanceService interface
+type MaintenanceService struct {
+	analyzer     ports.SnapshotTableAnalyzer
+	analyzeAfter int64
+	logger       *slog.Logger
+
+	// mu keeps a prune and an import finishing together from analyzing
+	// the tables twice at once
+	mu sync.Mutex
+}
+
+// NewMaintenanceService creates a new maintenance service. A prune or
+// import changing at least analyzeAfter rows analyzes the snapshot tables;
+// 0 only analyzes them when asked to.
+func NewMaintenanceService(analyzer ports.SnapshotTableAnalyzer, analyzeAfter int64, logger *slog.Logger) *MaintenanceService {
+	return &MaintenanceService{
+		analyzer:     analyzer,
+		analyzeAfter: analyzeAfter,
+		logger:       logger.With("component", "maintenance_service"),
+	}
+}
+
+// Analyze refreshes the planner statistics of the snapshot tables and logs
+// their dead rows, warning about tables bloated enough to need a VACUUM
+func (m *MaintenanceService) Analyze(ctx context.Context) ([]domain.TableStats, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stats, err := m.analyzer.AnalyzeSnapshots(ctx)
+	if err != nil {
+		m.logger.Error("failed to analyze snapshot tables", "error", err)
+		return nil, err
+	}
+
+	for _, s := range stats {
+		attrs := []interface{}{
+			"table", s.Table,
+			"live_rows", s.LiveRows,
+			"dead_rows", s.DeadRows,
+			"dead_ratio", s.DeadRatio(),
+		}
+		if s.DeadRatio() >= bloatWarnRatio {
+			m.logger.Warn("table is bloated, consider VACUUM", attrs...)
+		} else {
+			m.logger.Info("analyzed table", attrs...)
+		}
+	}
+
+	return stats, nil
+}
+
+// RowsChanged analyzes the snapshot tables when rows reaches the configured
+// threshold. Failures are logged; the operation that changed the rows has
+// already succeeded.
+func (m *MaintenanceService) RowsChanged(ctx context.Context, reason string, rows int64) {
+	if m.analyzeAfter <= 0 || rows < m.analyzeAfter {
+		return
+	}
+
+	m.logger.Info("analyzing snapshot tables after large change", "reason", reason, "rows", rows)
+	_, _ = m.Analyze(ctx)
+}
+
+// Ensure MaintenanceService implements ports.MaintenanceService
+var _ ports.MaintenanceService = (*MaintenanceService)(nil)
@@ -0,0 +1,74 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+)
+
+// MetadataService implements the ports.MetadataService interface by looking
+// up each symbol's base asset with a market data provider
+type MetadataService struct {
+	symbolRepo ports.SymbolRepository
+	provider   ports.CoinMetadataProvider
+	logger     *slog.Logger
+}
+
+// NewMetadataService creates a new metadata service
+func NewMetadataService(symbolRepo ports.SymbolRepository, provider ports.CoinMetadataProvider, logger *slog.Logger) *MetadataService {
+	return &MetadataService{
+		symbolRepo: symbolRepo,
+		provider:   provider,
+		logger:     logger.With("component", "metadata_service"),
+	}
+}
+
+// RefreshMetadata updates the coin metadata of every tracked symbol. Symbols
+// whose base asset the provider does not know keep their previous metadata.
+func (s *MetadataService) RefreshMetadata(ctx context.Context) (int, error) {
+	symbols, err := s.symbolRepo.List(ctx)
+	if err != nil {
+		s.logger.Error("failed to list symbols", "error", err)
+		return 0, domain.ErrInternal
+	}
+	if len(symbols) == 0 {
+		return 0, nil
+	}
+
+	seen := make(map[string]bool, len(symbols))
+	assets := make([]string, 0, len(symbols))
+	for _, sym := range symbols {
+		asset := domain.BaseAsset(sym.Name)
+		if !seen[asset] {
+			seen[asset] = true
+			assets = append(assets, asset)
+		}
+	}
+
+	metadata, err := s.provider.GetCoinMetadata(ctx, assets)
+	if err != nil {
+		s.logger.Error("failed to fetch coin metadata", "error", err)
+		return 0, domain.ErrExchangeUnavailable
+	}
+
+	enriched := 0
+	for _, sym := range symbols {
+		m, ok := metadata[domain.BaseAsset(sym.Name)]
+		if !ok {
+			continue
+		}
+		if err := s.symbolRepo.UpdateMetadata(ctx, sym.Name, m); err != nil {
+			s.logger.Warn("failed to store symbol metadata", "symbol", sym.Name, "error", err)
+			continue
+		}
+		enriched++
+	}
+
+	s.logger.Info("symbol metadata refreshed", "symbols", len(symbols), "enriched", enriched)
+	return enriched, nil
+}
+
+// Ensure MetadataService implements ports.MetadataService
+var _ ports.MetadataService = (*MetadataService)(nil)
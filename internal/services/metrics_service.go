@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 	"log/slog"
+	"sort"
 	"sync"
 	"time"
 
@@ -14,8 +15,19 @@ import (
 type MetricsService struct {
 	symbolRepo   ports.SymbolRepository
 	snapshotRepo ports.SnapshotRepository
-	exchange     ports.ExchangeClient
+	health       ports.HealthService
+	errors       *ExchangeErrorCounter
+	slowQueries  *SlowQueryCounter
+	retries      *ExchangeRetryCounter
+	clock        ports.Clock
 	startTime    time.Time
+	build        *domain.BuildInfo
+	labels       map[string]string
+	config       *domain.EffectiveConfig
+	symbolLimits *domain.SymbolLimits
+	runtimeStats bool
+	pool         ports.PoolStatsProvider
+	budget       ports.ExchangeBudgetStatsProvider
 	logger       *slog.Logger
 
 	mu               sync.RWMutex
@@ -24,24 +36,106 @@ type MetricsService struct {
 	pollSuccessCount int64
 	pollErrorCount   int64
 	totalPollTime    time.Duration
+	prunedSnapshots  int64
+	lastPruneTime    *time.Time
+	partialPolls     int64
+	writeFailures    int64
+	groups           map[string]*domain.PollerGroupMetrics
+	pollSymbols      map[string]*domain.PollSymbols
+	stages           map[domain.PollStage]*stageTimes
+	pollDurations    *domain.Histogram
+	httpRequests     map[httpRequestKey]*domain.HTTPRequestMetrics
 }
 
-// NewMetricsService creates a new metrics service
+// httpRequestKey identifies the requests sharing a latency histogram
+type httpRequestKey struct {
+	route  string
+	method string
+	code   int
+}
+
+// stageTimes accumulates the durations of one poll stage
+type stageTimes struct {
+	count    int64
+	last     time.Duration
+	max      time.Duration
+	total    time.Duration
+	timeouts int64
+}
+
+// NewMetricsService creates a new metrics service. Database and exchange
+// status come from health. When exchangeErrors or slowQueries is non-nil, its counters are reported
+// in the metrics. Uptime and poll and prune times are read from clock.
 func NewMetricsService(
 	symbolRepo ports.SymbolRepository,
 	snapshotRepo ports.SnapshotRepository,
-	exchange ports.ExchangeClient,
+	health ports.HealthService,
+	exchangeErrors *ExchangeErrorCounter,
+	slowQueries *SlowQueryCounter,
+	clock ports.Clock,
 	logger *slog.Logger,
 ) *MetricsService {
 	return &MetricsService{
 		symbolRepo:   symbolRepo,
 		snapshotRepo: snapshotRepo,
-		exchange:     exchange,
-		startTime:    time.Now(),
+		health:       health,
+		errors:       exchangeErrors,
+		slowQueries:  slowQueries,
+		clock:        clock,
+		startTime:    clock.Now(),
 		logger:       logger.With("component", "metrics_service"),
+
+		pollDurations: domain.NewHistogram(domain.PollDurationBuckets),
+		httpRequests:  make(map[httpRequestKey]*domain.HTTPRequestMetrics),
+	}
+}
+
+// SetTarget sets the build info and constant labels reported with the
+// metrics. It must be called before the metrics are served.
+func (m *MetricsService) SetTarget(build domain.BuildInfo, labels map[string]string) {
+	m.build = &build
+	m.labels = labels
+}
+
+// SetConfig sets the effective configuration reported with the metrics. It
+// must be called before the metrics are served.
+func (m *MetricsService) SetConfig(config domain.EffectiveConfig) {
+	m.config = &config
+}
+
+// SetSymbolLimits sets the symbol caps reported next to the symbol counts.
+// It must be called before the metrics are served.
+func (m *MetricsService) SetSymbolLimits(limits domain.SymbolLimits) {
+	if limits.IsSet() {
+		m.symbolLimits = &limits
 	}
 }
 
+// EnableRuntimeStats adds Go runtime stats to the metrics. It must be
+// called before the metrics are served.
+func (m *MetricsService) EnableRuntimeStats() {
+	m.runtimeStats = true
+}
+
+// SetExchangeRetries adds the exchange request retries and attempts per
+// call counted by retries to the metrics. It must be called before the
+// metrics are served.
+func (m *MetricsService) SetExchangeRetries(retries *ExchangeRetryCounter) {
+	m.retries = retries
+}
+
+// SetPoolStats adds database connection pool usage to the metrics. It must
+// be called before the metrics are served.
+func (m *MetricsService) SetPoolStats(pool ports.PoolStatsProvider) {
+	m.pool = pool
+}
+
+// SetExchangeBudget adds the use of the exchange request budget to the
+// metrics. It must be called before the metrics are served.
+func (m *MetricsService) SetExchangeBudget(budget ports.ExchangeBudgetStatsProvider) {
+	m.budget = budget
+}
+
 // GetMetrics returns current operational metrics
 func (m *MetricsService) GetMetrics(ctx context.Context) (*domain.Metrics, error) {
 	m.mu.RLock()
@@ -49,8 +143,47 @@ func (m *MetricsService) GetMetrics(ctx context.Context) (*domain.Metrics, error
 	lastPollDuration := m.lastPollDuration
 	pollSuccessCount := m.pollSuccessCount
 	pollErrorCount := m.pollErrorCount
+	prunedSnapshots := m.prunedSnapshots
+	lastPruneTime := m.lastPruneTime
+	partialPolls := m.partialPolls
+	writeFailures := m.writeFailures
+	groups := make([]domain.PollerGroupMetrics, 0, len(m.groups))
+	for _, g := range m.groups {
+		groups = append(groups, *g)
+	}
+	stages := make([]domain.PollStageMetrics, 0, len(m.stages))
+	for stage, t := range m.stages {
+		stages = append(stages, domain.PollStageMetrics{
+			Stage:          stage,
+			Count:          t.count,
+			LastDurationMs: durationMs(t.last),
+			MaxDurationMs:  durationMs(t.max),
+			AvgDurationMs:  durationMs(t.total / time.Duration(t.count)),
+			Timeouts:       t.timeouts,
+		})
+	}
+	pollDurations := m.pollDurations.Clone()
+	requests := make([]domain.HTTPRequestMetrics, 0, len(m.httpRequests))
+	for _, r := range m.httpRequests {
+		c := *r
+		c.Duration = r.Duration.Clone()
+		requests = append(requests, c)
+	}
 	m.mu.RUnlock()
 
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Name < groups[j].Name })
+	sort.Slice(stages, func(i, j int) bool { return stages[i].Stage < stages[j].Stage })
+	sort.Slice(requests, func(i, j int) bool {
+		a, b := requests[i], requests[j]
+		if a.Route != b.Route {
+			return a.Route < b.Route
+		}
+		if a.Method != b.Method {
+			return a.Method < b.Method
+		}
+		return a.Code < b.Code
+	})
+
 	// Get symbol counts
 	totalSymbols, err := m.symbolRepo.Count(ctx)
 	if err != nil {
@@ -71,54 +204,230 @@ func (m *MetricsService) GetMetrics(ctx context.Context) (*domain.Metrics, error
 		totalSnapshots = 0
 	}
 
-	// Check database status
-	dbStatus := "healthy"
-	if err := m.checkDatabaseHealth(ctx); err != nil {
-		dbStatus = "unhealthy"
+	symbolSnapshots, err := m.snapshotRepo.CountPerSymbol(ctx)
+	if err != nil {
+		m.logger.Error("failed to count snapshots per symbol", "error", err)
+		symbolSnapshots = nil
+	}
+
+	dbStatus, exchangeStatus := ports.HealthUnhealthy, ports.HealthUnhealthy
+	if health, err := m.health.CheckHealth(ctx); err != nil {
+		m.logger.Error("failed to check health", "error", err)
+	} else {
+		dbStatus, exchangeStatus = health.Database, health.Exchange
+	}
+
+	var exchangeErrors []domain.ExchangeErrorCount
+	if m.errors != nil {
+		exchangeErrors = m.errors.Counts()
+	}
+
+	var exchangeRetries []domain.ExchangeRetryCount
+	var exchangeAttempts []domain.ExchangeCallAttempts
+	if m.retries != nil {
+		exchangeRetries = m.retries.Retries()
+		exchangeAttempts = m.retries.Attempts()
+	}
+
+	var slowQueries []domain.SlowQueryCount
+	if m.slowQueries != nil {
+		slowQueries = m.slowQueries.Counts()
+	}
+
+	var poolStats *domain.DatabasePoolStats
+	if m.pool != nil {
+		poolStats = m.pool.PoolStats()
 	}
 
-	// Check exchange status
-	exchangeStatus := "healthy"
-	if err := m.exchange.Ping(ctx); err != nil {
-		exchangeStatus = "unhealthy"
+	var budgetStats *domain.ExchangeBudgetStats
+	if m.budget != nil {
+		budgetStats = m.budget.BudgetStats()
+	}
+
+	var runtimeStats *domain.RuntimeStats
+	if m.runtimeStats {
+		runtimeStats = readRuntimeStats()
 	}
 
 	return &domain.Metrics{
-		Uptime:           time.Since(m.startTime).Seconds(),
+		Uptime:           m.clock.Since(m.startTime).Seconds(),
 		TrackedSymbols:   totalSymbols,
 		ActiveSymbols:    activeSymbols,
+		SymbolLimits:     m.symbolLimits,
 		TotalSnapshots:   totalSnapshots,
 		LastPollTime:     lastPollTime,
 		LastPollDuration: float64(lastPollDuration.Milliseconds()),
 		PollSuccessCount: pollSuccessCount,
 		PollErrorCount:   pollErrorCount,
-		DatabaseStatus:   dbStatus,
-		ExchangeStatus:   exchangeStatus,
+		PrunedSnapshots:  prunedSnapshots,
+		LastPruneTime:    lastPruneTime,
+
+		PartialPolls:          partialPolls,
+		SnapshotWriteFailures: writeFailures,
+
+		DatabaseStatus: dbStatus,
+		ExchangeStatus: exchangeStatus,
+		ExchangeErrors: exchangeErrors,
+		SlowQueries:    slowQueries,
+		PollerGroups:   groups,
+		PollStages:     stages,
+		Runtime:        runtimeStats,
+
+		ExchangeRetries:  exchangeRetries,
+		ExchangeAttempts: exchangeAttempts,
+		ExchangeBudget:   budgetStats,
+
+		PollDuration:    pollDurations,
+		HTTPRequests:    requests,
+		DatabasePool:    poolStats,
+		SymbolSnapshots: symbolSnapshots,
+
+		Build:  m.build,
+		Labels: m.labels,
+		Config: m.config,
 	}, nil
 }
 
 // RecordPollSuccess records a successful poll
-func (m *MetricsService) RecordPollSuccess(duration time.Duration) {
+func (m *MetricsService) RecordPollSuccess(group string, duration time.Duration) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	now := time.Now()
+	now := m.clock.Now()
 	m.lastPollTime = &now
 	m.lastPollDuration = duration
 	m.pollSuccessCount++
 	m.totalPollTime += duration
+	m.pollDurations.Observe(duration.Seconds())
+
+	if g := m.group(group); g != nil {
+		g.LastPollTime = &now
+		g.LastPollDuration = float64(duration.Milliseconds())
+		g.PollSuccessCount++
+	}
 }
 
 // RecordPollError records a failed poll
-func (m *MetricsService) RecordPollError(duration time.Duration) {
+func (m *MetricsService) RecordPollError(group string, duration time.Duration) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	now := time.Now()
+	now := m.clock.Now()
 	m.lastPollTime = &now
 	m.lastPollDuration = duration
 	m.pollErrorCount++
 	m.totalPollTime += duration
+	m.pollDurations.Observe(duration.Seconds())
+
+	if g := m.group(group); g != nil {
+		g.LastPollTime = &now
+		g.LastPollDuration = float64(duration.Milliseconds())
+		g.PollErrorCount++
+	}
+}
+
+// RecordHTTPRequest records the latency of a served HTTP request
+func (m *MetricsService) RecordHTTPRequest(route, method string, code int, duration time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := httpRequestKey{route: route, method: method, code: code}
+	r, ok := m.httpRequests[key]
+	if !ok {
+		r = &domain.HTTPRequestMetrics{
+			Route:    route,
+			Method:   method,
+			Code:     code,
+			Duration: domain.NewHistogram(domain.HTTPDurationBuckets),
+		}
+		m.httpRequests[key] = r
+	}
+	r.Duration.Observe(duration.Seconds())
+}
+
+// RecordSnapshotWriteFailures records snapshots a poll could not store
+func (m *MetricsService) RecordSnapshotWriteFailures(failed int) {
+	if failed <= 0 {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.partialPolls++
+	m.writeFailures += int64(failed)
+}
+
+// RecordPollStage records the duration of one poll stage
+func (m *MetricsService) RecordPollStage(stage domain.PollStage, duration time.Duration, timedOut bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.stages == nil {
+		m.stages = make(map[domain.PollStage]*stageTimes)
+	}
+	t, ok := m.stages[stage]
+	if !ok {
+		t = &stageTimes{}
+		m.stages[stage] = t
+	}
+
+	t.count++
+	t.last = duration
+	t.total += duration
+	if duration > t.max {
+		t.max = duration
+	}
+	if timedOut {
+		t.timeouts++
+	}
+}
+
+// durationMs converts a duration to fractional milliseconds
+func durationMs(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+// group returns the metrics of a named poller group, creating them on first
+// use. It returns nil for the unnamed default poller. Callers must hold mu.
+func (m *MetricsService) group(name string) *domain.PollerGroupMetrics {
+	if name == "" {
+		return nil
+	}
+	if m.groups == nil {
+		m.groups = make(map[string]*domain.PollerGroupMetrics)
+	}
+	g, ok := m.groups[name]
+	if !ok {
+		g = &domain.PollerGroupMetrics{Name: name}
+		m.groups[name] = g
+	}
+	return g
+}
+
+// RecordPollSymbols records the effective symbol list of a poll
+func (m *MetricsService) RecordPollSymbols(run *domain.PollSymbols) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.pollSymbols == nil {
+		m.pollSymbols = make(map[string]*domain.PollSymbols)
+	}
+	m.pollSymbols[run.Group] = run
+}
+
+// GetPollSymbols returns the effective symbol list of each poller group's
+// most recent poll
+func (m *MetricsService) GetPollSymbols() []*domain.PollSymbols {
+	m.mu.RLock()
+	runs := make([]*domain.PollSymbols, 0, len(m.pollSymbols))
+	for _, run := range m.pollSymbols {
+		runs = append(runs, run)
+	}
+	m.mu.RUnlock()
+
+	sort.Slice(runs, func(i, j int) bool { return runs[i].Group < runs[j].Group })
+	return runs
 }
 
 // GetLastPollTime returns the time of the last poll
@@ -128,11 +437,19 @@ func (m *MetricsService) GetLastPollTime() *time.Time {
 	return m.lastPollTime
 }
 
-func (m *MetricsService) checkDatabaseHealth(ctx context.Context) error {
-	// Simple health check - count symbols
-	_, err := m.symbolRepo.Count(ctx)
-	return err
+// RecordPrune records snapshots deleted for a symbol during a prune run
+func (m *MetricsService) RecordPrune(symbol string, deleted int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.clock.Now()
+	m.lastPruneTime = &now
+	m.prunedSnapshots += deleted
 }
 
-// Ensure MetricsService implements ports.MetricsService
-var _ ports.MetricsService = (*MetricsService)(nil)
+// Ensure MetricsService implements ports.MetricsService and
+// ports.HTTPRequestRecorder
+var (
+	_ ports.MetricsService      = (*MetricsService)(nil)
+	_ ports.HTTPRequestRecorder = (*MetricsService)(nil)
+)
@@ -2,7 +2,11 @@ package services
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
@@ -13,37 +17,333 @@ import (
 type PollerService struct {
 	symbolRepo   ports.SymbolRepository
 	snapshotRepo ports.SnapshotRepository
-	exchange     ports.ExchangeClient
+	tickerRepo   ports.TickerStatsRepository
+	exchanges    ports.ExchangeRegistry
 	metrics      ports.MetricsService
+	tsSource     domain.TimestampSource
+	budget       domain.PollBudget
+	writeMode    domain.SnapshotWriteMode
+	clock        ports.Clock
 	logger       *slog.Logger
+
+	// failover, when set, reports a database failover in progress
+	failover ports.FailoverMonitor
+
+	// fetchMode selects bulk or per-symbol price fetches; fetchHistory
+	// remembers recent bulk failures for auto mode
+	fetchMode        domain.PollFetchMode
+	fetchConcurrency int
+	fetchHistory     *pollFetchHistory
+
+	// group and groups restrict polling to one named poller group's symbols
+	group  string
+	groups domain.PollerGroups
+
+	// stream, when set, pushes prices that are buffered in streamed until
+	// the next FlushStream
+	stream   ports.PriceStream
+	streamed *streamBuffer
+
+	// alerts, when set, evaluates alert rules against each poll's prices
+	alerts ports.AlertService
+
+	// pipelineVersion is recorded on every snapshot the poller builds
+	pipelineVersion string
+
+	// synthetic defines the symbols priced from each poll's other prices
+	synthetic domain.SyntheticSymbols
 }
 
-// NewPollerService creates a new poller service
+// NewPollerService creates a new poller service that polls each symbol from
+// its exchange in exchanges. When tickerRepo is non-nil, each poll also
+// captures 24h ticker stats.
+// tsSource selects how snapshots are timestamped (poll, fetch or exchange),
+// budget bounds each stage of a poll, and writeMode decides whether one
+// rejected snapshot discards the rest of the poll. Snapshot timestamps and
+// stage durations are read from clock.
 func NewPollerService(
 	symbolRepo ports.SymbolRepository,
 	snapshotRepo ports.SnapshotRepository,
-	exchange ports.ExchangeClient,
+	tickerRepo ports.TickerStatsRepository,
+	exchanges ports.ExchangeRegistry,
 	metrics ports.MetricsService,
+	tsSource domain.TimestampSource,
+	budget domain.PollBudget,
+	writeMode domain.SnapshotWriteMode,
+	clock ports.Clock,
 	logger *slog.Logger,
 ) *PollerService {
+	if tsSource == "" {
+		tsSource = domain.TimestampSourcePoll
+	}
+	if writeMode == "" {
+		writeMode = domain.SnapshotWriteAtomic
+	}
+
 	return &PollerService{
 		symbolRepo:   symbolRepo,
 		snapshotRepo: snapshotRepo,
-		exchange:     exchange,
+		tickerRepo:   tickerRepo,
+		exchanges:    exchanges,
 		metrics:      metrics,
+		tsSource:     tsSource,
+		budget:       budget,
+		writeMode:    writeMode,
+		clock:        clock,
 		logger:       logger.With("component", "poller_service"),
+
+		fetchMode:        domain.PollFetchBulk,
+		fetchConcurrency: 1,
+		fetchHistory:     &pollFetchHistory{},
 	}
 }
 
-// PollPrices fetches and stores prices for all active symbols
+// pollFetchHistory guards the fetch history of one poller
+type pollFetchHistory struct {
+	mu      sync.Mutex
+	history domain.PollFetchHistory
+}
+
+// SetFetchMode selects how prices are fetched: in one bulk request, one
+// request per symbol with at most concurrency in flight, or automatically.
+// It must be called before ForGroup and before polling starts.
+func (p *PollerService) SetFetchMode(mode domain.PollFetchMode, concurrency int) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	p.fetchMode = mode
+	p.fetchConcurrency = concurrency
+}
+
+// SetPipelineVersion records version, typically the service's build
+// version, on every snapshot stored from then on. It must be called before
+// polling starts.
+func (p *PollerService) SetPipelineVersion(version string) {
+	p.pipelineVersion = version
+}
+
+// streamBuffer holds the latest streamed price of each symbol
+type streamBuffer struct {
+	mu     sync.Mutex
+	prices map[string]*domain.Price
+}
+
+// SetPriceStream makes the poller take prices from stream, written with
+// StreamPrices and FlushStream instead of PollPrices. It must be called
+// before polling starts.
+func (p *PollerService) SetPriceStream(stream ports.PriceStream) {
+	p.stream = stream
+	p.streamed = &streamBuffer{prices: make(map[string]*domain.Price)}
+}
+
+// SetDatabaseFailover makes polls skip, rather than fail, while the
+// database is failing over to a new primary. It must be called before
+// ForGroup and before polling starts.
+func (p *PollerService) SetDatabaseFailover(failover ports.FailoverMonitor) {
+	p.failover = failover
+}
+
+// SetAlerts makes every poll feed its stored prices to the alert rules. It
+// must be called before ForGroup and before polling starts.
+func (p *PollerService) SetAlerts(alerts ports.AlertService) {
+	p.alerts = alerts
+}
+
+// SetSyntheticSymbols makes every poll derive the prices of the synthetic
+// symbols among its active symbols from the prices it fetched. It must be
+// called before ForGroup and before polling starts.
+func (p *PollerService) SetSyntheticSymbols(symbols domain.SyntheticSymbols) {
+	p.synthetic = symbols
+}
+
+// TrackSyntheticSymbols starts tracking every synthetic symbol that is not
+// tracked yet, as an active symbol of the synthetic exchange. It fails when
+// a synthetic symbol's name is already tracked from an exchange.
+func (p *PollerService) TrackSyntheticSymbols(ctx context.Context) error {
+	for _, def := range p.synthetic {
+		existing, err := p.symbolRepo.GetByName(ctx, def.Name)
+		if err == nil {
+			if existing.Exchange != domain.SyntheticExchange {
+				return fmt.Errorf("%w: %s is already tracked from exchange %q", domain.ErrInvalidSyntheticSymbol, def.Name, existing.Exchange)
+			}
+			continue
+		}
+		if !errors.Is(err, domain.ErrSymbolNotFound) {
+			return err
+		}
+
+		symbol, err := domain.NewSymbol(def.Name)
+		if err != nil {
+			return err
+		}
+		symbol.Exchange = domain.SyntheticExchange
+		if err := p.symbolRepo.Create(ctx, symbol); err != nil {
+			return err
+		}
+		p.logger.Info("synthetic symbol added", "symbol", def.Name, "definition", def.String())
+	}
+	return nil
+}
+
+// ForGroup returns a poller service that only polls the active symbols
+// selected by the named group
+func (p *PollerService) ForGroup(name string, groups domain.PollerGroups) *PollerService {
+	scoped := *p
+	scoped.group = name
+	scoped.groups = groups
+	scoped.logger = p.logger.With("group", name)
+	scoped.fetchHistory = &pollFetchHistory{}
+	return &scoped
+}
+
+// PollPrices fetches and stores prices for all active symbols, or for the
+// group's symbols when scoped with ForGroup. The symbols the poll included
+// and excluded are recorded whether or not it succeeds. A poll during a
+// database failover is skipped instead of counted as an error.
 func (p *PollerService) PollPrices(ctx context.Context) error {
-	start := time.Now()
+	ctx = domain.WithExchangePriority(ctx, domain.ExchangePriorityPoll)
+	start := p.clock.Now()
+	run := domain.NewPollSymbols(domain.NewPollRunID(), p.group, start)
+
+	if p.failingOver() {
+		p.logger.Warn("skipping poll during database failover")
+		run.Error = pollSkippedFailover
+		p.metrics.RecordPollSymbols(run)
+		return nil
+	}
+
+	return p.record(run, start, p.pollPrices(ctx, run, start))
+}
+
+// PollSymbol fetches the current price of one tracked symbol from its
+// exchange and stores it as the only snapshot of a new poll run, outside
+// the schedule. The snapshot is written with Create, past any write
+// buffer, so it can be read as soon as PollSymbol returns. Inactive
+// symbols are polled too, and the run is not reported as a poll of the
+// group.
+func (p *PollerService) PollSymbol(ctx context.Context, name string) (*domain.PriceSnapshot, error) {
+	ctx = domain.WithExchangePriority(ctx, domain.ExchangePriorityLive)
+
+	symbol, err := p.symbolRepo.GetByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if symbol.Exchange == domain.SyntheticExchange {
+		return nil, fmt.Errorf("%w: %s is synthetic and priced by polls only", domain.ErrInvalidSymbol, symbol.Name)
+	}
+
+	exchangeName := strings.ToLower(symbol.Exchange)
+	if exchangeName == "" {
+		exchangeName = p.exchanges.Default()
+	}
+	exchange, err := p.exchanges.Client(exchangeName)
+	if err != nil {
+		return nil, err
+	}
 
-	// Get active symbols
-	symbols, err := p.symbolRepo.ListActive(ctx)
+	start := p.clock.Now()
+	prices, err := exchange.GetPrices(ctx, []string{symbol.Name})
+	if err != nil {
+		return nil, err
+	}
+	fetchLatencyMs := p.clock.Since(start).Milliseconds()
+
+	run := domain.NewPollSymbols(domain.NewPollRunID(), p.group, start)
+	run.Included = []string{symbol.Name}
+	symbolMap := map[string]*domain.Symbol{symbol.Name: symbol}
+	snapshots := p.buildSnapshots(run, symbolMap, exchangeName, prices, domain.IngestModePoll, &fetchLatencyMs, p.clock.Now().UTC())
+	if len(snapshots) == 0 {
+		return nil, fmt.Errorf("%w: no price for %s", domain.ErrInvalidResponse, symbol.Name)
+	}
+
+	snapshot := snapshots[0]
+	if err := p.snapshotRepo.Create(ctx, snapshot); err != nil {
+		p.logger.Error("failed to store snapshot", "symbol", symbol.Name, "error", err)
+		return nil, err
+	}
+	p.evaluateAlerts(ctx, run, snapshots)
+
+	p.logger.Info("symbol polled",
+		"symbol", symbol.Name,
+		"price", snapshot.Price.String(),
+		"fetch_latency_ms", fetchLatencyMs,
+	)
+
+	return snapshot, nil
+}
+
+// StreamPrices buffers the prices pushed by the price stream until ctx is
+// done. Only the latest price of each symbol is kept until FlushStream.
+func (p *PollerService) StreamPrices(ctx context.Context) error {
+	if p.stream == nil {
+		return fmt.Errorf("no price stream configured")
+	}
+
+	return p.stream.Stream(ctx, func(prices []*domain.Price) {
+		p.streamed.mu.Lock()
+		defer p.streamed.mu.Unlock()
+		for _, price := range prices {
+			p.streamed.prices[price.Symbol] = price
+		}
+	})
+}
+
+// FlushStream stores the prices streamed since the last flush for the
+// active symbols, recorded as one poll run. Symbols whose price did not
+// change are not written. During a database failover the prices stay
+// buffered, and only the latest is written once it completes.
+func (p *PollerService) FlushStream(ctx context.Context) error {
+	if p.streamed == nil || p.failingOver() {
+		return nil
+	}
+
+	p.streamed.mu.Lock()
+	prices := make([]*domain.Price, 0, len(p.streamed.prices))
+	for _, price := range p.streamed.prices {
+		prices = append(prices, price)
+	}
+	clear(p.streamed.prices)
+	p.streamed.mu.Unlock()
+
+	if len(prices) == 0 {
+		return nil
+	}
+
+	start := p.clock.Now()
+	run := domain.NewPollSymbols(domain.NewPollRunID(), p.group, start)
+	run.FetchMode = domain.PollFetchStream
+
+	return p.record(run, start, p.flushStream(ctx, run, prices, start))
+}
+
+// record reports the outcome of a poll run and its included and excluded
+// symbols. An error caused by a database failover is recorded as skipped
+// rather than failed.
+func (p *PollerService) record(run *domain.PollSymbols, start time.Time, err error) error {
+	if err != nil && p.failingOver() {
+		p.logger.Warn("poll interrupted by database failover", "error", err)
+		run.Error = pollSkippedFailover
+		err = nil
+	} else if err != nil {
+		p.metrics.RecordPollError(p.group, p.clock.Since(start))
+		run.Error = err.Error()
+	}
+	p.metrics.RecordPollSymbols(run)
+	return err
+}
+
+// pollSkippedFailover is recorded as the error of a poll skipped during
+// a database failover
+const pollSkippedFailover = "skipped during database failover"
+
+// failingOver reports whether the database is failing over
+func (p *PollerService) failingOver() bool {
+	return p.failover != nil && p.failover.Failover() != nil
+}
+
+func (p *PollerService) pollPrices(ctx context.Context, run *domain.PollSymbols, start time.Time) error {
+	symbols, err := p.activeSymbols(ctx, run)
 	if err != nil {
-		p.logger.Error("failed to list active symbols", "error", err)
-		p.metrics.RecordPollError(time.Since(start))
 		return err
 	}
 
@@ -52,62 +352,592 @@ func (p *PollerService) PollPrices(ctx context.Context) error {
 		return nil
 	}
 
-	// Extract symbol names and create lookup map
 	symbolNames := make([]string, len(symbols))
-	symbolMap := make(map[string]*domain.Symbol)
 	for i, s := range symbols {
 		symbolNames[i] = s.Name
-		symbolMap[s.Name] = s
 	}
+	run.Included = symbolNames
 
 	p.logger.Debug("polling prices", "symbols", len(symbols))
 
-	// Fetch prices from exchange
-	prices, err := p.exchange.GetPrices(ctx, symbolNames)
+	// Fetch prices from each exchange; the poll only fails when every
+	// exchange did
+	mode := p.chooseFetchMode(len(symbolNames))
+	run.FetchMode = mode
+	var fetches []*exchangeFetch
+	var fetchErr error
+	var fetchLatencyMs int64
+	err = p.stage(ctx, domain.PollStageFetch, func(ctx context.Context) error {
+		fetchStart := p.clock.Now()
+		fetches = p.fetchExchanges(ctx, mode, byExchange(p.exchanges, symbols))
+		fetchLatencyMs = p.clock.Since(fetchStart).Milliseconds()
+
+		var all bool
+		all, fetchErr = joinFetchErrors(fetches)
+		if all {
+			return fetchErr
+		}
+		return nil
+	})
+	run.FetchLatencyMs = &fetchLatencyMs
+	p.recordFetch(mode, fetchErr)
 	if err != nil {
-		p.logger.Error("failed to fetch prices from exchange", "error", err)
-		p.metrics.RecordPollError(time.Since(start))
 		return err
 	}
 
 	// Create snapshots
-	now := time.Now().UTC()
-	snapshots := make([]*domain.PriceSnapshot, 0, len(prices))
-	for _, price := range prices {
-		if sym, ok := symbolMap[price.Symbol]; ok {
-			snapshots = append(snapshots, &domain.PriceSnapshot{
-				SymbolID:  sym.ID,
-				Symbol:    price.Symbol,
-				Price:     price.Price,
-				Timestamp: now,
-			})
+	now := p.clock.Now().UTC()
+	var snapshots []*domain.PriceSnapshot
+	_ = p.stage(ctx, domain.PollStageBuild, func(context.Context) error {
+		for _, f := range fetches {
+			snapshots = append(snapshots, p.buildSnapshots(run, f.symbolMap(), f.group.exchange, f.prices, domain.IngestModePoll, &f.latencyMs, now)...)
 		}
-	}
+
+		priced := make(map[string]bool, len(snapshots))
+		for _, s := range snapshots {
+			priced[s.Symbol] = true
+		}
+		for _, f := range fetches {
+			for _, name := range f.group.names() {
+				if priced[name] {
+					continue
+				}
+				if f.err != nil {
+					run.Exclude(name, domain.PollExclusionFetchFailed, f.err.Error())
+				} else if err, ok := f.errs[name]; ok {
+					run.Exclude(name, domain.PollExclusionFetchFailed, err.Error())
+				} else {
+					run.Exclude(name, domain.PollExclusionNoPrice, "")
+				}
+			}
+		}
+
+		snapshots = p.deriveSynthetic(run, symbols, snapshots)
+		return nil
+	})
 
 	if len(snapshots) == 0 {
 		p.logger.Warn("no prices to store")
-		p.metrics.RecordPollSuccess(time.Since(start))
+		p.metrics.RecordPollSuccess(p.group, p.clock.Since(start))
 		return nil
 	}
 
-	// Store snapshots
-	if err := p.snapshotRepo.CreateBatch(ctx, snapshots); err != nil {
-		p.logger.Error("failed to store snapshots", "error", err)
-		p.metrics.RecordPollError(time.Since(start))
+	stored, err := p.storeSnapshots(ctx, run, snapshots)
+	if err != nil {
 		return err
 	}
+	p.evaluateAlerts(ctx, run, snapshots)
 
-	duration := time.Since(start)
-	p.metrics.RecordPollSuccess(duration)
+	if p.tickerRepo != nil {
+		_ = p.stage(ctx, domain.PollStageTickerStats, func(ctx context.Context) error {
+			for _, f := range fetches {
+				if f.err == nil {
+					p.pollTickerStats(ctx, f, now)
+				}
+			}
+			return nil
+		})
+	}
+
+	duration := p.clock.Since(start)
+	p.metrics.RecordPollSuccess(p.group, duration)
 
 	p.logger.Info("poll completed",
 		"symbols", len(symbols),
-		"snapshots", len(snapshots),
+		"snapshots", stored,
+		"duration_ms", duration.Milliseconds(),
+	)
+
+	return nil
+}
+
+// flushStream stores the streamed prices of the active symbols. Only the
+// default exchange is streamed, so symbols of other exchanges are excluded
+// rather than priced by name from it.
+func (p *PollerService) flushStream(ctx context.Context, run *domain.PollSymbols, prices []*domain.Price, start time.Time) error {
+	symbols, err := p.activeSymbols(ctx, run)
+	if err != nil {
+		return err
+	}
+
+	symbolMap := make(map[string]*domain.Symbol, len(symbols))
+	for _, group := range byExchange(p.exchanges, symbols) {
+		if group.exchange != p.exchanges.Default() {
+			for _, s := range group.symbols {
+				run.Exclude(s.Name, domain.PollExclusionFetchFailed, "exchange "+group.exchange+" is not streamed")
+			}
+			continue
+		}
+		for _, s := range group.symbols {
+			symbolMap[s.Name] = s
+		}
+	}
+
+	now := p.clock.Now().UTC()
+	var snapshots []*domain.PriceSnapshot
+	_ = p.stage(ctx, domain.PollStageBuild, func(context.Context) error {
+		snapshots = p.buildSnapshots(run, symbolMap, p.exchanges.Default(), prices, domain.IngestModeStream, nil, now)
+		snapshots = p.deriveSynthetic(run, symbols, snapshots)
+		return nil
+	})
+
+	if len(snapshots) == 0 {
+		return nil
+	}
+
+	run.Included = make([]string, len(snapshots))
+	for i, s := range snapshots {
+		run.Included[i] = s.Symbol
+	}
+
+	stored, err := p.storeSnapshots(ctx, run, snapshots)
+	if err != nil {
+		return err
+	}
+	p.evaluateAlerts(ctx, run, snapshots)
+
+	duration := p.clock.Since(start)
+	p.metrics.RecordPollSuccess(p.group, duration)
+
+	p.logger.Debug("stream flushed",
+		"snapshots", stored,
 		"duration_ms", duration.Milliseconds(),
 	)
 
 	return nil
 }
 
-// Ensure PollerService implements ports.PollerService
-var _ ports.PollerService = (*PollerService)(nil)
+// activeSymbols lists the active symbols to poll, recording inactive ones
+// and those of other groups as excluded from run
+func (p *PollerService) activeSymbols(ctx context.Context, run *domain.PollSymbols) ([]*domain.Symbol, error) {
+	// Get tracked symbols; inactive ones are only listed to report them as
+	// excluded
+	var tracked []*domain.Symbol
+	err := p.stage(ctx, domain.PollStageListSymbols, func(ctx context.Context) error {
+		var err error
+		tracked, err = p.symbolRepo.List(ctx)
+		return err
+	})
+	if err != nil {
+		p.logger.Error("failed to list symbols", "error", err)
+		return nil, err
+	}
+
+	symbols := make([]*domain.Symbol, 0, len(tracked))
+	for _, s := range tracked {
+		if s.Active {
+			symbols = append(symbols, s)
+		} else {
+			run.Exclude(s.Name, domain.PollExclusionInactive, "")
+		}
+	}
+
+	if p.group != "" {
+		active := symbols
+		symbols = p.groups.Select(p.group, active)
+
+		selected := make(map[string]bool, len(symbols))
+		for _, s := range symbols {
+			selected[s.Name] = true
+		}
+		for _, s := range active {
+			if !selected[s.Name] {
+				run.Exclude(s.Name, domain.PollExclusionOtherGroup, "")
+			}
+		}
+	}
+
+	return symbols, nil
+}
+
+// buildSnapshots creates a snapshot of run for each price of a symbol in
+// symbolMap fetched from exchange in mode; prices of other symbols are
+// ignored
+func (p *PollerService) buildSnapshots(
+	run *domain.PollSymbols,
+	symbolMap map[string]*domain.Symbol,
+	exchange string,
+	prices []*domain.Price,
+	mode domain.IngestMode,
+	fetchLatencyMs *int64,
+	now time.Time,
+) []*domain.PriceSnapshot {
+	snapshots := make([]*domain.PriceSnapshot, 0, len(prices))
+	for _, price := range prices {
+		if sym, ok := symbolMap[price.Symbol]; ok {
+			ts, source := p.snapshotTime(price, now)
+			snapshots = append(snapshots, &domain.PriceSnapshot{
+				SymbolID:        sym.ID,
+				Symbol:          price.Symbol,
+				Price:           price.Price,
+				Timestamp:       ts,
+				TimestampSource: source,
+				FetchLatencyMs:  fetchLatencyMs,
+				PollRunID:       run.RunID,
+				Exchange:        exchange,
+				IngestMode:      mode,
+				IngestLatencyMs: price.IngestLatencyMs(now),
+				PipelineVersion: p.pipelineVersion,
+			})
+		}
+	}
+	return snapshots
+}
+
+// deriveSynthetic appends to snapshots those of the synthetic symbols among
+// symbols, priced from the snapshots built so far. Synthetic symbols missing
+// a price or a definition are excluded from run.
+func (p *PollerService) deriveSynthetic(run *domain.PollSymbols, symbols []*domain.Symbol, snapshots []*domain.PriceSnapshot) []*domain.PriceSnapshot {
+	defined := make(map[string]bool, len(p.synthetic))
+	for _, def := range p.synthetic {
+		defined[def.Name] = true
+	}
+
+	symbolMap := make(map[string]*domain.Symbol)
+	for _, s := range symbols {
+		if s.Exchange != domain.SyntheticExchange {
+			continue
+		}
+		if !defined[s.Name] {
+			run.Exclude(s.Name, domain.PollExclusionNoPrice, "no synthetic symbol definition")
+			continue
+		}
+		symbolMap[s.Name] = s
+	}
+	if len(symbolMap) == 0 {
+		return snapshots
+	}
+
+	derived, missing := p.synthetic.Derive(symbolMap, snapshots)
+	for _, def := range p.synthetic {
+		if pair, ok := missing[def.Name]; ok {
+			run.Exclude(def.Name, domain.PollExclusionNoPrice, "no price for "+pair)
+		}
+	}
+	return append(snapshots, derived...)
+}
+
+// storeSnapshots writes the snapshots of run in the configured write mode
+// and returns how many were stored
+func (p *PollerService) storeSnapshots(ctx context.Context, run *domain.PollSymbols, snapshots []*domain.PriceSnapshot) (int, error) {
+	stored := len(snapshots)
+	err := p.stage(ctx, domain.PollStageStore, func(ctx context.Context) error {
+		if p.writeMode != domain.SnapshotWritePartial {
+			return p.retryStore(ctx, func(ctx context.Context) error {
+				return p.snapshotRepo.CreateBatch(ctx, snapshots)
+			})
+		}
+
+		var result *domain.BatchWriteResult
+		err := p.retryStore(ctx, func(ctx context.Context) error {
+			var err error
+			result, err = p.snapshotRepo.CreateBatchPartial(ctx, snapshots)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		stored = result.Written
+		for _, f := range result.Failed {
+			run.Exclude(f.Symbol, domain.PollExclusionWriteFailed, f.Error)
+		}
+		return p.reportWriteFailures(result)
+	})
+	if err != nil {
+		p.logger.Error("failed to store snapshots", "error", err)
+		return 0, err
+	}
+	return stored, nil
+}
+
+// evaluateAlerts feeds stored snapshots to the alert rules, leaving out
+// those a partial write rejected
+func (p *PollerService) evaluateAlerts(ctx context.Context, run *domain.PollSymbols, snapshots []*domain.PriceSnapshot) {
+	if p.alerts == nil {
+		return
+	}
+
+	rejected := make(map[string]bool)
+	for _, e := range run.Excluded {
+		if e.Reason == domain.PollExclusionWriteFailed {
+			rejected[e.Symbol] = true
+		}
+	}
+	if len(rejected) > 0 {
+		stored := make([]*domain.PriceSnapshot, 0, len(snapshots))
+		for _, snap := range snapshots {
+			if !rejected[snap.Symbol] {
+				stored = append(stored, snap)
+			}
+		}
+		snapshots = stored
+	}
+
+	p.alerts.Evaluate(ctx, snapshots)
+}
+
+// chooseFetchMode resolves the configured fetch mode for a poll of the
+// given number of symbols
+func (p *PollerService) chooseFetchMode(symbols int) domain.PollFetchMode {
+	if p.fetchMode != domain.PollFetchAuto {
+		return p.fetchMode
+	}
+
+	p.fetchHistory.mu.Lock()
+	defer p.fetchHistory.mu.Unlock()
+
+	mode := p.fetchHistory.history.Choose(symbols)
+	if mode == domain.PollFetchIndividual {
+		p.logger.Debug("fetching prices individually after bulk fetch failures")
+	}
+	return mode
+}
+
+// recordFetch adds a fetch to the history auto mode chooses by. A fetch
+// cut short by the poll being cancelled says nothing about the exchange.
+func (p *PollerService) recordFetch(mode domain.PollFetchMode, err error) {
+	if p.fetchMode != domain.PollFetchAuto || errors.Is(err, context.Canceled) {
+		return
+	}
+
+	p.fetchHistory.mu.Lock()
+	defer p.fetchHistory.mu.Unlock()
+	p.fetchHistory.history.Record(mode, err != nil)
+}
+
+// exchangeFetch is the outcome of fetching one exchange's prices for a poll
+type exchangeFetch struct {
+	group     exchangeSymbols
+	client    ports.ExchangeClient
+	prices    []*domain.Price
+	errs      map[string]error
+	latencyMs int64
+	err       error
+}
+
+// symbolMap indexes the fetched exchange's symbols by name
+func (f *exchangeFetch) symbolMap() map[string]*domain.Symbol {
+	symbols := make(map[string]*domain.Symbol, len(f.group.symbols))
+	for _, s := range f.group.symbols {
+		symbols[s.Name] = s
+	}
+	return symbols
+}
+
+// fetchExchanges fetches the prices of each exchange's symbols, from all
+// exchanges at once
+func (p *PollerService) fetchExchanges(ctx context.Context, mode domain.PollFetchMode, groups []exchangeSymbols) []*exchangeFetch {
+	fetches := make([]*exchangeFetch, len(groups))
+	var wg sync.WaitGroup
+	for i, group := range groups {
+		f := &exchangeFetch{group: group}
+		fetches[i] = f
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			f.client, f.err = p.exchanges.Client(group.exchange)
+			if f.err != nil {
+				return
+			}
+
+			start := p.clock.Now()
+			if mode == domain.PollFetchIndividual {
+				f.prices, f.errs, f.err = p.fetchIndividually(ctx, f.client, group.names())
+			} else {
+				f.prices, f.err = f.client.GetPrices(ctx, group.names())
+			}
+			f.latencyMs = p.clock.Since(start).Milliseconds()
+		}()
+	}
+	wg.Wait()
+
+	for _, f := range fetches {
+		if f.err != nil {
+			p.logger.Error("failed to fetch prices from exchange", "exchange", f.group.exchange, "error", f.err)
+		}
+	}
+	return fetches
+}
+
+// joinFetchErrors joins the errors of the exchanges whose fetch failed and
+// reports whether every one did
+func joinFetchErrors(fetches []*exchangeFetch) (bool, error) {
+	var errs []error
+	for _, f := range fetches {
+		if f.err != nil {
+			errs = append(errs, f.err)
+		}
+	}
+
+	switch len(errs) {
+	case 0:
+		return false, nil
+	case 1:
+		return len(fetches) == 1, errs[0]
+	default:
+		return len(errs) == len(fetches), errors.Join(errs...)
+	}
+}
+
+// fetchIndividually fetches each symbol's price in its own request, at most
+// fetchConcurrency at a time. It returns the prices it got and the errors
+// of the symbols it did not; it only fails when every fetch did.
+func (p *PollerService) fetchIndividually(ctx context.Context, exchange ports.ExchangeClient, symbols []string) ([]*domain.Price, map[string]error, error) {
+	results := make([]*domain.Price, len(symbols))
+	errs := make([]error, len(symbols))
+
+	var (
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, p.fetchConcurrency)
+	)
+
+	for i, symbol := range symbols {
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			return nil, nil, ctx.Err()
+		case sem <- struct{}{}:
+		}
+
+		wg.Add(1)
+		go func(i int, symbol string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			results[i], errs[i] = exchange.GetPrice(ctx, symbol)
+		}(i, symbol)
+	}
+
+	wg.Wait()
+
+	prices := make([]*domain.Price, 0, len(symbols))
+	failed := make(map[string]error)
+	var firstErr error
+	for i, symbol := range symbols {
+		if errs[i] != nil {
+			p.logger.Warn("failed to fetch price", "symbol", symbol, "error", errs[i])
+			failed[symbol] = errs[i]
+			if firstErr == nil {
+				firstErr = errs[i]
+			}
+			continue
+		}
+		prices = append(prices, results[i])
+	}
+
+	if len(prices) == 0 && firstErr != nil {
+		return nil, nil, fmt.Errorf("all %d price fetches failed: %w", len(symbols), firstErr)
+	}
+	return prices, failed, nil
+}
+
+// retryStore runs a snapshot write once more when it failed with the store
+// budget left. The snapshots carry the poll's run ID, so a write that was
+// committed before its acknowledgement was lost is not stored twice.
+func (p *PollerService) retryStore(ctx context.Context, write func(ctx context.Context) error) error {
+	err := write(ctx)
+	if err == nil || ctx.Err() != nil {
+		return err
+	}
+
+	p.logger.Warn("failed to store snapshots, retrying", "error", err)
+	return write(ctx)
+}
+
+// reportWriteFailures logs and counts the snapshots a partial write
+// rejected. The poll only fails when none were stored.
+func (p *PollerService) reportWriteFailures(result *domain.BatchWriteResult) error {
+	if len(result.Failed) == 0 {
+		return nil
+	}
+
+	for _, f := range result.Failed {
+		p.logger.Warn("failed to store snapshot", "symbol", f.Symbol, "error", f.Error)
+	}
+
+	if result.Written == 0 {
+		return fmt.Errorf("all %d snapshots were rejected", len(result.Failed))
+	}
+
+	p.metrics.RecordSnapshotWriteFailures(len(result.Failed))
+	return nil
+}
+
+// stage runs one poll stage within its budget and records its duration.
+// A stage only counts as timed out when its own budget expired, not when
+// the poll as a whole was cancelled.
+func (p *PollerService) stage(ctx context.Context, stage domain.PollStage, fn func(ctx context.Context) error) error {
+	budget := p.budget.For(stage)
+	stageCtx, cancel := ctx, func() {}
+	if budget > 0 {
+		stageCtx, cancel = context.WithTimeout(ctx, budget)
+	}
+	defer cancel()
+
+	start := p.clock.Now()
+	err := fn(stageCtx)
+	duration := p.clock.Since(start)
+
+	timedOut := budget > 0 && ctx.Err() == nil && errors.Is(stageCtx.Err(), context.DeadlineExceeded)
+	p.metrics.RecordPollStage(stage, duration, timedOut)
+
+	if timedOut {
+		p.logger.Warn("poll stage exceeded its budget",
+			"stage", stage,
+			"budget", budget.String(),
+			"duration_ms", duration.Milliseconds(),
+		)
+	}
+
+	return err
+}
+
+// snapshotTime picks a snapshot's timestamp according to the configured
+// source, falling back to the next coarser source when the exchange did not
+// provide the preferred one. The source actually used is returned with it.
+func (p *PollerService) snapshotTime(price *domain.Price, pollTime time.Time) (time.Time, domain.TimestampSource) {
+	if p.tsSource == domain.TimestampSourceExchange && price.EventTime != nil {
+		return price.EventTime.UTC(), domain.TimestampSourceExchange
+	}
+
+	if p.tsSource != domain.TimestampSourcePoll && !price.FetchedAt.IsZero() {
+		return price.FetchedAt.UTC(), domain.TimestampSourceFetch
+	}
+
+	return pollTime, domain.TimestampSourcePoll
+}
+
+// pollTickerStats captures the 24h stats of an exchange's symbols alongside
+// prices. Failures are logged but do not fail the poll, since prices are
+// already stored.
+func (p *PollerService) pollTickerStats(ctx context.Context, f *exchangeFetch, now time.Time) {
+	stats, err := f.client.GetTickerStats(ctx, f.group.names())
+	if err != nil {
+		p.logger.Error("failed to fetch ticker stats from exchange", "exchange", f.group.exchange, "error", err)
+		return
+	}
+
+	symbolMap := f.symbolMap()
+	captured := make([]*domain.TickerStats, 0, len(stats))
+	for _, s := range stats {
+		if sym, ok := symbolMap[s.Symbol]; ok {
+			s.SymbolID = sym.ID
+			s.Timestamp = now
+			captured = append(captured, s)
+		}
+	}
+
+	if err := p.tickerRepo.CreateBatch(ctx, captured); err != nil {
+		p.logger.Error("failed to store ticker stats", "error", err)
+	}
+}
+
+// Ensure PollerService implements ports.PollerService,
+// ports.StreamPollerService and ports.SymbolPoller
+var (
+	_ ports.PollerService       = (*PollerService)(nil)
+	_ ports.StreamPollerService = (*PollerService)(nil)
+	_ ports.SymbolPoller        = (*PollerService)(nil)
+)
@@ -0,0 +1,95 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+)
+
+// precisionRetryAfter is how long a failed metadata fetch is not retried,
+// so an exchange outage does not add a slow call to every price request
+const precisionRetryAfter = 30 * time.Second
+
+// PrecisionService implements the ports.PrecisionService interface. It keeps
+// the tick sizes of every listed symbol in memory, loaded with a single
+// exchangeInfo call and refreshed after the TTL. Stale data is served while
+// a refresh fails.
+type PrecisionService struct {
+	exchange ports.ExchangeClient
+	ttl      time.Duration
+	logger   *slog.Logger
+
+	mu         sync.Mutex
+	bySymbol   map[string]*domain.SymbolPrecision
+	loadedAt   time.Time
+	failedAt   time.Time
+	refreshing bool
+}
+
+// NewPrecisionService creates a new precision service
+func NewPrecisionService(exchange ports.ExchangeClient, ttl time.Duration, logger *slog.Logger) *PrecisionService {
+	return &PrecisionService{
+		exchange: exchange,
+		ttl:      ttl,
+		logger:   logger.With("component", "precision_service"),
+	}
+}
+
+// GetPrecisions returns the known precisions of the given symbols. Symbols
+// the exchange reported no precision for are missing from the result.
+func (s *PrecisionService) GetPrecisions(ctx context.Context, symbols []string) map[string]*domain.SymbolPrecision {
+	bySymbol := s.load(ctx)
+
+	result := make(map[string]*domain.SymbolPrecision, len(symbols))
+	for _, name := range symbols {
+		if p, ok := bySymbol[name]; ok {
+			result[name] = p
+		}
+	}
+	return result
+}
+
+// load returns the cached precisions, fetching them when missing or expired.
+// Only one caller fetches at a time; the others are served what is cached.
+func (s *PrecisionService) load(ctx context.Context) map[string]*domain.SymbolPrecision {
+	s.mu.Lock()
+	cached := s.bySymbol
+	fresh := cached != nil && time.Since(s.loadedAt) < s.ttl
+	backingOff := time.Since(s.failedAt) < precisionRetryAfter
+	if fresh || backingOff || s.refreshing {
+		s.mu.Unlock()
+		return cached
+	}
+	s.refreshing = true
+	s.mu.Unlock()
+
+	precisions, err := s.exchange.GetSymbolPrecisions(ctx, nil)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.refreshing = false
+
+	if err != nil {
+		s.logger.Warn("failed to load symbol precisions", "error", err)
+		s.failedAt = time.Now()
+		return s.bySymbol
+	}
+
+	bySymbol := make(map[string]*domain.SymbolPrecision, len(precisions))
+	for _, p := range precisions {
+		bySymbol[p.Symbol] = p
+	}
+
+	s.bySymbol = bySymbol
+	s.loadedAt = time.Now()
+	s.logger.Debug("symbol precisions loaded", "symbols", len(bySymbol))
+
+	return bySymbol
+}
+
+// Ensure PrecisionService implements ports.PrecisionService
+var _ ports.PrecisionService = (*PrecisionService)(nil)
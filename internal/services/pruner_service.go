@@ -0,0 +1,152 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+)
+
+// PrunerService implements the ports.PrunerService interface
+type PrunerService struct {
+	symbolRepo   ports.SymbolRepository
+	snapshotRepo ports.SnapshotRepository
+	tickerRepo   ports.TickerStatsRepository
+	metrics      ports.MetricsService
+	retention    time.Duration
+	concurrency  int
+	clock        ports.Clock
+	logger       *slog.Logger
+
+	// maintenance, when set, is told how many rows each prune deleted
+	maintenance ports.MaintenanceService
+}
+
+// NewPrunerService creates a new pruner service.
+// tickerRepo may be nil when 24h ticker stats are not captured. The
+// retention cutoff is measured back from clock's current time.
+func NewPrunerService(
+	symbolRepo ports.SymbolRepository,
+	snapshotRepo ports.SnapshotRepository,
+	tickerRepo ports.TickerStatsRepository,
+	metrics ports.MetricsService,
+	retentionDays int,
+	concurrency int,
+	clock ports.Clock,
+	logger *slog.Logger,
+) *PrunerService {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	return &PrunerService{
+		symbolRepo:   symbolRepo,
+		snapshotRepo: snapshotRepo,
+		tickerRepo:   tickerRepo,
+		metrics:      metrics,
+		retention:    time.Duration(retentionDays) * 24 * time.Hour,
+		concurrency:  concurrency,
+		clock:        clock,
+		logger:       logger.With("component", "pruner_service"),
+	}
+}
+
+// SetMaintenance makes large prunes analyze the snapshot tables. It must be
+// called before pruning starts.
+func (p *PrunerService) SetMaintenance(maintenance ports.MaintenanceService) {
+	p.maintenance = maintenance
+}
+
+// PruneExpired removes snapshots older than the retention period.
+// Symbols are pruned one DELETE at a time with bounded concurrency so that no
+// single statement holds locks across the whole table.
+func (p *PrunerService) PruneExpired(ctx context.Context) (*domain.PruneResult, error) {
+	start := p.clock.Now()
+	cutoff := start.UTC().Add(-p.retention)
+
+	// Removed symbols keep their history, which expires like any other
+	symbols, err := p.symbolRepo.ListIncludingDeleted(ctx)
+	if err != nil {
+		p.logger.Error("failed to list symbols", "error", err)
+		return nil, domain.ErrInternal
+	}
+
+	result := &domain.PruneResult{
+		Cutoff:   cutoff,
+		BySymbol: make(map[string]int64, len(symbols)),
+	}
+
+	var (
+		mu  sync.Mutex
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, p.concurrency)
+	)
+
+	for _, s := range symbols {
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			return result, ctx.Err()
+		case sem <- struct{}{}:
+		}
+
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			deleted, err := p.snapshotRepo.PruneBySymbol(ctx, name, cutoff)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if err != nil {
+				p.logger.Error("failed to prune symbol", "symbol", name, "error", err)
+				result.Failed = append(result.Failed, name)
+				return
+			}
+
+			result.BySymbol[name] = deleted
+			result.Deleted += deleted
+			p.metrics.RecordPrune(name, deleted)
+
+			p.logger.Info("pruned symbol",
+				"symbol", name,
+				"deleted", deleted,
+				"progress", len(result.BySymbol)+len(result.Failed),
+				"total", len(symbols),
+			)
+		}(s.Name)
+	}
+
+	wg.Wait()
+
+	if p.tickerRepo != nil {
+		if deleted, err := p.tickerRepo.Prune(ctx, cutoff); err != nil {
+			p.logger.Error("failed to prune ticker stats", "error", err)
+		} else {
+			p.logger.Info("pruned ticker stats", "deleted", deleted)
+		}
+	}
+
+	if p.maintenance != nil {
+		p.maintenance.RowsChanged(ctx, "prune", result.Deleted)
+	}
+
+	result.DurationMs = p.clock.Since(start).Milliseconds()
+
+	p.logger.Info("prune completed",
+		"symbols", len(symbols),
+		"deleted", result.Deleted,
+		"failed", len(result.Failed),
+		"duration_ms", result.DurationMs,
+	)
+
+	return result, nil
+}
+
+// Ensure PrunerService implements ports.PrunerService
+var _ ports.PrunerService = (*PrunerService)(nil)
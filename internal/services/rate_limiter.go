@@ -0,0 +1,76 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+)
+
+// RateLimiter implements the ports.RateLimiter interface with fixed-window
+// counters kept in the repository, so limits hold across restarts and are
+// shared by every replica
+type RateLimiter struct {
+	repo   ports.RateLimitRepository
+	limit  int
+	window time.Duration
+	logger *slog.Logger
+
+	mu       sync.Mutex
+	prunedAt time.Time
+}
+
+// NewRateLimiter creates a rate limiter allowing limit requests per key in
+// each window
+func NewRateLimiter(repo ports.RateLimitRepository, limit int, window time.Duration, logger *slog.Logger) *RateLimiter {
+	return &RateLimiter{
+		repo:   repo,
+		limit:  limit,
+		window: window,
+		logger: logger.With("component", "rate_limiter"),
+	}
+}
+
+// Allow counts a request against key and reports whether it is within the
+// limit of the current window
+func (l *RateLimiter) Allow(ctx context.Context, key string) (*domain.RateLimitDecision, error) {
+	windowStart := domain.RateLimitWindow(time.Now().UTC(), l.window)
+
+	count, err := l.repo.Increment(ctx, key, windowStart)
+	if err != nil {
+		l.logger.Error("failed to count request", "key", key, "error", err)
+		return nil, domain.ErrInternal
+	}
+
+	l.pruneExpired(ctx, windowStart)
+
+	return domain.NewRateLimitDecision(l.limit, count, windowStart.Add(l.window)), nil
+}
+
+// pruneExpired drops counters of past windows, at most once per window per
+// process. Active keys drop their own past windows on increment; this
+// catches keys that went quiet.
+func (l *RateLimiter) pruneExpired(ctx context.Context, windowStart time.Time) {
+	l.mu.Lock()
+	if !l.prunedAt.Before(windowStart) {
+		l.mu.Unlock()
+		return
+	}
+	l.prunedAt = windowStart
+	l.mu.Unlock()
+
+	deleted, err := l.repo.DeleteBefore(ctx, windowStart)
+	if err != nil {
+		l.logger.Warn("failed to prune rate limit counters", "error", err)
+		return
+	}
+	if deleted > 0 {
+		l.logger.Debug("pruned rate limit counters", "deleted", deleted)
+	}
+}
+
+// Ensure RateLimiter implements ports.RateLimiter
+var _ ports.RateLimiter = (*RateLimiter)(nil)
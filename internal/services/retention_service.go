@@ -0,0 +1,72 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+)
+
+// growthWindow is the period observed when measuring storage growth
+const growthWindow = 7 * 24 * time.Hour
+
+// RetentionService implements the ports.RetentionService interface
+type RetentionService struct {
+	symbolRepo    ports.SymbolRepository
+	snapshotRepo  ports.SnapshotRepository
+	pollInterval  time.Duration
+	retentionDays int
+	diskThreshold int64
+	logger        *slog.Logger
+}
+
+// NewRetentionService creates a new retention report service. A
+// diskThreshold of zero disables the threshold forecast.
+func NewRetentionService(
+	symbolRepo ports.SymbolRepository,
+	snapshotRepo ports.SnapshotRepository,
+	pollInterval time.Duration,
+	retentionDays int,
+	diskThreshold int64,
+	logger *slog.Logger,
+) *RetentionService {
+	return &RetentionService{
+		symbolRepo:    symbolRepo,
+		snapshotRepo:  snapshotRepo,
+		pollInterval:  pollInterval,
+		retentionDays: retentionDays,
+		diskThreshold: diskThreshold,
+		logger:        logger.With("component", "retention_service"),
+	}
+}
+
+// Report estimates storage growth over the last 7 days and forecasts when
+// the disk threshold will be reached at the current polling rate
+func (s *RetentionService) Report(ctx context.Context) (*domain.RetentionReport, error) {
+	now := time.Now().UTC()
+
+	stats, err := s.snapshotRepo.GetStorageStats(ctx, now.Add(-growthWindow))
+	if err != nil {
+		s.logger.Error("failed to get storage stats", "error", err)
+		return nil, domain.ErrInternal
+	}
+
+	active, err := s.symbolRepo.CountActive(ctx)
+	if err != nil {
+		s.logger.Error("failed to count active symbols", "error", err)
+		return nil, domain.ErrInternal
+	}
+
+	return domain.NewRetentionReport(stats, domain.RetentionParams{
+		Window:             growthWindow,
+		PollInterval:       s.pollInterval,
+		ActiveSymbols:      active,
+		RetentionDays:      s.retentionDays,
+		DiskThresholdBytes: s.diskThreshold,
+	}, now), nil
+}
+
+// Ensure RetentionService implements ports.RetentionService
+var _ ports.RetentionService = (*RetentionService)(nil)
@@ -0,0 +1,42 @@
+package services
+
+import (
+	"os"
+	"runtime"
+	"time"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+)
+
+// readRuntimeStats samples the Go runtime. Reading memory statistics
+// briefly stops the world, so it is only done when metrics are requested.
+func readRuntimeStats() *domain.RuntimeStats {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	stats := &domain.RuntimeStats{
+		Goroutines:     runtime.NumGoroutine(),
+		HeapAllocBytes: mem.HeapAlloc,
+		HeapSysBytes:   mem.HeapSys,
+		GCCycles:       mem.NumGC,
+		GCPauseTotalMs: durationMs(time.Duration(mem.PauseTotalNs)),
+	}
+	if mem.NumGC > 0 {
+		stats.LastGCPauseMs = durationMs(time.Duration(mem.PauseNs[(mem.NumGC+255)%256]))
+	}
+	if fds, err := openFDs(); err == nil {
+		stats.OpenFDs = &fds
+	}
+	return stats
+}
+
+// openFDs counts the process's open file descriptors. It fails where
+// /proc is not available, e.g. on macOS.
+func openFDs() (int, error) {
+	entries, err := os.ReadDir("/proc/self/fd")
+	if err != nil {
+		return 0, err
+	}
+	// The directory read holds a descriptor of its own
+	return len(entries) - 1, nil
+}
@@ -0,0 +1,44 @@
+package services
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+)
+
+// SlowQueryCounter counts slow database statements per query name. It
+// implements ports.SlowQueryRecorder.
+type SlowQueryCounter struct {
+	mu     sync.Mutex
+	counts map[string]int64
+}
+
+// NewSlowQueryCounter creates an empty slow query counter
+func NewSlowQueryCounter() *SlowQueryCounter {
+	return &SlowQueryCounter{counts: make(map[string]int64)}
+}
+
+// RecordSlowQuery counts a slow execution of the named query
+func (c *SlowQueryCounter) RecordSlowQuery(query string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.counts[query]++
+}
+
+// Counts returns the non-zero counters ordered by query name
+func (c *SlowQueryCounter) Counts() []domain.SlowQueryCount {
+	c.mu.Lock()
+	counts := make([]domain.SlowQueryCount, 0, len(c.counts))
+	for query, n := range c.counts {
+		counts = append(counts, domain.SlowQueryCount{Query: query, Count: n})
+	}
+	c.mu.Unlock()
+
+	sort.Slice(counts, func(i, j int) bool { return counts[i].Query < counts[j].Query })
+	return counts
+}
+
+// Ensure SlowQueryCounter implements ports.SlowQueryRecorder
+var _ ports.SlowQueryRecorder = (*SlowQueryCounter)(nil)
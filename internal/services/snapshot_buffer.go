@@ -0,0 +1,115 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+)
+
+// snapshotBufferBacklog is how many flushes' worth of snapshots the buffer
+// keeps while the database rejects them before it refuses new ones
+const snapshotBufferBacklog = 10
+
+// BufferedSnapshotRepository wraps a ports.SnapshotRepository and holds the
+// batches passed to CreateBatch in memory until Flush writes them as one.
+// Reads go straight to the wrapped repository, so buffered snapshots are
+// not visible until flushed. When a flush cannot reach the database its
+// snapshots stay buffered for the next one, their poll run IDs keeping them
+// from being stored twice; snapshots the database rejects, such as those of
+// a symbol removed in the meantime, are dropped so they cannot block the rest.
+type BufferedSnapshotRepository struct {
+	ports.SnapshotRepository
+
+	maxRows int
+	logger  *slog.Logger
+	full    chan struct{}
+
+	// flushMu serializes flushes so that retried snapshots keep their order
+	flushMu sync.Mutex
+
+	mu      sync.Mutex
+	pending []*domain.PriceSnapshot
+}
+
+// NewBufferedSnapshotRepository creates a snapshot buffer that asks to be
+// flushed once it holds maxRows snapshots
+func NewBufferedSnapshotRepository(repo ports.SnapshotRepository, maxRows int, logger *slog.Logger) *BufferedSnapshotRepository {
+	return &BufferedSnapshotRepository{
+		SnapshotRepository: repo,
+		maxRows:            maxRows,
+		logger:             logger.With("component", "snapshot_buffer"),
+		full:               make(chan struct{}, 1),
+	}
+}
+
+// CreateBatch buffers snapshots until the next flush. It fails when
+// earlier flushes could not keep up and the backlog is exhausted.
+func (b *BufferedSnapshotRepository) CreateBatch(ctx context.Context, snapshots []*domain.PriceSnapshot) error {
+	if len(snapshots) == 0 {
+		return nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if len(b.pending)+len(snapshots) > b.maxRows*snapshotBufferBacklog {
+		return fmt.Errorf("snapshot buffer full with %d unflushed snapshots", len(b.pending))
+	}
+
+	b.pending = append(b.pending, snapshots...)
+	if len(b.pending) >= b.maxRows {
+		select {
+		case b.full <- struct{}{}:
+		default:
+		}
+	}
+	return nil
+}
+
+// Flush writes every buffered snapshot in one batch
+func (b *BufferedSnapshotRepository) Flush(ctx context.Context) (int, error) {
+	b.flushMu.Lock()
+	defer b.flushMu.Unlock()
+
+	b.mu.Lock()
+	batch := b.pending
+	b.pending = nil
+	b.mu.Unlock()
+
+	if len(batch) == 0 {
+		return 0, nil
+	}
+
+	result, err := b.SnapshotRepository.CreateBatchPartial(ctx, batch)
+	if err != nil {
+		b.mu.Lock()
+		b.pending = append(batch, b.pending...)
+		b.mu.Unlock()
+
+		b.logger.Error("failed to flush snapshot buffer", "snapshots", len(batch), "error", err)
+		return 0, err
+	}
+
+	for _, f := range result.Failed {
+		b.logger.Warn("dropped buffered snapshot", "symbol", f.Symbol, "error", f.Error)
+	}
+
+	b.logger.Debug("snapshot buffer flushed", "snapshots", result.Written, "dropped", len(result.Failed))
+	return result.Written, nil
+}
+
+// Full receives when the buffer reached its row limit
+func (b *BufferedSnapshotRepository) Full() <-chan struct{} {
+	return b.full
+}
+
+// Ensure BufferedSnapshotRepository implements ports.SnapshotRepository and
+// ports.SnapshotBuffer
+var (
+	_ ports.SnapshotRepository = (*BufferedSnapshotRepository)(nil)
+	_ ports.SnapshotBuffer     = (*BufferedSnapshotRepository)(nil)
+)
@@ -3,7 +3,8 @@ package services
 import (
 	"context"
 	"log/slog"
-	"strings"
+	"sort"
+	"time"
 
 	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
 	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
@@ -13,6 +14,7 @@ import (
 type SnapshotService struct {
 	snapshotRepo ports.SnapshotRepository
 	symbolRepo   ports.SymbolRepository
+	symbols      *domain.SymbolNormalizer
 	logger       *slog.Logger
 }
 
@@ -20,11 +22,13 @@ type SnapshotService struct {
 func NewSnapshotService(
 	snapshotRepo ports.SnapshotRepository,
 	symbolRepo ports.SymbolRepository,
+	symbols *domain.SymbolNormalizer,
 	logger *slog.Logger,
 ) *SnapshotService {
 	return &SnapshotService{
 		snapshotRepo: snapshotRepo,
 		symbolRepo:   symbolRepo,
+		symbols:      symbols,
 		logger:       logger.With("component", "snapshot_service"),
 	}
 }
@@ -37,10 +41,7 @@ func (s *SnapshotService) GetLatestPrices(ctx context.Context, symbols []string)
 	}
 
 	// Normalize symbols
-	normalizedSymbols := make([]string, len(symbols))
-	for i, sym := range symbols {
-		normalizedSymbols[i] = strings.ToUpper(strings.TrimSpace(sym))
-	}
+	normalizedSymbols := s.symbols.NormalizeAll(symbols)
 
 	// Get latest snapshots
 	snapshots, err := s.snapshotRepo.GetLatestBySymbols(ctx, normalizedSymbols)
@@ -65,30 +66,39 @@ func (s *SnapshotService) GetLatestPrices(ctx context.Context, symbols []string)
 	return snapshots, missing, nil
 }
 
-// GetPriceHistory returns historical prices for a symbol
-func (s *SnapshotService) GetPriceHistory(ctx context.Context, symbol string, limit int) ([]*domain.PriceSnapshot, error) {
-	symbol = strings.ToUpper(strings.TrimSpace(symbol))
-
-	// Validate limit
-	if limit <= 0 {
-		limit = 100
-	}
-	if limit > 1000 {
-		limit = 1000
+// GetPreviousPrices returns the snapshot before the latest one for the
+// specified symbols, keyed by symbol. Symbols with a single snapshot are
+// left out.
+func (s *SnapshotService) GetPreviousPrices(ctx context.Context, symbols []string) (map[string]*domain.PriceSnapshot, error) {
+	if len(symbols) == 0 {
+		return nil, nil
 	}
 
-	// Check if symbol is tracked
-	exists, err := s.symbolRepo.Exists(ctx, symbol)
+	normalizedSymbols := s.symbols.NormalizeAll(symbols)
+
+	snapshots, err := s.snapshotRepo.GetPreviousBySymbols(ctx, normalizedSymbols)
 	if err != nil {
-		s.logger.Error("failed to check symbol existence", "symbol", symbol, "error", err)
+		s.logger.Error("failed to get previous prices", "error", err)
 		return nil, domain.ErrInternal
 	}
-	if !exists {
-		return nil, domain.ErrSymbolNotFound
+
+	previous := make(map[string]*domain.PriceSnapshot, len(snapshots))
+	for _, snap := range snapshots {
+		previous[snap.Symbol] = snap
+	}
+	return previous, nil
+}
+
+// GetPriceHistory returns historical prices for a symbol, newest first,
+// continuing after before when it is set
+func (s *SnapshotService) GetPriceHistory(ctx context.Context, symbol string, before *domain.HistoryCursor, limit int) ([]*domain.PriceSnapshot, error) {
+	symbol, limit, err := s.prepareHistory(ctx, symbol, limit)
+	if err != nil {
+		return nil, err
 	}
 
 	// Get history
-	history, err := s.snapshotRepo.GetHistory(ctx, symbol, limit)
+	history, err := s.snapshotRepo.GetHistory(ctx, symbol, before, limit)
 	if err != nil {
 		s.logger.Error("failed to get price history", "symbol", symbol, "error", err)
 		return nil, domain.ErrInternal
@@ -97,5 +107,176 @@ func (s *SnapshotService) GetPriceHistory(ctx context.Context, symbol string, li
 	return history, nil
 }
 
+// GetPriceHistoryBetween returns historical prices for a symbol within a
+// time range, newest first, continuing after before when it is set
+func (s *SnapshotService) GetPriceHistoryBetween(ctx context.Context, symbol string, from, to time.Time, before *domain.HistoryCursor, limit int) ([]*domain.PriceSnapshot, error) {
+	if from.After(to) {
+		return nil, domain.ErrInvalidTimeRange
+	}
+
+	symbol, limit, err := s.prepareHistory(ctx, symbol, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	history, err := s.snapshotRepo.GetHistoryBetween(ctx, symbol, from, to, before, limit)
+	if err != nil {
+		s.logger.Error("failed to get price history", "symbol", symbol, "from", from, "to", to, "error", err)
+		return nil, domain.ErrInternal
+	}
+
+	return history, nil
+}
+
+// GetHistorySummary returns how many snapshots are stored for a tracked
+// symbol and when the oldest and newest were taken
+func (s *SnapshotService) GetHistorySummary(ctx context.Context, symbol string) (*domain.HistorySummary, error) {
+	symbol, _, err := s.prepareHistory(ctx, symbol, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	summary, err := s.snapshotRepo.GetHistorySummary(ctx, symbol)
+	if err != nil {
+		s.logger.Error("failed to summarize price history", "symbol", symbol, "error", err)
+		return nil, domain.ErrInternal
+	}
+
+	return summary, nil
+}
+
+// GetStaleSymbols returns the active symbols whose latest snapshot is
+// older than olderThan, or that have none, ordered by symbol
+func (s *SnapshotService) GetStaleSymbols(ctx context.Context, olderThan time.Time) ([]*domain.StaleSymbol, error) {
+	symbols, err := s.symbolRepo.ListActive(ctx)
+	if err != nil {
+		s.logger.Error("failed to list active symbols", "error", err)
+		return nil, domain.ErrInternal
+	}
+	if len(symbols) == 0 {
+		return []*domain.StaleSymbol{}, nil
+	}
+
+	names := make([]string, len(symbols))
+	for i, sym := range symbols {
+		names[i] = sym.Name
+	}
+
+	latest, err := s.snapshotRepo.GetLatestBySymbols(ctx, names)
+	if err != nil {
+		s.logger.Error("failed to get latest prices", "error", err)
+		return nil, domain.ErrInternal
+	}
+
+	latestAt := make(map[string]time.Time, len(latest))
+	for _, snap := range latest {
+		latestAt[snap.Symbol] = snap.Timestamp
+	}
+
+	stale := []*domain.StaleSymbol{}
+	for _, name := range names {
+		at, ok := latestAt[name]
+		switch {
+		case !ok:
+			stale = append(stale, &domain.StaleSymbol{Symbol: name})
+		case at.Before(olderThan):
+			stale = append(stale, &domain.StaleSymbol{Symbol: name, LastSnapshotAt: &at})
+		}
+	}
+
+	sort.Slice(stale, func(i, j int) bool { return stale[i].Symbol < stale[j].Symbol })
+	return stale, nil
+}
+
+// snapshotDeleteBatch is the number of rows each statement of a range
+// deletion removes
+const snapshotDeleteBatch = 5000
+
+// DeleteSnapshotRange removes a tracked symbol's snapshots in [from, to),
+// or with dryRun only counts them
+func (s *SnapshotService) DeleteSnapshotRange(ctx context.Context, symbol string, from, to time.Time, dryRun bool) (*domain.SnapshotRangeDeletion, error) {
+	if !from.Before(to) {
+		return nil, domain.ErrInvalidTimeRange
+	}
+
+	symbol, _, err := s.prepareHistory(ctx, symbol, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	if dryRun {
+		result, err := s.snapshotRepo.CountRange(ctx, symbol, from, to)
+		if err != nil {
+			s.logger.Error("failed to count snapshot range", "symbol", symbol, "from", from, "to", to, "error", err)
+			return nil, domain.ErrInternal
+		}
+		return result, nil
+	}
+
+	result, err := s.snapshotRepo.DeleteRange(ctx, symbol, from, to, snapshotDeleteBatch)
+	if err != nil {
+		s.logger.Error("failed to delete snapshot range", "symbol", symbol, "from", from, "to", to, "error", err)
+		return nil, domain.ErrInternal
+	}
+	return result, nil
+}
+
+// GetPollRun returns the snapshots a poll stored, or
+// domain.ErrPollRunNotFound when it stored none that are left
+func (s *SnapshotService) GetPollRun(ctx context.Context, runID string) (*domain.PollRun, error) {
+	snapshots, err := s.snapshotRepo.GetPollRun(ctx, runID)
+	if err != nil {
+		s.logger.Error("failed to get poll run", "run_id", runID, "error", err)
+		return nil, domain.ErrInternal
+	}
+	if len(snapshots) == 0 {
+		return nil, domain.ErrPollRunNotFound
+	}
+
+	return &domain.PollRun{ID: runID, Snapshots: snapshots}, nil
+}
+
+// DeletePollRun removes the snapshots a poll stored, returning
+// domain.ErrPollRunNotFound when there were none
+func (s *SnapshotService) DeletePollRun(ctx context.Context, runID string) (int64, error) {
+	deleted, err := s.snapshotRepo.DeletePollRun(ctx, runID)
+	if err != nil {
+		s.logger.Error("failed to delete poll run", "run_id", runID, "error", err)
+		return 0, domain.ErrInternal
+	}
+	if deleted == 0 {
+		return 0, domain.ErrPollRunNotFound
+	}
+
+	s.logger.Info("poll run deleted", "run_id", runID, "snapshots", deleted)
+	return deleted, nil
+}
+
+// prepareHistory normalizes a history request and checks that the symbol
+// is tracked or was removed with its history kept
+func (s *SnapshotService) prepareHistory(ctx context.Context, symbol string, limit int) (string, int, error) {
+	symbol = s.symbols.Normalize(symbol)
+
+	// Validate limit
+	if limit <= 0 {
+		limit = 100
+	}
+	if limit > domain.MaxHistoryLimit {
+		limit = domain.MaxHistoryLimit
+	}
+
+	// Check if symbol is tracked, or was removed with its history kept
+	exists, err := s.symbolRepo.ExistsIncludingDeleted(ctx, symbol)
+	if err != nil {
+		s.logger.Error("failed to check symbol existence", "symbol", symbol, "error", err)
+		return "", 0, domain.ErrInternal
+	}
+	if !exists {
+		return "", 0, domain.ErrSymbolNotFound
+	}
+
+	return symbol, limit, nil
+}
+
 // Ensure SnapshotService implements ports.SnapshotService
 var _ ports.SnapshotService = (*SnapshotService)(nil)
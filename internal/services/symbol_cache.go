@@ -0,0 +1,193 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+)
+
+// CachedSymbolRepository wraps a ports.SymbolRepository and serves Exists,
+// GetByName and ListByTag from an in-memory copy of the symbol table. The
+// cache is dropped on every write through this wrapper and reloaded after the
+// TTL to pick up changes made by other instances.
+type CachedSymbolRepository struct {
+	ports.SymbolRepository
+
+	ttl    time.Duration
+	logger *slog.Logger
+
+	mu       sync.Mutex
+	byName   map[string]*domain.Symbol
+	loadedAt time.Time
+}
+
+// NewCachedSymbolRepository creates a caching symbol repository
+func NewCachedSymbolRepository(repo ports.SymbolRepository, ttl time.Duration, logger *slog.Logger) *CachedSymbolRepository {
+	return &CachedSymbolRepository{
+		SymbolRepository: repo,
+		ttl:              ttl,
+		logger:           logger.With("component", "symbol_cache"),
+	}
+}
+
+// Create adds a new symbol and invalidates the cache
+func (c *CachedSymbolRepository) Create(ctx context.Context, symbol *domain.Symbol) error {
+	if err := c.SymbolRepository.Create(ctx, symbol); err != nil {
+		return err
+	}
+	c.Invalidate()
+	return nil
+}
+
+// Delete removes a symbol and invalidates the cache
+func (c *CachedSymbolRepository) Delete(ctx context.Context, name string) error {
+	if err := c.SymbolRepository.Delete(ctx, name); err != nil {
+		return err
+	}
+	c.Invalidate()
+	return nil
+}
+
+// SoftDelete removes a symbol, keeping its snapshots, and invalidates the
+// cache
+func (c *CachedSymbolRepository) SoftDelete(ctx context.Context, name string) error {
+	if err := c.SymbolRepository.SoftDelete(ctx, name); err != nil {
+		return err
+	}
+	c.Invalidate()
+	return nil
+}
+
+// Update modifies a symbol and invalidates the cache
+func (c *CachedSymbolRepository) Update(ctx context.Context, symbol *domain.Symbol) error {
+	if err := c.SymbolRepository.Update(ctx, symbol); err != nil {
+		return err
+	}
+	c.Invalidate()
+	return nil
+}
+
+// SetActive sets the active flag of symbols and invalidates the cache
+func (c *CachedSymbolRepository) SetActive(ctx context.Context, names []string, active bool) ([]string, error) {
+	changed, err := c.SymbolRepository.SetActive(ctx, names, active)
+	if err != nil {
+		return nil, err
+	}
+	c.Invalidate()
+	return changed, nil
+}
+
+// UpdateMetadata stores a symbol's coin metadata and invalidates the cache
+func (c *CachedSymbolRepository) UpdateMetadata(ctx context.Context, name string, metadata *domain.CoinMetadata) error {
+	if err := c.SymbolRepository.UpdateMetadata(ctx, name, metadata); err != nil {
+		return err
+	}
+	c.Invalidate()
+	return nil
+}
+
+// GetByName retrieves a symbol by its name from the cache
+func (c *CachedSymbolRepository) GetByName(ctx context.Context, name string) (*domain.Symbol, error) {
+	symbols, err := c.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	symbol, ok := symbols[name]
+	if !ok {
+		return nil, domain.ErrSymbolNotFound
+	}
+
+	return copySymbol(symbol), nil
+}
+
+// ListByTag returns symbols carrying the given tag from the cache
+func (c *CachedSymbolRepository) ListByTag(ctx context.Context, tag string) ([]*domain.Symbol, error) {
+	symbols, err := c.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var tagged []*domain.Symbol
+	for _, s := range symbols {
+		if s.HasTag(tag) {
+			tagged = append(tagged, copySymbol(s))
+		}
+	}
+
+	sort.Slice(tagged, func(i, j int) bool { return tagged[i].Name < tagged[j].Name })
+	return tagged, nil
+}
+
+// copySymbol returns a copy so callers cannot mutate the cached entry
+func copySymbol(symbol *domain.Symbol) *domain.Symbol {
+	copied := *symbol
+	copied.Tags = append([]string(nil), symbol.Tags...)
+	if symbol.Metadata != nil {
+		metadata := *symbol.Metadata
+		copied.Metadata = &metadata
+	}
+	return &copied
+}
+
+// Exists checks if a symbol exists using the cache
+func (c *CachedSymbolRepository) Exists(ctx context.Context, name string) (bool, error) {
+	symbols, err := c.load(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	_, ok := symbols[name]
+	return ok, nil
+}
+
+// Invalidate drops the cached symbol set
+func (c *CachedSymbolRepository) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.byName = nil
+}
+
+// Reload drops the cached symbol set and loads it again
+func (c *CachedSymbolRepository) Reload(ctx context.Context) (int, error) {
+	c.Invalidate()
+	symbols, err := c.load(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return len(symbols), nil
+}
+
+// load returns the cached symbol set, reloading it when empty or expired
+func (c *CachedSymbolRepository) load(ctx context.Context) (map[string]*domain.Symbol, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.byName != nil && time.Since(c.loadedAt) < c.ttl {
+		return c.byName, nil
+	}
+
+	symbols, err := c.SymbolRepository.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string]*domain.Symbol, len(symbols))
+	for _, s := range symbols {
+		byName[s.Name] = s
+	}
+
+	c.byName = byName
+	c.loadedAt = time.Now()
+	c.logger.Debug("symbol cache loaded", "symbols", len(byName))
+
+	return byName, nil
+}
+
+// Ensure CachedSymbolRepository implements ports.SymbolRepository
+var _ ports.SymbolRepository = (*CachedSymbolRepository)(nil)
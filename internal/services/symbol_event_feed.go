@@ -0,0 +1,86 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+)
+
+const (
+	// symbolEventSubscriber names the webhook's delivery cursor
+	symbolEventSubscriber = "webhook"
+
+	// symbolEventBatch caps the events posted in one digest
+	symbolEventBatch = 500
+)
+
+// SymbolEventFeed implements the ports.SymbolEventFeedService interface
+type SymbolEventFeed struct {
+	events   ports.SymbolEventRepository
+	notifier ports.SymbolEventNotifier
+	types    []domain.SymbolEventType
+	logger   *slog.Logger
+}
+
+// NewSymbolEventFeed creates a feed delivering events of the given types
+// to notifier
+func NewSymbolEventFeed(
+	events ports.SymbolEventRepository,
+	notifier ports.SymbolEventNotifier,
+	types []domain.SymbolEventType,
+	logger *slog.Logger,
+) *SymbolEventFeed {
+	return &SymbolEventFeed{
+		events:   events,
+		notifier: notifier,
+		types:    types,
+		logger:   logger.With("component", "symbol_event_feed"),
+	}
+}
+
+// DeliverEvents posts the events recorded since the last delivery, oldest
+// first, in digests of up to symbolEventBatch events. The cursor only moves
+// past a digest once it was accepted, so a failed post is retried on the
+// next run and a subscriber may see an event twice.
+func (f *SymbolEventFeed) DeliverEvents(ctx context.Context) (int, error) {
+	cursor, err := f.events.DeliveryCursor(ctx, symbolEventSubscriber)
+	if err != nil {
+		f.logger.Error("failed to get symbol event cursor", "error", err)
+		return 0, domain.ErrInternal
+	}
+
+	delivered := 0
+	for {
+		events, err := f.events.ListAfter(ctx, cursor, f.types, symbolEventBatch)
+		if err != nil {
+			f.logger.Error("failed to list symbol events", "after", cursor, "error", err)
+			return delivered, domain.ErrInternal
+		}
+		if len(events) == 0 {
+			return delivered, nil
+		}
+
+		if err := f.notifier.NotifySymbolEvents(ctx, domain.NewSymbolEventDigest(events)); err != nil {
+			f.logger.Error("failed to deliver symbol events", "events", len(events), "error", err)
+			return delivered, err
+		}
+
+		cursor = events[len(events)-1].ID
+		if err := f.events.SetDeliveryCursor(ctx, symbolEventSubscriber, cursor); err != nil {
+			f.logger.Error("failed to set symbol event cursor", "cursor", cursor, "error", err)
+			return delivered, domain.ErrInternal
+		}
+		delivered += len(events)
+
+		f.logger.Info("symbol events delivered", "events", len(events), "cursor", cursor)
+
+		if len(events) < symbolEventBatch {
+			return delivered, nil
+		}
+	}
+}
+
+// Ensure SymbolEventFeed implements ports.SymbolEventFeedService
+var _ ports.SymbolEventFeedService = (*SymbolEventFeed)(nil)
@@ -3,8 +3,11 @@ package services
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log/slog"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
 	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
@@ -12,33 +15,70 @@ import (
 
 // SymbolService implements the ports.SymbolService interface
 type SymbolService struct {
-	repo     ports.SymbolRepository
-	exchange ports.ExchangeClient
-	logger   *slog.Logger
+	repo      ports.SymbolRepository
+	events    ports.SymbolEventRepository
+	exchanges ports.ExchangeRegistry
+	symbols   *domain.SymbolNormalizer
+	limits    domain.SymbolLimits
+	logger    *slog.Logger
+
+	// validationTimeout bounds the exchange check made while adding a
+	// symbol; revalidation is enqueued on jobs when that check cannot
+	// finish in time
+	validationTimeout time.Duration
+	jobs              ports.JobService
 }
 
 // NewSymbolService creates a new symbol service
 func NewSymbolService(
 	repo ports.SymbolRepository,
-	exchange ports.ExchangeClient,
+	events ports.SymbolEventRepository,
+	exchanges ports.ExchangeRegistry,
+	symbols *domain.SymbolNormalizer,
+	limits domain.SymbolLimits,
+	validationTimeout time.Duration,
 	logger *slog.Logger,
 ) *SymbolService {
 	return &SymbolService{
-		repo:     repo,
-		exchange: exchange,
-		logger:   logger.With("component", "symbol_service"),
+		repo:              repo,
+		events:            events,
+		exchanges:         exchanges,
+		symbols:           symbols,
+		limits:            limits,
+		validationTimeout: validationTimeout,
+		logger:            logger.With("component", "symbol_service"),
 	}
 }
 
-// AddSymbol adds a new symbol to track
-func (s *SymbolService) AddSymbol(ctx context.Context, name string) (*domain.Symbol, error) {
-	name = strings.ToUpper(strings.TrimSpace(name))
+// SetRevalidation makes AddSymbol accept a symbol whose exchange check did
+// not finish in time, and enqueue a revalidate job on jobs to verify it
+// thoroughly, instead of failing the request
+func (s *SymbolService) SetRevalidation(jobs ports.JobService) {
+	s.jobs = jobs
+}
+
+// AddSymbol adds a new symbol to track on the named exchange, or on the
+// default exchange when exchangeName is empty, optionally with tags
+func (s *SymbolService) AddSymbol(ctx context.Context, name, exchangeName string, tags ...string) (*domain.Symbol, error) {
+	name = s.symbols.Normalize(name)
 
 	// Create and validate symbol
 	symbol, err := domain.NewSymbol(name)
 	if err != nil {
 		return nil, err
 	}
+	if err := symbol.SetTags(tags); err != nil {
+		return nil, err
+	}
+
+	exchange, err := s.exchanges.Client(exchangeName)
+	if err != nil {
+		return nil, err
+	}
+	symbol.Exchange = strings.ToLower(strings.TrimSpace(exchangeName))
+	if symbol.Exchange == "" {
+		symbol.Exchange = s.exchanges.Default()
+	}
 
 	// Check if already tracked
 	exists, err := s.repo.Exists(ctx, name)
@@ -50,30 +90,83 @@ func (s *SymbolService) AddSymbol(ctx context.Context, name string) (*domain.Sym
 		return nil, domain.ErrSymbolExists
 	}
 
+	if err := s.checkLimits(ctx); err != nil {
+		return nil, err
+	}
+
 	// Validate symbol exists on exchange
-	valid, err := s.exchange.ValidateSymbol(ctx, name)
+	valid, err := s.validate(ctx, exchange, name)
+	deferred := err != nil && s.jobs != nil
 	if err != nil {
-		s.logger.Error("failed to validate symbol on exchange",
-			"symbol", name, "error", err)
-		return nil, domain.ErrExchangeUnavailable
-	}
-	if !valid {
+		s.logger.Warn("failed to validate symbol on exchange",
+			"symbol", name, "exchange", symbol.Exchange, "deferred", deferred, "error", err)
+		if !deferred {
+			return nil, domain.ErrExchangeUnavailable
+		}
+	} else if !valid {
 		return nil, domain.ErrInvalidSymbol
 	}
 
 	// Create in repository
 	if err := s.repo.Create(ctx, symbol); err != nil {
+		if errors.Is(err, domain.ErrSymbolExists) {
+			return nil, err
+		}
 		s.logger.Error("failed to create symbol", "symbol", name, "error", err)
 		return nil, domain.ErrInternal
 	}
 
-	s.logger.Info("symbol added", "symbol", name, "id", symbol.ID)
+	details := map[string]interface{}{
+		"exchange": symbol.Exchange,
+		"tags":     symbol.Tags,
+	}
+	if deferred {
+		details["validation"] = "deferred"
+		if _, err := s.jobs.Enqueue(ctx, domain.JobTypeRevalidate, nil, time.Time{}); err != nil {
+			s.logger.Error("failed to enqueue symbol revalidation", "symbol", name, "error", err)
+		}
+	}
+	s.recordEvent(ctx, domain.NewSymbolEvent(name, domain.SymbolEventAdded, details))
+
+	s.logger.Info("symbol added", "symbol", name, "exchange", symbol.Exchange, "id", symbol.ID)
 	return symbol, nil
 }
 
-// RemoveSymbol stops tracking a symbol
+// validate checks that the exchange trades name with a single attempt
+// bounded by validationTimeout, so a slow exchange cannot hold up the request
+func (s *SymbolService) validate(ctx context.Context, exchange ports.ExchangeClient, name string) (bool, error) {
+	ctx = domain.WithSingleExchangeAttempt(domain.WithExchangePriority(ctx, domain.ExchangePriorityValidation))
+	if s.validationTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.validationTimeout)
+		defer cancel()
+	}
+	return exchange.ValidateSymbol(ctx, name)
+}
+
+// RemoveSymbol stops tracking a symbol. Its snapshots are kept and stay
+// queryable; adding the symbol again resumes its history.
 func (s *SymbolService) RemoveSymbol(ctx context.Context, name string) error {
-	name = strings.ToUpper(strings.TrimSpace(name))
+	name = s.symbols.Normalize(name)
+
+	if err := s.repo.SoftDelete(ctx, name); err != nil {
+		if errors.Is(err, domain.ErrSymbolNotFound) {
+			return err
+		}
+		s.logger.Error("failed to remove symbol", "symbol", name, "error", err)
+		return domain.ErrInternal
+	}
+
+	s.recordEvent(ctx, domain.NewSymbolEvent(name, domain.SymbolEventRemoved, nil))
+
+	s.logger.Info("symbol removed", "symbol", name)
+	return nil
+}
+
+// PurgeSymbol deletes a symbol and all of its snapshots, whether it is
+// still tracked or was removed before
+func (s *SymbolService) PurgeSymbol(ctx context.Context, name string) error {
+	name = s.symbols.Normalize(name)
 
 	if err := s.repo.Delete(ctx, name); err != nil {
 		if errors.Is(err, domain.ErrSymbolNotFound) {
@@ -83,7 +176,11 @@ func (s *SymbolService) RemoveSymbol(ctx context.Context, name string) error {
 		return domain.ErrInternal
 	}
 
-	s.logger.Info("symbol removed", "symbol", name)
+	s.recordEvent(ctx, domain.NewSymbolEvent(name, domain.SymbolEventRemoved, map[string]interface{}{
+		"purged": true,
+	}))
+
+	s.logger.Info("symbol purged", "symbol", name)
 	return nil
 }
 
@@ -97,17 +194,308 @@ func (s *SymbolService) ListSymbols(ctx context.Context) ([]*domain.Symbol, erro
 	return symbols, nil
 }
 
+// ListSymbolsByTag returns tracked symbols carrying the given tag
+func (s *SymbolService) ListSymbolsByTag(ctx context.Context, tag string) ([]*domain.Symbol, error) {
+	tag, err := domain.NormalizeTag(tag)
+	if err != nil {
+		return nil, err
+	}
+
+	symbols, err := s.repo.ListByTag(ctx, tag)
+	if err != nil {
+		s.logger.Error("failed to list symbols by tag", "tag", tag, "error", err)
+		return nil, domain.ErrInternal
+	}
+	return symbols, nil
+}
+
+// ListSymbolsPage returns a page of tracked symbols ordered by name,
+// optionally carrying a tag
+func (s *SymbolService) ListSymbolsPage(ctx context.Context, tag string, after *domain.SymbolCursor, limit int) ([]*domain.Symbol, error) {
+	if tag != "" {
+		var err error
+		if tag, err = domain.NormalizeTag(tag); err != nil {
+			return nil, err
+		}
+	}
+
+	symbols, err := s.repo.ListPage(ctx, tag, after, limit)
+	if err != nil {
+		s.logger.Error("failed to list symbol page", "tag", tag, "error", err)
+		return nil, domain.ErrInternal
+	}
+	return symbols, nil
+}
+
+// SetTags replaces a symbol's tags
+func (s *SymbolService) SetTags(ctx context.Context, name string, tags []string) (*domain.Symbol, error) {
+	name = s.symbols.Normalize(name)
+
+	symbol, err := s.repo.GetByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	previous := symbol.Tags
+	if err := symbol.SetTags(tags); err != nil {
+		return nil, err
+	}
+	if slices.Equal(previous, symbol.Tags) {
+		return symbol, nil
+	}
+
+	if err := s.repo.Update(ctx, symbol); err != nil {
+		s.logger.Error("failed to update symbol tags", "symbol", name, "error", err)
+		return nil, domain.ErrInternal
+	}
+
+	s.recordEvent(ctx, domain.NewTagsChangedEvent(name, previous, symbol.Tags))
+
+	s.logger.Info("symbol tags updated", "symbol", name, "tags", symbol.Tags)
+	return symbol, nil
+}
+
+// RemoveTag removes a single tag from a symbol. Removing a tag the symbol
+// does not carry is not an error.
+func (s *SymbolService) RemoveTag(ctx context.Context, name, tag string) (*domain.Symbol, error) {
+	name = s.symbols.Normalize(name)
+
+	tag, err := domain.NormalizeTag(tag)
+	if err != nil {
+		return nil, err
+	}
+
+	symbol, err := s.repo.GetByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	previous := symbol.Tags
+	if !symbol.RemoveTag(tag) {
+		return symbol, nil
+	}
+
+	if err := s.repo.Update(ctx, symbol); err != nil {
+		s.logger.Error("failed to update symbol tags", "symbol", name, "error", err)
+		return nil, domain.ErrInternal
+	}
+
+	s.recordEvent(ctx, domain.NewTagsChangedEvent(name, previous, symbol.Tags))
+
+	s.logger.Info("symbol tag removed", "symbol", name, "tag", tag)
+	return symbol, nil
+}
+
+// SetActive resumes or pauses polling of a symbol. Its snapshots are kept
+// either way; activating it again counts against the active symbol cap.
+func (s *SymbolService) SetActive(ctx context.Context, name string, active bool) (*domain.Symbol, error) {
+	name = s.symbols.Normalize(name)
+
+	symbol, err := s.repo.GetByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if symbol.Active == active {
+		return symbol, nil
+	}
+
+	event := domain.SymbolEventDeactivated
+	if active {
+		if err := s.checkActiveLimit(ctx); err != nil {
+			return nil, err
+		}
+		symbol.Activate()
+		event = domain.SymbolEventActivated
+	} else {
+		symbol.Deactivate()
+	}
+
+	if err := s.repo.Update(ctx, symbol); err != nil {
+		s.logger.Error("failed to update symbol", "symbol", name, "error", err)
+		return nil, domain.ErrInternal
+	}
+
+	s.recordEvent(ctx, domain.NewSymbolEvent(name, event, nil))
+
+	s.logger.Info("symbol "+string(event), "symbol", name)
+	return symbol, nil
+}
+
+// SetActiveBatch resumes or pauses polling of several symbols at once.
+// Every symbol is checked before anything is changed: when one is not
+// tracked, or activating the batch would exceed the active symbol cap, no
+// symbol is changed and the others are reported as skipped. The changes
+// are applied in one transaction.
+func (s *SymbolService) SetActiveBatch(ctx context.Context, names []string, active bool) (*domain.SymbolBatch, error) {
+	names = s.symbols.NormalizeAll(names)
+
+	batch := &domain.SymbolBatch{Results: make([]domain.SymbolBatchResult, 0, len(names))}
+	seen := make(map[string]bool, len(names))
+	var changing []string
+	for _, name := range names {
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		symbol, err := s.repo.GetByName(ctx, name)
+		if errors.Is(err, domain.ErrSymbolNotFound) {
+			batch.Results = append(batch.Results, domain.SymbolBatchResult{
+				Symbol:  name,
+				Outcome: domain.SymbolBatchNotFound,
+				Error:   err.Error(),
+			})
+			continue
+		}
+		if err != nil {
+			s.logger.Error("failed to get symbol", "symbol", name, "error", err)
+			return nil, domain.ErrInternal
+		}
+
+		outcome := domain.SymbolBatchUnchanged
+		if symbol.Active != active {
+			outcome = domain.SymbolBatchDeactivated
+			if active {
+				outcome = domain.SymbolBatchActivated
+			}
+			changing = append(changing, name)
+		}
+		batch.Results = append(batch.Results, domain.SymbolBatchResult{Symbol: name, Outcome: outcome})
+	}
+
+	if active && len(changing) > 0 {
+		if err := s.checkActiveLimitFor(ctx, len(changing)); err != nil {
+			if !errors.Is(err, domain.ErrSymbolQuota) {
+				return nil, err
+			}
+			for i, r := range batch.Results {
+				if r.Outcome == domain.SymbolBatchActivated {
+					batch.Results[i] = domain.SymbolBatchResult{
+						Symbol:  r.Symbol,
+						Outcome: domain.SymbolBatchQuotaExceeded,
+						Error:   err.Error(),
+					}
+				}
+			}
+		}
+	}
+
+	if batch.Failed() {
+		batch.Skip()
+		return batch, nil
+	}
+
+	if len(changing) > 0 {
+		changed, err := s.repo.SetActive(ctx, changing, active)
+		if errors.Is(err, domain.ErrSymbolNotFound) {
+			// A symbol was removed since it was checked
+			return nil, err
+		}
+		if err != nil {
+			s.logger.Error("failed to update symbols", "symbols", len(changing), "error", err)
+			return nil, domain.ErrInternal
+		}
+
+		event := domain.SymbolEventDeactivated
+		if active {
+			event = domain.SymbolEventActivated
+		}
+		for _, name := range changed {
+			s.recordEvent(ctx, domain.NewSymbolEvent(name, event, nil))
+		}
+		s.logger.Info("symbols "+string(event), "symbols", len(changed))
+	}
+
+	batch.Applied = true
+	return batch, nil
+}
+
 // GetSymbol retrieves a specific symbol
 func (s *SymbolService) GetSymbol(ctx context.Context, name string) (*domain.Symbol, error) {
-	name = strings.ToUpper(strings.TrimSpace(name))
+	name = s.symbols.Normalize(name)
 	return s.repo.GetByName(ctx, name)
 }
 
 // SymbolExists checks if a symbol is being tracked
 func (s *SymbolService) SymbolExists(ctx context.Context, name string) (bool, error) {
-	name = strings.ToUpper(strings.TrimSpace(name))
+	name = s.symbols.Normalize(name)
 	return s.repo.Exists(ctx, name)
 }
 
+// ListEvents returns a symbol's change history, newest first. A symbol that
+// was never tracked has no history and is reported as not found.
+func (s *SymbolService) ListEvents(ctx context.Context, name string, limit int) ([]*domain.SymbolEvent, error) {
+	name = s.symbols.Normalize(name)
+
+	events, err := s.events.ListBySymbol(ctx, name, limit)
+	if err != nil {
+		s.logger.Error("failed to list symbol events", "symbol", name, "error", err)
+		return nil, domain.ErrInternal
+	}
+
+	if len(events) == 0 {
+		exists, err := s.repo.Exists(ctx, name)
+		if err != nil {
+			s.logger.Error("failed to check symbol existence", "symbol", name, "error", err)
+			return nil, domain.ErrInternal
+		}
+		if !exists {
+			return nil, domain.ErrSymbolNotFound
+		}
+	}
+
+	return events, nil
+}
+
+// checkLimits returns ErrSymbolQuota when another symbol would exceed the
+// configured caps. New symbols start active, so both caps apply.
+func (s *SymbolService) checkLimits(ctx context.Context) error {
+	if s.limits.MaxTracked > 0 {
+		count, err := s.repo.Count(ctx)
+		if err != nil {
+			s.logger.Error("failed to count symbols", "error", err)
+			return domain.ErrInternal
+		}
+		if count >= s.limits.MaxTracked {
+			return fmt.Errorf("%w: %d of %d tracked symbols in use", domain.ErrSymbolQuota, count, s.limits.MaxTracked)
+		}
+	}
+
+	return s.checkActiveLimit(ctx)
+}
+
+// checkActiveLimit returns ErrSymbolQuota when another active symbol would
+// exceed the configured cap
+func (s *SymbolService) checkActiveLimit(ctx context.Context) error {
+	return s.checkActiveLimitFor(ctx, 1)
+}
+
+// checkActiveLimitFor fails with domain.ErrSymbolQuota when activating n
+// more symbols would exceed the active symbol cap
+func (s *SymbolService) checkActiveLimitFor(ctx context.Context, n int) error {
+	if s.limits.MaxActive <= 0 {
+		return nil
+	}
+
+	count, err := s.repo.CountActive(ctx)
+	if err != nil {
+		s.logger.Error("failed to count active symbols", "error", err)
+		return domain.ErrInternal
+	}
+	if count+n > s.limits.MaxActive {
+		return fmt.Errorf("%w: %d of %d active symbols in use", domain.ErrSymbolQuota, count, s.limits.MaxActive)
+	}
+	return nil
+}
+
+// recordEvent stores a symbol change. The change itself has already been
+// applied, so a failure is logged rather than returned.
+func (s *SymbolService) recordEvent(ctx context.Context, event *domain.SymbolEvent) {
+	if err := s.events.Create(ctx, event); err != nil {
+		s.logger.Error("failed to record symbol event",
+			"symbol", event.Symbol, "type", event.Type, "error", err)
+	}
+}
+
 // Ensure SymbolService implements ports.SymbolService
 var _ ports.SymbolService = (*SymbolService)(nil)
@@ -0,0 +1,61 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+)
+
+// TickerService implements the ports.TickerService interface
+type TickerService struct {
+	tickerRepo ports.TickerStatsRepository
+	symbolRepo ports.SymbolRepository
+	symbols    *domain.SymbolNormalizer
+	logger     *slog.Logger
+}
+
+// NewTickerService creates a new ticker service
+func NewTickerService(
+	tickerRepo ports.TickerStatsRepository,
+	symbolRepo ports.SymbolRepository,
+	symbols *domain.SymbolNormalizer,
+	logger *slog.Logger,
+) *TickerService {
+	return &TickerService{
+		tickerRepo: tickerRepo,
+		symbolRepo: symbolRepo,
+		symbols:    symbols,
+		logger:     logger.With("component", "ticker_service"),
+	}
+}
+
+// GetTicker24h returns the latest captured 24h stats for a tracked symbol
+func (s *TickerService) GetTicker24h(ctx context.Context, symbol string) (*domain.TickerStats, error) {
+	symbol = s.symbols.Normalize(symbol)
+
+	exists, err := s.symbolRepo.Exists(ctx, symbol)
+	if err != nil {
+		s.logger.Error("failed to check symbol existence", "symbol", symbol, "error", err)
+		return nil, domain.ErrInternal
+	}
+	if !exists {
+		return nil, domain.ErrSymbolNotFound
+	}
+
+	stats, err := s.tickerRepo.GetLatestBySymbol(ctx, symbol)
+	if err != nil {
+		if errors.Is(err, domain.ErrTickerStatsNotFound) {
+			return nil, err
+		}
+		s.logger.Error("failed to get ticker stats", "symbol", symbol, "error", err)
+		return nil, domain.ErrInternal
+	}
+
+	return stats, nil
+}
+
+// Ensure TickerService implements ports.TickerService
+var _ ports.TickerService = (*TickerService)(nil)
@@ -0,0 +1,129 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+)
+
+// usageKey identifies one daily count
+type usageKey struct {
+	day      time.Time
+	key      string
+	endpoint string
+}
+
+// UsageService implements the ports.UsageService interface. Requests are
+// counted in memory and added to the stored daily counts on each Flush, so
+// serving a request costs no database write. Every replica adds its own
+// counts.
+type UsageService struct {
+	repo   ports.UsageRepository
+	clock  ports.Clock
+	logger *slog.Logger
+
+	// flushMu serializes flushes so that counts put back after a failed
+	// flush are not written twice
+	flushMu sync.Mutex
+
+	mu      sync.Mutex
+	pending map[usageKey]int64
+}
+
+// NewUsageService creates a new usage service that counts requests in the
+// UTC day of clock
+func NewUsageService(repo ports.UsageRepository, clock ports.Clock, logger *slog.Logger) *UsageService {
+	return &UsageService{
+		repo:    repo,
+		clock:   clock,
+		logger:  logger.With("component", "usage_service"),
+		pending: make(map[usageKey]int64),
+	}
+}
+
+// RecordUsage counts a request of key to endpoint until the next flush
+func (s *UsageService) RecordUsage(key, endpoint string) {
+	k := usageKey{day: domain.UsageDay(s.clock.Now()), key: key, endpoint: endpoint}
+
+	s.mu.Lock()
+	s.pending[k]++
+	s.mu.Unlock()
+}
+
+// Flush adds the requests counted since the last flush to the stored
+// counts. When the database cannot be reached they are kept for the next
+// flush.
+func (s *UsageService) Flush(ctx context.Context) (int, error) {
+	s.flushMu.Lock()
+	defer s.flushMu.Unlock()
+
+	s.mu.Lock()
+	pending := s.pending
+	s.pending = make(map[usageKey]int64)
+	s.mu.Unlock()
+
+	if len(pending) == 0 {
+		return 0, nil
+	}
+
+	counts := make([]domain.UsageCount, 0, len(pending))
+	for k, requests := range pending {
+		counts = append(counts, domain.UsageCount{Day: k.day, Key: k.key, Endpoint: k.endpoint, Requests: requests})
+	}
+
+	if err := s.repo.Add(ctx, counts); err != nil {
+		s.mu.Lock()
+		for k, requests := range pending {
+			s.pending[k] += requests
+		}
+		s.mu.Unlock()
+
+		s.logger.Error("failed to flush usage counts", "counts", len(counts), "error", err)
+		return 0, err
+	}
+
+	s.logger.Debug("usage counts flushed", "counts", len(counts))
+	return len(counts), nil
+}
+
+// GetUsage returns the stored daily counts of the days from through to.
+// Requests not flushed yet are not included.
+func (s *UsageService) GetUsage(ctx context.Context, from, to time.Time) ([]domain.UsageCount, error) {
+	from, to = domain.UsageDay(from), domain.UsageDay(to)
+	if to.Before(from) || to.Sub(from) >= domain.MaxUsageRangeDays*24*time.Hour {
+		return nil, domain.ErrInvalidTimeRange
+	}
+
+	counts, err := s.repo.List(ctx, from, to)
+	if err != nil {
+		s.logger.Error("failed to list usage counts", "error", err)
+		return nil, domain.ErrInternal
+	}
+	if counts == nil {
+		counts = []domain.UsageCount{}
+	}
+	return counts, nil
+}
+
+// Summarize totals the stored usage of the days from up to, but not
+// including, to per key and endpoint
+func (s *UsageService) Summarize(ctx context.Context, from, to time.Time) (*domain.UsageSummary, error) {
+	from, to = domain.UsageDay(from), domain.UsageDay(to)
+	if !from.Before(to) || to.Sub(from) > domain.MaxUsageRangeDays*24*time.Hour {
+		return nil, domain.ErrInvalidTimeRange
+	}
+
+	counts, err := s.repo.List(ctx, from, to.AddDate(0, 0, -1))
+	if err != nil {
+		s.logger.Error("failed to list usage counts", "error", err)
+		return nil, domain.ErrInternal
+	}
+	return domain.SummarizeUsage(from, to, counts), nil
+}
+
+// Ensure UsageService implements ports.UsageService
+var _ ports.UsageService = (*UsageService)(nil)
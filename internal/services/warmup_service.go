@@ -0,0 +1,159 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+)
+
+// WarmupService implements the ports.WarmupService interface
+type WarmupService struct {
+	symbolRepo ports.SymbolRepository
+	exchanges  ports.ExchangeRegistry
+	metrics    ports.MetricsService
+	events     ports.SymbolEventRepository
+	logger     *slog.Logger
+
+	mu     sync.RWMutex
+	report *domain.WarmupReport
+}
+
+// NewWarmupService creates a new warm-up service
+func NewWarmupService(
+	symbolRepo ports.SymbolRepository,
+	exchanges ports.ExchangeRegistry,
+	metrics ports.MetricsService,
+	logger *slog.Logger,
+) *WarmupService {
+	return &WarmupService{
+		symbolRepo: symbolRepo,
+		exchanges:  exchanges,
+		metrics:    metrics,
+		logger:     logger.With("component", "warmup_service"),
+	}
+}
+
+// SetEvents records a delisted event for each tracked symbol that its
+// exchange stopped trading. It must be called before Warmup.
+func (w *WarmupService) SetEvents(events ports.SymbolEventRepository) {
+	w.events = events
+}
+
+// Warmup loads active symbols and verifies that their exchanges still trade them.
+// A report is recorded even when verification fails so that readiness is not
+// blocked by a transient exchange outage at boot.
+func (w *WarmupService) Warmup(ctx context.Context) (*domain.WarmupReport, error) {
+	symbols, err := w.symbolRepo.ListActive(ctx)
+	if err != nil {
+		w.logger.Error("failed to list active symbols", "error", err)
+		w.setReport(&domain.WarmupReport{CompletedAt: time.Now().UTC()})
+		return nil, domain.ErrInternal
+	}
+
+	report := &domain.WarmupReport{
+		CheckedSymbols: len(symbols),
+		Verified:       true,
+	}
+
+	for _, group := range byExchange(w.exchanges, symbols) {
+		exchange, err := w.exchanges.Client(group.exchange)
+		if err != nil {
+			w.logger.Warn("tracked symbols on an unconfigured exchange",
+				"exchange", group.exchange, "symbols", group.names())
+			report.UnrecognizedSymbols = append(report.UnrecognizedSymbols, group.names()...)
+			continue
+		}
+
+		trading, err := exchange.ListTradingSymbols(domain.WithExchangePriority(ctx, domain.ExchangePriorityValidation))
+		if err != nil {
+			w.logger.Error("failed to list trading symbols from exchange", "exchange", group.exchange, "error", err)
+			report.Verified = false
+			report.CompletedAt = time.Now().UTC()
+			w.setReport(report)
+			return report, domain.ErrExchangeUnavailable
+		}
+
+		tradingSet := make(map[string]bool, len(trading))
+		for _, name := range trading {
+			tradingSet[name] = true
+		}
+
+		for _, s := range group.symbols {
+			if !tradingSet[s.Name] {
+				w.logger.Warn("tracked symbol not recognized by exchange", "symbol", s.Name, "exchange", group.exchange)
+				report.UnrecognizedSymbols = append(report.UnrecognizedSymbols, s.Name)
+				w.recordDelisted(ctx, s.Name, group.exchange)
+			}
+		}
+	}
+
+	report.CompletedAt = time.Now().UTC()
+	w.setReport(report)
+
+	w.logger.Info("warm-up completed",
+		"checked", report.CheckedSymbols,
+		"unrecognized", len(report.UnrecognizedSymbols),
+	)
+
+	return report, nil
+}
+
+// recordDelisted records that exchange no longer trades symbol, once: a
+// symbol whose latest event already says so is left alone. Failures are
+// logged, as the check itself succeeded.
+func (w *WarmupService) recordDelisted(ctx context.Context, symbol, exchange string) {
+	if w.events == nil {
+		return
+	}
+
+	latest, err := w.events.ListBySymbol(ctx, symbol, 1)
+	if err != nil {
+		w.logger.Error("failed to list symbol events", "symbol", symbol, "error", err)
+		return
+	}
+	if len(latest) > 0 && latest[0].Type == domain.SymbolEventDelisted {
+		return
+	}
+
+	event := domain.NewSymbolEvent(symbol, domain.SymbolEventDelisted, map[string]interface{}{
+		"exchange": exchange,
+	})
+	if err := w.events.Create(ctx, event); err != nil {
+		w.logger.Error("failed to record symbol event", "symbol", symbol, "type", event.Type, "error", err)
+	}
+}
+
+// Ready reports whether warm-up finished and the first poll has primed latest prices
+func (w *WarmupService) Ready() bool {
+	report := w.Report()
+	if report == nil {
+		return false
+	}
+
+	// Nothing to prime when no symbols are tracked
+	if report.CheckedSymbols == 0 {
+		return true
+	}
+
+	return w.metrics.GetLastPollTime() != nil
+}
+
+// Report returns the last warm-up report
+func (w *WarmupService) Report() *domain.WarmupReport {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.report
+}
+
+func (w *WarmupService) setReport(report *domain.WarmupReport) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.report = report
+}
+
+// Ensure WarmupService implements ports.WarmupService
+var _ ports.WarmupService = (*WarmupService)(nil)
@@ -0,0 +1,103 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+)
+
+// CandleAggregator rolls snapshots up into OHLC candles at regular intervals
+type CandleAggregator struct {
+	service  ports.CandleService
+	interval time.Duration
+	logger   *slog.Logger
+
+	mu      sync.Mutex
+	running bool
+	stopCh  chan struct{}
+	doneCh  chan struct{}
+}
+
+// NewCandleAggregator creates a new candle aggregator
+func NewCandleAggregator(service ports.CandleService, interval time.Duration, logger *slog.Logger) *CandleAggregator {
+	return &CandleAggregator{
+		service:  service,
+		interval: interval,
+		logger:   logger.With("component", "candle_aggregator"),
+		stopCh:   make(chan struct{}),
+		doneCh:   make(chan struct{}),
+	}
+}
+
+// Start aggregates candles right away, catching up on the backfill period,
+// and then every interval
+func (a *CandleAggregator) Start(ctx context.Context) error {
+	a.mu.Lock()
+	if a.running {
+		a.mu.Unlock()
+		return nil
+	}
+	a.running = true
+	a.stopCh = make(chan struct{})
+	a.doneCh = make(chan struct{})
+	a.mu.Unlock()
+
+	a.logger.Info("starting candle aggregator", "interval", a.interval.String())
+
+	a.aggregate(ctx)
+
+	ticker := time.NewTicker(a.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			a.logger.Info("candle aggregator context cancelled")
+			close(a.doneCh)
+			a.mu.Lock()
+			a.running = false
+			a.mu.Unlock()
+			return ctx.Err()
+
+		case <-a.stopCh:
+			a.logger.Info("candle aggregator stopped")
+			close(a.doneCh)
+			a.mu.Lock()
+			a.running = false
+			a.mu.Unlock()
+			return nil
+
+		case <-ticker.C:
+			a.aggregate(ctx)
+		}
+	}
+}
+
+func (a *CandleAggregator) aggregate(ctx context.Context) {
+	if _, err := a.service.Aggregate(ctx); err != nil {
+		a.logger.Error("candle aggregation failed", "error", err)
+	}
+}
+
+// Stop gracefully stops the candle aggregator
+func (a *CandleAggregator) Stop() error {
+	a.mu.Lock()
+	if !a.running {
+		a.mu.Unlock()
+		return nil
+	}
+	a.mu.Unlock()
+
+	a.logger.Info("stopping candle aggregator")
+	close(a.stopCh)
+
+	select {
+	case <-a.doneCh:
+		return nil
+	case <-time.After(10 * time.Second):
+		return context.DeadlineExceeded
+	}
+}
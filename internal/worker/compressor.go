@@ -0,0 +1,100 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+)
+
+// Compressor packs old snapshots into compressed chunks at regular intervals
+type Compressor struct {
+	service  ports.CompressionService
+	interval time.Duration
+	logger   *slog.Logger
+
+	mu      sync.Mutex
+	running bool
+	stopCh  chan struct{}
+	doneCh  chan struct{}
+}
+
+// NewCompressor creates a new snapshot compressor
+func NewCompressor(service ports.CompressionService, interval time.Duration, logger *slog.Logger) *Compressor {
+	return &Compressor{
+		service:  service,
+		interval: interval,
+		logger:   logger.With("component", "compressor"),
+		stopCh:   make(chan struct{}),
+		doneCh:   make(chan struct{}),
+	}
+}
+
+// Start begins compressing snapshots
+func (c *Compressor) Start(ctx context.Context) error {
+	c.mu.Lock()
+	if c.running {
+		c.mu.Unlock()
+		return nil
+	}
+	c.running = true
+	c.stopCh = make(chan struct{})
+	c.doneCh = make(chan struct{})
+	c.mu.Unlock()
+
+	c.logger.Info("starting compressor", "interval", c.interval.String())
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			c.logger.Info("compressor context cancelled")
+			close(c.doneCh)
+			c.mu.Lock()
+			c.running = false
+			c.mu.Unlock()
+			return ctx.Err()
+
+		case <-c.stopCh:
+			c.logger.Info("compressor stopped")
+			close(c.doneCh)
+			c.mu.Lock()
+			c.running = false
+			c.mu.Unlock()
+			return nil
+
+		case <-ticker.C:
+			c.compress(ctx)
+		}
+	}
+}
+
+func (c *Compressor) compress(ctx context.Context) {
+	if _, err := c.service.CompressOld(ctx); err != nil {
+		c.logger.Error("compression failed", "error", err)
+	}
+}
+
+// Stop gracefully stops the compressor
+func (c *Compressor) Stop() error {
+	c.mu.Lock()
+	if !c.running {
+		c.mu.Unlock()
+		return nil
+	}
+	c.mu.Unlock()
+
+	c.logger.Info("stopping compressor")
+	close(c.stopCh)
+
+	select {
+	case <-c.doneCh:
+		return nil
+	case <-time.After(10 * time.Second):
+		return context.DeadlineExceeded
+	}
+}
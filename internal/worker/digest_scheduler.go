@@ -0,0 +1,114 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+)
+
+// DigestScheduler sends price digests on a cron schedule
+type DigestScheduler struct {
+	service  ports.DigestService
+	schedule cron.Schedule
+	spec     string
+	logger   *slog.Logger
+
+	mu      sync.Mutex
+	running bool
+	stopCh  chan struct{}
+	doneCh  chan struct{}
+}
+
+// NewDigestScheduler creates a new digest scheduler from a standard
+// five-field cron expression (evaluated in UTC unless prefixed with CRON_TZ=)
+func NewDigestScheduler(service ports.DigestService, spec string, logger *slog.Logger) (*DigestScheduler, error) {
+	schedule, err := cron.ParseStandard(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DigestScheduler{
+		service:  service,
+		schedule: schedule,
+		spec:     spec,
+		logger:   logger.With("component", "digest_scheduler"),
+		stopCh:   make(chan struct{}),
+		doneCh:   make(chan struct{}),
+	}, nil
+}
+
+// Start begins sending digests at each scheduled time
+func (d *DigestScheduler) Start(ctx context.Context) error {
+	d.mu.Lock()
+	if d.running {
+		d.mu.Unlock()
+		return nil
+	}
+	d.running = true
+	d.stopCh = make(chan struct{})
+	d.doneCh = make(chan struct{})
+	d.mu.Unlock()
+
+	d.logger.Info("starting digest scheduler", "schedule", d.spec)
+
+	for {
+		next := d.schedule.Next(time.Now().UTC())
+		d.logger.Debug("next digest scheduled", "at", next)
+
+		timer := time.NewTimer(time.Until(next))
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			d.logger.Info("digest scheduler context cancelled")
+			close(d.doneCh)
+			d.mu.Lock()
+			d.running = false
+			d.mu.Unlock()
+			return ctx.Err()
+
+		case <-d.stopCh:
+			timer.Stop()
+			d.logger.Info("digest scheduler stopped")
+			close(d.doneCh)
+			d.mu.Lock()
+			d.running = false
+			d.mu.Unlock()
+			return nil
+
+		case <-timer.C:
+			d.send(ctx)
+		}
+	}
+}
+
+func (d *DigestScheduler) send(ctx context.Context) {
+	if err := d.service.SendDigest(ctx); err != nil {
+		d.logger.Error("digest delivery failed", "error", err)
+	}
+}
+
+// Stop gracefully stops the digest scheduler
+func (d *DigestScheduler) Stop() error {
+	d.mu.Lock()
+	if !d.running {
+		d.mu.Unlock()
+		return nil
+	}
+	d.mu.Unlock()
+
+	d.logger.Info("stopping digest scheduler")
+	close(d.stopCh)
+
+	select {
+	case <-d.doneCh:
+		return nil
+	case <-time.After(10 * time.Second):
+		return context.DeadlineExceeded
+	}
+}
@@ -0,0 +1,132 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+)
+
+// JobRunner executes queued jobs with a fixed number of workers
+type JobRunner struct {
+	executor    ports.JobExecutor
+	interval    time.Duration
+	concurrency int
+	logger      *slog.Logger
+
+	mu      sync.Mutex
+	running bool
+	stopCh  chan struct{}
+	wg      sync.WaitGroup
+}
+
+// NewJobRunner creates a new job runner. Each worker checks the queue every
+// interval and drains due jobs back to back.
+func NewJobRunner(executor ports.JobExecutor, interval time.Duration, concurrency int, logger *slog.Logger) *JobRunner {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	return &JobRunner{
+		executor:    executor,
+		interval:    interval,
+		concurrency: concurrency,
+		logger:      logger.With("component", "job_runner"),
+		stopCh:      make(chan struct{}),
+	}
+}
+
+// Start launches the workers and blocks until they exit
+func (r *JobRunner) Start(ctx context.Context) error {
+	r.mu.Lock()
+	if r.running {
+		r.mu.Unlock()
+		return nil
+	}
+	r.running = true
+	r.stopCh = make(chan struct{})
+	r.mu.Unlock()
+
+	r.logger.Info("starting job runner",
+		"interval", r.interval.String(),
+		"concurrency", r.concurrency,
+	)
+
+	for i := 0; i < r.concurrency; i++ {
+		r.wg.Add(1)
+		go r.work(ctx, i)
+	}
+
+	r.wg.Wait()
+
+	r.mu.Lock()
+	r.running = false
+	r.mu.Unlock()
+
+	return ctx.Err()
+}
+
+func (r *JobRunner) work(ctx context.Context, id int) {
+	defer r.wg.Done()
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		// Drain due jobs before waiting for the next tick
+		for {
+			ran, err := r.executor.RunNext(ctx)
+			if err != nil {
+				r.logger.Error("failed to run job", "worker", id, "error", err)
+				break
+			}
+			if !ran {
+				break
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-r.stopCh:
+				return
+			default:
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-r.stopCh:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Stop signals the workers to exit after their current job
+func (r *JobRunner) Stop() error {
+	r.mu.Lock()
+	if !r.running {
+		r.mu.Unlock()
+		return nil
+	}
+	r.mu.Unlock()
+
+	r.logger.Info("stopping job runner")
+	close(r.stopCh)
+
+	done := make(chan struct{})
+	go func() {
+		r.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-time.After(10 * time.Second):
+		return context.DeadlineExceeded
+	}
+}
@@ -0,0 +1,103 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+)
+
+// MetadataRefresher enriches symbols with coin metadata on start and at
+// regular intervals
+type MetadataRefresher struct {
+	service  ports.MetadataService
+	interval time.Duration
+	logger   *slog.Logger
+
+	mu      sync.Mutex
+	running bool
+	stopCh  chan struct{}
+	doneCh  chan struct{}
+}
+
+// NewMetadataRefresher creates a new metadata refresher
+func NewMetadataRefresher(service ports.MetadataService, interval time.Duration, logger *slog.Logger) *MetadataRefresher {
+	return &MetadataRefresher{
+		service:  service,
+		interval: interval,
+		logger:   logger.With("component", "metadata_refresher"),
+		stopCh:   make(chan struct{}),
+		doneCh:   make(chan struct{}),
+	}
+}
+
+// Start begins refreshing metadata
+func (m *MetadataRefresher) Start(ctx context.Context) error {
+	m.mu.Lock()
+	if m.running {
+		m.mu.Unlock()
+		return nil
+	}
+	m.running = true
+	m.stopCh = make(chan struct{})
+	m.doneCh = make(chan struct{})
+	m.mu.Unlock()
+
+	m.logger.Info("starting metadata refresher", "interval", m.interval.String())
+
+	m.refresh(ctx)
+
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			m.logger.Info("metadata refresher context cancelled")
+			close(m.doneCh)
+			m.mu.Lock()
+			m.running = false
+			m.mu.Unlock()
+			return ctx.Err()
+
+		case <-m.stopCh:
+			m.logger.Info("metadata refresher stopped")
+			close(m.doneCh)
+			m.mu.Lock()
+			m.running = false
+			m.mu.Unlock()
+			return nil
+
+		case <-ticker.C:
+			m.refresh(ctx)
+		}
+	}
+}
+
+func (m *MetadataRefresher) refresh(ctx context.Context) {
+	if _, err := m.service.RefreshMetadata(ctx); err != nil {
+		m.logger.Error("metadata refresh failed", "error", err)
+	}
+}
+
+// Stop gracefully stops the metadata refresher
+func (m *MetadataRefresher) Stop() error {
+	m.mu.Lock()
+	if !m.running {
+		m.mu.Unlock()
+		return nil
+	}
+	m.mu.Unlock()
+
+	m.logger.Info("stopping metadata refresher")
+	close(m.stopCh)
+
+	select {
+	case <-m.doneCh:
+		return nil
+	case <-time.After(10 * time.Second):
+		return context.DeadlineExceeded
+	}
+}
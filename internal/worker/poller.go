@@ -13,6 +13,7 @@ import (
 type Poller struct {
 	service  ports.PollerService
 	interval time.Duration
+	clock    ports.Clock
 	logger   *slog.Logger
 
 	mu      sync.Mutex
@@ -21,11 +22,12 @@ type Poller struct {
 	doneCh  chan struct{}
 }
 
-// NewPoller creates a new price poller
-func NewPoller(service ports.PollerService, interval time.Duration, logger *slog.Logger) *Poller {
+// NewPoller creates a new price poller that polls every interval of clock
+func NewPoller(service ports.PollerService, interval time.Duration, clock ports.Clock, logger *slog.Logger) *Poller {
 	return &Poller{
 		service:  service,
 		interval: interval,
+		clock:    clock,
 		logger:   logger.With("component", "poller"),
 		stopCh:   make(chan struct{}),
 		doneCh:   make(chan struct{}),
@@ -46,7 +48,7 @@ func (p *Poller) Start(ctx context.Context) error {
 
 	p.logger.Info("starting poller", "interval", p.interval.String())
 
-	ticker := time.NewTicker(p.interval)
+	ticker := p.clock.NewTicker(p.interval)
 	defer ticker.Stop()
 
 	// Initial poll
@@ -70,7 +72,7 @@ func (p *Poller) Start(ctx context.Context) error {
 			p.mu.Unlock()
 			return nil
 
-		case <-ticker.C:
+		case <-ticker.C():
 			p.poll(ctx)
 		}
 	}
@@ -0,0 +1,51 @@
+package worker_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/adapters/clock"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/worker"
+)
+
+type countingPoller struct {
+	polls atomic.Int32
+}
+
+func (p *countingPoller) PollPrices(ctx context.Context) error {
+	p.polls.Add(1)
+	return nil
+}
+
+func TestPoller_PollsEveryInterval(t *testing.T) {
+	fake := clock.NewFake(time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC))
+	service := &countingPoller{}
+	poller := worker.NewPoller(service, time.Minute, fake, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	done := make(chan error, 1)
+	go func() { done <- poller.Start(context.Background()) }()
+
+	// The first poll runs right away
+	require.Eventually(t, func() bool { return service.polls.Load() == 1 && fake.Tickers() == 1 }, time.Second, time.Millisecond)
+
+	fake.Advance(59 * time.Second)
+	time.Sleep(10 * time.Millisecond)
+	assert.Equal(t, int32(1), service.polls.Load())
+
+	fake.Advance(time.Second)
+	require.Eventually(t, func() bool { return service.polls.Load() == 2 }, time.Second, time.Millisecond)
+
+	fake.Advance(time.Minute)
+	require.Eventually(t, func() bool { return service.polls.Load() == 3 }, time.Second, time.Millisecond)
+
+	require.NoError(t, poller.Stop())
+	require.NoError(t, <-done)
+	assert.Zero(t, fake.Tickers())
+}
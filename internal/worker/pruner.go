@@ -0,0 +1,102 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+)
+
+// Pruner removes expired snapshots at regular intervals
+type Pruner struct {
+	service  ports.PrunerService
+	interval time.Duration
+	clock    ports.Clock
+	logger   *slog.Logger
+
+	mu      sync.Mutex
+	running bool
+	stopCh  chan struct{}
+	doneCh  chan struct{}
+}
+
+// NewPruner creates a new snapshot pruner that prunes every interval of clock
+func NewPruner(service ports.PrunerService, interval time.Duration, clock ports.Clock, logger *slog.Logger) *Pruner {
+	return &Pruner{
+		service:  service,
+		interval: interval,
+		clock:    clock,
+		logger:   logger.With("component", "pruner"),
+		stopCh:   make(chan struct{}),
+		doneCh:   make(chan struct{}),
+	}
+}
+
+// Start begins pruning snapshots
+func (p *Pruner) Start(ctx context.Context) error {
+	p.mu.Lock()
+	if p.running {
+		p.mu.Unlock()
+		return nil
+	}
+	p.running = true
+	p.stopCh = make(chan struct{})
+	p.doneCh = make(chan struct{})
+	p.mu.Unlock()
+
+	p.logger.Info("starting pruner", "interval", p.interval.String())
+
+	ticker := p.clock.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			p.logger.Info("pruner context cancelled")
+			close(p.doneCh)
+			p.mu.Lock()
+			p.running = false
+			p.mu.Unlock()
+			return ctx.Err()
+
+		case <-p.stopCh:
+			p.logger.Info("pruner stopped")
+			close(p.doneCh)
+			p.mu.Lock()
+			p.running = false
+			p.mu.Unlock()
+			return nil
+
+		case <-ticker.C():
+			p.prune(ctx)
+		}
+	}
+}
+
+func (p *Pruner) prune(ctx context.Context) {
+	if _, err := p.service.PruneExpired(ctx); err != nil {
+		p.logger.Error("prune failed", "error", err)
+	}
+}
+
+// Stop gracefully stops the pruner
+func (p *Pruner) Stop() error {
+	p.mu.Lock()
+	if !p.running {
+		p.mu.Unlock()
+		return nil
+	}
+	p.mu.Unlock()
+
+	p.logger.Info("stopping pruner")
+	close(p.stopCh)
+
+	select {
+	case <-p.doneCh:
+		return nil
+	case <-time.After(10 * time.Second):
+		return context.DeadlineExceeded
+	}
+}
@@ -0,0 +1,120 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+)
+
+// finalFlushTimeout bounds the flush made when the flusher stops
+const finalFlushTimeout = 5 * time.Second
+
+// SnapshotFlusher writes the snapshot buffer every interval, or sooner
+// whenever the buffer fills up
+type SnapshotFlusher struct {
+	buffer   ports.SnapshotBuffer
+	interval time.Duration
+	clock    ports.Clock
+	logger   *slog.Logger
+
+	mu      sync.Mutex
+	running bool
+	stopCh  chan struct{}
+	doneCh  chan struct{}
+}
+
+// NewSnapshotFlusher creates a new snapshot flusher that flushes buffer
+// every interval of clock
+func NewSnapshotFlusher(buffer ports.SnapshotBuffer, interval time.Duration, clock ports.Clock, logger *slog.Logger) *SnapshotFlusher {
+	return &SnapshotFlusher{
+		buffer:   buffer,
+		interval: interval,
+		clock:    clock,
+		logger:   logger.With("component", "snapshot_flusher"),
+		stopCh:   make(chan struct{}),
+		doneCh:   make(chan struct{}),
+	}
+}
+
+// Start flushes the buffer until stopped. What is still buffered then is
+// flushed once more, so stopping the pollers first loses no snapshots.
+func (f *SnapshotFlusher) Start(ctx context.Context) error {
+	f.mu.Lock()
+	if f.running {
+		f.mu.Unlock()
+		return nil
+	}
+	f.running = true
+	f.stopCh = make(chan struct{})
+	f.doneCh = make(chan struct{})
+	f.mu.Unlock()
+
+	defer func() {
+		close(f.doneCh)
+		f.mu.Lock()
+		f.running = false
+		f.mu.Unlock()
+	}()
+
+	f.logger.Info("starting snapshot flusher", "interval", f.interval.String())
+
+	ticker := f.clock.NewTicker(f.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			f.logger.Info("snapshot flusher context cancelled")
+			f.finalFlush()
+			return ctx.Err()
+
+		case <-f.stopCh:
+			f.logger.Info("snapshot flusher stopped")
+			f.finalFlush()
+			return nil
+
+		case <-ticker.C():
+			f.flush(ctx)
+
+		case <-f.buffer.Full():
+			f.flush(ctx)
+		}
+	}
+}
+
+func (f *SnapshotFlusher) flush(ctx context.Context) {
+	if _, err := f.buffer.Flush(ctx); err != nil {
+		f.logger.Error("snapshot flush failed", "error", err)
+	}
+}
+
+// finalFlush writes what is left in the buffer, detached from the
+// cancelled run context
+func (f *SnapshotFlusher) finalFlush() {
+	ctx, cancel := context.WithTimeout(context.Background(), finalFlushTimeout)
+	defer cancel()
+	f.flush(ctx)
+}
+
+// Stop gracefully stops the flusher after a final flush
+func (f *SnapshotFlusher) Stop() error {
+	f.mu.Lock()
+	if !f.running {
+		f.mu.Unlock()
+		return nil
+	}
+	f.mu.Unlock()
+
+	f.logger.Info("stopping snapshot flusher")
+	close(f.stopCh)
+
+	select {
+	case <-f.doneCh:
+		return nil
+	case <-time.After(10 * time.Second):
+		return context.DeadlineExceeded
+	}
+}
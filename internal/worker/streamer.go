@@ -0,0 +1,127 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+)
+
+// Streamer runs the poller in stream mode: prices pushed by the exchange
+// are buffered as they arrive and written every flush interval
+type Streamer struct {
+	service ports.StreamPollerService
+	flush   time.Duration
+	clock   ports.Clock
+	logger  *slog.Logger
+
+	mu      sync.Mutex
+	running bool
+	stopCh  chan struct{}
+	doneCh  chan struct{}
+}
+
+// NewStreamer creates a new price streamer that flushes every flush
+// interval of clock
+func NewStreamer(service ports.StreamPollerService, flush time.Duration, clock ports.Clock, logger *slog.Logger) *Streamer {
+	return &Streamer{
+		service: service,
+		flush:   flush,
+		clock:   clock,
+		logger:  logger.With("component", "streamer"),
+		stopCh:  make(chan struct{}),
+		doneCh:  make(chan struct{}),
+	}
+}
+
+// Start subscribes to the price stream and flushes it until stopped. It
+// fails if the stream ends on its own.
+func (s *Streamer) Start(ctx context.Context) error {
+	s.mu.Lock()
+	if s.running {
+		s.mu.Unlock()
+		return nil
+	}
+	s.running = true
+	s.stopCh = make(chan struct{})
+	s.doneCh = make(chan struct{})
+	s.mu.Unlock()
+
+	defer func() {
+		close(s.doneCh)
+		s.mu.Lock()
+		s.running = false
+		s.mu.Unlock()
+	}()
+
+	s.logger.Info("starting streamer", "flush_interval", s.flush.String())
+
+	streamCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	streamDone := make(chan error, 1)
+	go func() {
+		streamDone <- s.service.StreamPrices(streamCtx)
+	}()
+
+	ticker := s.clock.NewTicker(s.flush)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.logger.Info("streamer context cancelled")
+			<-streamDone
+			return ctx.Err()
+
+		case <-s.stopCh:
+			cancel()
+			<-streamDone
+			// Write what arrived since the last flush
+			s.flushPrices(ctx)
+			s.logger.Info("streamer stopped")
+			return nil
+
+		case err := <-streamDone:
+			return err
+
+		case <-ticker.C():
+			s.flushPrices(ctx)
+		}
+	}
+}
+
+func (s *Streamer) flushPrices(ctx context.Context) {
+	flushTimeout := s.flush * 5
+	if flushTimeout < 5*time.Second {
+		flushTimeout = 5 * time.Second
+	}
+
+	flushCtx, cancel := context.WithTimeout(ctx, flushTimeout)
+	defer cancel()
+
+	if err := s.service.FlushStream(flushCtx); err != nil {
+		s.logger.Error("stream flush failed", "error", err)
+	}
+}
+
+// Stop gracefully stops the streamer after a final flush
+func (s *Streamer) Stop() error {
+	s.mu.Lock()
+	if !s.running {
+		s.mu.Unlock()
+		return nil
+	}
+	s.mu.Unlock()
+
+	s.logger.Info("stopping streamer")
+	close(s.stopCh)
+
+	select {
+	case <-s.doneCh:
+		return nil
+	case <-time.After(10 * time.Second):
+		return context.DeadlineExceeded
+	}
+}
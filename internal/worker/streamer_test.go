@@ -0,0 +1,58 @@
+package worker_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/adapters/clock"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/worker"
+)
+
+type countingStreamer struct {
+	streaming atomic.Bool
+	flushes   atomic.Int32
+}
+
+func (s *countingStreamer) StreamPrices(ctx context.Context) error {
+	s.streaming.Store(true)
+	<-ctx.Done()
+	s.streaming.Store(false)
+	return ctx.Err()
+}
+
+func (s *countingStreamer) FlushStream(ctx context.Context) error {
+	s.flushes.Add(1)
+	return nil
+}
+
+func TestStreamer_FlushesEveryInterval(t *testing.T) {
+	fake := clock.NewFake(time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC))
+	service := &countingStreamer{}
+	streamer := worker.NewStreamer(service, time.Second, fake, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	done := make(chan error, 1)
+	go func() { done <- streamer.Start(context.Background()) }()
+
+	require.Eventually(t, func() bool { return service.streaming.Load() && fake.Tickers() == 1 }, time.Second, time.Millisecond)
+	assert.Zero(t, service.flushes.Load())
+
+	fake.Advance(time.Second)
+	require.Eventually(t, func() bool { return service.flushes.Load() == 1 }, time.Second, time.Millisecond)
+
+	fake.Advance(time.Second)
+	require.Eventually(t, func() bool { return service.flushes.Load() == 2 }, time.Second, time.Millisecond)
+
+	// Stopping ends the stream and flushes what is left
+	require.NoError(t, streamer.Stop())
+	require.NoError(t, <-done)
+	assert.False(t, service.streaming.Load())
+	assert.Equal(t, int32(3), service.flushes.Load())
+	assert.Zero(t, fake.Tickers())
+}
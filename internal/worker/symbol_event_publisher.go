@@ -0,0 +1,103 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+)
+
+// SymbolEventPublisher delivers symbol lifecycle events to subscribers on
+// start and at regular intervals
+type SymbolEventPublisher struct {
+	service  ports.SymbolEventFeedService
+	interval time.Duration
+	logger   *slog.Logger
+
+	mu      sync.Mutex
+	running bool
+	stopCh  chan struct{}
+	doneCh  chan struct{}
+}
+
+// NewSymbolEventPublisher creates a new symbol event publisher
+func NewSymbolEventPublisher(service ports.SymbolEventFeedService, interval time.Duration, logger *slog.Logger) *SymbolEventPublisher {
+	return &SymbolEventPublisher{
+		service:  service,
+		interval: interval,
+		logger:   logger.With("component", "symbol_event_publisher"),
+		stopCh:   make(chan struct{}),
+		doneCh:   make(chan struct{}),
+	}
+}
+
+// Start begins delivering events
+func (p *SymbolEventPublisher) Start(ctx context.Context) error {
+	p.mu.Lock()
+	if p.running {
+		p.mu.Unlock()
+		return nil
+	}
+	p.running = true
+	p.stopCh = make(chan struct{})
+	p.doneCh = make(chan struct{})
+	p.mu.Unlock()
+
+	p.logger.Info("starting symbol event publisher", "interval", p.interval.String())
+
+	p.publish(ctx)
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			p.logger.Info("symbol event publisher context cancelled")
+			close(p.doneCh)
+			p.mu.Lock()
+			p.running = false
+			p.mu.Unlock()
+			return ctx.Err()
+
+		case <-p.stopCh:
+			p.logger.Info("symbol event publisher stopped")
+			close(p.doneCh)
+			p.mu.Lock()
+			p.running = false
+			p.mu.Unlock()
+			return nil
+
+		case <-ticker.C:
+			p.publish(ctx)
+		}
+	}
+}
+
+func (p *SymbolEventPublisher) publish(ctx context.Context) {
+	if _, err := p.service.DeliverEvents(ctx); err != nil {
+		p.logger.Error("symbol event delivery failed", "error", err)
+	}
+}
+
+// Stop gracefully stops the symbol event publisher
+func (p *SymbolEventPublisher) Stop() error {
+	p.mu.Lock()
+	if !p.running {
+		p.mu.Unlock()
+		return nil
+	}
+	p.mu.Unlock()
+
+	p.logger.Info("stopping symbol event publisher")
+	close(p.stopCh)
+
+	select {
+	case <-p.doneCh:
+		return nil
+	case <-time.After(10 * time.Second):
+		return context.DeadlineExceeded
+	}
+}
@@ -0,0 +1,124 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+)
+
+const (
+	// minRenewDelay bounds how often a token with a very short TTL is renewed
+	minRenewDelay = time.Second
+
+	// renewRetryDelay is how long a failed renewal waits to be retried
+	renewRetryDelay = 30 * time.Second
+)
+
+// TokenRenewer renews a secrets store token at half of its TTL, so the
+// token outlives the process. A token that never expires is renewed once.
+type TokenRenewer struct {
+	renewer ports.TokenRenewer
+	logger  *slog.Logger
+
+	mu      sync.Mutex
+	running bool
+	stopCh  chan struct{}
+	doneCh  chan struct{}
+}
+
+// NewTokenRenewer creates a new token renewer
+func NewTokenRenewer(renewer ports.TokenRenewer, logger *slog.Logger) *TokenRenewer {
+	return &TokenRenewer{
+		renewer: renewer,
+		logger:  logger.With("component", "token_renewer"),
+		stopCh:  make(chan struct{}),
+		doneCh:  make(chan struct{}),
+	}
+}
+
+// Start renews the token until stopped. Failed renewals are retried; they
+// never stop the renewer.
+func (t *TokenRenewer) Start(ctx context.Context) error {
+	t.mu.Lock()
+	if t.running {
+		t.mu.Unlock()
+		return nil
+	}
+	t.running = true
+	t.stopCh = make(chan struct{})
+	t.doneCh = make(chan struct{})
+	t.mu.Unlock()
+
+	defer func() {
+		close(t.doneCh)
+		t.mu.Lock()
+		t.running = false
+		t.mu.Unlock()
+	}()
+
+	t.logger.Info("starting token renewer")
+
+	for {
+		delay, ok := t.renew(ctx)
+		if !ok {
+			t.logger.Info("token does not expire, no further renewal needed")
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-t.stopCh:
+				return nil
+			}
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			t.logger.Info("token renewer context cancelled")
+			return ctx.Err()
+		case <-t.stopCh:
+			timer.Stop()
+			t.logger.Info("token renewer stopped")
+			return nil
+		case <-timer.C:
+		}
+	}
+}
+
+// renew renews the token and returns when to renew it next; ok is false
+// when the token never expires
+func (t *TokenRenewer) renew(ctx context.Context) (delay time.Duration, ok bool) {
+	ttl, err := t.renewer.RenewToken(ctx)
+	if err != nil {
+		t.logger.Error("token renewal failed", "error", err, "retry_in", renewRetryDelay.String())
+		return renewRetryDelay, true
+	}
+	if ttl == 0 {
+		return 0, false
+	}
+
+	t.logger.Debug("token renewed", "ttl", ttl.String())
+	return max(ttl/2, minRenewDelay), true
+}
+
+// Stop gracefully stops the token renewer
+func (t *TokenRenewer) Stop() error {
+	t.mu.Lock()
+	if !t.running {
+		t.mu.Unlock()
+		return nil
+	}
+	t.mu.Unlock()
+
+	close(t.stopCh)
+
+	select {
+	case <-t.doneCh:
+		return nil
+	case <-time.After(10 * time.Second):
+		return context.DeadlineExceeded
+	}
+}
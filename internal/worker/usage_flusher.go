@@ -0,0 +1,141 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/prxgr4mmer/price-snapshot-service/internal/domain"
+	"github.com/prxgr4mmer/price-snapshot-service/internal/ports"
+)
+
+// UsageFlusher stores the counted API requests every interval and, once a
+// month is over, logs the month's usage summary
+type UsageFlusher struct {
+	service  ports.UsageService
+	interval time.Duration
+	clock    ports.Clock
+	logger   *slog.Logger
+
+	// month is the UTC month flushes currently count in
+	month time.Time
+
+	mu      sync.Mutex
+	running bool
+	stopCh  chan struct{}
+	doneCh  chan struct{}
+}
+
+// NewUsageFlusher creates a new usage flusher that flushes service every
+// interval of clock
+func NewUsageFlusher(service ports.UsageService, interval time.Duration, clock ports.Clock, logger *slog.Logger) *UsageFlusher {
+	return &UsageFlusher{
+		service:  service,
+		interval: interval,
+		clock:    clock,
+		logger:   logger.With("component", "usage_flusher"),
+		stopCh:   make(chan struct{}),
+		doneCh:   make(chan struct{}),
+	}
+}
+
+// Start flushes the usage counts until stopped, then flushes once more
+func (f *UsageFlusher) Start(ctx context.Context) error {
+	f.mu.Lock()
+	if f.running {
+		f.mu.Unlock()
+		return nil
+	}
+	f.running = true
+	f.stopCh = make(chan struct{})
+	f.doneCh = make(chan struct{})
+	f.mu.Unlock()
+
+	defer func() {
+		close(f.doneCh)
+		f.mu.Lock()
+		f.running = false
+		f.mu.Unlock()
+	}()
+
+	f.logger.Info("starting usage flusher", "interval", f.interval.String())
+	f.month = domain.UsageMonth(f.clock.Now())
+
+	ticker := f.clock.NewTicker(f.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			f.logger.Info("usage flusher context cancelled")
+			f.finalFlush()
+			return ctx.Err()
+
+		case <-f.stopCh:
+			f.logger.Info("usage flusher stopped")
+			f.finalFlush()
+			return nil
+
+		case <-ticker.C():
+			f.flush(ctx)
+			f.summarizeMonth(ctx)
+		}
+	}
+}
+
+func (f *UsageFlusher) flush(ctx context.Context) {
+	if _, err := f.service.Flush(ctx); err != nil {
+		f.logger.Error("usage flush failed", "error", err)
+	}
+}
+
+// summarizeMonth logs the summary of the month that just ended, once its
+// last counts are flushed. Each replica logs the summary of every replica's
+// counts.
+func (f *UsageFlusher) summarizeMonth(ctx context.Context) {
+	month := domain.UsageMonth(f.clock.Now())
+	if !month.After(f.month) {
+		return
+	}
+
+	summary, err := f.service.Summarize(ctx, f.month, month)
+	if err != nil {
+		f.logger.Error("failed to summarize monthly usage", "month", f.month.Format("2006-01"), "error", err)
+		return
+	}
+	f.month = month
+
+	f.logger.Info("monthly API usage summary",
+		"month", summary.From.Format("2006-01"),
+		"requests", summary.Requests,
+		"keys", summary.Keys,
+	)
+}
+
+// finalFlush stores what is left, detached from the cancelled run context
+func (f *UsageFlusher) finalFlush() {
+	ctx, cancel := context.WithTimeout(context.Background(), finalFlushTimeout)
+	defer cancel()
+	f.flush(ctx)
+}
+
+// Stop gracefully stops the flusher after a final flush
+func (f *UsageFlusher) Stop() error {
+	f.mu.Lock()
+	if !f.running {
+		f.mu.Unlock()
+		return nil
+	}
+	f.mu.Unlock()
+
+	f.logger.Info("stopping usage flusher")
+	close(f.stopCh)
+
+	select {
+	case <-f.doneCh:
+		return nil
+	case <-time.After(10 * time.Second):
+		return context.DeadlineExceeded
+	}
+}
@@ -0,0 +1,4 @@
+-- Crypto Snapshot Service - Rollback Job Queue
+
+DROP TRIGGER IF EXISTS update_jobs_updated_at ON jobs;
+DROP TABLE IF EXISTS jobs;
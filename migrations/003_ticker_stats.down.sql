@@ -0,0 +1,3 @@
+-- Crypto Snapshot Service - Rollback Ticker Stats
+
+DROP TABLE IF EXISTS ticker_stats;
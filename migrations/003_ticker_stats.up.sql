@@ -0,0 +1,18 @@
+-- Crypto Snapshot Service - Ticker Stats
+-- Creates the table storing rolling 24h statistics captured by the poller
+
+-- Ticker stats table: stores 24h high, low, volume and change per poll
+CREATE TABLE IF NOT EXISTS ticker_stats (
+    id BIGSERIAL PRIMARY KEY,
+    symbol_id BIGINT NOT NULL REFERENCES symbols(id) ON DELETE CASCADE,
+    symbol VARCHAR(20) NOT NULL,
+    high NUMERIC(24, 8) NOT NULL,
+    low NUMERIC(24, 8) NOT NULL,
+    volume NUMERIC(36, 8) NOT NULL,
+    price_change_percent NUMERIC(12, 4) NOT NULL,
+    timestamp TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Indexes for ticker_stats table
+CREATE INDEX IF NOT EXISTS idx_ticker_stats_symbol_timestamp ON ticker_stats(symbol, timestamp DESC);
+CREATE INDEX IF NOT EXISTS idx_ticker_stats_timestamp ON ticker_stats(timestamp);
@@ -0,0 +1,3 @@
+-- Crypto Snapshot Service - Rollback Snapshot Timestamp Source
+
+ALTER TABLE snapshots DROP COLUMN IF EXISTS ts_source;
@@ -0,0 +1,5 @@
+-- Crypto Snapshot Service - Snapshot Timestamp Source
+-- Records where each snapshot's timestamp came from (poll, fetch, exchange, import)
+
+ALTER TABLE snapshots
+    ADD COLUMN IF NOT EXISTS ts_source VARCHAR(16) NOT NULL DEFAULT 'poll';
@@ -0,0 +1,4 @@
+-- Crypto Snapshot Service - Rollback Symbol Tags
+
+DROP INDEX IF EXISTS idx_symbols_tags;
+ALTER TABLE symbols DROP COLUMN IF EXISTS tags;
@@ -0,0 +1,8 @@
+-- Crypto Snapshot Service - Symbol Tags
+-- Adds free-form tags to symbols for label-based querying
+
+ALTER TABLE symbols
+    ADD COLUMN IF NOT EXISTS tags TEXT[] NOT NULL DEFAULT '{}';
+
+-- GIN index for containment queries (tags @> ARRAY['layer1'])
+CREATE INDEX IF NOT EXISTS idx_symbols_tags ON symbols USING GIN (tags);
@@ -0,0 +1,3 @@
+-- Crypto Snapshot Service - Rollback Symbol Events
+
+DROP TABLE IF EXISTS symbol_events;
@@ -0,0 +1,13 @@
+-- Crypto Snapshot Service - Symbol Events
+-- Records every change to a symbol's tracking configuration. Events are keyed
+-- by name rather than symbol_id so they outlive the symbol row.
+
+CREATE TABLE IF NOT EXISTS symbol_events (
+    id BIGSERIAL PRIMARY KEY,
+    symbol VARCHAR(20) NOT NULL,
+    type VARCHAR(32) NOT NULL,
+    details JSONB,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_symbol_events_symbol_created_at ON symbol_events(symbol, created_at DESC);
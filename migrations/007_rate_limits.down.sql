@@ -0,0 +1,3 @@
+-- Crypto Snapshot Service - Rollback Rate Limits
+
+DROP TABLE IF EXISTS rate_limit_counters;
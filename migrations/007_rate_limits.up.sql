@@ -0,0 +1,12 @@
+-- Crypto Snapshot Service - Rate Limits
+-- Fixed-window request counters per API key or client IP, shared by every
+-- replica and kept across restarts.
+
+CREATE TABLE IF NOT EXISTS rate_limit_counters (
+    key VARCHAR(128) NOT NULL,
+    window_start TIMESTAMPTZ NOT NULL,
+    count INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (key, window_start)
+);
+
+CREATE INDEX IF NOT EXISTS idx_rate_limit_counters_window_start ON rate_limit_counters(window_start);
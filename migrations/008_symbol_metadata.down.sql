@@ -0,0 +1,7 @@
+-- Crypto Snapshot Service - Rollback Symbol Metadata
+
+ALTER TABLE symbols
+    DROP COLUMN IF EXISTS metadata_updated_at,
+    DROP COLUMN IF EXISTS market_cap,
+    DROP COLUMN IF EXISTS logo_url,
+    DROP COLUMN IF EXISTS display_name;
@@ -0,0 +1,9 @@
+-- Crypto Snapshot Service - Symbol Metadata
+-- Display name, logo and market cap of each symbol's base asset, filled in
+-- by the optional CoinGecko enrichment
+
+ALTER TABLE symbols
+    ADD COLUMN IF NOT EXISTS display_name VARCHAR(100),
+    ADD COLUMN IF NOT EXISTS logo_url TEXT,
+    ADD COLUMN IF NOT EXISTS market_cap NUMERIC(30, 2),
+    ADD COLUMN IF NOT EXISTS metadata_updated_at TIMESTAMPTZ;
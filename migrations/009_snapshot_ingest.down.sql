@@ -0,0 +1,6 @@
+-- Crypto Snapshot Service - Rollback Snapshot Ingestion
+
+DROP INDEX IF EXISTS idx_snapshots_ingest_key;
+
+ALTER TABLE snapshots
+    DROP COLUMN IF EXISTS source;
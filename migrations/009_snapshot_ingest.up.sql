@@ -0,0 +1,11 @@
+-- Crypto Snapshot Service - Snapshot Ingestion
+-- Names the external system that pushed a snapshot through POST /ingest.
+-- A pushed snapshot is identified by its symbol, timestamp and source, so
+-- pushing it again is a no-op.
+
+ALTER TABLE snapshots
+    ADD COLUMN IF NOT EXISTS source VARCHAR(64);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_snapshots_ingest_key
+    ON snapshots(symbol, timestamp, source)
+    WHERE source IS NOT NULL;
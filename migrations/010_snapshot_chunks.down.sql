@@ -0,0 +1,4 @@
+-- Crypto Snapshot Service - Rollback Compressed Snapshot Chunks
+-- Compressed snapshots are lost; decompress them first to keep them.
+
+DROP TABLE IF EXISTS snapshot_chunks;
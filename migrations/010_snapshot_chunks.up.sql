@@ -0,0 +1,20 @@
+-- Crypto Snapshot Service - Compressed Snapshot Chunks
+-- Optional compressed layout for old snapshots: each row packs a run of one
+-- symbol's prices as the first price plus scaled integer deltas. Chunks are
+-- only ever inserted or pruned, never updated.
+
+CREATE TABLE IF NOT EXISTS snapshot_chunks (
+    id BIGSERIAL PRIMARY KEY,
+    symbol_id BIGINT NOT NULL REFERENCES symbols(id) ON DELETE CASCADE,
+    symbol VARCHAR(20) NOT NULL,
+    ts_source VARCHAR(16) NOT NULL,
+    start_ts TIMESTAMPTZ NOT NULL,
+    end_ts TIMESTAMPTZ NOT NULL,
+    count INTEGER NOT NULL,
+    scale SMALLINT NOT NULL,
+    data BYTEA NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_snapshot_chunks_symbol_end ON snapshot_chunks(symbol, end_ts DESC);
+CREATE INDEX IF NOT EXISTS idx_snapshot_chunks_symbol_start ON snapshot_chunks(symbol, start_ts);
@@ -0,0 +1,4 @@
+-- Crypto Snapshot Service - Rollback Snapshot Fetch Latency
+
+ALTER TABLE snapshots
+    DROP COLUMN IF EXISTS fetch_latency_ms;
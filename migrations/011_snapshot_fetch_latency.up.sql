@@ -0,0 +1,7 @@
+-- Crypto Snapshot Service - Snapshot Fetch Latency
+-- How long the exchange took to answer the poll that stored a snapshot, so
+-- late prices can be told apart from slow exchange responses. NULL for
+-- snapshots that were not polled.
+
+ALTER TABLE snapshots
+    ADD COLUMN IF NOT EXISTS fetch_latency_ms INTEGER;
@@ -0,0 +1,6 @@
+-- Crypto Snapshot Service - Rollback Snapshot Poll Runs
+
+DROP INDEX IF EXISTS idx_snapshots_poll_run;
+
+ALTER TABLE snapshots
+    DROP COLUMN IF EXISTS poll_run_id;
@@ -0,0 +1,12 @@
+-- Crypto Snapshot Service - Snapshot Poll Runs
+-- Tags each polled snapshot with the UUID of the poll that stored it, so a
+-- bad run can be inspected or deleted in one statement. A symbol is stored
+-- at most once per run, which makes retrying a poll's write idempotent.
+-- NULL for snapshots that were not polled.
+
+ALTER TABLE snapshots
+    ADD COLUMN IF NOT EXISTS poll_run_id UUID;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_snapshots_poll_run
+    ON snapshots(poll_run_id, symbol_id)
+    WHERE poll_run_id IS NOT NULL;
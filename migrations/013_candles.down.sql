@@ -0,0 +1,3 @@
+-- Crypto Snapshot Service - Rollback Candles
+
+DROP TABLE IF EXISTS candles;
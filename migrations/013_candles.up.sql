@@ -0,0 +1,26 @@
+-- Crypto Snapshot Service - Candles
+-- Creates the table of OHLC candles aggregated from snapshots, so chart
+-- queries over long ranges read one row per interval instead of scanning
+-- raw snapshots. 1m candles are built from snapshots and each coarser
+-- resolution is rolled up from the one below it.
+
+CREATE TABLE IF NOT EXISTS candles (
+    symbol_id BIGINT NOT NULL REFERENCES symbols(id) ON DELETE CASCADE,
+    symbol VARCHAR(20) NOT NULL,
+    resolution VARCHAR(3) NOT NULL,
+    open_time TIMESTAMPTZ NOT NULL,
+    open NUMERIC(24, 8) NOT NULL,
+    high NUMERIC(24, 8) NOT NULL,
+    low NUMERIC(24, 8) NOT NULL,
+    close NUMERIC(24, 8) NOT NULL,
+    samples INTEGER NOT NULL,
+    PRIMARY KEY (symbol_id, resolution, open_time)
+);
+
+-- Index for chart queries by symbol name
+CREATE INDEX IF NOT EXISTS idx_candles_symbol_resolution_open_time
+    ON candles(symbol, resolution, open_time);
+
+-- Index for rolling up a resolution across every symbol
+CREATE INDEX IF NOT EXISTS idx_candles_resolution_open_time
+    ON candles(resolution, open_time);
@@ -0,0 +1,7 @@
+-- Crypto Snapshot Service - Rollback Exchanges
+
+ALTER TABLE snapshots
+    DROP COLUMN IF EXISTS exchange;
+
+ALTER TABLE symbols
+    DROP COLUMN IF EXISTS exchange;
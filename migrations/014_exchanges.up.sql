@@ -0,0 +1,10 @@
+-- Crypto Snapshot Service - Exchanges
+-- The exchange each symbol is polled from, empty for the default exchange,
+-- and the exchange each polled snapshot came from. NULL for snapshots that
+-- were not polled.
+
+ALTER TABLE symbols
+    ADD COLUMN IF NOT EXISTS exchange TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE snapshots
+    ADD COLUMN IF NOT EXISTS exchange TEXT;
@@ -0,0 +1,3 @@
+-- Crypto Snapshot Service - Rollback Alerts
+
+DROP TABLE IF EXISTS alerts;
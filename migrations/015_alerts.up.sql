@@ -0,0 +1,22 @@
+-- Crypto Snapshot Service - Alerts
+-- Creates the table of alert rules evaluated against polled prices. Each
+-- firing is posted to the alert's webhook by an alert-webhook job; the
+-- latest firing and the state of its delivery are kept on the alert.
+
+CREATE TABLE IF NOT EXISTS alerts (
+    id BIGSERIAL PRIMARY KEY,
+    symbol_id BIGINT NOT NULL REFERENCES symbols(id) ON DELETE CASCADE,
+    symbol VARCHAR(20) NOT NULL,
+    condition VARCHAR(20) NOT NULL,
+    threshold NUMERIC(24, 8) NOT NULL,
+    window_seconds BIGINT NOT NULL DEFAULT 0,
+    webhook_url TEXT NOT NULL,
+    firings BIGINT NOT NULL DEFAULT 0,
+    last_firing JSONB,
+    delivery VARCHAR(20),
+    delivery_error TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Index for evaluating a symbol's alerts
+CREATE INDEX IF NOT EXISTS idx_alerts_symbol ON alerts(symbol);
@@ -0,0 +1,7 @@
+-- Crypto Snapshot Service - Rollback Alert Watches
+
+DELETE FROM alerts WHERE once;
+
+ALTER TABLE alerts
+    DROP COLUMN IF EXISTS once,
+    DROP COLUMN IF EXISTS expires_at;
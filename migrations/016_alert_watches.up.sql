@@ -0,0 +1,7 @@
+-- Crypto Snapshot Service - Alert Watches
+-- Watches are one-shot alerts: they fire at most once and are removed
+-- after their webhook call, or once expires_at passes without firing.
+
+ALTER TABLE alerts
+    ADD COLUMN IF NOT EXISTS once BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS expires_at TIMESTAMPTZ;
@@ -0,0 +1,6 @@
+-- Crypto Snapshot Service - Rollback Snapshot Provenance
+
+ALTER TABLE snapshots
+    DROP COLUMN IF EXISTS pipeline_version,
+    DROP COLUMN IF EXISTS ingest_latency_ms,
+    DROP COLUMN IF EXISTS ingest_mode;
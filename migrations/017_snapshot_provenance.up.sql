@@ -0,0 +1,12 @@
+-- Crypto Snapshot Service - Snapshot Provenance
+-- How a polled or streamed snapshot was ingested, so the data quality of
+-- the ingestion modes can be compared after the fact: the mode ('poll' or
+-- 'stream'), how long before the snapshot was built its price was observed
+-- at the exchange, and the version of the service that built it. NULL for
+-- pushed and imported snapshots, and for those stored before this
+-- migration.
+
+ALTER TABLE snapshots
+    ADD COLUMN IF NOT EXISTS ingest_mode VARCHAR(16),
+    ADD COLUMN IF NOT EXISTS ingest_latency_ms INTEGER,
+    ADD COLUMN IF NOT EXISTS pipeline_version VARCHAR(64);
@@ -0,0 +1,6 @@
+-- Crypto Snapshot Service - Rollback Symbol Soft Delete
+-- Soft deleted symbols are kept, as inactive symbols, rather than deleting
+-- their history with them.
+
+ALTER TABLE symbols
+    DROP COLUMN IF EXISTS deleted_at;
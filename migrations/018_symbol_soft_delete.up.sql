@@ -0,0 +1,8 @@
+-- Crypto Snapshot Service - Symbol Soft Delete
+-- Removing a symbol marks it deleted instead of deleting the row, so its
+-- snapshots, candles and ticker stats, which cascade from it, stay
+-- queryable. Deleted symbols are inactive and left out of symbol lookups;
+-- adding the name again revives the row. NULL for tracked symbols.
+
+ALTER TABLE symbols
+    ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
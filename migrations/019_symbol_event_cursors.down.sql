@@ -0,0 +1,3 @@
+-- Crypto Snapshot Service - Rollback Symbol Event Cursors
+
+DROP TABLE IF EXISTS symbol_event_cursors;
@@ -0,0 +1,9 @@
+-- Crypto Snapshot Service - Symbol Event Cursors
+-- The last symbol event delivered to each subscriber of the symbol event
+-- feed, so delivery resumes where it stopped after a restart.
+
+CREATE TABLE IF NOT EXISTS symbol_event_cursors (
+    subscriber VARCHAR(64) PRIMARY KEY,
+    last_event_id BIGINT NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,3 @@
+-- Crypto Snapshot Service - Rollback API Usage
+
+DROP TABLE IF EXISTS api_usage;
@@ -0,0 +1,12 @@
+-- Crypto Snapshot Service - API Usage
+-- Requests per API key and endpoint per UTC day, kept long-term for
+-- chargeback and capacity planning. Keys are stored as a fingerprint,
+-- never in the clear.
+
+CREATE TABLE IF NOT EXISTS api_usage (
+    day DATE NOT NULL,
+    key VARCHAR(64) NOT NULL,
+    endpoint VARCHAR(128) NOT NULL,
+    requests BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (day, key, endpoint)
+);
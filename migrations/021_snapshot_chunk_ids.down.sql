@@ -0,0 +1,3 @@
+-- Crypto Snapshot Service - Rollback Snapshot Chunk IDs
+
+ALTER TABLE snapshot_chunks DROP COLUMN IF EXISTS ids;
@@ -0,0 +1,6 @@
+-- Crypto Snapshot Service - Snapshot Chunk IDs
+-- Row IDs of the snapshots packed into each chunk, as varint deltas, so
+-- history cursors keep paging through compressed rows. Chunks written
+-- before this column existed leave it NULL.
+
+ALTER TABLE snapshot_chunks ADD COLUMN IF NOT EXISTS ids BYTEA;
@@ -0,0 +1,222 @@
+// Package client is a Go client for the price snapshot service HTTP API.
+//
+// Requests that fail with 429 or a 5xx status, or that cannot reach the
+// service at all, are retried with backoff; every call stops as soon as its
+// context is cancelled.
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/prxgr4mmer/price-snapshot-service/pkg/retry"
+)
+
+// Client calls the price snapshot service
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	apiKey     string
+	retry      retry.Config
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithHTTPClient sets the HTTP client used for requests
+func WithHTTPClient(c *http.Client) Option {
+	return func(cl *Client) {
+		cl.httpClient = c
+	}
+}
+
+// WithAPIKey sends key in the X-API-Key header of every request, which
+// identifies the caller to the service's rate limiter
+func WithAPIKey(key string) Option {
+	return func(c *Client) {
+		c.apiKey = key
+	}
+}
+
+// WithRetry sets how failed requests are retried. MaxRetries 0 disables
+// retries.
+func WithRetry(cfg retry.Config) Option {
+	return func(c *Client) {
+		c.retry = cfg
+	}
+}
+
+// New creates a client for the service at baseURL, e.g.
+// "http://localhost:8080"
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		retry:      retry.DefaultConfig(),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// APIError is an error response from the service
+type APIError struct {
+	StatusCode int
+	Code       string
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	if e.Code != "" {
+		return fmt.Sprintf("price snapshot service: %d %s: %s", e.StatusCode, e.Code, e.Message)
+	}
+	return fmt.Sprintf("price snapshot service: %d: %s", e.StatusCode, e.Message)
+}
+
+// Temporary reports whether the request may succeed when retried
+func (e *APIError) Temporary() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500
+}
+
+// IsNotFound reports whether err is a 404 from the service, e.g. for a
+// symbol that is not tracked
+func IsNotFound(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// Price is the latest price of a symbol
+type Price struct {
+	Symbol    string          `json:"symbol"`
+	Price     decimal.Decimal `json:"price"`
+	Timestamp time.Time       `json:"ts"`
+}
+
+// Prices returns the latest price of each symbol. Symbols without a
+// snapshot are left out.
+func (c *Client) Prices(ctx context.Context, symbols ...string) ([]Price, error) {
+	q := url.Values{}
+	q.Set("symbols", strings.Join(symbols, ","))
+	q.Set("fields", "symbol,price,ts")
+
+	var resp struct {
+		Prices []Price `json:"prices"`
+	}
+	if err := c.get(ctx, "/prices", q, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Prices, nil
+}
+
+// HistoryItem is a single price of a symbol's history
+type HistoryItem struct {
+	Price     decimal.Decimal `json:"price"`
+	Timestamp time.Time       `json:"ts"`
+}
+
+// HistoryQuery selects a page of history. Zero From or To leaves that end
+// of the window open, and zero Limit uses the service default.
+type HistoryQuery struct {
+	From  time.Time
+	To    time.Time
+	Limit int
+}
+
+// History returns a single page of a symbol's history, newest first. Use
+// NewHistoryIterator to walk a window of any length.
+func (c *Client) History(ctx context.Context, symbol string, query HistoryQuery) ([]HistoryItem, error) {
+	q := url.Values{}
+	q.Set("symbol", symbol)
+	q.Set("fields", "price,ts")
+	if !query.From.IsZero() {
+		q.Set("from", query.From.UTC().Format(time.RFC3339Nano))
+	}
+	if !query.To.IsZero() {
+		q.Set("to", query.To.UTC().Format(time.RFC3339Nano))
+	}
+	if query.Limit > 0 {
+		q.Set("limit", strconv.Itoa(query.Limit))
+	}
+
+	var resp struct {
+		Items []HistoryItem `json:"items"`
+	}
+	if err := c.get(ctx, "/history", q, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Items, nil
+}
+
+// get sends a GET request, retrying temporary failures, and decodes the
+// JSON response into out
+func (c *Client) get(ctx context.Context, path string, query url.Values, out interface{}) error {
+	return retry.Do(ctx, c.retry, func(ctx context.Context) error {
+		err := c.do(ctx, path, query, out)
+
+		var apiErr *APIError
+		switch {
+		case err == nil:
+			return nil
+		case ctx.Err() != nil:
+			return ctx.Err()
+		case errors.As(err, &apiErr):
+			if apiErr.Temporary() {
+				return retry.NewRetryableError(err)
+			}
+			return err
+		default:
+			// The service could not be reached
+			return retry.NewRetryableError(err)
+		}
+	})
+}
+
+func (c *Client) do(ctx context.Context, path string, query url.Values, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path+"?"+query.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.apiKey != "" {
+		req.Header.Set("X-API-Key", c.apiKey)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode >= 400 {
+		apiErr := &APIError{StatusCode: resp.StatusCode, Message: http.StatusText(resp.StatusCode)}
+		var errResp struct {
+			Error string `json:"error"`
+			Code  string `json:"code"`
+		}
+		if json.Unmarshal(body, &errResp) == nil && errResp.Error != "" {
+			apiErr.Code, apiErr.Message = errResp.Code, errResp.Error
+		}
+		return apiErr
+	}
+
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("failed to decode %s response: %w", path, err)
+	}
+	return nil
+}
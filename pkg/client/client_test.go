@@ -0,0 +1,224 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prxgr4mmer/price-snapshot-service/pkg/client"
+	"github.com/prxgr4mmer/price-snapshot-service/pkg/retry"
+)
+
+func fastRetry(maxRetries int) client.Option {
+	return client.WithRetry(retry.Config{
+		MaxRetries:     maxRetries,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     5 * time.Millisecond,
+		Multiplier:     2,
+	})
+}
+
+func TestClient_Prices(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/prices", r.URL.Path)
+		assert.Equal(t, "BTCUSDT,ETHUSDT", r.URL.Query().Get("symbols"))
+		assert.Equal(t, "secret", r.Header.Get("X-API-Key"))
+		w.Write([]byte(`{"prices": [{"symbol": "BTCUSDT", "price": "43123.45", "ts": "2024-01-15T10:30:00Z"}]}`))
+	}))
+	defer server.Close()
+
+	prices, err := client.New(server.URL, client.WithAPIKey("secret")).Prices(context.Background(), "BTCUSDT", "ETHUSDT")
+
+	require.NoError(t, err)
+	require.Len(t, prices, 1)
+	assert.Equal(t, "BTCUSDT", prices[0].Symbol)
+	assert.Equal(t, "43123.45", prices[0].Price.String())
+	assert.Equal(t, time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC), prices[0].Timestamp)
+}
+
+func TestClient_Retry(t *testing.T) {
+	t.Run("retries 429 and 5xx responses", func(t *testing.T) {
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch calls.Add(1) {
+			case 1:
+				w.WriteHeader(http.StatusTooManyRequests)
+				w.Write([]byte(`{"error": "rate limit exceeded", "code": "RATE_LIMIT_EXCEEDED"}`))
+			case 2:
+				w.WriteHeader(http.StatusServiceUnavailable)
+			default:
+				w.Write([]byte(`{"prices": []}`))
+			}
+		}))
+		defer server.Close()
+
+		_, err := client.New(server.URL, fastRetry(3)).Prices(context.Background(), "BTCUSDT")
+
+		require.NoError(t, err)
+		assert.Equal(t, int32(3), calls.Load())
+	})
+
+	t.Run("returns the last error once retries run out", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		defer server.Close()
+
+		_, err := client.New(server.URL, fastRetry(2)).Prices(context.Background(), "BTCUSDT")
+
+		var apiErr *client.APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusBadGateway, apiErr.StatusCode)
+	})
+
+	t.Run("does not retry client errors", func(t *testing.T) {
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error": "symbol not found", "code": "SYMBOL_NOT_FOUND"}`))
+		}))
+		defer server.Close()
+
+		_, err := client.New(server.URL, fastRetry(3)).History(context.Background(), "NOPE", client.HistoryQuery{})
+
+		assert.True(t, client.IsNotFound(err))
+		assert.EqualError(t, err, "price snapshot service: 404 SYMBOL_NOT_FOUND: symbol not found")
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("stops when the context is cancelled", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		c := client.New(server.URL, client.WithRetry(retry.Config{MaxRetries: 100, InitialBackoff: 10 * time.Millisecond, MaxBackoff: 10 * time.Millisecond, Multiplier: 1}))
+
+		_, err := c.Prices(ctx, "BTCUSDT")
+
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
+
+// historyServer serves /history from snapshots stored newest first,
+// reporting timestamps to the second like the service does
+func historyServer(snapshots []time.Time) (*httptest.Server, *atomic.Int32) {
+	var pages atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pages.Add(1)
+		q := r.URL.Query()
+		limit, _ := strconv.Atoi(q.Get("limit"))
+
+		var from, to time.Time
+		if v := q.Get("from"); v != "" {
+			from, _ = time.Parse(time.RFC3339, v)
+		}
+		if v := q.Get("to"); v != "" {
+			to, _ = time.Parse(time.RFC3339, v)
+		}
+
+		type item struct {
+			Price string `json:"price"`
+			Ts    string `json:"ts"`
+		}
+		items := []item{}
+		for i, ts := range snapshots {
+			if (!from.IsZero() && ts.Before(from)) || (!to.IsZero() && ts.After(to)) {
+				continue
+			}
+			if len(items) == limit {
+				break
+			}
+			items = append(items, item{Price: strconv.Itoa(i), Ts: ts.Format(time.RFC3339)})
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"symbol": q.Get("symbol"), "items": items})
+	}))
+	return server, &pages
+}
+
+func TestHistoryIterator(t *testing.T) {
+	start := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	t.Run("walks every page, newest first", func(t *testing.T) {
+		// Three snapshots a second, so pages end mid-second
+		var snapshots []time.Time
+		for i := 29; i >= 0; i-- {
+			snapshots = append(snapshots, start.Add(time.Duration(i)*time.Second/3+time.Millisecond))
+		}
+		server, pages := historyServer(snapshots)
+		defer server.Close()
+
+		it := client.New(server.URL).NewHistoryIterator("BTCUSDT", client.HistoryQuery{Limit: 4})
+		var prices []string
+		for it.Next(context.Background()) {
+			prices = append(prices, it.Item().Price.String())
+		}
+
+		require.NoError(t, it.Err())
+		require.Len(t, prices, len(snapshots))
+		for i, p := range prices {
+			assert.Equal(t, strconv.Itoa(i), p)
+		}
+		assert.Greater(t, pages.Load(), int32(len(snapshots)/4))
+	})
+
+	t.Run("stays within the window", func(t *testing.T) {
+		var snapshots []time.Time
+		for i := 9; i >= 0; i-- {
+			snapshots = append(snapshots, start.Add(time.Duration(i)*time.Minute))
+		}
+		server, _ := historyServer(snapshots)
+		defer server.Close()
+
+		it := client.New(server.URL).NewHistoryIterator("BTCUSDT", client.HistoryQuery{
+			From:  start.Add(2 * time.Minute),
+			To:    start.Add(7 * time.Minute),
+			Limit: 2,
+		})
+		var times []time.Time
+		for it.Next(context.Background()) {
+			times = append(times, it.Item().Timestamp)
+		}
+
+		require.NoError(t, it.Err())
+		require.Len(t, times, 6)
+		assert.Equal(t, start.Add(7*time.Minute), times[0])
+		assert.Equal(t, start.Add(2*time.Minute), times[5])
+	})
+
+	t.Run("fails when a second does not fit in a page", func(t *testing.T) {
+		snapshots := []time.Time{start.Add(900 * time.Millisecond), start.Add(500 * time.Millisecond), start.Add(100 * time.Millisecond), start.Add(-time.Second)}
+		server, _ := historyServer(snapshots)
+		defer server.Close()
+
+		it := client.New(server.URL).NewHistoryIterator("BTCUSDT", client.HistoryQuery{Limit: 2})
+		for it.Next(context.Background()) {
+		}
+
+		assert.ErrorIs(t, it.Err(), client.ErrPageTooSmall)
+	})
+
+	t.Run("stops on a cancelled context", func(t *testing.T) {
+		server, _ := historyServer([]time.Time{start})
+		defer server.Close()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		it := client.New(server.URL).NewHistoryIterator("BTCUSDT", client.HistoryQuery{})
+
+		assert.False(t, it.Next(ctx))
+		assert.ErrorIs(t, it.Err(), context.Canceled)
+	})
+}
@@ -0,0 +1,127 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrPageTooSmall is returned by a HistoryIterator when more snapshots
+// share one second than fit in a page, so the window cannot be paged
+// without skipping some of them
+var ErrPageTooSmall = errors.New("more snapshots in one second than fit in a page")
+
+// DefaultPageSize is the number of items a HistoryIterator requests per page
+const DefaultPageSize = 500
+
+// HistoryIterator walks a symbol's history newest first, requesting one
+// page at a time:
+//
+//	it := c.NewHistoryIterator("BTCUSDT", client.HistoryQuery{From: from})
+//	for it.Next(ctx) {
+//		item := it.Item()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+//
+// Each page ends where the previous one left off. The service reports
+// timestamps to the second, so a page boundary is a second: the next page
+// repeats that second and the items already returned are skipped.
+type HistoryIterator struct {
+	client   *Client
+	symbol   string
+	from     time.Time
+	until    time.Time
+	pageSize int
+
+	// to is the upper bound of the next page, boundary the oldest second
+	// returned so far and seen the number of items at boundary
+	to       time.Time
+	boundary time.Time
+	seen     int
+
+	page []HistoryItem
+	item HistoryItem
+	last bool
+	err  error
+}
+
+// NewHistoryIterator returns an iterator over the items of query's window.
+// query.Limit is the page size, DefaultPageSize when zero.
+func (c *Client) NewHistoryIterator(symbol string, query HistoryQuery) *HistoryIterator {
+	pageSize := query.Limit
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+	return &HistoryIterator{
+		client:   c,
+		symbol:   symbol,
+		from:     query.From,
+		until:    query.To,
+		to:       query.To,
+		pageSize: pageSize,
+	}
+}
+
+// Next advances to the next item, fetching a page when needed. It returns
+// false at the end of the window, on error or once ctx is cancelled.
+func (it *HistoryIterator) Next(ctx context.Context) bool {
+	for len(it.page) == 0 {
+		if it.last || it.err != nil {
+			return false
+		}
+		it.fetch(ctx)
+	}
+
+	it.item, it.page = it.page[0], it.page[1:]
+	return true
+}
+
+// Item returns the current item
+func (it *HistoryIterator) Item() HistoryItem {
+	return it.item
+}
+
+// Err returns the error that stopped the iteration, if any
+func (it *HistoryIterator) Err() error {
+	return it.err
+}
+
+func (it *HistoryIterator) fetch(ctx context.Context) {
+	items, err := it.client.History(ctx, it.symbol, HistoryQuery{From: it.from, To: it.to, Limit: it.pageSize})
+	if err != nil {
+		it.err = err
+		return
+	}
+	if len(items) < it.pageSize {
+		it.last = true
+	}
+	if len(items) == 0 {
+		return
+	}
+
+	skip := 0
+	for skip < len(items) && skip < it.seen && items[skip].Timestamp.Equal(it.boundary) {
+		skip++
+	}
+	it.page = items[skip:]
+
+	oldest := items[len(items)-1].Timestamp
+	atOldest := 0
+	for i := len(items) - 1; i >= 0 && items[i].Timestamp.Equal(oldest); i-- {
+		atOldest++
+	}
+	if !it.last && atOldest == len(items) {
+		it.page = nil
+		it.err = ErrPageTooSmall
+		return
+	}
+
+	it.boundary, it.seen = oldest, atOldest
+	it.to = oldest.Add(time.Second - time.Microsecond)
+	if !it.until.IsZero() && it.to.After(it.until) {
+		it.to = it.until
+	}
+}
@@ -3,18 +3,93 @@ package retry
 import (
 	"context"
 	"errors"
+	"fmt"
 	"math"
 	"math/rand"
+	"sync"
 	"time"
 )
 
+// JitterMode selects how randomness is applied to the computed backoff
+type JitterMode int
+
+const (
+	// JitterProportional varies the backoff by up to +/- Jitter of its value
+	JitterProportional JitterMode = iota
+	// JitterFull picks a uniformly random backoff between zero and the
+	// computed value, spreading retries from many callers across the window
+	JitterFull
+)
+
+// ParseJitterMode converts a configuration string into a JitterMode
+func ParseJitterMode(s string) (JitterMode, error) {
+	switch s {
+	case "", "proportional":
+		return JitterProportional, nil
+	case "full":
+		return JitterFull, nil
+	default:
+		return 0, fmt.Errorf("unknown jitter mode: %s", s)
+	}
+}
+
+// Source supplies random numbers in [0, 1) for jitter calculation.
+// Implementations must be safe for concurrent use.
+type Source interface {
+	Float64() float64
+}
+
+type lockedSource struct {
+	mu  sync.Mutex
+	rnd *rand.Rand
+}
+
+// NewSource returns a concurrency-safe Source seeded deterministically,
+// so tests can assert exact retry timings
+func NewSource(seed int64) Source {
+	return &lockedSource{rnd: rand.New(rand.NewSource(seed))}
+}
+
+func (s *lockedSource) Float64() float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.rnd.Float64()
+}
+
+type globalSource struct{}
+
+func (globalSource) Float64() float64 {
+	return rand.Float64()
+}
+
 // Config defines retry configuration
 type Config struct {
 	MaxRetries     int
 	InitialBackoff time.Duration
 	MaxBackoff     time.Duration
 	Multiplier     float64
-	Jitter         float64 // Random jitter factor (0-1)
+	Jitter         float64 // Random jitter factor (0-1), used by JitterProportional
+	JitterMode     JitterMode
+	Rand           Source // Defaults to the global math/rand source
+
+	// Classify, when set, decides whether the error of a failed attempt is
+	// retried, in place of IsRetryable
+	Classify func(err error) bool
+
+	// Delay, when set, returns how long to wait before the given retry
+	// (1-based) of an attempt that failed with err, in place of backoff,
+	// the delay computed from the settings above. Returning false gives up
+	// and returns err, e.g. when the server asks for a longer wait than the
+	// caller is willing to spend.
+	Delay func(retry int, err error, backoff time.Duration) (time.Duration, bool)
+
+	// OnRetry, when set, is called before each retry with its number
+	// (1-based) and the error of the attempt being retried
+	OnRetry func(retry int, err error)
+
+	// OnDone, when set, is called once per call with the number of attempts
+	// made and the error returned, nil on success
+	OnDone func(attempts int, err error)
 }
 
 // DefaultConfig returns sensible defaults
@@ -54,66 +129,63 @@ func IsRetryable(err error) bool {
 
 // Do executes a function with retry logic
 func Do(ctx context.Context, cfg Config, fn func(ctx context.Context) error) error {
-	var lastErr error
-
-	for attempt := 0; attempt <= cfg.MaxRetries; attempt++ {
-		if attempt > 0 {
-			backoff := calculateBackoff(cfg, attempt)
-
-			select {
-			case <-ctx.Done():
-				return ctx.Err()
-			case <-time.After(backoff):
-			}
-		}
-
-		err := fn(ctx)
-		if err == nil {
-			return nil
-		}
-
-		lastErr = err
-
-		// Only retry if error is retryable
-		if !IsRetryable(err) {
-			return err
-		}
-	}
-
-	return lastErr
+	_, err := DoWithResult(ctx, cfg, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, fn(ctx)
+	})
+	return err
 }
 
 // DoWithResult executes a function with retry logic and returns a result
-func DoWithResult[T any](ctx context.Context, cfg Config, fn func(ctx context.Context) (T, error)) (T, error) {
-	var result T
-	var lastErr error
+func DoWithResult[T any](ctx context.Context, cfg Config, fn func(ctx context.Context) (T, error)) (result T, err error) {
+	attempts := 0
+	if cfg.OnDone != nil {
+		defer func() { cfg.OnDone(attempts, err) }()
+	}
+
+	retryable := cfg.Classify
+	if retryable == nil {
+		retryable = IsRetryable
+	}
 
 	for attempt := 0; attempt <= cfg.MaxRetries; attempt++ {
 		if attempt > 0 {
 			backoff := calculateBackoff(cfg, attempt)
+			if cfg.Delay != nil {
+				var ok bool
+				if backoff, ok = cfg.Delay(attempt, err, backoff); !ok {
+					return result, err
+				}
+			}
 
 			select {
 			case <-ctx.Done():
 				return result, ctx.Err()
 			case <-time.After(backoff):
 			}
+
+			if cfg.OnRetry != nil {
+				cfg.OnRetry(attempt, err)
+			}
 		}
 
-		var err error
+		attempts++
 		result, err = fn(ctx)
 		if err == nil {
 			return result, nil
 		}
 
-		lastErr = err
-
 		// Only retry if error is retryable
-		if !IsRetryable(err) {
+		if !retryable(err) {
 			return result, err
 		}
 	}
 
-	return result, lastErr
+	return result, err
+}
+
+// Backoff returns the delay before the given retry attempt (1-based)
+func Backoff(cfg Config, attempt int) time.Duration {
+	return calculateBackoff(cfg, attempt)
 }
 
 func calculateBackoff(cfg Config, attempt int) time.Duration {
@@ -124,10 +196,21 @@ func calculateBackoff(cfg Config, attempt int) time.Duration {
 		backoff = float64(cfg.MaxBackoff)
 	}
 
-	// Apply jitter (random value between -jitter% and +jitter%)
-	if cfg.Jitter > 0 {
-		jitter := backoff * cfg.Jitter * (rand.Float64()*2 - 1)
-		backoff += jitter
+	src := cfg.Rand
+	if src == nil {
+		src = globalSource{}
+	}
+
+	switch cfg.JitterMode {
+	case JitterFull:
+		// Random value between zero and the computed backoff
+		backoff *= src.Float64()
+	default:
+		// Apply jitter (random value between -jitter% and +jitter%)
+		if cfg.Jitter > 0 {
+			jitter := backoff * cfg.Jitter * (src.Float64()*2 - 1)
+			backoff += jitter
+		}
 	}
 
 	// Ensure backoff is not negative
@@ -104,6 +104,162 @@ func TestDo_RespectsContextCancellation(t *testing.T) {
 	assert.LessOrEqual(t, callCount, 2)
 }
 
+func TestDo_Hooks(t *testing.T) {
+	cfg := retry.Config{
+		MaxRetries:     3,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     10 * time.Millisecond,
+		Multiplier:     2.0,
+	}
+
+	t.Run("reports each retry and the attempts of the call", func(t *testing.T) {
+		var retries []int
+		var retried []error
+		attempts := 0
+		var doneErr error
+
+		cfg := cfg
+		cfg.OnRetry = func(retry int, err error) {
+			retries = append(retries, retry)
+			retried = append(retried, err)
+		}
+		cfg.OnDone = func(n int, err error) {
+			attempts, doneErr = n, err
+		}
+
+		temporary := errors.New("temporary")
+		callCount := 0
+		err := retry.Do(context.Background(), cfg, func(ctx context.Context) error {
+			callCount++
+			if callCount < 3 {
+				return retry.NewRetryableError(temporary)
+			}
+			return nil
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, []int{1, 2}, retries)
+		require.Len(t, retried, 2)
+		assert.ErrorIs(t, retried[0], temporary)
+		assert.Equal(t, 3, attempts)
+		assert.NoError(t, doneErr)
+	})
+
+	t.Run("reports the final error of a failed call", func(t *testing.T) {
+		attempts := 0
+		var doneErr error
+
+		cfg := cfg
+		cfg.OnDone = func(n int, err error) {
+			attempts, doneErr = n, err
+		}
+
+		permanent := errors.New("permanent")
+		err := retry.Do(context.Background(), cfg, func(ctx context.Context) error {
+			return permanent
+		})
+
+		assert.ErrorIs(t, err, permanent)
+		assert.Equal(t, 1, attempts)
+		assert.ErrorIs(t, doneErr, permanent)
+	})
+}
+
+func TestDo_Classify(t *testing.T) {
+	cfg := retry.Config{
+		MaxRetries:     3,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     10 * time.Millisecond,
+		Multiplier:     2.0,
+	}
+
+	busy := errors.New("busy")
+	cfg.Classify = func(err error) bool {
+		return errors.Is(err, busy)
+	}
+
+	t.Run("retries errors the function classifies as retryable", func(t *testing.T) {
+		callCount := 0
+		err := retry.Do(context.Background(), cfg, func(ctx context.Context) error {
+			callCount++
+			if callCount < 3 {
+				return busy
+			}
+			return nil
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, 3, callCount)
+	})
+
+	t.Run("replaces the retryable error wrapper", func(t *testing.T) {
+		callCount := 0
+		err := retry.Do(context.Background(), cfg, func(ctx context.Context) error {
+			callCount++
+			return retry.NewRetryableError(errors.New("temporary"))
+		})
+
+		assert.Error(t, err)
+		assert.Equal(t, 1, callCount)
+	})
+}
+
+func TestDo_Delay(t *testing.T) {
+	cfg := retry.Config{
+		MaxRetries:     3,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     10 * time.Millisecond,
+		Multiplier:     2.0,
+	}
+
+	t.Run("waits the returned delay before each retry", func(t *testing.T) {
+		var retries []int
+		var backoffs []time.Duration
+
+		cfg := cfg
+		cfg.Delay = func(retry int, err error, backoff time.Duration) (time.Duration, bool) {
+			retries = append(retries, retry)
+			backoffs = append(backoffs, backoff)
+			return 20 * time.Millisecond, true
+		}
+
+		callCount := 0
+		start := time.Now()
+		err := retry.Do(context.Background(), cfg, func(ctx context.Context) error {
+			callCount++
+			if callCount < 3 {
+				return retry.NewRetryableError(errors.New("temporary"))
+			}
+			return nil
+		})
+
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, time.Since(start), 40*time.Millisecond)
+		assert.Equal(t, []int{1, 2}, retries)
+		assert.Equal(t, []time.Duration{time.Millisecond, 2 * time.Millisecond}, backoffs)
+	})
+
+	t.Run("gives up when the delay is refused", func(t *testing.T) {
+		attempts := 0
+
+		cfg := cfg
+		cfg.Delay = func(int, error, time.Duration) (time.Duration, bool) {
+			return 0, false
+		}
+		cfg.OnDone = func(n int, err error) {
+			attempts = n
+		}
+
+		temporary := errors.New("temporary")
+		err := retry.Do(context.Background(), cfg, func(ctx context.Context) error {
+			return retry.NewRetryableError(temporary)
+		})
+
+		assert.ErrorIs(t, err, temporary)
+		assert.Equal(t, 1, attempts)
+	})
+}
+
 func TestDoWithResult_Success(t *testing.T) {
 	result, err := retry.DoWithResult(context.Background(), retry.DefaultConfig(), func(ctx context.Context) (int, error) {
 		return 42, nil
@@ -163,3 +319,74 @@ func TestDefaultConfig(t *testing.T) {
 	assert.Equal(t, 2.0, cfg.Multiplier)
 	assert.Equal(t, 0.1, cfg.Jitter)
 }
+
+func TestBackoff_SeededSourceIsDeterministic(t *testing.T) {
+	cfg := retry.Config{
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     10 * time.Second,
+		Multiplier:     2.0,
+		Jitter:         0.5,
+	}
+
+	var first, second []time.Duration
+	cfg.Rand = retry.NewSource(42)
+	for attempt := 1; attempt <= 5; attempt++ {
+		first = append(first, retry.Backoff(cfg, attempt))
+	}
+	cfg.Rand = retry.NewSource(42)
+	for attempt := 1; attempt <= 5; attempt++ {
+		second = append(second, retry.Backoff(cfg, attempt))
+	}
+
+	assert.Equal(t, first, second)
+}
+
+func TestBackoff_ProportionalJitterStaysInRange(t *testing.T) {
+	cfg := retry.Config{
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     10 * time.Second,
+		Multiplier:     2.0,
+		Jitter:         0.1,
+		Rand:           retry.NewSource(1),
+	}
+
+	for i := 0; i < 100; i++ {
+		backoff := retry.Backoff(cfg, 3)
+		assert.GreaterOrEqual(t, backoff, 360*time.Millisecond)
+		assert.LessOrEqual(t, backoff, 440*time.Millisecond)
+	}
+}
+
+func TestBackoff_FullJitter(t *testing.T) {
+	cfg := retry.Config{
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     time.Second,
+		Multiplier:     2.0,
+		JitterMode:     retry.JitterFull,
+		Rand:           retry.NewSource(7),
+	}
+
+	var below bool
+	for i := 0; i < 100; i++ {
+		backoff := retry.Backoff(cfg, 10)
+		assert.GreaterOrEqual(t, backoff, time.Duration(0))
+		assert.Less(t, backoff, time.Second)
+		if backoff < 500*time.Millisecond {
+			below = true
+		}
+	}
+	assert.True(t, below, "full jitter should spread backoffs across the whole window")
+}
+
+func TestParseJitterMode(t *testing.T) {
+	mode, err := retry.ParseJitterMode("full")
+	require.NoError(t, err)
+	assert.Equal(t, retry.JitterFull, mode)
+
+	mode, err = retry.ParseJitterMode("")
+	require.NoError(t, err)
+	assert.Equal(t, retry.JitterProportional, mode)
+
+	_, err = retry.ParseJitterMode("bogus")
+	assert.Error(t, err)
+}
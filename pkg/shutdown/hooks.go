@@ -0,0 +1,106 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DefaultTimeout is used for hooks registered without a timeout
+const DefaultTimeout = 10 * time.Second
+
+// HookFunc flushes or closes a component
+type HookFunc func(ctx context.Context) error
+
+// Hook is a named teardown step with its own time budget
+type Hook struct {
+	Name    string
+	Timeout time.Duration
+	Fn      HookFunc
+}
+
+// Registry collects teardown hooks and runs them in reverse registration order.
+// Components should register right after they are constructed, so that a
+// component is always torn down before the dependencies it was built from.
+type Registry struct {
+	mu    sync.Mutex
+	hooks []Hook
+	done  bool
+}
+
+// NewRegistry creates an empty hook registry
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Register adds a hook. A non-positive timeout uses DefaultTimeout.
+func (r *Registry) Register(name string, timeout time.Duration, fn HookFunc) {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.hooks = append(r.hooks, Hook{Name: name, Timeout: timeout, Fn: fn})
+}
+
+// Hooks returns the registered hooks in execution order
+func (r *Registry) Hooks() []Hook {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	hooks := make([]Hook, len(r.hooks))
+	for i, h := range r.hooks {
+		hooks[len(r.hooks)-1-i] = h
+	}
+	return hooks
+}
+
+// Shutdown runs every hook once, last registered first. Each hook gets its
+// own timeout bounded by ctx; a hook that fails or times out does not stop
+// the remaining ones. The returned error joins every hook failure.
+// Calling Shutdown again is a no-op.
+func (r *Registry) Shutdown(ctx context.Context) error {
+	r.mu.Lock()
+	if r.done {
+		r.mu.Unlock()
+		return nil
+	}
+	r.done = true
+	r.mu.Unlock()
+
+	var errs []error
+	for _, h := range r.Hooks() {
+		if err := run(ctx, h); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", h.Name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// run executes a hook, abandoning it once its timeout expires
+func run(ctx context.Context, h Hook) error {
+	hookCtx, cancel := context.WithTimeout(ctx, h.Timeout)
+	defer cancel()
+
+	errCh := make(chan error, 1)
+	go func() {
+		defer func() {
+			if p := recover(); p != nil {
+				errCh <- fmt.Errorf("hook panicked: %v", p)
+			}
+		}()
+		errCh <- h.Fn(hookCtx)
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-hookCtx.Done():
+		return hookCtx.Err()
+	}
+}
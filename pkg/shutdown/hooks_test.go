@@ -0,0 +1,75 @@
+package shutdown_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prxgr4mmer/price-snapshot-service/pkg/shutdown"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry_RunsInReverseOrder(t *testing.T) {
+	r := shutdown.NewRegistry()
+
+	var order []string
+	for _, name := range []string{"database", "cache", "poller"} {
+		name := name
+		r.Register(name, time.Second, func(ctx context.Context) error {
+			order = append(order, name)
+			return nil
+		})
+	}
+
+	require.NoError(t, r.Shutdown(context.Background()))
+	assert.Equal(t, []string{"poller", "cache", "database"}, order)
+}
+
+func TestRegistry_ContinuesAfterFailure(t *testing.T) {
+	r := shutdown.NewRegistry()
+
+	closed := false
+	r.Register("database", time.Second, func(ctx context.Context) error {
+		closed = true
+		return nil
+	})
+	r.Register("producer", time.Second, func(ctx context.Context) error {
+		return errors.New("flush failed")
+	})
+
+	err := r.Shutdown(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "producer: flush failed")
+	assert.True(t, closed)
+}
+
+func TestRegistry_EnforcesHookTimeout(t *testing.T) {
+	r := shutdown.NewRegistry()
+
+	r.Register("stuck", 20*time.Millisecond, func(ctx context.Context) error {
+		time.Sleep(time.Second)
+		return nil
+	})
+
+	start := time.Now()
+	err := r.Shutdown(context.Background())
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+}
+
+func TestRegistry_ShutdownOnce(t *testing.T) {
+	r := shutdown.NewRegistry()
+
+	calls := 0
+	r.Register("once", time.Second, func(ctx context.Context) error {
+		calls++
+		return nil
+	})
+
+	require.NoError(t, r.Shutdown(context.Background()))
+	require.NoError(t, r.Shutdown(context.Background()))
+	assert.Equal(t, 1, calls)
+}
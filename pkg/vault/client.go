@@ -0,0 +1,142 @@
+// Package vault reads secrets from HashiCorp Vault's HTTP API and keeps the
+// token it reads them with alive.
+//
+// Only the token auth method and the KV secrets engine, versions 1 and 2,
+// are supported.
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Client calls a Vault server with a token
+type Client struct {
+	addr       string
+	token      string
+	httpClient *http.Client
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithHTTPClient sets the HTTP client used for requests
+func WithHTTPClient(c *http.Client) Option {
+	return func(cl *Client) {
+		cl.httpClient = c
+	}
+}
+
+// New creates a client for the Vault server at addr, e.g.
+// "https://vault.internal:8200", authenticating with token
+func New(addr, token string, opts ...Option) *Client {
+	c := &Client{
+		addr:       strings.TrimRight(addr, "/"),
+		token:      token,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// ReadSecret returns the string values of the secret at path, e.g.
+// "secret/data/price-snapshot-service" for a KV version 2 engine mounted at
+// "secret". Values that are not strings are skipped.
+func (c *Client) ReadSecret(ctx context.Context, path string) (map[string]string, error) {
+	var resp struct {
+		Data map[string]json.RawMessage `json:"data"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/v1/"+strings.TrimLeft(path, "/"), &resp); err != nil {
+		return nil, fmt.Errorf("failed to read secret %s: %w", path, err)
+	}
+
+	// KV version 2 nests the values under data.data, next to data.metadata
+	data := resp.Data
+	if nested, ok := data["data"]; ok {
+		if _, versioned := data["metadata"]; versioned {
+			data = nil
+			if err := json.Unmarshal(nested, &data); err != nil {
+				return nil, fmt.Errorf("failed to decode secret %s: %w", path, err)
+			}
+		}
+	}
+
+	values := make(map[string]string, len(data))
+	for key, raw := range data {
+		var value string
+		if json.Unmarshal(raw, &value) == nil {
+			values[key] = value
+		}
+	}
+	return values, nil
+}
+
+// RenewToken extends the client's token lease and returns its new TTL. A
+// TTL of zero means the token never expires. Renewing a token that is not
+// renewable fails.
+func (c *Client) RenewToken(ctx context.Context) (time.Duration, error) {
+	var resp struct {
+		Auth struct {
+			LeaseDuration int `json:"lease_duration"`
+		} `json:"auth"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/v1/auth/token/renew-self", &resp); err != nil {
+		return 0, fmt.Errorf("failed to renew token: %w", err)
+	}
+	return time.Duration(resp.Auth.LeaseDuration) * time.Second, nil
+}
+
+// Error is an error response from Vault
+type Error struct {
+	StatusCode int
+	Errors     []string
+}
+
+func (e *Error) Error() string {
+	if len(e.Errors) == 0 {
+		return fmt.Sprintf("vault returned status %d", e.StatusCode)
+	}
+	return fmt.Sprintf("vault returned status %d: %s", e.StatusCode, strings.Join(e.Errors, "; "))
+}
+
+func (c *Client) do(ctx context.Context, method, path string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, method, c.addr+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-Vault-Token", c.token)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		apiErr := &Error{StatusCode: resp.StatusCode}
+		var errResp struct {
+			Errors []string `json:"errors"`
+		}
+		if json.Unmarshal(body, &errResp) == nil {
+			apiErr.Errors = errResp.Errors
+		}
+		return apiErr
+	}
+
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package vault_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prxgr4mmer/price-snapshot-service/pkg/vault"
+)
+
+func TestClient_ReadSecret(t *testing.T) {
+	t.Run("reads a KV version 2 secret", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodGet, r.Method)
+			assert.Equal(t, "/v1/secret/data/snapshots", r.URL.Path)
+			assert.Equal(t, "s.token", r.Header.Get("X-Vault-Token"))
+			w.Write([]byte(`{"data": {"data": {"DATABASE_URL": "postgres://db", "RETRIES": 3}, "metadata": {"version": 2}}}`))
+		}))
+		defer server.Close()
+
+		values, err := vault.New(server.URL+"/", "s.token").ReadSecret(context.Background(), "/secret/data/snapshots")
+
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"DATABASE_URL": "postgres://db"}, values)
+	})
+
+	t.Run("reads a KV version 1 secret", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/v1/kv/snapshots", r.URL.Path)
+			w.Write([]byte(`{"data": {"COINGECKO_API_KEY": "cg-key", "data": "kept"}}`))
+		}))
+		defer server.Close()
+
+		values, err := vault.New(server.URL, "s.token").ReadSecret(context.Background(), "kv/snapshots")
+
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"COINGECKO_API_KEY": "cg-key", "data": "kept"}, values)
+	})
+
+	t.Run("returns Vault's errors", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"errors": ["permission denied"]}`))
+		}))
+		defer server.Close()
+
+		_, err := vault.New(server.URL, "s.token").ReadSecret(context.Background(), "secret/data/snapshots")
+
+		var vaultErr *vault.Error
+		require.ErrorAs(t, err, &vaultErr)
+		assert.Equal(t, http.StatusForbidden, vaultErr.StatusCode)
+		assert.Contains(t, err.Error(), "permission denied")
+	})
+}
+
+func TestClient_RenewToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/v1/auth/token/renew-self", r.URL.Path)
+		assert.Equal(t, "s.token", r.Header.Get("X-Vault-Token"))
+		w.Write([]byte(`{"auth": {"lease_duration": 3600, "renewable": true}}`))
+	}))
+	defer server.Close()
+
+	ttl, err := vault.New(server.URL, "s.token").RenewToken(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, time.Hour, ttl)
+}